@@ -18,9 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"sync"
+	"sync/atomic"
 
+	"github.com/nlpodyssey/openai-agents-go/memory"
 	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 	"github.com/nlpodyssey/openai-agents-go/util/transforms"
 	"github.com/openai/openai-go/v3/packages/param"
@@ -53,6 +56,11 @@ type MCPConfig struct {
 // We strongly recommend passing `Instructions`, which is the "system prompt" for the agent. In
 // addition, you can pass `HandoffDescription`, which is a human-readable description of the
 // agent, used when the agent is used inside tools/handoffs.
+//
+// The runner never modifies an agent, so an agent can be used by any number of
+// concurrent runs, provided it is not modified meanwhile. Freeze enforces
+// that: the builder methods of a frozen agent panic. To derive an agent from a
+// frozen one, configure a Clone.
 type Agent struct {
 	// The name of the agent.
 	Name string
@@ -138,6 +146,72 @@ type Agent struct {
 	// Defaults to true.
 	// This ensures that the agent doesn't enter an infinite loop of tool usage.
 	ResetToolChoice param.Opt[bool]
+
+	// Optional long-term memory for this agent. Relevant memories are
+	// recalled and prepended to the model input on each turn, and the
+	// conversation is remembered when the agent produces the final output.
+	// Overrides RunConfig.LongTermMemory.
+	LongTermMemory memory.LongTermMemory
+
+	// Optional limits on the usage of the agent in a run.
+	UsageLimits AgentUsageLimits
+
+	// Set to 1, atomically, by Freeze.
+	frozen uint32
+}
+
+// Clone returns a copy of the agent which is not frozen, and whose lists
+// (tools, handoffs, guardrails...) can be modified without affecting a.
+func (a *Agent) Clone() *Agent {
+	c := &Agent{
+		Name:               a.Name,
+		Instructions:       a.Instructions,
+		Prompt:             a.Prompt,
+		HandoffDescription: a.HandoffDescription,
+		Handoffs:           slices.Clone(a.Handoffs),
+		AgentHandoffs:      slices.Clone(a.AgentHandoffs),
+		Model:              a.Model,
+		ModelSettings:      a.ModelSettings,
+		Tools:              slices.Clone(a.Tools),
+		MCPServers:         slices.Clone(a.MCPServers),
+		MCPConfig:          a.MCPConfig,
+		InputGuardrails:    slices.Clone(a.InputGuardrails),
+		OutputGuardrails:   slices.Clone(a.OutputGuardrails),
+		OutputType:         a.OutputType,
+		Hooks:              a.Hooks,
+		ToolUseBehavior:    a.ToolUseBehavior,
+		ResetToolChoice:    a.ResetToolChoice,
+		LongTermMemory:     a.LongTermMemory,
+		UsageLimits:        a.UsageLimits,
+	}
+	c.ModelSettings.Metadata = maps.Clone(a.ModelSettings.Metadata)
+	c.ModelSettings.ResponseInclude = slices.Clone(a.ModelSettings.ResponseInclude)
+	c.ModelSettings.ExtraQuery = maps.Clone(a.ModelSettings.ExtraQuery)
+	c.ModelSettings.ExtraHeaders = maps.Clone(a.ModelSettings.ExtraHeaders)
+	c.ModelSettings.ExtraBody = maps.Clone(a.ModelSettings.ExtraBody)
+	return c
+}
+
+// Freeze makes the builder methods of the agent panic from now on, so that
+// the agent can be shared safely by concurrent runs. Its fields must not be
+// modified directly either. See also RunConfig.FreezeAgents.
+func (a *Agent) Freeze() *Agent {
+	if atomic.LoadUint32(&a.frozen) == 0 {
+		atomic.StoreUint32(&a.frozen, 1)
+	}
+	return a
+}
+
+// Frozen reports whether the agent has been frozen.
+func (a *Agent) Frozen() bool {
+	return atomic.LoadUint32(&a.frozen) == 1
+}
+
+// mustBeMutable panics if the agent is frozen; see Agent.
+func (a *Agent) mustBeMutable() {
+	if a.Frozen() {
+		panic(fmt.Sprintf("agents: agent %q is frozen; configure a Clone instead", a.Name))
+	}
 }
 
 type AgentAsToolParams struct {
@@ -0,0 +1,132 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAgentConcurrentRuns shares agents between concurrent runs, as the
+// parallelization example does. Run it with -race.
+func TestAgentConcurrentRuns(t *testing.T) {
+	var toolCalls, guardrailCalls atomic.Int64
+	tool := agents.NewFunctionTool("lookup", "", func(ctx context.Context, args struct{}) (string, error) {
+		toolCalls.Add(1)
+		return "found", nil
+	})
+	tool.IsEnabled = agents.FunctionToolEnabled()
+	guardrail := func(context.Context, *agents.Agent, any) (agents.GuardrailFunctionOutput, error) {
+		guardrailCalls.Add(1)
+		return agents.GuardrailFunctionOutput{}, nil
+	}
+
+	target := agents.New("target").
+		WithInstructions("Answer.").
+		WithOutputGuardrails([]agents.OutputGuardrail{{Name: "check", GuardrailFunction: guardrail}})
+	source := agents.New("source").
+		WithInstructionsFunc(func(context.Context, *agents.Agent) (string, error) { return "Look up.", nil }).
+		WithTools(tool).
+		WithAgentHandoffs(target).
+		WithInputGuardrails([]agents.InputGuardrail{{
+			Name: "check",
+			GuardrailFunction: func(context.Context, *agents.Agent, agents.Input) (agents.GuardrailFunctionOutput, error) {
+				guardrailCalls.Add(1)
+				return agents.GuardrailFunctionOutput{}, nil
+			},
+		}})
+
+	const runs = 16
+	var wg sync.WaitGroup
+	errs := make([]error, runs)
+	outputs := make([]any, runs)
+	for i := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			model := agentstesting.NewFakeModel(false, nil)
+			model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+				{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("lookup", "{}")}},
+				{Value: []agents.TResponseOutputItem{agentstesting.GetHandoffToolCall(target, "", "")}},
+				{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage(fmt.Sprintf("done %d", i))}},
+			})
+			runner := agents.Runner{Config: agents.RunConfig{
+				Model:        param.NewOpt(agents.NewAgentModel(model)),
+				FreezeAgents: true,
+			}}
+			if i%2 == 0 {
+				result, err := runner.Run(t.Context(), source, "hello")
+				if errs[i] = err; err == nil {
+					outputs[i] = result.FinalOutput
+				}
+				return
+			}
+			result, err := runner.RunStreamed(t.Context(), source, "hello")
+			if err == nil {
+				err = result.StreamEvents(func(agents.StreamEvent) error { return nil })
+			}
+			if errs[i] = err; err == nil {
+				outputs[i] = result.FinalOutput()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range runs {
+		require.NoError(t, errs[i])
+		assert.Equal(t, fmt.Sprintf("done %d", i), outputs[i])
+	}
+	assert.Equal(t, int64(runs), toolCalls.Load())
+	assert.Equal(t, int64(2*runs), guardrailCalls.Load())
+	assert.True(t, source.Frozen())
+	assert.True(t, target.Frozen())
+}
+
+func TestAgentFreeze(t *testing.T) {
+	tool := agentstesting.GetFunctionTool("foo", "result")
+	agent := agents.New("test").WithTools(tool).Freeze()
+
+	assert.True(t, agent.Frozen())
+	assert.PanicsWithValue(t, `agents: agent "test" is frozen; configure a Clone instead`, func() {
+		agent.AddTool(tool)
+	})
+
+	clone := agent.Clone()
+	assert.False(t, clone.Frozen())
+	clone.AddTool(agentstesting.GetFunctionTool("bar", "result"))
+	assert.Len(t, clone.Tools, 2)
+	assert.Len(t, agent.Tools, 1)
+}
+
+func TestAgentNotFrozenByDefault(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")},
+	})
+	agent := agents.New("test").WithModelInstance(model)
+
+	_, err := agents.Runner{}.Run(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	assert.False(t, agent.Frozen())
+}
@@ -116,13 +116,6 @@ func SafeOutputType[T any](opts OutputTypeOpts) (OutputTypeInterface, error) {
 		// that would definitely not be a JSON Schema object.
 		isWrapped = !isStruct[T]()
 
-		var schema *jsonschema.Schema
-		reflector := jsonschema.Reflector{
-			Anonymous:                 true,
-			AllowAdditionalProperties: !opts.StrictJSONSchema,
-			ExpandedStruct:            true,
-		}
-
 		var valueToReflect any
 		if isWrapped {
 			valueToReflect = wrappedOutputType[T]{}
@@ -130,14 +123,10 @@ func SafeOutputType[T any](opts OutputTypeOpts) (OutputTypeInterface, error) {
 			valueToReflect = zero
 		}
 
-		schema = reflector.Reflect(valueToReflect)
-		b, err := json.Marshal(schema)
+		var err error
+		outputSchema, err = reflectOutputSchema(valueToReflect, opts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to JSON-marshal JSON schema: %w", err)
-		}
-		err = json.Unmarshal(b, &outputSchema)
-		if err != nil {
-			return nil, fmt.Errorf("failed to JSON-unmarshal JSON schema: %w", err)
+			return nil, err
 		}
 
 		if opts.StrictJSONSchema {
@@ -165,6 +154,68 @@ func SafeOutputType[T any](opts OutputTypeOpts) (OutputTypeInterface, error) {
 	}, nil
 }
 
+// TypedOutputType is an OutputTypeInterface for T, which also converts the
+// final outputs of the runs into T.
+type TypedOutputType[T any] interface {
+	OutputTypeInterface
+
+	// Decode converts a final output produced with this output type into T.
+	// Besides values of type T, it accepts the JSON-decoded forms of T, such
+	// as maps and JSON strings, e.g. for outputs restored from storage.
+	Decode(finalOutput any) (T, error)
+}
+
+// OutputTypeOf creates a new output type for T with a strict JSON schema
+// derived from its Go type. Struct fields are described by their json tags
+// and by jsonschema tags, e.g.
+//
+//	type Review struct {
+//		Verdict string `json:"verdict" jsonschema:"enum=approve,enum=reject"`
+//		Reason  string `json:"reason" jsonschema:"description=Why the verdict was given"`
+//	}
+//
+//	outputType := agents.OutputTypeOf[Review]()
+//	agent := agents.New("Reviewer").WithOutputType(outputType)
+//	...
+//	review, err := outputType.Decode(result.FinalOutput)
+//
+// It panics in case of errors. For a safer variant, see SafeOutputTypeOf.
+func OutputTypeOf[T any]() TypedOutputType[T] {
+	result, err := SafeOutputTypeOf[T](defaultOutputTypeOpts)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// SafeOutputTypeOf creates a new output type for T with custom options.
+func SafeOutputTypeOf[T any](opts OutputTypeOpts) (TypedOutputType[T], error) {
+	result, err := SafeOutputType[T](opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.(outputTypeImpl[T]), nil
+}
+
+// reflectOutputSchema returns the JSON schema of the Go type of v.
+func reflectOutputSchema(v any, opts OutputTypeOpts) (map[string]any, error) {
+	reflector := jsonschema.Reflector{
+		Anonymous:                 true,
+		AllowAdditionalProperties: !opts.StrictJSONSchema,
+		ExpandedStruct:            true,
+	}
+	b, err := json.Marshal(reflector.Reflect(v))
+	if err != nil {
+		return nil, fmt.Errorf("failed to JSON-marshal JSON schema: %w", err)
+	}
+	var schema map[string]any
+	err = json.Unmarshal(b, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to JSON-unmarshal JSON schema: %w", err)
+	}
+	return schema, nil
+}
+
 // isStruct reports whether v is a struct or pointer to struct.
 func isStruct[T any]() bool {
 	var zero T
@@ -225,3 +276,26 @@ func (t outputTypeImpl[T]) ValidateJSON(ctx context.Context, jsonStr string) (_
 		return output, nil
 	}
 }
+
+func (t outputTypeImpl[T]) Decode(finalOutput any) (T, error) {
+	var output T
+	switch v := finalOutput.(type) {
+	case T:
+		return v, nil
+	case nil:
+		return output, fmt.Errorf("cannot decode nil final output as %s", t.name)
+	case string:
+		if err := json.Unmarshal([]byte(v), &output); err != nil {
+			return output, fmt.Errorf("failed to decode final output as %s: %w", t.name, err)
+		}
+		return output, nil
+	}
+	b, err := json.Marshal(finalOutput)
+	if err != nil {
+		return output, fmt.Errorf("failed to JSON-marshal final output: %w", err)
+	}
+	if err = json.Unmarshal(b, &output); err != nil {
+		return output, fmt.Errorf("failed to decode final output as %s: %w", t.name, err)
+	}
+	return output, nil
+}
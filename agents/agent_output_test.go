@@ -128,6 +128,88 @@ func TestOutputType(t *testing.T) {
 	})
 }
 
+func TestOutputTypeOf(t *testing.T) {
+	type Review struct {
+		Verdict string   `json:"verdict" jsonschema:"enum=approve,enum=reject"`
+		Reason  string   `json:"reason" jsonschema:"description=Why the verdict was given"`
+		Tags    []string `json:"tags"`
+	}
+
+	type m = map[string]any
+
+	t.Run("schema from struct tags", func(t *testing.T) {
+		ot := agents.OutputTypeOf[Review]()
+
+		assert.False(t, ot.IsPlainText())
+		assert.Equal(t, "agents_test.Review", ot.Name())
+		assert.True(t, ot.IsStrictJSONSchema())
+
+		schema, err := ot.JSONSchema()
+		require.NoError(t, err)
+		assert.Equal(t, m{
+			"$schema":              "https://json-schema.org/draft/2020-12/schema",
+			"type":                 "object",
+			"required":             []any{"reason", "tags", "verdict"},
+			"additionalProperties": false,
+			"properties": m{
+				"verdict": m{"type": "string", "enum": []any{"approve", "reject"}},
+				"reason":  m{"type": "string", "description": "Why the verdict was given"},
+				"tags":    m{"type": "array", "items": m{"type": "string"}},
+			},
+		}, schema)
+
+		_, err = ot.ValidateJSON(t.Context(), `{"verdict": "maybe", "reason": "", "tags": []}`)
+		require.ErrorAs(t, err, &agents.ModelBehaviorError{})
+	})
+
+	t.Run("decode final output", func(t *testing.T) {
+		ot := agents.OutputTypeOf[Review]()
+		want := Review{Verdict: "approve", Reason: "looks good", Tags: []string{"go"}}
+
+		validated, err := ot.ValidateJSON(t.Context(), `{"verdict": "approve", "reason": "looks good", "tags": ["go"]}`)
+		require.NoError(t, err)
+		review, err := ot.Decode(validated)
+		require.NoError(t, err)
+		assert.Equal(t, want, review)
+
+		review, err = ot.Decode(m{"verdict": "approve", "reason": "looks good", "tags": []any{"go"}})
+		require.NoError(t, err)
+		assert.Equal(t, want, review)
+
+		review, err = ot.Decode(`{"verdict": "approve", "reason": "looks good", "tags": ["go"]}`)
+		require.NoError(t, err)
+		assert.Equal(t, want, review)
+
+		_, err = ot.Decode(nil)
+		assert.Error(t, err)
+		_, err = ot.Decode([]string{"not", "a", "review"})
+		assert.Error(t, err)
+	})
+
+	t.Run("decode wrapped output", func(t *testing.T) {
+		ot := agents.OutputTypeOf[[]int]()
+
+		validated, err := ot.ValidateJSON(t.Context(), `{"response": [1, 2]}`)
+		require.NoError(t, err)
+		values, err := ot.Decode(validated)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, values)
+
+		values, err = ot.Decode([]any{1.0, 2.0})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, values)
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		ot := agents.OutputTypeOf[string]()
+
+		assert.True(t, ot.IsPlainText())
+		text, err := ot.Decode("hello")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", text)
+	})
+}
+
 var CustomOutputTypeJSONSchema = map[string]any{
 	"type": "object",
 	"properties": map[string]any{
@@ -0,0 +1,231 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// UnionDiscriminator is the property holding the kind of the variant in the
+// JSON produced for union output types.
+const UnionDiscriminator = "type"
+
+// OutputVariant is a variant of a union output type, see UnionOutputType.
+type OutputVariant struct {
+	kind    string
+	typ     reflect.Type
+	reflect func(OutputTypeOpts) (map[string]any, error)
+	decode  func([]byte) (any, error)
+}
+
+// OutputVariantOf declares a variant of kind `kind` of a union output type,
+// whose value is the struct T.
+func OutputVariantOf[T any](kind string) OutputVariant {
+	return OutputVariant{
+		kind: kind,
+		typ:  reflect.TypeFor[T](),
+		reflect: func(opts OutputTypeOpts) (map[string]any, error) {
+			var zero T
+			return reflectOutputSchema(zero, opts)
+		},
+		decode: func(b []byte) (any, error) {
+			var value T
+			err := json.Unmarshal(b, &value)
+			return value, err
+		},
+	}
+}
+
+// UnionOutput is the final output of the agents with a union output type.
+// Value is of the Go type of the variant of kind Kind, see UnionOutputAs.
+type UnionOutput struct {
+	Kind  string `json:"kind"`
+	Value any    `json:"value"`
+}
+
+// UnionOutputAs returns the value of a union final output, and whether it is
+// of type T.
+//
+//	if answer, ok := agents.UnionOutputAs[Answer](result.FinalOutput); ok {
+//		...
+//	}
+func UnionOutputAs[T any](finalOutput any) (T, bool) {
+	if output, ok := finalOutput.(UnionOutput); ok {
+		value, ok := output.Value.(T)
+		return value, ok
+	}
+	var zero T
+	return zero, false
+}
+
+type unionOutputType struct {
+	name             string
+	variants         map[string]OutputVariant
+	outputSchema     map[string]any
+	strictJSONSchema bool
+}
+
+// UnionOutputType creates an output type whose values are one of the given
+// variants, e.g. an answer, a clarification request or an escalation, each
+// described by its own struct. The model produces a variant tagged with its
+// kind in the UnionDiscriminator property, and the final output is a
+// UnionOutput. The schema is strict.
+// It panics in case of errors. For a safer variant, see SafeUnionOutputType.
+func UnionOutputType(name string, variants ...OutputVariant) OutputTypeInterface {
+	result, err := SafeUnionOutputType(name, defaultOutputTypeOpts, variants...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// SafeUnionOutputType creates a union output type with custom options.
+func SafeUnionOutputType(name string, opts OutputTypeOpts, variants ...OutputVariant) (OutputTypeInterface, error) {
+	if len(variants) == 0 {
+		return nil, NewUserError("union output type requires at least one variant")
+	}
+
+	t := unionOutputType{
+		name:             name,
+		variants:         make(map[string]OutputVariant, len(variants)),
+		strictJSONSchema: opts.StrictJSONSchema,
+	}
+	defs := make(map[string]any)
+	anyOf := make([]any, len(variants))
+
+	for i, variant := range variants {
+		if variant.kind == "" {
+			return nil, UserErrorf("union output type variant %d has no kind", i)
+		}
+		if _, dup := t.variants[variant.kind]; dup {
+			return nil, UserErrorf("union output type has duplicate variant kind %q", variant.kind)
+		}
+		t.variants[variant.kind] = variant
+
+		if variant.typ.Kind() != reflect.Struct {
+			return nil, UserErrorf("union output type variant %q must be a struct, got %s", variant.kind, variant.typ)
+		}
+		schema, err := variant.reflect(opts)
+		if err != nil {
+			return nil, err
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		if properties == nil {
+			properties = make(map[string]any)
+			schema["properties"] = properties
+		}
+		if _, ok := properties[UnionDiscriminator]; ok {
+			return nil, UserErrorf("union output type variant %q cannot have a %q property", variant.kind, UnionDiscriminator)
+		}
+		properties[UnionDiscriminator] = map[string]any{"type": "string", "enum": []any{variant.kind}}
+		required, _ := schema["required"].([]any)
+		schema["required"] = append([]any{UnionDiscriminator}, required...)
+
+		// The definitions of the nested types are moved to the root.
+		if variantDefs, ok := schema["$defs"].(map[string]any); ok {
+			for defName, def := range variantDefs {
+				if existing, ok := defs[defName]; ok && !reflect.DeepEqual(existing, def) {
+					return nil, UserErrorf("union output type variants have conflicting definitions of %q", defName)
+				}
+				defs[defName] = def
+			}
+		}
+		delete(schema, "$defs")
+		delete(schema, "$schema")
+		anyOf[i] = schema
+	}
+
+	t.outputSchema = map[string]any{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"type":     "object",
+		"required": []any{"response"},
+		"properties": map[string]any{
+			"response": map[string]any{"anyOf": anyOf},
+		},
+	}
+	if len(defs) > 0 {
+		t.outputSchema["$defs"] = defs
+	}
+
+	if opts.StrictJSONSchema {
+		var err error
+		t.outputSchema, err = EnsureStrictJSONSchema(t.outputSchema)
+		if err != nil {
+			var userError UserError
+			if errors.As(err, &userError) {
+				return nil, UserErrorf(
+					"strict JSON schema is enabled, but the union output type is not valid: either make the "+
+						"variants strict, or disable strict JSON schema; error: %w", userError,
+				)
+			}
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t unionOutputType) IsPlainText() bool                   { return false }
+func (t unionOutputType) Name() string                        { return t.name }
+func (t unionOutputType) IsStrictJSONSchema() bool            { return t.strictJSONSchema }
+func (t unionOutputType) JSONSchema() (map[string]any, error) { return t.outputSchema, nil }
+
+func (t unionOutputType) ValidateJSON(ctx context.Context, jsonStr string) (_ any, err error) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(t.outputSchema))
+	if err != nil {
+		return nil, ModelBehaviorErrorf("failed to load and compile output JSON schema: %w", err)
+	}
+
+	err = ValidateJSON(ctx, schema, jsonStr)
+	if err != nil {
+		return nil, fmt.Errorf("output type validation error: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			AttachErrorToCurrentSpan(ctx, tracing.SpanError{
+				Message: "Invalid JSON",
+				Data:    map[string]any{"details": err.Error()},
+			})
+		}
+	}()
+
+	var wrapped struct {
+		Response json.RawMessage `json:"response"`
+	}
+	if err = json.Unmarshal([]byte(jsonStr), &wrapped); err != nil {
+		return nil, ModelBehaviorErrorf("failed to unmarshal JSON output (wrapped): %w", err)
+	}
+	var tagged map[string]any
+	if err = json.Unmarshal(wrapped.Response, &tagged); err != nil {
+		return nil, ModelBehaviorErrorf("failed to unmarshal JSON output: %w", err)
+	}
+	kind, _ := tagged[UnionDiscriminator].(string)
+	variant, ok := t.variants[kind]
+	if !ok {
+		return nil, ModelBehaviorErrorf("unknown union output variant %q", kind)
+	}
+	value, err := variant.decode(wrapped.Response)
+	if err != nil {
+		return nil, ModelBehaviorErrorf("failed to unmarshal JSON output of variant %q: %w", kind, err)
+	}
+	return UnionOutput{Kind: kind, Value: value}, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type UnionTestAnswer struct {
+	Text string `json:"text"`
+}
+
+type UnionTestClarification struct {
+	Question string `json:"question"`
+}
+
+type UnionTestEscalation struct {
+	Team     string                `json:"team"`
+	Severity UnionTestSeverityInfo `json:"severity"`
+}
+
+type UnionTestSeverityInfo struct {
+	Level int `json:"level"`
+}
+
+func unionTestOutputType() agents.OutputTypeInterface {
+	return agents.UnionOutputType("Reply",
+		agents.OutputVariantOf[UnionTestAnswer]("answer"),
+		agents.OutputVariantOf[UnionTestClarification]("clarification_request"),
+		agents.OutputVariantOf[UnionTestEscalation]("escalation"),
+	)
+}
+
+func TestUnionOutputType(t *testing.T) {
+	type m = map[string]any
+
+	t.Run("schema", func(t *testing.T) {
+		ot := agents.UnionOutputType("Reply",
+			agents.OutputVariantOf[UnionTestAnswer]("answer"),
+			agents.OutputVariantOf[UnionTestClarification]("clarification_request"),
+		)
+
+		assert.False(t, ot.IsPlainText())
+		assert.Equal(t, "Reply", ot.Name())
+		assert.True(t, ot.IsStrictJSONSchema())
+
+		schema, err := ot.JSONSchema()
+		require.NoError(t, err)
+		assert.Equal(t, m{
+			"$schema":              "https://json-schema.org/draft/2020-12/schema",
+			"type":                 "object",
+			"required":             []any{"response"},
+			"additionalProperties": false,
+			"properties": m{"response": m{"anyOf": []any{
+				m{
+					"type":                 "object",
+					"required":             []any{"text", "type"},
+					"additionalProperties": false,
+					"properties": m{
+						"type": m{"type": "string", "enum": []any{"answer"}},
+						"text": m{"type": "string"},
+					},
+				},
+				m{
+					"type":                 "object",
+					"required":             []any{"question", "type"},
+					"additionalProperties": false,
+					"properties": m{
+						"type":     m{"type": "string", "enum": []any{"clarification_request"}},
+						"question": m{"type": "string"},
+					},
+				},
+			}}},
+		}, schema)
+	})
+
+	t.Run("validate variants", func(t *testing.T) {
+		ot := unionTestOutputType()
+
+		validated, err := ot.ValidateJSON(t.Context(), `{"response": {"type": "answer", "text": "42"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, agents.UnionOutput{Kind: "answer", Value: UnionTestAnswer{Text: "42"}}, validated)
+
+		validated, err = ot.ValidateJSON(t.Context(), `{"response": {"type": "escalation", "team": "billing", "severity": {"level": 2}}}`)
+		require.NoError(t, err)
+		escalation, ok := agents.UnionOutputAs[UnionTestEscalation](validated)
+		require.True(t, ok)
+		assert.Equal(t, UnionTestEscalation{Team: "billing", Severity: UnionTestSeverityInfo{Level: 2}}, escalation)
+
+		_, ok = agents.UnionOutputAs[UnionTestAnswer](validated)
+		assert.False(t, ok)
+		_, ok = agents.UnionOutputAs[UnionTestAnswer]("not a union output")
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid JSON causes error", func(t *testing.T) {
+		ot := unionTestOutputType()
+
+		badValues := []string{
+			`not valid JSON`,
+			`{"response": {"type": "answer"}}`,
+			`{"response": {"type": "unknown", "text": "42"}}`,
+			`{"response": {"type": "answer", "question": "?"}}`,
+			`{"response": {"text": "42"}}`,
+		}
+		for _, val := range badValues {
+			_, err := ot.ValidateJSON(t.Context(), val)
+			require.ErrorAs(t, err, &agents.ModelBehaviorError{}, val)
+		}
+	})
+
+	t.Run("invalid variants", func(t *testing.T) {
+		type Tagged struct {
+			Type string `json:"type"`
+		}
+
+		_, err := agents.SafeUnionOutputType("Reply", agents.OutputTypeOpts{StrictJSONSchema: true})
+		require.ErrorAs(t, err, &agents.UserError{})
+
+		_, err = agents.SafeUnionOutputType("Reply", agents.OutputTypeOpts{StrictJSONSchema: true},
+			agents.OutputVariantOf[UnionTestAnswer]("answer"),
+			agents.OutputVariantOf[UnionTestClarification]("answer"),
+		)
+		require.ErrorAs(t, err, &agents.UserError{})
+
+		_, err = agents.SafeUnionOutputType("Reply", agents.OutputTypeOpts{StrictJSONSchema: true},
+			agents.OutputVariantOf[[]string]("list"),
+		)
+		require.ErrorAs(t, err, &agents.UserError{})
+
+		_, err = agents.SafeUnionOutputType("Reply", agents.OutputTypeOpts{StrictJSONSchema: true},
+			agents.OutputVariantOf[Tagged]("tagged"),
+		)
+		require.ErrorAs(t, err, &agents.UserError{})
+	})
+}
+
+func TestUnionOutputTypeFinalOutput(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := &agents.Agent{
+		Name:       "test",
+		Model:      param.NewOpt(agents.NewAgentModel(model)),
+		OutputType: unionTestOutputType(),
+	}
+
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"response": {"type": "clarification_request", "question": "Which account?"}}`),
+		},
+	})
+
+	result, err := agents.Runner{}.Run(t.Context(), agent, "user_message")
+	require.NoError(t, err)
+
+	output, ok := result.FinalOutput.(agents.UnionOutput)
+	require.True(t, ok)
+	assert.Equal(t, "clarification_request", output.Kind)
+	clarification, ok := agents.UnionOutputAs[UnionTestClarification](result.FinalOutput)
+	require.True(t, ok)
+	assert.Equal(t, "Which account?", clarification.Question)
+}
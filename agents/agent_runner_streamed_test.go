@@ -656,3 +656,36 @@ func TestDynamicToolAdditionRunStreamed(t *testing.T) {
 	assert.Equal(t, "done", result.FinalOutput())
 	assert.True(t, tool2Called)
 }
+
+func TestStructuredOutputValidationRetriesStreamed(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := &agents.Agent{
+		Name:       "test",
+		Model:      param.NewOpt(agents.NewAgentModel(model)),
+		OutputType: agents.OutputType[AgentRunnerTestFoo](),
+	}
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{}`),
+		}},
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": "baz"}`),
+		}},
+	})
+
+	result, err := agents.Runner{Config: agents.RunConfig{OutputValidationRetries: 1}}.RunStreamed(t.Context(), agent, "user_message")
+	require.NoError(t, err)
+	var failed []agents.RunItem
+	err = result.StreamEvents(func(event agents.StreamEvent) error {
+		if e, ok := event.(agents.RunItemStreamEvent); ok && e.Name == agents.StreamEventOutputValidationFailed {
+			failed = append(failed, e.Item)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, AgentRunnerTestFoo{Bar: "baz"}, result.FinalOutput())
+
+	require.Len(t, failed, 1)
+	feedback := failed[0].(agents.OutputValidationFeedbackItem)
+	assert.Contains(t, feedback.Feedback, "- /bar: bar is required (expected: present)")
+}
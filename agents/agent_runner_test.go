@@ -831,3 +831,67 @@ func TestTestRunStreamedSeq(t *testing.T) {
 	assert.Greater(t, seen, 0)
 	assert.NoError(t, seq.Err)
 }
+
+func TestStructuredOutputValidationErrorReportsPaths(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := &agents.Agent{
+		Name:       "test",
+		Model:      param.NewOpt(agents.NewAgentModel(model)),
+		OutputType: agents.OutputType[AgentRunnerTestFoo](),
+	}
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": 1}`),
+		},
+	})
+
+	_, err := agents.Runner{}.Run(t.Context(), agent, "user_message")
+	var validationErr *agents.JSONValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Violations, 1)
+	assert.Equal(t, "/bar", validationErr.Violations[0].Path)
+	assert.Equal(t, "string", validationErr.Violations[0].Expected)
+}
+
+func TestStructuredOutputValidationRetries(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := &agents.Agent{
+		Name:       "test",
+		Model:      param.NewOpt(agents.NewAgentModel(model)),
+		OutputType: agents.OutputType[AgentRunnerTestFoo](),
+	}
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": 1}`),
+		}},
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": "baz"}`),
+		}},
+	})
+
+	result, err := agents.Runner{Config: agents.RunConfig{OutputValidationRetries: 1}}.Run(t.Context(), agent, "user_message")
+	require.NoError(t, err)
+	assert.Equal(t, AgentRunnerTestFoo{Bar: "baz"}, result.FinalOutput)
+
+	require.Len(t, result.NewItems, 3)
+	feedback, ok := result.NewItems[1].(agents.OutputValidationFeedbackItem)
+	require.True(t, ok)
+	assert.Contains(t, feedback.Feedback, "- /bar: Invalid type. Expected: string, given: integer (expected: string)")
+
+	// The feedback is the last input of the second turn.
+	input := model.LastTurnArgs.Input.(agents.InputItems)
+	assert.Equal(t, feedback.ToInputItem(), input[len(input)-1])
+
+	// Retries are bounded.
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": 1}`),
+		}},
+		{Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"bar": 2}`),
+		}},
+	})
+	_, err = agents.Runner{Config: agents.RunConfig{OutputValidationRetries: 1}}.Run(t.Context(), agent, "user_message")
+	var validationErr *agents.JSONValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/usage"
+)
+
+// AgentUsageLimits bound the share of a run used by an agent, so that a
+// noisy agent cannot consume the whole budget of a multi-agent run. They
+// apply on top of RunConfig.MaxTurns. Zero values are unlimited.
+type AgentUsageLimits struct {
+	// The maximum number of turns of the agent in a run.
+	MaxTurns uint64
+
+	// The maximum number of tool calls of the agent in a run, handoffs
+	// excluded. The run fails before running the calls exceeding it.
+	MaxToolCalls uint64
+
+	// The maximum number of tokens used by the agent in a run. Since usage is
+	// known once a model response is complete, the run fails after the
+	// response exceeding it.
+	MaxTokens uint64
+}
+
+// agentUsage counts the usage of an agent in a run.
+type agentUsage struct {
+	turns, toolCalls, tokens uint64
+}
+
+type agentUsageState struct {
+	mu     sync.Mutex
+	usages map[*Agent]*agentUsage
+}
+
+type agentUsageStateKey struct{}
+
+// contextWithAgentUsageState returns a context counting the usage of the
+// agents of a run.
+func contextWithAgentUsageState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, agentUsageStateKey{}, &agentUsageState{usages: make(map[*Agent]*agentUsage)})
+}
+
+// updateAgentUsage applies update to the usage of agent, if it has limits.
+func updateAgentUsage(ctx context.Context, agent *Agent, update func(*agentUsage) error) error {
+	if agent.UsageLimits == (AgentUsageLimits{}) {
+		return nil
+	}
+	s, _ := ctx.Value(agentUsageStateKey{}).(*agentUsageState)
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usages[agent]
+	if u == nil {
+		u = new(agentUsage)
+		s.usages[agent] = u
+	}
+	return update(u)
+}
+
+// startAgentTurn counts a turn of the agent, failing if it exceeds its
+// limits.
+func startAgentTurn(ctx context.Context, agent *Agent) error {
+	limits := agent.UsageLimits
+	return updateAgentUsage(ctx, agent, func(u *agentUsage) error {
+		if limits.MaxTokens > 0 && u.tokens >= limits.MaxTokens {
+			return agentUsageLimitExceeded(ctx, agent, "max_tokens", limits.MaxTokens, u.tokens)
+		}
+		u.turns++
+		if limits.MaxTurns > 0 && u.turns > limits.MaxTurns {
+			return agentUsageLimitExceeded(ctx, agent, "max_turns", limits.MaxTurns, u.turns)
+		}
+		return nil
+	})
+}
+
+// recordAgentResponse counts the tokens and tool calls of a model response
+// of the agent, failing if they exceed its limits.
+func recordAgentResponse(ctx context.Context, agent *Agent, responseUsage *usage.Usage, toolCalls int) error {
+	limits := agent.UsageLimits
+	return updateAgentUsage(ctx, agent, func(u *agentUsage) error {
+		if responseUsage != nil {
+			u.tokens += responseUsage.TotalTokens
+		}
+		u.toolCalls += uint64(toolCalls)
+		if limits.MaxTokens > 0 && u.tokens > limits.MaxTokens {
+			return agentUsageLimitExceeded(ctx, agent, "max_tokens", limits.MaxTokens, u.tokens)
+		}
+		if limits.MaxToolCalls > 0 && u.toolCalls > limits.MaxToolCalls {
+			return agentUsageLimitExceeded(ctx, agent, "max_tool_calls", limits.MaxToolCalls, u.toolCalls)
+		}
+		return nil
+	})
+}
+
+func agentUsageLimitExceeded(ctx context.Context, agent *Agent, limit string, max, used uint64) error {
+	Logger().WarnContext(ctx, "Agent usage limit exceeded",
+		slog.String("agent", agent.Name), slog.String("limit", limit),
+		slog.Uint64("max", max), slog.Uint64("used", used))
+	return NewAgentUsageLimitExceededError(agent.Name, limit, max, used)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runWithLimits(t *testing.T, streaming bool, agent *agents.Agent) error {
+	t.Helper()
+	if !streaming {
+		_, err := agents.Run(t.Context(), agent, "hello")
+		return err
+	}
+	result, err := agents.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	return result.StreamEvents(func(agents.StreamEvent) error { return nil })
+}
+
+func TestAgentUsageLimitsMaxTurns(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		agent := agents.New("test").
+			WithModelInstance(model).
+			WithTools(agentstesting.GetFunctionTool("foo", "result")).
+			WithUsageLimits(agents.AgentUsageLimits{MaxTurns: 1})
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		err := runWithLimits(t, streaming, agent)
+		var limitErr agents.AgentUsageLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, "test", limitErr.Agent)
+		assert.Equal(t, "max_turns", limitErr.Limit)
+		assert.Equal(t, uint64(1), limitErr.Max)
+		assert.Equal(t, uint64(2), limitErr.Used)
+	}
+}
+
+func TestAgentUsageLimitsMaxToolCalls(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		called := 0
+		tool := agents.NewFunctionTool("foo", "", func(context.Context, struct{}) (string, error) {
+			called++
+			return "result", nil
+		})
+		model := agentstesting.NewFakeModel(false, nil)
+		agent := agents.New("test").
+			WithModelInstance(model).
+			WithTools(tool).
+			WithUsageLimits(agents.AgentUsageLimits{MaxToolCalls: 2})
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{
+				agentstesting.GetFunctionToolCall("foo", "{}"),
+				agentstesting.GetFunctionToolCall("foo", "{}"),
+			}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		err := runWithLimits(t, streaming, agent)
+		var limitErr agents.AgentUsageLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, "max_tool_calls", limitErr.Limit)
+		assert.Equal(t, uint64(3), limitErr.Used)
+		assert.Equal(t, 1, called, "the calls exceeding the limit must not run")
+	}
+}
+
+func TestAgentUsageLimitsMaxTokens(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		model.SetHardcodedUsage(usage.Usage{Requests: 1, InputTokens: 60, OutputTokens: 10, TotalTokens: 70})
+		agent := agents.New("test").
+			WithModelInstance(model).
+			WithTools(agentstesting.GetFunctionTool("foo", "result")).
+			WithUsageLimits(agents.AgentUsageLimits{MaxTokens: 100})
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		err := runWithLimits(t, streaming, agent)
+		var limitErr agents.AgentUsageLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, "max_tokens", limitErr.Limit)
+		assert.Equal(t, uint64(140), limitErr.Used)
+	}
+}
+
+func TestAgentUsageLimitsArePerAgent(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		limits := agents.AgentUsageLimits{MaxTurns: 2}
+		target := agents.New("target").
+			WithModelInstance(model).
+			WithTools(agentstesting.GetFunctionTool("foo", "result")).
+			WithUsageLimits(limits)
+		source := agents.New("source").
+			WithModelInstance(model).
+			WithTools(agentstesting.GetFunctionTool("foo", "result")).
+			WithAgentHandoffs(target).
+			WithUsageLimits(limits)
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetHandoffToolCall(target, "", "")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		require.NoError(t, runWithLimits(t, streaming, source))
+		assert.Empty(t, model.TurnOutputs, "all the turns ran")
+	}
+}
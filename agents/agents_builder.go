@@ -15,145 +15,183 @@
 package agents
 
 import (
+	"github.com/nlpodyssey/openai-agents-go/memory"
 	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 	"github.com/openai/openai-go/v3/packages/param"
 )
 
 // New creates a new Agent with the given name.
 //
-// The returned Agent can be further configured using the builder methods.
+// The returned Agent can be further configured using the builder methods,
+// until it is frozen.
 func New(name string) *Agent {
 	return &Agent{Name: name}
 }
 
 // WithInstructions sets the Agent instructions.
 func (a *Agent) WithInstructions(instr string) *Agent {
+	a.mustBeMutable()
 	a.Instructions = InstructionsStr(instr)
 	return a
 }
 
 // WithInstructionsFunc sets dynamic instructions using an InstructionsFunc.
 func (a *Agent) WithInstructionsFunc(fn InstructionsFunc) *Agent {
+	a.mustBeMutable()
 	a.Instructions = fn
 	return a
 }
 
 // WithInstructionsGetter sets custom instructions implementing InstructionsGetter.
 func (a *Agent) WithInstructionsGetter(g InstructionsGetter) *Agent {
+	a.mustBeMutable()
 	a.Instructions = g
 	return a
 }
 
 // WithPrompt sets the agent's static or dynamic prompt.
 func (a *Agent) WithPrompt(prompt Prompter) *Agent {
+	a.mustBeMutable()
 	a.Prompt = prompt
 	return a
 }
 
 // WithHandoffDescription sets the handoff description.
 func (a *Agent) WithHandoffDescription(desc string) *Agent {
+	a.mustBeMutable()
 	a.HandoffDescription = desc
 	return a
 }
 
 // WithHandoffs sets the agent handoffs.
 func (a *Agent) WithHandoffs(handoffs ...Handoff) *Agent {
+	a.mustBeMutable()
 	a.Handoffs = handoffs
 	return a
 }
 
 // WithAgentHandoffs sets the agent handoffs using Agent pointers.
 func (a *Agent) WithAgentHandoffs(agents ...*Agent) *Agent {
+	a.mustBeMutable()
 	a.AgentHandoffs = agents
 	return a
 }
 
 // WithModel sets the model to use by name.
 func (a *Agent) WithModel(name string) *Agent {
+	a.mustBeMutable()
 	a.Model = param.NewOpt(NewAgentModelName(name))
 	return a
 }
 
 // WithModelInstance sets the model using a Model implementation.
 func (a *Agent) WithModelInstance(m Model) *Agent {
+	a.mustBeMutable()
 	a.Model = param.NewOpt(NewAgentModel(m))
 	return a
 }
 
 // WithModelOpt sets the model using an AgentModel wrapped in param.Opt.
 func (a *Agent) WithModelOpt(model param.Opt[AgentModel]) *Agent {
+	a.mustBeMutable()
 	a.Model = model
 	return a
 }
 
 // WithModelSettings sets model-specific settings.
 func (a *Agent) WithModelSettings(settings modelsettings.ModelSettings) *Agent {
+	a.mustBeMutable()
 	a.ModelSettings = settings
 	return a
 }
 
 // WithTools sets the list of tools available to the agent.
 func (a *Agent) WithTools(t ...Tool) *Agent {
+	a.mustBeMutable()
 	a.Tools = append([]Tool{}, t...)
 	return a
 }
 
 // AddTool appends a tool to the agent's tool list.
 func (a *Agent) AddTool(t Tool) *Agent {
+	a.mustBeMutable()
 	a.Tools = append(a.Tools, t)
 	return a
 }
 
 // WithMCPServers sets the list of MCP servers available to the agent.
 func (a *Agent) WithMCPServers(mcpServers []MCPServer) *Agent {
+	a.mustBeMutable()
 	a.MCPServers = mcpServers
 	return a
 }
 
 // AddMCPServer appends an MCP server to the agent's MCP server list.
 func (a *Agent) AddMCPServer(mcpServer MCPServer) *Agent {
+	a.mustBeMutable()
 	a.MCPServers = append(a.MCPServers, mcpServer)
 	return a
 }
 
 // WithMCPConfig sets the agent's MCP configuration.
 func (a *Agent) WithMCPConfig(mcpConfig MCPConfig) *Agent {
+	a.mustBeMutable()
 	a.MCPConfig = mcpConfig
 	return a
 }
 
 // WithInputGuardrails sets the input guardrails.
 func (a *Agent) WithInputGuardrails(gr []InputGuardrail) *Agent {
+	a.mustBeMutable()
 	a.InputGuardrails = gr
 	return a
 }
 
 // WithOutputGuardrails sets the output guardrails.
 func (a *Agent) WithOutputGuardrails(gr []OutputGuardrail) *Agent {
+	a.mustBeMutable()
 	a.OutputGuardrails = gr
 	return a
 }
 
 // WithOutputType sets the output type.
 func (a *Agent) WithOutputType(outputType OutputTypeInterface) *Agent {
+	a.mustBeMutable()
 	a.OutputType = outputType
 	return a
 }
 
 // WithHooks sets the lifecycle hooks for the agent.
 func (a *Agent) WithHooks(hooks AgentHooks) *Agent {
+	a.mustBeMutable()
 	a.Hooks = hooks
 	return a
 }
 
 // WithToolUseBehavior configures how tool use is handled.
 func (a *Agent) WithToolUseBehavior(b ToolUseBehavior) *Agent {
+	a.mustBeMutable()
 	a.ToolUseBehavior = b
 	return a
 }
 
 // WithResetToolChoice sets whether tool choice is reset after use.
 func (a *Agent) WithResetToolChoice(v param.Opt[bool]) *Agent {
+	a.mustBeMutable()
 	a.ResetToolChoice = v
 	return a
 }
+
+// WithLongTermMemory sets the long-term memory of the agent.
+func (a *Agent) WithLongTermMemory(ltm memory.LongTermMemory) *Agent {
+	a.mustBeMutable()
+	a.LongTermMemory = ltm
+	return a
+}
+
+// WithUsageLimits sets the limits on the usage of the agent in a run.
+func (a *Agent) WithUsageLimits(limits AgentUsageLimits) *Agent {
+	a.mustBeMutable()
+	a.UsageLimits = limits
+	return a
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// ArtifactKind is the kind of an Artifact.
+type ArtifactKind string
+
+const (
+	ArtifactKindImage ArtifactKind = "image"
+	ArtifactKindFile  ArtifactKind = "file"
+)
+
+// Artifact is an image or a file output by the model or a tool, exposed by
+// the Artifacts methods of the run items. Depending on its source, its
+// content is inline in Data, or referenced by URL or FileID.
+type Artifact struct {
+	Kind ArtifactKind
+
+	// Content of the artifact, when inline, e.g. the result of an image
+	// generation call or a base64 data URL.
+	Data []byte
+
+	// URL of the artifact, when not inline.
+	URL string
+
+	// ID of the artifact in the Files API, or in the container of ContainerID.
+	FileID      string
+	ContainerID string
+
+	// Optional name of the file.
+	Filename string
+
+	// MIME type of the artifact, when known, e.g. "image/png".
+	MIMEType string
+}
+
+// Artifacts returns the files cited by the message, e.g. the files created
+// by the code interpreter or found by the file search.
+func (item MessageOutputItem) Artifacts() []Artifact {
+	var artifacts []Artifact
+	seen := make(map[string]struct{})
+	for _, content := range item.RawItem.Content {
+		for _, annotation := range content.Annotations {
+			switch annotation.Type {
+			case "file_citation", "container_file_citation", "file_path":
+			default:
+				continue
+			}
+			if _, ok := seen[annotation.ContainerID+"/"+annotation.FileID]; ok {
+				continue
+			}
+			seen[annotation.ContainerID+"/"+annotation.FileID] = struct{}{}
+			artifacts = append(artifacts, Artifact{
+				Kind:        ArtifactKindFile,
+				FileID:      annotation.FileID,
+				ContainerID: annotation.ContainerID,
+				Filename:    annotation.Filename,
+				MIMEType:    mime.TypeByExtension(path.Ext(annotation.Filename)),
+			})
+		}
+	}
+	return artifacts
+}
+
+// Artifacts returns the images output by the tool call, e.g. generated by
+// the image generation tool or by the code interpreter.
+func (item ToolCallItem) Artifacts() []Artifact {
+	switch rawItem := item.RawItem.(type) {
+	case ResponseOutputItemImageGenerationCall:
+		if rawItem.Result == "" {
+			return nil
+		}
+		data, err := base64.StdEncoding.DecodeString(rawItem.Result)
+		if err != nil {
+			return nil
+		}
+		return []Artifact{{
+			Kind:     ArtifactKindImage,
+			Data:     data,
+			MIMEType: http.DetectContentType(data),
+		}}
+	case ResponseCodeInterpreterToolCall:
+		var artifacts []Artifact
+		for _, output := range rawItem.Outputs {
+			if output.Type == "image" {
+				artifacts = append(artifacts, urlArtifact(ArtifactKindImage, output.URL))
+			}
+		}
+		return artifacts
+	default:
+		return nil
+	}
+}
+
+// Artifacts returns the images and files output by the tool, e.g. the
+// screenshots of the computer tool.
+func (item ToolCallOutputItem) Artifacts() []Artifact {
+	switch rawItem := item.RawItem.(type) {
+	case ResponseInputItemComputerCallOutputParam:
+		return []Artifact{imageArtifact(rawItem.Output.ImageURL, rawItem.Output.FileID)}
+	case ResponseInputItemFunctionCallOutputParam:
+		var artifacts []Artifact
+		for _, output := range rawItem.Output.OfResponseFunctionCallOutputItemArray {
+			switch {
+			case output.OfInputImage != nil:
+				artifacts = append(artifacts, imageArtifact(output.OfInputImage.ImageURL, output.OfInputImage.FileID))
+			case output.OfInputFile != nil:
+				artifacts = append(artifacts, fileArtifact(output.OfInputFile))
+			}
+		}
+		return artifacts
+	default:
+		return nil
+	}
+}
+
+func imageArtifact(imageURL, fileID param.Opt[string]) Artifact {
+	artifact := urlArtifact(ArtifactKindImage, imageURL.Value)
+	artifact.FileID = fileID.Value
+	return artifact
+}
+
+func fileArtifact(file *responses.ResponseInputFileContentParam) Artifact {
+	artifact := urlArtifact(ArtifactKindFile, file.FileURL.Value)
+	if data, mimeType, ok := parseDataURL(file.FileData.Value); ok {
+		artifact.Data = data
+		artifact.MIMEType = mimeType
+	}
+	artifact.FileID = file.FileID.Value
+	artifact.Filename = file.Filename.Value
+	if artifact.MIMEType == "" {
+		artifact.MIMEType = mime.TypeByExtension(path.Ext(artifact.Filename))
+	}
+	return artifact
+}
+
+// urlArtifact returns the artifact of the URL, decoding the data URLs.
+func urlArtifact(kind ArtifactKind, url string) Artifact {
+	if data, mimeType, ok := parseDataURL(url); ok {
+		return Artifact{Kind: kind, Data: data, MIMEType: mimeType}
+	}
+	return Artifact{Kind: kind, URL: url}
+}
+
+// parseDataURL decodes a base64 data URL, e.g. "data:image/png;base64,...".
+func parseDataURL(url string) (data []byte, mimeType string, ok bool) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return nil, "", false
+	}
+	mimeType, encoded, ok := strings.Cut(rest, ";base64,")
+	if !ok {
+		return nil, "", false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", false
+	}
+	return data, mimeType, true
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+)
+
+// A 1x1 PNG image.
+var testPNG, _ = base64.StdEncoding.DecodeString(
+	"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+
+func TestMessageOutputItemArtifacts(t *testing.T) {
+	item := agents.MessageOutputItem{
+		RawItem: responses.ResponseOutputMessage{
+			Content: []responses.ResponseOutputMessageContentUnion{{
+				Type: "output_text",
+				Text: "See plot.png and the docs.",
+				Annotations: []responses.ResponseOutputTextAnnotationUnion{
+					{Type: "container_file_citation", ContainerID: "cntr_1", FileID: "cfile_1", Filename: "plot.png"},
+					{Type: "url_citation", URL: "https://example.com"},
+					{Type: "file_citation", FileID: "file_1", Filename: "docs.pdf"},
+					{Type: "file_citation", FileID: "file_1", Filename: "docs.pdf"},
+				},
+			}},
+		},
+	}
+	assert.Equal(t, []agents.Artifact{
+		{Kind: agents.ArtifactKindFile, FileID: "cfile_1", ContainerID: "cntr_1", Filename: "plot.png", MIMEType: "image/png"},
+		{Kind: agents.ArtifactKindFile, FileID: "file_1", Filename: "docs.pdf", MIMEType: "application/pdf"},
+	}, item.Artifacts())
+}
+
+func TestToolCallItemArtifacts(t *testing.T) {
+	imageGeneration := agents.ToolCallItem{
+		RawItem: agents.ResponseOutputItemImageGenerationCall{
+			Result: base64.StdEncoding.EncodeToString(testPNG),
+		},
+	}
+	assert.Equal(t, []agents.Artifact{
+		{Kind: agents.ArtifactKindImage, Data: testPNG, MIMEType: "image/png"},
+	}, imageGeneration.Artifacts())
+
+	codeInterpreter := agents.ToolCallItem{
+		RawItem: agents.ResponseCodeInterpreterToolCall{
+			Outputs: []responses.ResponseCodeInterpreterToolCallOutputUnion{
+				{Type: "logs", Logs: "done"},
+				{Type: "image", URL: "https://example.com/plot.png"},
+			},
+		},
+	}
+	assert.Equal(t, []agents.Artifact{
+		{Kind: agents.ArtifactKindImage, URL: "https://example.com/plot.png"},
+	}, codeInterpreter.Artifacts())
+
+	assert.Empty(t, agents.ToolCallItem{RawItem: agents.ResponseFunctionToolCall{}}.Artifacts())
+}
+
+func TestToolCallOutputItemArtifacts(t *testing.T) {
+	screenshot := agents.ToolCallOutputItem{
+		RawItem: agents.ResponseInputItemComputerCallOutputParam{
+			Output: responses.ResponseComputerToolCallOutputScreenshotParam{
+				ImageURL: param.NewOpt("data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG)),
+			},
+		},
+	}
+	assert.Equal(t, []agents.Artifact{
+		{Kind: agents.ArtifactKindImage, Data: testPNG, MIMEType: "image/png"},
+	}, screenshot.Artifacts())
+
+	function := agents.ToolCallOutputItem{
+		RawItem: agents.ResponseInputItemFunctionCallOutputParam{
+			Output: responses.ResponseInputItemFunctionCallOutputOutputUnionParam{
+				OfResponseFunctionCallOutputItemArray: responses.ResponseFunctionCallOutputItemListParam{
+					{OfInputText: &responses.ResponseInputTextContentParam{Text: "chart:"}},
+					{OfInputImage: &responses.ResponseInputImageContentParam{ImageURL: param.NewOpt("https://example.com/chart.png")}},
+					{OfInputFile: &responses.ResponseInputFileContentParam{
+						FileData: param.NewOpt("data:text/plain;base64,aGk="),
+						Filename: param.NewOpt("notes.txt"),
+					}},
+					{OfInputFile: &responses.ResponseInputFileContentParam{FileID: param.NewOpt("file_2"), Filename: param.NewOpt("a.pdf")}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []agents.Artifact{
+		{Kind: agents.ArtifactKindImage, URL: "https://example.com/chart.png"},
+		{Kind: agents.ArtifactKindFile, Data: []byte("hi"), Filename: "notes.txt", MIMEType: "text/plain"},
+		{Kind: agents.ArtifactKindFile, FileID: "file_2", Filename: "a.pdf", MIMEType: "application/pdf"},
+	}, function.Artifacts())
+
+	text := agents.ToolCallOutputItem{
+		RawItem: agents.ResponseInputItemFunctionCallOutputParam{
+			Output: responses.ResponseInputItemFunctionCallOutputOutputUnionParam{OfString: param.NewOpt("ok")},
+		},
+	}
+	assert.Empty(t, text.Artifacts())
+}
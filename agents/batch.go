@@ -0,0 +1,376 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/modelsettings"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// DefaultBatchPollInterval is the interval at which BatchRunner.Wait polls
+// the status of a batch when the BatchRunner does not set it.
+const DefaultBatchPollInterval = 30 * time.Second
+
+// BatchRequest is a request of a batch: a single turn of the agent on the
+// input.
+type BatchRequest struct {
+	// Optional ID of the request, unique in the batch, identifying its
+	// result. Default: "request-<index>".
+	CustomID string
+	Agent    *Agent
+	Input    Input
+}
+
+// BatchResult is the result of a BatchRequest.
+type BatchResult struct {
+	CustomID string
+	Agent    *Agent
+	// Response of the model, nil when the request failed.
+	Response *ModelResponse
+	// FinalOutput of the agent, as for a run: the text of the response, or a
+	// value of the output type of the agent.
+	FinalOutput any
+	// Err is the error of the request, e.g. returned by the API, or a
+	// ModelBehaviorError when the output is not a final output.
+	Err error
+}
+
+// BatchRunner runs single-turn agent requests as a job of the OpenAI Batch
+// API, at half the cost of the synchronous requests, for large offline
+// workloads whose results can wait up to the completion window.
+//
+// Each request is a Responses API request of the agent, as in the first turn
+// of a run: its instructions, model settings, tools, handoffs and output type
+// are sent to the model, but tools and handoffs are not executed, and a
+// response calling them is reported as a ModelBehaviorError. Agents must set
+// the name of an OpenAI model. Guardrails, hooks and sessions are not used.
+type BatchRunner struct {
+	// Optional client. Defaults to the default OpenAI client, see
+	// SetDefaultOpenaiClient.
+	Client *OpenaiClient
+
+	// Optional settings overriding the model settings of the agents, as
+	// RunConfig.ModelSettings.
+	ModelSettings modelsettings.ModelSettings
+
+	// Optional completion window of the batch. Default: "24h".
+	CompletionWindow openai.BatchNewParamsCompletionWindow
+
+	// Optional interval at which Wait polls the status of the batch.
+	// Default: DefaultBatchPollInterval.
+	PollInterval time.Duration
+
+	// Optional metadata of the batch.
+	Metadata map[string]string
+}
+
+// Run submits the requests as a batch, waits for its completion and returns
+// the results, in the order of the requests.
+func (b BatchRunner) Run(ctx context.Context, requests []BatchRequest) ([]BatchResult, error) {
+	batch, err := b.Submit(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	batch, err = b.Wait(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	return b.Results(ctx, batch, requests)
+}
+
+// Submit uploads the requests and creates their batch. The batch can be
+// awaited later, even by another process, with Wait and Results.
+func (b BatchRunner) Submit(ctx context.Context, requests []BatchRequest) (*openai.Batch, error) {
+	if len(requests) == 0 {
+		return nil, UserErrorf("at least one batch request is required")
+	}
+	var data bytes.Buffer
+	customIDs := make(map[string]struct{}, len(requests))
+	for i, req := range requests {
+		customID := batchCustomID(req, i)
+		if _, ok := customIDs[customID]; ok {
+			return nil, UserErrorf("duplicate batch request custom ID %q", customID)
+		}
+		customIDs[customID] = struct{}{}
+
+		body, err := b.requestBody(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("batch request %q: %w", customID, err)
+		}
+		line, err := json.Marshal(map[string]any{
+			"custom_id": customID,
+			"method":    "POST",
+			"url":       string(openai.BatchNewParamsEndpointV1Responses),
+			"body":      body,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("batch request %q: failed to marshal request: %w", customID, err)
+		}
+		data.Write(line)
+		data.WriteByte('\n')
+	}
+
+	client := b.client()
+	fileID, err := uploadFile(ctx, client, data.Bytes(), "batch.jsonl", "application/jsonl", openai.FilePurposeBatch)
+	if err != nil {
+		return nil, err
+	}
+	completionWindow := b.CompletionWindow
+	if completionWindow == "" {
+		completionWindow = openai.BatchNewParamsCompletionWindow24h
+	}
+	batch, err := client.Batches.New(ctx, openai.BatchNewParams{
+		CompletionWindow: completionWindow,
+		Endpoint:         openai.BatchNewParamsEndpointV1Responses,
+		InputFileID:      fileID,
+		Metadata:         b.Metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+	Logger().DebugContext(ctx, "Created batch", slog.String("batch_id", batch.ID), slog.Int("requests", len(requests)))
+	return batch, nil
+}
+
+// Wait polls the batch until it is completed, failed, expired or cancelled.
+// Expired and cancelled batches may still have the results of some
+// requests.
+func (b BatchRunner) Wait(ctx context.Context, batchID string) (*openai.Batch, error) {
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = DefaultBatchPollInterval
+	}
+	client := b.client()
+	for {
+		batch, err := client.Batches.Get(ctx, batchID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch %s: %w", batchID, err)
+		}
+		switch batch.Status {
+		case openai.BatchStatusCompleted, openai.BatchStatusFailed, openai.BatchStatusExpired, openai.BatchStatusCancelled:
+			return batch, nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, context.Cause(ctx)
+		}
+	}
+}
+
+// Results downloads the results of the batch and maps them back to the
+// requests it was submitted with, returning them in the same order. The
+// requests without a result, e.g. in expired batches, have an error.
+func (b BatchRunner) Results(ctx context.Context, batch *openai.Batch, requests []BatchRequest) ([]BatchResult, error) {
+	if batch.Status == openai.BatchStatusFailed {
+		var messages []string
+		for _, e := range batch.Errors.Data {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("batch %s failed: %s", batch.ID, strings.Join(messages, "; "))
+	}
+
+	lines := make(map[string]batchOutputLine, len(requests))
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := b.readOutputFile(ctx, fileID, lines); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]BatchResult, len(requests))
+	for i, req := range requests {
+		customID := batchCustomID(req, i)
+		result := BatchResult{CustomID: customID, Agent: req.Agent}
+		if line, ok := lines[customID]; ok {
+			result.Response, result.FinalOutput, result.Err = line.result(ctx, req.Agent)
+		} else {
+			result.Err = fmt.Errorf("no result for batch request %q: batch %s", customID, batch.Status)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (b BatchRunner) requestBody(ctx context.Context, req BatchRequest) (*responses.ResponseNewParams, error) {
+	agent := req.Agent
+	if agent == nil {
+		return nil, UserErrorf("agent is required")
+	}
+	var modelName string
+	if agent.Model.Valid() {
+		modelName, _ = agent.Model.Value.SafeModelName()
+	}
+	modelName = strings.TrimPrefix(modelName, "openai/")
+	if modelName == "" || strings.Contains(modelName, "/") {
+		return nil, UserErrorf("agent %q must set the name of an OpenAI model", agent.Name)
+	}
+
+	systemPrompt, promptConfig, err := getAgentSystemPromptAndPromptConfig(ctx, agent)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := agent.GetAllTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	handoffs, err := Runner{}.getHandoffs(ctx, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	model := NewOpenAIResponsesModel(modelName, OpenaiClient{})
+	body, _, err := model.prepareRequest(
+		ctx,
+		systemPrompt,
+		req.Input,
+		agent.ModelSettings.Resolve(b.ModelSettings),
+		tools,
+		agent.OutputType,
+		handoffs,
+		"",
+		false,
+		promptConfig,
+	)
+	return body, err
+}
+
+func (b BatchRunner) readOutputFile(ctx context.Context, fileID string, lines map[string]batchOutputLine) error {
+	resp, err := b.client().Files.Content(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line batchOutputLine
+		err := dec.Decode(&line)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode batch file %s: %w", fileID, err)
+		}
+		lines[line.CustomID] = line
+	}
+}
+
+func (b BatchRunner) client() *OpenaiClient {
+	if b.Client != nil {
+		return b.Client
+	}
+	return resolveDefaultOpenaiClient()
+}
+
+func batchCustomID(req BatchRequest, index int) string {
+	if req.CustomID != "" {
+		return req.CustomID
+	}
+	return "request-" + strconv.Itoa(index)
+}
+
+// batchOutputLine is a line of the output or error file of a batch.
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// result maps the line back to the response and the final output of the
+// agent.
+func (line batchOutputLine) result(ctx context.Context, agent *Agent) (*ModelResponse, any, error) {
+	if line.Error != nil {
+		return nil, nil, fmt.Errorf("batch request %q failed: %s: %s", line.CustomID, line.Error.Code, line.Error.Message)
+	}
+	if line.Response == nil {
+		return nil, nil, fmt.Errorf("batch request %q has no response", line.CustomID)
+	}
+	if line.Response.StatusCode != 200 {
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(line.Response.Body, &body)
+		return nil, nil, fmt.Errorf("batch request %q failed with status %d: %s",
+			line.CustomID, line.Response.StatusCode, body.Error.Message)
+	}
+
+	var response responses.Response
+	if err := json.Unmarshal(line.Response.Body, &response); err != nil {
+		return nil, nil, fmt.Errorf("batch request %q: failed to decode response: %w", line.CustomID, err)
+	}
+	u := usage.NewUsage()
+	if !reflect.ValueOf(response.Usage).IsZero() {
+		*u = usage.Usage{
+			Requests:            1,
+			InputTokens:         uint64(response.Usage.InputTokens),
+			InputTokensDetails:  response.Usage.InputTokensDetails,
+			OutputTokens:        uint64(response.Usage.OutputTokens),
+			OutputTokensDetails: response.Usage.OutputTokensDetails,
+			TotalTokens:         uint64(response.Usage.TotalTokens),
+		}
+	}
+	modelResponse := &ModelResponse{Output: response.Output, Usage: u, ResponseID: response.ID}
+
+	var text string
+	var hasText bool
+	for _, item := range response.Output {
+		switch item.Type {
+		case "function_call", "custom_tool_call", "computer_call", "local_shell_call":
+			return modelResponse, nil, ModelBehaviorErrorf(
+				"batch request %q: the model called %s %q, which is not executed in batches", line.CustomID, item.Type, item.Name)
+		case "message":
+			text, hasText = ItemHelpers().ExtractLastText(item)
+		}
+	}
+	if !hasText {
+		return modelResponse, nil, ModelBehaviorErrorf("batch request %q: the model returned no text output", line.CustomID)
+	}
+	outputType := agent.OutputType
+	if outputType == nil || outputType.IsPlainText() {
+		return modelResponse, text, nil
+	}
+	finalOutput, err := outputType.ValidateJSON(ctx, text)
+	if err != nil {
+		return modelResponse, nil, fmt.Errorf("batch request %q: %w", line.CustomID, err)
+	}
+	return modelResponse, finalOutput, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchAPI serves the endpoints of the Files and Batch APIs used by the
+// BatchRunner, answering each request with the output of its custom ID.
+type fakeBatchAPI struct {
+	mu       sync.Mutex
+	outputs  map[string]string // custom ID -> output line, without custom_id
+	errors   map[string]string // custom ID -> error file line, without custom_id
+	requests []map[string]any
+	batch    map[string]any
+	polls    int
+}
+
+func (f *fakeBatchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		file, _, _ := r.FormFile("file")
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var req map[string]any
+			_ = json.Unmarshal(scanner.Bytes(), &req)
+			f.requests = append(f.requests, req)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "file_in"})
+	case r.Method == http.MethodPost && r.URL.Path == "/batches":
+		_ = json.NewDecoder(r.Body).Decode(&f.batch)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "batch_1", "status": "validating"})
+	case r.URL.Path == "/batches/batch_1":
+		f.polls++
+		if f.polls < 2 {
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "batch_1", "status": "in_progress"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":             "batch_1",
+			"status":         "completed",
+			"output_file_id": "file_out",
+			"error_file_id":  "file_err",
+		})
+	case r.URL.Path == "/files/file_out/content":
+		f.writeLines(w, f.outputs)
+	case r.URL.Path == "/files/file_err/content":
+		f.writeLines(w, f.errors)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeBatchAPI) writeLines(w io.Writer, lines map[string]string) {
+	for _, req := range f.requests {
+		customID := req["custom_id"].(string)
+		if line, ok := lines[customID]; ok {
+			_, _ = fmt.Fprintf(w, `{"custom_id": %q, %s}`+"\n", customID, line)
+		}
+	}
+}
+
+func batchResponseLine(output string) string {
+	return `"response": {"status_code": 200, "body": {"id": "resp_1", "object": "response", "output": [` + output +
+		`], "usage": {"input_tokens": 3, "output_tokens": 2, "total_tokens": 5}}}`
+}
+
+func batchMessage(text string) string {
+	return fmt.Sprintf(`{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed", `+
+		`"content": [{"type": "output_text", "text": %q, "annotations": []}]}`, text)
+}
+
+func TestBatchRunner(t *testing.T) {
+	api := &fakeBatchAPI{
+		outputs: map[string]string{
+			"plain": batchResponseLine(batchMessage("Paris")),
+			"typed": batchResponseLine(batchMessage(`{"city": "Rome"}`)),
+			"tool": batchResponseLine(`{"type": "function_call", "id": "fc_1", "call_id": "call_1", ` +
+				`"name": "lookup", "arguments": "{}", "status": "completed"}`),
+		},
+		errors: map[string]string{
+			"failed": `"response": {"status_code": 400, "body": {"error": {"message": "invalid model"}}}`,
+		},
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client := agents.NewOpenaiClient(param.NewOpt(server.URL), param.NewOpt("test_key"))
+
+	type City struct {
+		City string `json:"city"`
+	}
+	plain := agents.New("plain").WithModel("gpt-4.1").WithInstructions("Answer with a city.")
+	typed := agents.New("typed").WithModel("openai/gpt-4.1").WithOutputType(agents.OutputType[City]())
+	requests := []agents.BatchRequest{
+		{CustomID: "plain", Agent: plain, Input: agents.InputString("Capital of France?")},
+		{CustomID: "typed", Agent: typed, Input: agents.InputString("Capital of Italy?")},
+		{CustomID: "tool", Agent: plain, Input: agents.InputString("Look it up.")},
+		{CustomID: "failed", Agent: plain, Input: agents.InputString("Capital of Spain?")},
+		{CustomID: "missing", Agent: plain, Input: agents.InputString("Capital of Peru?")},
+	}
+
+	runner := agents.BatchRunner{Client: &client, PollInterval: time.Millisecond}
+	results, err := runner.Run(t.Context(), requests)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.Equal(t, "/v1/responses", api.batch["endpoint"])
+	assert.Equal(t, "24h", api.batch["completion_window"])
+	assert.Equal(t, "file_in", api.batch["input_file_id"])
+	require.Len(t, api.requests, 5)
+	assert.Equal(t, "plain", api.requests[0]["custom_id"])
+	assert.Equal(t, "/v1/responses", api.requests[0]["url"])
+	body := api.requests[0]["body"].(map[string]any)
+	assert.Equal(t, "gpt-4.1", body["model"])
+	assert.Equal(t, "Answer with a city.", body["instructions"])
+	assert.Equal(t, "gpt-4.1", api.requests[1]["body"].(map[string]any)["model"])
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "Paris", results[0].FinalOutput)
+	assert.Equal(t, uint64(5), results[0].Response.Usage.TotalTokens)
+	assert.Same(t, plain, results[0].Agent)
+
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, City{City: "Rome"}, results[1].FinalOutput)
+
+	var behaviorErr agents.ModelBehaviorError
+	assert.ErrorAs(t, results[2].Err, &behaviorErr)
+	assert.NotNil(t, results[2].Response)
+
+	assert.ErrorContains(t, results[3].Err, "invalid model")
+	assert.Nil(t, results[3].Response)
+
+	assert.ErrorContains(t, results[4].Err, "no result")
+}
+
+func TestBatchRunnerValidation(t *testing.T) {
+	client := agents.NewOpenaiClient(param.NewOpt("http://127.0.0.1:0"), param.NewOpt("test_key"))
+	runner := agents.BatchRunner{Client: &client}
+	agent := agents.New("test").WithModel("gpt-4.1")
+
+	_, err := runner.Submit(t.Context(), nil)
+	assert.Error(t, err)
+
+	_, err = runner.Submit(t.Context(), []agents.BatchRequest{
+		{CustomID: "a", Agent: agent, Input: agents.InputString("hi")},
+		{CustomID: "a", Agent: agent, Input: agents.InputString("hi")},
+	})
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = runner.Submit(t.Context(), []agents.BatchRequest{
+		{Agent: agents.New("no model"), Input: agents.InputString("hi")},
+	})
+	assert.ErrorContains(t, err, "must set the name of an OpenAI model")
+}
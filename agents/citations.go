@@ -0,0 +1,132 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"encoding/json"
+
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// FileSearchResult is a file chunk retrieved by a file search tool call, to
+// be cited as a source of the answer.
+type FileSearchResult struct {
+	// The ID of the file.
+	FileID string `json:"file_id"`
+
+	// The name of the file.
+	Filename string `json:"filename,omitempty"`
+
+	// The relevance score of the chunk, between 0 and 1.
+	Score float64 `json:"score"`
+
+	// The text retrieved from the file.
+	Text string `json:"text,omitempty"`
+
+	// The attributes of the file, with string, number or boolean values.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// FileSearchResults extracts the results of a file search tool call item.
+// They are only present when FileSearchTool.IncludeSearchResults is set.
+func (itemHelpers) FileSearchResults(item ToolCallItem) []FileSearchResult {
+	call, ok := item.RawItem.(ResponseFileSearchToolCall)
+	if !ok || len(call.Results) == 0 {
+		return nil
+	}
+	results := make([]FileSearchResult, len(call.Results))
+	for i, r := range call.Results {
+		results[i] = FileSearchResult{
+			FileID:   r.FileID,
+			Filename: r.Filename,
+			Score:    r.Score,
+			Text:     r.Text,
+		}
+		if len(r.Attributes) == 0 {
+			continue
+		}
+		results[i].Attributes = make(map[string]any, len(r.Attributes))
+		for k, v := range r.Attributes {
+			var value any
+			if err := json.Unmarshal([]byte(v.RawJSON()), &value); err == nil {
+				results[i].Attributes[k] = value
+			}
+		}
+	}
+	return results
+}
+
+// Annotation types of message text content.
+const (
+	AnnotationURLCitation           = "url_citation"
+	AnnotationFileCitation          = "file_citation"
+	AnnotationContainerFileCitation = "container_file_citation"
+	AnnotationFilePath              = "file_path"
+)
+
+// Annotation is a source cited by the text content of a message, such as a
+// web page found by a web search or a file found by a file search.
+type Annotation struct {
+	// The type of the annotation, e.g. AnnotationURLCitation.
+	Type string `json:"type"`
+
+	// The index of the output_text content part of the message which the
+	// annotation refers to.
+	ContentIndex int `json:"content_index"`
+
+	// The URL and title of the web resource of a URL citation.
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	// The file of a file citation, container file citation or file path.
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+
+	// The container of a container file citation.
+	ContainerID string `json:"container_id,omitempty"`
+
+	// The position in the text of a file citation or file path.
+	Index int64 `json:"index,omitempty"`
+
+	// The range of the text citing a URL or container file.
+	StartIndex int64 `json:"start_index,omitempty"`
+	EndIndex   int64 `json:"end_index,omitempty"`
+}
+
+// MessageAnnotations extracts the annotations of the text content of a
+// message.
+func (itemHelpers) MessageAnnotations(message responses.ResponseOutputMessage) []Annotation {
+	var annotations []Annotation
+	for i, part := range message.Content {
+		if part.Type != "output_text" {
+			continue
+		}
+		for _, a := range part.Annotations {
+			annotations = append(annotations, Annotation{
+				Type:         a.Type,
+				ContentIndex: i,
+				URL:          a.URL,
+				Title:        a.Title,
+				FileID:       a.FileID,
+				Filename:     a.Filename,
+				ContainerID:  a.ContainerID,
+				Index:        a.Index,
+				StartIndex:   a.StartIndex,
+				EndIndex:     a.EndIndex,
+			})
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSearchResults(t *testing.T) {
+	var call responses.ResponseFileSearchToolCall
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "fs1",
+		"queries": ["refund policy"],
+		"status": "completed",
+		"type": "file_search_call",
+		"results": [
+			{"file_id": "file-1", "filename": "policy.pdf", "score": 0.9, "text": "Refunds within 30 days.",
+			 "attributes": {"lang": "en", "year": 2025, "public": true}},
+			{"file_id": "file-2", "filename": "faq.md", "score": 0.4, "text": "Contact support."}
+		]
+	}`), &call))
+
+	results := agents.ItemHelpers().FileSearchResults(agents.ToolCallItem{
+		RawItem: agents.ResponseFileSearchToolCall(call),
+	})
+	assert.Equal(t, []agents.FileSearchResult{
+		{
+			FileID:     "file-1",
+			Filename:   "policy.pdf",
+			Score:      0.9,
+			Text:       "Refunds within 30 days.",
+			Attributes: map[string]any{"lang": "en", "year": 2025.0, "public": true},
+		},
+		{FileID: "file-2", Filename: "faq.md", Score: 0.4, Text: "Contact support."},
+	}, results)
+
+	assert.Nil(t, agents.ItemHelpers().FileSearchResults(agents.ToolCallItem{
+		RawItem: agents.ResponseFunctionToolCall{Name: "fn"},
+	}))
+}
+
+func TestMessageAnnotations(t *testing.T) {
+	var message responses.ResponseOutputMessage
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "msg1",
+		"role": "assistant",
+		"status": "completed",
+		"type": "message",
+		"content": [
+			{"type": "output_text", "text": "See the docs.", "annotations": [
+				{"type": "url_citation", "url": "https://example.com", "title": "Docs", "start_index": 8, "end_index": 12}
+			]},
+			{"type": "refusal", "refusal": "no"},
+			{"type": "output_text", "text": "And the policy.", "annotations": [
+				{"type": "file_citation", "file_id": "file-1", "filename": "policy.pdf", "index": 14}
+			]}
+		]
+	}`), &message))
+
+	assert.Equal(t, []agents.Annotation{
+		{
+			Type:         agents.AnnotationURLCitation,
+			ContentIndex: 0,
+			URL:          "https://example.com",
+			Title:        "Docs",
+			StartIndex:   8,
+			EndIndex:     12,
+		},
+		{
+			Type:         agents.AnnotationFileCitation,
+			ContentIndex: 2,
+			FileID:       "file-1",
+			Filename:     "policy.pdf",
+			Index:        14,
+		},
+	}, agents.ItemHelpers().MessageAnnotations(message))
+}
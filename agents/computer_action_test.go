@@ -358,3 +358,21 @@ func TestPendingSafetyCheckAcknowledged(t *testing.T) {
 		},
 	}, called)
 }
+
+func TestAcknowledgeSafetyChecks(t *testing.T) {
+	data := ComputerToolSafetyCheckData{
+		SafetyCheck: responses.ResponseComputerToolCallPendingSafetyCheck{ID: "sc", Code: "malicious_instructions"},
+	}
+
+	ack, err := AcknowledgeSafetyChecks()(t.Context(), data)
+	require.NoError(t, err)
+	assert.True(t, ack)
+
+	ack, err = AcknowledgeSafetyChecks("irrelevant_domain", "malicious_instructions")(t.Context(), data)
+	require.NoError(t, err)
+	assert.True(t, ack)
+
+	ack, err = AcknowledgeSafetyChecks("irrelevant_domain")(t.Context(), data)
+	require.NoError(t, err)
+	assert.False(t, ack)
+}
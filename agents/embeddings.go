@@ -0,0 +1,162 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+// DefaultEmbeddingModel is the OpenAI embedding model used when no model
+// name is given.
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// MaxEmbeddingInputs is the maximum number of texts embedded by a request of
+// the OpenAI Embeddings API; OpenAIEmbeddingModel splits larger batches.
+const MaxEmbeddingInputs = 2048
+
+// EmbeddingModel converts texts into embedding vectors, one for each text in
+// the same order. It implements memory.Embedder, so it can back a
+// memory.VectorMemory.
+type EmbeddingModel interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingModelProvider is implemented by the ModelProviders which also
+// provide embedding models, like OpenAIProvider and MultiProvider.
+type EmbeddingModelProvider interface {
+	// GetEmbeddingModel returns an embedding model by name.
+	GetEmbeddingModel(modelName string) (EmbeddingModel, error)
+}
+
+// NewEmbeddingModel returns the embedding model of the provider by name,
+// which follows the routing of MultiProvider, e.g. "openai/text-embedding-3-large".
+// The provider can be nil to use a MultiProvider with the default settings.
+func NewEmbeddingModel(provider ModelProvider, modelName string) (EmbeddingModel, error) {
+	if provider == nil {
+		provider = NewMultiProvider(NewMultiProviderParams{})
+	}
+	embeddingProvider, ok := provider.(EmbeddingModelProvider)
+	if !ok {
+		return nil, UserErrorf("model provider %T does not provide embedding models", provider)
+	}
+	return embeddingProvider.GetEmbeddingModel(modelName)
+}
+
+// EmbedText embeds a single text with the model.
+func EmbedText(ctx context.Context, model EmbeddingModel, text string) ([]float32, error) {
+	vectors, err := model.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedding model returned %d vectors for 1 text", len(vectors))
+	}
+	return vectors[0], nil
+}
+
+// OpenAIEmbeddingModel is an EmbeddingModel that uses the OpenAI Embeddings
+// API.
+type OpenAIEmbeddingModel struct {
+	Model string
+
+	// Optional number of dimensions of the vectors, for the models
+	// supporting it.
+	Dimensions param.Opt[int64]
+
+	client OpenaiClient
+}
+
+func NewOpenAIEmbeddingModel(model string, client OpenaiClient) OpenAIEmbeddingModel {
+	return OpenAIEmbeddingModel{
+		Model:  model,
+		client: client,
+	}
+}
+
+func (m OpenAIEmbeddingModel) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += MaxEmbeddingInputs {
+		inputs := texts[start:min(start+MaxEmbeddingInputs, len(texts))]
+		response, err := m.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+			Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+			Model:          m.Model,
+			Dimensions:     m.Dimensions,
+			EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create embeddings: %w", err)
+		}
+		if len(response.Data) != len(inputs) {
+			return nil, fmt.Errorf("embeddings API returned %d embeddings for %d texts", len(response.Data), len(inputs))
+		}
+		batch := make([][]float32, len(inputs))
+		for _, embedding := range response.Data {
+			if embedding.Index < 0 || int(embedding.Index) >= len(batch) {
+				return nil, fmt.Errorf("embeddings API returned an embedding with index %d out of range", embedding.Index)
+			}
+			vector := make([]float32, len(embedding.Embedding))
+			for i, v := range embedding.Embedding {
+				vector[i] = float32(v)
+			}
+			batch[embedding.Index] = vector
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// GetEmbeddingModel returns an OpenAIEmbeddingModel, DefaultEmbeddingModel
+// when the name is empty.
+func (provider *OpenAIProvider) GetEmbeddingModel(modelName string) (EmbeddingModel, error) {
+	if modelName == "" {
+		modelName = DefaultEmbeddingModel
+	}
+	return NewOpenAIEmbeddingModel(modelName, provider.getClient()), nil
+}
+
+// GetEmbeddingModel returns an EmbeddingModel based on the model name,
+// routed by prefix like GetModel. The provider of the prefix must implement
+// EmbeddingModelProvider.
+func (mp *MultiProvider) GetEmbeddingModel(modelName string) (EmbeddingModel, error) {
+	prefix, name := mp.getPrefixAndModelName(modelName)
+
+	var provider ModelProvider
+	if prefix != "" && mp.ProviderMap != nil {
+		provider, _ = mp.ProviderMap.GetProvider(prefix)
+	}
+	if provider == nil {
+		fp, err := mp.getFallbackProvider(prefix)
+		if err != nil {
+			return nil, err
+		}
+		provider = fp
+	}
+	embeddingProvider, ok := provider.(EmbeddingModelProvider)
+	if !ok {
+		return nil, UserErrorf("model provider %T of prefix %q does not provide embedding models", provider, prefix)
+	}
+	return embeddingProvider.GetEmbeddingModel(name)
+}
+
+var (
+	_ memory.Embedder        = OpenAIEmbeddingModel{}
+	_ EmbeddingModelProvider = (*OpenAIProvider)(nil)
+	_ EmbeddingModelProvider = (*MultiProvider)(nil)
+)
@@ -0,0 +1,153 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmbeddingsAPI embeds each text as the vector [len(text), index],
+// returning the embeddings in reverse order.
+type fakeEmbeddingsAPI struct {
+	mu       sync.Mutex
+	requests []map[string]any
+}
+
+func (f *fakeEmbeddingsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.requests = append(f.requests, body)
+	var data []map[string]any
+	for i, text := range body["input"].([]any) {
+		data = append(data, map[string]any{
+			"object":    "embedding",
+			"index":     i,
+			"embedding": []float64{float64(len(text.(string))), float64(i)},
+		})
+	}
+	slices.Reverse(data)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data, "model": body["model"]})
+}
+
+func newTestEmbeddingsProvider(t *testing.T) (*agents.MultiProvider, *fakeEmbeddingsAPI) {
+	api := &fakeEmbeddingsAPI{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client := agents.NewOpenaiClient(param.NewOpt(server.URL), param.NewOpt("test_key"))
+	return agents.NewMultiProvider(agents.NewMultiProviderParams{OpenaiClient: &client}), api
+}
+
+func TestOpenAIEmbeddingModel(t *testing.T) {
+	provider, api := newTestEmbeddingsProvider(t)
+
+	model, err := agents.NewEmbeddingModel(provider, "openai/text-embedding-3-large")
+	require.NoError(t, err)
+	vectors, err := model.Embed(t.Context(), []string{"a", "bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {2, 1}, {3, 2}}, vectors)
+	assert.Equal(t, "text-embedding-3-large", api.requests[0]["model"])
+
+	vector, err := agents.EmbedText(t.Context(), model, "dddd")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{4, 0}, vector)
+
+	model, err = agents.NewEmbeddingModel(provider, "")
+	require.NoError(t, err)
+	_, err = model.Embed(t.Context(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, agents.DefaultEmbeddingModel, api.requests[2]["model"])
+}
+
+func TestOpenAIEmbeddingModelSplitsLargeBatches(t *testing.T) {
+	provider, api := newTestEmbeddingsProvider(t)
+	model, err := provider.GetEmbeddingModel("")
+	require.NoError(t, err)
+
+	texts := make([]string, agents.MaxEmbeddingInputs+2)
+	for i := range texts {
+		texts[i] = "text"
+	}
+	vectors, err := model.Embed(t.Context(), texts)
+	require.NoError(t, err)
+	require.Len(t, vectors, len(texts))
+	assert.Equal(t, []float32{4, agents.MaxEmbeddingInputs - 1}, vectors[agents.MaxEmbeddingInputs-1])
+	assert.Equal(t, []float32{4, 1}, vectors[agents.MaxEmbeddingInputs+1])
+	assert.Len(t, api.requests, 2)
+}
+
+type fakeEmbeddingProvider struct {
+	DummyProvider
+	requested string
+}
+
+func (p *fakeEmbeddingProvider) GetEmbeddingModel(modelName string) (agents.EmbeddingModel, error) {
+	p.requested = modelName
+	return fakeEmbeddingModel{}, nil
+}
+
+type fakeEmbeddingModel struct{}
+
+func (fakeEmbeddingModel) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{1}
+	}
+	return vectors, nil
+}
+
+func TestMultiProviderEmbeddingModelRouting(t *testing.T) {
+	providerMap := agents.NewMultiProviderMap()
+	custom := &fakeEmbeddingProvider{}
+	providerMap.AddProvider("custom", custom)
+	providerMap.AddProvider("chat-only", NewDummyProvider(nil))
+	provider := agents.NewMultiProvider(agents.NewMultiProviderParams{ProviderMap: providerMap})
+
+	model, err := agents.NewEmbeddingModel(provider, "custom/my-embedder")
+	require.NoError(t, err)
+	assert.Equal(t, "my-embedder", custom.requested)
+
+	vectors, err := model.Embed(t.Context(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}, {1}}, vectors)
+
+	// Embedding models back the vector memory.
+	vm := memory.VectorMemory{Embedder: model, Store: memory.NewInMemoryVectorStore()}
+	require.NoError(t, vm.Remember(t.Context(), agents.ItemHelpers().InputToNewInputList(agents.InputString("hello"))))
+	recalled, err := vm.Recall(t.Context(), agents.ItemHelpers().InputToNewInputList(agents.InputString("hi")))
+	require.NoError(t, err)
+	assert.NotEmpty(t, recalled)
+
+	_, err = agents.NewEmbeddingModel(provider, "chat-only/model")
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+
+	_, err = agents.NewEmbeddingModel(NewDummyProvider(nil), "model")
+	assert.ErrorAs(t, err, &userErr)
+}
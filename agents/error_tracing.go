@@ -16,6 +16,6 @@ func AttachErrorToCurrentSpan(ctx context.Context, err tracing.SpanError) {
 	if span != nil {
 		AttachErrorToSpan(span, err)
 	} else {
-		Logger().Warn("No span to add error to", slog.String("error", err.Error()))
+		Logger().WarnContext(ctx, "No span to add error to", slog.String("error", err.Error()))
 	}
 }
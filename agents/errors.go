@@ -18,6 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openai/openai-go/v3"
 )
 
 // RunErrorDetails provides data collected from an agent run when an error occurs.
@@ -182,3 +187,171 @@ func NewOutputGuardrailTripwireTriggeredError(guardrailResult OutputGuardrailRes
 		GuardrailResult: guardrailResult,
 	}
 }
+
+// HandoffLoopError is returned when the agents hand off to each other in a
+// loop, see RunConfig.HandoffLoopPolicy.
+type HandoffLoopError struct {
+	*AgentsError
+	// The agents of the repeated handoff.
+	FromAgent string
+	ToAgent   string
+	// The number of handoffs from FromAgent to ToAgent in the run, including
+	// the rejected one.
+	Repeats int
+}
+
+func (err HandoffLoopError) Error() string {
+	if err.AgentsError == nil {
+		return "HandoffLoopError"
+	}
+	return err.AgentsError.Error()
+}
+
+func (err HandoffLoopError) Unwrap() error {
+	return err.AgentsError
+}
+
+func NewHandoffLoopError(fromAgent, toAgent string, repeats int) HandoffLoopError {
+	return HandoffLoopError{
+		AgentsError: AgentsErrorf("handoff loop detected: %d handoffs from %s to %s", repeats, fromAgent, toAgent),
+		FromAgent:   fromAgent,
+		ToAgent:     toAgent,
+		Repeats:     repeats,
+	}
+}
+
+// AgentUsageLimitExceededError is returned when an agent exceeds its
+// AgentUsageLimits in a run.
+type AgentUsageLimitExceededError struct {
+	*AgentsError
+	// The name of the agent.
+	Agent string
+	// The limit exceeded: "max_turns", "max_tool_calls" or "max_tokens".
+	Limit string
+	// The value of the limit, and the usage of the agent exceeding it.
+	Max  uint64
+	Used uint64
+}
+
+func (err AgentUsageLimitExceededError) Error() string {
+	if err.AgentsError == nil {
+		return "AgentUsageLimitExceededError"
+	}
+	return err.AgentsError.Error()
+}
+
+func (err AgentUsageLimitExceededError) Unwrap() error {
+	return err.AgentsError
+}
+
+func NewAgentUsageLimitExceededError(agent, limit string, max, used uint64) AgentUsageLimitExceededError {
+	return AgentUsageLimitExceededError{
+		AgentsError: AgentsErrorf("agent %s exceeded its %s limit of %d", agent, limit, max),
+		Agent:       agent,
+		Limit:       limit,
+		Max:         max,
+		Used:        used,
+	}
+}
+
+// ModelRateLimitError is returned when the model provider rejects a request
+// because of a rate limit (HTTP 429) or an exhausted quota. It wraps the
+// *openai.Error of the provider, and carries the rate limit headers of the
+// response so that callers can back off.
+type ModelRateLimitError struct {
+	*AgentsError
+	// Whether the quota of the account is exhausted ("insufficient_quota"),
+	// in which case retrying doesn't help, unlike for the rate limits.
+	QuotaExceeded bool
+	// The time to wait before retrying, from the Retry-After headers, or
+	// zero if unknown.
+	RetryAfter time.Duration
+	// The requests and tokens remaining in the current rate limit windows,
+	// from the X-Ratelimit-Remaining-* headers, or -1 if unknown.
+	RemainingRequests int64
+	RemainingTokens   int64
+	// The time until the request and token rate limit windows reset, from
+	// the X-Ratelimit-Reset-* headers, or zero if unknown.
+	ResetRequests time.Duration
+	ResetTokens   time.Duration
+}
+
+func (err ModelRateLimitError) Error() string {
+	if err.AgentsError == nil {
+		return "ModelRateLimitError"
+	}
+	return err.AgentsError.Error()
+}
+
+func (err ModelRateLimitError) Unwrap() error {
+	return err.AgentsError
+}
+
+// NewModelRateLimitError creates a ModelRateLimitError wrapping the given
+// API error, reading the rate limit headers of its response.
+func NewModelRateLimitError(apiErr *openai.Error) ModelRateLimitError {
+	err := ModelRateLimitError{
+		AgentsError:       &AgentsError{Err: apiErr},
+		QuotaExceeded:     apiErr.Code == "insufficient_quota",
+		RemainingRequests: -1,
+		RemainingTokens:   -1,
+	}
+	if apiErr.Response == nil {
+		return err
+	}
+	header := apiErr.Response.Header
+	err.RetryAfter = parseRetryAfter(header)
+	err.RemainingRequests = parseRateLimitInt(header.Get("X-Ratelimit-Remaining-Requests"))
+	err.RemainingTokens = parseRateLimitInt(header.Get("X-Ratelimit-Remaining-Tokens"))
+	err.ResetRequests = parseRateLimitDuration(header.Get("X-Ratelimit-Reset-Requests"))
+	err.ResetTokens = parseRateLimitDuration(header.Get("X-Ratelimit-Reset-Tokens"))
+	return err
+}
+
+// asModelRateLimitError returns err as a ModelRateLimitError when it is a
+// rate limit or quota error of the provider, or err unchanged.
+func asModelRateLimitError(err error) error {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.Code != "insufficient_quota" {
+		return err
+	}
+	rateLimitErr := NewModelRateLimitError(apiErr)
+	rateLimitErr.AgentsError.Err = err
+	return rateLimitErr
+}
+
+// parseRetryAfter reads the Retry-After-Ms header sent by OpenAI, or the
+// standard Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("Retry-After")
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+func parseRateLimitInt(value string) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// parseRateLimitDuration reads durations like "1s", "6m0s" or "20ms".
+func parseRateLimitDuration(value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/modelsettings"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyCapturingClient(t *testing.T, reqBody *map[string]any) agents.OpenaiClient {
+	t.Helper()
+	return agents.OpenaiClient{
+		Client: openai.NewClient(
+			option.WithMiddleware(func(req *http.Request, _ option.MiddlewareNext) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(b, reqBody))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader(nil)),
+				}, nil
+			}),
+		),
+	}
+}
+
+func TestExtraBodyPassedToOpenaiResponsesModel(t *testing.T) {
+	// Ensure ExtraBody in ModelSettings is merged into the Responses API request body.
+
+	var reqBody map[string]any
+	model := agents.NewOpenAIResponsesModel("gpt-4", bodyCapturingClient(t, &reqBody))
+	_, _ = model.GetResponse(t.Context(), agents.ModelResponseParams{
+		Input: agents.InputString("hi"),
+		ModelSettings: modelsettings.ModelSettings{
+			ExtraBody: map[string]any{
+				"provider": map[string]any{"order": []string{"azure"}},
+			},
+		},
+		Tracing: agents.ModelTracingDisabled,
+	})
+
+	assert.Equal(t, "gpt-4", reqBody["model"])
+	assert.Equal(t, map[string]any{"order": []any{"azure"}}, reqBody["provider"])
+}
+
+func TestExtraBodyPassedToOpenaiChatCompletionsClient(t *testing.T) {
+	// Ensure ExtraBody in ModelSettings is merged into the chat completions request body,
+	// overriding the fields set from the other settings.
+
+	var reqBody map[string]any
+	model := agents.NewOpenAIChatCompletionsModel("gpt-4", bodyCapturingClient(t, &reqBody))
+	_, _ = model.GetResponse(t.Context(), agents.ModelResponseParams{
+		Input: agents.InputString("hi"),
+		ModelSettings: modelsettings.ModelSettings{
+			Temperature: param.NewOpt(0.5),
+			ExtraBody: map[string]any{
+				"safe_prompt": true,
+				"temperature": 0.1,
+			},
+		},
+		Tracing: agents.ModelTracingDisabled,
+	})
+
+	assert.Equal(t, "gpt-4", reqBody["model"])
+	assert.Equal(t, true, reqBody["safe_prompt"])
+	assert.Equal(t, 0.1, reqBody["temperature"])
+}
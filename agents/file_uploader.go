@@ -0,0 +1,229 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// FileCache remembers the IDs of the files and vector stores created by a
+// FileUploader, by key, so that the same content is not uploaded again.
+// Implementations backed by a database share the uploads across processes.
+type FileCache interface {
+	GetFileID(ctx context.Context, key string) (id string, ok bool, err error)
+	SetFileID(ctx context.Context, key, id string) error
+}
+
+// InMemoryFileCache is a FileCache for the lifetime of the process.
+type InMemoryFileCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewInMemoryFileCache returns an empty InMemoryFileCache.
+func NewInMemoryFileCache() *InMemoryFileCache {
+	return &InMemoryFileCache{ids: make(map[string]string)}
+}
+
+func (c *InMemoryFileCache) GetFileID(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[key]
+	return id, ok, nil
+}
+
+func (c *InMemoryFileCache) SetFileID(_ context.Context, key, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[key] = id
+	return nil
+}
+
+// FileUploader uploads documents to the Files API, too large to be sent
+// inline with each request, and references them by file ID in the input
+// items and in file search tools. Uploads are cached by content, so that a
+// FileUploader shared by the runs uploads each document once.
+//
+// Files deleted from the Files API are not evicted from the cache: use a new
+// cache, or delete the entries of a persistent one.
+type FileUploader struct {
+	// Optional client. Defaults to the default OpenAI client, see
+	// SetDefaultOpenaiClient.
+	Client *OpenaiClient
+
+	// Optional cache of the uploads. Defaults to an InMemoryFileCache created
+	// with the first upload.
+	Cache FileCache
+
+	mu sync.Mutex
+}
+
+// NewFileUploader returns a FileUploader with the given client, which can be
+// nil to use the default one.
+func NewFileUploader(client *OpenaiClient) *FileUploader {
+	return &FileUploader{Client: client, Cache: NewInMemoryFileCache()}
+}
+
+// Upload uploads the document with the given purpose, unless uploaded
+// before, and returns its file ID.
+func (u *FileUploader) Upload(ctx context.Context, r io.Reader, filename string, purpose openai.FilePurpose) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file to upload: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	key := "file:" + string(purpose) + ":" + hex.EncodeToString(sum[:])
+	return u.cached(ctx, key, func() (string, error) {
+		return uploadFile(ctx, u.client(), data, filename, detectMIMEType(data, filename), purpose)
+	})
+}
+
+// UploadFile uploads the file at path with the given purpose, unless
+// uploaded before, and returns its file ID.
+func (u *FileUploader) UploadFile(ctx context.Context, path string, purpose openai.FilePurpose) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file to upload: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return u.Upload(ctx, f, filepath.Base(path), purpose)
+}
+
+// InputFile uploads the file at path, unless uploaded before, and returns
+// an input content part referencing it by file ID.
+func (u *FileUploader) InputFile(ctx context.Context, path string) (responses.ResponseInputContentUnionParam, error) {
+	fileID, err := u.UploadFile(ctx, path, openai.FilePurposeUserData)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, err
+	}
+	return responses.ResponseInputContentUnionParam{
+		OfInputFile: &responses.ResponseInputFileParam{
+			FileID:   param.NewOpt(fileID),
+			Filename: param.NewOpt(filepath.Base(path)),
+		},
+	}, nil
+}
+
+// FileSearchTool uploads the files at paths, unless uploaded before, and
+// returns a FileSearchTool searching a vector store of the files. The vector
+// store is created once for each set of files, waiting for the files to be
+// processed.
+func (u *FileUploader) FileSearchTool(ctx context.Context, paths ...string) (FileSearchTool, error) {
+	if len(paths) == 0 {
+		return FileSearchTool{}, UserErrorf("at least one file is required")
+	}
+	fileIDs := make([]string, len(paths))
+	for i, path := range paths {
+		fileID, err := u.UploadFile(ctx, path, openai.FilePurposeAssistants)
+		if err != nil {
+			return FileSearchTool{}, err
+		}
+		fileIDs[i] = fileID
+	}
+	slices.Sort(fileIDs)
+	fileIDs = slices.Compact(fileIDs)
+
+	sum := sha256.Sum256([]byte(strings.Join(fileIDs, "\n")))
+	key := "vector_store:" + hex.EncodeToString(sum[:])
+	vectorStoreID, err := u.cached(ctx, key, func() (string, error) {
+		return createVectorStore(ctx, u.client(), fileIDs)
+	})
+	if err != nil {
+		return FileSearchTool{}, err
+	}
+	return FileSearchTool{VectorStoreIDs: []string{vectorStoreID}}, nil
+}
+
+// cached returns the ID of the key in the cache, or creates it. Creations
+// are serialized, so that concurrent uploads of a document do not duplicate
+// it.
+func (u *FileUploader) cached(ctx context.Context, key string, create func() (string, error)) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.Cache == nil {
+		u.Cache = NewInMemoryFileCache()
+	}
+	id, ok, err := u.Cache.GetFileID(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("file cache error: %w", err)
+	}
+	if ok {
+		return id, nil
+	}
+	id, err = create()
+	if err != nil {
+		return "", err
+	}
+	if err = u.Cache.SetFileID(ctx, key, id); err != nil {
+		return "", fmt.Errorf("file cache error: %w", err)
+	}
+	return id, nil
+}
+
+func (u *FileUploader) client() *OpenaiClient {
+	if u.Client != nil {
+		return u.Client
+	}
+	return resolveDefaultOpenaiClient()
+}
+
+func uploadFile(
+	ctx context.Context,
+	client *OpenaiClient,
+	data []byte,
+	filename, mimeType string,
+	purpose openai.FilePurpose,
+) (string, error) {
+	file, err := client.Files.New(ctx, openai.FileNewParams{
+		File:    openai.File(bytes.NewReader(data), filename, mimeType),
+		Purpose: purpose,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return file.ID, nil
+}
+
+func createVectorStore(ctx context.Context, client *OpenaiClient, fileIDs []string) (string, error) {
+	vectorStore, err := client.VectorStores.New(ctx, openai.VectorStoreNewParams{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create vector store: %w", err)
+	}
+	batch, err := client.VectorStores.FileBatches.NewAndPoll(ctx, vectorStore.ID, openai.VectorStoreFileBatchNewParams{
+		FileIDs: fileIDs,
+	}, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to add files to vector store: %w", err)
+	}
+	if batch.Status != openai.VectorStoreFileBatchStatusCompleted || batch.FileCounts.Failed > 0 {
+		return "", fmt.Errorf("failed to add files to vector store %s: batch %s, %d failed files",
+			vectorStore.ID, batch.Status, batch.FileCounts.Failed)
+	}
+	return vectorStore.ID, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFilesAPI serves the endpoints of the Files and Vector Stores APIs used
+// by the FileUploader.
+type fakeFilesAPI struct {
+	mu              sync.Mutex
+	uploads         []string // purpose/filename
+	vectorStores    int
+	batchedFileIDs  [][]string
+	batchFailedFile bool
+}
+
+func (f *fakeFilesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		_, header, _ := r.FormFile("file")
+		f.uploads = append(f.uploads, r.FormValue("purpose")+"/"+header.Filename)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": fmt.Sprintf("file_%d", len(f.uploads))})
+	case r.Method == http.MethodPost && r.URL.Path == "/vector_stores":
+		f.vectorStores++
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": fmt.Sprintf("vs_%d", f.vectorStores)})
+	case r.Method == http.MethodPost:
+		var body struct {
+			FileIDs []string `json:"file_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.batchedFileIDs = append(f.batchedFileIDs, body.FileIDs)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "batch_1", "status": "in_progress"})
+	default:
+		failed := 0
+		if f.batchFailedFile {
+			failed = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":          "batch_1",
+			"status":      "completed",
+			"file_counts": map[string]any{"failed": failed},
+		})
+	}
+}
+
+func (f *fakeFilesAPI) setBatchFailedFile(failed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batchFailedFile = failed
+}
+
+func newTestFileUploader(t *testing.T) (*agents.FileUploader, *fakeFilesAPI) {
+	api := &fakeFilesAPI{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client := agents.NewOpenaiClient(param.NewOpt(server.URL), param.NewOpt("test_key"))
+	return agents.NewFileUploader(&client), api
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileUploaderInputFileIsCached(t *testing.T) {
+	uploader, api := newTestFileUploader(t)
+	path := writeTestFile(t, "report.pdf", "%PDF-1.7 report")
+
+	for range 2 {
+		content, err := uploader.InputFile(t.Context(), path)
+		require.NoError(t, err)
+		assert.Equal(t, responses.ResponseInputContentUnionParam{
+			OfInputFile: &responses.ResponseInputFileParam{
+				FileID:   param.NewOpt("file_1"),
+				Filename: param.NewOpt("report.pdf"),
+			},
+		}, content)
+	}
+	assert.Equal(t, []string{"user_data/report.pdf"}, api.uploads)
+
+	// The same content with another purpose is uploaded again.
+	fileID, err := uploader.UploadFile(t.Context(), path, openai.FilePurposeAssistants)
+	require.NoError(t, err)
+	assert.Equal(t, "file_2", fileID)
+}
+
+func TestFileUploaderFileSearchTool(t *testing.T) {
+	uploader, api := newTestFileUploader(t)
+	a := writeTestFile(t, "a.txt", "alpha")
+	b := writeTestFile(t, "b.txt", "beta")
+
+	tool, err := uploader.FileSearchTool(t.Context(), a, b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vs_1"}, tool.VectorStoreIDs)
+
+	tool, err = uploader.FileSearchTool(t.Context(), b, a)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vs_1"}, tool.VectorStoreIDs)
+
+	assert.Equal(t, []string{"assistants/a.txt", "assistants/b.txt"}, api.uploads)
+	assert.Equal(t, 1, api.vectorStores)
+	assert.Equal(t, [][]string{{"file_1", "file_2"}}, api.batchedFileIDs)
+}
+
+func TestFileUploaderFileSearchToolFailedFiles(t *testing.T) {
+	uploader, api := newTestFileUploader(t)
+	api.setBatchFailedFile(true)
+
+	_, err := uploader.FileSearchTool(t.Context(), writeTestFile(t, "a.txt", "alpha"))
+	assert.ErrorContains(t, err, "1 failed files")
+
+	// The failed vector store is not cached.
+	api.setBatchFailedFile(false)
+	tool, err := uploader.FileSearchTool(t.Context(), writeTestFile(t, "a.txt", "alpha"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vs_2"}, tool.VectorStoreIDs)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"time"
+)
+
+// HandoffDetails describes a handoff, e.g. so that observability tools can
+// explain why it happened. It is available to the OnHandoff hooks through
+// HandoffDetailsFromContext, and in HandoffOutputItem.Details.
+type HandoffDetails struct {
+	// The tool call through which the model requested the handoff, whose
+	// arguments hold the input of the handoff, if any.
+	ToolCall ResponseFunctionToolCall
+
+	// When the handoff started being executed.
+	StartedAt time.Time
+
+	// The time taken by the handoff, including its OnHandoff callback and the
+	// input filter, but not the OnHandoff hooks.
+	Duration time.Duration
+
+	// The input the target agent runs with, after the input filter.
+	Input []TResponseInputItem
+}
+
+type handoffDetailsKey struct{}
+
+func contextWithHandoffDetails(ctx context.Context, details *HandoffDetails) context.Context {
+	return context.WithValue(ctx, handoffDetailsKey{}, details)
+}
+
+// HandoffDetailsFromContext returns the details of the handoff for which the
+// OnHandoff hooks are called, or nil.
+func HandoffDetailsFromContext(ctx context.Context) *HandoffDetails {
+	v, _ := ctx.Value(handoffDetailsKey{}).(*HandoffDetails)
+	return v
+}
+
+// handoffInputList returns the input of the model for the target agent.
+func handoffInputList(originalInput Input, preStepItems, newStepItems []RunItem) []TResponseInputItem {
+	input := ItemHelpers().InputToNewInputList(originalInput)
+	for _, item := range preStepItems {
+		input = append(input, item.ToInputItem())
+	}
+	for _, item := range newStepItems {
+		input = append(input, item.ToInputItem())
+	}
+	return input
+}
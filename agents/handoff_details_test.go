@@ -0,0 +1,100 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handoffDetailsHooks struct {
+	agents.NoOpRunHooks
+	details *agents.HandoffDetails
+}
+
+func (h *handoffDetailsHooks) OnHandoff(ctx context.Context, _, _ *agents.Agent) error {
+	h.details = agents.HandoffDetailsFromContext(ctx)
+	return nil
+}
+
+// removeMessages removes the messages of the agent handing off.
+func removeMessages(_ context.Context, data agents.HandoffInputData) (agents.HandoffInputData, error) {
+	var newItems []agents.RunItem
+	for _, item := range data.NewItems {
+		if _, ok := item.(agents.MessageOutputItem); !ok {
+			newItems = append(newItems, item)
+		}
+	}
+	data.NewItems = newItems
+	return data, nil
+}
+
+func TestHandoffDetails(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		target := agents.New("target").WithModelInstance(model)
+		source := agents.New("source").WithModelInstance(model).WithHandoffs(
+			agents.HandoffFromAgent(agents.HandoffFromAgentParams{
+				Agent:       target,
+				InputFilter: removeMessages,
+			}))
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{
+				agentstesting.GetTextMessage("transferring"),
+				agentstesting.GetHandoffToolCall(target, "", `{"reason":"billing"}`),
+			}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		hooks := &handoffDetailsHooks{}
+		runner := agents.Runner{Config: agents.RunConfig{Hooks: hooks}}
+		var newItems []agents.RunItem
+		if streaming {
+			result, err := runner.RunStreamed(t.Context(), source, "hello")
+			require.NoError(t, err)
+			require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+			newItems = result.NewItems()
+		} else {
+			result, err := runner.Run(t.Context(), source, "hello")
+			require.NoError(t, err)
+			newItems = result.NewItems
+		}
+
+		details := hooks.details
+		require.NotNil(t, details)
+		assert.Equal(t, `{"reason":"billing"}`, details.ToolCall.Arguments)
+		assert.Equal(t, agents.DefaultHandoffToolName(target), details.ToolCall.Name)
+		assert.False(t, details.StartedAt.IsZero())
+		// The input filter removed the message of the source agent.
+		require.Len(t, details.Input, 3)
+		assert.Equal(t, "hello", details.Input[0].OfMessage.Content.OfString.Value)
+		assert.NotNil(t, details.Input[1].OfFunctionCall)
+		assert.NotNil(t, details.Input[2].OfFunctionCallOutput)
+
+		var handoffItem *agents.HandoffOutputItem
+		for _, item := range newItems {
+			if v, ok := item.(agents.HandoffOutputItem); ok {
+				handoffItem = &v
+			}
+		}
+		require.NotNil(t, handoffItem)
+		assert.Same(t, details, handoffItem.Details)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// HandoffWithInputParams are the parameters of a handoff whose tool takes a
+// typed input, see SafeHandoffWithInput.
+type HandoffWithInputParams[T any] struct {
+	// The agent to hand off to.
+	Agent *Agent
+
+	// Optional override for the name of the tool that represents the handoff.
+	ToolNameOverride string
+
+	// Optional override for the description of the tool that represents the handoff.
+	ToolDescriptionOverride string
+
+	// Optional function that runs when the handoff is invoked, with the input
+	// filled by the model.
+	OnHandoff func(ctx context.Context, input T) error
+
+	// Optional function that filters the inputs that are passed to the next agent.
+	InputFilter HandoffInputFilter
+
+	// Optional flag reporting whether the handoff is enabled.
+	// Default value, if omitted: true.
+	IsEnabled HandoffEnabler
+}
+
+// HandoffWithInput creates a Handoff from an Agent taking a typed input. It
+// panics in case of problems. For a safer variant, see SafeHandoffWithInput.
+func HandoffWithInput[T any](params HandoffWithInputParams[T]) Handoff {
+	h, err := SafeHandoffWithInput(params)
+	if err != nil {
+		panic(err)
+	}
+	return *h
+}
+
+// SafeHandoffWithInput creates a Handoff from an Agent whose tool takes an
+// input of type T, a struct, which the model fills when invoking the
+// handoff, e.g. with the reason of the handoff or the fields extracted from
+// the conversation:
+//
+//	type Escalation struct {
+//		Reason   string `json:"reason"`
+//		Priority string `json:"priority" jsonschema:"enum=low,enum=high"`
+//	}
+//
+//	handoff := agents.HandoffWithInput(agents.HandoffWithInputParams[Escalation]{Agent: supportAgent})
+//
+// The JSON schema of the input is derived from T as for OutputTypeOf. The
+// input is passed to OnHandoff, and is available to the target agent, e.g.
+// to its dynamic instructions and tools, with HandoffInput.
+func SafeHandoffWithInput[T any](params HandoffWithInputParams[T]) (*Handoff, error) {
+	if !isStruct[T]() {
+		var zero T
+		return nil, UserErrorf("handoff input type must be a struct, got %T", zero)
+	}
+	inputType, err := SafeOutputTypeOf[T](OutputTypeOpts{StrictJSONSchema: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create handoff input type: %w", err)
+	}
+	schema, err := inputType.JSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	return SafeHandoffFromAgent(HandoffFromAgentParams{
+		Agent:                   params.Agent,
+		ToolNameOverride:        params.ToolNameOverride,
+		ToolDescriptionOverride: params.ToolDescriptionOverride,
+		OnHandoff: OnHandoffWithInput(func(ctx context.Context, jsonInput any) error {
+			v, err := inputType.ValidateJSON(ctx, jsonInput.(string))
+			if err != nil {
+				return err
+			}
+			input := v.(T)
+			setHandoffInput(ctx, input)
+			if params.OnHandoff == nil {
+				return nil
+			}
+			return params.OnHandoff(ctx, input)
+		}),
+		InputJSONSchema: schema,
+		InputFilter:     params.InputFilter,
+		IsEnabled:       params.IsEnabled,
+	})
+}
+
+// HandoffInput returns the input of the last handoff of the run, if the
+// handoff took an input of type T, see SafeHandoffWithInput. The input is
+// kept until the next handoff, so it is available to the agent handed off
+// to for the rest of its turns.
+func HandoffInput[T any](ctx context.Context) (T, bool) {
+	h, _ := ctx.Value(handoffStateKey{}).(*handoffState)
+	if h == nil {
+		var zero T
+		return zero, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, ok := h.input.(T)
+	return v, ok
+}
+
+type handoffStateKey struct{}
+
+// handoffState tracks the handoffs of a run: the input of the last one, and
+// the number of handoffs between each pair of agents.
+type handoffState struct {
+	mu     sync.Mutex
+	input  any
+	counts map[[2]string]int
+}
+
+func contextWithHandoffState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, handoffStateKey{}, &handoffState{counts: make(map[[2]string]int)})
+}
+
+// setHandoffInput sets the input of the last handoff of the run, if any.
+func setHandoffInput(ctx context.Context, value any) {
+	h, _ := ctx.Value(handoffStateKey{}).(*handoffState)
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.input = value
+}
+
+// countHandoff counts a handoff of the run between the agents, returning the
+// number of handoffs between them so far, or 0 outside of runs.
+func countHandoff(ctx context.Context, fromAgent, toAgent string) int {
+	h, _ := ctx.Value(handoffStateKey{}).(*handoffState)
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := [2]string{fromAgent, toAgent}
+	h.counts[key]++
+	return h.counts[key]
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type escalation struct {
+	Reason   string `json:"reason"`
+	Priority string `json:"priority" jsonschema:"enum=low,enum=high"`
+}
+
+func TestHandoffWithInput(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		support := agents.New("support").
+			WithModelInstance(model).
+			WithInstructionsFunc(func(ctx context.Context, _ *agents.Agent) (string, error) {
+				input, ok := agents.HandoffInput[escalation](ctx)
+				if !ok {
+					return "Help the user.", nil
+				}
+				return "Help the user, priority " + input.Priority + ".", nil
+			})
+
+		var received escalation
+		handoff := agents.HandoffWithInput(agents.HandoffWithInputParams[escalation]{
+			Agent: support,
+			OnHandoff: func(_ context.Context, input escalation) error {
+				received = input
+				return nil
+			},
+		})
+		schema := handoff.InputJSONSchema
+		assert.ElementsMatch(t, []any{"reason", "priority"}, schema["required"])
+
+		triage := agents.New("triage").WithModelInstance(model).WithHandoffs(handoff)
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{
+				agentstesting.GetHandoffToolCall(support, "", `{"reason": "refund", "priority": "high"}`),
+			}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		var lastAgent *agents.Agent
+		if streaming {
+			result, err := agents.Runner{}.RunStreamed(t.Context(), triage, "I want a refund")
+			require.NoError(t, err)
+			require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+			lastAgent = result.LastAgent()
+		} else {
+			result, err := agents.Runner{}.Run(t.Context(), triage, "I want a refund")
+			require.NoError(t, err)
+			lastAgent = result.LastAgent
+		}
+		assert.Same(t, support, lastAgent)
+		assert.Equal(t, escalation{Reason: "refund", Priority: "high"}, received)
+		assert.Equal(t, "Help the user, priority high.", model.LastTurnArgs.SystemInstructions.Value)
+	}
+}
+
+func TestHandoffWithInputValidation(t *testing.T) {
+	agent := agents.New("support")
+
+	_, err := agents.SafeHandoffWithInput(agents.HandoffWithInputParams[string]{Agent: agent})
+	assert.Error(t, err)
+
+	handoff, err := agents.SafeHandoffWithInput(agents.HandoffWithInputParams[escalation]{Agent: agent})
+	require.NoError(t, err)
+	_, err = handoff.OnInvokeHandoff(t.Context(), `{"reason": "refund"}`)
+	assert.Error(t, err)
+
+	// Outside of runs, the input is not kept.
+	_, err = handoff.OnInvokeHandoff(t.Context(), `{"reason": "refund", "priority": "low"}`)
+	require.NoError(t, err)
+	_, ok := agents.HandoffInput[escalation](t.Context())
+	assert.False(t, ok)
+}
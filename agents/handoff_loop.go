@@ -0,0 +1,52 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"log/slog"
+)
+
+// HandoffLoopPolicy detects the agents handing off to each other in a loop,
+// e.g. A→B→A→B, which would otherwise burn the turns of the run until
+// MaxTurns. See RunConfig.HandoffLoopPolicy.
+type HandoffLoopPolicy struct {
+	// MaxRepeats is the number of handoffs from an agent to another allowed
+	// in a run: one more is a loop. Zero disables the detection.
+	MaxRepeats int
+
+	// Optional message rejecting the handoff of a loop, sent to the model as
+	// the output of the handoff tool call, so that the current agent handles
+	// the request instead. When empty, the run fails with a HandoffLoopError.
+	InterventionMessage string
+}
+
+// checkHandoffLoop counts the handoff and reports whether it is a loop,
+// returning a HandoffLoopError unless the policy intervenes.
+func (p HandoffLoopPolicy) checkHandoffLoop(ctx context.Context, fromAgent, toAgent string) (bool, error) {
+	if p.MaxRepeats <= 0 {
+		return false, nil
+	}
+	repeats := countHandoff(ctx, fromAgent, toAgent)
+	if repeats <= p.MaxRepeats {
+		return false, nil
+	}
+	Logger().WarnContext(ctx, "Handoff loop detected",
+		slog.String("from_agent", fromAgent), slog.String("to_agent", toAgent), slog.Int("repeats", repeats))
+	if p.InterventionMessage == "" {
+		return true, NewHandoffLoopError(fromAgent, toAgent, repeats)
+	}
+	return true, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPingPongAgents() (*agents.Agent, *agents.Agent, *agentstesting.FakeModel) {
+	model := agentstesting.NewFakeModel(false, nil)
+	a := agents.New("a").WithModelInstance(model)
+	b := agents.New("b").WithModelInstance(model)
+	a.WithAgentHandoffs(b)
+	b.WithAgentHandoffs(a)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetHandoffToolCall(b, "", "")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetHandoffToolCall(a, "", "")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetHandoffToolCall(b, "", "")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+	return a, b, model
+}
+
+func TestHandoffLoopError(t *testing.T) {
+	a, _, _ := newPingPongAgents()
+	runner := agents.Runner{Config: agents.RunConfig{
+		HandoffLoopPolicy: agents.HandoffLoopPolicy{MaxRepeats: 1},
+	}}
+
+	_, err := runner.Run(t.Context(), a, "hello")
+	var loopErr agents.HandoffLoopError
+	require.ErrorAs(t, err, &loopErr)
+	assert.Equal(t, "a", loopErr.FromAgent)
+	assert.Equal(t, "b", loopErr.ToAgent)
+	assert.Equal(t, 2, loopErr.Repeats)
+}
+
+func TestHandoffLoopIntervention(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		a, _, model := newPingPongAgents()
+		runner := agents.Runner{Config: agents.RunConfig{
+			HandoffLoopPolicy: agents.HandoffLoopPolicy{
+				MaxRepeats:          1,
+				InterventionMessage: "Stop handing off, answer yourself.",
+			},
+		}}
+
+		var lastAgent *agents.Agent
+		var newItems []agents.RunItem
+		if streaming {
+			result, err := runner.RunStreamed(t.Context(), a, "hello")
+			require.NoError(t, err)
+			require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+			lastAgent, newItems = result.LastAgent(), result.NewItems()
+		} else {
+			result, err := runner.Run(t.Context(), a, "hello")
+			require.NoError(t, err)
+			lastAgent, newItems = result.LastAgent, result.NewItems
+		}
+
+		// The looping handoff is rejected, and the agent answers instead.
+		assert.Same(t, a, lastAgent)
+		var rejection *agents.ToolCallOutputItem
+		for _, item := range newItems {
+			if item, ok := item.(agents.ToolCallOutputItem); ok {
+				rejection = &item
+			}
+		}
+		require.NotNil(t, rejection)
+		assert.Equal(t, "Stop handing off, answer yourself.", rejection.Output)
+		input := model.LastTurnArgs.Input.(agents.InputItems)
+		assert.Equal(t, "Stop handing off, answer yourself.", input[len(input)-1].OfFunctionCallOutput.Output.OfString.Value)
+	}
+}
+
+func TestHandoffLoopDisabled(t *testing.T) {
+	a, b, _ := newPingPongAgents()
+	result, err := agents.Runner{}.Run(t.Context(), a, "hello")
+	require.NoError(t, err)
+	assert.Same(t, b, result.LastAgent)
+	assert.Equal(t, "done", result.FinalOutput)
+}
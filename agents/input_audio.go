@@ -0,0 +1,314 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// Formats of the audio input content.
+const (
+	AudioFormatWAV = "wav"
+	AudioFormatMP3 = "mp3"
+)
+
+// DefaultAudioTranscriptionModel is the model of the OpenAIAudioTranscriber
+// when not set.
+const DefaultAudioTranscriptionModel = "gpt-4o-mini-transcribe"
+
+// InputText returns an input content part with the given text.
+func InputText(text string) responses.ResponseInputContentUnionParam {
+	return responses.ResponseInputContentUnionParam{
+		OfInputText: &responses.ResponseInputTextParam{Text: text},
+	}
+}
+
+// InputAudio returns an input content part carrying the audio, in
+// AudioFormatWAV or AudioFormatMP3 format.
+//
+// The audio is carried as an input file with an audio data URL, so that it
+// can be stored in sessions like any other input. Chat Completions models
+// supporting audio receive it as input audio, while the other models receive
+// its transcript instead (see RunConfig.AudioTranscriber).
+func InputAudio(data []byte, format string) responses.ResponseInputContentUnionParam {
+	return responses.ResponseInputContentUnionParam{
+		OfInputFile: &responses.ResponseInputFileParam{
+			FileData: param.NewOpt(dataURL(audioMIMEType(format), data)),
+			Filename: param.NewOpt("audio." + format),
+		},
+	}
+}
+
+// InputAudioFromFile reads an audio file and returns it as an input content
+// part, see InputAudio. The format is inferred from the ".wav" or ".mp3"
+// extension of the file.
+func InputAudioFromFile(path string) (responses.ResponseInputContentUnionParam, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if format != AudioFormatWAV && format != AudioFormatMP3 {
+		return responses.ResponseInputContentUnionParam{}, UserErrorf("unsupported audio file %q: expected a .wav or .mp3 file", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	return InputAudio(data, format), nil
+}
+
+// UserContentMessage returns a user message with the given content parts.
+func UserContentMessage(content ...responses.ResponseInputContentUnionParam) TResponseInputItem {
+	return TResponseInputItem{
+		OfMessage: &responses.EasyInputMessageParam{
+			Content: responses.EasyInputMessageContentUnionParam{OfInputItemContentList: content},
+			Role:    responses.EasyInputMessageRoleUser,
+			// The type lets the item be decoded back, e.g. from sessions.
+			Type: responses.EasyInputMessageTypeMessage,
+		},
+	}
+}
+
+// UserAudioMessage returns a user message with the given audio, see InputAudio.
+func UserAudioMessage(data []byte, format string) TResponseInputItem {
+	return UserContentMessage(InputAudio(data, format))
+}
+
+// UserAudioMessageFromFile returns a user message with the audio of the given
+// file, see InputAudioFromFile.
+func UserAudioMessageFromFile(path string) (TResponseInputItem, error) {
+	content, err := InputAudioFromFile(path)
+	if err != nil {
+		return TResponseInputItem{}, err
+	}
+	return UserContentMessage(content), nil
+}
+
+// InputAudioData returns the audio carried by an input content part built
+// with InputAudio, and its format.
+func InputAudioData(content responses.ResponseInputContentUnionParam) (data []byte, format string, ok bool) {
+	if content.OfInputFile == nil || !content.OfInputFile.FileData.Valid() {
+		return nil, "", false
+	}
+	data, mimeType, ok := parseDataURL(content.OfInputFile.FileData.Value)
+	if !ok {
+		return nil, "", false
+	}
+	format = audioFormatOf(mimeType)
+	if format == "" {
+		return nil, "", false
+	}
+	return data, format, true
+}
+
+func audioMIMEType(format string) string {
+	switch format {
+	case AudioFormatMP3:
+		return "audio/mpeg"
+	default:
+		return "audio/" + format
+	}
+}
+
+func audioFormatOf(mimeType string) string {
+	switch mimeType {
+	case "audio/wav", "audio/x-wav", "audio/wave":
+		return AudioFormatWAV
+	case "audio/mpeg", "audio/mp3":
+		return AudioFormatMP3
+	default:
+		return ""
+	}
+}
+
+// AudioInputSupport is implemented by the models which tell whether they
+// accept audio input. The audio input of the models which do not support it
+// is replaced by its transcript, while models not implementing the interface
+// receive it as is.
+type AudioInputSupport interface {
+	SupportsAudioInput() bool
+}
+
+// SupportsAudioInput reports whether the model accepts audio input: the
+// Responses API does not.
+func (OpenAIResponsesModel) SupportsAudioInput() bool { return false }
+
+// SupportsAudioInput reports whether the model accepts audio input, as the
+// audio models of OpenAI do, e.g. "gpt-4o-audio-preview".
+func (m OpenAIChatCompletionsModel) SupportsAudioInput() bool {
+	return strings.Contains(string(m.Model), "audio")
+}
+
+// AudioTranscriber transcribes the audio input for the models without audio
+// support.
+type AudioTranscriber interface {
+	TranscribeAudio(ctx context.Context, data []byte, format string) (string, error)
+}
+
+// OpenAIAudioTranscriber transcribes audio with the transcriptions API of
+// OpenAI.
+type OpenAIAudioTranscriber struct {
+	// Optional client. Defaults to the default OpenAI client, see
+	// SetDefaultOpenaiClient.
+	Client *OpenaiClient
+
+	// Optional transcription model. Defaults to DefaultAudioTranscriptionModel.
+	Model string
+}
+
+func (t OpenAIAudioTranscriber) TranscribeAudio(ctx context.Context, data []byte, format string) (string, error) {
+	client := t.Client
+	if client == nil {
+		client = resolveDefaultOpenaiClient()
+	}
+	response, err := client.Audio.Transcriptions.New(ctx, openai.AudioTranscriptionNewParams{
+		Model: cmp.Or(t.Model, DefaultAudioTranscriptionModel),
+		File:  openai.File(bytes.NewReader(data), "audio."+format, audioMIMEType(format)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("audio transcription error: %w", err)
+	}
+	return response.Text, nil
+}
+
+// TranscribeInputAudio returns the input items with their audio content, see
+// InputAudio, replaced by its transcript as text.
+func TranscribeInputAudio(ctx context.Context, transcriber AudioTranscriber, items []TResponseInputItem) ([]TResponseInputItem, error) {
+	var out []TResponseInputItem
+	for i, item := range items {
+		content, replaced, err := transcribeMessageAudio(ctx, transcriber, item)
+		if err != nil {
+			return nil, err
+		}
+		if !replaced {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(items)
+		}
+		switch {
+		case item.OfMessage != nil:
+			message := *item.OfMessage
+			message.Content = responses.EasyInputMessageContentUnionParam{OfInputItemContentList: content}
+			out[i] = TResponseInputItem{OfMessage: &message}
+		case item.OfInputMessage != nil:
+			message := *item.OfInputMessage
+			message.Content = content
+			out[i] = TResponseInputItem{OfInputMessage: &message}
+		}
+	}
+	if out == nil {
+		return items, nil
+	}
+	return out, nil
+}
+
+// transcribeMessageAudio returns the content of the message item with the
+// audio replaced, if any.
+func transcribeMessageAudio(
+	ctx context.Context,
+	transcriber AudioTranscriber,
+	item TResponseInputItem,
+) (_ responses.ResponseInputMessageContentListParam, replaced bool, _ error) {
+	var content responses.ResponseInputMessageContentListParam
+	switch {
+	case item.OfMessage != nil:
+		content = item.OfMessage.Content.OfInputItemContentList
+	case item.OfInputMessage != nil:
+		content = item.OfInputMessage.Content
+	}
+
+	var out responses.ResponseInputMessageContentListParam
+	for i, c := range content {
+		data, format, ok := InputAudioData(c)
+		if !ok {
+			continue
+		}
+		transcript, err := transcribeAudioCached(ctx, transcriber, data, format)
+		if err != nil {
+			return nil, false, err
+		}
+		if out == nil {
+			out = slices.Clone(content)
+		}
+		out[i] = InputText(transcript)
+	}
+	return out, out != nil, nil
+}
+
+type audioTranscriptsKey struct{}
+
+// audioTranscripts caches the transcripts of the audio input of a run, which
+// is sent to the model at every turn.
+type audioTranscripts struct {
+	mu          sync.Mutex
+	transcripts map[[sha256.Size]byte]string
+}
+
+func contextWithAudioTranscripts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, audioTranscriptsKey{}, &audioTranscripts{
+		transcripts: make(map[[sha256.Size]byte]string),
+	})
+}
+
+func transcribeAudioCached(ctx context.Context, transcriber AudioTranscriber, data []byte, format string) (string, error) {
+	cache, _ := ctx.Value(audioTranscriptsKey{}).(*audioTranscripts)
+	if cache == nil {
+		return transcriber.TranscribeAudio(ctx, data, format)
+	}
+	key := sha256.Sum256(data)
+	cache.mu.Lock()
+	transcript, ok := cache.transcripts[key]
+	cache.mu.Unlock()
+	if ok {
+		return transcript, nil
+	}
+	transcript, err := transcriber.TranscribeAudio(ctx, data, format)
+	if err != nil {
+		return "", err
+	}
+	cache.mu.Lock()
+	cache.transcripts[key] = transcript
+	cache.mu.Unlock()
+	return transcript, nil
+}
+
+// maybeTranscribeAudioInput replaces the audio input by its transcript for
+// the models without audio support.
+func maybeTranscribeAudioInput(ctx context.Context, model Model, runConfig RunConfig, input []TResponseInputItem) ([]TResponseInputItem, error) {
+	support, ok := model.(AudioInputSupport)
+	if !ok || support.SupportsAudioInput() {
+		return input, nil
+	}
+	transcriber := runConfig.AudioTranscriber
+	if transcriber == nil {
+		transcriber = OpenAIAudioTranscriber{}
+	}
+	transcribed, err := TranscribeInputAudio(ctx, transcriber, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcribe audio input: %w", err)
+	}
+	return transcribed, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputAudioRoundTrip(t *testing.T) {
+	item := agents.UserAudioMessage([]byte("RIFF...."), agents.AudioFormatWAV)
+
+	raw, err := json.Marshal(item)
+	require.NoError(t, err)
+	var decoded agents.TResponseInputItem
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	require.NotNil(t, decoded.OfMessage)
+	content := decoded.OfMessage.Content.OfInputItemContentList
+	require.Len(t, content, 1)
+	data, format, ok := agents.InputAudioData(content[0])
+	require.True(t, ok)
+	assert.Equal(t, []byte("RIFF...."), data)
+	assert.Equal(t, agents.AudioFormatWAV, format)
+
+	_, _, ok = agents.InputAudioData(agents.InputText("hi"))
+	assert.False(t, ok)
+}
+
+func TestInputAudioFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "question.MP3")
+	require.NoError(t, os.WriteFile(path, []byte("ID3"), 0o600))
+
+	item, err := agents.UserAudioMessageFromFile(path)
+	require.NoError(t, err)
+	data, format, ok := agents.InputAudioData(item.OfMessage.Content.OfInputItemContentList[0])
+	require.True(t, ok)
+	assert.Equal(t, []byte("ID3"), data)
+	assert.Equal(t, agents.AudioFormatMP3, format)
+
+	_, err = agents.InputAudioFromFile(filepath.Join(dir, "question.ogg"))
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+}
+
+func TestChatCmplConverterInputAudio(t *testing.T) {
+	messages, err := agents.ChatCmplConverter().ItemsToMessages(agents.InputItems{
+		agents.UserContentMessage(agents.InputText("Answer this:"), agents.InputAudio([]byte("ID3"), agents.AudioFormatMP3)),
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	parts := messages[0].OfUser.Content.OfArrayOfContentParts
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Answer this:", parts[0].OfText.Text)
+	require.NotNil(t, parts[1].OfInputAudio)
+	assert.Equal(t, openai.ChatCompletionContentPartInputAudioInputAudioParam{
+		Data:   "SUQz",
+		Format: agents.AudioFormatMP3,
+	}, parts[1].OfInputAudio.InputAudio)
+}
+
+func TestOpenAIModelsSupportAudioInput(t *testing.T) {
+	assert.False(t, agents.NewOpenAIResponsesModel("gpt-4o", agents.OpenaiClient{}).SupportsAudioInput())
+	assert.False(t, agents.NewOpenAIChatCompletionsModel("gpt-4o", agents.OpenaiClient{}).SupportsAudioInput())
+	assert.True(t, agents.NewOpenAIChatCompletionsModel("gpt-4o-audio-preview", agents.OpenaiClient{}).SupportsAudioInput())
+}
+
+type fakeAudioTranscriber struct {
+	calls int
+}
+
+func (t *fakeAudioTranscriber) TranscribeAudio(_ context.Context, data []byte, format string) (string, error) {
+	t.calls++
+	return "transcript of " + string(data) + " (" + format + ")", nil
+}
+
+// textOnlyModel is a FakeModel without audio support.
+type textOnlyModel struct {
+	*agentstesting.FakeModel
+}
+
+func (textOnlyModel) SupportsAudioInput() bool { return false }
+
+func TestRunTranscribesAudioForModelsWithoutAudioSupport(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+	agent := &agents.Agent{
+		Name:  "test",
+		Model: param.NewOpt(agents.NewAgentModel(textOnlyModel{model})),
+		Tools: []agents.Tool{agentstesting.GetFunctionTool("foo", "result")},
+	}
+	input := []agents.TResponseInputItem{
+		agents.UserContentMessage(agents.InputText("Listen:"), agents.InputAudio([]byte("hello"), agents.AudioFormatWAV)),
+	}
+	transcriber := &fakeAudioTranscriber{}
+
+	result, err := agents.Runner{Config: agents.RunConfig{AudioTranscriber: transcriber}}.
+		RunInputs(t.Context(), agent, input)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.FinalOutput)
+
+	// The transcript is reused at every turn.
+	assert.Equal(t, 1, transcriber.calls)
+	sent := model.LastTurnArgs.Input.(agents.InputItems)
+	assert.Equal(t,
+		agents.UserContentMessage(agents.InputText("Listen:"), agents.InputText("transcript of hello (wav)")),
+		sent[0],
+	)
+	// The input of the run keeps the audio.
+	_, _, ok := agents.InputAudioData(agents.ItemHelpers().InputToNewInputList(result.Input)[0].OfMessage.Content.OfInputItemContentList[1])
+	assert.True(t, ok)
+}
+
+func TestRunKeepsAudioForModelsWithAudioSupport(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")},
+	})
+	agent := &agents.Agent{
+		Name:  "test",
+		Model: param.NewOpt(agents.NewAgentModel(model)),
+	}
+	input := []agents.TResponseInputItem{agents.UserAudioMessage([]byte("hello"), agents.AudioFormatWAV)}
+	transcriber := &fakeAudioTranscriber{}
+
+	_, err := agents.Runner{Config: agents.RunConfig{AudioTranscriber: transcriber}}.
+		RunInputs(t.Context(), agent, input)
+	require.NoError(t, err)
+	assert.Zero(t, transcriber.calls)
+	assert.Equal(t, input, []agents.TResponseInputItem(model.LastTurnArgs.Input.(agents.InputItems)))
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// Size limits of the content read by the input helpers, matching the ones
+// of the OpenAI API.
+const (
+	MaxInputImageSize = 20 << 20
+	MaxInputFileSize  = 32 << 20
+)
+
+// The MIME types of the images accepted as input.
+var inputImageMIMETypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// InputImage returns an input content part with the image, encoded as a
+// base64 data URL. The MIME type is detected from the data when empty.
+func InputImage(data []byte, mimeType string) responses.ResponseInputContentUnionParam {
+	if mimeType == "" {
+		mimeType = detectMIMEType(data, "")
+	}
+	return responses.ResponseInputContentUnionParam{
+		OfInputImage: &responses.ResponseInputImageParam{
+			ImageURL: param.NewOpt(dataURL(mimeType, data)),
+			Detail:   responses.ResponseInputImageDetailAuto,
+		},
+	}
+}
+
+// InputImageFromFile reads a PNG, JPEG, GIF or WebP image file, of at most
+// MaxInputImageSize bytes, and returns it as an input content part.
+func InputImageFromFile(path string) (responses.ResponseInputContentUnionParam, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return InputImageFromReader(f, filepath.Base(path))
+}
+
+// InputImageFromReader reads an image, of at most MaxInputImageSize bytes,
+// and returns it as an input content part. The filename is optional, and
+// helps the detection of the MIME type.
+func InputImageFromReader(r io.Reader, filename string) (responses.ResponseInputContentUnionParam, error) {
+	data, err := readInputContent(r, MaxInputImageSize)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, err
+	}
+	mimeType := detectMIMEType(data, filename)
+	if !slices.Contains(inputImageMIMETypes, mimeType) {
+		return responses.ResponseInputContentUnionParam{}, UserErrorf("unsupported image type %q: expected one of %v", mimeType, inputImageMIMETypes)
+	}
+	return InputImage(data, mimeType), nil
+}
+
+// InputFile returns an input content part with the file, e.g. a PDF,
+// encoded as a base64 data URL.
+func InputFile(data []byte, filename string) responses.ResponseInputContentUnionParam {
+	return responses.ResponseInputContentUnionParam{
+		OfInputFile: &responses.ResponseInputFileParam{
+			FileData: param.NewOpt(dataURL(detectMIMEType(data, filename), data)),
+			Filename: param.NewOpt(filename),
+		},
+	}
+}
+
+// InputFileFromFile reads a file, of at most MaxInputFileSize bytes, and
+// returns it as an input content part.
+func InputFileFromFile(path string) (responses.ResponseInputContentUnionParam, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return InputFileFromReader(f, filepath.Base(path))
+}
+
+// InputFileFromReader reads a file, of at most MaxInputFileSize bytes, and
+// returns it as an input content part with the given filename.
+func InputFileFromReader(r io.Reader, filename string) (responses.ResponseInputContentUnionParam, error) {
+	if filename == "" {
+		return responses.ResponseInputContentUnionParam{}, UserErrorf("input file name is required")
+	}
+	data, err := readInputContent(r, MaxInputFileSize)
+	if err != nil {
+		return responses.ResponseInputContentUnionParam{}, err
+	}
+	return InputFile(data, filename), nil
+}
+
+// UploadInputContent uploads the image or file of an input content part,
+// built with the input helpers, to the Files API, and returns the content
+// part referencing it by file ID instead. Uploaded content is not sent again
+// with each request, but can only be used with the Responses API. The client
+// defaults to the default OpenAI client.
+func UploadInputContent(
+	ctx context.Context,
+	client *OpenaiClient,
+	content responses.ResponseInputContentUnionParam,
+) (responses.ResponseInputContentUnionParam, error) {
+	var url, filename string
+	switch {
+	case content.OfInputImage != nil:
+		url, filename = content.OfInputImage.ImageURL.Value, "image"
+	case content.OfInputFile != nil:
+		url, filename = content.OfInputFile.FileData.Value, content.OfInputFile.Filename.Value
+	}
+	data, mimeType, ok := parseDataURL(url)
+	if !ok {
+		return content, UserErrorf("only inline images and files can be uploaded")
+	}
+	if ext, _ := mime.ExtensionsByType(mimeType); filepath.Ext(filename) == "" && len(ext) > 0 {
+		filename += ext[0]
+	}
+
+	if client == nil {
+		client = resolveDefaultOpenaiClient()
+	}
+	fileID, err := uploadFile(ctx, client, data, filename, mimeType, openai.FilePurposeUserData)
+	if err != nil {
+		return content, err
+	}
+
+	if content.OfInputImage != nil {
+		return responses.ResponseInputContentUnionParam{
+			OfInputImage: &responses.ResponseInputImageParam{
+				FileID: param.NewOpt(fileID),
+				Detail: content.OfInputImage.Detail,
+			},
+		}, nil
+	}
+	return responses.ResponseInputContentUnionParam{
+		OfInputFile: &responses.ResponseInputFileParam{
+			FileID:   param.NewOpt(fileID),
+			Filename: content.OfInputFile.Filename,
+		},
+	}, nil
+}
+
+// readInputContent reads at most limit bytes.
+func readInputContent(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input content: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, UserErrorf("input content exceeds the limit of %d bytes", limit)
+	}
+	return data, nil
+}
+
+// detectMIMEType returns the MIME type of the data, from its content or the
+// extension of the filename.
+func detectMIMEType(data []byte, filename string) string {
+	detected := http.DetectContentType(data)
+	if detected == "application/octet-stream" || detected == "text/plain; charset=utf-8" {
+		if byExt := mime.TypeByExtension(filepath.Ext(filename)); byExt != "" {
+			detected = byExt
+		}
+	}
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return detected
+	}
+	return mediaType
+}
+
+func dataURL(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
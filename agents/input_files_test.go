@@ -0,0 +1,122 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputImageFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pixel")
+	require.NoError(t, os.WriteFile(path, testPNG, 0o600))
+
+	content, err := agents.InputImageFromFile(path)
+	require.NoError(t, err)
+	require.NotNil(t, content.OfInputImage)
+	assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(testPNG), content.OfInputImage.ImageURL.Value)
+	assert.Equal(t, responses.ResponseInputImageDetailAuto, content.OfInputImage.Detail)
+}
+
+func TestInputImageFromReaderRejectsOtherTypes(t *testing.T) {
+	_, err := agents.InputImageFromReader(strings.NewReader("%PDF-1.7"), "doc.pdf")
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+}
+
+func TestInputImageFromReaderSizeLimit(t *testing.T) {
+	_, err := agents.InputImageFromReader(bytes.NewReader(make([]byte, agents.MaxInputImageSize+1)), "big.png")
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+}
+
+func TestInputFileFromReader(t *testing.T) {
+	content, err := agents.InputFileFromReader(strings.NewReader("%PDF-1.7"), "doc.pdf")
+	require.NoError(t, err)
+	require.NotNil(t, content.OfInputFile)
+	assert.Equal(t, "data:application/pdf;base64,"+base64.StdEncoding.EncodeToString([]byte("%PDF-1.7")), content.OfInputFile.FileData.Value)
+	assert.Equal(t, "doc.pdf", content.OfInputFile.Filename.Value)
+
+	content, err = agents.InputFileFromReader(strings.NewReader(`{"a": 1}`), "data.json")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(content.OfInputFile.FileData.Value, "data:application/json;base64,"))
+
+	_, err = agents.InputFileFromReader(strings.NewReader("x"), "")
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+}
+
+func TestUploadInputContent(t *testing.T) {
+	var uploaded struct {
+		filename, contentType, purpose string
+		data                           []byte
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files", r.URL.Path)
+		file, header, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			return
+		}
+		uploaded.filename = header.Filename
+		uploaded.contentType = header.Header.Get("Content-Type")
+		uploaded.purpose = r.FormValue("purpose")
+		uploaded.data, _ = io.ReadAll(file)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "file_123", "object": "file", "filename": header.Filename})
+	}))
+	t.Cleanup(server.Close)
+	client := agents.NewOpenaiClient(param.NewOpt(server.URL), param.NewOpt("test_key"))
+
+	content, err := agents.UploadInputContent(t.Context(), &client, agents.InputFile([]byte("%PDF-1.7"), "doc.pdf"))
+	require.NoError(t, err)
+	assert.Equal(t, "doc.pdf", uploaded.filename)
+	assert.Equal(t, "application/pdf", uploaded.contentType)
+	assert.Equal(t, "user_data", uploaded.purpose)
+	assert.Equal(t, []byte("%PDF-1.7"), uploaded.data)
+	assert.Equal(t, responses.ResponseInputContentUnionParam{
+		OfInputFile: &responses.ResponseInputFileParam{
+			FileID:   param.NewOpt("file_123"),
+			Filename: param.NewOpt("doc.pdf"),
+		},
+	}, content)
+
+	content, err = agents.UploadInputContent(t.Context(), &client, agents.InputImage(testPNG, ""))
+	require.NoError(t, err)
+	assert.Equal(t, "image.png", uploaded.filename)
+	assert.Equal(t, responses.ResponseInputContentUnionParam{
+		OfInputImage: &responses.ResponseInputImageParam{
+			FileID: param.NewOpt("file_123"),
+			Detail: responses.ResponseInputImageDetailAuto,
+		},
+	}, content)
+
+	_, err = agents.UploadInputContent(t.Context(), &client, agents.InputText("hi"))
+	var userErr agents.UserError
+	assert.ErrorAs(t, err, &userErr)
+}
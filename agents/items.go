@@ -57,6 +57,24 @@ func (mr ModelResponse) ToInputItems() []TResponseInputItem {
 	return inputItems
 }
 
+// Logprobs returns the token log probabilities of all the output_text content
+// in the response, in order. They are only present when requested through
+// ModelSettings.Logprobs or ModelSettings.TopLogprobs.
+func (mr ModelResponse) Logprobs() []responses.ResponseOutputTextLogprob {
+	var logprobs []responses.ResponseOutputTextLogprob
+	for _, outputItem := range mr.Output {
+		if outputItem.Type != "message" {
+			continue
+		}
+		for _, content := range outputItem.Content {
+			if content.Type == "output_text" {
+				logprobs = append(logprobs, content.Logprobs...)
+			}
+		}
+	}
+	return logprobs
+}
+
 type itemHelpers struct{}
 
 func ItemHelpers() itemHelpers { return itemHelpers{} }
@@ -134,6 +152,19 @@ func (itemHelpers) TextMessageOutput(message MessageOutputItem) string {
 	return sb.String()
 }
 
+// TextMessageLogprobs extracts the token log probabilities of all the text
+// content from a single message output item. They are only present when
+// requested through ModelSettings.Logprobs or ModelSettings.TopLogprobs.
+func (itemHelpers) TextMessageLogprobs(message MessageOutputItem) []responses.ResponseOutputTextLogprob {
+	var logprobs []responses.ResponseOutputTextLogprob
+	for _, item := range message.RawItem.Content {
+		if item.Type == "output_text" {
+			logprobs = append(logprobs, item.Logprobs...)
+		}
+	}
+	return logprobs
+}
+
 // ToolCallOutputItem creates a tool call output item from a tool call and its output.
 func (itemHelpers) ToolCallOutputItem(
 	toolCall ResponseFunctionToolCall,
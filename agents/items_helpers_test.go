@@ -205,6 +205,40 @@ func TestTextMessageOutputConcatenatesTextSegments(t *testing.T) {
 	assert.Equal(t, "ab", v)
 }
 
+func TestTextMessageLogprobsConcatenatesTextSegments(t *testing.T) {
+	// Logprobs of every output_text segment are returned in order.
+	message := makeMessage(
+		responses.ResponseOutputMessageContentUnion{ // responses.ResponseOutputText
+			Text:     "a",
+			Type:     "output_text",
+			Logprobs: []responses.ResponseOutputTextLogprob{{Token: "a", Logprob: -0.1}},
+		},
+		responses.ResponseOutputMessageContentUnion{ // responses.ResponseOutputRefusal
+			Refusal: "denied",
+			Type:    "refusal",
+		},
+		responses.ResponseOutputMessageContentUnion{ // responses.ResponseOutputText
+			Text:     "b",
+			Type:     "output_text",
+			Logprobs: []responses.ResponseOutputTextLogprob{{Token: "b", Logprob: -0.2}},
+		},
+	)
+	item := agents.MessageOutputItem{
+		Agent:   &agents.Agent{Name: "test"},
+		RawItem: openaitypes.ResponseOutputMessageFromResponseOutputItemUnion(message),
+		Type:    "message_output_item",
+	}
+
+	want := []responses.ResponseOutputTextLogprob{
+		{Token: "a", Logprob: -0.1},
+		{Token: "b", Logprob: -0.2},
+	}
+	assert.Equal(t, want, agents.ItemHelpers().TextMessageLogprobs(item))
+
+	modelResponse := agents.ModelResponse{Output: []agents.TResponseOutputItem{message}}
+	assert.Equal(t, want, modelResponse.Logprobs())
+}
+
 func TestTextMessageOutputsAcrossListOfRunItems(t *testing.T) {
 	// Compose several RunItem instances, including a non-message run item, and ensure
 	// that only MessageOutputItem instances contribute any text. The non-message
@@ -35,6 +35,10 @@ type MessageOutputItem struct {
 	// The raw response output message.
 	RawItem responses.ResponseOutputMessage
 
+	// The annotations of the text content of the message, e.g. the URL and
+	// file citations of the sources of the answer.
+	Annotations []Annotation
+
 	// Always `message_output_item`.
 	Type string
 }
@@ -77,6 +81,10 @@ type HandoffOutputItem struct {
 	// The agent that is being handed off to.
 	TargetAgent *Agent
 
+	// Details of the handoff, complete once the handoff is done, that is
+	// after the input filter has run.
+	Details *HandoffDetails
+
 	// Always `handoff_output_item`.
 	Type string
 }
@@ -298,3 +306,26 @@ func (MCPApprovalResponseItem) isRunItem() {}
 func (item MCPApprovalResponseItem) ToInputItem() TResponseInputItem {
 	return openaitypes.ResponseInputItemUnionParamFromResponseInputItemMcpApprovalResponseParam(item.RawItem)
 }
+
+// OutputValidationFeedbackItem asks the model to fix a structured final output
+// which failed validation against the output type of the agent.
+// See RunConfig.OutputValidationRetries.
+type OutputValidationFeedbackItem struct {
+	// The agent whose output failed validation.
+	Agent *Agent
+
+	// The validation error, usually wrapping a *JSONValidationError.
+	Error error
+
+	// The message sent to the model.
+	Feedback string
+
+	// Always `output_validation_feedback_item`.
+	Type string
+}
+
+func (OutputValidationFeedbackItem) isRunItem() {}
+
+func (item OutputValidationFeedbackItem) ToInputItem() TResponseInputItem {
+	return UserMessage(item.Feedback)
+}
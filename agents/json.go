@@ -40,10 +40,87 @@ func ValidateJSON(ctx context.Context, schema *gojsonschema.Schema, jsonValue st
 		return nil
 	}
 
+	validationErr := &JSONValidationError{
+		Violations: make([]JSONViolation, len(result.Errors())),
+	}
+	for i, e := range result.Errors() {
+		validationErr.Violations[i] = newJSONViolation(e)
+	}
+	return ModelBehaviorErrorf("%w", validationErr)
+}
+
+// JSONValidationError lists the violations of a JSON value against a JSON
+// schema. ValidateJSON returns it wrapped in a ModelBehaviorError, so callers
+// can inspect the violations with errors.As.
+type JSONValidationError struct {
+	Violations []JSONViolation
+}
+
+func (e *JSONValidationError) Error() string {
 	var sb strings.Builder
 	sb.WriteString("JSON validation failed with the following errors:\n")
-	for _, e := range result.Errors() {
-		_, _ = fmt.Fprintf(&sb, "- %s\n", e)
+	for _, v := range e.Violations {
+		_, _ = fmt.Fprintf(&sb, "- %s\n", v)
+	}
+	return sb.String()
+}
+
+// JSONViolation is a single violation of a JSON schema.
+type JSONViolation struct {
+	// JSON pointer (RFC 6901) of the invalid value, empty for the whole
+	// value. For missing and unexpected properties it points to the
+	// property itself.
+	Path string `json:"path"`
+	// What the schema expects at the path, e.g. the JSON type or the allowed
+	// values, when known.
+	Expected string `json:"expected,omitempty"`
+	// Description of the violation.
+	Message string `json:"message"`
+}
+
+func (v JSONViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + v.Message
+}
+
+func newJSONViolation(e gojsonschema.ResultError) JSONViolation {
+	// The context is built from unescaped keys: split it on a separator
+	// which cannot appear in them to escape each token.
+	tokens := strings.Split(e.Context().String("\x00"), "\x00")[1:]
+	details := e.Details()
+	switch e.Type() {
+	case "required", "additional_property_not_allowed":
+		if property, ok := details["property"].(string); ok {
+			tokens = append(tokens, property)
+		}
+	}
+
+	var path strings.Builder
+	for _, token := range tokens {
+		path.WriteByte('/')
+		path.WriteString(jsonPointerEscaper.Replace(token))
+	}
+
+	v := JSONViolation{
+		Path:    path.String(),
+		Message: e.Description(),
+	}
+	switch e.Type() {
+	case "required":
+		v.Expected = "present"
+	case "additional_property_not_allowed":
+		v.Expected = "absent"
+	case "enum":
+		v.Expected = fmt.Sprintf("one of %v", details["allowed"])
+	case "const":
+		v.Expected = fmt.Sprintf("%v", details["allowed"])
+	default:
+		if expected, ok := details["expected"]; ok {
+			v.Expected = fmt.Sprintf("%v", expected)
+		}
 	}
-	return NewModelBehaviorError(sb.String())
+	return v
 }
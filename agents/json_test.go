@@ -0,0 +1,82 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestValidateJSONViolations(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"level": map[string]any{"type": "string", "enum": []any{"low", "high"}},
+			"items": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"properties":           map[string]any{"a/b": map[string]any{"type": "integer"}},
+					"required":             []any{"a/b"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []any{"name", "level", "items"},
+		"additionalProperties": false,
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, agents.ValidateJSON(t.Context(), schema, `{"name": "x", "level": "low", "items": [{"a/b": 1}]}`))
+
+	err = agents.ValidateJSON(t.Context(), schema, `{"level": "mid", "items": [{"a/b": "1"}, {"c": 2}]}`)
+	require.ErrorAs(t, err, &agents.ModelBehaviorError{})
+
+	var validationErr *agents.JSONValidationError
+	require.True(t, errors.As(err, &validationErr))
+
+	byPath := make(map[string]agents.JSONViolation)
+	for _, v := range validationErr.Violations {
+		byPath[v.Path] = v
+	}
+	assert.Equal(t, "present", byPath["/name"].Expected)
+	assert.Equal(t, `one of "low", "high"`, byPath["/level"].Expected)
+	assert.Equal(t, "integer", byPath["/items/0/a~1b"].Expected)
+	assert.Equal(t, "present", byPath["/items/1/a~1b"].Expected)
+	assert.Equal(t, "absent", byPath["/items/1/c"].Expected)
+	assert.Len(t, validationErr.Violations, 5)
+
+	assert.Contains(t, err.Error(), "JSON validation failed with the following errors:\n")
+	assert.Contains(t, err.Error(), "- /items/0/a~1b: Invalid type. Expected: integer, given: string\n")
+}
+
+func TestValidateJSONRootViolation(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(map[string]any{"type": "object"}))
+	require.NoError(t, err)
+
+	err = agents.ValidateJSON(t.Context(), schema, `"foo"`)
+	var validationErr *agents.JSONValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Violations, 1)
+	assert.Equal(t, "", validationErr.Violations[0].Path)
+	assert.Equal(t, "object", validationErr.Violations[0].Expected)
+	assert.Equal(t, "(root): Invalid type. Expected: object, given: string", validationErr.Violations[0].String())
+}
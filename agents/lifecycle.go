@@ -35,7 +35,8 @@ type RunHooks interface {
 	// OnAgentEnd is called when the agent produces a final output.
 	OnAgentEnd(ctx context.Context, agent *Agent, output any) error
 
-	// OnHandoff is called when a handoff occurs.
+	// OnHandoff is called when a handoff occurs. HandoffDetailsFromContext
+	// returns the details of the handoff.
 	OnHandoff(ctx context.Context, fromAgent, toAgent *Agent) error
 
 	// OnToolStart is called concurrently with tool invocation.
@@ -81,6 +82,7 @@ type AgentHooks interface {
 
 	// OnHandoff is called when the agent is being handed off to.
 	// The `source` is the agent that is handing off to this agent.
+	// HandoffDetailsFromContext returns the details of the handoff.
 	OnHandoff(ctx context.Context, agent, source *Agent) error
 
 	// OnToolStart is called concurrently with tool invocation.
@@ -0,0 +1,205 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/asyncqueue"
+)
+
+// LiveEventKind identifies the kind of a LiveEvent.
+type LiveEventKind string
+
+const (
+	LiveEventRunStarted  LiveEventKind = "run_started"
+	LiveEventRunFinished LiveEventKind = "run_finished"
+	LiveEventStream      LiveEventKind = "stream_event"
+	LiveEventAgentStart  LiveEventKind = "agent_start"
+	LiveEventAgentEnd    LiveEventKind = "agent_end"
+	LiveEventHandoff     LiveEventKind = "handoff"
+	LiveEventLLMStart    LiveEventKind = "llm_start"
+	LiveEventLLMEnd      LiveEventKind = "llm_end"
+	LiveEventToolStart   LiveEventKind = "tool_start"
+	LiveEventToolEnd     LiveEventKind = "tool_end"
+)
+
+// LiveEvent is an event of an active run, broadcast to the subscribers
+// registered with SubscribeLiveEvents.
+//
+// Only the fields relevant to the event Kind are set.
+type LiveEvent struct {
+	Kind LiveEventKind
+
+	// The ID of the run, as found in log records (see Logger).
+	RunID    string
+	Workflow string
+	Time     time.Time
+
+	// The agent the event refers to. For handoffs, it is the agent being
+	// handed off to.
+	Agent *Agent
+
+	// The stream event, for LiveEventStream. Stream events are only produced
+	// by streamed runs.
+	StreamEvent StreamEvent
+
+	// The agent handing off, for LiveEventHandoff.
+	FromAgent *Agent
+
+	// The tool being invoked, for LiveEventToolStart and LiveEventToolEnd.
+	Tool Tool
+
+	// The tool result, for LiveEventToolEnd, or the final output of the agent,
+	// for LiveEventAgentEnd.
+	Output any
+
+	// The model response, for LiveEventLLMEnd.
+	Response *ModelResponse
+
+	// The error which made the run fail, for LiveEventRunFinished.
+	Err error
+}
+
+// LiveEventSubscription receives the events of all active runs. It is
+// created with SubscribeLiveEvents.
+type LiveEventSubscription struct {
+	events    chan LiveEvent
+	dropped   atomic.Uint64
+	closeOnce sync.Once
+}
+
+// SubscribeLiveEvents registers a new subscription receiving every stream
+// event and lifecycle hook of all the runs in the process, for example to
+// feed a live activity dashboard.
+//
+// Events are buffered up to bufferSize. Publishing never blocks the runs:
+// when the buffer is full, new events are dropped and counted (see
+// LiveEventSubscription.Dropped). Close the subscription when done.
+func SubscribeLiveEvents(bufferSize int) *LiveEventSubscription {
+	s := &LiveEventSubscription{events: make(chan LiveEvent, max(bufferSize, 0))}
+	liveEvents.mu.Lock()
+	defer liveEvents.mu.Unlock()
+	liveEvents.subscriptions[s] = struct{}{}
+	liveEvents.count.Store(int64(len(liveEvents.subscriptions)))
+	return s
+}
+
+// Events returns the channel of events, which is closed by Close.
+func (s *LiveEventSubscription) Events() <-chan LiveEvent {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (s *LiveEventSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unregisters the subscription and closes its events channel.
+func (s *LiveEventSubscription) Close() {
+	s.closeOnce.Do(func() {
+		liveEvents.mu.Lock()
+		defer liveEvents.mu.Unlock()
+		delete(liveEvents.subscriptions, s)
+		liveEvents.count.Store(int64(len(liveEvents.subscriptions)))
+		close(s.events)
+	})
+}
+
+var liveEvents = struct {
+	mu            sync.RWMutex
+	subscriptions map[*LiveEventSubscription]struct{}
+	count         atomic.Int64
+}{
+	subscriptions: make(map[*LiveEventSubscription]struct{}),
+}
+
+type liveRunContextKey struct{}
+
+type liveRun struct {
+	id       string
+	workflow string
+}
+
+func contextWithLiveRun(ctx context.Context, runID, workflow string) context.Context {
+	return context.WithValue(ctx, liveRunContextKey{}, liveRun{id: runID, workflow: workflow})
+}
+
+// publishLiveEvent broadcasts the event to all subscribers, completing it
+// with the run found in the context.
+func publishLiveEvent(ctx context.Context, event LiveEvent) {
+	if liveEvents.count.Load() == 0 {
+		return
+	}
+	if run, ok := ctx.Value(liveRunContextKey{}).(liveRun); ok {
+		event.RunID = run.id
+		event.Workflow = run.workflow
+	}
+	event.Time = time.Now()
+
+	liveEvents.mu.RLock()
+	defer liveEvents.mu.RUnlock()
+	for s := range liveEvents.subscriptions {
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// emitStreamEvent puts the event in the queue of a streamed run, and
+// broadcasts it to live event subscribers.
+func emitStreamEvent(ctx context.Context, queue *asyncqueue.Queue[StreamEvent], event StreamEvent) {
+	// A full queue is waited for until the run is canceled.
+	if queue.PutContext(ctx, event) != nil {
+		return
+	}
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventStream, StreamEvent: event})
+}
+
+// liveEventRunHooks wraps the RunHooks of a run, broadcasting each hook to
+// live event subscribers.
+type liveEventRunHooks struct {
+	RunHooks
+}
+
+func (h liveEventRunHooks) OnAgentStart(ctx context.Context, agent *Agent) error {
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventAgentStart, Agent: agent})
+	return h.RunHooks.OnAgentStart(ctx, agent)
+}
+
+func (h liveEventRunHooks) OnAgentEnd(ctx context.Context, agent *Agent, output any) error {
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventAgentEnd, Agent: agent, Output: output})
+	return h.RunHooks.OnAgentEnd(ctx, agent, output)
+}
+
+func (h liveEventRunHooks) OnHandoff(ctx context.Context, fromAgent, toAgent *Agent) error {
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventHandoff, Agent: toAgent, FromAgent: fromAgent})
+	return h.RunHooks.OnHandoff(ctx, fromAgent, toAgent)
+}
+
+func (h liveEventRunHooks) OnToolStart(ctx context.Context, agent *Agent, tool Tool) error {
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventToolStart, Agent: agent, Tool: tool})
+	return h.RunHooks.OnToolStart(ctx, agent, tool)
+}
+
+func (h liveEventRunHooks) OnToolEnd(ctx context.Context, agent *Agent, tool Tool, result any) error {
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventToolEnd, Agent: agent, Tool: tool, Output: result})
+	return h.RunHooks.OnToolEnd(ctx, agent, tool, result)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveEvents(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming %v", streaming), func(t *testing.T) {
+			subscription := agents.SubscribeLiveEvents(1000)
+			defer subscription.Close()
+
+			model := agentstesting.NewFakeModel(false, nil)
+			agent := agents.New("test").
+				WithModelInstance(model).
+				WithTools(agentstesting.GetFunctionTool("foo", "tool_result"))
+
+			model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+				{Value: []agents.TResponseOutputItem{
+					agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`),
+				}},
+				{Value: []agents.TResponseOutputItem{
+					agentstesting.GetTextMessage("done"),
+				}},
+			})
+
+			workflow := fmt.Sprintf("live_events_%v", streaming)
+			runner := agents.Runner{Config: agents.RunConfig{WorkflowName: workflow}}
+			var streamEvents int
+			if streaming {
+				result, err := runner.RunStreamed(t.Context(), agent, "user_message")
+				require.NoError(t, err)
+				require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error {
+					streamEvents++
+					return nil
+				}))
+			} else {
+				_, err := runner.Run(t.Context(), agent, "user_message")
+				require.NoError(t, err)
+			}
+
+			// The end of a streamed run may be published after the stream
+			// is complete: read until then.
+			var (
+				kinds           []agents.LiveEventKind
+				liveStreamEvent int
+				runID           string
+			)
+			for len(kinds) == 0 || kinds[len(kinds)-1] != agents.LiveEventRunFinished {
+				var event agents.LiveEvent
+				select {
+				case event = <-subscription.Events():
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timeout waiting for live events, got %v", kinds)
+				}
+				if event.Workflow != workflow {
+					continue
+				}
+				if runID == "" {
+					runID = event.RunID
+				}
+				assert.Equal(t, runID, event.RunID)
+				assert.False(t, event.Time.IsZero())
+				if event.Kind == agents.LiveEventStream {
+					liveStreamEvent++
+					continue
+				}
+				kinds = append(kinds, event.Kind)
+			}
+			assert.Zero(t, subscription.Dropped())
+			assert.NotEmpty(t, runID)
+			assert.Equal(t, streamEvents, liveStreamEvent)
+			assert.Equal(t, []agents.LiveEventKind{
+				agents.LiveEventRunStarted,
+				agents.LiveEventAgentStart,
+				agents.LiveEventLLMStart,
+				agents.LiveEventLLMEnd,
+				agents.LiveEventToolStart,
+				agents.LiveEventToolEnd,
+				agents.LiveEventLLMStart,
+				agents.LiveEventLLMEnd,
+				agents.LiveEventAgentEnd,
+				agents.LiveEventRunFinished,
+			}, kinds)
+		})
+	}
+}
+
+func TestLiveEventSubscriptionDropsWhenFull(t *testing.T) {
+	subscription := agents.SubscribeLiveEvents(0)
+	defer subscription.Close()
+
+	model := agentstesting.NewFakeModel(false, nil)
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")},
+	})
+	_, err := agents.Run(t.Context(), agents.New("test").WithModelInstance(model), "user_message")
+	require.NoError(t, err)
+	assert.NotZero(t, subscription.Dropped())
+}
@@ -15,8 +15,10 @@
 package agents
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"slices"
 	"sync/atomic"
 )
 
@@ -28,7 +30,13 @@ func init() {
 
 // Logger is the global logger used by Agents SDK.
 // By default, it is a logger with a text handler which writes to stdout,
-// with minimum level "info". You can change it with SetLogger.
+// with minimum level "info". You can change it with SetLogger or
+// SetLogHandler.
+//
+// When logging with a context (e.g. Logger().InfoContext(ctx, ...)), the
+// attributes attached to the context with ContextWithLogAttrs are added to
+// the record. During a run, they include the run ID, the current agent and
+// turn, and the tool being executed.
 func Logger() *slog.Logger {
 	return agentsLogger.Load()
 }
@@ -37,18 +45,78 @@ func Logger() *slog.Logger {
 // A nil value is ignored.
 func SetLogger(l *slog.Logger) {
 	if l != nil {
-		agentsLogger.Store(l)
+		SetLogHandler(l.Handler())
+	}
+}
+
+// SetLogHandler sets the handler of the global logger used by Agents SDK.
+// A nil value is ignored.
+func SetLogHandler(h slog.Handler) {
+	if h == nil {
+		return
+	}
+	if _, ok := h.(contextLogHandler); !ok {
+		h = contextLogHandler{Handler: h}
 	}
+	agentsLogger.Store(slog.New(h))
 }
 
 func ResetLogger() {
 	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
-	SetLogger(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+	SetLogHandler(slog.NewTextHandler(os.Stderr, opts))
 }
 
 // EnableVerboseStdoutLogging enables verbose logging to stdout.
 // This is useful for debugging.
 func EnableVerboseStdoutLogging() {
 	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
-	agentsLogger.Store(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+	SetLogHandler(slog.NewTextHandler(os.Stderr, opts))
+}
+
+type logAttrsContextKey struct{}
+
+// ContextWithLogAttrs returns a copy of ctx carrying the given attributes,
+// which are added to the records logged with that context. An attribute
+// replaces any previous one with the same key.
+func ContextWithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	current := LogAttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(current)+len(attrs))
+	for _, attr := range current {
+		if !slices.ContainsFunc(attrs, func(a slog.Attr) bool { return a.Key == attr.Key }) {
+			merged = append(merged, attr)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logAttrsContextKey{}, merged)
+}
+
+// LogAttrsFromContext returns the attributes attached to ctx with
+// ContextWithLogAttrs.
+func LogAttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(logAttrsContextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextLogHandler adds the attributes carried by the context to each record.
+type contextLogHandler struct {
+	slog.Handler
+}
+
+func (h contextLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := LogAttrsFromContext(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextLogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextLogHandler) WithGroup(name string) slog.Handler {
+	return contextLogHandler{Handler: h.Handler.WithGroup(name)}
 }
@@ -0,0 +1,84 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuilder struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuilder) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func TestContextWithLogAttrs(t *testing.T) {
+	ctx := agents.ContextWithLogAttrs(t.Context(), slog.String("a", "1"), slog.Int("b", 2))
+	ctx = agents.ContextWithLogAttrs(ctx, slog.String("a", "3"))
+
+	assert.Equal(t, []slog.Attr{slog.Int("b", 2), slog.String("a", "3")}, agents.LogAttrsFromContext(ctx))
+	assert.Empty(t, agents.LogAttrsFromContext(t.Context()))
+}
+
+func TestLoggerRunAttributes(t *testing.T) {
+	var out syncBuilder
+	agents.SetLogHandler(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(agents.ResetLogger)
+
+	tool := agents.NewFunctionTool("log_something", "", func(ctx context.Context, _ struct{}) (string, error) {
+		agents.Logger().InfoContext(ctx, "inside tool")
+		return "ok", nil
+	})
+
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := agents.New("logging_agent").WithModelInstance(model).WithTools(tool)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("log_something", `{}`)}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+
+	_, err := agents.Runner{Config: agents.RunConfig{WorkflowName: "logs"}}.Run(t.Context(), agent, "hi")
+	require.NoError(t, err)
+
+	var toolRecord map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.sb.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["msg"] == "inside tool" {
+			toolRecord = record
+		}
+	}
+	require.NotNil(t, toolRecord)
+	assert.Regexp(t, "^run_", toolRecord["run_id"])
+	assert.Equal(t, "logs", toolRecord["workflow"])
+	assert.Equal(t, "logging_agent", toolRecord["agent"])
+	assert.Equal(t, float64(1), toolRecord["turn"])
+	assert.Equal(t, "log_something", toolRecord["tool"])
+}
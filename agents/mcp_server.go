@@ -110,7 +110,7 @@ func NewMCPServerWithClientSession(params MCPServerWithClientSessionParams) *MCP
 func (s *MCPServerWithClientSession) Connect(ctx context.Context) (err error) {
 	defer func() {
 		if err != nil {
-			Logger().Error("Error initializing MCP server", slog.String("error", err.Error()))
+			Logger().ErrorContext(ctx, "Error initializing MCP server", slog.String("error", err.Error()))
 			if e := s.Cleanup(ctx); e != nil {
 				err = errors.Join(err, fmt.Errorf("MCP server cleanup error: %w", e))
 			}
@@ -209,9 +209,9 @@ func (mcpUtil) InvokeMCPTool(
 		err := json.Unmarshal([]byte(jsonInput), &jsonData)
 		if err != nil {
 			if DontLogToolData {
-				Logger().Debug("Invalid JSON input", slog.String("toolName", tool.Name))
+				Logger().DebugContext(ctx, "Invalid JSON input", slog.String("toolName", tool.Name))
 			} else {
-				Logger().Debug("Invalid JSON input",
+				Logger().DebugContext(ctx, "Invalid JSON input",
 					slog.String("toolName", tool.Name),
 					slog.String("jsonInput", jsonInput))
 			}
@@ -221,25 +221,25 @@ func (mcpUtil) InvokeMCPTool(
 	}
 
 	if DontLogToolData {
-		Logger().Debug("Invoking MCP tool", slog.String("toolName", tool.Name))
+		Logger().DebugContext(ctx, "Invoking MCP tool", slog.String("toolName", tool.Name))
 	} else {
-		Logger().Debug("Invoking MCP tool",
+		Logger().DebugContext(ctx, "Invoking MCP tool",
 			slog.String("toolName", tool.Name),
 			slog.String("input", jsonInput))
 	}
 
 	result, err := server.CallTool(ctx, tool.Name, jsonData)
 	if err != nil {
-		Logger().Error("Error invoking MCP tool",
+		Logger().ErrorContext(ctx, "Error invoking MCP tool",
 			slog.String("toolName", tool.Name),
 			slog.String("error", err.Error()))
 		return "", AgentsErrorf("error invoking MCP tool %s: %w", tool.Name, err)
 	}
 
 	if DontLogToolData {
-		Logger().Debug("MCP tool completed", slog.String("toolName", tool.Name))
+		Logger().DebugContext(ctx, "MCP tool completed", slog.String("toolName", tool.Name))
 	} else {
-		Logger().Debug("MCP tool completed",
+		Logger().DebugContext(ctx, "MCP tool completed",
 			slog.String("toolName", tool.Name),
 			slog.Any("result", *result))
 	}
@@ -281,7 +281,7 @@ func (mcpUtil) InvokeMCPTool(
 			spanData.Output = toolOutput
 			spanData.MCPData = map[string]any{"server": server.Name()}
 		} else {
-			Logger().Warn(fmt.Sprintf("Current span is not a FunctionSpanData, skipping tool output: %#v", currentSpan))
+			Logger().WarnContext(ctx, fmt.Sprintf("Current span is not a FunctionSpanData, skipping tool output: %#v", currentSpan))
 		}
 	}
 
@@ -304,7 +304,7 @@ func ApplyMCPToolFilter(
 	for _, tool := range tools {
 		shouldInclude, err := toolFilter.FilterMCPTool(ctx, filterContext, tool)
 		if err != nil {
-			Logger().Error("Error applying tool filter",
+			Logger().ErrorContext(ctx, "Error applying tool filter",
 				slog.String("toolName", tool.Name),
 				slog.String("serverName", filterContext.ServerName),
 				slog.String("error", err.Error()),
@@ -0,0 +1,108 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingMetrics) record(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingMetrics) RunStarted(workflow string) {
+	r.record("run_started %s", workflow)
+}
+
+func (r *recordingMetrics) RunFinished(workflow, outcome string, turns uint64, _ time.Duration) {
+	r.record("run_finished %s %s %d", workflow, outcome, turns)
+}
+
+func (r *recordingMetrics) RunRejected(workflow, reason string) {
+	r.record("run_rejected %s %s", workflow, reason)
+}
+
+func (r *recordingMetrics) ModelCall(model, outcome string, _ time.Duration) {
+	r.record("model_call %s %s", model, outcome)
+}
+
+func (r *recordingMetrics) TokensUsed(model string, inputTokens, outputTokens uint64) {
+	r.record("tokens %s %d %d", model, inputTokens, outputTokens)
+}
+
+func (r *recordingMetrics) ToolCall(tool, outcome string, _ time.Duration) {
+	r.record("tool_call %s %s", tool, outcome)
+}
+
+func (r *recordingMetrics) GuardrailTripped(guardrail, kind string) {
+	r.record("guardrail %s %s", guardrail, kind)
+}
+
+func TestRunMetrics(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming %v", streaming), func(t *testing.T) {
+			recorder := new(recordingMetrics)
+			metrics.SetRecorder(recorder)
+			t.Cleanup(func() { metrics.SetRecorder(nil) })
+
+			model := agentstesting.NewFakeModel(false, nil)
+			agent := agents.New("test").
+				WithModelInstance(model).
+				WithTools(agentstesting.GetFunctionTool("foo", "tool_result"))
+
+			model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+				{Value: []agents.TResponseOutputItem{
+					agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`),
+				}},
+				{Value: []agents.TResponseOutputItem{
+					agentstesting.GetTextMessage("done"),
+				}},
+			})
+
+			runner := agents.Runner{Config: agents.RunConfig{WorkflowName: "metrics"}}
+			if streaming {
+				result, err := runner.RunStreamed(t.Context(), agent, "user_message")
+				require.NoError(t, err)
+				require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+			} else {
+				_, err := runner.Run(t.Context(), agent, "user_message")
+				require.NoError(t, err)
+			}
+
+			recorder.mu.Lock()
+			defer recorder.mu.Unlock()
+			assert.Equal(t, "run_started metrics", recorder.events[0])
+			assert.Equal(t, "run_finished metrics succeeded 2", recorder.events[len(recorder.events)-1])
+			assert.Contains(t, recorder.events, "model_call custom succeeded")
+			assert.Contains(t, recorder.events, "tool_call foo succeeded")
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package agents
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -137,6 +138,53 @@ func (chatCmplConverter) MessageToOutputItems(message openai.ChatCompletionMessa
 	return items, nil
 }
 
+// AttachLogprobs sets the given Chat Completions token logprobs on the
+// output_text content of the first message among items, if any.
+func (c chatCmplConverter) AttachLogprobs(items []TResponseOutputItem, logprobs []openai.ChatCompletionTokenLogprob) {
+	if len(logprobs) == 0 {
+		return
+	}
+	for i := range items {
+		if items[i].Type != "message" {
+			continue
+		}
+		for j := range items[i].Content {
+			if items[i].Content[j].Type == "output_text" {
+				items[i].Content[j].Logprobs = c.ConvertLogprobs(logprobs)
+				return
+			}
+		}
+	}
+}
+
+// ConvertLogprobs converts Chat Completions token logprobs into their Responses API equivalent.
+func (chatCmplConverter) ConvertLogprobs(logprobs []openai.ChatCompletionTokenLogprob) []responses.ResponseOutputTextLogprob {
+	if len(logprobs) == 0 {
+		return nil
+	}
+	result := make([]responses.ResponseOutputTextLogprob, len(logprobs))
+	for i, lp := range logprobs {
+		var topLogprobs []responses.ResponseOutputTextLogprobTopLogprob
+		if len(lp.TopLogprobs) > 0 {
+			topLogprobs = make([]responses.ResponseOutputTextLogprobTopLogprob, len(lp.TopLogprobs))
+			for j, top := range lp.TopLogprobs {
+				topLogprobs[j] = responses.ResponseOutputTextLogprobTopLogprob{
+					Token:   top.Token,
+					Bytes:   top.Bytes,
+					Logprob: top.Logprob,
+				}
+			}
+		}
+		result[i] = responses.ResponseOutputTextLogprob{
+			Token:       lp.Token,
+			Bytes:       lp.Bytes,
+			Logprob:     lp.Logprob,
+			TopLogprobs: topLogprobs,
+		}
+	}
+	return result
+}
+
 func (conv chatCmplConverter) ExtractTextContentFromEasyInputMessageContentUnionParam(
 	content responses.EasyInputMessageContentUnionParam,
 ) (param.Opt[string], []openai.ChatCompletionContentPartTextParam, error) {
@@ -230,6 +278,16 @@ func (chatCmplConverter) ExtractAllContentFromResponseInputContentUnionParams(
 					},
 				},
 			}
+		} else if data, format, ok := InputAudioData(c); ok {
+			out[i] = openai.ChatCompletionContentPartUnionParam{
+				OfInputAudio: &openai.ChatCompletionContentPartInputAudioParam{
+					InputAudio: openai.ChatCompletionContentPartInputAudioInputAudioParam{
+						Data:   base64.StdEncoding.EncodeToString(data),
+						Format: format,
+					},
+					Type: constant.ValueOf[constant.InputAudio](),
+				},
+			}
 		} else if !param.IsOmitted(c.OfInputFile) {
 			fileParam := c.OfInputFile
 			if !fileParam.FileData.Valid() {
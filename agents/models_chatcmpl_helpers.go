@@ -63,3 +63,39 @@ func (h chatCmplHelpers) GetStreamOptionsParam(
 	}
 	return options
 }
+
+func (chatCmplHelpers) GetLogprobsParam(modelSettings modelsettings.ModelSettings) param.Opt[bool] {
+	// The API rejects top_logprobs unless logprobs is enabled
+	switch {
+	case modelSettings.Logprobs.Valid():
+		return modelSettings.Logprobs
+	case modelSettings.TopLogprobs.Valid():
+		return param.NewOpt(true)
+	default:
+		return param.Opt[bool]{}
+	}
+}
+
+// IsReasoningModel reports whether the model belongs to the o-series or
+// GPT-5 families of reasoning models, which reject max_tokens.
+func (chatCmplHelpers) IsReasoningModel(model string) bool {
+	model = strings.ToLower(model[strings.LastIndex(model, "/")+1:])
+	if strings.HasPrefix(model, "gpt-5") {
+		return true
+	}
+	return len(model) > 1 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9'
+}
+
+func (h chatCmplHelpers) GetMaxTokensParams(
+	model string,
+	modelSettings modelsettings.ModelSettings,
+) (maxTokens, maxCompletionTokens param.Opt[int64]) {
+	switch {
+	case modelSettings.MaxCompletionTokens.Valid():
+		return param.Opt[int64]{}, modelSettings.MaxCompletionTokens
+	case modelSettings.MaxTokens.Valid() && h.IsReasoningModel(model):
+		return param.Opt[int64]{}, modelSettings.MaxTokens
+	default:
+		return modelSettings.MaxTokens, param.Opt[int64]{}
+	}
+}
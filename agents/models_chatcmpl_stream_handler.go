@@ -31,6 +31,7 @@ type StreamingState struct {
 	TextContentIndexAndOutput    *textContentIndexAndOutput
 	RefusalContentIndexAndOutput *refusalContentIndexAndOutput
 	FunctionCalls                map[int64]*responses.ResponseOutputItemUnion // responses.ResponseFunctionToolCall
+	TextLogprobs                 []openai.ChatCompletionTokenLogprob
 }
 
 func NewStreamingState() StreamingState {
@@ -106,6 +107,10 @@ func (chatCmplStreamHandler) HandleStream(
 
 		delta := chunk.Choices[0].Delta
 
+		// Logprobs are only sent when requested; they are attached to the
+		// final output_text part once the stream is over.
+		state.TextLogprobs = append(state.TextLogprobs, chunk.Choices[0].Logprobs.Content...)
+
 		// Handle text
 		if delta.Content != "" {
 			if state.TextContentIndexAndOutput == nil {
@@ -262,6 +267,7 @@ func (chatCmplStreamHandler) HandleStream(
 	functionCallStartingIndex := int64(0)
 	if state.TextContentIndexAndOutput != nil {
 		functionCallStartingIndex += 1
+		state.TextContentIndexAndOutput.Output.Logprobs = ChatCmplConverter().ConvertLogprobs(state.TextLogprobs)
 		// Send end event for this content part
 		if err = yield(TResponseStreamEvent{ // responses.ResponseContentPartDoneEvent
 			ContentIndex:   state.TextContentIndexAndOutput.Index,
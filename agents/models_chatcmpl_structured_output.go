@@ -0,0 +1,195 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared"
+	"github.com/openai/openai-go/v3/shared/constant"
+)
+
+// StructuredOutputMode is how a Chat Completions model is asked to produce
+// the JSON of a structured output type.
+type StructuredOutputMode uint8
+
+const (
+	// StructuredOutputJSONSchema sends the schema of the output type as
+	// response format, for the models supporting structured outputs.
+	StructuredOutputJSONSchema StructuredOutputMode = iota
+	// StructuredOutputJSONMode is a fallback for the models supporting only
+	// JSON mode: the schema is embedded in the system instructions, and the
+	// JSON object response format is requested.
+	StructuredOutputJSONMode
+	// StructuredOutputPrompt is a fallback for the models without any
+	// response format support: the schema is only embedded in the system
+	// instructions.
+	StructuredOutputPrompt
+)
+
+// StructuredOutputFallbackRetries is the number of times a model using a
+// fallback StructuredOutputMode is asked again for an output which does
+// not conform to the schema, even after RepairJSON, before returning it.
+// The retries are only made for non-streamed responses, while streamed ones
+// are only repaired.
+const StructuredOutputFallbackRetries = 2
+
+func (m StructuredOutputMode) String() string {
+	switch m {
+	case StructuredOutputJSONSchema:
+		return "json_schema"
+	case StructuredOutputJSONMode:
+		return "json_mode"
+	case StructuredOutputPrompt:
+		return "prompt"
+	default:
+		return fmt.Sprintf("StructuredOutputMode(%d)", uint8(m))
+	}
+}
+
+// usesStructuredOutputFallback reports whether the schema of the output type
+// is embedded in the instructions instead of the response format.
+func (m OpenAIChatCompletionsModel) usesStructuredOutputFallback(outputType OutputTypeInterface) bool {
+	return m.StructuredOutputMode != StructuredOutputJSONSchema && outputType != nil && !outputType.IsPlainText()
+}
+
+// structuredOutputInstructions returns the instructions describing the JSON
+// expected for the output type.
+func structuredOutputInstructions(outputType OutputTypeInterface) (string, error) {
+	schema, err := outputType.JSONSchema()
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to JSON-marshal output schema: %w", err)
+	}
+	return "When you give your final answer, respond only with a JSON document conforming to " +
+		"the following JSON schema, without code fences or any other text:\n" + string(b), nil
+}
+
+// structuredOutputFallbackFormat returns the response format of a fallback
+// StructuredOutputMode.
+func (m OpenAIChatCompletionsModel) structuredOutputFallbackFormat() openai.ChatCompletionNewParamsResponseFormatUnion {
+	if m.StructuredOutputMode != StructuredOutputJSONMode {
+		return openai.ChatCompletionNewParamsResponseFormatUnion{}
+	}
+	return openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONObject: &shared.ResponseFormatJSONObjectParam{
+			Type: constant.ValueOf[constant.JSONObject](),
+		},
+	}
+}
+
+// validateFallbackOutput validates the final output of a response obtained
+// with a fallback StructuredOutputMode, repairing it with RepairJSON, and
+// asks the model again up to StructuredOutputFallbackRetries times when it
+// does not conform to the schema. The returned response carries the usage
+// of all the attempts, whose number is returned too.
+func (m OpenAIChatCompletionsModel) validateFallbackOutput(
+	ctx context.Context,
+	body openai.ChatCompletionNewParams,
+	opts []option.RequestOption,
+	outputType OutputTypeInterface,
+	response *openai.ChatCompletion,
+) (*openai.ChatCompletion, int, error) {
+	schema, err := outputType.JSONSchema()
+	if err != nil {
+		return nil, 0, err
+	}
+	body.Messages = append([]openai.ChatCompletionMessageParamUnion(nil), body.Messages...)
+
+	for attempt := 0; ; attempt++ {
+		if len(response.Choices) == 0 {
+			return response, attempt + 1, nil
+		}
+		message := &response.Choices[0].Message
+		if len(message.ToolCalls) > 0 || message.Content == "" {
+			return response, attempt + 1, nil
+		}
+
+		repaired, _, validationErr := RepairJSON(message.Content, schema)
+		if validationErr == nil {
+			_, validationErr = outputType.ValidateJSON(ctx, repaired)
+		}
+		if validationErr == nil {
+			message.Content = repaired
+			return response, attempt + 1, nil
+		}
+		if attempt == StructuredOutputFallbackRetries {
+			// The run reports the validation error.
+			return response, attempt + 1, nil
+		}
+
+		Logger().DebugContext(ctx, "Retrying invalid structured output",
+			slog.String("mode", m.StructuredOutputMode.String()),
+			slog.Int("attempt", attempt+1),
+			slog.String("error", validationErr.Error()))
+
+		body.Messages = append(body.Messages,
+			openai.AssistantMessage(message.Content),
+			openai.UserMessage(fmt.Sprintf(
+				"Your response does not conform to the JSON schema: %s\n"+
+					"Respond again with only the corrected JSON document.", validationErr)),
+		)
+		next, err := m.client.Chat.Completions.New(ctx, body, opts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		next.Usage.PromptTokens += response.Usage.PromptTokens
+		next.Usage.CompletionTokens += response.Usage.CompletionTokens
+		next.Usage.TotalTokens += response.Usage.TotalTokens
+		next.Usage.PromptTokensDetails.CachedTokens += response.Usage.PromptTokensDetails.CachedTokens
+		next.Usage.CompletionTokensDetails.ReasoningTokens += response.Usage.CompletionTokensDetails.ReasoningTokens
+		response = next
+	}
+}
+
+// repairFallbackStreamOutput repairs with RepairJSON the final output of a
+// streamed response obtained with a fallback StructuredOutputMode, if it
+// then conforms to the schema.
+func repairFallbackStreamOutput(ctx context.Context, outputType OutputTypeInterface, response *responses.Response) {
+	schema, err := outputType.JSONSchema()
+	if err != nil {
+		return
+	}
+	for i := len(response.Output) - 1; i >= 0; i-- {
+		item := &response.Output[i]
+		if item.Type != "message" {
+			continue
+		}
+		for j := len(item.Content) - 1; j >= 0; j-- {
+			content := &item.Content[j]
+			if content.Type != "output_text" {
+				continue
+			}
+			repaired, repairs, err := RepairJSON(content.Text, schema)
+			if err != nil || len(repairs) == 0 {
+				return
+			}
+			if _, err = outputType.ValidateJSON(ctx, repaired); err == nil {
+				content.Text = repaired
+			}
+			return
+		}
+		return
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/modelsettings"
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type structuredOutputTestFoo struct {
+	Bar   string `json:"bar"`
+	Count int    `json:"count"`
+}
+
+// makeOpenaiClientWithContents returns a client answering the chat
+// completions requests with the given contents in turn, and recording the
+// request bodies.
+func makeOpenaiClientWithContents(t *testing.T, contents ...string) (OpenaiClient, *[]map[string]any) {
+	t.Helper()
+
+	var requests []map[string]any
+	return OpenaiClient{
+		BaseURL: param.NewOpt("https://fake"),
+		Client: openai.NewClient(
+			option.WithMiddleware(func(req *http.Request, _ option.MiddlewareNext) (*http.Response, error) {
+				var reqBody map[string]any
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&reqBody))
+				content := contents[len(requests)]
+				requests = append(requests, reqBody)
+
+				body, err := json.Marshal(map[string]any{
+					"id":      "resp-id",
+					"object":  "chat.completion",
+					"model":   "fake",
+					"choices": []any{map[string]any{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": content}}},
+					"usage":   map[string]any{"completion_tokens": 5, "prompt_tokens": 7, "total_tokens": 12},
+				})
+				require.NoError(t, err)
+				return &http.Response{
+					StatusCode:    http.StatusOK,
+					Body:          io.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Header:        http.Header{"Content-Type": []string{"application/json"}},
+				}, nil
+			}),
+		),
+	}, &requests
+}
+
+func TestStructuredOutputFallbackPrepareRequest(t *testing.T) {
+	outputType := OutputType[structuredOutputTestFoo]()
+	schema, err := outputType.JSONSchema()
+	require.NoError(t, err)
+	schemaJSON, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	prepare := func(t *testing.T, mode StructuredOutputMode, outputType OutputTypeInterface) *openai.ChatCompletionNewParams {
+		t.Helper()
+		client := NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{})
+		provider := NewOpenAIProvider(OpenAIProviderParams{
+			OpenaiClient: &client,
+			UseResponses: param.NewOpt(false),
+			StructuredOutputMode: func(modelName string) StructuredOutputMode {
+				assert.Equal(t, "local-model", modelName)
+				return mode
+			},
+		})
+		model, err := provider.GetModel("local-model")
+		require.NoError(t, err)
+
+		var params *openai.ChatCompletionNewParams
+		err = tracing.GenerationSpan(
+			t.Context(), tracing.GenerationSpanParams{Disabled: true},
+			func(ctx context.Context, span tracing.Span) (err error) {
+				params, _, err = model.(OpenAIChatCompletionsModel).prepareRequest(
+					ctx, param.NewOpt("sys"), InputString("hi"), modelsettings.ModelSettings{},
+					nil, outputType, nil, span, ModelTracingDisabled, false,
+				)
+				return err
+			},
+		)
+		require.NoError(t, err)
+		return params
+	}
+
+	t.Run("json schema", func(t *testing.T) {
+		params := prepare(t, StructuredOutputJSONSchema, outputType)
+		assert.NotNil(t, params.ResponseFormat.OfJSONSchema)
+		assert.Equal(t, "sys", params.Messages[0].OfSystem.Content.OfString.Value)
+	})
+
+	t.Run("json mode", func(t *testing.T) {
+		params := prepare(t, StructuredOutputJSONMode, outputType)
+		assert.Nil(t, params.ResponseFormat.OfJSONSchema)
+		assert.NotNil(t, params.ResponseFormat.OfJSONObject)
+		instructions := params.Messages[0].OfSystem.Content.OfString.Value
+		assert.Contains(t, instructions, "sys\n\n")
+		assert.Contains(t, instructions, string(schemaJSON))
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		params := prepare(t, StructuredOutputPrompt, outputType)
+		assert.Zero(t, params.ResponseFormat)
+		assert.Contains(t, params.Messages[0].OfSystem.Content.OfString.Value, string(schemaJSON))
+	})
+
+	t.Run("plain text output", func(t *testing.T) {
+		params := prepare(t, StructuredOutputJSONMode, nil)
+		assert.Zero(t, params.ResponseFormat)
+		assert.Equal(t, "sys", params.Messages[0].OfSystem.Content.OfString.Value)
+	})
+}
+
+func TestStructuredOutputFallbackRetries(t *testing.T) {
+	getResponse := func(t *testing.T, contents ...string) (*ModelResponse, []map[string]any) {
+		t.Helper()
+		client, requests := makeOpenaiClientWithContents(t, contents...)
+		model := NewOpenAIChatCompletionsModel("local-model", client)
+		model.StructuredOutputMode = StructuredOutputJSONMode
+
+		resp, err := model.GetResponse(t.Context(), ModelResponseParams{
+			Input:      InputString("hi"),
+			OutputType: OutputType[structuredOutputTestFoo](),
+			Tracing:    ModelTracingDisabled,
+		})
+		require.NoError(t, err)
+		return resp, *requests
+	}
+
+	t.Run("repaired output", func(t *testing.T) {
+		resp, requests := getResponse(t, "```json\n{\"bar\": \"baz\", \"count\": \"3\"}\n```")
+		assert.Len(t, requests, 1)
+		require.Len(t, resp.Output, 1)
+		assert.JSONEq(t, `{"bar": "baz", "count": 3}`, resp.Output[0].Content[0].Text)
+		assert.Equal(t, uint64(1), resp.Usage.Requests)
+	})
+
+	t.Run("retried output", func(t *testing.T) {
+		resp, requests := getResponse(t, `{"bar": "baz"}`, `{"bar": "baz", "count": 2}`)
+		require.Len(t, requests, 2)
+		messages := requests[1]["messages"].([]any)
+		require.Len(t, messages, 4)
+		assert.Equal(t, "assistant", messages[2].(map[string]any)["role"])
+		assert.Equal(t, "user", messages[3].(map[string]any)["role"])
+		assert.Contains(t, messages[3].(map[string]any)["content"], "does not conform to the JSON schema")
+
+		assert.JSONEq(t, `{"bar": "baz", "count": 2}`, resp.Output[0].Content[0].Text)
+		assert.Equal(t, uint64(2), resp.Usage.Requests)
+		assert.Equal(t, uint64(14), resp.Usage.InputTokens)
+		assert.Equal(t, uint64(24), resp.Usage.TotalTokens)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		resp, requests := getResponse(t, `nope`, `still nope`, `{"bar": 1}`)
+		assert.Len(t, requests, 1+StructuredOutputFallbackRetries)
+		assert.Equal(t, `{"bar": 1}`, resp.Output[0].Content[0].Text)
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/openai/openai-go/v3/option"
+)
+
+// ModelHTTPHooks receives the HTTP requests sent by the OpenAI models to
+// their provider, and the raw responses, for debugging provider quirks
+// without enabling the global HTTP debug logging.
+//
+// Credential headers (Authorization, API keys and cookies) are always
+// redacted before being given to the hooks.
+type ModelHTTPHooks struct {
+	// OnRequest is called just before a request is sent, once per attempt.
+	OnRequest func(ctx context.Context, request ModelHTTPRequest)
+
+	// OnResponse is called when the response of a request is received, or
+	// when the request failed.
+	OnResponse func(ctx context.Context, response ModelHTTPResponse)
+
+	// Optional function redacting the request and response bodies before
+	// they are given to the hooks, e.g. to remove user data.
+	RedactBody func(body []byte) []byte
+}
+
+// ModelHTTPRequest is an HTTP request sent by a model to its provider.
+type ModelHTTPRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// ModelHTTPResponse is the raw HTTP response to a ModelHTTPRequest.
+type ModelHTTPResponse struct {
+	Request    ModelHTTPRequest
+	StatusCode int
+	Header     http.Header
+	// The body of the response. It is nil for streamed responses, which are
+	// consumed by the model as they are received.
+	Body []byte
+	// Time elapsed between sending the request and receiving the response.
+	Latency time.Duration
+	// Error of the failed requests, in which case there is no response.
+	Err error
+}
+
+const redactedHeaderValue = "[REDACTED]"
+
+var redactedModelHTTPHeaders = []string{
+	"Authorization",
+	"Api-Key",
+	"X-Api-Key",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// RequestOption returns the request option installing the hooks on a
+// client or a single request.
+func (h ModelHTTPHooks) RequestOption() option.RequestOption {
+	return option.WithMiddleware(h.middleware)
+}
+
+func (h ModelHTTPHooks) middleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	ctx := req.Context()
+
+	request := ModelHTTPRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: redactModelHTTPHeader(req.Header),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		request.Body = h.redactBody(body)
+	}
+	if h.OnRequest != nil {
+		h.OnRequest(ctx, request)
+	}
+
+	start := time.Now()
+	resp, err := next(req)
+	response := ModelHTTPResponse{
+		Request: request,
+		Latency: time.Since(start),
+		Err:     err,
+	}
+	if err == nil {
+		response.StatusCode = resp.StatusCode
+		response.Header = redactModelHTTPHeader(resp.Header)
+		if resp.Body != nil && !isEventStream(resp.Header) {
+			body, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			response.Body = h.redactBody(body)
+			if readErr != nil {
+				response.Err = readErr
+				resp, err = nil, readErr
+			}
+		}
+	}
+	if h.OnResponse != nil {
+		h.OnResponse(ctx, response)
+	}
+	return resp, err
+}
+
+func (h ModelHTTPHooks) redactBody(body []byte) []byte {
+	if h.RedactBody == nil {
+		return body
+	}
+	return h.RedactBody(body)
+}
+
+func redactModelHTTPHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedModelHTTPHeaders {
+		if _, ok := header[name]; ok {
+			header.Set(name, redactedHeaderValue)
+		}
+	}
+	return header
+}
+
+func isEventStream(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
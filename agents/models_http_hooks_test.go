@@ -0,0 +1,122 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestModelHTTPHooksReceiveRequestAndResponse(t *testing.T) {
+	const responseBody = `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4",` +
+		`"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"hello"}}]}`
+
+	var (
+		requests  []agents.ModelHTTPRequest
+		responses []agents.ModelHTTPResponse
+	)
+	hooks := &agents.ModelHTTPHooks{
+		OnRequest: func(_ context.Context, request agents.ModelHTTPRequest) {
+			requests = append(requests, request)
+		},
+		OnResponse: func(_ context.Context, response agents.ModelHTTPResponse) {
+			responses = append(responses, response)
+		},
+		RedactBody: func(body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("secret"), []byte("***"))
+		},
+	}
+
+	client := agents.NewOpenaiClient(
+		param.Opt[string]{},
+		param.NewOpt("sk-test"),
+		option.WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				// The provider must still receive the unredacted body.
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Contains(t, string(body), "my secret")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}, "Set-Cookie": {"session=1"}},
+					Body:       io.NopCloser(strings.NewReader(responseBody)),
+				}, nil
+			}),
+		}),
+	)
+
+	model := agents.NewOpenAIChatCompletionsModel("gpt-4", client)
+	model.HTTPHooks = hooks
+	resp, err := model.GetResponse(t.Context(), agents.ModelResponseParams{
+		Input:   agents.InputString("my secret"),
+		Tracing: agents.ModelTracingDisabled,
+	})
+	require.NoError(t, err)
+	text, ok := agents.ItemHelpers().ExtractLastText(resp.Output[0])
+	require.True(t, ok)
+	assert.Equal(t, "hello", text)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.True(t, strings.HasSuffix(requests[0].URL, "/chat/completions"))
+	assert.Equal(t, "[REDACTED]", requests[0].Header.Get("Authorization"))
+	assert.Contains(t, string(requests[0].Body), "my ***")
+
+	require.Len(t, responses, 1)
+	assert.NoError(t, responses[0].Err)
+	assert.Equal(t, http.StatusOK, responses[0].StatusCode)
+	assert.Equal(t, "[REDACTED]", responses[0].Header.Get("Set-Cookie"))
+	assert.Equal(t, responseBody, string(responses[0].Body))
+	assert.Equal(t, requests[0], responses[0].Request)
+}
+
+func TestModelHTTPHooksSetByOpenAIProvider(t *testing.T) {
+	hooks := &agents.ModelHTTPHooks{}
+	client := agents.OpenaiClient{Client: openai.NewClient()}
+
+	for _, useResponses := range []bool{true, false} {
+		provider := agents.NewOpenAIProvider(agents.OpenAIProviderParams{
+			OpenaiClient: &client,
+			UseResponses: param.NewOpt(useResponses),
+			HTTPHooks:    hooks,
+		})
+		model, err := provider.GetModel("gpt-4")
+		require.NoError(t, err)
+
+		switch m := model.(type) {
+		case agents.OpenAIResponsesModel:
+			assert.Same(t, hooks, m.HTTPHooks)
+		case agents.OpenAIChatCompletionsModel:
+			assert.Same(t, hooks, m.HTTPHooks)
+		default:
+			t.Fatalf("unexpected model type %T", model)
+		}
+	}
+}
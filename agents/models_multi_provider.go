@@ -59,6 +59,14 @@ type NewMultiProviderParams struct {
 
 	// Whether to use the OpenAI responses API.
 	OpenaiUseResponses param.Opt[bool]
+
+	// Optional function returning how the OpenAI Chat Completions models are
+	// asked for structured outputs. See OpenAIProviderParams.
+	OpenaiStructuredOutputMode func(modelName string) StructuredOutputMode
+
+	// Optional hooks receiving the HTTP requests and responses of the OpenAI
+	// models. See OpenAIProviderParams.
+	OpenaiHTTPHooks *ModelHTTPHooks
 }
 
 // NewMultiProvider creates a new OpenAI provider.
@@ -72,6 +80,9 @@ func NewMultiProvider(params NewMultiProviderParams) *MultiProvider {
 			Organization: params.OpenaiOrganization,
 			Project:      params.OpenaiProject,
 			UseResponses: params.OpenaiUseResponses,
+
+			StructuredOutputMode: params.OpenaiStructuredOutputMode,
+			HTTPHooks:            params.OpenaiHTTPHooks,
 		}),
 		fallbackProviders: make(map[string]ModelProvider),
 	}
@@ -35,8 +35,14 @@ import (
 )
 
 type OpenAIChatCompletionsModel struct {
-	Model  openai.ChatModel
-	client OpenaiClient
+	Model openai.ChatModel
+	// StructuredOutputMode is how the model is asked for structured outputs,
+	// StructuredOutputJSONSchema by default. Set a fallback mode for the
+	// models of OpenAI-compatible providers without structured outputs.
+	StructuredOutputMode StructuredOutputMode
+	// Optional hooks receiving the HTTP requests and responses of the model.
+	HTTPHooks *ModelHTTPHooks
+	client    OpenaiClient
 }
 
 func NewOpenAIChatCompletionsModel(model openai.ChatModel, client OpenaiClient) OpenAIChatCompletionsModel {
@@ -81,6 +87,14 @@ func (m OpenAIChatCompletionsModel) GetResponse(
 				return err
 			}
 
+			requests := 1
+			if m.usesStructuredOutputFallback(params.OutputType) {
+				response, requests, err = m.validateFallbackOutput(ctx, *body, opts, params.OutputType, response)
+				if err != nil {
+					return err
+				}
+			}
+
 			var message *openai.ChatCompletionMessage
 			var firstChoice *openai.ChatCompletionChoice
 
@@ -91,21 +105,21 @@ func (m OpenAIChatCompletionsModel) GetResponse(
 
 			switch {
 			case DontLogModelData:
-				Logger().Debug("LLM responded")
+				Logger().DebugContext(ctx, "LLM responded")
 			case message != nil:
-				Logger().Debug("LLM responded", slog.String("message", SimplePrettyJSONMarshal(*message)))
+				Logger().DebugContext(ctx, "LLM responded", slog.String("message", SimplePrettyJSONMarshal(*message)))
 			default:
 				finishReason := "-"
 				if firstChoice != nil {
 					finishReason = firstChoice.FinishReason
 				}
-				Logger().Debug("LLM response", slog.String("finish_reason", finishReason))
+				Logger().DebugContext(ctx, "LLM response", slog.String("finish_reason", finishReason))
 			}
 
 			u := usage.NewUsage()
 			if !reflect.ValueOf(response.Usage).IsZero() {
 				*u = usage.Usage{
-					Requests:    1,
+					Requests:    uint64(requests),
 					InputTokens: uint64(response.Usage.PromptTokens),
 					InputTokensDetails: responses.ResponseUsageInputTokensDetails{
 						CachedTokens: response.Usage.PromptTokensDetails.CachedTokens,
@@ -129,10 +143,7 @@ func (m OpenAIChatCompletionsModel) GetResponse(
 				}
 				spanGeneration.SpanData().(*tracing.GenerationSpanData).Output = output
 			}
-			spanGeneration.SpanData().(*tracing.GenerationSpanData).Usage = map[string]any{
-				"input_tokens":  u.InputTokens,
-				"output_tokens": u.OutputTokens,
-			}
+			spanGeneration.SpanData().(*tracing.GenerationSpanData).Usage = generationSpanUsage(m.Model, u)
 
 			var items []TResponseOutputItem
 			if message != nil {
@@ -140,6 +151,7 @@ func (m OpenAIChatCompletionsModel) GetResponse(
 				if err != nil {
 					return err
 				}
+				ChatCmplConverter().AttachLogprobs(items, firstChoice.Logprobs.Content)
 			}
 			modelResponse = &ModelResponse{
 				Output:     items,
@@ -209,6 +221,9 @@ func (m OpenAIChatCompletionsModel) StreamResponse(
 			var finalResponse *responses.Response
 			err = ChatCmplStreamHandler().HandleStream(response, stream, func(chunk TResponseStreamEvent) error {
 				if chunk.Type == "response.completed" {
+					if m.usesStructuredOutputFallback(params.OutputType) {
+						repairFallbackStreamOutput(ctx, params.OutputType, &chunk.Response)
+					}
 					finalResponse = &chunk.Response
 				}
 				return yield(ctx, chunk)
@@ -229,16 +244,36 @@ func (m OpenAIChatCompletionsModel) StreamResponse(
 				}
 
 				if u := finalResponse.Usage; !reflect.ValueOf(u).IsZero() {
-					spanData.Usage = map[string]any{
-						"input_tokens":  u.InputTokens,
-						"output_tokens": u.OutputTokens,
-					}
+					spanData.Usage = generationSpanUsage(m.Model, &usage.Usage{
+						Requests:            1,
+						InputTokens:         uint64(u.InputTokens),
+						InputTokensDetails:  u.InputTokensDetails,
+						OutputTokens:        uint64(u.OutputTokens),
+						OutputTokensDetails: u.OutputTokensDetails,
+						TotalTokens:         uint64(u.TotalTokens),
+					})
 				}
 			}
 			return nil
 		})
 }
 
+// generationSpanUsage returns the usage recorded on generation spans, including
+// the estimated cost in USD when the model pricing is known.
+func generationSpanUsage(model string, u *usage.Usage) map[string]any {
+	data := map[string]any{
+		"input_tokens":        u.InputTokens,
+		"output_tokens":       u.OutputTokens,
+		"cached_input_tokens": u.InputTokensDetails.CachedTokens,
+		"reasoning_tokens":    u.OutputTokensDetails.ReasoningTokens,
+		"total_tokens":        u.TotalTokens,
+	}
+	if cost, ok := usage.EstimateCost(model, u); ok {
+		data["estimated_cost_usd"] = cost
+	}
+	return data
+}
+
 func (m OpenAIChatCompletionsModel) generationSpanParams(params ModelResponseParams) (*tracing.GenerationSpanParams, error) {
 	modelConfig, err := util.JSONMap(params.ModelSettings)
 	if err != nil {
@@ -271,6 +306,17 @@ func (m OpenAIChatCompletionsModel) prepareRequest(
 		return nil, nil, err
 	}
 
+	if m.usesStructuredOutputFallback(outputType) {
+		instructions, err := structuredOutputInstructions(outputType)
+		if err != nil {
+			return nil, nil, err
+		}
+		if systemInstructions.Valid() {
+			instructions = systemInstructions.Value + "\n\n" + instructions
+		}
+		systemInstructions = param.NewOpt(instructions)
+	}
+
 	if systemInstructions.Valid() {
 		convertedMessages = slices.Insert(convertedMessages, 0, openai.ChatCompletionMessageParamUnion{
 			OfSystem: &openai.ChatCompletionSystemMessageParam{
@@ -303,8 +349,10 @@ func (m OpenAIChatCompletionsModel) prepareRequest(
 	if err != nil {
 		return nil, nil, err
 	}
-	responseFormat, _, err := ChatCmplConverter().ConvertResponseFormat(outputType)
-	if err != nil {
+	var responseFormat openai.ChatCompletionNewParamsResponseFormatUnion
+	if m.usesStructuredOutputFallback(outputType) {
+		responseFormat = m.structuredOutputFallbackFormat()
+	} else if responseFormat, _, err = ChatCmplConverter().ConvertResponseFormat(outputType); err != nil {
 		return nil, nil, err
 	}
 
@@ -322,9 +370,10 @@ func (m OpenAIChatCompletionsModel) prepareRequest(
 	}
 
 	if DontLogModelData {
-		Logger().Debug("Calling LLM")
+		Logger().DebugContext(ctx, "Calling LLM")
 	} else {
-		Logger().Debug(
+		Logger().DebugContext(
+			ctx,
 			"Calling LLM",
 			slog.String("Messages", SimplePrettyJSONMarshal(convertedMessages)),
 			slog.String("Tools", SimplePrettyJSONMarshal(convertedTools)),
@@ -338,24 +387,31 @@ func (m OpenAIChatCompletionsModel) prepareRequest(
 
 	streamOptions := ChatCmplHelpers().GetStreamOptionsParam(m.client, modelSettings, stream)
 
+	logprobs := ChatCmplHelpers().GetLogprobsParam(modelSettings)
+
+	maxTokens, maxCompletionTokens := ChatCmplHelpers().GetMaxTokensParams(m.Model, modelSettings)
+
 	params := &openai.ChatCompletionNewParams{
-		Model:             m.Model,
-		Messages:          convertedMessages,
-		Tools:             convertedTools,
-		Temperature:       modelSettings.Temperature,
-		TopP:              modelSettings.TopP,
-		FrequencyPenalty:  modelSettings.FrequencyPenalty,
-		PresencePenalty:   modelSettings.PresencePenalty,
-		MaxTokens:         modelSettings.MaxTokens,
-		ToolChoice:        toolChoice,
-		ResponseFormat:    responseFormat,
-		ParallelToolCalls: parallelToolCalls,
-		StreamOptions:     streamOptions,
-		Store:             store,
-		ReasoningEffort:   modelSettings.Reasoning.Effort,
-		Verbosity:         openai.ChatCompletionNewParamsVerbosity(modelSettings.Verbosity.Or("")),
-		TopLogprobs:       modelSettings.TopLogprobs,
-		Metadata:          modelSettings.Metadata,
+		Model:               m.Model,
+		Messages:            convertedMessages,
+		Tools:               convertedTools,
+		Temperature:         modelSettings.Temperature,
+		TopP:                modelSettings.TopP,
+		FrequencyPenalty:    modelSettings.FrequencyPenalty,
+		PresencePenalty:     modelSettings.PresencePenalty,
+		MaxTokens:           maxTokens,
+		MaxCompletionTokens: maxCompletionTokens,
+		ToolChoice:          toolChoice,
+		ResponseFormat:      responseFormat,
+		ParallelToolCalls:   parallelToolCalls,
+		StreamOptions:       streamOptions,
+		Store:               store,
+		ReasoningEffort:     modelSettings.Reasoning.Effort,
+		Verbosity:           openai.ChatCompletionNewParamsVerbosity(modelSettings.Verbosity.Or("")),
+		ServiceTier:         openai.ChatCompletionNewParamsServiceTier(modelSettings.ServiceTier.Or("")),
+		Logprobs:            logprobs,
+		TopLogprobs:         modelSettings.TopLogprobs,
+		Metadata:            modelSettings.Metadata,
 	}
 
 	var opts []option.RequestOption
@@ -365,6 +421,12 @@ func (m OpenAIChatCompletionsModel) prepareRequest(
 	for k, v := range modelSettings.ExtraQuery {
 		opts = append(opts, option.WithQuery(k, v))
 	}
+	for k, v := range modelSettings.ExtraBody {
+		opts = append(opts, option.WithJSONSet(k, v))
+	}
+	if m.HTTPHooks != nil {
+		opts = append(opts, m.HTTPHooks.RequestOption())
+	}
 
 	if modelSettings.CustomizeChatCompletionsRequest != nil {
 		return modelSettings.CustomizeChatCompletionsRequest(ctx, params, opts)
@@ -72,6 +72,106 @@ func TestOpenAIChatCompletionsModel_prepareRequest(t *testing.T) {
 		assert.Nil(t, opts)
 	})
 
+	t.Run("with ModelSettings.ServiceTier", func(t *testing.T) {
+		m := NewOpenAIChatCompletionsModel("model-name", NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+
+		var params *openai.ChatCompletionNewParams
+		err := tracing.GenerationSpan(
+			t.Context(), tracing.GenerationSpanParams{Disabled: true},
+			func(ctx context.Context, span tracing.Span) (err error) {
+				params, _, err = m.prepareRequest(
+					t.Context(),
+					param.Opt[string]{},
+					InputString("input"),
+					modelsettings.ModelSettings{
+						ServiceTier: param.NewOpt(modelsettings.ServiceTierFlex),
+					},
+					nil,
+					nil,
+					nil,
+					span,
+					ModelTracingDisabled,
+					false,
+				)
+				return err
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, openai.ChatCompletionNewParamsServiceTierFlex, params.ServiceTier)
+	})
+
+	t.Run("with ModelSettings.TopLogprobs", func(t *testing.T) {
+		m := NewOpenAIChatCompletionsModel("model-name", NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+
+		var params *openai.ChatCompletionNewParams
+		err := tracing.GenerationSpan(
+			t.Context(), tracing.GenerationSpanParams{Disabled: true},
+			func(ctx context.Context, span tracing.Span) (err error) {
+				params, _, err = m.prepareRequest(
+					t.Context(),
+					param.Opt[string]{},
+					InputString("input"),
+					modelsettings.ModelSettings{
+						TopLogprobs: param.NewOpt(int64(3)),
+					},
+					nil,
+					nil,
+					nil,
+					span,
+					ModelTracingDisabled,
+					false,
+				)
+				return err
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, param.NewOpt(true), params.Logprobs)
+		assert.Equal(t, param.NewOpt(int64(3)), params.TopLogprobs)
+	})
+
+	t.Run("with ModelSettings.MaxTokens", func(t *testing.T) {
+		testCases := []struct {
+			model                   string
+			settings                modelsettings.ModelSettings
+			wantMaxTokens           param.Opt[int64]
+			wantMaxCompletionTokens param.Opt[int64]
+		}{
+			{"gpt-4.1", modelsettings.ModelSettings{MaxTokens: param.NewOpt(int64(100))}, param.NewOpt(int64(100)), param.Opt[int64]{}},
+			{"o3-mini", modelsettings.ModelSettings{MaxTokens: param.NewOpt(int64(100))}, param.Opt[int64]{}, param.NewOpt(int64(100))},
+			{"gpt-5", modelsettings.ModelSettings{MaxTokens: param.NewOpt(int64(100))}, param.Opt[int64]{}, param.NewOpt(int64(100))},
+			{"gpt-4.1", modelsettings.ModelSettings{
+				MaxTokens:           param.NewOpt(int64(100)),
+				MaxCompletionTokens: param.NewOpt(int64(200)),
+			}, param.Opt[int64]{}, param.NewOpt(int64(200))},
+		}
+		for _, tc := range testCases {
+			m := NewOpenAIChatCompletionsModel(tc.model, NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+
+			var params *openai.ChatCompletionNewParams
+			err := tracing.GenerationSpan(
+				t.Context(), tracing.GenerationSpanParams{Disabled: true},
+				func(ctx context.Context, span tracing.Span) (err error) {
+					params, _, err = m.prepareRequest(
+						t.Context(),
+						param.Opt[string]{},
+						InputString("input"),
+						tc.settings,
+						nil,
+						nil,
+						nil,
+						span,
+						ModelTracingDisabled,
+						false,
+					)
+					return err
+				},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMaxTokens, params.MaxTokens, tc.model)
+			assert.Equal(t, tc.wantMaxCompletionTokens, params.MaxCompletionTokens, tc.model)
+		}
+	})
+
 	t.Run("with ModelSettings.CustomizeChatCompletionsRequest returning values", func(t *testing.T) {
 		customParams := &openai.ChatCompletionNewParams{
 			Model: "foo",
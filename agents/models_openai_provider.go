@@ -44,6 +44,17 @@ type OpenAIProviderParams struct {
 
 	// Whether to use the OpenAI responses API.
 	UseResponses param.Opt[bool]
+
+	// Optional function returning how the Chat Completions models are asked
+	// for structured outputs, by model name. By default, all the models are
+	// assumed to support structured outputs. Return a fallback mode for the
+	// models of OpenAI-compatible providers without that support. It is not
+	// used for the Responses API.
+	StructuredOutputMode func(modelName string) StructuredOutputMode
+
+	// Optional hooks receiving the HTTP requests and responses of all the
+	// models of the provider.
+	HTTPHooks *ModelHTTPHooks
 }
 
 type OpenAIProvider struct {
@@ -80,9 +91,16 @@ func (provider *OpenAIProvider) GetModel(modelName string) (Model, error) {
 	client := provider.getClient()
 
 	if provider.useResponses {
-		return NewOpenAIResponsesModel(modelName, client), nil
+		model := NewOpenAIResponsesModel(modelName, client)
+		model.HTTPHooks = provider.params.HTTPHooks
+		return model, nil
+	}
+	model := NewOpenAIChatCompletionsModel(modelName, client)
+	model.HTTPHooks = provider.params.HTTPHooks
+	if provider.params.StructuredOutputMode != nil {
+		model.StructuredOutputMode = provider.params.StructuredOutputMode(modelName)
 	}
-	return NewOpenAIChatCompletionsModel(modelName, client), nil
+	return model, nil
 }
 
 // We lazy load the client in case you never actually use OpenAIProvider.
@@ -34,8 +34,10 @@ import (
 
 // OpenAIResponsesModel is an implementation of Model that uses the OpenAI Responses API.
 type OpenAIResponsesModel struct {
-	Model  openai.ChatModel
-	client OpenaiClient
+	Model openai.ChatModel
+	// Optional hooks receiving the HTTP requests and responses of the model.
+	HTTPHooks *ModelHTTPHooks
+	client    OpenaiClient
 }
 
 func NewOpenAIResponsesModel(model openai.ChatModel, client OpenaiClient) OpenAIResponsesModel {
@@ -88,14 +90,14 @@ func (m OpenAIResponsesModel) GetResponse(
 
 			response, err = m.client.Responses.New(ctx, *body, opts...)
 			if err != nil {
-				Logger().Error("error getting response", slog.String("error", err.Error()))
+				Logger().ErrorContext(ctx, "error getting response", slog.String("error", err.Error()))
 				return err
 			}
 
 			if DontLogModelData {
-				Logger().Debug("LLM responded")
+				Logger().DebugContext(ctx, "LLM responded")
 			} else {
-				Logger().Debug("LLM responded", slog.String("output", SimplePrettyJSONMarshal(response.Output)))
+				Logger().DebugContext(ctx, "LLM responded", slog.String("output", SimplePrettyJSONMarshal(response.Output)))
 			}
 
 			u = usage.NewUsage()
@@ -151,7 +153,7 @@ func (m OpenAIResponsesModel) StreamResponse(
 						Message: "Error streaming response",
 						Data:    map[string]any{"error": v},
 					})
-					Logger().Error("error streaming response", slog.String("error", err.Error()))
+					Logger().ErrorContext(ctx, "error streaming response", slog.String("error", err.Error()))
 				}
 			}()
 
@@ -235,9 +237,14 @@ func (m OpenAIResponsesModel) prepareRequest(
 		return nil, nil, err
 	}
 
+	maxOutputTokens := modelSettings.MaxTokens
+	if modelSettings.MaxCompletionTokens.Valid() {
+		maxOutputTokens = modelSettings.MaxCompletionTokens
+	}
+
 	include := slices.Concat(convertedTools.Includes, modelSettings.ResponseInclude)
-	if modelSettings.TopLogprobs.Valid() {
-		include = append(include, "message.output_text.logprobs")
+	if modelSettings.TopLogprobs.Valid() || modelSettings.Logprobs.Value {
+		include = append(include, responses.ResponseIncludableMessageOutputTextLogprobs)
 	}
 
 	// Remove duplicates
@@ -249,9 +256,10 @@ func (m OpenAIResponsesModel) prepareRequest(
 	}
 
 	if DontLogModelData {
-		Logger().Debug("Calling LLM")
+		Logger().DebugContext(ctx, "Calling LLM")
 	} else {
-		Logger().Debug(
+		Logger().DebugContext(
+			ctx,
 			"Calling LLM",
 			slog.String("Input", SimplePrettyJSONMarshal(listInput)),
 			slog.String("Tools", SimplePrettyJSONMarshal(convertedTools.Tools)),
@@ -278,7 +286,8 @@ func (m OpenAIResponsesModel) prepareRequest(
 		Temperature:        modelSettings.Temperature,
 		TopP:               modelSettings.TopP,
 		Truncation:         responses.ResponseNewParamsTruncation(modelSettings.Truncation.Or("")),
-		MaxOutputTokens:    modelSettings.MaxTokens,
+		ServiceTier:        responses.ResponseNewParamsServiceTier(modelSettings.ServiceTier.Or("")),
+		MaxOutputTokens:    maxOutputTokens,
 		ToolChoice:         toolChoice,
 		ParallelToolCalls:  parallelToolCalls,
 		Text:               responseFormat,
@@ -295,6 +304,12 @@ func (m OpenAIResponsesModel) prepareRequest(
 	for k, v := range modelSettings.ExtraQuery {
 		opts = append(opts, option.WithQuery(k, v))
 	}
+	for k, v := range modelSettings.ExtraBody {
+		opts = append(opts, option.WithJSONSet(k, v))
+	}
+	if m.HTTPHooks != nil {
+		opts = append(opts, m.HTTPHooks.RequestOption())
+	}
 
 	if modelSettings.CustomizeResponsesRequest != nil {
 		return modelSettings.CustomizeResponsesRequest(ctx, params, opts)
@@ -461,12 +476,12 @@ func (conv responsesConverter) convertTool(
 			*includes = responses.ResponseIncludableFileSearchCallResults
 		}
 	case ComputerTool:
-		environment, err := t.Computer.Environment(ctx)
+		environment, err := t.environment(ctx)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		dimensions, err := t.Computer.Dimensions(ctx)
+		dimensions, err := t.dimensions(ctx)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -62,6 +62,67 @@ func TestOpenAIResponsesModel_prepareRequest(t *testing.T) {
 		assert.Nil(t, opts)
 	})
 
+	t.Run("with ModelSettings.ServiceTier", func(t *testing.T) {
+		m := NewOpenAIResponsesModel("model-name", NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+		params, _, err := m.prepareRequest(
+			t.Context(),
+			param.Opt[string]{},
+			InputString("input"),
+			modelsettings.ModelSettings{
+				ServiceTier: param.NewOpt(modelsettings.ServiceTierPriority),
+			},
+			nil,
+			nil,
+			nil,
+			"",
+			false,
+			responses.ResponsePromptParam{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, responses.ResponseNewParamsServiceTierPriority, params.ServiceTier)
+	})
+
+	t.Run("with ModelSettings.Truncation", func(t *testing.T) {
+		m := NewOpenAIResponsesModel("model-name", NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+		params, _, err := m.prepareRequest(
+			t.Context(),
+			param.Opt[string]{},
+			InputString("input"),
+			modelsettings.ModelSettings{
+				Truncation: param.NewOpt(modelsettings.TruncationAuto),
+			},
+			nil,
+			nil,
+			nil,
+			"",
+			false,
+			responses.ResponsePromptParam{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, responses.ResponseNewParamsTruncationAuto, params.Truncation)
+	})
+
+	t.Run("with ModelSettings.Logprobs", func(t *testing.T) {
+		m := NewOpenAIResponsesModel("model-name", NewOpenaiClient(param.Opt[string]{}, param.Opt[string]{}))
+		params, _, err := m.prepareRequest(
+			t.Context(),
+			param.Opt[string]{},
+			InputString("input"),
+			modelsettings.ModelSettings{
+				Logprobs: param.NewOpt(true),
+			},
+			nil,
+			nil,
+			nil,
+			"",
+			false,
+			responses.ResponsePromptParam{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []responses.ResponseIncludable{responses.ResponseIncludableMessageOutputTextLogprobs}, params.Include)
+		assert.False(t, params.TopLogprobs.Valid())
+	})
+
 	t.Run("with ModelSettings.CustomizeResponsesRequest returning values", func(t *testing.T) {
 		customParams := &responses.ResponseNewParams{
 			Model: "foo",
@@ -74,6 +74,16 @@ func GetDefaultOpenaiClient() *OpenaiClient {
 	return defaultOpenaiClient.Load()
 }
 
+// resolveDefaultOpenaiClient returns the default OpenAI client, or a client
+// with the default key.
+func resolveDefaultOpenaiClient() *OpenaiClient {
+	if client := GetDefaultOpenaiClient(); client != nil {
+		return client
+	}
+	client := NewOpenaiClient(param.Opt[string]{}, GetDefaultOpenaiKey())
+	return &client
+}
+
 func SetUseResponsesByDefault(useResponses bool) {
 	useResponsesByDefault.Store(useResponses)
 }
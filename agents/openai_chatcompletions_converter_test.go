@@ -55,6 +55,47 @@ func TestMessageToOutputItemsWithTextOnly(t *testing.T) {
 	}, items)
 }
 
+func TestAttachLogprobsSetsOutputTextLogprobs(t *testing.T) {
+	// Chat Completions logprobs of the first choice must end up on the
+	// output_text content, converted to their Responses API shape.
+
+	msg := openai.ChatCompletionMessage{
+		Content: "Hi",
+		Refusal: "no",
+		Role:    constant.ValueOf[constant.Assistant](),
+	}
+
+	items, err := agents.ChatCmplConverter().MessageToOutputItems(msg)
+	require.NoError(t, err)
+
+	agents.ChatCmplConverter().AttachLogprobs(items, []openai.ChatCompletionTokenLogprob{
+		{
+			Token:   "Hi",
+			Bytes:   []int64{72, 105},
+			Logprob: -0.25,
+			TopLogprobs: []openai.ChatCompletionTokenLogprobTopLogprob{
+				{Token: "Hi", Bytes: []int64{72, 105}, Logprob: -0.25},
+				{Token: "Hey", Bytes: []int64{72, 101, 121}, Logprob: -1.5},
+			},
+		},
+	})
+
+	require.Len(t, items, 1)
+	require.Len(t, items[0].Content, 2)
+	assert.Equal(t, []responses.ResponseOutputTextLogprob{
+		{
+			Token:   "Hi",
+			Bytes:   []int64{72, 105},
+			Logprob: -0.25,
+			TopLogprobs: []responses.ResponseOutputTextLogprobTopLogprob{
+				{Token: "Hi", Bytes: []int64{72, 105}, Logprob: -0.25},
+				{Token: "Hey", Bytes: []int64{72, 101, 121}, Logprob: -1.5},
+			},
+		},
+	}, items[0].Content[0].Logprobs)
+	assert.Nil(t, items[0].Content[1].Logprobs)
+}
+
 func TestMessageToOutputItemsWithRefusal(t *testing.T) {
 	// Make sure a message with a refusal string produces a ResponseOutputMessage
 	// with a ResponseOutputRefusal content part.
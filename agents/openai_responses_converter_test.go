@@ -260,6 +260,26 @@ func TestConvertToolsBasicTypesAndIncludes(t *testing.T) {
 	})
 }
 
+func TestConvertComputerToolOverrides(t *testing.T) {
+	compTool := agents.ComputerTool{
+		Computer:    DummyComputer{},
+		Environment: computer.EnvironmentBrowser,
+		Dimensions:  computer.Dimensions{Width: 1280, Height: 720},
+	}
+	converted, err := agents.ResponsesConverter().ConvertTools(t.Context(), []agents.Tool{compTool}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []responses.ToolUnionParam{
+		{
+			OfComputerUsePreview: &responses.ComputerToolParam{
+				DisplayHeight: 720,
+				DisplayWidth:  1280,
+				Environment:   responses.ComputerToolEnvironmentBrowser,
+				Type:          constant.ValueOf[constant.ComputerUsePreview](),
+			},
+		},
+	}, converted.Tools)
+}
+
 func TestConvertToolsIncludesHandoffs(t *testing.T) {
 	//  When handoff objects are included, `ConvertTools` should append their
 	//  tool param items after tools and include appropriate descriptions.
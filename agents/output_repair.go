@@ -0,0 +1,461 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONRepair describes a change made by RepairJSON.
+type JSONRepair struct {
+	// Path is the JSON pointer of the repaired value, empty for the whole
+	// document.
+	Path string `json:"path"`
+	// Description of the change.
+	Description string `json:"description"`
+}
+
+func (r JSONRepair) String() string {
+	if r.Path == "" {
+		return r.Description
+	}
+	return r.Path + ": " + r.Description
+}
+
+// RepairJSON makes a best-effort attempt to turn the text produced by a model
+// which ignored the JSON schema into a document conforming to it. It strips
+// code fences and surrounding prose, removes trailing commas, completes
+// truncated documents, converts the values of the wrong type when possible
+// (e.g. "42" for an integer), matches enum values regardless of case, fills
+// the missing properties having a default or accepting null, and drops the
+// properties not allowed by the schema.
+//
+// It returns the repaired document and the list of repairs, or the original
+// text and no repairs when nothing was changed. It returns an error if the
+// text cannot be parsed as JSON even after repairs. A nil schema only repairs
+// the syntax.
+func RepairJSON(text string, schema map[string]any) (string, []JSONRepair, error) {
+	r := jsonRepairer{root: schema}
+	value, err := r.parse(r.extract(text))
+	if err != nil {
+		return text, nil, err
+	}
+	if schema != nil {
+		value = r.coerce(value, schema, "")
+	}
+	if len(r.repairs) == 0 {
+		return text, nil, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return text, nil, fmt.Errorf("failed to JSON-marshal repaired output: %w", err)
+	}
+	return string(b), r.repairs, nil
+}
+
+type jsonRepairer struct {
+	root    map[string]any
+	repairs []JSONRepair
+}
+
+func (r *jsonRepairer) record(path, format string, args ...any) {
+	r.repairs = append(r.repairs, JSONRepair{Path: path, Description: fmt.Sprintf(format, args...)})
+}
+
+// extract returns the JSON document in the text, without code fences and
+// surrounding prose.
+func (r *jsonRepairer) extract(text string) string {
+	doc := strings.TrimSpace(text)
+	if rest, ok := strings.CutPrefix(doc, "```"); ok {
+		// Skip the language of the fence, e.g. ```json.
+		if i := strings.IndexByte(rest, '\n'); i >= 0 {
+			rest = rest[i+1:]
+		} else {
+			rest = ""
+		}
+		rest, _ = strings.CutSuffix(strings.TrimSpace(rest), "```")
+		doc = strings.TrimSpace(rest)
+		r.record("", "removed code fences")
+	}
+	if doc == "" || strings.IndexByte(`{["`, doc[0]) >= 0 || json.Valid([]byte(doc)) {
+		return doc
+	}
+	start := strings.IndexAny(doc, "{[")
+	if start < 0 {
+		return doc
+	}
+	end := strings.LastIndexAny(doc, "}]")
+	if end < start {
+		end = len(doc) - 1
+	}
+	r.record("", "removed text around the JSON document")
+	return doc[start : end+1]
+}
+
+func (r *jsonRepairer) parse(doc string) (any, error) {
+	value, err := decodeJSONNumbers(doc)
+	if err == nil {
+		return value, nil
+	}
+	if fixed := removeTrailingCommas(doc); fixed != doc {
+		if value, err := decodeJSONNumbers(fixed); err == nil {
+			r.record("", "removed trailing commas")
+			return value, nil
+		}
+		doc = fixed
+	}
+	if value, partialErr := ParsePartialJSON(doc); partialErr == nil && value != nil {
+		r.record("", "completed truncated JSON")
+		return value, nil
+	}
+	return nil, fmt.Errorf("failed to parse JSON output: %w", err)
+}
+
+func decodeJSONNumbers(doc string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid data after top-level value")
+	}
+	return value, nil
+}
+
+// removeTrailingCommas removes the commas before closing brackets, outside
+// of strings.
+func removeTrailingCommas(doc string) string {
+	var b bytes.Buffer
+	inString, escaped := false, false
+	for i := 0; i < len(doc); i++ {
+		c := doc[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			next := strings.TrimLeft(doc[i+1:], " \t\r\n")
+			if next == "" || next[0] == '}' || next[0] == ']' {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// resolve follows the $ref of the schema.
+func (r *jsonRepairer) resolve(schema map[string]any) map[string]any {
+	for range 32 {
+		ref, ok := schema["$ref"].(string)
+		if !ok || r.root == nil {
+			return schema
+		}
+		resolved, err := resolveJONSchemaRef(r.root, ref)
+		if err != nil {
+			return schema
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+func (r *jsonRepairer) coerce(value any, schema map[string]any, path string) any {
+	schema = r.resolve(schema)
+
+	if anyOf, ok := schema["anyOf"].([]any); ok && len(anyOf) > 0 {
+		return r.coerceAnyOf(value, anyOf, path)
+	}
+
+	types := schemaTypes(schema)
+	if value == nil {
+		if def, ok := schema["default"]; ok && def != nil && !slices.Contains(types, "null") {
+			r.record(path, "replaced null with the default value")
+			return def
+		}
+		return nil
+	}
+	if len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return jsonValueHasType(value, t) }) {
+		value = r.convert(value, types, path)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		r.coerceObject(v, schema, path)
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				v[i] = r.coerce(item, items, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case string:
+		if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, any(v)) {
+			for _, allowed := range enum {
+				if s, ok := allowed.(string); ok && strings.EqualFold(s, strings.TrimSpace(v)) {
+					r.record(path, "replaced %q with the enum value %q", v, s)
+					return s
+				}
+			}
+		}
+	}
+	return value
+}
+
+// coerceAnyOf coerces the value with the first variant of its type.
+func (r *jsonRepairer) coerceAnyOf(value any, anyOf []any, path string) any {
+	var fallback map[string]any
+	for _, v := range anyOf {
+		variant, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		variant = r.resolve(variant)
+		types := schemaTypes(variant)
+		if len(types) == 0 || slices.ContainsFunc(types, func(t string) bool { return jsonValueHasType(value, t) }) {
+			return r.coerce(value, variant, path)
+		}
+		if fallback == nil && !slices.Equal(types, []string{"null"}) {
+			fallback = variant
+		}
+	}
+	if fallback == nil {
+		return value
+	}
+	return r.coerce(value, fallback, path)
+}
+
+func (r *jsonRepairer) coerceObject(obj map[string]any, schema map[string]any, path string) {
+	properties, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]any)
+
+	for _, key := range slices.Sorted(maps.Keys(properties)) {
+		propSchema, _ := properties[key].(map[string]any)
+		propPath := path + "/" + jsonPointerEscaper.Replace(key)
+		if value, ok := obj[key]; ok {
+			obj[key] = r.coerce(value, propSchema, propPath)
+			continue
+		}
+		propSchema = r.resolve(propSchema)
+		if def, ok := propSchema["default"]; ok {
+			obj[key] = def
+			r.record(propPath, "filled missing property with the default value")
+		} else if slices.Contains(required, any(key)) && schemaAllowsNull(propSchema) {
+			obj[key] = nil
+			r.record(propPath, "filled missing property with null")
+		}
+	}
+
+	if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+		var unknown []string
+		for key := range obj {
+			if _, ok := properties[key]; !ok {
+				unknown = append(unknown, key)
+			}
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			delete(obj, key)
+			r.record(path+"/"+jsonPointerEscaper.Replace(key), "removed property not allowed by the schema")
+		}
+	}
+}
+
+// convert converts the value to the first of the types it can be converted
+// to, or returns it unchanged.
+func (r *jsonRepairer) convert(value any, types []string, path string) any {
+	from := jsonValueType(value)
+	for _, typ := range types {
+		var converted any
+		switch v := value.(type) {
+		case string:
+			s := strings.TrimSpace(v)
+			switch typ {
+			case "number", "integer":
+				if f, err := strconv.ParseFloat(s, 64); err == nil && (typ == "number" || f == math.Trunc(f)) {
+					converted = json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+				}
+			case "boolean":
+				if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+					converted = b
+				}
+			case "object", "array":
+				if parsed, err := decodeJSONNumbers(s); err == nil && jsonValueHasType(parsed, typ) {
+					converted = parsed
+				}
+			}
+		case json.Number:
+			switch typ {
+			case "string":
+				converted = v.String()
+			case "integer":
+				if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+					converted = json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+				}
+			}
+		case bool:
+			if typ == "string" {
+				converted = strconv.FormatBool(v)
+			}
+		}
+		if converted == nil && typ == "array" {
+			converted = []any{value}
+			r.record(path, "wrapped %s in an array", from)
+			return converted
+		}
+		if converted != nil {
+			r.record(path, "converted %s to %s", from, typ)
+			return converted
+		}
+	}
+	return value
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// schemaTypes returns the types allowed by the "type" of the schema.
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func schemaAllowsNull(schema map[string]any) bool {
+	if slices.Contains(schemaTypes(schema), "null") {
+		return true
+	}
+	anyOf, _ := schema["anyOf"].([]any)
+	return slices.ContainsFunc(anyOf, func(v any) bool {
+		variant, _ := v.(map[string]any)
+		return slices.Contains(schemaTypes(variant), "null")
+	})
+}
+
+func jsonValueHasType(value any, typ string) bool {
+	if typ == "integer" {
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return jsonValueType(value) == typ
+}
+
+// jsonValueType returns the JSON type of a value decoded with json.Number.
+func jsonValueType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// RepairOutputTypeOpts configures RepairOutputTypeWithOpts.
+type RepairOutputTypeOpts struct {
+	// OnRepair is called with the repairs made to the outputs which were
+	// accepted after repair.
+	OnRepair func(ctx context.Context, repairs []JSONRepair)
+}
+
+type repairOutputType struct {
+	OutputTypeInterface
+	opts RepairOutputTypeOpts
+}
+
+// RepairOutputType wraps an output type to repair the JSON produced by the
+// models which do not follow its schema, e.g. OpenAI-compatible providers
+// ignoring strict schemas. Outputs failing validation are repaired with
+// RepairJSON and validated again; the repairs are logged.
+func RepairOutputType(outputType OutputTypeInterface) OutputTypeInterface {
+	return RepairOutputTypeWithOpts(outputType, RepairOutputTypeOpts{})
+}
+
+// RepairOutputTypeWithOpts is like RepairOutputType, with custom options.
+func RepairOutputTypeWithOpts(outputType OutputTypeInterface, opts RepairOutputTypeOpts) OutputTypeInterface {
+	return repairOutputType{OutputTypeInterface: outputType, opts: opts}
+}
+
+func (t repairOutputType) ValidateJSON(ctx context.Context, jsonStr string) (any, error) {
+	output, err := t.OutputTypeInterface.ValidateJSON(ctx, jsonStr)
+	if err == nil || t.IsPlainText() {
+		return output, err
+	}
+	schema, schemaErr := t.JSONSchema()
+	if schemaErr != nil {
+		return nil, err
+	}
+	repaired, repairs, repairErr := RepairJSON(jsonStr, schema)
+	if repairErr != nil || len(repairs) == 0 {
+		return nil, err
+	}
+	output, err = t.OutputTypeInterface.ValidateJSON(ctx, repaired)
+	if err != nil {
+		return nil, fmt.Errorf("output still invalid after repair: %w", err)
+	}
+
+	descriptions := make([]string, len(repairs))
+	for i, repair := range repairs {
+		descriptions[i] = repair.String()
+	}
+	Logger().InfoContext(ctx, "Repaired invalid JSON output",
+		slog.String("outputType", t.Name()),
+		slog.Any("repairs", descriptions))
+	if t.opts.OnRepair != nil {
+		t.opts.OnRepair(ctx, repairs)
+	}
+	return output, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJSON(t *testing.T) {
+	type m = map[string]any
+
+	schema := m{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []any{"name", "count", "ratio", "active", "tags", "status", "note"},
+		"properties": m{
+			"name":   m{"type": "string"},
+			"count":  m{"type": "integer"},
+			"ratio":  m{"type": "number"},
+			"active": m{"type": "boolean"},
+			"tags":   m{"type": "array", "items": m{"type": "string"}},
+			"status": m{"type": "string", "enum": []any{"open", "closed"}},
+			"note":   m{"type": []any{"string", "null"}},
+			"limit":  m{"type": "integer", "default": 10},
+			"owner":  m{"$ref": "#/$defs/Owner"},
+		},
+		"$defs": m{
+			"Owner": m{
+				"type":       "object",
+				"properties": m{"id": m{"type": "integer"}},
+			},
+		},
+	}
+
+	t.Run("valid output is unchanged", func(t *testing.T) {
+		text := `{"name": "a", "count": 1, "ratio": 0.5, "active": true, "tags": [], "status": "open", "note": null, "limit": 3}`
+		repaired, repairs, err := agents.RepairJSON(text, schema)
+		require.NoError(t, err)
+		assert.Equal(t, text, repaired)
+		assert.Empty(t, repairs)
+	})
+
+	t.Run("coercion", func(t *testing.T) {
+		text := "Here is the result:\n```json\n" +
+			`{"name": 42, "count": "7", "ratio": "0.25", "active": "TRUE", "tags": "x", "status": "Closed", "owner": {"id": "3"}, "extra": 1,}` +
+			"\n```"
+		repaired, repairs, err := agents.RepairJSON(text, schema)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"name": "42", "count": 7, "ratio": 0.25, "active": true, "tags": ["x"],
+			"status": "closed", "note": null, "limit": 10, "owner": {"id": 3}
+		}`, repaired)
+
+		descriptions := make([]string, len(repairs))
+		for i, repair := range repairs {
+			descriptions[i] = repair.String()
+		}
+		assert.Equal(t, []string{
+			"removed text around the JSON document",
+			"removed trailing commas",
+			"/active: converted string to boolean",
+			"/count: converted string to integer",
+			"/limit: filled missing property with the default value",
+			"/name: converted number to string",
+			"/note: filled missing property with null",
+			"/owner/id: converted string to integer",
+			"/ratio: converted string to number",
+			"/status: replaced \"Closed\" with the enum value \"closed\"",
+			"/tags: wrapped string in an array",
+			"/extra: removed property not allowed by the schema",
+		}, descriptions)
+	})
+
+	t.Run("code fences", func(t *testing.T) {
+		repaired, repairs, err := agents.RepairJSON("```json\n{\"a\": 1}\n```", nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a": 1}`, repaired)
+		assert.Equal(t, []agents.JSONRepair{{Description: "removed code fences"}}, repairs)
+	})
+
+	t.Run("truncated output", func(t *testing.T) {
+		repaired, repairs, err := agents.RepairJSON(`{"tags": ["a", "b`, m{
+			"type":       "object",
+			"properties": m{"tags": m{"type": "array", "items": m{"type": "string"}}},
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tags": ["a", "b"]}`, repaired)
+		assert.Equal(t, []agents.JSONRepair{{Description: "completed truncated JSON"}}, repairs)
+	})
+
+	t.Run("not JSON", func(t *testing.T) {
+		_, _, err := agents.RepairJSON(`I cannot answer that.`, schema)
+		assert.Error(t, err)
+	})
+}
+
+func TestRepairOutputType(t *testing.T) {
+	type Foo struct {
+		Bar   string `json:"bar"`
+		Count int    `json:"count"`
+	}
+
+	var repairs []agents.JSONRepair
+	ot := agents.RepairOutputTypeWithOpts(agents.OutputType[Foo](), agents.RepairOutputTypeOpts{
+		OnRepair: func(_ context.Context, r []agents.JSONRepair) { repairs = r },
+	})
+
+	assert.Equal(t, "agents_test.Foo", ot.Name())
+	assert.True(t, ot.IsStrictJSONSchema())
+
+	validated, err := ot.ValidateJSON(t.Context(), `{"bar": "baz", "count": 1}`)
+	require.NoError(t, err)
+	assert.Equal(t, Foo{Bar: "baz", Count: 1}, validated)
+	assert.Empty(t, repairs)
+
+	validated, err = ot.ValidateJSON(t.Context(), "```json\n{\"bar\": \"baz\", \"count\": \"2\"}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, Foo{Bar: "baz", Count: 2}, validated)
+	assert.Len(t, repairs, 2)
+
+	_, err = ot.ValidateJSON(t.Context(), `{"bar": "baz"}`)
+	require.ErrorAs(t, err, &agents.ModelBehaviorError{})
+}
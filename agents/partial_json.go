@@ -0,0 +1,384 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ParsePartialJSON parses the prefix of a JSON document, such as the text of
+// a structured output still being streamed, returning the value it describes
+// so far: open objects, arrays and strings are closed, while incomplete keys,
+// numbers and literals are dropped. Numbers are returned as json.Number.
+// It returns an error if the text is not the prefix of a JSON document.
+func ParsePartialJSON(text string) (any, error) {
+	p := partialJSONParser{s: text}
+	p.skipSpace()
+	if p.eof() {
+		return nil, nil
+	}
+	value, complete, err := p.parseValue()
+	if errors.Is(err, errPartialJSONOmitted) {
+		return nil, nil
+	}
+	if err != nil || !complete {
+		return value, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, fmt.Errorf("invalid character %q after top-level value", p.s[p.i])
+	}
+	return value, nil
+}
+
+var jsonEscapes = map[byte]byte{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+var errPartialJSONOmitted = errors.New("partial value omitted")
+
+type partialJSONParser struct {
+	s string
+	i int
+}
+
+func (p *partialJSONParser) eof() bool { return p.i >= len(p.s) }
+
+func (p *partialJSONParser) skipSpace() {
+	for !p.eof() && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+// parseValue parses the value at the current position, reporting whether it
+// is complete. Incomplete values which cannot be shown partially are
+// reported with errPartialJSONOmitted.
+func (p *partialJSONParser) parseValue() (any, bool, error) {
+	switch c := p.s[p.i]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		return p.parseString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	default:
+		return p.parseLiteral()
+	}
+}
+
+func (p *partialJSONParser) parseObject() (any, bool, error) {
+	obj := make(map[string]any)
+	p.i++ // {
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return obj, false, nil
+		}
+		if p.s[p.i] == '}' && len(obj) == 0 {
+			p.i++
+			return obj, true, nil
+		}
+		if p.s[p.i] != '"' {
+			return nil, false, fmt.Errorf("invalid character %q looking for object key", p.s[p.i])
+		}
+		key, complete, err := p.parseString()
+		if err != nil || !complete {
+			return obj, false, err
+		}
+		p.skipSpace()
+		if p.eof() {
+			return obj, false, nil
+		}
+		if p.s[p.i] != ':' {
+			return nil, false, fmt.Errorf("invalid character %q after object key", p.s[p.i])
+		}
+		p.i++
+		p.skipSpace()
+		if p.eof() {
+			return obj, false, nil
+		}
+		value, complete, err := p.parseValue()
+		if errors.Is(err, errPartialJSONOmitted) {
+			return obj, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		obj[key.(string)] = value
+		if !complete {
+			return obj, false, nil
+		}
+		p.skipSpace()
+		if p.eof() {
+			return obj, false, nil
+		}
+		switch p.s[p.i] {
+		case ',':
+			p.i++
+		case '}':
+			p.i++
+			return obj, true, nil
+		default:
+			return nil, false, fmt.Errorf("invalid character %q after object value", p.s[p.i])
+		}
+	}
+}
+
+func (p *partialJSONParser) parseArray() (any, bool, error) {
+	arr := make([]any, 0)
+	p.i++ // [
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return arr, false, nil
+		}
+		if p.s[p.i] == ']' && len(arr) == 0 {
+			p.i++
+			return arr, true, nil
+		}
+		value, complete, err := p.parseValue()
+		if errors.Is(err, errPartialJSONOmitted) {
+			return arr, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		arr = append(arr, value)
+		if !complete {
+			return arr, false, nil
+		}
+		p.skipSpace()
+		if p.eof() {
+			return arr, false, nil
+		}
+		switch p.s[p.i] {
+		case ',':
+			p.i++
+		case ']':
+			p.i++
+			return arr, true, nil
+		default:
+			return nil, false, fmt.Errorf("invalid character %q after array element", p.s[p.i])
+		}
+	}
+}
+
+func (p *partialJSONParser) parseString() (any, bool, error) {
+	var sb strings.Builder
+	p.i++ // "
+	for !p.eof() {
+		c := p.s[p.i]
+		switch {
+		case c == '"':
+			p.i++
+			return sb.String(), true, nil
+		case c == '\\':
+			if p.i+1 >= len(p.s) {
+				return sb.String(), false, nil
+			}
+			switch esc := p.s[p.i+1]; esc {
+			case 'u':
+				n, complete := unicodeEscapeLen(p.s[p.i:])
+				if !complete {
+					return sb.String(), false, nil
+				}
+				var unescaped string
+				if err := json.Unmarshal([]byte(`"`+p.s[p.i:p.i+n]+`"`), &unescaped); err != nil {
+					return nil, false, fmt.Errorf("invalid unicode escape %q in string", p.s[p.i:p.i+n])
+				}
+				sb.WriteString(unescaped)
+				p.i += n
+			default:
+				unescaped, ok := jsonEscapes[esc]
+				if !ok {
+					return nil, false, fmt.Errorf("invalid escape %q in string", esc)
+				}
+				sb.WriteByte(unescaped)
+				p.i += 2
+			}
+		case c < 0x20:
+			return nil, false, fmt.Errorf("invalid control character %q in string", c)
+		default:
+			r, size := utf8.DecodeRuneInString(p.s[p.i:])
+			if r == utf8.RuneError && size == 1 && !utf8.FullRuneInString(p.s[p.i:]) {
+				// Multi-byte characters can be split across deltas.
+				return sb.String(), false, nil
+			}
+			sb.WriteString(p.s[p.i : p.i+size])
+			p.i += size
+		}
+	}
+	return sb.String(), false, nil
+}
+
+// unicodeEscapeLen returns the length of the \uXXXX escape at the start of
+// s, including the low surrogate following a high one, and whether it is
+// complete.
+func unicodeEscapeLen(s string) (int, bool) {
+	if len(s) < 6 {
+		return 0, false
+	}
+	r, err := strconv.ParseUint(s[2:6], 16, 16)
+	if err != nil || !utf16.IsSurrogate(rune(r)) || r >= 0xdc00 {
+		// Invalid escapes are reported when decoding.
+		return 6, true
+	}
+	rest := s[6:]
+	if len(rest) < 6 && strings.HasPrefix(`\u`, rest[:min(len(rest), 2)]) {
+		return 0, false
+	}
+	if strings.HasPrefix(rest, `\u`) {
+		return 12, true
+	}
+	return 6, true
+}
+
+func (p *partialJSONParser) parseNumber() (any, bool, error) {
+	start := p.i
+	for !p.eof() && strings.IndexByte("+-0123456789.eE", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	if p.eof() {
+		// The number could continue.
+		return nil, false, errPartialJSONOmitted
+	}
+	var number json.Number
+	if err := json.Unmarshal([]byte(p.s[start:p.i]), &number); err != nil {
+		return nil, false, fmt.Errorf("invalid number %q", p.s[start:p.i])
+	}
+	return number, true, nil
+}
+
+func (p *partialJSONParser) parseLiteral() (any, bool, error) {
+	for _, lit := range []struct {
+		text  string
+		value any
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		rest := p.s[p.i:]
+		if strings.HasPrefix(rest, lit.text) {
+			p.i += len(lit.text)
+			return lit.value, true, nil
+		}
+		if strings.HasPrefix(lit.text, rest) {
+			p.i = len(p.s)
+			return nil, false, errPartialJSONOmitted
+		}
+	}
+	return nil, false, fmt.Errorf("invalid character %q looking for value", p.s[p.i])
+}
+
+// PartialOutputParser builds snapshots of the structured output of type T
+// from the output_text deltas of a streamed run, so that it can be rendered
+// progressively. The snapshots are partially populated: the fields not
+// streamed yet have their zero values, and strings can be truncated.
+//
+//	parser := agents.NewPartialOutputParser[Report]()
+//	err = result.StreamEvents(func(event agents.StreamEvent) error {
+//		if report, ok := parser.Feed(event); ok {
+//			render(report)
+//		}
+//		return nil
+//	})
+type PartialOutputParser[T any] struct {
+	text       strings.Builder
+	wrapped    bool
+	plainText  bool
+	lastOutput []byte
+}
+
+// NewPartialOutputParser returns a PartialOutputParser of the outputs of
+// the agents with output type OutputType[T] or OutputTypeOf[T].
+func NewPartialOutputParser[T any]() *PartialOutputParser[T] {
+	var zero T
+	_, plainText := any(zero).(string)
+	return &PartialOutputParser[T]{
+		wrapped:   !plainText && !isStruct[T](),
+		plainText: plainText,
+	}
+}
+
+// Feed processes an event of the streamed run. It returns the new snapshot
+// of the output, and true when the event changed it. The snapshot is reset
+// when the model starts a new message.
+func (p *PartialOutputParser[T]) Feed(event StreamEvent) (T, bool) {
+	var zero T
+	e, ok := event.(RawResponsesStreamEvent)
+	if !ok {
+		return zero, false
+	}
+	switch e.Data.Type {
+	case "response.created":
+		p.Reset()
+	case "response.output_item.added":
+		if e.Data.Item.Type == "message" {
+			p.Reset()
+		}
+	case "response.output_text.delta":
+		return p.AddDelta(e.Data.Delta)
+	}
+	return zero, false
+}
+
+// AddDelta appends a delta of the output text. It returns the new snapshot
+// of the output, and true when the delta changed it. Text which is not a
+// JSON prefix of T leaves the snapshot unchanged.
+func (p *PartialOutputParser[T]) AddDelta(delta string) (T, bool) {
+	var output T
+	p.text.WriteString(delta)
+	if p.plainText {
+		if delta == "" {
+			return output, false
+		}
+		return any(p.text.String()).(T), true
+	}
+
+	value, err := ParsePartialJSON(p.text.String())
+	if err != nil || value == nil {
+		return output, false
+	}
+	if p.wrapped {
+		obj, _ := value.(map[string]any)
+		if value = obj["response"]; value == nil {
+			return output, false
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil || bytes.Equal(b, p.lastOutput) {
+		return output, false
+	}
+	if err = json.Unmarshal(b, &output); err != nil {
+		return output, false
+	}
+	p.lastOutput = b
+	return output, true
+}
+
+// Text returns the output text received since the last reset.
+func (p *PartialOutputParser[T]) Text() string {
+	return p.text.String()
+}
+
+// Reset discards the output text received so far.
+func (p *PartialOutputParser[T]) Reset() {
+	p.text.Reset()
+	p.lastOutput = nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePartialJSON(t *testing.T) {
+	type m = map[string]any
+
+	testCases := []struct {
+		text string
+		want any
+	}{
+		{``, nil},
+		{`  `, nil},
+		{`{`, m{}},
+		{`{"ti`, m{}},
+		{`{"title"`, m{}},
+		{`{"title": `, m{}},
+		{`{"title": "Hel`, m{"title": "Hel"}},
+		{`{"title": "Hello", "items": [`, m{"title": "Hello", "items": []any{}}},
+		{`{"title": "Hello", "items": ["a", "b`, m{"title": "Hello", "items": []any{"a", "b"}}},
+		{`{"count": 12`, m{}},
+		{`{"count": 12,`, m{"count": json.Number("12")}},
+		{`{"ok": tr`, m{}},
+		{`{"ok": true, "none": null}`, m{"ok": true, "none": nil}},
+		{`[{"a": 1}, {"b": [1, 2`, []any{m{"a": json.Number("1")}, m{"b": []any{json.Number("1")}}}},
+		{`"line\`, "line"},
+		{`"line\n`, "line\n"},
+		{`"caf\u00`, "caf"},
+		{`"café"`, "café"},
+		{`"\ud83d`, ""},
+		{`"\ud83d\u`, ""},
+		{`"😀"`, "😀"},
+		{"\"caf\xc3", "caf"},
+		{`{} `, m{}},
+		{`[]`, []any{}},
+		{`12`, nil},
+		{`12 `, json.Number("12")},
+	}
+	for _, tc := range testCases {
+		got, err := agents.ParsePartialJSON(tc.text)
+		require.NoError(t, err, tc.text)
+		assert.Equal(t, tc.want, got, tc.text)
+	}
+
+	for _, text := range []string{`x`, `{"a" 1}`, `{1: 2}`, `[1 2]`, `{"a": 1}}`, `"\x"`, `[truth]`} {
+		_, err := agents.ParsePartialJSON(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestPartialOutputParser(t *testing.T) {
+	type Report struct {
+		Title string   `json:"title"`
+		Items []string `json:"items"`
+		Count int      `json:"count"`
+	}
+
+	textDelta := func(delta string) agents.StreamEvent {
+		return agents.RawResponsesStreamEvent{
+			Data: responses.ResponseStreamEventUnion{Type: "response.output_text.delta", Delta: delta},
+			Type: "raw_response_event",
+		}
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		parser := agents.NewPartialOutputParser[Report]()
+
+		var snapshots []Report
+		for _, delta := range []string{`{"ti`, `tle": "Wee`, `kly", "items": ["a`, `", "b"], `, `"count": 1`, `2}`} {
+			if report, ok := parser.Feed(textDelta(delta)); ok {
+				snapshots = append(snapshots, report)
+			}
+		}
+		assert.Equal(t, []Report{
+			{},
+			{Title: "Wee"},
+			{Title: "Weekly", Items: []string{"a"}},
+			{Title: "Weekly", Items: []string{"a", "b"}},
+			{Title: "Weekly", Items: []string{"a", "b"}, Count: 12},
+		}, snapshots)
+		assert.Equal(t, `{"title": "Weekly", "items": ["a", "b"], "count": 12}`, parser.Text())
+
+		_, ok := parser.Feed(agents.RawResponsesStreamEvent{
+			Data: responses.ResponseStreamEventUnion{
+				Type: "response.output_item.added",
+				Item: responses.ResponseOutputItemUnion{Type: "message"},
+			},
+		})
+		assert.False(t, ok)
+		assert.Empty(t, parser.Text())
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		parser := agents.NewPartialOutputParser[[]string]()
+
+		_, ok := parser.AddDelta(`{"resp`)
+		assert.False(t, ok)
+		values, ok := parser.AddDelta(`onse": ["x", "y`)
+		require.True(t, ok)
+		assert.Equal(t, []string{"x", "y"}, values)
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		parser := agents.NewPartialOutputParser[string]()
+
+		parser.AddDelta("Hello, ")
+		text, ok := parser.AddDelta("world")
+		require.True(t, ok)
+		assert.Equal(t, "Hello, world", text)
+	})
+
+	t.Run("other events", func(t *testing.T) {
+		parser := agents.NewPartialOutputParser[Report]()
+
+		_, ok := parser.Feed(agents.AgentUpdatedStreamEvent{})
+		assert.False(t, ok)
+	})
+}
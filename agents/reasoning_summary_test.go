@@ -0,0 +1,91 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reasoningModel streams reasoning summary deltas before the response of
+// the fake model.
+type reasoningModel struct {
+	*agentstesting.FakeModel
+	deltas []string
+}
+
+func (m reasoningModel) StreamResponse(ctx context.Context, params agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	for _, delta := range m.deltas {
+		err := yield(ctx, agents.TResponseStreamEvent{
+			Type:   "response.reasoning_summary_text.delta",
+			ItemID: "rs_1",
+			Delta:  delta,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return m.FakeModel.StreamResponse(ctx, params, yield)
+}
+
+func TestReasoningSummaryDeltaStreamEvents(t *testing.T) {
+	fake := agentstesting.NewFakeModel(false, nil)
+	fake.SetNextOutput(agentstesting.FakeModelTurnOutput{Value: []agents.TResponseOutputItem{
+		{
+			ID:      "rs_1",
+			Type:    "reasoning",
+			Summary: []responses.ResponseReasoningItemSummary{{Text: "Thinking hard.", Type: "summary_text"}},
+		},
+		agentstesting.GetTextMessage("done"),
+	}})
+	agent := agents.New("test").WithModelInstance(reasoningModel{FakeModel: fake, deltas: []string{"Thinking", " hard."}})
+
+	result, err := agents.Runner{}.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	var (
+		deltas    []agents.ReasoningSummaryDeltaStreamEvent
+		reasoning []agents.ReasoningItem
+	)
+	err = result.StreamEvents(func(event agents.StreamEvent) error {
+		switch e := event.(type) {
+		case agents.ReasoningSummaryDeltaStreamEvent:
+			deltas = append(deltas, e)
+		case agents.RunItemStreamEvent:
+			if item, ok := e.Item.(agents.ReasoningItem); ok {
+				assert.Equal(t, agents.StreamEventReasoningItemCreated, e.Name)
+				reasoning = append(reasoning, item)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, deltas, 2)
+	for i, delta := range []string{"Thinking", " hard."} {
+		assert.Same(t, agent, deltas[i].Agent)
+		assert.Equal(t, "rs_1", deltas[i].ItemID)
+		assert.Equal(t, delta, deltas[i].Delta)
+		assert.Equal(t, "reasoning_summary_delta_event", deltas[i].Type)
+	}
+	require.Len(t, reasoning, 1)
+	assert.Equal(t, "Thinking hard.", reasoning[0].RawItem.Summary[0].Text)
+	assert.Equal(t, "done", result.FinalOutput())
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/usage"
+)
+
+// ResponseCache stores the model responses by the key of their requests, see
+// RunConfig.ResponseCache. Implementations backed by a database share the
+// responses across processes.
+type ResponseCache interface {
+	GetResponse(ctx context.Context, key string) (response *ModelResponse, ok bool, err error)
+	SetResponse(ctx context.Context, key string, response ModelResponse) error
+}
+
+// InMemoryResponseCache is a ResponseCache for the lifetime of the process.
+type InMemoryResponseCache struct {
+	mu        sync.Mutex
+	responses map[string]ModelResponse
+}
+
+// NewInMemoryResponseCache returns an empty InMemoryResponseCache.
+func NewInMemoryResponseCache() *InMemoryResponseCache {
+	return &InMemoryResponseCache{responses: make(map[string]ModelResponse)}
+}
+
+func (c *InMemoryResponseCache) GetResponse(_ context.Context, key string) (*ModelResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response, ok := c.responses[key]
+	if !ok {
+		return nil, false, nil
+	}
+	response = cloneModelResponse(response)
+	return &response, true, nil
+}
+
+func (c *InMemoryResponseCache) SetResponse(_ context.Context, key string, response ModelResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[key] = cloneModelResponse(response)
+	return nil
+}
+
+// Len returns the number of cached responses.
+func (c *InMemoryResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.responses)
+}
+
+func cloneModelResponse(response ModelResponse) ModelResponse {
+	response.Output = slices.Clone(response.Output)
+	if response.Usage != nil {
+		u := *response.Usage
+		response.Usage = &u
+	}
+	return response
+}
+
+// responseCacheKey returns the key of the request of a model call: the
+// SHA-256 of the model, its settings and the normalized request. The IDs of
+// the input items are left out, as they differ between the runs replaying
+// the same conversation.
+func responseCacheKey(modelID string, params ModelResponseParams) (string, error) {
+	input, err := json.Marshal(ItemHelpers().InputToNewInputList(params.Input))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal model input: %w", err)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(input, &items); err != nil {
+		return "", fmt.Errorf("failed to normalize model input: %w", err)
+	}
+	for _, item := range items {
+		delete(item, "id")
+	}
+
+	tools := make([]any, len(params.Tools))
+	for i, tool := range params.Tools {
+		tools[i] = responseCacheToolKey(tool)
+	}
+	handoffs := make([]any, len(params.Handoffs))
+	for i, h := range params.Handoffs {
+		handoffs[i] = map[string]any{
+			"name":        h.ToolName,
+			"description": h.ToolDescription,
+			"schema":      h.InputJSONSchema,
+			"strict":      h.StrictJSONSchema,
+		}
+	}
+	var outputType any
+	if ot := params.OutputType; ot != nil && !ot.IsPlainText() {
+		schema, err := ot.JSONSchema()
+		if err != nil {
+			return "", fmt.Errorf("failed to get output JSON schema: %w", err)
+		}
+		outputType = map[string]any{"name": ot.Name(), "schema": schema, "strict": ot.IsStrictJSONSchema()}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"model":                modelID,
+		"settings":             params.ModelSettings,
+		"instructions":         params.SystemInstructions,
+		"input":                items,
+		"tools":                tools,
+		"handoffs":             handoffs,
+		"output_type":          outputType,
+		"previous_response_id": params.PreviousResponseID,
+		"prompt":               params.Prompt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal model request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "response:" + hex.EncodeToString(sum[:]), nil
+}
+
+// responseCacheToolKey identifies a tool by its definition. Tools which
+// cannot be marshaled, e.g. holding functions, are identified by their type
+// and name.
+func responseCacheToolKey(tool Tool) any {
+	if t, ok := tool.(FunctionTool); ok {
+		return map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+			"schema":      t.ParamsJSONSchema,
+			"strict":      t.StrictJSONSchema,
+		}
+	}
+	key := map[string]any{"type": fmt.Sprintf("%T", tool), "name": tool.ToolName()}
+	if data, err := json.Marshal(tool); err == nil {
+		key["definition"] = json.RawMessage(data)
+	}
+	return key
+}
+
+// responseCacheModelID identifies the model of the agent: by name, or for
+// models set as instances by their type and address, so that their
+// responses are only reused within the process.
+func responseCacheModelID(agent *Agent, runConfig RunConfig, model Model) string {
+	if name := modelNameForMetrics(agent, runConfig); name != "custom" {
+		return name
+	}
+	if v := reflect.ValueOf(model); v.Kind() == reflect.Pointer {
+		return fmt.Sprintf("%T@%x", model, v.Pointer())
+	}
+	return fmt.Sprintf("%T", model)
+}
+
+// getCachedResponse looks the request up in the ResponseCache of the run,
+// returning its key, and the cached response on hits. Cached responses are
+// returned without usage, as they cost no model request.
+func getCachedResponse(
+	ctx context.Context,
+	agent *Agent,
+	runConfig RunConfig,
+	model Model,
+	params ModelResponseParams,
+) (string, *ModelResponse, error) {
+	if runConfig.ResponseCache == nil {
+		return "", nil, nil
+	}
+	key, err := responseCacheKey(responseCacheModelID(agent, runConfig, model), params)
+	if err != nil {
+		return "", nil, err
+	}
+	response, ok, err := runConfig.ResponseCache.GetResponse(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("response cache: %w", err)
+	}
+	if !ok {
+		return key, nil, nil
+	}
+	response.Usage = usage.NewUsage()
+	Logger().DebugContext(ctx, "Using cached model response", slog.String("agent", agent.Name))
+	return key, response, nil
+}
+
+// setCachedResponse stores the response of a model call under its key, if
+// the run has a ResponseCache.
+func setCachedResponse(ctx context.Context, runConfig RunConfig, key string, response ModelResponse) error {
+	if runConfig.ResponseCache == nil || key == "" {
+		return nil
+	}
+	if err := runConfig.ResponseCache.SetResponse(ctx, key, cloneModelResponse(response)); err != nil {
+		return fmt.Errorf("response cache: %w", err)
+	}
+	return nil
+}
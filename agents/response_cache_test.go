@@ -0,0 +1,89 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("first")},
+	})
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("second")}},
+	})
+	agent := &agents.Agent{
+		Name:  "test",
+		Model: param.NewOpt(agents.NewAgentModel(model)),
+	}
+	cache := agents.NewInMemoryResponseCache()
+	runner := agents.Runner{Config: agents.RunConfig{ResponseCache: cache}}
+
+	result, err := runner.Run(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "first", result.FinalOutput)
+	assert.Equal(t, uint64(1), result.RawResponses[0].Usage.Requests)
+	assert.Equal(t, 1, cache.Len())
+
+	// The identical request is served by the cache, not by the model.
+	result, err = runner.Run(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "first", result.FinalOutput)
+	assert.Zero(t, result.RawResponses[0].Usage.Requests)
+
+	streamed, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	require.NoError(t, streamed.StreamEvents(func(agents.StreamEvent) error { return nil }))
+	assert.Equal(t, "first", streamed.FinalOutput())
+
+	// Other inputs call the model.
+	result, err = runner.Run(t.Context(), agent, "goodbye")
+	require.NoError(t, err)
+	assert.Equal(t, "second", result.FinalOutput)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestResponseCacheStreamed(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("first")},
+	})
+	agent := &agents.Agent{
+		Name:         "test",
+		Instructions: agents.InstructionsStr("Be brief."),
+		Model:        param.NewOpt(agents.NewAgentModel(model)),
+	}
+	runner := agents.Runner{Config: agents.RunConfig{ResponseCache: agents.NewInMemoryResponseCache()}}
+
+	for range 2 {
+		result, err := runner.RunStreamed(t.Context(), agent, "hello")
+		require.NoError(t, err)
+		var rawEvents int
+		require.NoError(t, result.StreamEvents(func(event agents.StreamEvent) error {
+			if _, ok := event.(agents.RawResponsesStreamEvent); ok {
+				rawEvents++
+			}
+			return nil
+		}))
+		assert.Equal(t, "first", result.FinalOutput())
+		assert.Equal(t, 1, rawEvents)
+	}
+}
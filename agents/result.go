@@ -75,8 +75,7 @@ func (r RunResult) LastResponseID() string {
 type RunResultStreaming struct {
 	context                context.Context
 	input                  *atomic.Pointer[Input]
-	newItems               *atomic.Pointer[[]RunItem]
-	rawResponses           *atomic.Pointer[[]ModelResponse]
+	log                    *runItemLog
 	finalOutput            *atomic.Value
 	inputGuardrailResults  *atomic.Pointer[[]InputGuardrailResult]
 	outputGuardrailResults *atomic.Pointer[[]OutputGuardrailResult]
@@ -92,14 +91,16 @@ type RunResultStreaming struct {
 	inputGuardrailsTask    *atomic.Pointer[asynctask.TaskNoValue]
 	outputGuardrailsTask   *atomic.Pointer[asynctask.Task[[]OutputGuardrailResult]]
 	storedError            *atomic.Pointer[error]
+	// dropDeltas is StreamBuffer.DropDeltas.
+	dropDeltas    bool
+	droppedEvents *atomic.Uint64
 }
 
 func newRunResultStreaming(ctx context.Context) *RunResultStreaming {
 	return &RunResultStreaming{
 		context:                ctx,
 		input:                  newZeroValAtomicPointer[Input](),
-		newItems:               newZeroValAtomicPointer[[]RunItem](),
-		rawResponses:           newZeroValAtomicPointer[[]ModelResponse](),
+		log:                    new(runItemLog),
 		finalOutput:            new(atomic.Value),
 		inputGuardrailResults:  newZeroValAtomicPointer[[]InputGuardrailResult](),
 		outputGuardrailResults: newZeroValAtomicPointer[[]OutputGuardrailResult](),
@@ -115,6 +116,7 @@ func newRunResultStreaming(ctx context.Context) *RunResultStreaming {
 		inputGuardrailsTask:    new(atomic.Pointer[asynctask.TaskNoValue]),
 		outputGuardrailsTask:   new(atomic.Pointer[asynctask.Task[[]OutputGuardrailResult]]),
 		storedError:            newZeroValAtomicPointer[error](),
+		droppedEvents:          new(atomic.Uint64),
 	}
 }
 
@@ -132,15 +134,10 @@ func (r *RunResultStreaming) setInput(v Input) { r.input.Store(&v) }
 
 // NewItems returns the new items generated during the agent run.
 // These include things like new messages, tool calls and their outputs, etc.
-func (r *RunResultStreaming) NewItems() []RunItem     { return *r.newItems.Load() }
-func (r *RunResultStreaming) setNewItems(v []RunItem) { r.newItems.Store(&v) }
+func (r *RunResultStreaming) NewItems() []RunItem { return r.log.Items() }
 
 // RawResponses returns the raw LLM responses generated by the model during the agent run.
-func (r *RunResultStreaming) RawResponses() []ModelResponse     { return *r.rawResponses.Load() }
-func (r *RunResultStreaming) setRawResponses(v []ModelResponse) { r.rawResponses.Store(&v) }
-func (r *RunResultStreaming) appendRawResponses(v ...ModelResponse) {
-	r.setRawResponses(append(r.RawResponses(), v...))
-}
+func (r *RunResultStreaming) RawResponses() []ModelResponse { return r.log.RawResponses() }
 
 // FinalOutput returns the output of the last agent.
 // This is nil until the agent has finished running.
@@ -240,6 +237,12 @@ func (r *RunResultStreaming) LastAgent() *Agent {
 	return r.CurrentAgent()
 }
 
+// DroppedEvents returns the number of delta events dropped so far because
+// the consumer was too slow, see StreamBuffer.DropDeltas.
+func (r *RunResultStreaming) DroppedEvents() uint64 {
+	return r.droppedEvents.Load()
+}
+
 // Cancel the streaming run, stopping all background tasks and marking the run as complete.
 func (r *RunResultStreaming) Cancel() {
 	r.markAsComplete() // Mark the run as complete to stop event streaming
@@ -262,7 +265,13 @@ func (r *RunResultStreaming) Cancel() {
 // Possible well-known errors returned:
 //   - A MaxTurnsExceededError if the agent exceeds the MaxTurns limit.
 //   - A *GuardrailTripwireTriggeredError if a guardrail is tripped.
+//
+// With a RunConfig.StreamBuffer, the run waits for the events to be consumed
+// once its buffer is full, or until it is canceled. The events emitted after
+// StreamEvents returns are discarded.
 func (r *RunResultStreaming) StreamEvents(fn func(StreamEvent) error) error {
+	// The run must not wait for a consumer which stopped.
+	defer r.eventQueue.Close()
 	for {
 		err := r.checkErrors()
 		if err != nil {
@@ -295,6 +304,7 @@ func (r *RunResultStreaming) StreamEvents(fn func(StreamEvent) error) error {
 		}
 	}
 
+	r.eventQueue.Close()
 	r.awaitTasks()
 	if err := r.checkErrors(); err != nil {
 		return err
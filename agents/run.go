@@ -17,16 +17,22 @@ package agents
 import (
 	"cmp"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"iter"
 	"log/slog"
 	"reflect"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/nlpodyssey/openai-agents-go/asyncqueue"
 	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/nlpodyssey/openai-agents-go/metrics"
 	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 	"github.com/nlpodyssey/openai-agents-go/tracing"
 	"github.com/nlpodyssey/openai-agents-go/usage"
@@ -77,6 +83,13 @@ type RunConfig struct {
 	// override the agent-specific model settings.
 	ModelSettings modelsettings.ModelSettings
 
+	// Optional model settings by model name, resolved at call time on top of
+	// the agent-specific and global model settings when an agent runs on
+	// that model, e.g. a temperature only for "gpt-4.1" or a reasoning effort
+	// only for "o3". Names with a provider prefix, like "litellm/o3", are
+	// matched in full first, then without the prefix.
+	ModelSettingsByModel map[string]modelsettings.ModelSettings
+
 	// Optional global input filter to apply to all handoffs. If `Handoff.InputFilter` is set, then that
 	// will take precedence. The input filter allows you to edit the inputs that are sent to the new
 	// agent. See the documentation in `Handoff.InputFilter` for more details.
@@ -130,6 +143,11 @@ type RunConfig struct {
 	// Optional object that receives callbacks on various lifecycle events.
 	Hooks RunHooks
 
+	// Whether to freeze the agents when they first run, see Agent.Freeze, so
+	// that modifying an agent shared by concurrent runs panics instead of
+	// racing with them.
+	FreezeAgents bool
+
 	// Optional ID of the previous response, if using OpenAI models via the Responses API,
 	// this allows you to skip passing in input from the previous turn.
 	PreviousResponseID string
@@ -139,6 +157,47 @@ type RunConfig struct {
 
 	// Optional limit for the recover of the session of memory.
 	LimitMemory int
+
+	// Optional policy applied to the session history after it has been
+	// retrieved (and limited by LimitMemory), for example to keep it within
+	// a token budget. See memory.TokenBudgetTrimPolicy.
+	SessionTrimPolicy memory.TrimPolicy
+
+	// Optional long-term memory used by agents which do not set their own
+	// Agent.LongTermMemory. See memory.VectorMemory.
+	LongTermMemory memory.LongTermMemory
+
+	// Optional number of times the model is asked to fix a structured final
+	// output which fails validation against the output type of the agent.
+	// The violations, with their JSON paths, are sent back to the model as an
+	// OutputValidationFeedbackItem. Default (when left zero): the run fails
+	// with the validation error.
+	OutputValidationRetries int
+
+	// Optional transcriber of the audio input (see InputAudio) sent to models
+	// without audio support (see AudioInputSupport), which receive the
+	// transcript instead. Default: OpenAIAudioTranscriber.
+	AudioTranscriber AudioTranscriber
+
+	// Optional cache of the model responses, keyed on the model, the model
+	// settings and the normalized request (instructions, input items, tools,
+	// handoffs and output type). Identical requests get the cached response
+	// instead of calling the model, e.g. in evaluation sweeps and idempotent
+	// batch jobs. Cached responses report no usage. See
+	// InMemoryResponseCache.
+	ResponseCache ResponseCache
+
+	// Optional bounds of the items and raw responses retained by the results
+	// of streamed runs, for very long runs. See RunRetention.
+	Retention RunRetention
+
+	// Optional detection of the agents handing off to each other in a loop.
+	// See HandoffLoopPolicy.
+	HandoffLoopPolicy HandoffLoopPolicy
+
+	// Optional bound of the events of streamed runs waiting to be consumed.
+	// See StreamBuffer.
+	StreamBuffer StreamBuffer
 }
 
 // EventSeqResult contains the sequence of streaming events generated by
@@ -324,11 +383,28 @@ func (r Runner) runStreamedSeq(ctx context.Context, startingAgent *Agent, input
 	return res, nil
 }
 
-func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*RunResult, error) {
+func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (_ *RunResult, err error) {
 	if startingAgent == nil {
 		return nil, fmt.Errorf("startingAgent must not be nil")
 	}
 
+	workflowName := cmp.Or(r.Config.WorkflowName, DefaultWorkflowName)
+	runID := newRunID()
+	ctx = ContextWithLogAttrs(ctx, slog.String("run_id", runID), slog.String("workflow", workflowName))
+	ctx = contextWithLiveRun(ctx, runID, workflowName)
+	ctx = contextWithAudioTranscripts(ctx)
+	ctx = contextWithHandoffState(ctx)
+	ctx = contextWithAgentUsageState(ctx)
+	recorder := metrics.GetRecorder()
+	recorder.RunStarted(workflowName)
+	startedAt := time.Now()
+	currentTurn := uint64(0)
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventRunStarted, Agent: startingAgent})
+	defer func() {
+		recorder.RunFinished(workflowName, metrics.OutcomeOf(err), currentTurn, time.Since(startedAt))
+		publishLiveEvent(ctx, LiveEvent{Kind: LiveEventRunFinished, Err: err})
+	}()
+
 	// Prepare input with session if enabled
 	preparedInput, err := r.prepareInputWithSession(ctx, input)
 	if err != nil {
@@ -339,20 +415,20 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 	if hooks == nil {
 		hooks = NoOpRunHooks{}
 	}
+	hooks = liveEventRunHooks{RunHooks: hooks}
 
 	toolUseTracker := NewAgentToolUseTracker()
 
 	var runResult *RunResult
 
 	traceParams := tracing.TraceParams{
-		WorkflowName: cmp.Or(r.Config.WorkflowName, DefaultWorkflowName),
+		WorkflowName: workflowName,
 		TraceID:      r.Config.TraceID,
 		GroupID:      r.Config.GroupID,
 		Metadata:     r.Config.TraceMetadata,
 		Disabled:     r.Config.TracingDisabled,
 	}
 	err = ManageTraceCtx(ctx, traceParams, func(ctx context.Context) (err error) {
-		currentTurn := uint64(0)
 		originalInput := CopyInput(preparedInput)
 
 		maxTurns := r.Config.MaxTurns
@@ -402,6 +478,9 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 		defer cancel()
 
 		for {
+			if r.Config.FreezeAgents {
+				currentAgent.Freeze()
+			}
 			allTools, err := r.getAllTools(childCtx, currentAgent)
 			if err != nil {
 				return err
@@ -447,11 +526,15 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 				})
 				return MaxTurnsExceededErrorf("max turns %d exceeded", maxTurns)
 			}
-			Logger().Debug(
-				"Running agent",
-				slog.String("agentName", currentAgent.Name),
+			if err = startAgentTurn(childCtx, currentAgent); err != nil {
+				return err
+			}
+			turnCtx := ContextWithLogAttrs(
+				childCtx,
+				slog.String("agent", currentAgent.Name),
 				slog.Uint64("turn", currentTurn),
 			)
+			Logger().DebugContext(turnCtx, "Running agent")
 
 			var turnResult *SingleStepResult
 
@@ -477,7 +560,7 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 				go func() {
 					defer wg.Done()
 					turnResult, turnError = r.runSingleTurn(
-						childCtx,
+						turnCtx,
 						currentAgent,
 						allTools,
 						originalInput,
@@ -499,7 +582,7 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 				}
 			} else {
 				turnResult, err = r.runSingleTurn(
-					childCtx,
+					turnCtx,
 					currentAgent,
 					allTools,
 					originalInput,
@@ -547,6 +630,10 @@ func (r Runner) run(ctx context.Context, startingAgent *Agent, input Input) (*Ru
 				if err != nil {
 					return err
 				}
+				err = r.rememberItems(ctx, currentAgent, turnItems(input, runResult))
+				if err != nil {
+					return err
+				}
 
 				return nil
 			case NextStepHandoff:
@@ -582,6 +669,7 @@ func (r Runner) runStreamed(ctx context.Context, startingAgent *Agent, input Inp
 	if hooks == nil {
 		hooks = NoOpRunHooks{}
 	}
+	hooks = liveEventRunHooks{RunHooks: hooks}
 
 	// If there's already a trace, we don't create a new one. In addition, we can't end the trace
 	// here, because the actual work is done in StreamEvents and this method ends before that.
@@ -602,6 +690,9 @@ func (r Runner) runStreamed(ctx context.Context, startingAgent *Agent, input Inp
 	}
 
 	streamedResult := newRunResultStreaming(ctx)
+	streamedResult.log.retention = r.Config.Retention
+	streamedResult.eventQueue = asyncqueue.NewBounded[StreamEvent](r.Config.StreamBuffer.Size)
+	streamedResult.dropDeltas = r.Config.StreamBuffer.DropDeltas
 	streamedResult.setInput(CopyInput(input))
 	streamedResult.setCurrentAgent(startingAgent)
 	streamedResult.setMaxTurns(maxTurns)
@@ -625,6 +716,28 @@ func (r Runner) runStreamed(ctx context.Context, startingAgent *Agent, input Inp
 	return streamedResult, nil
 }
 
+// Prepend the memories recalled by the agent's long-term memory, if any, to
+// the model input.
+func (r Runner) maybeRecallMemories(
+	ctx context.Context,
+	agent *Agent,
+	runConfig RunConfig,
+	inputItems []TResponseInputItem,
+) ([]TResponseInputItem, error) {
+	ltm := cmp.Or(agent.LongTermMemory, runConfig.LongTermMemory)
+	if ltm == nil {
+		return inputItems, nil
+	}
+	recalled, err := ltm.Recall(ctx, inputItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recall memories: %w", err)
+	}
+	if len(recalled) == 0 {
+		return inputItems, nil
+	}
+	return slices.Concat(recalled, inputItems), nil
+}
+
 // Apply optional CallModelInputFilter to modify model input.
 //
 // Returns a ModelInputData that will be sent to the model.
@@ -746,6 +859,22 @@ func (r Runner) startStreaming(
 	currentAgent := startingAgent
 	var currentSpan tracing.Span
 
+	workflowName := cmp.Or(runConfig.WorkflowName, DefaultWorkflowName)
+	runID := newRunID()
+	ctx = ContextWithLogAttrs(ctx, slog.String("run_id", runID), slog.String("workflow", workflowName))
+	ctx = contextWithLiveRun(ctx, runID, workflowName)
+	ctx = contextWithAudioTranscripts(ctx)
+	ctx = contextWithHandoffState(ctx)
+	ctx = contextWithAgentUsageState(ctx)
+	recorder := metrics.GetRecorder()
+	recorder.RunStarted(workflowName)
+	startedAt := time.Now()
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventRunStarted, Agent: startingAgent})
+	defer func() {
+		recorder.RunFinished(workflowName, metrics.OutcomeOf(err), streamedResult.CurrentTurn(), time.Since(startedAt))
+		publishLiveEvent(ctx, LiveEvent{Kind: LiveEventRunFinished, Err: err})
+	}()
+
 	defer func() {
 		// Recover from panics to ensure the queue is properly closed
 		if r := recover(); r != nil {
@@ -772,7 +901,7 @@ func (r Runner) startStreaming(
 			}
 
 			streamedResult.markAsComplete()
-			streamedResult.eventQueue.Put(queueCompleteSentinel{})
+			_ = streamedResult.eventQueue.PutContext(ctx, queueCompleteSentinel{})
 		}
 
 		if currentSpan != nil {
@@ -798,8 +927,9 @@ func (r Runner) startStreaming(
 	currentTurn := uint64(0)
 	shouldRunAgentStartHooks := true
 	toolUseTracker := NewAgentToolUseTracker()
+	offloadedToSession := false
 
-	streamedResult.eventQueue.Put(AgentUpdatedStreamEvent{
+	emitStreamEvent(ctx, streamedResult.eventQueue, AgentUpdatedStreamEvent{
 		NewAgent: currentAgent,
 		Type:     "agent_updated_stream_event",
 	})
@@ -814,6 +944,9 @@ func (r Runner) startStreaming(
 	streamedResult.setInput(preparedInput)
 
 	for !streamedResult.IsComplete() {
+		if r.Config.FreezeAgents {
+			currentAgent.Freeze()
+		}
 		allTools, err := r.getAllTools(ctx, currentAgent)
 		if err != nil {
 			return err
@@ -859,9 +992,12 @@ func (r Runner) startStreaming(
 				Message: "Max turns exceeded",
 				Data:    map[string]any{"max_turns": maxTurns},
 			})
-			streamedResult.eventQueue.Put(queueCompleteSentinel{})
+			_ = streamedResult.eventQueue.PutContext(ctx, queueCompleteSentinel{})
 			break
 		}
+		if err = startAgentTurn(ctx, currentAgent); err != nil {
+			return err
+		}
 
 		if currentTurn == 1 {
 			// Run the input guardrails in the background and put the results on the queue
@@ -877,8 +1013,15 @@ func (r Runner) startStreaming(
 			})
 		}
 
-		turnResult, err := r.runSingleTurnStreamed(
+		turnCtx := ContextWithLogAttrs(
 			ctx,
+			slog.String("agent", currentAgent.Name),
+			slog.Uint64("turn", currentTurn),
+		)
+		Logger().DebugContext(turnCtx, "Running agent")
+
+		turnResult, err := r.runSingleTurnStreamed(
+			turnCtx,
 			streamedResult,
 			currentAgent,
 			hooks,
@@ -893,9 +1036,26 @@ func (r Runner) startStreaming(
 		}
 		shouldRunAgentStartHooks = false
 
-		streamedResult.appendRawResponses(turnResult.ModelResponse)
+		evictedResponses := streamedResult.log.AppendRawResponses(turnResult.ModelResponse)
 		streamedResult.setInput(turnResult.OriginalInput)
-		streamedResult.setNewItems(turnResult.GeneratedItems())
+		evictedItems := streamedResult.log.AppendStep(*turnResult)
+		if len(evictedItems) > 0 {
+			// The input of the run precedes the items first evicted.
+			sessionInput := startingInput
+			if offloadedToSession {
+				sessionInput = InputItems(nil)
+			}
+			err = r.saveResultToSession(ctx, sessionInput, &RunResult{NewItems: evictedItems})
+			if err != nil {
+				return err
+			}
+			offloadedToSession = true
+		}
+		if offload := runConfig.Retention.Offload; offload != nil && (len(evictedItems) > 0 || len(evictedResponses) > 0) {
+			if err = offload(ctx, evictedItems, evictedResponses); err != nil {
+				return fmt.Errorf("failed to offload run items: %w", err)
+			}
+		}
 
 		switch nextStep := turnResult.NextStep.(type) {
 		case NextStepFinalOutput:
@@ -931,12 +1091,20 @@ func (r Runner) startStreaming(
 				OutputGuardrailResults: streamedResult.OutputGuardrailResults(),
 				LastAgent:              currentAgent,
 			}
-			err = r.saveResultToSession(ctx, startingInput, tempResult)
+			sessionInput := startingInput
+			if offloadedToSession {
+				sessionInput = InputItems(nil)
+			}
+			err = r.saveResultToSession(ctx, sessionInput, tempResult)
+			if err != nil {
+				return err
+			}
+			err = r.rememberStreamedResult(ctx, currentAgent, startingInput, streamedResult)
 			if err != nil {
 				return err
 			}
 
-			streamedResult.eventQueue.Put(queueCompleteSentinel{})
+			_ = streamedResult.eventQueue.PutContext(ctx, queueCompleteSentinel{})
 		case NextStepHandoff:
 			currentAgent = nextStep.NewAgent
 			err = currentSpan.Finish(ctx, true)
@@ -945,7 +1113,7 @@ func (r Runner) startStreaming(
 			}
 			currentSpan = nil
 			shouldRunAgentStartHooks = true
-			streamedResult.eventQueue.Put(AgentUpdatedStreamEvent{
+			emitStreamEvent(ctx, streamedResult.eventQueue, AgentUpdatedStreamEvent{
 				NewAgent: currentAgent,
 				Type:     "agent_updated_stream_event",
 			})
@@ -1024,14 +1192,23 @@ func (r Runner) runSingleTurnStreamed(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get model: %w", err)
 	}
-	modelSettings := agent.ModelSettings.Resolve(runConfig.ModelSettings)
+	modelSettings := r.resolveModelSettings(agent, runConfig, model)
 	modelSettings = RunImpl().MaybeResetToolChoice(agent, toolUseTracker, modelSettings)
 
-	var finalResponse *ModelResponse
-
 	input := ItemHelpers().InputToNewInputList(streamedResult.Input())
-	for _, item := range streamedResult.NewItems() {
-		input = append(input, item.ToInputItem())
+	if runConfig.CallModelInputFilter == nil {
+		input = append(input, streamedResult.log.InputItems()...)
+	} else {
+		// The filter gets its own copy of the items, which it may modify.
+		input = append(input, streamedResult.log.EvictedInputItems()...)
+		for _, item := range streamedResult.NewItems() {
+			input = append(input, item.ToInputItem())
+		}
+	}
+
+	input, err = r.maybeRecallMemories(ctx, agent, runConfig, input)
+	if err != nil {
+		return nil, err
 	}
 
 	filtered, err := r.maybeFilterModelInput(
@@ -1044,8 +1221,13 @@ func (r Runner) runSingleTurnStreamed(
 	if err != nil {
 		return nil, err
 	}
+	filtered.Input, err = maybeTranscribeAudioInput(ctx, model, runConfig, filtered.Input)
+	if err != nil {
+		return nil, err
+	}
 
 	// Call hook just before the model is invoked, with the correct system prompt.
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventLLMStart, Agent: agent})
 	if agent.Hooks != nil {
 		err = agent.Hooks.OnLLMStart(ctx, agent, filtered.Instructions, filtered.Input)
 		if err != nil {
@@ -1068,42 +1250,38 @@ func (r Runner) runSingleTurnStreamed(
 		PreviousResponseID: previousResponseID,
 		Prompt:             promptConfig,
 	}
-	err = model.StreamResponse(
-		ctx, modelResponseParams,
-		func(ctx context.Context, event TResponseStreamEvent) error {
-			if event.Type == "response.completed" {
-				u := usage.NewUsage()
-				if !reflect.ValueOf(event.Response.Usage).IsZero() {
-					*u = usage.Usage{
-						Requests:            1,
-						InputTokens:         uint64(event.Response.Usage.InputTokens),
-						InputTokensDetails:  event.Response.Usage.InputTokensDetails,
-						OutputTokens:        uint64(event.Response.Usage.OutputTokens),
-						OutputTokensDetails: event.Response.Usage.OutputTokensDetails,
-						TotalTokens:         uint64(event.Response.Usage.TotalTokens),
-					}
-				}
-				finalResponse = &ModelResponse{
-					Output:     event.Response.Output,
-					Usage:      u,
-					ResponseID: event.Response.ID,
-				}
-				if contextUsage, _ := usage.FromContext(ctx); contextUsage != nil {
-					contextUsage.Add(u)
-				}
-			}
-			streamedResult.eventQueue.Put(RawResponsesStreamEvent{
-				Data: event,
-				Type: "raw_response_event",
-			})
-			return nil
-		},
-	)
+	cacheKey, finalResponse, err := getCachedResponse(ctx, agent, runConfig, model, modelResponseParams)
 	if err != nil {
 		return nil, err
 	}
+	if finalResponse != nil {
+		emitStreamEvent(ctx, streamedResult.eventQueue, RawResponsesStreamEvent{
+			Data: TResponseStreamEvent{ // responses.ResponseCompletedEvent
+				Response: responses.Response{
+					ID:     finalResponse.ResponseID,
+					Object: "response",
+					Output: finalResponse.Output,
+				},
+				Type: "response.completed",
+			},
+			Type: "raw_response_event",
+		})
+	} else {
+		finalResponse, err = r.streamModelResponse(ctx, streamedResult, agent, runConfig, model, modelResponseParams)
+		if err != nil {
+			return nil, err
+		}
+		if finalResponse != nil {
+			if err = setCachedResponse(ctx, runConfig, cacheKey, *finalResponse); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// Call hook just after the model response is finalized.
+	if finalResponse != nil {
+		publishLiveEvent(ctx, LiveEvent{Kind: LiveEventLLMEnd, Agent: agent, Response: finalResponse})
+	}
 	if agent.Hooks != nil && finalResponse != nil {
 		err = agent.Hooks.OnLLMEnd(ctx, agent, *finalResponse)
 		if err != nil {
@@ -1134,10 +1312,82 @@ func (r Runner) runSingleTurnStreamed(
 		return nil, err
 	}
 
-	RunImpl().StreamStepResultToQueue(*singleStepResult, streamedResult.eventQueue)
+	RunImpl().StreamStepResultToQueue(ctx, *singleStepResult, streamedResult.eventQueue)
 	return singleStepResult, nil
 }
 
+// streamModelResponse streams the response of the model to the queue of the
+// streamed result, returning the final response.
+func (r Runner) streamModelResponse(
+	ctx context.Context,
+	streamedResult *RunResultStreaming,
+	agent *Agent,
+	runConfig RunConfig,
+	model Model,
+	modelResponseParams ModelResponseParams,
+) (*ModelResponse, error) {
+	var finalResponse *ModelResponse
+	modelCallStartedAt := time.Now()
+	err := model.StreamResponse(
+		ctx, modelResponseParams,
+		func(ctx context.Context, event TResponseStreamEvent) error {
+			if event.Type == "response.completed" {
+				u := usage.NewUsage()
+				if !reflect.ValueOf(event.Response.Usage).IsZero() {
+					*u = usage.Usage{
+						Requests:            1,
+						InputTokens:         uint64(event.Response.Usage.InputTokens),
+						InputTokensDetails:  event.Response.Usage.InputTokensDetails,
+						OutputTokens:        uint64(event.Response.Usage.OutputTokens),
+						OutputTokensDetails: event.Response.Usage.OutputTokensDetails,
+						TotalTokens:         uint64(event.Response.Usage.TotalTokens),
+					}
+				}
+				finalResponse = &ModelResponse{
+					Output:     event.Response.Output,
+					Usage:      u,
+					ResponseID: event.Response.ID,
+				}
+				if contextUsage, _ := usage.FromContext(ctx); contextUsage != nil {
+					contextUsage.Add(u)
+				}
+			}
+			streamedResult.emitDeltaStreamEvent(ctx, RawResponsesStreamEvent{
+				Data: event,
+				Type: "raw_response_event",
+			})
+			if event.Type == "response.reasoning_summary_text.delta" {
+				streamedResult.emitDeltaStreamEvent(ctx, ReasoningSummaryDeltaStreamEvent{
+					Agent:        agent,
+					ItemID:       event.ItemID,
+					SummaryIndex: event.SummaryIndex,
+					Delta:        event.Delta,
+					Type:         "reasoning_summary_delta_event",
+				})
+			}
+			if event.Type == "response.image_generation_call.partial_image" {
+				streamedResult.emitDeltaStreamEvent(ctx, ImageGenerationPartialImageStreamEvent{
+					Agent:             agent,
+					ItemID:            event.ItemID,
+					PartialImageIndex: event.PartialImageIndex,
+					PartialImageB64:   event.PartialImageB64,
+					Type:              "image_generation_partial_image_event",
+				})
+			}
+			return nil
+		},
+	)
+	modelName := modelNameForMetrics(agent, runConfig)
+	metrics.GetRecorder().ModelCall(modelName, metrics.OutcomeOf(err), time.Since(modelCallStartedAt))
+	if err != nil {
+		return nil, asModelRateLimitError(err)
+	}
+	if finalResponse != nil && finalResponse.Usage != nil {
+		metrics.GetRecorder().TokensUsed(modelName, finalResponse.Usage.InputTokens, finalResponse.Usage.OutputTokens)
+	}
+	return finalResponse, nil
+}
+
 func (r Runner) runSingleTurn(
 	ctx context.Context,
 	agent *Agent,
@@ -1296,6 +1546,11 @@ func (Runner) getSingleStepResultFromResponse(
 
 	toolUseTracker.AddToolUse(agent, processedResponse.ToolsUsed)
 
+	toolCalls := len(processedResponse.ToolsUsed) - len(processedResponse.Handoffs)
+	if err = recordAgentResponse(ctx, agent, newResponse.Usage, toolCalls); err != nil {
+		return nil, err
+	}
+
 	return RunImpl().ExecuteToolsAndSideEffects(
 		ctx,
 		agent,
@@ -1450,6 +1705,11 @@ func (r Runner) getNewResponse(
 	previousResponseID string,
 	promptConfig responses.ResponsePromptParam,
 ) (*ModelResponse, error) {
+	input, err := r.maybeRecallMemories(ctx, agent, runConfig, input)
+	if err != nil {
+		return nil, err
+	}
+
 	// Allow user to modify model input right before the call, if configured
 	filtered, err := r.maybeFilterModelInput(
 		ctx,
@@ -1466,11 +1726,16 @@ func (r Runner) getNewResponse(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get model: %w", err)
 	}
+	filtered.Input, err = maybeTranscribeAudioInput(ctx, model, runConfig, filtered.Input)
+	if err != nil {
+		return nil, err
+	}
 
-	modelSettings := agent.ModelSettings.Resolve(runConfig.ModelSettings)
+	modelSettings := r.resolveModelSettings(agent, runConfig, model)
 	modelSettings = RunImpl().MaybeResetToolChoice(agent, toolUseTracker, modelSettings)
 
 	// If the agent has hooks, we need to call them before and after the LLM call
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventLLMStart, Agent: agent})
 	if agent.Hooks != nil {
 		err = agent.Hooks.OnLLMStart(ctx, agent, filtered.Instructions, filtered.Input)
 		if err != nil {
@@ -1478,7 +1743,7 @@ func (r Runner) getNewResponse(
 		}
 	}
 
-	newResponse, err := model.GetResponse(ctx, ModelResponseParams{
+	modelResponseParams := ModelResponseParams{
 		SystemInstructions: filtered.Instructions,
 		Input:              InputItems(filtered.Input),
 		ModelSettings:      modelSettings,
@@ -1491,12 +1756,30 @@ func (r Runner) getNewResponse(
 		),
 		PreviousResponseID: previousResponseID,
 		Prompt:             promptConfig,
-	})
+	}
+	cacheKey, newResponse, err := getCachedResponse(ctx, agent, runConfig, model, modelResponseParams)
 	if err != nil {
 		return nil, err
 	}
+	cached := newResponse != nil
+	if !cached {
+		modelCallStartedAt := time.Now()
+		newResponse, err = model.GetResponse(ctx, modelResponseParams)
+		modelName := modelNameForMetrics(agent, runConfig)
+		metrics.GetRecorder().ModelCall(modelName, metrics.OutcomeOf(err), time.Since(modelCallStartedAt))
+		if err != nil {
+			return nil, asModelRateLimitError(err)
+		}
+		if newResponse.Usage != nil {
+			metrics.GetRecorder().TokensUsed(modelName, newResponse.Usage.InputTokens, newResponse.Usage.OutputTokens)
+		}
+		if err = setCachedResponse(ctx, runConfig, cacheKey, *newResponse); err != nil {
+			return nil, err
+		}
+	}
 
 	// If the agent has hooks, we need to call them after the LLM call
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventLLMEnd, Agent: agent, Response: newResponse})
 	if agent.Hooks != nil {
 		err = agent.Hooks.OnLLMEnd(ctx, agent, *newResponse)
 		if err != nil {
@@ -1504,6 +1787,9 @@ func (r Runner) getNewResponse(
 		}
 	}
 
+	if cached {
+		return newResponse, nil
+	}
 	if newResponse.Usage == nil {
 		newResponse.Usage = &usage.Usage{Requests: 1}
 	} else {
@@ -1574,6 +1860,30 @@ func (Runner) getAllTools(ctx context.Context, agent *Agent) ([]Tool, error) {
 	return agent.GetAllTools(ctx)
 }
 
+// modelNameForMetrics returns the name of the model used by the agent, as
+// resolved by getModel, for use as a metric label.
+func modelNameForMetrics(agent *Agent, runConfig RunConfig) string {
+	var agentModel AgentModel
+	switch {
+	case runConfig.Model.Valid():
+		agentModel = runConfig.Model.Value
+	case agent.Model.Valid():
+		agentModel = agent.Model.Value
+	default:
+		return "default"
+	}
+	if name, ok := agentModel.SafeModelName(); ok {
+		return cmp.Or(name, "default")
+	}
+	return "custom"
+}
+
+// newRunID returns a new random ID identifying a run in log records.
+func newRunID() string {
+	u := uuid.New()
+	return "run_" + hex.EncodeToString(u[:])
+}
+
 func (r Runner) getModel(agent *Agent, runConfig RunConfig) (Model, error) {
 	modelProvider := runConfig.ModelProvider
 	if modelProvider == nil {
@@ -1599,6 +1909,48 @@ func (r Runner) getModel(agent *Agent, runConfig RunConfig) (Model, error) {
 	return modelProvider.GetModel("")
 }
 
+// resolveModelSettings returns the settings of a call of the agent on the
+// given model: the agent-specific settings, overridden by the global ones,
+// and then by the ones of the model in RunConfig.ModelSettingsByModel.
+func (r Runner) resolveModelSettings(agent *Agent, runConfig RunConfig, model Model) modelsettings.ModelSettings {
+	modelSettings := agent.ModelSettings.Resolve(runConfig.ModelSettings)
+	if len(runConfig.ModelSettingsByModel) == 0 {
+		return modelSettings
+	}
+	name := r.getModelName(agent, runConfig, model)
+	if override, ok := runConfig.ModelSettingsByModel[name]; ok {
+		return modelSettings.Resolve(override)
+	}
+	if _, bareName, ok := strings.Cut(name, "/"); ok {
+		if override, ok := runConfig.ModelSettingsByModel[bareName]; ok {
+			return modelSettings.Resolve(override)
+		}
+	}
+	return modelSettings
+}
+
+// getModelName returns the name of the model the agent runs on, as
+// configured, or as known by the OpenAI model instances.
+func (r Runner) getModelName(agent *Agent, runConfig RunConfig, model Model) string {
+	agentModel := agent.Model
+	if runConfig.Model.Valid() {
+		agentModel = runConfig.Model
+	}
+	if agentModel.Valid() {
+		if name, ok := agentModel.Value.SafeModelName(); ok {
+			return name
+		}
+	}
+	switch m := model.(type) {
+	case OpenAIResponsesModel:
+		return m.Model
+	case OpenAIChatCompletionsModel:
+		return m.Model
+	default:
+		return ""
+	}
+}
+
 // prepareInputWithSession prepares input by combining it with session history if enabled.
 func (r Runner) prepareInputWithSession(ctx context.Context, input Input) (Input, error) {
 	session := r.Config.Session
@@ -1624,6 +1976,13 @@ func (r Runner) prepareInputWithSession(ctx context.Context, input Input) (Input
 		return nil, fmt.Errorf("failed to get session items: %w", err)
 	}
 
+	if policy := r.Config.SessionTrimPolicy; policy != nil {
+		history, err = policy.Trim(ctx, history)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trim session items: %w", err)
+		}
+	}
+
 	// Convert input to list format
 	newInputList := ItemHelpers().InputToNewInputList(input)
 
@@ -1640,6 +1999,58 @@ func (r Runner) saveResultToSession(ctx context.Context, originalInput Input, re
 		return nil
 	}
 
+	// Save all items from this turn
+	err := session.AddItems(ctx, turnItems(originalInput, result))
+	if err != nil {
+		return fmt.Errorf("failed to add session items: %w", err)
+	}
+
+	return err
+}
+
+// longTermMemory returns the long-term memory of the agent, or else of the
+// run, if any.
+func (r Runner) longTermMemory(agent *Agent) memory.LongTermMemory {
+	var ltm memory.LongTermMemory
+	if agent != nil {
+		ltm = agent.LongTermMemory
+	}
+	return cmp.Or(ltm, r.Config.LongTermMemory)
+}
+
+// rememberItems stores the conversation items in the long-term memory of
+// the last agent, if any.
+func (r Runner) rememberItems(ctx context.Context, lastAgent *Agent, items []TResponseInputItem) error {
+	ltm := r.longTermMemory(lastAgent)
+	if ltm == nil {
+		return nil
+	}
+	err := ltm.Remember(ctx, items)
+	if err != nil {
+		return fmt.Errorf("failed to remember conversation: %w", err)
+	}
+	return nil
+}
+
+// rememberStreamedResult stores the conversation turn of a streamed run in
+// the long-term memory of the last agent, if any, including the items
+// evicted under the RunRetention. Runs whose evicted items were left out of
+// the model input are not remembered, as their beginning is lost.
+func (r Runner) rememberStreamedResult(ctx context.Context, lastAgent *Agent, originalInput Input, result *RunResultStreaming) error {
+	if r.longTermMemory(lastAgent) == nil {
+		return nil
+	}
+	if result.log.Truncated() {
+		Logger().WarnContext(ctx, "Not remembering a run whose evicted items were truncated",
+			slog.String("agent", lastAgent.Name))
+		return nil
+	}
+	items := slices.Concat(ItemHelpers().InputToNewInputList(originalInput), result.log.InputItems())
+	return r.rememberItems(ctx, lastAgent, items)
+}
+
+// turnItems returns the original input followed by the new items of a run.
+func turnItems(originalInput Input, result *RunResult) []TResponseInputItem {
 	// Convert original input to list format if needed
 	inputList := ItemHelpers().InputToNewInputList(originalInput)
 
@@ -1649,12 +2060,5 @@ func (r Runner) saveResultToSession(ctx context.Context, originalInput Input, re
 		newItemsAsInput[i] = item.ToInputItem()
 	}
 
-	// Save all items from this turn
-	itemsToSave := slices.Concat(inputList, newItemsAsInput)
-	err := session.AddItems(ctx, itemsToSave)
-	if err != nil {
-		return fmt.Errorf("failed to add session items: %w", err)
-	}
-
-	return err
+	return slices.Concat(inputList, newItemsAsInput)
 }
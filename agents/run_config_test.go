@@ -19,6 +19,7 @@ import (
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 	"github.com/openai/openai-go/v3/packages/param"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,3 +148,43 @@ func TestAgentModelObjectIsUsedWhenPresent(t *testing.T) {
 	assert.Nil(t, provider.LastRequested)
 	assert.Equal(t, "from-agent-object", result.FinalOutput)
 }
+
+func TestRunConfigModelSettingsByModelOverridesMatchingModel(t *testing.T) {
+	// Settings keyed by the model name apply on top of the agent and global
+	// settings, for the model the agent runs on only. Prefixed names fall
+	// back to their bare names.
+	for _, tc := range []struct {
+		modelName       string
+		wantTemperature float64
+	}{
+		{"gpt-4.1", 0.2},
+		{"litellm/gpt-4.1", 0.2},
+		{"o3", 0.7},
+	} {
+		t.Run(tc.modelName, func(t *testing.T) {
+			fakeModel := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+				Value: []agents.TResponseOutputItem{
+					agentstesting.GetTextMessage("done"),
+				},
+			})
+			agent := &agents.Agent{
+				Name:          "test",
+				Model:         param.NewOpt(agents.NewAgentModelName(tc.modelName)),
+				ModelSettings: modelsettings.ModelSettings{Temperature: param.NewOpt(0.5), TopP: param.NewOpt(0.9)},
+			}
+			runConfig := agents.RunConfig{
+				ModelProvider: NewDummyProvider(fakeModel),
+				ModelSettings: modelsettings.ModelSettings{Temperature: param.NewOpt(0.7)},
+				ModelSettingsByModel: map[string]modelsettings.ModelSettings{
+					"gpt-4.1": {Temperature: param.NewOpt(0.2)},
+				},
+			}
+			_, err := (agents.Runner{Config: runConfig}).Run(t.Context(), agent, "any")
+			require.NoError(t, err)
+
+			settings := fakeModel.LastTurnArgs.ModelSettings
+			assert.Equal(t, param.NewOpt(tc.wantTemperature), settings.Temperature)
+			assert.Equal(t, param.NewOpt(0.9), settings.TopP)
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package agents_test
 
 import (
+	"io"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/packages/param"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -67,3 +74,67 @@ func TestStreamedRunErrorIncludesData(t *testing.T) {
 	assert.Len(t, data.RawResponses, 1)
 	assert.NotEmpty(t, data.NewItems)
 }
+
+func rateLimitedClient(code string, header http.Header) agents.OpenaiClient {
+	return agents.NewOpenaiClient(
+		param.Opt[string]{},
+		param.NewOpt("sk-test"),
+		option.WithMaxRetries(0),
+		option.WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				header.Set("Content-Type", "application/json")
+				return &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     header,
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"slow down","type":"requests","code":"` + code + `"}}`)),
+					Request:    req,
+				}, nil
+			}),
+		}),
+	)
+}
+
+func TestRunRateLimitErrorIncludesHeadersAndData(t *testing.T) {
+	client := rateLimitedClient("rate_limit_exceeded", http.Header{
+		"Retry-After-Ms":                 {"1500"},
+		"X-Ratelimit-Remaining-Requests": {"0"},
+		"X-Ratelimit-Remaining-Tokens":   {"2500"},
+		"X-Ratelimit-Reset-Requests":     {"6m0s"},
+		"X-Ratelimit-Reset-Tokens":       {"20ms"},
+	})
+	agent := agents.New("test").WithModelInstance(agents.NewOpenAIChatCompletionsModel("gpt-4", client))
+
+	_, err := agents.Runner{Config: agents.RunConfig{TracingDisabled: true}}.Run(t.Context(), agent, "hello")
+
+	var target agents.ModelRateLimitError
+	require.ErrorAs(t, err, &target)
+	assert.False(t, target.QuotaExceeded)
+	assert.Equal(t, 1500*time.Millisecond, target.RetryAfter)
+	assert.Equal(t, int64(0), target.RemainingRequests)
+	assert.Equal(t, int64(2500), target.RemainingTokens)
+	assert.Equal(t, 6*time.Minute, target.ResetRequests)
+	assert.Equal(t, 20*time.Millisecond, target.ResetTokens)
+	require.NotNil(t, target.AgentsError.RunData)
+	assert.Same(t, agent, target.AgentsError.RunData.LastAgent)
+
+	var apiErr *openai.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+}
+
+func TestStreamedRunQuotaErrorIncludesData(t *testing.T) {
+	client := rateLimitedClient("insufficient_quota", http.Header{"Retry-After": {"2"}})
+	agent := agents.New("test").WithModelInstance(agents.NewOpenAIResponsesModel("gpt-4", client))
+
+	result, err := agents.Runner{Config: agents.RunConfig{TracingDisabled: true}}.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	err = result.StreamEvents(func(agents.StreamEvent) error { return nil })
+
+	var target agents.ModelRateLimitError
+	require.ErrorAs(t, err, &target)
+	assert.True(t, target.QuotaExceeded)
+	assert.Equal(t, 2*time.Second, target.RetryAfter)
+	assert.Equal(t, int64(-1), target.RemainingRequests)
+	require.NotNil(t, target.AgentsError.RunData)
+	assert.Same(t, agent, target.AgentsError.RunData.LastAgent)
+}
@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/nlpodyssey/openai-agents-go/asyncqueue"
 	"github.com/nlpodyssey/openai-agents-go/computer"
+	"github.com/nlpodyssey/openai-agents-go/metrics"
 	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 	"github.com/nlpodyssey/openai-agents-go/openaitypes"
 	"github.com/nlpodyssey/openai-agents-go/tracing"
@@ -269,7 +272,7 @@ func (ri runImpl) ExecuteToolsAndSideEffects(
 
 	if checkToolUse.IsFinalOutput {
 		if !checkToolUse.FinalOutput.Valid() {
-			Logger().Error("Model returned a final output of None. Not raising an error because we assume you know what you're doing.")
+			Logger().ErrorContext(ctx, "Model returned a final output of None. Not raising an error because we assume you know what you're doing.")
 		}
 
 		// If the output type is string, then let's just stringify the result
@@ -313,7 +316,23 @@ func (ri runImpl) ExecuteToolsAndSideEffects(
 	if outputType != nil && !outputType.IsPlainText() && potentialFinalOutputText != "" {
 		finalOutput, err := outputType.ValidateJSON(ctx, potentialFinalOutputText)
 		if err != nil {
-			return nil, fmt.Errorf("final output type JSON validation failed: %w", err)
+			if countOutputValidationFeedback(preStepItems) >= runConfig.OutputValidationRetries {
+				return nil, fmt.Errorf("final output type JSON validation failed: %w", err)
+			}
+			// Give the violations back to the model, to produce the output again.
+			newStepItems = append(newStepItems, OutputValidationFeedbackItem{
+				Agent:    agent,
+				Error:    err,
+				Feedback: outputValidationFeedback(err),
+				Type:     "output_validation_feedback_item",
+			})
+			return &SingleStepResult{
+				OriginalInput: originalInput,
+				ModelResponse: newResponse,
+				PreStepItems:  preStepItems,
+				NewStepItems:  newStepItems,
+				NextStep:      NextStepRunAgain{},
+			}, nil
 		}
 		return ri.ExecuteFinalOutput(
 			ctx,
@@ -348,6 +367,38 @@ func (ri runImpl) ExecuteToolsAndSideEffects(
 	}
 }
 
+func countOutputValidationFeedback(items []RunItem) int {
+	n := 0
+	for _, item := range items {
+		if _, ok := item.(OutputValidationFeedbackItem); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// outputValidationFeedback returns the message asking the model to fix its
+// invalid final output.
+func outputValidationFeedback(err error) string {
+	var sb strings.Builder
+	sb.WriteString("Your output does not match the required JSON schema.")
+	var validationErr *JSONValidationError
+	if errors.As(err, &validationErr) {
+		sb.WriteString(" Violations:\n")
+		for _, v := range validationErr.Violations {
+			_, _ = fmt.Fprintf(&sb, "- %s", v)
+			if v.Expected != "" {
+				_, _ = fmt.Fprintf(&sb, " (expected: %s)", v.Expected)
+			}
+			sb.WriteByte('\n')
+		}
+	} else {
+		_, _ = fmt.Fprintf(&sb, "\nError: %s\n", err)
+	}
+	sb.WriteString("Reply with the corrected output only.")
+	return sb.String()
+}
+
 // MaybeResetToolChoice resets tool choice to nil if the agent has used tools
 // and the agent's ResetToolChoice flag is true.
 func (runImpl) MaybeResetToolChoice(
@@ -415,9 +466,10 @@ func (runImpl) ProcessModelResponse(
 				Type:    constant.ValueOf[constant.Message](),
 			}
 			items = append(items, MessageOutputItem{
-				Agent:   agent,
-				RawItem: output,
-				Type:    "message_output_item",
+				Agent:       agent,
+				RawItem:     output,
+				Annotations: ItemHelpers().MessageAnnotations(output),
+				Type:        "message_output_item",
 			})
 		case "file_search_call":
 			output := responses.ResponseFileSearchToolCall{
@@ -507,7 +559,7 @@ func (runImpl) ProcessModelResponse(
 					MCPTool:     server,
 				})
 			} else {
-				Logger().Warn("MCP server has no OnApprovalRequest hook",
+				Logger().WarnContext(ctx, "MCP server has no OnApprovalRequest hook",
 					slog.String("serverLabel", output.ServerLabel))
 			}
 		case "mcp_list_tools":
@@ -632,7 +684,7 @@ func (runImpl) ProcessModelResponse(
 				})
 			}
 		default:
-			Logger().Warn(fmt.Sprintf("unexpected output type, ignoring %q", outputUnion.Type))
+			Logger().WarnContext(ctx, fmt.Sprintf("unexpected output type, ignoring %q", outputUnion.Type))
 		}
 	}
 
@@ -683,6 +735,7 @@ func (runImpl) ExecuteFunctionToolCalls(
 			ctx, tracing.FunctionSpanParams{Name: funcTool.Name},
 			func(ctx context.Context, spanFn tracing.Span) (err error) {
 				ctx = ContextWithToolData(ctx, toolCall.CallID, responses.ResponseFunctionToolCall(toolCall))
+				ctx = ContextWithLogAttrs(ctx, slog.String("tool", funcTool.Name), slog.String("tool_call_id", toolCall.CallID))
 				if traceIncludeSensitiveData {
 					spanFn.SpanData().(*tracing.FunctionSpanData).Input = toolCall.Arguments
 				}
@@ -730,7 +783,9 @@ func (runImpl) ExecuteFunctionToolCalls(
 				wg.Add(1)
 				go func() {
 					defer wg.Done()
+					toolStartedAt := time.Now()
 					result, toolError = funcTool.OnInvokeTool(ctx, toolCall.Arguments)
+					metrics.GetRecorder().ToolCall(funcTool.Name, metrics.OutcomeOf(toolError), time.Since(toolStartedAt))
 					if toolError != nil && errorFn == nil {
 						cancel()
 					}
@@ -950,13 +1005,45 @@ func (runImpl) ExecuteHandoffs(
 	}
 
 	actualHandoff := runHandoffs[0]
+
+	loop, err := runConfig.HandoffLoopPolicy.checkHandoffLoop(ctx, agent.Name, actualHandoff.Handoff.AgentName)
+	if err != nil {
+		return nil, err
+	}
+	if loop {
+		// Reject the handoff: the agent runs again, told to stop the loop.
+		message := runConfig.HandoffLoopPolicy.InterventionMessage
+		newStepItems = append(newStepItems, ToolCallOutputItem{
+			Agent: agent,
+			RawItem: ResponseInputItemFunctionCallOutputParam(
+				ItemHelpers().ToolCallOutputItem(actualHandoff.ToolCall, message)),
+			Output: message,
+			Type:   "tool_call_output_item",
+		})
+		return &SingleStepResult{
+			OriginalInput: originalInput,
+			ModelResponse: newResponse,
+			PreStepItems:  preStepItems,
+			NewStepItems:  newStepItems,
+			NextStep:      NextStepRunAgain{},
+		}, nil
+	}
+
+	details := &HandoffDetails{
+		ToolCall:  actualHandoff.ToolCall,
+		StartedAt: time.Now(),
+	}
+
 	var handoff Handoff
 	var newAgent *Agent
 
-	err := tracing.HandoffSpan(
+	err = tracing.HandoffSpan(
 		ctx, tracing.HandoffSpanParams{FromAgent: agent.Name},
 		func(ctx context.Context, spanHandoff tracing.Span) error {
 			handoff = actualHandoff.Handoff
+			// The input of the previous handoff is replaced by the one of
+			// this handoff, if any.
+			setHandoffInput(ctx, nil)
 			var err error
 			newAgent, err = handoff.OnInvokeHandoff(ctx, actualHandoff.ToolCall.Arguments)
 			if err != nil {
@@ -993,10 +1080,36 @@ func (runImpl) ExecuteHandoffs(
 		},
 		SourceAgent: agent,
 		TargetAgent: newAgent,
+		Details:     details,
 		Type:        "handoff_output_item",
 	})
 
-	childCtx, cancel := context.WithCancel(ctx)
+	// If there's an input filter, filter the input for the next agent
+	inputFilter := handoff.InputFilter
+	if inputFilter == nil {
+		inputFilter = runConfig.HandoffInputFilter
+	}
+	if inputFilter != nil {
+		Logger().DebugContext(ctx, "Filtering inputs for handoff")
+		handoffInputData := HandoffInputData{
+			InputHistory:    CopyInput(originalInput),
+			PreHandoffItems: slices.Clone(preStepItems),
+			NewItems:        slices.Clone(newStepItems),
+		}
+		filtered, err := inputFilter(ctx, handoffInputData)
+		if err != nil {
+			return nil, fmt.Errorf("handoff input filter error: %w", err)
+		}
+
+		originalInput = CopyInput(filtered.InputHistory)
+		preStepItems = slices.Clone(filtered.PreHandoffItems)
+		newStepItems = slices.Clone(filtered.NewItems)
+	}
+
+	details.Input = handoffInputList(originalInput, preStepItems, newStepItems)
+	details.Duration = time.Since(details.StartedAt)
+
+	childCtx, cancel := context.WithCancel(contextWithHandoffDetails(ctx, details))
 	defer cancel()
 
 	// Execute handoff hooks
@@ -1030,28 +1143,6 @@ func (runImpl) ExecuteHandoffs(
 		return nil, err
 	}
 
-	// If there's an input filter, filter the input for the next agent
-	inputFilter := handoff.InputFilter
-	if inputFilter == nil {
-		inputFilter = runConfig.HandoffInputFilter
-	}
-	if inputFilter != nil {
-		Logger().Debug("Filtering inputs for handoff")
-		handoffInputData := HandoffInputData{
-			InputHistory:    CopyInput(originalInput),
-			PreHandoffItems: slices.Clone(preStepItems),
-			NewItems:        slices.Clone(newStepItems),
-		}
-		filtered, err := inputFilter(ctx, handoffInputData)
-		if err != nil {
-			return nil, fmt.Errorf("handoff input filter error: %w", err)
-		}
-
-		originalInput = CopyInput(filtered.InputHistory)
-		preStepItems = slices.Clone(filtered.PreHandoffItems)
-		newStepItems = slices.Clone(filtered.NewItems)
-	}
-
 	return &SingleStepResult{
 		OriginalInput: originalInput,
 		ModelResponse: newResponse,
@@ -1212,6 +1303,9 @@ func (runImpl) RunSingleInputGuardrail(
 			if err != nil {
 				return err
 			}
+			if result.Output.TripwireTriggered {
+				metrics.GetRecorder().GuardrailTripped(guardrail.Name, metrics.GuardrailKindInput)
+			}
 			spanGuardrail.SpanData().(*tracing.GuardrailSpanData).Triggered = result.Output.TripwireTriggered
 			return nil
 		},
@@ -1236,6 +1330,9 @@ func (runImpl) RunSingleOutputGuardrail(
 			if err != nil {
 				return err
 			}
+			if result.Output.TripwireTriggered {
+				metrics.GetRecorder().GuardrailTripped(guardrail.Name, metrics.GuardrailKindOutput)
+			}
 			spanGuardrail.SpanData().(*tracing.GuardrailSpanData).Triggered = result.Output.TripwireTriggered
 			return nil
 		},
@@ -1244,7 +1341,7 @@ func (runImpl) RunSingleOutputGuardrail(
 	return result, err
 }
 
-func (runImpl) StreamStepResultToQueue(stepResult SingleStepResult, queue *asyncqueue.Queue[StreamEvent]) {
+func (runImpl) StreamStepResultToQueue(ctx context.Context, stepResult SingleStepResult, queue *asyncqueue.Queue[StreamEvent]) {
 	for _, item := range stepResult.NewStepItems {
 		var event StreamEvent
 
@@ -1265,6 +1362,8 @@ func (runImpl) StreamStepResultToQueue(stepResult SingleStepResult, queue *async
 			event = NewRunItemStreamEvent(StreamEventMCPApprovalRequested, item)
 		case MCPListToolsItem:
 			event = NewRunItemStreamEvent(StreamEventMCPListTools, item)
+		case OutputValidationFeedbackItem:
+			event = NewRunItemStreamEvent(StreamEventOutputValidationFailed, item)
 		// TODO: is it right not to handle MCPApprovalResponseItem here?
 		default:
 			Logger().Warn(fmt.Sprintf("Unexpected RunItem type %T", item))
@@ -1272,7 +1371,7 @@ func (runImpl) StreamStepResultToQueue(stepResult SingleStepResult, queue *async
 		}
 
 		if event != nil {
-			queue.Put(event)
+			emitStreamEvent(ctx, queue, event)
 		}
 	}
 }
@@ -0,0 +1,211 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// RunRetention bounds the items and raw responses retained by the result of
+// a streamed run, see RunConfig.Retention. The zero value retains everything.
+//
+// Items are evicted by whole turns, oldest first, so that the retained items
+// keep tool calls with their outputs and always include the last turn; the
+// retained items may therefore exceed MaxItems. Evicted items are left out of
+// RunResultStreaming.NewItems, but are still sent to the model in the
+// following turns, as input items, unless TruncateModelInput is set. Handoff
+// input filters only get the retained items. When the run has a session,
+// evicted items are added to it as they are evicted, preceded by the input of
+// the run, so that the session still receives the whole conversation, even if
+// the run fails afterwards.
+type RunRetention struct {
+	// MaxItems is the number of items retained, unlimited when zero.
+	MaxItems int
+
+	// MaxRawResponses is the number of raw responses retained, the last ones,
+	// unlimited when zero.
+	MaxRawResponses int
+
+	// TruncateModelInput also leaves the evicted items out of the input of
+	// the model in the following turns, bounding its context. The model then
+	// no longer sees the beginning of the run, and the run is not stored in
+	// the long-term memory.
+	TruncateModelInput bool
+
+	// Optional function receiving the items and raw responses as they are
+	// evicted, e.g. to save them to an artifact store. An error fails the
+	// run.
+	Offload func(ctx context.Context, items []RunItem, rawResponses []ModelResponse) error
+}
+
+// runItemLog is the append-only log of the items and raw responses of a
+// streamed run. Appending never modifies the entries already logged, so the
+// snapshots returned to the readers share the backing arrays of the log
+// instead of copying them, and each item is converted to an input item once.
+// With a RunRetention, the oldest entries are evicted from the log.
+type runItemLog struct {
+	mu         sync.RWMutex
+	retention  RunRetention
+	items      []RunItem
+	inputItems []TResponseInputItem
+	// evictedInputs is the number of leading input items whose items were
+	// evicted, the following ones being those of items.
+	evictedInputs int
+	// truncated is set once the input items of evicted items were dropped,
+	// see RunRetention.TruncateModelInput.
+	truncated    bool
+	rawResponses []ModelResponse
+	// turnSizes is the number of items of each turn in the log, the units of
+	// eviction.
+	turnSizes []int
+}
+
+// Items returns a snapshot of the items, which readers must not modify.
+func (l *runItemLog) Items() []RunItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clip(l.items)
+}
+
+// InputItems returns a snapshot of the items converted to input items,
+// including the evicted ones kept for the model, which readers must not
+// modify.
+func (l *runItemLog) InputItems() []TResponseInputItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clip(l.inputItems)
+}
+
+// EvictedInputItems returns a snapshot of the input items of the evicted
+// items kept for the model, which readers must not modify.
+func (l *runItemLog) EvictedInputItems() []TResponseInputItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clip(l.inputItems[:l.evictedInputs])
+}
+
+// Truncated reports whether the input items of evicted items were dropped,
+// InputItems then missing the beginning of the run.
+func (l *runItemLog) Truncated() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.truncated
+}
+
+// RawResponses returns a snapshot of the raw responses, which readers must
+// not modify.
+func (l *runItemLog) RawResponses() []ModelResponse {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clip(l.rawResponses)
+}
+
+// AppendStep logs the items of a turn, returning the items evicted. When its
+// pre-step items are the snapshot of the log, only the new items are
+// appended; otherwise, e.g. after a handoff input filter, the items of the
+// log are replaced.
+func (l *runItemLog) AppendStep(result SingleStepResult) []RunItem {
+	if _, handoff := result.NextStep.(NextStepHandoff); handoff || !l.isSnapshot(result.PreStepItems) {
+		items := result.GeneratedItems()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.setItems(items, len(items)-len(result.NewStepItems), len(result.NewStepItems))
+		return l.evictItems()
+	}
+	inputItems := make([]TResponseInputItem, len(result.NewStepItems))
+	for i, item := range result.NewStepItems {
+		inputItems[i] = item.ToInputItem()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = append(l.items, result.NewStepItems...)
+	l.inputItems = append(l.inputItems, inputItems...)
+	l.turnSizes = append(l.turnSizes, len(result.NewStepItems))
+	return l.evictItems()
+}
+
+// AppendRawResponses logs the raw responses, returning the raw responses
+// evicted.
+func (l *runItemLog) AppendRawResponses(responses ...ModelResponse) []ModelResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rawResponses = append(l.rawResponses, responses...)
+	n := len(l.rawResponses) - l.retention.MaxRawResponses
+	if l.retention.MaxRawResponses <= 0 || n <= 0 {
+		return nil
+	}
+	evicted := l.rawResponses[:n]
+	l.rawResponses = slices.Clone(l.rawResponses[n:])
+	return evicted
+}
+
+// setItems replaces the items of the log, made of turns of the given sizes.
+// The input items of the evicted items are kept.
+func (l *runItemLog) setItems(items []RunItem, turnSizes ...int) {
+	l.items = slices.Clone(items)
+	inputItems := make([]TResponseInputItem, l.evictedInputs, l.evictedInputs+len(items))
+	copy(inputItems, l.inputItems)
+	for _, item := range items {
+		inputItems = append(inputItems, item.ToInputItem())
+	}
+	l.inputItems = inputItems
+	l.turnSizes = l.turnSizes[:0]
+	for _, size := range turnSizes {
+		if size > 0 {
+			l.turnSizes = append(l.turnSizes, size)
+		}
+	}
+}
+
+// evictItems evicts the oldest turns beyond the retention, keeping the last
+// turn, and returns their items. The retained entries are copied, so that
+// the evicted ones are released once the snapshots sharing them are. Their
+// input items are kept, unless the model input is truncated.
+func (l *runItemLog) evictItems() []RunItem {
+	if l.retention.MaxItems <= 0 {
+		return nil
+	}
+	n, turns := 0, 0
+	for turns < len(l.turnSizes)-1 && len(l.items)-n > l.retention.MaxItems {
+		n += l.turnSizes[turns]
+		turns++
+	}
+	if n == 0 {
+		return nil
+	}
+	evicted := l.items[:n]
+	l.items = slices.Clone(l.items[n:])
+	if l.retention.TruncateModelInput {
+		l.inputItems = slices.Clone(l.inputItems[n:])
+		l.truncated = true
+	} else {
+		l.evictedInputs += n
+	}
+	l.turnSizes = slices.Delete(l.turnSizes, 0, turns)
+	return evicted
+}
+
+// isSnapshot reports whether items is a snapshot of all the items of the
+// log.
+func (l *runItemLog) isSnapshot(items []RunItem) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(items) != len(l.items) {
+		return false
+	}
+	return len(items) == 0 || &items[0] == &l.items[0]
+}
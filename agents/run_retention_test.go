@@ -0,0 +1,193 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRetention(t *testing.T) {
+	session, err := memory.NewSQLiteSession(t.Context(), memory.SQLiteSessionParams{
+		SessionID:        "test",
+		DBDataSourceName: filepath.Join(t.TempDir(), "test.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, session.Close()) })
+
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := agents.New("test").
+		WithModelInstance(model).
+		WithTools(agentstesting.GetFunctionTool("foo", "result"))
+	toolTurn := agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`)},
+	}
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		toolTurn, toolTurn, toolTurn, toolTurn,
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+
+	var offloadedItems []agents.RunItem
+	var offloadedResponses []agents.ModelResponse
+	runner := agents.Runner{Config: agents.RunConfig{
+		Session: session,
+		Retention: agents.RunRetention{
+			MaxItems:        3,
+			MaxRawResponses: 2,
+			Offload: func(_ context.Context, items []agents.RunItem, rawResponses []agents.ModelResponse) error {
+				offloadedItems = append(offloadedItems, items...)
+				offloadedResponses = append(offloadedResponses, rawResponses...)
+				return nil
+			},
+		},
+	}}
+
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+	assert.Equal(t, "done", result.FinalOutput())
+
+	// The last tool call turn and the final message are retained, the
+	// earlier turns are evicted whole.
+	newItems := result.NewItems()
+	require.Len(t, newItems, 3)
+	assert.IsType(t, agents.ToolCallItem{}, newItems[0])
+	assert.IsType(t, agents.ToolCallOutputItem{}, newItems[1])
+	assert.IsType(t, agents.MessageOutputItem{}, newItems[2])
+	assert.Len(t, offloadedItems, 6)
+	assert.Len(t, result.RawResponses(), 2)
+	assert.Len(t, offloadedResponses, 3)
+
+	// The model still receives the evicted items.
+	assert.Len(t, model.LastTurnArgs.Input, 9)
+
+	// The session receives the whole conversation.
+	items, err := session.GetItems(t.Context(), 0)
+	require.NoError(t, err)
+	assert.Len(t, items, 10)
+}
+
+func TestRunRetentionOffloadError(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := agents.New("test").
+		WithModelInstance(model).
+		WithTools(agentstesting.GetFunctionTool("foo", "result"))
+	toolTurn := agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`)},
+	}
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		toolTurn, toolTurn,
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+
+	runner := agents.Runner{Config: agents.RunConfig{
+		Retention: agents.RunRetention{
+			MaxItems: 1,
+			Offload: func(context.Context, []agents.RunItem, []agents.ModelResponse) error {
+				return assert.AnError
+			},
+		},
+	}}
+
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	err = result.StreamEvents(func(agents.StreamEvent) error { return nil })
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRunRetentionTruncateModelInput(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	agent := agents.New("test").
+		WithModelInstance(model).
+		WithTools(agentstesting.GetFunctionTool("foo", "result"))
+	toolTurn := agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`)},
+	}
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		toolTurn, toolTurn, toolTurn, toolTurn,
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+
+	runner := agents.Runner{Config: agents.RunConfig{
+		Retention: agents.RunRetention{MaxItems: 3, TruncateModelInput: true},
+	}}
+
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+	assert.Equal(t, "done", result.FinalOutput())
+
+	// The model only receives the input and the retained items.
+	assert.Len(t, model.LastTurnArgs.Input, 3)
+	assert.Len(t, result.NewItems(), 3)
+}
+
+// recordingMemory is a LongTermMemory recording the remembered items.
+type recordingMemory struct {
+	remembered []agents.TResponseInputItem
+}
+
+func (m *recordingMemory) Remember(_ context.Context, items []agents.TResponseInputItem) error {
+	m.remembered = append(m.remembered, items...)
+	return nil
+}
+
+func (m *recordingMemory) Recall(context.Context, []agents.TResponseInputItem) ([]agents.TResponseInputItem, error) {
+	return nil, nil
+}
+
+func TestRunRetentionLongTermMemory(t *testing.T) {
+	for _, truncate := range []bool{false, true} {
+		model := agentstesting.NewFakeModel(false, nil)
+		ltm := &recordingMemory{}
+		agent := agents.New("test").
+			WithModelInstance(model).
+			WithTools(agentstesting.GetFunctionTool("foo", "result")).
+			WithLongTermMemory(ltm)
+		toolTurn := agentstesting.FakeModelTurnOutput{
+			Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", `{"a": "b"}`)},
+		}
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			toolTurn, toolTurn, toolTurn, toolTurn,
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+
+		runner := agents.Runner{Config: agents.RunConfig{
+			Retention: agents.RunRetention{MaxItems: 3, TruncateModelInput: truncate},
+		}}
+
+		result, err := runner.RunStreamed(t.Context(), agent, "hello")
+		require.NoError(t, err)
+		require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+		assert.Len(t, result.NewItems(), 3)
+		if truncate {
+			// The beginning of the run is lost.
+			assert.Empty(t, ltm.remembered)
+			continue
+		}
+		// The memory receives the whole conversation, evicted items included.
+		require.Len(t, ltm.remembered, 10)
+		assert.Equal(t, "hello", ltm.remembered[0].OfMessage.Content.OfString.Value)
+		assert.NotNil(t, ltm.remembered[1].OfFunctionCall)
+		assert.NotNil(t, ltm.remembered[9].OfOutputMessage)
+	}
+}
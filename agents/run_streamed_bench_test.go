@@ -0,0 +1,96 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// deltaStreamingModel streams each reply as text deltas, calling the "echo"
+// tool for the first toolTurns turns of each run.
+type deltaStreamingModel struct {
+	deltas    int
+	toolTurns int
+	turn      int
+}
+
+func (m *deltaStreamingModel) GetResponse(context.Context, agents.ModelResponseParams) (*agents.ModelResponse, error) {
+	panic("not implemented")
+}
+
+func (m *deltaStreamingModel) StreamResponse(ctx context.Context, _ agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	for i := range m.deltas {
+		err := yield(ctx, agents.TResponseStreamEvent{ // responses.ResponseTextDeltaEvent
+			Type:           "response.output_text.delta",
+			Delta:          "token ",
+			SequenceNumber: int64(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	output := agentstesting.GetTextMessage("done")
+	if m.turn < m.toolTurns {
+		output = agentstesting.GetFunctionToolCall("echo", `{"text": "hello"}`)
+	}
+	m.turn++
+	return yield(ctx, agents.TResponseStreamEvent{ // responses.ResponseCompletedEvent
+		Type: "response.completed",
+		Response: responses.Response{
+			ID:     "resp",
+			Output: []responses.ResponseOutputItemUnion{output},
+			Usage:  responses.ResponseUsage{InputTokens: 1, OutputTokens: 1, TotalTokens: 2},
+		},
+		SequenceNumber: int64(m.deltas),
+	})
+}
+
+func BenchmarkRunStreamed(b *testing.B) {
+	benchmarks := []struct {
+		name              string
+		deltas, toolTurns int
+	}{
+		{"text", 500, 0},
+		{"tools", 10, 60},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			model := &deltaStreamingModel{deltas: bm.deltas, toolTurns: bm.toolTurns}
+			agent := &agents.Agent{
+				Name:  "bench",
+				Model: param.NewOpt(agents.NewAgentModel(model)),
+				Tools: []agents.Tool{agentstesting.GetFunctionTool("echo", "hello")},
+			}
+			runner := agents.Runner{Config: agents.RunConfig{MaxTurns: 100}}
+			b.ReportAllocs()
+			for b.Loop() {
+				model.turn = 0
+				result, err := runner.RunStreamed(b.Context(), agent, "hello")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := result.StreamEvents(func(agents.StreamEvent) error { return nil }); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
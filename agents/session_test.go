@@ -15,8 +15,10 @@
 package agents_test
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
@@ -173,6 +175,79 @@ func TestAgentSession(t *testing.T) {
 				assert.Equal(t, "I like dogs", lastInput.(agents.InputItems)[0].OfMessage.Content.OfString.Value)
 			})
 
+			t.Run("trim policy", func(t *testing.T) {
+				// Test that the session trim policy is applied to the retrieved history.
+				session, err := memory.NewSQLiteSession(t.Context(), memory.SQLiteSessionParams{
+					SessionID:        "test",
+					DBDataSourceName: filepath.Join(t.TempDir(), "test.db"),
+				})
+				require.NoError(t, err)
+				t.Cleanup(func() { assert.NoError(t, session.Close()) })
+
+				model := agentstesting.NewFakeModel(false, nil)
+				agent := agents.New("test").WithModelInstance(model)
+
+				model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+					Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("Paris")},
+				})
+				runAgent(t, streaming, session, agent, "What is the capital of France?")
+
+				runner := agents.Runner{
+					Config: agents.RunConfig{
+						Session: session,
+						SessionTrimPolicy: memory.TokenBudgetTrimPolicy{
+							MaxTokens:    1,
+							TokenCounter: func(memory.TResponseInputItem) int { return 1 },
+						},
+					},
+				}
+				model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+					Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("Rome")},
+				})
+				if streaming {
+					result, err := runner.RunStreamed(t.Context(), agent, "And of Italy?")
+					require.NoError(t, err)
+					require.NoError(t, result.StreamEvents(func(agents.StreamEvent) error { return nil }))
+				} else {
+					_, err := runner.Run(t.Context(), agent, "And of Italy?")
+					require.NoError(t, err)
+				}
+
+				// Only the last history item fits the budget, plus the new input.
+				lastInput := model.LastTurnArgs.Input
+				require.IsType(t, agents.InputItems{}, lastInput)
+				assert.Len(t, lastInput.(agents.InputItems), 2)
+			})
+
+			t.Run("long-term memory", func(t *testing.T) {
+				// Test that memories from a previous run are recalled without a session.
+				model := agentstesting.NewFakeModel(false, nil)
+				agent := agents.New("test").
+					WithModelInstance(model).
+					WithLongTermMemory(memory.VectorMemory{
+						Embedder: keywordEmbedder{"france", "italy"},
+						Store:    memory.NewInMemoryVectorStore(),
+						TopK:     1,
+					})
+
+				model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+					Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("Paris is the capital of France")},
+				})
+				runAgent(t, streaming, nil, agent, "What is the capital of France?")
+
+				model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+					Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("Yes")},
+				})
+				runAgent(t, streaming, nil, agent, "Do you remember France?")
+
+				lastInput := model.LastTurnArgs.Input
+				require.IsType(t, agents.InputItems{}, lastInput)
+				items := lastInput.(agents.InputItems)
+				require.Len(t, items, 2)
+				assert.Equal(t, responses.EasyInputMessageRoleDeveloper, items[0].OfMessage.Role)
+				assert.Contains(t, items[0].OfMessage.Content.OfString.Value, "What is the capital of France?")
+			})
+
 			t.Run("cannot use both session and list input items", func(t *testing.T) {
 				// Test that passing both a session and list input raises a UserError.
 				session, err := memory.NewSQLiteSession(t.Context(), memory.SQLiteSessionParams{
@@ -222,3 +297,19 @@ func TestAgentSession(t *testing.T) {
 		})
 	}
 }
+
+// keywordEmbedder embeds a text as the presence of each of its keywords.
+type keywordEmbedder []string
+
+func (e keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(e))
+		for j, keyword := range e {
+			if strings.Contains(strings.ToLower(text), keyword) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents
+
+import (
+	"context"
+	"strings"
+)
+
+// StreamBuffer bounds the events of a streamed run waiting to be consumed
+// by RunResultStreaming.StreamEvents, so that a slow consumer cannot make
+// them grow without bounds. The zero value does not bound them.
+//
+// When the buffer is full, the run waits for the consumer, slowing down the
+// streaming of the model response, unless DropDeltas is set. The wait ends
+// when the run is canceled, through its context or
+// RunResultStreaming.Cancel, so a run whose events are never consumed does
+// not block forever.
+type StreamBuffer struct {
+	// Size is the number of events buffered, unlimited when zero.
+	Size int
+
+	// DropDeltas drops the raw delta events (such as
+	// "response.output_text.delta"), the reasoning summary deltas and the
+	// partial images of image generation calls when the buffer is full,
+	// instead of waiting. The other events, such as the run items and the
+	// completed responses, are never dropped. See
+	// RunResultStreaming.DroppedEvents.
+	DropDeltas bool
+}
+
+// isDeltaStreamEvent reports whether the event is a delta which can be
+// dropped by StreamBuffer.DropDeltas.
+func isDeltaStreamEvent(event StreamEvent) bool {
+	switch ev := event.(type) {
+	case RawResponsesStreamEvent:
+		return strings.HasSuffix(ev.Data.Type, ".delta") ||
+			ev.Data.Type == "response.image_generation_call.partial_image"
+	case ReasoningSummaryDeltaStreamEvent, ImageGenerationPartialImageStreamEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// emitDeltaStreamEvent emits an event which may be a delta, dropping it if
+// the buffer of the run is full and its StreamBuffer drops the deltas.
+func (r *RunResultStreaming) emitDeltaStreamEvent(ctx context.Context, event StreamEvent) {
+	if !r.dropDeltas || !isDeltaStreamEvent(event) {
+		emitStreamEvent(ctx, r.eventQueue, event)
+		return
+	}
+	if !r.eventQueue.TryPut(event) {
+		r.droppedEvents.Add(1)
+		return
+	}
+	publishLiveEvent(ctx, LiveEvent{Kind: LiveEventStream, StreamEvent: event})
+}
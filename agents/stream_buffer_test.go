@@ -0,0 +1,110 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamBufferDropDeltas(t *testing.T) {
+	agent := agents.New("test").WithModelInstance(&deltaStreamingModel{deltas: 100})
+	runner := agents.Runner{Config: agents.RunConfig{
+		StreamBuffer: agents.StreamBuffer{Size: 4, DropDeltas: true},
+	}}
+
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+
+	var deltas, completed, items int
+	require.NoError(t, result.StreamEvents(func(event agents.StreamEvent) error {
+		// A slow consumer.
+		time.Sleep(time.Millisecond)
+		switch ev := event.(type) {
+		case agents.RawResponsesStreamEvent:
+			if ev.Data.Type == "response.completed" {
+				completed++
+			} else {
+				deltas++
+			}
+		case agents.RunItemStreamEvent:
+			items++
+		}
+		return nil
+	}))
+
+	assert.Equal(t, "done", result.FinalOutput())
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 1, items)
+	assert.Less(t, deltas, 100)
+	assert.Equal(t, uint64(100-deltas), result.DroppedEvents())
+}
+
+func TestStreamBufferWaitsForConsumer(t *testing.T) {
+	agent := agents.New("test").WithModelInstance(&deltaStreamingModel{deltas: 50})
+	runner := agents.Runner{Config: agents.RunConfig{
+		StreamBuffer: agents.StreamBuffer{Size: 2},
+	}}
+
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+
+	var deltas int
+	require.NoError(t, result.StreamEvents(func(event agents.StreamEvent) error {
+		if ev, ok := event.(agents.RawResponsesStreamEvent); ok && ev.Data.Type == "response.output_text.delta" {
+			deltas++
+		}
+		return nil
+	}))
+	assert.Equal(t, 50, deltas)
+	assert.Zero(t, result.DroppedEvents())
+	assert.Equal(t, "done", result.FinalOutput())
+
+	// A consumer stopping early does not block the run.
+	result, err = runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	stop := errors.New("stop")
+	assert.ErrorIs(t, result.StreamEvents(func(agents.StreamEvent) error { return stop }), stop)
+	require.Eventually(t, result.IsComplete, time.Second, time.Millisecond)
+}
+
+func TestStreamBufferCanceledRun(t *testing.T) {
+	agent := agents.New("test").WithModelInstance(&deltaStreamingModel{deltas: 50})
+	runner := agents.Runner{Config: agents.RunConfig{
+		StreamBuffer: agents.StreamBuffer{Size: 2},
+	}}
+
+	// The events are never consumed: the run waits until it is canceled.
+	result, err := runner.RunStreamed(t.Context(), agent, "hello")
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, result.IsComplete())
+
+	canceled := make(chan struct{})
+	go func() {
+		result.Cancel()
+		close(canceled)
+	}()
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the canceled run is still waiting for the consumer")
+	}
+}
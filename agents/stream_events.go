@@ -58,14 +58,15 @@ func NewRunItemStreamEvent(name RunItemStreamEventName, item RunItem) RunItemStr
 type RunItemStreamEventName string
 
 const (
-	StreamEventMessageOutputCreated RunItemStreamEventName = "message_output_created"
-	StreamEventHandoffRequested     RunItemStreamEventName = "handoff_requested"
-	StreamEventHandoffOccurred      RunItemStreamEventName = "handoff_occurred"
-	StreamEventToolCalled           RunItemStreamEventName = "tool_called"
-	StreamEventToolOutput           RunItemStreamEventName = "tool_output"
-	StreamEventReasoningItemCreated RunItemStreamEventName = "reasoning_item_created"
-	StreamEventMCPApprovalRequested RunItemStreamEventName = "mcp_approval_requested"
-	StreamEventMCPListTools         RunItemStreamEventName = "mcp_list_tools"
+	StreamEventMessageOutputCreated   RunItemStreamEventName = "message_output_created"
+	StreamEventHandoffRequested       RunItemStreamEventName = "handoff_requested"
+	StreamEventHandoffOccurred        RunItemStreamEventName = "handoff_occurred"
+	StreamEventToolCalled             RunItemStreamEventName = "tool_called"
+	StreamEventToolOutput             RunItemStreamEventName = "tool_output"
+	StreamEventReasoningItemCreated   RunItemStreamEventName = "reasoning_item_created"
+	StreamEventMCPApprovalRequested   RunItemStreamEventName = "mcp_approval_requested"
+	StreamEventMCPListTools           RunItemStreamEventName = "mcp_list_tools"
+	StreamEventOutputValidationFailed RunItemStreamEventName = "output_validation_failed"
 )
 
 // AgentUpdatedStreamEvent is an event that notifies that there is a new agent running.
@@ -78,3 +79,49 @@ type AgentUpdatedStreamEvent struct {
 }
 
 func (AgentUpdatedStreamEvent) isStreamEvent() {}
+
+// ReasoningSummaryDeltaStreamEvent is a delta of the reasoning summary of a
+// reasoning model, e.g. so that UIs can render the "thinking" progress apart
+// from the answer. The complete summary is in the ReasoningItem of the
+// `reasoning_item_created` event which follows.
+type ReasoningSummaryDeltaStreamEvent struct {
+	// The agent whose model is reasoning.
+	Agent *Agent
+
+	// The ID of the reasoning item the summary belongs to.
+	ItemID string
+
+	// The index of the summary part the delta belongs to.
+	SummaryIndex int64
+
+	// The text added to the summary.
+	Delta string
+
+	// Always `reasoning_summary_delta_event`.
+	Type string
+}
+
+func (ReasoningSummaryDeltaStreamEvent) isStreamEvent() {}
+
+// ImageGenerationPartialImageStreamEvent is a partial image streamed while an
+// image generation tool call is in progress, when requested through the
+// PartialImages of ImageGenerationTool.ToolConfig. The final image is in the
+// ToolCallItem of the call.
+type ImageGenerationPartialImageStreamEvent struct {
+	// The agent whose model is generating the image.
+	Agent *Agent
+
+	// The ID of the image generation call item.
+	ItemID string
+
+	// The 0-based index of the partial image.
+	PartialImageIndex int64
+
+	// The partial image, base64 encoded.
+	PartialImageB64 string
+
+	// Always `image_generation_partial_image_event`.
+	Type string
+}
+
+func (ImageGenerationPartialImageStreamEvent) isStreamEvent() {}
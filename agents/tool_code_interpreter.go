@@ -15,7 +15,13 @@
 package agents
 
 import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/openai/openai-go/v3/packages/param"
 	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared/constant"
 )
 
 // CodeInterpreterTool is a tool that allows the LLM to execute code in a sandboxed environment.
@@ -29,3 +35,54 @@ func (t CodeInterpreterTool) ToolName() string {
 }
 
 func (t CodeInterpreterTool) isTool() {}
+
+// CodeInterpreterContainerID returns the container configuration of a code
+// interpreter running in the existing container with the given ID.
+func CodeInterpreterContainerID(id string) responses.ToolCodeInterpreterContainerUnionParam {
+	return responses.ToolCodeInterpreterContainerUnionParam{OfString: param.NewOpt(id)}
+}
+
+// CodeInterpreterAutoContainer returns the container configuration of a code
+// interpreter running in a new container, with the given uploaded files
+// available to the code. The memory limit ("1g", "4g", "16g" or "64g") is
+// the default of the API when empty.
+func CodeInterpreterAutoContainer(memoryLimit string, fileIDs ...string) responses.ToolCodeInterpreterContainerUnionParam {
+	return responses.ToolCodeInterpreterContainerUnionParam{
+		OfCodeInterpreterToolAuto: &responses.ToolCodeInterpreterContainerCodeInterpreterContainerAutoParam{
+			MemoryLimit: memoryLimit,
+			FileIDs:     fileIDs,
+			Type:        constant.ValueOf[constant.Auto](),
+		},
+	}
+}
+
+// ContainerFiles returns the annotations of the message citing the files
+// generated by the code interpreter, whose content can be retrieved with
+// ContainerFileContent.
+func (itemHelpers) ContainerFiles(message MessageOutputItem) []Annotation {
+	var files []Annotation
+	for _, annotation := range message.Annotations {
+		if annotation.Type == AnnotationContainerFileCitation {
+			files = append(files, annotation)
+		}
+	}
+	return files
+}
+
+// ContainerFileContent downloads a file generated by the code interpreter in
+// a container. The default OpenAI client is used when client is nil.
+func ContainerFileContent(ctx context.Context, client *OpenaiClient, containerID, fileID string) ([]byte, error) {
+	if client == nil {
+		client = resolveDefaultOpenaiClient()
+	}
+	resp, err := client.Containers.Files.Content.Get(ctx, containerID, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download container file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeInterpreterContainers(t *testing.T) {
+	data, err := json.Marshal(agents.CodeInterpreterContainerID("cntr_1"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"cntr_1"`, string(data))
+
+	data, err = json.Marshal(agents.CodeInterpreterAutoContainer("4g", "file-1", "file-2"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "auto", "memory_limit": "4g", "file_ids": ["file-1", "file-2"]}`, string(data))
+}
+
+func TestContainerFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/cntr_1/files/cfile_1/content" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("a,b\n1,2\n"))
+	}))
+	t.Cleanup(server.Close)
+	client := agents.NewOpenaiClient(param.NewOpt(server.URL), param.NewOpt("test_key"))
+
+	message := agents.MessageOutputItem{
+		Annotations: []agents.Annotation{
+			{Type: agents.AnnotationURLCitation, URL: "https://example.com"},
+			{Type: agents.AnnotationContainerFileCitation, ContainerID: "cntr_1", FileID: "cfile_1", Filename: "data.csv"},
+		},
+	}
+	files := agents.ItemHelpers().ContainerFiles(message)
+	require.Len(t, files, 1)
+	assert.Equal(t, "data.csv", files[0].Filename)
+
+	content, err := agents.ContainerFileContent(t.Context(), &client, files[0].ContainerID, files[0].FileID)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(content))
+
+	_, err = agents.ContainerFileContent(t.Context(), &client, "cntr_1", "missing")
+	assert.Error(t, err)
+}
@@ -16,6 +16,7 @@ package agents
 
 import (
 	"context"
+	"slices"
 
 	"github.com/nlpodyssey/openai-agents-go/computer"
 	"github.com/openai/openai-go/v3/responses"
@@ -28,10 +29,37 @@ type ComputerTool struct {
 	// like click, screenshot, etc.
 	Computer computer.Computer
 
-	// Optional callback to acknowledge computer tool safety checks.
+	// Optional environment declared to the model, overriding the one
+	// described by the Computer.
+	Environment computer.Environment
+
+	// Optional display size declared to the model, overriding the one
+	// described by the Computer. It is used only when both its width and
+	// height are set.
+	Dimensions computer.Dimensions
+
+	// Optional callback to acknowledge computer tool safety checks. The run
+	// fails when a pending safety check is not acknowledged, see
+	// AcknowledgeSafetyChecks.
 	OnSafetyCheck func(context.Context, ComputerToolSafetyCheckData) (bool, error)
 }
 
+// environment returns the environment declared to the model.
+func (t ComputerTool) environment(ctx context.Context) (computer.Environment, error) {
+	if t.Environment != "" {
+		return t.Environment, nil
+	}
+	return t.Computer.Environment(ctx)
+}
+
+// dimensions returns the display size declared to the model.
+func (t ComputerTool) dimensions(ctx context.Context) (computer.Dimensions, error) {
+	if t.Dimensions.Width > 0 && t.Dimensions.Height > 0 {
+		return t.Dimensions, nil
+	}
+	return t.Computer.Dimensions(ctx)
+}
+
 func (t ComputerTool) ToolName() string {
 	return "computer_use_preview"
 }
@@ -49,3 +77,12 @@ type ComputerToolSafetyCheckData struct {
 	// The pending safety check to acknowledge.
 	SafetyCheck responses.ResponseComputerToolCallPendingSafetyCheck
 }
+
+// AcknowledgeSafetyChecks returns a ComputerTool.OnSafetyCheck callback
+// acknowledging the safety checks with one of the given codes, or all of them
+// when no code is given.
+func AcknowledgeSafetyChecks(codes ...string) func(context.Context, ComputerToolSafetyCheckData) (bool, error) {
+	return func(_ context.Context, data ComputerToolSafetyCheckData) (bool, error) {
+		return len(codes) == 0 || slices.Contains(codes, data.SafetyCheck.Code), nil
+	}
+}
@@ -20,7 +20,10 @@ import (
 
 // ImageGenerationTool is a tool that allows the LLM to generate images.
 type ImageGenerationTool struct {
-	// The tool config, which image generation settings.
+	// The tool config, which image generation settings, such as the Size,
+	// Quality, OutputFormat and Background of the images. With
+	// PartialImages (1 to 3), streamed runs emit the partial images as
+	// ImageGenerationPartialImageStreamEvent while they are generated.
 	ToolConfig responses.ToolImageGenerationParam
 }
 
@@ -0,0 +1,75 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialImageModel streams partial images before the response of the fake
+// model.
+type partialImageModel struct {
+	*agentstesting.FakeModel
+	partialImages []string
+}
+
+func (m partialImageModel) StreamResponse(ctx context.Context, params agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	for i, image := range m.partialImages {
+		err := yield(ctx, agents.TResponseStreamEvent{
+			Type:              "response.image_generation_call.partial_image",
+			ItemID:            "ig_1",
+			PartialImageIndex: int64(i),
+			PartialImageB64:   image,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return m.FakeModel.StreamResponse(ctx, params, yield)
+}
+
+func TestImageGenerationPartialImageStreamEvents(t *testing.T) {
+	fake := agentstesting.NewFakeModel(false, nil)
+	fake.SetNextOutput(agentstesting.FakeModelTurnOutput{Value: []agents.TResponseOutputItem{
+		agentstesting.GetTextMessage("done"),
+	}})
+	agent := agents.New("test").WithModelInstance(partialImageModel{FakeModel: fake, partialImages: []string{"aW1n", "aW1hZ2U="}})
+
+	result, err := agents.Runner{}.RunStreamed(t.Context(), agent, "draw")
+	require.NoError(t, err)
+	var partials []agents.ImageGenerationPartialImageStreamEvent
+	err = result.StreamEvents(func(event agents.StreamEvent) error {
+		if e, ok := event.(agents.ImageGenerationPartialImageStreamEvent); ok {
+			partials = append(partials, e)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, partials, 2)
+	for i, image := range []string{"aW1n", "aW1hZ2U="} {
+		assert.Same(t, agent, partials[i].Agent)
+		assert.Equal(t, "ig_1", partials[i].ItemID)
+		assert.Equal(t, int64(i), partials[i].PartialImageIndex)
+		assert.Equal(t, image, partials[i].PartialImageB64)
+		assert.Equal(t, "image_generation_partial_image_event", partials[i].Type)
+	}
+}
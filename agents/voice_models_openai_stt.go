@@ -320,9 +320,9 @@ func (s *OpenAISTTTranscriptionSession) setupConnection(ctx context.Context, c *
 		return err
 	}
 	if DontLogModelData {
-		Logger().Debug("Session updated")
+		Logger().DebugContext(ctx, "Session updated")
 	} else {
-		Logger().Debug("Session updated", slog.Any("event", event))
+		Logger().DebugContext(ctx, "Session updated", slog.Any("event", event))
 	}
 	return nil
 }
@@ -439,7 +439,7 @@ func (s *OpenAISTTTranscriptionSession) processWebsocketConnection(ctx context.C
 	s.connected = true
 
 	if s.listenerTask == nil {
-		Logger().Error("Listener task not initialized")
+		Logger().ErrorContext(ctx, "Listener task not initialized")
 		return NewAgentsError("listener task not initialized")
 	}
 
@@ -155,7 +155,7 @@ func (p *VoicePipeline) runSingleTurn(ctx context.Context, audioInput AudioInput
 			output.createTextGenerationTask(ctx, func(ctx context.Context) (err error) {
 				defer func() {
 					if err != nil {
-						Logger().Error("Error processing single turn", slog.String("error", err.Error()))
+						Logger().ErrorContext(ctx, "Error processing single turn", slog.String("error", err.Error()))
 						output.addError(err)
 					}
 				}()
@@ -203,7 +203,7 @@ func (p *VoicePipeline) runMultiTurn(ctx context.Context, audioInput StreamedAud
 				}
 			}
 			if err := onStartResult.Error(); err != nil {
-				Logger().Error("OnStart() failed", slog.String("error", err.Error()))
+				Logger().ErrorContext(ctx, "OnStart() failed", slog.String("error", err.Error()))
 			}
 
 			transcriptionSession, err := p.sttModel.CreateSession(ctx, STTModelCreateSessionParams{
@@ -219,7 +219,7 @@ func (p *VoicePipeline) runMultiTurn(ctx context.Context, audioInput StreamedAud
 			output.createTextGenerationTask(ctx, func(ctx context.Context) (err error) {
 				defer func() {
 					if err != nil {
-						Logger().Error("Error processing turns", slog.String("error", err.Error()))
+						Logger().ErrorContext(ctx, "Error processing turns", slog.String("error", err.Error()))
 						output.addError(err)
 					}
 					if e := transcriptionSession.Close(ctx); e != nil {
@@ -0,0 +1,192 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentstesting
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// ErrInjectedFault is the default error of the FaultError faults.
+var ErrInjectedFault = errors.New("injected tool fault")
+
+// DefaultMalformedOutput is the default output of the FaultMalformedOutput
+// faults: truncated JSON.
+const DefaultMalformedOutput = `{"result": "trunc`
+
+// FaultKind is the kind of a Fault.
+type FaultKind string
+
+const (
+	// FaultError fails the tool call with an error.
+	FaultError FaultKind = "error"
+	// FaultTimeout waits for the delay of the fault, or for the end of the
+	// context, and fails the tool call with context.DeadlineExceeded.
+	FaultTimeout FaultKind = "timeout"
+	// FaultMalformedOutput returns a malformed output instead of calling the
+	// tool.
+	FaultMalformedOutput FaultKind = "malformed_output"
+)
+
+// Fault is a failure injected in a tool call.
+type Fault struct {
+	Kind FaultKind
+
+	// Optional error of FaultError. Default: ErrInjectedFault.
+	Err error
+
+	// Delay of FaultTimeout.
+	Delay time.Duration
+
+	// Optional output of FaultMalformedOutput. Default: DefaultMalformedOutput.
+	Output any
+}
+
+// FaultRule injects a fault in the matching calls of the tools.
+type FaultRule struct {
+	// Optional name of the tool. Default: all the tools.
+	Tool string
+
+	// Optional numbers of the calls of the tool to fail, starting from 1.
+	Calls []int
+
+	// Optional probability of failing the matching calls. Default (zero):
+	// all the matching calls fail.
+	Probability float64
+
+	Fault Fault
+}
+
+// InjectedFault records a fault injected by a FaultInjector.
+type InjectedFault struct {
+	Tool string
+	// Number of the call of the tool, starting from 1.
+	Call int
+	Kind FaultKind
+}
+
+// FaultInjector wraps function tools, injecting faults in their calls
+// according to its rules, to exercise the error handling of the agents:
+//
+//	injector := &agentstesting.FaultInjector{Rules: []agentstesting.FaultRule{
+//		{Tool: "get_weather", Calls: []int{1}, Fault: agentstesting.Fault{Kind: agentstesting.FaultTimeout}},
+//		{Probability: 0.1, Fault: agentstesting.Fault{Kind: agentstesting.FaultError}},
+//	}}
+//	agent.Tools = injector.WrapTools(agent.Tools)
+//
+// The first matching rule applies. A FaultInjector is safe for concurrent
+// use.
+type FaultInjector struct {
+	Rules []FaultRule
+
+	// Optional source of the probabilities, e.g. seeded for reproducible
+	// tests. Default: the global source of math/rand/v2.
+	Rand *rand.Rand
+
+	mu       sync.Mutex
+	calls    map[string]int
+	injected []InjectedFault
+}
+
+// WrapTools returns the tools with the function tools wrapped, see WrapTool.
+// The other tools are returned as they are.
+func (fi *FaultInjector) WrapTools(tools []agents.Tool) []agents.Tool {
+	wrapped := make([]agents.Tool, len(tools))
+	for i, tool := range tools {
+		if functionTool, ok := tool.(agents.FunctionTool); ok {
+			tool = fi.WrapTool(functionTool)
+		}
+		wrapped[i] = tool
+	}
+	return wrapped
+}
+
+// WrapTool returns the tool with faults injected in its calls.
+func (fi *FaultInjector) WrapTool(tool agents.FunctionTool) agents.FunctionTool {
+	onInvokeTool := tool.OnInvokeTool
+	tool.OnInvokeTool = func(ctx context.Context, arguments string) (any, error) {
+		fault, ok := fi.nextFault(tool.Name)
+		if !ok {
+			return onInvokeTool(ctx, arguments)
+		}
+		switch fault.Kind {
+		case FaultTimeout:
+			timer := time.NewTimer(fault.Delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+			return nil, context.DeadlineExceeded
+		case FaultMalformedOutput:
+			if fault.Output == nil {
+				return DefaultMalformedOutput, nil
+			}
+			return fault.Output, nil
+		default:
+			if fault.Err == nil {
+				return nil, ErrInjectedFault
+			}
+			return nil, fault.Err
+		}
+	}
+	return tool
+}
+
+// Injected returns the faults injected so far, in order.
+func (fi *FaultInjector) Injected() []InjectedFault {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return slices.Clone(fi.injected)
+}
+
+// nextFault counts a call of the tool, and returns its fault, if any.
+func (fi *FaultInjector) nextFault(toolName string) (Fault, bool) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if fi.calls == nil {
+		fi.calls = make(map[string]int)
+	}
+	fi.calls[toolName]++
+	call := fi.calls[toolName]
+
+	for _, rule := range fi.Rules {
+		if rule.Tool != "" && rule.Tool != toolName {
+			continue
+		}
+		if len(rule.Calls) > 0 && !slices.Contains(rule.Calls, call) {
+			continue
+		}
+		if rule.Probability > 0 && fi.float64() >= rule.Probability {
+			continue
+		}
+		fi.injected = append(fi.injected, InjectedFault{Tool: toolName, Call: call, Kind: rule.Fault.Kind})
+		return rule.Fault, true
+	}
+	return Fault{}, false
+}
+
+func (fi *FaultInjector) float64() float64 {
+	if fi.Rand != nil {
+		return fi.Rand.Float64()
+	}
+	return rand.Float64()
+}
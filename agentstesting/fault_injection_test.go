@@ -0,0 +1,115 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentstesting_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectorSchedule(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	injector := &agentstesting.FaultInjector{Rules: []agentstesting.FaultRule{
+		{Tool: "foo", Calls: []int{1}, Fault: agentstesting.Fault{Kind: agentstesting.FaultError, Err: errQuota}},
+		{Tool: "foo", Calls: []int{2}, Fault: agentstesting.Fault{Kind: agentstesting.FaultMalformedOutput}},
+		{Tool: "foo", Calls: []int{3}, Fault: agentstesting.Fault{Kind: agentstesting.FaultTimeout, Delay: time.Millisecond}},
+	}}
+	tool := injector.WrapTool(agentstesting.GetFunctionTool("foo", "ok"))
+
+	_, err := tool.OnInvokeTool(t.Context(), "{}")
+	assert.ErrorIs(t, err, errQuota)
+	out, err := tool.OnInvokeTool(t.Context(), "{}")
+	require.NoError(t, err)
+	assert.Equal(t, agentstesting.DefaultMalformedOutput, out)
+	_, err = tool.OnInvokeTool(t.Context(), "{}")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	out, err = tool.OnInvokeTool(t.Context(), "{}")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+
+	assert.Equal(t, []agentstesting.InjectedFault{
+		{Tool: "foo", Call: 1, Kind: agentstesting.FaultError},
+		{Tool: "foo", Call: 2, Kind: agentstesting.FaultMalformedOutput},
+		{Tool: "foo", Call: 3, Kind: agentstesting.FaultTimeout},
+	}, injector.Injected())
+}
+
+func TestFaultInjectorTimeoutEndsWithContext(t *testing.T) {
+	injector := &agentstesting.FaultInjector{Rules: []agentstesting.FaultRule{
+		{Fault: agentstesting.Fault{Kind: agentstesting.FaultTimeout, Delay: time.Hour}},
+	}}
+	tool := injector.WrapTool(agentstesting.GetFunctionTool("foo", "ok"))
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := tool.OnInvokeTool(ctx, "{}")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFaultInjectorProbability(t *testing.T) {
+	injector := &agentstesting.FaultInjector{
+		Rules: []agentstesting.FaultRule{{Probability: 0.5, Fault: agentstesting.Fault{Kind: agentstesting.FaultError}}},
+		Rand:  rand.New(rand.NewPCG(1, 2)),
+	}
+	tool := injector.WrapTool(agentstesting.GetFunctionTool("foo", "ok"))
+	failures := 0
+	for range 1000 {
+		if _, err := tool.OnInvokeTool(t.Context(), "{}"); err != nil {
+			failures++
+		}
+	}
+	assert.InDelta(t, 500, failures, 60)
+	assert.Len(t, injector.Injected(), failures)
+}
+
+func TestFaultInjectorInRun(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("foo", "{}")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+	injector := &agentstesting.FaultInjector{Rules: []agentstesting.FaultRule{
+		{Tool: "foo", Calls: []int{1}, Fault: agentstesting.Fault{Kind: agentstesting.FaultError}},
+	}}
+	agent := &agents.Agent{
+		Name:  "test",
+		Model: param.NewOpt(agents.NewAgentModel(model)),
+		Tools: injector.WrapTools([]agents.Tool{agentstesting.GetFunctionTool("foo", "result")}),
+	}
+
+	result, err := agents.Runner{}.Run(t.Context(), agent, "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.FinalOutput)
+
+	var outputs []any
+	for _, item := range result.NewItems {
+		if output, ok := item.(agents.ToolCallOutputItem); ok {
+			outputs = append(outputs, output.Output)
+		}
+	}
+	assert.Equal(t, []any{
+		"An error occurred while running the tool. Please try again. Error: injected tool fault",
+		"result",
+	}, outputs)
+}
@@ -0,0 +1,242 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentstesting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// UpdateGoldenEnv is the environment variable which, when set to a non-empty
+// value, makes AssertGolden write the golden files instead of comparing them:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Transcript returns the normalized transcript of the run, see
+// FormatTranscript.
+func Transcript(result *agents.RunResult) string {
+	return FormatTranscript(result.Input, result.NewItems, result.FinalOutput)
+}
+
+// FormatTranscript renders the input, the items and the final output of a
+// run in a stable text format, for comparison with golden files. Each item
+// is rendered as the indented JSON of its input item, with sorted keys. The
+// IDs of the items, which change from run to run, are removed, and the call
+// IDs are numbered in order of appearance.
+func FormatTranscript(input agents.Input, items []agents.RunItem, finalOutput any) string {
+	n := transcriptNormalizer{callIDs: make(map[string]string)}
+	var b strings.Builder
+	for _, item := range agents.ItemHelpers().InputToNewInputList(input) {
+		b.WriteString("## input\n")
+		n.writeJSON(&b, item)
+	}
+	for _, item := range items {
+		typeName := strings.TrimPrefix(fmt.Sprintf("%T", item), "agents.")
+		if agent := itemAgent(item); agent != nil {
+			fmt.Fprintf(&b, "## %s (agent: %s)\n", typeName, agent.Name)
+		} else {
+			fmt.Fprintf(&b, "## %s\n", typeName)
+		}
+		n.writeJSON(&b, item.ToInputItem())
+	}
+	b.WriteString("## final_output\n")
+	if s, ok := finalOutput.(string); ok {
+		b.WriteString(strings.TrimSpace(s))
+		b.WriteByte('\n')
+	} else {
+		n.writeJSON(&b, finalOutput)
+	}
+	return b.String()
+}
+
+func itemAgent(item agents.RunItem) *agents.Agent {
+	field := reflect.ValueOf(item).FieldByName("Agent")
+	if !field.IsValid() {
+		return nil
+	}
+	agent, _ := field.Interface().(*agents.Agent)
+	return agent
+}
+
+type transcriptNormalizer struct {
+	callIDs map[string]string
+}
+
+func (n transcriptNormalizer) writeJSON(b *strings.Builder, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(b, "<error: %v>\n", err)
+		return
+	}
+	var decoded any
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		fmt.Fprintf(b, "<error: %v>\n", err)
+		return
+	}
+	data, _ = json.MarshalIndent(n.normalize(decoded), "", "  ")
+	b.Write(data)
+	b.WriteByte('\n')
+}
+
+func (n transcriptNormalizer) normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "id")
+		if callID, ok := v["call_id"].(string); ok {
+			if _, seen := n.callIDs[callID]; !seen {
+				n.callIDs[callID] = fmt.Sprintf("call_%d", len(n.callIDs)+1)
+			}
+			v["call_id"] = n.callIDs[callID]
+		}
+		for key, value := range v {
+			v[key] = n.normalize(value)
+		}
+		// Arguments of the tool calls are JSON strings.
+		if arguments, ok := v["arguments"].(string); ok {
+			var decoded any
+			if json.Unmarshal([]byte(arguments), &decoded) == nil {
+				normalized, _ := json.Marshal(decoded)
+				v["arguments"] = string(normalized)
+			}
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = n.normalize(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// AssertGolden compares got with the content of the golden file at path,
+// reporting the differences line by line. When UpdateGoldenEnv is set, it
+// writes got to the file instead.
+func AssertGolden(t testing.TB, path string, got string) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist: run the test with %s=1 to create it", path, UpdateGoldenEnv)
+		return
+	}
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+		return
+	}
+	want := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if want != got {
+		t.Errorf("transcript differs from golden file %s (-want +got):\n%s\nrun the test with %s=1 to update it",
+			path, lineDiff(want, got), UpdateGoldenEnv)
+	}
+}
+
+// AssertGoldenTranscript compares the transcript of the run with the golden
+// file at path, see AssertGolden.
+func AssertGoldenTranscript(t testing.TB, path string, result *agents.RunResult) {
+	t.Helper()
+	AssertGolden(t, path, Transcript(result))
+}
+
+// diffContext is the number of unchanged lines shown around the changes.
+const diffContext = 3
+
+// lineDiff returns the differences of the lines of want and got, as lines
+// prefixed by "-" when removed, "+" when added, and " " when unchanged
+// around the changes.
+func lineDiff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	skipped := false
+	for k, line := range lines {
+		if line.op == ' ' && !changedNear(k, func(k int) bool { return k >= 0 && k < len(lines) && lines[k].op != ' ' }) {
+			if !skipped {
+				out.WriteString("...\n")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		fmt.Fprintf(&out, "%c %s\n", line.op, line.text)
+	}
+	return out.String()
+}
+
+// changedNear reports whether a line within diffContext of k changed.
+func changedNear(k int, changed func(int) bool) bool {
+	for d := -diffContext; d <= diffContext; d++ {
+		if changed(k + d) {
+			return true
+		}
+	}
+	return false
+}
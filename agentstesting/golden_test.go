@@ -0,0 +1,97 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentstesting_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runToolCallTranscript(t *testing.T, arguments string) *agents.RunResult {
+	t.Helper()
+	model := agentstesting.NewFakeModel(false, nil)
+	toolCall := agentstesting.GetFunctionToolCall("get_weather", arguments)
+	toolCall.CallID = "call_" + t.Name()
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{toolCall}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("It is sunny in Paris.")}},
+	})
+	agent := &agents.Agent{
+		Name:  "weather",
+		Model: param.NewOpt(agents.NewAgentModel(model)),
+		Tools: []agents.Tool{agentstesting.GetFunctionTool("get_weather", "sunny")},
+	}
+	result, err := agents.Runner{}.Run(t.Context(), agent, "What's the weather in Paris?")
+	require.NoError(t, err)
+	return result
+}
+
+func TestAssertGoldenTranscript(t *testing.T) {
+	// Call IDs and the formatting of the arguments do not change the transcript.
+	agentstesting.AssertGoldenTranscript(t, "testdata/tool_call.golden", runToolCallTranscript(t, `{"city":"Paris"}`))
+	agentstesting.AssertGoldenTranscript(t, "testdata/tool_call.golden", runToolCallTranscript(t, `{ "city": "Paris" }`))
+}
+
+// recordingTB records the failures of the assertions.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGoldenReportsDiff(t *testing.T) {
+	t.Setenv(agentstesting.UpdateGoldenEnv, "")
+	path := filepath.Join(t.TempDir(), "lines.golden")
+	require.NoError(t, os.WriteFile(path, []byte("a\nb\nc\nd\ne\nf\ng\nh\n"), 0o644))
+
+	tb := &recordingTB{TB: t}
+	agentstesting.AssertGolden(tb, path, "a\nb\nc\nd\ne\nf\ng\nh\n")
+	assert.Empty(t, tb.failures)
+
+	agentstesting.AssertGolden(tb, path, "a\nb\nc\nd\nE\nf\ng\nh\n")
+	require.Len(t, tb.failures, 1)
+	assert.Contains(t, tb.failures[0], "...\n  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n")
+
+	tb.failures = nil
+	agentstesting.AssertGolden(tb, filepath.Join(t.TempDir(), "missing.golden"), "x")
+	require.Len(t, tb.failures, 1)
+	assert.Contains(t, tb.failures[0], "UPDATE_GOLDEN=1")
+}
+
+func TestAssertGoldenUpdates(t *testing.T) {
+	t.Setenv(agentstesting.UpdateGoldenEnv, "1")
+	path := filepath.Join(t.TempDir(), "new", "transcript.golden")
+	agentstesting.AssertGolden(t, path, "content\n")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content\n", string(data))
+}
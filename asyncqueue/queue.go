@@ -15,6 +15,7 @@
 package asyncqueue
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -22,6 +23,13 @@ import (
 type Queue[T any] struct {
 	cond   *sync.Cond
 	values []T
+	// head is the index of the first value. Values are consumed by
+	// advancing it, so that Get does not shift the buffer, which is reused
+	// once drained.
+	head int
+	// capacity bounds the values in the queue, unlimited when zero.
+	capacity int
+	closed   bool
 }
 
 func New[T any]() *Queue[T] {
@@ -30,16 +38,78 @@ func New[T any]() *Queue[T] {
 	}
 }
 
+// NewBounded returns a queue holding at most capacity values, where Put
+// waits for values to be consumed when it is full. The queue is unbounded
+// when capacity is not positive.
+func NewBounded[T any](capacity int) *Queue[T] {
+	q := New[T]()
+	q.capacity = max(capacity, 0)
+	return q
+}
+
+// Put adds the value to the queue, waiting until there is room for it in
+// bounded queues. The value is dropped if the queue is closed.
 func (q *Queue[T]) Put(v T) {
 	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.full() && !q.closed {
+		q.cond.Wait()
+	}
+	if !q.closed {
+		q.put(v)
+	}
+}
+
+// PutContext is like Put, but stops waiting for room in the queue once ctx
+// is done, returning its error without adding v.
+func (q *Queue[T]) PutContext(ctx context.Context, v T) error {
+	stop := context.AfterFunc(ctx, func() {
+		q.cond.L.Lock()
+		defer q.cond.L.Unlock()
+		q.cond.Broadcast()
+	})
+	defer stop()
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.full() && !q.closed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		q.cond.Wait()
+	}
+	if !q.closed {
+		q.put(v)
+	}
+	return nil
+}
+
+// TryPut adds the value to the queue unless it is full or closed, reporting
+// whether it was added.
+func (q *Queue[T]) TryPut(v T) bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.full() || q.closed {
+		return false
+	}
 	q.put(v)
+	return true
+}
+
+// Close drops the values put from now on, releasing the producers waiting
+// for room in a full queue, e.g. once the consumer stopped. The values
+// already in the queue can still be consumed.
+func (q *Queue[T]) Close() {
+	q.cond.L.Lock()
+	q.closed = true
 	q.cond.L.Unlock()
+	q.cond.Broadcast()
 }
 
 func (q *Queue[T]) Get() T {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	for len(q.values) == 0 {
+	for q.len() == 0 {
 		q.cond.Wait()
 	}
 	return q.get()
@@ -57,7 +127,7 @@ func (q *Queue[T]) GetTimeout(timeout time.Duration) (T, bool) {
 
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	for len(q.values) == 0 && !timedOut {
+	for q.len() == 0 && !timedOut {
 		q.cond.Wait()
 	}
 
@@ -73,7 +143,7 @@ func (q *Queue[T]) GetNoWait() (T, bool) {
 	defer q.cond.L.Unlock()
 
 	var zero T
-	if len(q.values) == 0 {
+	if q.len() == 0 {
 		return zero, false
 	}
 
@@ -83,7 +153,7 @@ func (q *Queue[T]) GetNoWait() (T, bool) {
 func (q *Queue[T]) IsEmpty() bool {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	return len(q.values) == 0
+	return q.len() == 0
 }
 
 func (q *Queue[T]) put(v T) {
@@ -92,10 +162,29 @@ func (q *Queue[T]) put(v T) {
 }
 
 func (q *Queue[T]) get() T {
-	v := q.values[0]
-	copy(q.values[:len(q.values)-1], q.values[1:])
-	clear(q.values[len(q.values)-1:]) // helps GC
-	q.values = q.values[:len(q.values)-1]
+	v := q.values[q.head]
+	clear(q.values[q.head : q.head+1]) // helps GC
+	q.head++
+	switch {
+	case q.head == len(q.values):
+		q.values = q.values[:0]
+		q.head = 0
+	case q.head*2 >= len(q.values):
+		// Move the values back to the start of the buffer, at an amortized
+		// constant cost, as the values moved are fewer than the consumed ones.
+		n := copy(q.values, q.values[q.head:])
+		clear(q.values[n:])
+		q.values = q.values[:n]
+		q.head = 0
+	}
 	q.cond.Broadcast()
 	return v
 }
+
+func (q *Queue[T]) full() bool {
+	return q.capacity > 0 && q.len() >= q.capacity
+}
+
+func (q *Queue[T]) len() int {
+	return len(q.values) - q.head
+}
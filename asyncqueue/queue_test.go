@@ -15,9 +15,13 @@
 package asyncqueue
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestQueue(t *testing.T) {
@@ -56,3 +60,100 @@ func TestQueue(t *testing.T) {
 	v, ok = q.GetNoWait()
 	assert.False(t, ok)
 }
+
+func TestQueueInterleaved(t *testing.T) {
+	q := New[int]()
+	next := 0
+	for i := range 1000 {
+		q.Put(i)
+		if i%3 != 0 {
+			v, ok := q.GetNoWait()
+			assert.True(t, ok)
+			assert.Equal(t, next, v)
+			next++
+		}
+	}
+	for !q.IsEmpty() {
+		assert.Equal(t, next, q.Get())
+		next++
+	}
+	assert.Equal(t, 1000, next)
+}
+
+func TestBoundedQueue(t *testing.T) {
+	q := NewBounded[int](2)
+
+	q.Put(1)
+	assert.True(t, q.TryPut(2))
+	assert.False(t, q.TryPut(3))
+
+	put := make(chan struct{})
+	go func() {
+		q.Put(3) // Waits for room.
+		close(put)
+	}()
+	select {
+	case <-put:
+		t.Fatal("Put did not wait for room in the full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	assert.Equal(t, 1, q.Get())
+	<-put
+	assert.Equal(t, 2, q.Get())
+	assert.Equal(t, 3, q.Get())
+	assert.True(t, q.IsEmpty())
+}
+
+func TestBoundedQueueClose(t *testing.T) {
+	q := NewBounded[int](1)
+	q.Put(1)
+
+	put := make(chan struct{})
+	go func() {
+		q.Put(2)
+		close(put)
+	}()
+	q.Close()
+	<-put // The waiting producer is released, and its value dropped.
+
+	assert.False(t, q.TryPut(3))
+	assert.Equal(t, 1, q.Get())
+	assert.True(t, q.IsEmpty())
+}
+
+func TestBoundedQueuePutContext(t *testing.T) {
+	q := NewBounded[int](1)
+	require.NoError(t, q.PutContext(t.Context(), 1))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	put := make(chan error)
+	go func() { put <- q.PutContext(ctx, 2) }()
+	select {
+	case <-put:
+		t.Fatal("PutContext did not wait for room in the full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	assert.ErrorIs(t, <-put, context.Canceled) // The value is dropped.
+
+	assert.Equal(t, 1, q.Get())
+	assert.True(t, q.IsEmpty())
+}
+
+func BenchmarkQueue(b *testing.B) {
+	// The consumer lags behind the producer by a backlog of values.
+	for _, backlog := range []int{1, 1000} {
+		b.Run(fmt.Sprintf("backlog=%d", backlog), func(b *testing.B) {
+			q := New[int]()
+			for i := range backlog {
+				q.Put(i)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				q.Put(0)
+				q.Get()
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command agenttrace inspects traces written by tracing.JSONLExporter.
+//
+// Usage:
+//
+//	agenttrace view [-trace ID] FILE
+//
+// The view command prints each trace as a tree of agent, generation, tool,
+// handoff and guardrail spans, with their durations and errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "agenttrace:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\nusage: agenttrace view [-trace ID] FILE")
+	}
+	switch args[0] {
+	case "view":
+		return runView(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runView(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("view", flag.ContinueOnError)
+	traceID := fs.String("trace", "", "only show the trace with this ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: agenttrace view [-trace ID] FILE")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	traces, err := loadTraces(f)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, t := range traces {
+		if *traceID != "" && t.ID != *traceID {
+			continue
+		}
+		found = true
+		renderTrace(stdout, t)
+	}
+	if !found && *traceID != "" {
+		return fmt.Errorf("trace %q not found", *traceID)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// exportedTrace holds a trace and its spans as read from a JSONL file.
+type exportedTrace struct {
+	ID           string
+	WorkflowName string
+	GroupID      string
+	Spans        []*exportedSpan
+}
+
+type exportedSpan struct {
+	ID        string         `json:"id"`
+	TraceID   string         `json:"trace_id"`
+	ParentID  string         `json:"parent_id"`
+	StartedAt time.Time      `json:"started_at"`
+	EndedAt   time.Time      `json:"ended_at"`
+	SpanData  map[string]any `json:"span_data"`
+	Error     *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+
+	children []*exportedSpan
+}
+
+// loadTraces reads traces and spans, in order of first appearance of each trace.
+// Spans whose trace record is missing are grouped under a placeholder trace.
+func loadTraces(r io.Reader) ([]*exportedTrace, error) {
+	var traces []*exportedTrace
+	byID := make(map[string]*exportedTrace)
+	getTrace := func(id string) *exportedTrace {
+		t, ok := byID[id]
+		if !ok {
+			t = &exportedTrace{ID: id}
+			byID[id] = t
+			traces = append(traces, t)
+		}
+		return t
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var header struct {
+			Object       string `json:"object"`
+			ID           string `json:"id"`
+			WorkflowName string `json:"workflow_name"`
+			GroupID      string `json:"group_id"`
+		}
+		if err := json.Unmarshal(line, &header); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		switch header.Object {
+		case "trace":
+			t := getTrace(header.ID)
+			t.WorkflowName = header.WorkflowName
+			t.GroupID = header.GroupID
+		case "trace.span":
+			span := new(exportedSpan)
+			if err := json.Unmarshal(line, span); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			t := getTrace(span.TraceID)
+			t.Spans = append(t.Spans, span)
+		default:
+			return nil, fmt.Errorf("line %d: unexpected object %q", lineNum, header.Object)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+func renderTrace(w io.Writer, t *exportedTrace) {
+	title := fmt.Sprintf("%s %s", t.ID, cmp.Or(t.WorkflowName, "(unknown workflow)"))
+	if t.GroupID != "" {
+		title += fmt.Sprintf(" [group %s]", t.GroupID)
+	}
+	_, _ = fmt.Fprintln(w, title)
+
+	byID := make(map[string]*exportedSpan, len(t.Spans))
+	for _, span := range t.Spans {
+		span.children = nil
+		byID[span.ID] = span
+	}
+	var roots []*exportedSpan
+	for _, span := range t.Spans {
+		if parent, ok := byID[span.ParentID]; ok && span.ParentID != "" {
+			parent.children = append(parent.children, span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+	renderSpans(w, roots, "")
+}
+
+func renderSpans(w io.Writer, spans []*exportedSpan, indent string) {
+	slices.SortStableFunc(spans, func(a, b *exportedSpan) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	for i, span := range spans {
+		branch, childIndent := "├─ ", "│  "
+		if i == len(spans)-1 {
+			branch, childIndent = "└─ ", "   "
+		}
+		_, _ = fmt.Fprintf(w, "%s%s%s\n", indent, branch, describeSpan(span))
+		renderSpans(w, span.children, indent+childIndent)
+	}
+}
+
+func describeSpan(span *exportedSpan) string {
+	data := span.SpanData
+	spanType := stringField(data, "type")
+	parts := []string{cmp.Or(spanType, "span")}
+
+	switch spanType {
+	case "agent", "function", "guardrail", "custom":
+		parts = append(parts, stringField(data, "name"))
+	case "generation":
+		parts = append(parts, stringField(data, "model"))
+	case "response":
+		parts = append(parts, stringField(data, "response_id"))
+	case "handoff":
+		parts = append(parts, fmt.Sprintf("%s -> %s", stringField(data, "from_agent"), stringField(data, "to_agent")))
+	case "mcp_tools":
+		parts = append(parts, stringField(data, "server"))
+	}
+
+	if !span.StartedAt.IsZero() && !span.EndedAt.IsZero() {
+		parts = append(parts, span.EndedAt.Sub(span.StartedAt).Round(time.Millisecond).String())
+	}
+	if usage, ok := data["usage"].(map[string]any); ok {
+		parts = append(parts, fmt.Sprintf("tokens in=%v out=%v", usage["input_tokens"], usage["output_tokens"]))
+		if cost, ok := usage["estimated_cost_usd"].(float64); ok {
+			parts = append(parts, fmt.Sprintf("~$%.4f", cost))
+		}
+	}
+	if triggered, _ := data["triggered"].(bool); triggered {
+		parts = append(parts, "TRIGGERED")
+	}
+	if span.Error != nil {
+		parts = append(parts, "ERROR: "+span.Error.Message)
+	}
+
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), "  ")
+}
+
+func stringField(data map[string]any, key string) string {
+	s, _ := data[key].(string)
+	return s
+}
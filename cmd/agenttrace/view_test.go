@@ -0,0 +1,58 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	exporter, err := tracing.NewJSONLFileExporter(path)
+	require.NoError(t, err)
+
+	tr := tracing.NewTraceImpl("my_workflow", "trace_1", "", nil, nil)
+	agentSpan := tracing.NewSpanImpl("trace_1", "span_agent", "", nil, &tracing.AgentSpanData{Name: "assistant"})
+	toolSpan := tracing.NewSpanImpl("trace_1", "span_tool", "span_agent", nil, &tracing.FunctionSpanData{Name: "search"})
+	toolSpan.SetError(tracing.SpanError{Message: "boom"})
+	generationSpan := tracing.NewSpanImpl("trace_1", "span_gen", "span_agent", nil, &tracing.GenerationSpanData{
+		Model: "gpt-4o",
+		Usage: map[string]any{"input_tokens": 10, "output_tokens": 5, "estimated_cost_usd": 0.0125},
+	})
+
+	// Spans are exported as they end: children first.
+	require.NoError(t, exporter.Export(ctx, []any{tr, generationSpan, toolSpan, agentSpan}))
+	require.NoError(t, exporter.Close())
+
+	var out strings.Builder
+	require.NoError(t, run([]string{"view", path}, &out))
+	assert.Equal(t, strings.Join([]string{
+		"trace_1 my_workflow",
+		"└─ agent  assistant",
+		"   ├─ generation  gpt-4o  tokens in=10 out=5  ~$0.0125",
+		"   └─ function  search  ERROR: boom",
+		"",
+	}, "\n"), out.String())
+
+	err = run([]string{"view", "-trace", "missing", path}, &out)
+	assert.ErrorContains(t, err, `trace "missing" not found`)
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+func runApprovals(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing approvals command\nusage: wfrun approvals list|approve|deny ...")
+	}
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("approvals list", flag.ContinueOnError)
+		stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+		if err := parseFlags(fs, args[1:], 1, "wfrun approvals list [-state DIR] SESSION"); err != nil {
+			return err
+		}
+		state, err := loadState(ctx, *stateDir, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		pending := state.PendingApprovals
+		if pending == nil {
+			pending = []workflowrunner.ApprovalRequestState{}
+		}
+		return writeJSON(stdout, pending)
+	case "approve", "deny":
+		fs := flag.NewFlagSet("approvals "+args[0], flag.ContinueOnError)
+		stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+		reason := fs.String("reason", "", "reason of the decision")
+		usage := fmt.Sprintf("wfrun approvals %s [-state DIR] [-reason TEXT] SESSION REQUEST_ID", args[0])
+		if err := parseFlags(fs, args[1:], 2, usage); err != nil {
+			return err
+		}
+		service := newService(*stateDir, defaultSessionsDir)
+		state, err := service.ResolveApproval(ctx, fs.Arg(0), workflowrunner.ApprovalDecisionState{
+			RequestID: fs.Arg(1),
+			Approve:   args[0] == "approve",
+			Reason:    *reason,
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, state)
+	default:
+		return fmt.Errorf("unknown approvals command %q", args[0])
+	}
+}
+
+func runInputs(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing inputs command\nusage: wfrun inputs list|provide ...")
+	}
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("inputs list", flag.ContinueOnError)
+		stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+		if err := parseFlags(fs, args[1:], 1, "wfrun inputs list [-state DIR] SESSION"); err != nil {
+			return err
+		}
+		state, err := loadState(ctx, *stateDir, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		pending := state.PendingInputs
+		if pending == nil {
+			pending = []workflowrunner.InputRequestState{}
+		}
+		return writeJSON(stdout, pending)
+	case "provide":
+		fs := flag.NewFlagSet("inputs provide", flag.ContinueOnError)
+		stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+		isJSON := fs.Bool("json", false, "decode the answer as JSON")
+		if err := parseFlags(fs, args[1:], 3, "wfrun inputs provide [-state DIR] [-json] SESSION REQUEST_ID ANSWER"); err != nil {
+			return err
+		}
+		var answer any = fs.Arg(2)
+		if *isJSON {
+			if err := json.Unmarshal([]byte(fs.Arg(2)), &answer); err != nil {
+				return fmt.Errorf("decode answer: %w", err)
+			}
+		}
+		service := newService(*stateDir, defaultSessionsDir)
+		state, err := service.ProvideInput(ctx, fs.Arg(0), workflowrunner.InputResponseState{
+			RequestID: fs.Arg(1),
+			Answer:    answer,
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, state)
+	default:
+		return fmt.Errorf("unknown inputs command %q", args[0])
+	}
+}
+
+func runState(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "show" {
+		return fmt.Errorf("usage: wfrun state show [-state DIR] SESSION")
+	}
+	fs := flag.NewFlagSet("state show", flag.ContinueOnError)
+	stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+	if err := parseFlags(fs, args[1:], 1, "wfrun state show [-state DIR] SESSION"); err != nil {
+		return err
+	}
+	state, err := loadState(ctx, *stateDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, state)
+}
+
+func loadState(ctx context.Context, stateDir, sessionID string) (workflowrunner.WorkflowExecutionState, error) {
+	state, ok, err := workflowrunner.NewDirExecutionStateStore(stateDir).Load(ctx, sessionID)
+	if err != nil {
+		return state, err
+	}
+	if !ok {
+		return state, fmt.Errorf("%w: session %q", workflowrunner.ErrExecutionNotFound, sessionID)
+	}
+	return state, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner/loadtest"
+)
+
+const loadUsage = "wfrun load [-n RUNS] [-c CONCURRENCY] [-fake] [-fake-latency DURATION] [-fake-output TEXT] FILE..."
+
+func runLoad(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	runs := fs.Int("n", 0, "number of runs, one per request when zero")
+	concurrency := fs.Int("c", loadtest.DefaultConcurrency, "number of runs in flight")
+	fake := fs.Bool("fake", false, "replace the models of the agents with a fake model")
+	fakeLatency := fs.Duration("fake-latency", 0, "latency of the responses of the fake model")
+	fakeOutput := fs.String("fake-output", "", "text of the responses of the fake model")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w\nusage: %s", err, loadUsage)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: %s", loadUsage)
+	}
+	corpus, err := loadtest.LoadCorpus(fs.Args()...)
+	if err != nil {
+		return err
+	}
+
+	// States and sessions of the runs are thrown away.
+	dir, err := os.MkdirTemp("", "wfrun-load-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	service := newService(filepath.Join(dir, "state"), filepath.Join(dir, "sessions"))
+	if *fake {
+		loadtest.UseFakeModel(service.Builder, &loadtest.FakeModel{Latency: *fakeLatency, Output: *fakeOutput})
+		for i, req := range corpus {
+			corpus[i] = loadtest.WithFakeModel(req)
+		}
+	}
+	for i, req := range corpus {
+		if err := workflowrunner.ValidateWorkflowRequest(req); err != nil {
+			return fmt.Errorf("request %d: %w", i+1, err)
+		}
+	}
+
+	report, err := loadtest.Run(ctx, service, corpus, loadtest.Config{Runs: *runs, Concurrency: *concurrency})
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, report)
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command wfrun validates, plans and runs workflowrunner manifests, and
+// manages the approvals and inputs of the runs suspended on them.
+//
+// Usage:
+//
+//	wfrun validate FILE...
+//	wfrun lint [-strict] FILE...
+//	wfrun plan FILE
+//	wfrun run [-state DIR] [-sessions DIR] [-session ID] [-query TEXT] [-events] FILE
+//	wfrun resume [-state DIR] [-sessions DIR] [-session ID] [-events] FILE
+//	wfrun approvals list [-state DIR] SESSION
+//	wfrun approvals approve|deny [-state DIR] [-reason TEXT] SESSION REQUEST_ID
+//	wfrun inputs list [-state DIR] SESSION
+//	wfrun inputs provide [-state DIR] [-json] SESSION REQUEST_ID ANSWER
+//	wfrun state show [-state DIR] SESSION
+//	wfrun load [-n RUNS] [-c CONCURRENCY] [-fake] [-fake-latency DURATION] [-fake-output TEXT] FILE...
+//
+// FILE is a JSON WorkflowRequest manifest, or "-" to read it from stdin.
+//
+// The lint command prints the non-fatal issues of valid manifests, such as
+// unreachable agents or agent tools without descriptions; with -strict, it
+// fails when any is found.
+//
+// The run and resume commands print the RunSummary as JSON. Manifests
+// declaring a batch run each of its items, and print the BatchSummary; the
+// runs of the items are resumed separately, with -session. With -events the
+// callback events are written to stderr as JSON lines, instead of being
+// published to the callback declared by the manifest; the events of
+// manifests declaring the "stream" callback mode are discarded otherwise.
+// Execution states are kept in the -state directory, so that runs suspended
+// on approval or input requests can be resolved and resumed by later
+// invocations. With -json, the answer of inputs provide is decoded as JSON,
+// e.g. to fill the fields of a form.
+//
+// The load command replays the requests of the files, JSON manifests or
+// JSON lines files with a request per line, cycling through them for -n
+// runs with -c runs in flight, and prints the throughput, the latencies and
+// the memory usage as JSON. States and sessions are kept in a temporary
+// directory. With -fake the agents use a fake model replying after
+// -fake-latency, to measure the runner without calling the providers.
+//
+// wfrun exits with status 1 on errors and failed runs, and with status 2
+// when a run is suspended on approval or input requests.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+const (
+	defaultStateDir    = "wfrun_state"
+	defaultSessionsDir = "workflowrunner_sessions"
+)
+
+// errSuspended is returned by the run and resume commands when the run is
+// suspended on approval or input requests.
+var errSuspended = errors.New("run suspended on approval or input requests")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "wfrun:", err)
+		if errors.Is(err, errSuspended) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+const usage = `usage:
+  wfrun validate FILE...
+  wfrun lint [-strict] FILE...
+  wfrun plan FILE
+  wfrun run [-state DIR] [-sessions DIR] [-session ID] [-query TEXT] [-events] FILE
+  wfrun resume [-state DIR] [-sessions DIR] [-session ID] [-events] FILE
+  wfrun approvals list [-state DIR] SESSION
+  wfrun approvals approve|deny [-state DIR] [-reason TEXT] SESSION REQUEST_ID
+  wfrun inputs list [-state DIR] SESSION
+  wfrun inputs provide [-state DIR] [-json] SESSION REQUEST_ID ANSWER
+  wfrun state show [-state DIR] SESSION
+  ` + loadUsage
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdin, stdout)
+	case "lint":
+		return runLint(ctx, args[1:], stdin, stdout)
+	case "plan":
+		return runPlan(ctx, args[1:], stdin, stdout)
+	case "run":
+		return runRun(ctx, args[1:], stdin, stdout, stderr, false)
+	case "resume":
+		return runRun(ctx, args[1:], stdin, stdout, stderr, true)
+	case "approvals":
+		return runApprovals(ctx, args[1:], stdout)
+	case "inputs":
+		return runInputs(ctx, args[1:], stdout)
+	case "state":
+		return runState(ctx, args[1:], stdout)
+	case "load":
+		return runLoad(ctx, args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+// readManifest reads the manifest at path, or stdin for "-".
+func readManifest(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// loadRequest reads and decodes the manifest at path. Manifests of older
+// versions are migrated.
+func loadRequest(path string, stdin io.Reader) (workflowrunner.WorkflowRequest, error) {
+	data, err := readManifest(path, stdin)
+	if err != nil {
+		return workflowrunner.WorkflowRequest{}, err
+	}
+	var req workflowrunner.WorkflowRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return workflowrunner.WorkflowRequest{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return req, nil
+}
+
+// hasCallback reports whether the request declares a callback publishing
+// the events. Requests without one, or declaring the stream mode, are run
+// with their events discarded, or written with -events.
+func hasCallback(req workflowrunner.WorkflowRequest) bool {
+	if req.Callback.Mode == workflowrunner.CallbackModeStream {
+		return false
+	}
+	return req.Callback.Mode != "" || req.Callback.Target != ""
+}
+
+// newService returns a runner service keeping the execution states in
+// stateDir and the sessions in sessionsDir.
+func newService(stateDir, sessionsDir string) *workflowrunner.RunnerService {
+	builder := workflowrunner.NewDefaultBuilder()
+	builder.SessionFactory = workflowrunner.NewSQLiteSessionFactory(sessionsDir)
+	builder.SessionFactories["sqlite"] = builder.SessionFactory
+	service := workflowrunner.NewRunnerService(builder)
+	service.StateStore = workflowrunner.NewDirExecutionStateStore(stateDir)
+	return service
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func parseFlags(fs *flag.FlagSet, args []string, nargs int, usage string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w\nusage: %s", err, usage)
+	}
+	if fs.NArg() != nargs {
+		return fmt.Errorf("usage: %s", usage)
+	}
+	return nil
+}
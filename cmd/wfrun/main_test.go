@@ -0,0 +1,259 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner/loadtest"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "query": "hello",
+  "session": {
+    "session_id": "s1",
+    "credentials": {"user_id": "u1", "account_id": "a1"}
+  },
+  "callback": {"mode": "stream", "target": ""},
+  "workflow": {
+    "name": "wf",
+    "starting_agent": "assistant",
+    "agents": [
+      {"name": "assistant", "instructions": "Be brief.", "model": {"model": "gpt-4o"}}
+    ]
+  }
+}`
+
+func writeManifest(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "workflow.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	valid := writeManifest(t, testManifest)
+	invalid := writeManifest(t, strings.Replace(testManifest, `"starting_agent": "assistant"`, `"starting_agent": "missing"`, 1))
+
+	var out strings.Builder
+	require.NoError(t, run(t.Context(), []string{"validate", valid}, nil, &out, nil))
+	assert.Equal(t, valid+": ok\n", out.String())
+
+	out.Reset()
+	err := run(t.Context(), []string{"validate", valid, invalid}, nil, &out, nil)
+	assert.EqualError(t, err, "1 of 2 manifests are invalid")
+	assert.Contains(t, out.String(), invalid+": $.workflow.starting_agent: ")
+}
+
+func TestLint(t *testing.T) {
+	clean := writeManifest(t, testManifest)
+	warned := writeManifest(t, strings.Replace(testManifest,
+		`{"name": "assistant", "instructions": "Be brief.", "model": {"model": "gpt-4o"}}`,
+		`{"name": "assistant", "instructions": "Be brief.", "handoffs": ["expert"], "agent_tools": [{"agent_name": "expert"}]},
+      {"name": "expert", "output_guardrails": [{"name": "missing"}]},
+      {"name": "orphan"}`, 1))
+
+	var out strings.Builder
+	require.NoError(t, run(t.Context(), []string{"lint", clean, warned}, nil, &out, nil))
+	assert.Equal(t, clean+": ok\n"+
+		warned+": warning: $.workflow.agents[2]: agent \"orphan\" is not reachable from the starting agent \"assistant\" (unreachable_agent)\n"+
+		warned+": warning: $.workflow.agents[1].handoff_description: agent \"expert\" is a handoff target of \"assistant\" without handoff_description (missing_handoff_description)\n"+
+		warned+": warning: $.workflow.agents[0].agent_tools[0].description: agent tool \"expert\" has no description (missing_tool_description)\n"+
+		warned+": warning: $.workflow.agents[1].output_guardrails[0].name: output guardrail \"missing\" is not registered (unregistered)\n",
+		out.String())
+
+	out.Reset()
+	err := run(t.Context(), []string{"lint", "-strict", clean, warned}, nil, &out, nil)
+	assert.EqualError(t, err, "1 of 2 manifests have warnings")
+}
+
+func TestPlan(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, run(t.Context(), []string{"plan", "-"}, strings.NewReader(testManifest), &out, nil))
+
+	var plan workflowrunner.WorkflowPlan
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &plan))
+	assert.Equal(t, "wf", plan.WorkflowName)
+	require.Len(t, plan.Agents, 1)
+	assert.Equal(t, "gpt-4o", plan.Agents[0].Model)
+}
+
+func TestRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","created":0,"model":"m","choices":[{"index":0,"delta":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`+"\n\n")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	agents.SetDefaultOpenaiClient(agents.NewOpenaiClient(param.NewOpt(srv.URL), param.NewOpt("key")), false)
+	agents.SetDefaultOpenaiAPI(agents.OpenaiAPITypeChatCompletions)
+	t.Cleanup(func() { agents.SetDefaultOpenaiAPI(agents.OpenaiAPITypeResponses) })
+
+	dir := t.TempDir()
+	stateDir, sessionsDir := filepath.Join(dir, "state"), filepath.Join(dir, "sessions")
+	manifest := writeManifest(t, testManifest)
+
+	var out, events strings.Builder
+	err := run(t.Context(), []string{"run", "-state", stateDir, "-sessions", sessionsDir, "-session", "s2", "-events", manifest}, nil, &out, &events)
+	require.NoError(t, err)
+
+	var summary runOutput
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &summary))
+	assert.Equal(t, workflowrunner.ExecutionStatusCompleted, summary.Status)
+	assert.Equal(t, "s2", summary.SessionID)
+	assert.Equal(t, "hi", summary.FinalOutput)
+	assert.Contains(t, events.String(), `"type":"run.completed"`)
+
+	out.Reset()
+	require.NoError(t, run(t.Context(), []string{"state", "show", "-state", stateDir, "s2"}, nil, &out, nil))
+	var state workflowrunner.WorkflowExecutionState
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &state))
+	assert.Equal(t, workflowrunner.ExecutionStatusCompleted, state.Status)
+
+	err = run(t.Context(), []string{"resume", "-state", stateDir, "-sessions", sessionsDir, "-session", "s2", manifest}, nil, &out, nil)
+	assert.ErrorIs(t, err, workflowrunner.ErrExecutionNotSuspended)
+}
+
+func TestRunBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, `data: {"id":"x","object":"chat.completion.chunk","created":0,"model":"m","choices":[{"index":0,"delta":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`+"\n\n")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	agents.SetDefaultOpenaiClient(agents.NewOpenaiClient(param.NewOpt(srv.URL), param.NewOpt("key")), false)
+	agents.SetDefaultOpenaiAPI(agents.OpenaiAPITypeChatCompletions)
+	t.Cleanup(func() { agents.SetDefaultOpenaiAPI(agents.OpenaiAPITypeResponses) })
+
+	dir := t.TempDir()
+	manifest := writeManifest(t, strings.Replace(testManifest, `"query": "hello",`,
+		`"batch": {"items": [{"id": "a", "query": "hello"}, {"query": "hi"}]},`, 1))
+
+	var out strings.Builder
+	err := run(t.Context(), []string{"run", "-state", filepath.Join(dir, "state"), "-sessions", filepath.Join(dir, "sessions"), manifest}, nil, &out, nil)
+	require.NoError(t, err)
+
+	var summary workflowrunner.BatchSummary
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &summary))
+	assert.Equal(t, "s1", summary.BatchID)
+	assert.Equal(t, 2, summary.Completed)
+	require.Len(t, summary.Runs, 2)
+	assert.Equal(t, "a", summary.Runs[0].ItemID)
+	assert.Equal(t, "s1:a", summary.Runs[0].SessionID)
+	assert.Equal(t, "1", summary.Runs[1].ItemID)
+	assert.Equal(t, "hi", summary.Runs[1].FinalOutput)
+}
+
+func TestApprovals(t *testing.T) {
+	ctx := t.Context()
+	stateDir := t.TempDir()
+	store := workflowrunner.NewDirExecutionStateStore(stateDir)
+	require.NoError(t, store.Save(ctx, workflowrunner.WorkflowExecutionState{
+		SessionID:    "s1",
+		WorkflowName: "wf",
+		Status:       workflowrunner.ExecutionStatusWaitingApproval,
+		PendingApprovals: []workflowrunner.ApprovalRequestState{
+			{RequestID: "req_1", ToolName: "delete_repo"},
+			{RequestID: "req_2", ToolName: "create_issue"},
+		},
+	}))
+
+	var out strings.Builder
+	require.NoError(t, run(ctx, []string{"approvals", "list", "-state", stateDir, "s1"}, nil, &out, nil))
+	var pending []workflowrunner.ApprovalRequestState
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &pending))
+	assert.Len(t, pending, 2)
+
+	require.NoError(t, run(ctx, []string{"approvals", "deny", "-state", stateDir, "-reason", "too risky", "s1", "req_1"}, nil, &out, nil))
+	require.NoError(t, run(ctx, []string{"approvals", "approve", "-state", stateDir, "s1", "req_2"}, nil, &out, nil))
+
+	state, ok, err := store.Load(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, workflowrunner.ExecutionStatusRunning, state.Status)
+	assert.Empty(t, state.PendingApprovals)
+	require.Len(t, state.ResolvedApprovals, 2)
+	assert.False(t, state.ResolvedApprovals[0].Approve)
+	assert.Equal(t, "too risky", state.ResolvedApprovals[0].Reason)
+	assert.True(t, state.ResolvedApprovals[1].Approve)
+
+	err = run(ctx, []string{"approvals", "approve", "-state", stateDir, "s1", "req_2"}, nil, &out, nil)
+	assert.ErrorIs(t, err, workflowrunner.ErrApprovalNotFound)
+	err = run(ctx, []string{"state", "show", "-state", stateDir, "missing"}, nil, &out, nil)
+	assert.ErrorIs(t, err, workflowrunner.ErrExecutionNotFound)
+}
+
+func TestInputs(t *testing.T) {
+	ctx := t.Context()
+	stateDir := t.TempDir()
+	store := workflowrunner.NewDirExecutionStateStore(stateDir)
+	require.NoError(t, store.Save(ctx, workflowrunner.WorkflowExecutionState{
+		SessionID:     "s1",
+		WorkflowName:  "wf",
+		Status:        workflowrunner.ExecutionStatusWaitingInput,
+		PendingInputs: []workflowrunner.InputRequestState{{RequestID: "call_1", Question: "Contact?", Fields: []string{"email"}}},
+	}))
+
+	var out strings.Builder
+	require.NoError(t, run(ctx, []string{"inputs", "list", "-state", stateDir, "s1"}, nil, &out, nil))
+	var pending []workflowrunner.InputRequestState
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &pending))
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Contact?", pending[0].Question)
+
+	require.NoError(t, run(ctx, []string{"inputs", "provide", "-state", stateDir, "-json", "s1", "call_1", `{"email": "a@example.com"}`}, nil, &out, nil))
+	state, _, err := store.Load(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, workflowrunner.ExecutionStatusRunning, state.Status)
+	assert.Empty(t, state.PendingInputs)
+	require.Len(t, state.ProvidedInputs, 1)
+	assert.Equal(t, map[string]any{"email": "a@example.com"}, state.ProvidedInputs[0].Answer)
+	assert.Equal(t, "Contact?", state.ProvidedInputs[0].Question)
+}
+
+func TestLoad(t *testing.T) {
+	corpus := filepath.Join(t.TempDir(), "corpus.jsonl")
+	compact, err := json.Marshal(json.RawMessage(testManifest))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(corpus, append(compact, '\n'), 0o644))
+
+	var out strings.Builder
+	require.NoError(t, run(t.Context(), []string{"load", "-n", "6", "-c", "3", "-fake", corpus}, nil, &out, nil))
+	var report loadtest.Report
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &report))
+	assert.Equal(t, 6, report.Runs)
+	assert.Equal(t, 3, report.Concurrency)
+	assert.Equal(t, 6, report.Completed)
+	assert.Zero(t, report.Failed)
+	assert.Equal(t, uint64(6), report.Turns)
+	assert.Equal(t, 6, report.RunLatency.Samples)
+	assert.Equal(t, 6, report.TurnLatency.Samples)
+	assert.Positive(t, report.RunsPerSecond)
+	assert.Positive(t, report.Memory.TotalAllocBytes)
+
+	err = run(t.Context(), []string{"load"}, nil, &out, nil)
+	assert.EqualError(t, err, "usage: "+loadUsage)
+}
@@ -0,0 +1,245 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+func runValidate(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: wfrun validate FILE...")
+	}
+	invalid := 0
+	for _, path := range args {
+		data, err := readManifest(path, stdin)
+		if err != nil {
+			return err
+		}
+		var manifestErrs workflowrunner.ManifestErrors
+		switch err := workflowrunner.ValidateManifestBytes(data); {
+		case err == nil:
+			_, _ = fmt.Fprintf(stdout, "%s: ok\n", path)
+		case errors.As(err, &manifestErrs):
+			invalid++
+			for _, e := range manifestErrs {
+				_, _ = fmt.Fprintf(stdout, "%s: %s\n", path, e)
+			}
+		default:
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d manifests are invalid", invalid, len(args))
+	}
+	return nil
+}
+
+func runLint(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	strict := fs.Bool("strict", false, "fail when warnings are found")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w\nusage: wfrun lint [-strict] FILE...", err)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: wfrun lint [-strict] FILE...")
+	}
+	service := newService(defaultStateDir, defaultSessionsDir)
+	warned := 0
+	for _, path := range fs.Args() {
+		req, err := loadRequest(path, stdin)
+		if err != nil {
+			return err
+		}
+		warnings, err := service.Lint(ctx, req)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(warnings) == 0 {
+			_, _ = fmt.Fprintf(stdout, "%s: ok\n", path)
+			continue
+		}
+		warned++
+		for _, w := range warnings {
+			_, _ = fmt.Fprintf(stdout, "%s: warning: %s\n", path, w)
+		}
+	}
+	if *strict && warned > 0 {
+		return fmt.Errorf("%d of %d manifests have warnings", warned, fs.NArg())
+	}
+	return nil
+}
+
+func runPlan(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	if err := parseFlags(fs, args, 1, "wfrun plan FILE"); err != nil {
+		return err
+	}
+	req, err := loadRequest(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	if !hasCallback(req) {
+		req.Callback.Mode = workflowrunner.CallbackModeStream
+	}
+	plan, err := newService(defaultStateDir, defaultSessionsDir).Plan(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, plan)
+}
+
+// runOutput is the RunSummary printed by the run and resume commands, with
+// the error as a message.
+type runOutput struct {
+	workflowrunner.RunSummary
+	Error string `json:"error,omitempty"`
+}
+
+func runRun(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, resume bool) error {
+	name, usage := "run", "wfrun run [-state DIR] [-sessions DIR] [-session ID] [-query TEXT] [-events] FILE"
+	if resume {
+		name, usage = "resume", "wfrun resume [-state DIR] [-sessions DIR] [-session ID] [-events] FILE"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	stateDir := fs.String("state", defaultStateDir, "directory of the execution states")
+	sessionsDir := fs.String("sessions", defaultSessionsDir, "directory of the SQLite sessions")
+	sessionID := fs.String("session", "", "session ID, overriding the one of the manifest")
+	var query *string
+	if !resume {
+		query = fs.String("query", "", "query, overriding the one of the manifest")
+	}
+	events := fs.Bool("events", false, "write the callback events to stderr as JSON lines")
+	if err := parseFlags(fs, args, 1, usage); err != nil {
+		return err
+	}
+
+	req, err := loadRequest(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	if *sessionID != "" {
+		req.Session.SessionID = *sessionID
+	}
+	if query != nil && *query != "" {
+		req.Query = *query
+		req.Batch = nil
+	}
+
+	var publisher workflowrunner.CallbackPublisher
+	switch {
+	case *events:
+		publisher = &jsonLinesPublisher{w: stderr}
+	case !hasCallback(req):
+		publisher = discardPublisher{}
+	}
+
+	service := newService(*stateDir, *sessionsDir)
+	if !resume && req.Batch != nil {
+		return runBatch(ctx, service, req, publisher, stdout)
+	}
+	var task *asynctask.Task[workflowrunner.RunSummary]
+	switch {
+	case resume && publisher != nil:
+		task, err = service.ResumeWithPublisher(ctx, req, publisher)
+	case resume:
+		task, err = service.Resume(ctx, req)
+	case publisher != nil:
+		task, err = service.ExecuteWithPublisher(ctx, req, publisher)
+	default:
+		task, err = service.Execute(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := task.Await()
+	output := runOutput{RunSummary: result.Value}
+	if result.Error != nil {
+		output.Error = result.Error.Error()
+	}
+	if err := writeJSON(stdout, output); err != nil {
+		return err
+	}
+	switch {
+	case result.Error != nil:
+		return result.Error
+	case result.Value.Status == workflowrunner.ExecutionStatusWaitingApproval,
+		result.Value.Status == workflowrunner.ExecutionStatusWaitingInput:
+		return errSuspended
+	}
+	return nil
+}
+
+// runBatch runs the items of a batch manifest, printing the BatchSummary.
+func runBatch(ctx context.Context, service *workflowrunner.RunnerService, req workflowrunner.WorkflowRequest, publisher workflowrunner.CallbackPublisher, stdout io.Writer) error {
+	var task *asynctask.Task[workflowrunner.BatchSummary]
+	var err error
+	if publisher != nil {
+		task, err = service.ExecuteBatchWithPublisher(ctx, req, publisher)
+	} else {
+		task, err = service.ExecuteBatch(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	result := task.Await()
+	if result.Error != nil {
+		return result.Error
+	}
+	summary := result.Value
+	if err := writeJSON(stdout, summary); err != nil {
+		return err
+	}
+	switch {
+	case summary.Failed > 0:
+		return fmt.Errorf("%d of %d runs failed", summary.Failed, len(summary.Runs))
+	case summary.Suspended > 0:
+		return errSuspended
+	}
+	return nil
+}
+
+// jsonLinesPublisher writes the callback events as JSON lines.
+type jsonLinesPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (p *jsonLinesPublisher) Publish(_ context.Context, event workflowrunner.CallbackEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(data, '\n'))
+	return err
+}
+
+// discardPublisher drops the events of requests without a callback.
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(context.Context, workflowrunner.CallbackEvent) error { return nil }
@@ -0,0 +1,118 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evals
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Change is a difference between a baseline report and the current one: a
+// case changing outcome, or the mean score of a grader changing beyond the
+// tolerance.
+type Change struct {
+	// CaseID of the case, empty for the changes of the mean score of Grader.
+	CaseID string
+	Grader string
+
+	Baseline float64
+	Current  float64
+}
+
+func (c Change) String() string {
+	if c.CaseID != "" {
+		return fmt.Sprintf("case %s: %s", c.CaseID, passedText(c.Baseline)+" -> "+passedText(c.Current))
+	}
+	return fmt.Sprintf("grader %s: mean score %.3f -> %.3f", c.Grader, c.Baseline, c.Current)
+}
+
+func passedText(v float64) string {
+	if v == 1 {
+		return "passed"
+	}
+	return "failed"
+}
+
+// Comparison lists the changes of a report against a baseline.
+type Comparison struct {
+	Regressions  []Change
+	Improvements []Change
+}
+
+// HasRegressions reports whether the report regressed from the baseline.
+func (c Comparison) HasRegressions() bool {
+	return len(c.Regressions) > 0
+}
+
+func (c Comparison) String() string {
+	var b strings.Builder
+	for _, change := range c.Regressions {
+		fmt.Fprintf(&b, "regression: %s\n", change)
+	}
+	for _, change := range c.Improvements {
+		fmt.Fprintf(&b, "improvement: %s\n", change)
+	}
+	return b.String()
+}
+
+// Compare compares the report against a baseline: the cases of both which
+// changed outcome, and the graders whose mean score changed by more than the
+// tolerance, e.g. 0.05. Cases and graders missing from either report are
+// ignored.
+func Compare(baseline, current *Report, tolerance float64) Comparison {
+	var comparison Comparison
+
+	baselineCases := make(map[string]CaseResult, len(baseline.Results))
+	for _, result := range baseline.Results {
+		baselineCases[result.CaseID] = result
+	}
+	for _, result := range current.Results {
+		before, ok := baselineCases[result.CaseID]
+		if !ok || before.Passed == result.Passed {
+			continue
+		}
+		change := Change{CaseID: result.CaseID, Baseline: passedValue(before.Passed), Current: passedValue(result.Passed)}
+		if result.Passed {
+			comparison.Improvements = append(comparison.Improvements, change)
+		} else {
+			comparison.Regressions = append(comparison.Regressions, change)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(current.Graders)) {
+		before, ok := baseline.Graders[name]
+		if !ok {
+			continue
+		}
+		now := current.Graders[name]
+		change := Change{Grader: name, Baseline: before.MeanScore, Current: now.MeanScore}
+		switch delta := now.MeanScore - before.MeanScore; {
+		case delta < -tolerance:
+			comparison.Regressions = append(comparison.Regressions, change)
+		case delta > tolerance:
+			comparison.Improvements = append(comparison.Improvements, change)
+		}
+	}
+	return comparison
+}
+
+func passedValue(passed bool) float64 {
+	if passed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evals evaluates agents over datasets of cases: it runs an agent
+// over each case with a Runner, grades the outputs, aggregates the scores
+// and compares them against a baseline report to catch regressions.
+//
+//	cases, err := evals.LoadDataset("cases.jsonl")
+//	report, err := evals.Evaluator{
+//		Agent:   agent,
+//		Graders: map[string]evals.Grader{"exact": evals.ExactMatch{}},
+//	}.Run(ctx, cases)
+//
+// The model of the runs is the one of the agent, or of the Runner
+// configuration, e.g. a fake model replaying recorded outputs.
+package evals
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Case is an input of the agent, with the expected output.
+type Case struct {
+	// ID of the case, unique in the dataset. Defaults to its line number.
+	ID string `json:"id,omitempty"`
+
+	Input string `json:"input"`
+
+	// Optional expected output. JSON values other than strings are kept as
+	// JSON text, for the agents with structured outputs.
+	Expected string `json:"expected,omitempty"`
+
+	// Optional metadata of the case, e.g. tags.
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+func (c *Case) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID       string          `json:"id"`
+		Input    string          `json:"input"`
+		Expected json.RawMessage `json:"expected"`
+		Metadata map[string]any  `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Case{ID: raw.ID, Input: raw.Input, Metadata: raw.Metadata}
+	if len(raw.Expected) > 0 && !bytes.Equal(raw.Expected, []byte("null")) {
+		if err := json.Unmarshal(raw.Expected, &c.Expected); err != nil {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, raw.Expected); err != nil {
+				return err
+			}
+			c.Expected = compact.String()
+		}
+	}
+	return nil
+}
+
+// LoadDataset reads the JSONL dataset file at path, see ReadDataset.
+func LoadDataset(path string) ([]Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return ReadDataset(f)
+}
+
+// ReadDataset reads a JSONL dataset, with a JSON Case on each line. Blank
+// lines are skipped.
+func ReadDataset(r io.Reader) ([]Case, error) {
+	var cases []Case
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		if c.Input == "" {
+			return nil, fmt.Errorf("dataset line %d: input is required", line)
+		}
+		if c.ID == "" {
+			c.ID = strconv.Itoa(line)
+		}
+		if _, dup := seen[c.ID]; dup {
+			return nil, fmt.Errorf("dataset line %d: duplicate id %q", line, c.ID)
+		}
+		seen[c.ID] = struct{}{}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("dataset has no cases")
+	}
+	return cases, nil
+}
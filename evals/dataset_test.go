@@ -0,0 +1,50 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evals_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/evals"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDataset(t *testing.T) {
+	cases, err := evals.ReadDataset(strings.NewReader(`{"id": "greet", "input": "hi", "expected": "HI"}
+
+{"input": "order", "expected": {"id": 1, "items": ["a"]}, "metadata": {"tag": "json"}}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []evals.Case{
+		{ID: "greet", Input: "hi", Expected: "HI"},
+		{ID: "3", Input: "order", Expected: `{"id":1,"items":["a"]}`, Metadata: map[string]any{"tag": "json"}},
+	}, cases)
+}
+
+func TestReadDatasetErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":  "{\n",
+		"missing input": `{"id": "a"}`,
+		"duplicate id":  "{\"id\": \"a\", \"input\": \"x\"}\n{\"id\": \"a\", \"input\": \"y\"}",
+		"empty":         "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := evals.ReadDataset(strings.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// DefaultConcurrency is the number of cases run at once, when the Evaluator
+// does not set it.
+const DefaultConcurrency = 4
+
+// Evaluator runs an agent over the cases of a dataset and grades its
+// outputs.
+type Evaluator struct {
+	Agent *agents.Agent
+
+	// Optional runner of the agent, e.g. with a RunConfig setting the model.
+	Runner agents.Runner
+
+	// Graders of the outputs, by name.
+	Graders map[string]Grader
+
+	// Optional number of cases run at once. Default: DefaultConcurrency.
+	Concurrency int
+}
+
+// CaseResult is the outcome of a case.
+type CaseResult struct {
+	CaseID   string `json:"case_id"`
+	Input    string `json:"input"`
+	Expected string `json:"expected,omitempty"`
+	Output   string `json:"output"`
+
+	// Error of the run, if failed. Failed runs are not graded.
+	Error string `json:"error,omitempty"`
+
+	// Grades by grader name. Grader failures are reported as failed grades.
+	Grades map[string]Grade `json:"grades,omitempty"`
+
+	// Passed reports whether the run succeeded and all its grades passed.
+	Passed bool `json:"passed"`
+
+	DurationMS   int64  `json:"duration_ms"`
+	InputTokens  uint64 `json:"input_tokens"`
+	OutputTokens uint64 `json:"output_tokens"`
+}
+
+// GraderSummary aggregates the grades of a grader over the cases. Failed
+// runs count as failed grades with a zero score.
+type GraderSummary struct {
+	MeanScore float64 `json:"mean_score"`
+	PassRate  float64 `json:"pass_rate"`
+	Passed    int     `json:"passed"`
+	Failed    int     `json:"failed"`
+}
+
+// Report is the outcome of an evaluation, which can be saved as the baseline
+// of the next ones, see Compare.
+type Report struct {
+	Results []CaseResult             `json:"results"`
+	Graders map[string]GraderSummary `json:"graders"`
+
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Errors   int     `json:"errors"`
+	PassRate float64 `json:"pass_rate"`
+}
+
+// Run runs the agent over the cases, and returns the report of their
+// grades, with the results in the order of the cases. It fails only if the
+// context is canceled.
+func (e Evaluator) Run(ctx context.Context, cases []Case) (*Report, error) {
+	if e.Agent == nil {
+		return nil, errors.New("evals: agent is required")
+	}
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]CaseResult, len(cases))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range cases {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, context.Cause(ctx)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = e.runCase(ctx, c)
+		}()
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return newReport(results, slices.Sorted(maps.Keys(e.Graders))), nil
+}
+
+func (e Evaluator) runCase(ctx context.Context, c Case) CaseResult {
+	result := CaseResult{CaseID: c.ID, Input: c.Input, Expected: c.Expected}
+	startedAt := time.Now()
+	runResult, err := e.Runner.Run(ctx, e.Agent, c.Input)
+	result.DurationMS = time.Since(startedAt).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for _, response := range runResult.RawResponses {
+		if response.Usage != nil {
+			result.InputTokens += response.Usage.InputTokens
+			result.OutputTokens += response.Usage.OutputTokens
+		}
+	}
+	result.Output = outputText(runResult.FinalOutput)
+
+	sample := Sample{Case: c, Output: result.Output, Result: runResult}
+	result.Grades = make(map[string]Grade, len(e.Graders))
+	result.Passed = true
+	for name, grader := range e.Graders {
+		grade, err := grader.Grade(ctx, sample)
+		if err != nil {
+			grade = Grade{Reason: fmt.Sprintf("grader error: %v", err)}
+		}
+		result.Grades[name] = grade
+		result.Passed = result.Passed && grade.Passed
+	}
+	return result
+}
+
+func outputText(output any) string {
+	if s, ok := output.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Sprintf("%v", output)
+	}
+	return string(b)
+}
+
+func newReport(results []CaseResult, graders []string) *Report {
+	report := &Report{Results: results, Graders: make(map[string]GraderSummary, len(graders))}
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			report.Errors++
+			report.Failed++
+		case result.Passed:
+			report.Passed++
+		default:
+			report.Failed++
+		}
+	}
+	for _, name := range graders {
+		var summary GraderSummary
+		var total float64
+		for _, result := range results {
+			grade := result.Grades[name]
+			total += grade.Score
+			if grade.Passed {
+				summary.Passed++
+			} else {
+				summary.Failed++
+			}
+		}
+		if len(results) > 0 {
+			summary.MeanScore = total / float64(len(results))
+			summary.PassRate = float64(summary.Passed) / float64(len(results))
+		}
+		report.Graders[name] = summary
+	}
+	if len(results) > 0 {
+		report.PassRate = float64(report.Passed) / float64(len(results))
+	}
+	return report
+}
+
+// SaveReport writes the report as JSON to path.
+func SaveReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err = os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads a report saved with SaveReport.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var report Report
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return &report, nil
+}
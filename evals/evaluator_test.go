@@ -0,0 +1,186 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evals_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/evals"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperModel answers with the input in upper case, and fails on "fail".
+type upperModel struct{}
+
+func (upperModel) GetResponse(_ context.Context, params agents.ModelResponseParams) (*agents.ModelResponse, error) {
+	input := agents.ItemHelpers().InputToNewInputList(params.Input)
+	text := input[len(input)-1].OfMessage.Content.OfString.Value
+	if text == "fail" {
+		return nil, errors.New("model failure")
+	}
+	return &agents.ModelResponse{
+		Output: []agents.TResponseOutputItem{agentstesting.GetTextMessage(strings.ToUpper(text))},
+		Usage:  &usage.Usage{Requests: 1, InputTokens: 3, OutputTokens: 2},
+	}, nil
+}
+
+func (upperModel) StreamResponse(context.Context, agents.ModelResponseParams, agents.ModelStreamResponseCallback) error {
+	return errors.New("not implemented")
+}
+
+func newUpperAgent() *agents.Agent {
+	return &agents.Agent{Name: "upper", Model: param.NewOpt(agents.NewAgentModel(upperModel{}))}
+}
+
+func TestEvaluatorRun(t *testing.T) {
+	cases := []evals.Case{
+		{ID: "a", Input: "hello", Expected: "HELLO"},
+		{ID: "b", Input: "bye", Expected: "ciao"},
+		{ID: "c", Input: "fail"},
+		{ID: "d", Input: "ok", Expected: " ok "},
+	}
+	report, err := evals.Evaluator{
+		Agent: newUpperAgent(),
+		Graders: map[string]evals.Grader{
+			"exact": evals.ExactMatch{TrimSpace: true, IgnoreCase: true},
+			"short": evals.GraderFunc(func(_ context.Context, s evals.Sample) (evals.Grade, error) {
+				if len(s.Output) > 3 {
+					return evals.Grade{Score: 0.5}, nil
+				}
+				return evals.Grade{Score: 1, Passed: true}, nil
+			}),
+		},
+		Concurrency: 2,
+	}.Run(t.Context(), cases)
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 4)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, []string{
+		report.Results[0].CaseID, report.Results[1].CaseID, report.Results[2].CaseID, report.Results[3].CaseID,
+	})
+	a := report.Results[0]
+	assert.Equal(t, "HELLO", a.Output)
+	assert.Equal(t, evals.Grade{Score: 1, Passed: true}, a.Grades["exact"])
+	assert.False(t, a.Passed)
+	assert.Equal(t, uint64(3), a.InputTokens)
+	assert.Equal(t, uint64(2), a.OutputTokens)
+
+	assert.Contains(t, report.Results[2].Error, "model failure")
+	assert.Empty(t, report.Results[2].Grades)
+	assert.True(t, report.Results[3].Passed)
+
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 0.25, report.PassRate)
+	assert.Equal(t, evals.GraderSummary{MeanScore: 0.5, PassRate: 0.5, Passed: 2, Failed: 2}, report.Graders["exact"])
+	assert.Equal(t, evals.GraderSummary{MeanScore: 0.625, PassRate: 0.5, Passed: 2, Failed: 2}, report.Graders["short"])
+}
+
+func TestEvaluatorReportsGraderErrors(t *testing.T) {
+	report, err := evals.Evaluator{
+		Agent: newUpperAgent(),
+		Graders: map[string]evals.Grader{
+			"broken": evals.GraderFunc(func(context.Context, evals.Sample) (evals.Grade, error) {
+				return evals.Grade{}, errors.New("boom")
+			}),
+		},
+	}.Run(t.Context(), []evals.Case{{ID: "a", Input: "x"}})
+	require.NoError(t, err)
+	assert.Equal(t, evals.Grade{Reason: "grader error: boom"}, report.Results[0].Grades["broken"])
+	assert.False(t, report.Results[0].Passed)
+}
+
+func TestExactMatchJSON(t *testing.T) {
+	grade, err := evals.ExactMatch{}.Grade(t.Context(), evals.Sample{
+		Case:   evals.Case{Expected: `{"a":1,"b":[true]}`},
+		Output: `{"b": [true], "a": 1}`,
+	})
+	require.NoError(t, err)
+	assert.True(t, grade.Passed)
+}
+
+func TestLLMJudge(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{
+			agentstesting.GetFinalOutputMessage(`{"score": 0.6, "reason": "partially correct"}`),
+		},
+	})
+	judge := evals.LLMJudge{
+		Criteria: "The answer is shouted.",
+		Model:    param.NewOpt(agents.NewAgentModel(model)),
+	}
+	grade, err := judge.Grade(t.Context(), evals.Sample{
+		Case:   evals.Case{Input: "hello", Expected: "HELLO"},
+		Output: "Hello",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, evals.Grade{Score: 0.6, Passed: false, Reason: "partially correct"}, grade)
+
+	input := agents.ItemHelpers().InputToNewInputList(model.LastTurnArgs.Input)
+	prompt := input[0].OfMessage.Content.OfString.Value
+	assert.Contains(t, prompt, "The answer is shouted.")
+	assert.Contains(t, prompt, "Expected output:\nHELLO")
+	assert.Contains(t, prompt, "Output:\nHello")
+
+	judge.PassThreshold = 0.5
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetFinalOutputMessage(`{"score": 0.6, "reason": "ok"}`)},
+	})
+	grade, err = judge.Grade(t.Context(), evals.Sample{Output: "x"})
+	require.NoError(t, err)
+	assert.True(t, grade.Passed)
+}
+
+func TestCompareWithBaseline(t *testing.T) {
+	graders := map[string]evals.Grader{"exact": evals.ExactMatch{}}
+	baseline, err := evals.Evaluator{Agent: newUpperAgent(), Graders: graders}.Run(t.Context(), []evals.Case{
+		{ID: "a", Input: "a", Expected: "A"},
+		{ID: "b", Input: "b", Expected: "x"},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, evals.SaveReport(path, baseline))
+	loaded, err := evals.LoadReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, loaded)
+
+	current, err := evals.Evaluator{Agent: newUpperAgent(), Graders: graders}.Run(t.Context(), []evals.Case{
+		{ID: "a", Input: "a", Expected: "y"},
+		{ID: "b", Input: "b", Expected: "B"},
+		{ID: "c", Input: "c", Expected: "z"},
+	})
+	require.NoError(t, err)
+
+	// The mean score drops from 0.5 to 0.333, within the tolerance.
+	comparison := evals.Compare(loaded, current, 0.2)
+	assert.True(t, comparison.HasRegressions())
+	assert.Equal(t, []evals.Change{{CaseID: "a", Baseline: 1, Current: 0}}, comparison.Regressions)
+	assert.Equal(t, []evals.Change{{CaseID: "b", Baseline: 0, Current: 1}}, comparison.Improvements)
+
+	comparison = evals.Compare(loaded, current, 0.05)
+	assert.Len(t, comparison.Regressions, 2)
+	assert.Equal(t, "regression: case a: passed -> failed\nregression: grader exact: mean score 0.500 -> 0.333\nimprovement: case b: failed -> passed\n", comparison.String())
+}
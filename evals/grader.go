@@ -0,0 +1,154 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+// Sample is the output of the agent for a case, to be graded.
+type Sample struct {
+	Case Case
+
+	// Output is the final output of the run: strings as they are, other
+	// values as JSON.
+	Output string
+
+	// Result of the run, e.g. for graders checking the items of the run.
+	Result *agents.RunResult
+}
+
+// Grade is the outcome of the grading of a sample.
+type Grade struct {
+	// Score between 0 and 1.
+	Score  float64 `json:"score"`
+	Passed bool    `json:"passed"`
+	// Optional explanation of the grade.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Grader grades the samples of an evaluation.
+type Grader interface {
+	Grade(ctx context.Context, sample Sample) (Grade, error)
+}
+
+// GraderFunc is a custom Grader.
+type GraderFunc func(ctx context.Context, sample Sample) (Grade, error)
+
+func (f GraderFunc) Grade(ctx context.Context, sample Sample) (Grade, error) {
+	return f(ctx, sample)
+}
+
+// ExactMatch passes the samples whose output matches the expected output of
+// the case. JSON outputs match JSON expected outputs with the same value,
+// regardless of their formatting.
+type ExactMatch struct {
+	// Compare the outputs ignoring leading and trailing spaces.
+	TrimSpace bool
+	// Compare the outputs ignoring the case.
+	IgnoreCase bool
+}
+
+func (m ExactMatch) Grade(_ context.Context, sample Sample) (Grade, error) {
+	output, expected := sample.Output, sample.Case.Expected
+	if m.TrimSpace {
+		output, expected = strings.TrimSpace(output), strings.TrimSpace(expected)
+	}
+	passed := output == expected ||
+		m.IgnoreCase && strings.EqualFold(output, expected) ||
+		jsonEqual(output, expected)
+	if passed {
+		return Grade{Score: 1, Passed: true}, nil
+	}
+	return Grade{Reason: fmt.Sprintf("expected %q, got %q", expected, output)}, nil
+}
+
+func jsonEqual(a, b string) bool {
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// DefaultJudgePassThreshold is the score passing the LLMJudge grades, when
+// not set.
+const DefaultJudgePassThreshold = 0.7
+
+// LLMJudge grades the samples with a model, asked to score the output of
+// the agent against the criteria and the expected output of the case.
+type LLMJudge struct {
+	// Criteria of the grading, e.g. "The answer is polite and cites the
+	// order number."
+	Criteria string
+
+	// Optional model of the judge. Defaults to the model of the Runner.
+	Model param.Opt[agents.AgentModel]
+
+	// Optional runner of the judge.
+	Runner agents.Runner
+
+	// Optional minimum score passing the grade. Default:
+	// DefaultJudgePassThreshold.
+	PassThreshold float64
+}
+
+type judgeVerdict struct {
+	// Score between 0 and 1.
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+const judgeInstructions = `You grade the output of an AI agent for the given input.
+Score the output between 0 (completely wrong) and 1 (fully correct), judging it
+against the criteria and, when given, the expected output. Explain the score
+briefly in the reason.`
+
+func (j LLMJudge) Grade(ctx context.Context, sample Sample) (Grade, error) {
+	judge := &agents.Agent{
+		Name:         "Judge",
+		Instructions: agents.InstructionsStr(judgeInstructions),
+		Model:        j.Model,
+		OutputType:   agents.OutputType[judgeVerdict](),
+	}
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Criteria:\n%s\n\nInput:\n%s\n\n", j.Criteria, sample.Case.Input)
+	if sample.Case.Expected != "" {
+		fmt.Fprintf(&prompt, "Expected output:\n%s\n\n", sample.Case.Expected)
+	}
+	fmt.Fprintf(&prompt, "Output:\n%s", sample.Output)
+
+	result, err := j.Runner.Run(ctx, judge, prompt.String())
+	if err != nil {
+		return Grade{}, fmt.Errorf("judge run failed: %w", err)
+	}
+	verdict, ok := result.FinalOutput.(judgeVerdict)
+	if !ok {
+		return Grade{}, fmt.Errorf("unexpected judge output %T", result.FinalOutput)
+	}
+	score := min(max(verdict.Score, 0), 1)
+	threshold := j.PassThreshold
+	if threshold == 0 {
+		threshold = DefaultJudgePassThreshold
+	}
+	return Grade{Score: score, Passed: score >= threshold, Reason: verdict.Reason}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/realtime"
+)
+
+type GetWeatherArgs struct {
+	City string `json:"city"`
+}
+
+func GetWeather(_ context.Context, args GetWeatherArgs) (string, error) {
+	return fmt.Sprintf("The weather in %s is sunny.", args.City), nil
+}
+
+var GetWeatherTool = agents.NewFunctionTool(
+	"get_weather",
+	"Get the current weather information for a specified city.",
+	GetWeather,
+)
+
+func main() {
+	spanishAgent := agents.New("Spanish agent").
+		WithInstructions("You only speak Spanish.").
+		WithHandoffDescription("A Spanish speaking agent.")
+
+	agent := agents.New("Assistant").
+		WithInstructions("You are a helpful assistant. If the user speaks Spanish, handoff to the Spanish agent.").
+		WithTools(GetWeatherTool).
+		WithAgentHandoffs(spanishAgent)
+
+	ctx := context.Background()
+
+	// The session only answers with text here: set the "audio" modality and
+	// send microphone audio with SendAudio for a voice conversation.
+	session, err := realtime.Runner{
+		Config: realtime.RunConfig{
+			Session: realtime.SessionConfig{Modalities: []string{"text"}},
+		},
+	}.Run(ctx, agent)
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = session.Close() }()
+
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		fmt.Print("> ")
+		for scanner.Scan() {
+			if err := session.SendMessage(ctx, scanner.Text()); err != nil {
+				fmt.Println("error:", err)
+			}
+		}
+		_ = session.Close()
+	}()
+
+	for event := range session.Events() {
+		switch e := event.(type) {
+		case realtime.TranscriptDeltaEvent:
+			fmt.Print(e.Delta)
+		case realtime.AgentEndEvent:
+			fmt.Print("\n> ")
+		case realtime.ToolStartEvent:
+			fmt.Printf("[%s called %s]\n", e.Agent.Name, e.Tool.Name)
+		case realtime.HandoffEvent:
+			fmt.Printf("[handoff from %s to %s]\n", e.FromAgent.Name, e.ToAgent.Name)
+		case realtime.ErrorEvent:
+			fmt.Println("error:", e.Err)
+		}
+	}
+	if err := session.Err(); err != nil {
+		panic(err)
+	}
+}
@@ -31,7 +31,7 @@ func main() {
 			agents.CodeInterpreterTool{
 				ToolConfig: responses.ToolCodeInterpreterParam{
 					Container: responses.ToolCodeInterpreterContainerUnionParam{
-						OfCodeInterpreterContainerAuto: &responses.ToolCodeInterpreterContainerCodeInterpreterContainerAutoParam{
+						OfCodeInterpreterToolAuto: &responses.ToolCodeInterpreterContainerCodeInterpreterContainerAutoParam{
 							Type: constant.ValueOf[constant.Auto](),
 						},
 					},
@@ -0,0 +1,311 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrSessionNotFound is returned when forking a session which holds no items.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ForkLink links a forked session to the session it was forked from.
+type ForkLink struct {
+	ParentSessionID string
+	// SharedItems is the number of leading items of the parent session
+	// shared by the fork.
+	SharedItems int
+}
+
+// ForkLinkStore is implemented by the sessions which store the link to the
+// session they were forked from, so that OpenForkedSession can reopen them.
+// The link is removed by ClearSession.
+type ForkLinkStore interface {
+	// LoadForkLink returns the stored link, if any.
+	LoadForkLink(context.Context) (ForkLink, bool, error)
+	// StoreForkLink stores the link, replacing any previous one.
+	StoreForkLink(context.Context, ForkLink) error
+}
+
+// ForkedSession is a copy-on-write fork of a session. The leading items of
+// the parent session are shared rather than copied: they are read from the
+// parent when needed, followed by the items stored in the child session.
+// New items are only added to the child session, so the parent conversation
+// is never modified; the parent items added after forking are not seen by
+// the fork.
+//
+// The parent session must keep the shared items: reading a fork whose parent
+// lost them fails. PurgeExpiredSessions keeps the parents of the forks it
+// does not delete.
+type ForkedSession struct {
+	parent Session
+	child  Session
+	mu     sync.Mutex
+	shared int
+}
+
+// ForkSession forks the first atIndex items of parent into child, so that
+// child can continue the conversation from that point without affecting
+// parent. A negative atIndex forks the whole history. The items are not
+// copied: the link to parent is stored by child, when it implements
+// ForkLinkStore, to reopen the fork with OpenForkedSession.
+//
+// The parent session must exist, the child session must be empty, and atIndex
+// must not separate a tool call from its output.
+func ForkSession(ctx context.Context, parent, child Session, atIndex int) (*ForkedSession, error) {
+	existing, err := child.GetItems(ctx, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child session items: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("cannot fork into a non-empty session")
+	}
+
+	parentID := parent.SessionID(ctx)
+	items, err := parent.GetItems(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent session items: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("parent session %q: %w", parentID, ErrSessionNotFound)
+	}
+	if atIndex < 0 {
+		atIndex = len(items)
+	}
+	if atIndex > len(items) {
+		return nil, fmt.Errorf("fork index %d out of range: session has %d items", atIndex, len(items))
+	}
+
+	for _, item := range items[atIndex:] {
+		if !isToolCallOutput(item) {
+			continue
+		}
+		callID := itemCallID(item)
+		for _, kept := range items[:atIndex] {
+			if !isToolCallOutput(kept) && itemCallID(kept) == callID {
+				return nil, fmt.Errorf("fork index %d separates tool call %q from its output", atIndex, callID)
+			}
+		}
+	}
+
+	if store, ok := child.(ForkLinkStore); ok {
+		err = store.StoreForkLink(ctx, ForkLink{ParentSessionID: parentID, SharedItems: atIndex})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store fork link: %w", err)
+		}
+	}
+	return &ForkedSession{parent: parent, child: child, shared: atIndex}, nil
+}
+
+// OpenForkedSession reopens the fork whose link is stored by session, opening
+// its parent with openParent; parents which are forks themselves are
+// reopened too. The session is returned as it is when it stores no link.
+func OpenForkedSession(
+	ctx context.Context,
+	session Session,
+	openParent func(ctx context.Context, sessionID string) (Session, error),
+) (Session, error) {
+	return openForkedSession(ctx, session, openParent, map[string]bool{})
+}
+
+func openForkedSession(
+	ctx context.Context,
+	session Session,
+	openParent func(ctx context.Context, sessionID string) (Session, error),
+	seen map[string]bool,
+) (Session, error) {
+	store, ok := session.(ForkLinkStore)
+	if !ok {
+		return session, nil
+	}
+	link, ok, err := store.LoadForkLink(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fork link: %w", err)
+	}
+	if !ok {
+		return session, nil
+	}
+
+	seen[session.SessionID(ctx)] = true
+	if seen[link.ParentSessionID] {
+		return nil, fmt.Errorf("session %q is forked from itself", link.ParentSessionID)
+	}
+	parent, err := openParent(ctx, link.ParentSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parent session %q: %w", link.ParentSessionID, err)
+	}
+	forkedParent, err := openForkedSession(ctx, parent, openParent, seen)
+	if err != nil {
+		return nil, errors.Join(err, closeSessions(parent))
+	}
+	return &ForkedSession{parent: forkedParent, child: session, shared: link.SharedItems}, nil
+}
+
+func (s *ForkedSession) SessionID(ctx context.Context) string {
+	return s.child.SessionID(ctx)
+}
+
+// SharedItems returns the number of leading items of the parent session
+// shared by the fork.
+func (s *ForkedSession) SharedItems() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shared
+}
+
+func (s *ForkedSession) GetItems(ctx context.Context, limit int) ([]TResponseInputItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shared == 0 {
+		return s.child.GetItems(ctx, limit)
+	}
+	items, err := s.sharedItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	own, err := s.child.GetItems(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, own...)
+
+	if limit > 0 && len(items) > limit {
+		items = items[len(items)-limit:]
+		// Tool call outputs are never returned without their call.
+		if isToolCallOutput(items[0]) {
+			items = items[1:]
+		}
+	}
+	return items, nil
+}
+
+func (s *ForkedSession) AddItems(ctx context.Context, items []TResponseInputItem) error {
+	return s.child.AddItems(ctx, items)
+}
+
+// PopItem removes and returns the most recent item of the fork. Once the
+// items of the child session are exhausted, the shared items are popped by
+// sharing one item less, leaving the parent session untouched.
+func (s *ForkedSession) PopItem(ctx context.Context) (*TResponseInputItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.child.PopItem(ctx)
+	if err != nil || item != nil || s.shared == 0 {
+		return item, err
+	}
+
+	items, err := s.sharedItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if store, ok := s.child.(ForkLinkStore); ok {
+		link := ForkLink{ParentSessionID: s.parent.SessionID(ctx), SharedItems: s.shared - 1}
+		if err = store.StoreForkLink(ctx, link); err != nil {
+			return nil, fmt.Errorf("failed to store fork link: %w", err)
+		}
+	}
+	s.shared--
+	return &items[s.shared], nil
+}
+
+// ClearSession clears the child session and stops sharing the parent items.
+func (s *ForkedSession) ClearSession(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.child.ClearSession(ctx); err != nil {
+		return err
+	}
+	s.shared = 0
+	return nil
+}
+
+// Close closes the parent and child sessions.
+func (s *ForkedSession) Close() error {
+	return closeSessions(s.parent, s.child)
+}
+
+// sharedItems returns a copy of the items shared with the parent session.
+func (s *ForkedSession) sharedItems(ctx context.Context) ([]TResponseInputItem, error) {
+	items, err := s.parent.GetItems(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent session items: %w", err)
+	}
+	if len(items) < s.shared {
+		return nil, fmt.Errorf("parent session %q holds %d items, %d of which are forked",
+			s.parent.SessionID(ctx), len(items), s.shared)
+	}
+	return slices.Clip(items[:s.shared]), nil
+}
+
+// Fork creates a new session, stored in the same database, forked from the
+// first atIndex items of this session, see ForkSession. A negative atIndex
+// forks the whole history. The original session is left untouched.
+//
+// The returned session owns the handles it opens on the database; closing it
+// leaves this session open. The fork is reopened by OpenForkedSession.
+func (s *SQLiteSession) Fork(ctx context.Context, newSessionID string, atIndex int) (*ForkedSession, error) {
+	if newSessionID == s.sessionID {
+		return nil, errors.New("forked session ID must differ from the original one")
+	}
+	open := func(ctx context.Context, sessionID string) (Session, error) {
+		session, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+			SessionID:        sessionID,
+			Namespace:        s.namespace,
+			DBDataSourceName: s.dbDSN,
+			SessionTable:     s.sessionTable,
+			MessagesTable:    s.messagesTable,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return session, nil
+	}
+
+	opened, err := open(ctx, s.sessionID)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := OpenForkedSession(ctx, opened, open)
+	if err != nil {
+		return nil, errors.Join(err, closeSessions(opened))
+	}
+	forked, err := open(ctx, newSessionID)
+	if err != nil {
+		return nil, errors.Join(err, closeSessions(parent))
+	}
+	fork, err := ForkSession(ctx, parent, forked, atIndex)
+	if err != nil {
+		return nil, errors.Join(err, closeSessions(parent, forked))
+	}
+	return fork, nil
+}
+
+// closeSessions closes the sessions which can be closed.
+func closeSessions(sessions ...Session) error {
+	var errs []error
+	for _, session := range sessions {
+		if closer, ok := session.(interface{ Close() error }); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSession_Fork(t *testing.T) {
+	ctx := t.Context()
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	open := func(ctx context.Context, sessionID string) (Session, error) {
+		return NewSQLiteSession(ctx, SQLiteSessionParams{SessionID: sessionID, DBDataSourceName: dsn})
+	}
+
+	session, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+		SessionID:        "original",
+		DBDataSourceName: dsn,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, session.Close()) })
+
+	items := []TResponseInputItem{
+		trimTestMessage(responses.EasyInputMessageRoleUser, "Hello"),
+		trimTestMessage(responses.EasyInputMessageRoleAssistant, "Hi there!"),
+		{OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+			CallID:    "call_1",
+			Name:      "lookup",
+			Arguments: "{}",
+		}},
+		responses.ResponseInputItemParamOfFunctionCallOutput("call_1", "result"),
+	}
+	require.NoError(t, session.AddItems(ctx, items))
+
+	t.Run("at index", func(t *testing.T) {
+		forked, err := session.Fork(ctx, "fork_1", 2)
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, forked.Close()) })
+
+		assert.Equal(t, "fork_1", forked.SessionID(ctx))
+		forkedItems, err := forked.GetItems(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, forkedItems, 2)
+		assert.Equal(t, "Hello", forkedItems[0].OfMessage.Content.OfString.Value)
+
+		// Writing to the fork leaves the original untouched.
+		reply := trimTestMessage(responses.EasyInputMessageRoleUser, "What if?")
+		require.NoError(t, forked.AddItems(ctx, []TResponseInputItem{reply}))
+		originalItems, err := session.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, originalItems, 4)
+
+		// The shared items are not copied.
+		stored, err := open(ctx, "fork_1")
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, closeSessions(stored)) })
+		storedItems, err := stored.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{reply}, storedItems)
+
+		// The reopened fork shares them again.
+		reopened, err := OpenForkedSession(ctx, stored, open)
+		require.NoError(t, err)
+		reopenedItems, err := reopened.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, append(items[:2:2], reply), reopenedItems)
+		lastItems, err := reopened.GetItems(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{items[1], reply}, lastItems)
+	})
+
+	t.Run("whole history", func(t *testing.T) {
+		forked, err := session.Fork(ctx, "fork_2", -1)
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, forked.Close()) })
+
+		forkedItems, err := forked.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, forkedItems, 4)
+		assert.Equal(t, 4, forked.SharedItems())
+	})
+
+	t.Run("popping shared items", func(t *testing.T) {
+		forked, err := session.Fork(ctx, "fork_3", 2)
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, forked.Close()) })
+
+		popped, err := forked.PopItem(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, popped)
+		assert.Equal(t, "Hi there!", popped.OfMessage.Content.OfString.Value)
+		assert.Equal(t, 1, forked.SharedItems())
+
+		stored, err := open(ctx, "fork_3")
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, closeSessions(stored)) })
+		link, ok, err := stored.(ForkLinkStore).LoadForkLink(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, ForkLink{ParentSessionID: "original", SharedItems: 1}, link)
+
+		originalItems, err := session.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, originalItems, 4)
+
+		// Clearing the fork stops sharing the original items.
+		require.NoError(t, forked.ClearSession(ctx))
+		_, ok, err = stored.(ForkLinkStore).LoadForkLink(ctx)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		forkedItems, err := forked.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, forkedItems)
+	})
+
+	t.Run("fork of a fork", func(t *testing.T) {
+		forked, err := session.Fork(ctx, "fork_4", 2)
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, forked.Close()) })
+		require.NoError(t, forked.AddItems(ctx, items[:1]))
+
+		stored, err := open(ctx, "fork_4")
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, closeSessions(stored)) })
+		nested, err := stored.(*SQLiteSession).Fork(ctx, "fork_5", -1)
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, nested.Close()) })
+
+		nestedItems, err := nested.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, append(items[:2:2], items[0]), nestedItems)
+	})
+
+	t.Run("splitting a tool call", func(t *testing.T) {
+		_, err := session.Fork(ctx, "fork_6", 3)
+		assert.ErrorContains(t, err, "separates tool call")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := session.Fork(ctx, "fork_7", 5)
+		assert.ErrorContains(t, err, "out of range")
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		missing, err := NewSQLiteSession(ctx, SQLiteSessionParams{SessionID: "missing", DBDataSourceName: dsn})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, missing.Close()) })
+
+		_, err = missing.Fork(ctx, "fork_8", -1)
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("non-empty target", func(t *testing.T) {
+		target, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+			SessionID:        "target",
+			DBDataSourceName: filepath.Join(t.TempDir(), "target.db"),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, target.Close()) })
+		require.NoError(t, target.AddItems(ctx, items[:1]))
+
+		_, err = ForkSession(ctx, session, target, 1)
+		assert.ErrorContains(t, err, "non-empty")
+	})
+}
+
+func TestSQLiteSession_ForkLinkMigration(t *testing.T) {
+	ctx := t.Context()
+	dsn := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE agent_sessions (
+			session_id TEXT PRIMARY KEY,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	session, err := NewSQLiteSession(ctx, SQLiteSessionParams{SessionID: "s1", DBDataSourceName: dsn})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, session.Close()) })
+
+	require.NoError(t, session.StoreForkLink(ctx, ForkLink{ParentSessionID: "s0", SharedItems: 3}))
+	link, ok, err := session.LoadForkLink(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, ForkLink{ParentSessionID: "s0", SharedItems: 3}, link)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetentionPolicy describes when stored sessions expire.
+// Zero durations disable the corresponding check.
+type RetentionPolicy struct {
+	// Sessions created longer than MaxAge ago are deleted.
+	MaxAge time.Duration
+
+	// Sessions not updated for longer than MaxIdle are deleted.
+	MaxIdle time.Duration
+}
+
+func (p RetentionPolicy) isZero() bool {
+	return p.MaxAge <= 0 && p.MaxIdle <= 0
+}
+
+// A SessionPurger deletes stored sessions falling outside a RetentionPolicy.
+//
+// Purging operates on the whole backing store, not only on the session the
+// method is called on.
+type SessionPurger interface {
+	// PurgeExpiredSessions deletes expired sessions along with their items,
+	// and returns the number of deleted sessions.
+	PurgeExpiredSessions(ctx context.Context, policy RetentionPolicy) (int64, error)
+}
+
+// A SessionKeyPurger is a SessionPurger which also reports the keys of the
+// sessions it deletes, see NamespacedSessionKey, so that the resources they
+// were holding can be released.
+type SessionKeyPurger interface {
+	SessionPurger
+	// PurgeExpiredSessionKeys deletes expired sessions along with their
+	// items, and returns the keys of the deleted sessions.
+	PurgeExpiredSessionKeys(ctx context.Context, policy RetentionPolicy) ([]string, error)
+}
+
+// RunSessionGC calls purger.PurgeExpiredSessions every interval until ctx is
+// done. Errors are passed to onError, if not nil, and do not stop the loop.
+//
+// It blocks, so it is usually started in its own goroutine.
+func RunSessionGC(
+	ctx context.Context,
+	purger SessionPurger,
+	policy RetentionPolicy,
+	interval time.Duration,
+	onError func(error),
+) {
+	if policy.isZero() || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := purger.PurgeExpiredSessions(ctx, policy); err != nil && onError != nil {
+			onError(fmt.Errorf("session GC failed: %w", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSession_PurgeExpiredSessions(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	newSession := func(id string) *SQLiteSession {
+		session, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+			SessionID:        id,
+			DBDataSourceName: dbPath,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, session.Close()) })
+		require.NoError(t, session.AddItems(ctx, []TResponseInputItem{
+			trimTestMessage(responses.EasyInputMessageRoleUser, "Hello"),
+		}))
+		return session
+	}
+
+	stale := newSession("stale")
+	fresh := newSession("fresh")
+
+	_, err := stale.db.ExecContext(ctx,
+		`UPDATE agent_sessions SET updated_at = datetime('now', '-2 hours') WHERE session_id = ?`, "stale")
+	require.NoError(t, err)
+
+	t.Run("zero policy", func(t *testing.T) {
+		deleted, err := fresh.PurgeExpiredSessions(ctx, RetentionPolicy{})
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("max idle", func(t *testing.T) {
+		deleted, err := fresh.PurgeExpiredSessions(ctx, RetentionPolicy{MaxIdle: time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		items, err := stale.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		items, err = fresh.GetItems(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+	})
+}
+
+func TestSQLiteSession_PurgeExpiredSessionsKeepsForkParents(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	for _, namespace := range []string{"", "tenant_a"} {
+		t.Run("namespace "+namespace, func(t *testing.T) {
+			parent, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+				SessionID:        "parent",
+				Namespace:        namespace,
+				DBDataSourceName: dbPath,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { assert.NoError(t, parent.Close()) })
+			require.NoError(t, parent.AddItems(ctx, []TResponseInputItem{
+				trimTestMessage(responses.EasyInputMessageRoleUser, "Hello"),
+			}))
+			fork, err := parent.Fork(ctx, "fork", -1)
+			require.NoError(t, err)
+			t.Cleanup(func() { assert.NoError(t, fork.Close()) })
+
+			expire := func(sessionID string) {
+				_, err := parent.db.ExecContext(ctx,
+					`UPDATE agent_sessions SET updated_at = datetime('now', '-2 hours') WHERE session_id = ?`,
+					NamespacedSessionKey(namespace, sessionID))
+				require.NoError(t, err)
+			}
+			policy := RetentionPolicy{MaxIdle: time.Hour}
+
+			// The idle parent is kept while its fork is used.
+			expire("parent")
+			deleted, err := parent.PurgeExpiredSessions(ctx, policy)
+			require.NoError(t, err)
+			assert.Zero(t, deleted)
+			items, err := fork.GetItems(ctx, 0)
+			require.NoError(t, err)
+			assert.Len(t, items, 1)
+
+			// Once the fork expires, it is purged, then its parent.
+			expire("fork")
+			keys, err := parent.PurgeExpiredSessionKeys(ctx, policy)
+			require.NoError(t, err)
+			assert.Equal(t, []string{NamespacedSessionKey(namespace, "fork")}, keys)
+			keys, err = parent.PurgeExpiredSessionKeys(ctx, policy)
+			require.NoError(t, err)
+			assert.Equal(t, []string{NamespacedSessionKey(namespace, "parent")}, keys)
+		})
+	}
+}
+
+type countingPurger struct {
+	calls atomic.Int32
+}
+
+func (p *countingPurger) PurgeExpiredSessions(context.Context, RetentionPolicy) (int64, error) {
+	p.calls.Add(1)
+	return 0, nil
+}
+
+func TestRunSessionGC(t *testing.T) {
+	purger := new(countingPurger)
+	ctx, cancel := context.WithCancel(t.Context())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunSessionGC(ctx, purger, RetentionPolicy{MaxAge: time.Hour}, time.Millisecond, nil)
+	}()
+
+	require.Eventually(t, func() bool { return purger.calls.Load() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
@@ -16,6 +16,7 @@ package memory
 
 import (
 	"context"
+	"strings"
 
 	"github.com/openai/openai-go/v3/responses"
 )
@@ -44,3 +45,31 @@ type Session interface {
 	// ClearSession clears all items for this session.
 	ClearSession(context.Context) error
 }
+
+// namespaceEscaper escapes the separator of namespaced session keys in
+// namespaces, so that distinct namespaces and session IDs never share a key.
+var namespaceEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// NamespacedSessionKey returns the key under which a session is persisted:
+// the namespace, with its "/" and "%" escaped as in URLs, and the session ID
+// separated by "/". Sessions without a namespace are stored under their plain
+// ID.
+func NamespacedSessionKey(namespace, sessionID string) string {
+	if namespace == "" {
+		return sessionID
+	}
+	return namespaceEscaper.Replace(namespace) + "/" + sessionID
+}
+
+var namespaceUnescaper = strings.NewReplacer("%2F", "/", "%25", "%")
+
+// SplitNamespacedSessionKey returns the namespace and the session ID of a key
+// returned by NamespacedSessionKey. It reports false for the keys of sessions
+// without a namespace.
+func SplitNamespacedSessionKey(key string) (namespace, sessionID string, ok bool) {
+	namespace, sessionID, ok = strings.Cut(key, "/")
+	if !ok {
+		return "", key, false
+	}
+	return namespaceUnescaper.Replace(namespace), sessionID, true
+}
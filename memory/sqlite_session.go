@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/openai/openai-go/v3/shared/constant"
@@ -38,6 +39,8 @@ import (
 // For persistent storage, provide a file path.
 type SQLiteSession struct {
 	sessionID     string
+	namespace     string
+	storageKey    string
 	dbDSN         string
 	sessionTable  string
 	messagesTable string
@@ -49,6 +52,12 @@ type SQLiteSessionParams struct {
 	// Unique identifier for the conversation session
 	SessionID string
 
+	// Optional namespace (for example a tenant or account ID) scoping the
+	// session. Sessions with the same ID in different namespaces are stored
+	// separately. A session stored before having a namespace, under its plain
+	// ID, is not used unless moved by MigrateLegacySession.
+	Namespace string
+
 	// Optional database data source name.
 	// Defaults to ':memory:' (in-memory database).
 	DBDataSourceName string
@@ -66,6 +75,8 @@ type SQLiteSessionParams struct {
 func NewSQLiteSession(ctx context.Context, params SQLiteSessionParams) (_ *SQLiteSession, err error) {
 	s := &SQLiteSession{
 		sessionID:     params.SessionID,
+		namespace:     params.Namespace,
+		storageKey:    NamespacedSessionKey(params.Namespace, params.SessionID),
 		dbDSN:         cmp.Or(params.DBDataSourceName, "file::memory:?cache=shared"),
 		sessionTable:  cmp.Or(params.SessionTable, "agent_sessions"),
 		messagesTable: cmp.Or(params.MessagesTable, "agent_messages"),
@@ -111,7 +122,7 @@ func (s *SQLiteSession) GetItems(ctx context.Context, limit int) (_ []TResponseI
 			SELECT message_data FROM "%s"
 			WHERE session_id = ?
 			ORDER BY created_at ASC
-		`, s.messagesTable), s.sessionID)
+		`, s.messagesTable), s.storageKey)
 	} else {
 		// Fetch the latest N items in chronological order
 		rows, err = s.db.QueryContext(ctx, fmt.Sprintf(`
@@ -119,7 +130,7 @@ func (s *SQLiteSession) GetItems(ctx context.Context, limit int) (_ []TResponseI
 			WHERE session_id = ?
 			ORDER BY created_at DESC
 			LIMIT ?
-		`, s.messagesTable), s.sessionID, limit)
+		`, s.messagesTable), s.storageKey, limit)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("error querying session items: %w", err)
@@ -182,7 +193,7 @@ func (s *SQLiteSession) AddItems(ctx context.Context, items []TResponseInputItem
 	_, err := s.db.ExecContext(
 		ctx,
 		fmt.Sprintf(`INSERT OR IGNORE INTO "%s" (session_id) VALUES (?)`, s.sessionTable),
-		s.sessionID,
+		s.storageKey,
 	)
 	if err != nil {
 		return err
@@ -197,7 +208,7 @@ func (s *SQLiteSession) AddItems(ctx context.Context, items []TResponseInputItem
 		_, err = s.db.ExecContext(
 			ctx,
 			fmt.Sprintf(`INSERT INTO "%s" (session_id, message_data) VALUES (?, ?)`, s.messagesTable),
-			s.sessionID, string(jsonItem),
+			s.storageKey, string(jsonItem),
 		)
 		if err != nil {
 			return fmt.Errorf("error inserting item in messages table: %w", err)
@@ -208,7 +219,7 @@ func (s *SQLiteSession) AddItems(ctx context.Context, items []TResponseInputItem
 	_, err = s.db.ExecContext(
 		ctx,
 		fmt.Sprintf(`UPDATE "%s" SET updated_at = CURRENT_TIMESTAMP WHERE session_id = ?`, s.sessionTable),
-		s.sessionID,
+		s.storageKey,
 	)
 	if err != nil {
 		return fmt.Errorf("error updating session timestamp: %w", err)
@@ -232,7 +243,7 @@ func (s *SQLiteSession) PopItem(ctx context.Context) (*TResponseInputItem, error
 			)
 			RETURNING message_data
 		`, s.messagesTable, s.messagesTable),
-		s.sessionID,
+		s.storageKey,
 	).Scan(&messageData)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -256,7 +267,7 @@ func (s *SQLiteSession) ClearSession(ctx context.Context) error {
 	_, err := s.db.ExecContext(
 		ctx,
 		fmt.Sprintf(`DELETE FROM "%s" WHERE session_id = ?`, s.messagesTable),
-		s.sessionID,
+		s.storageKey,
 	)
 	if err != nil {
 		return err
@@ -265,7 +276,7 @@ func (s *SQLiteSession) ClearSession(ctx context.Context) error {
 	_, err = s.db.ExecContext(
 		ctx,
 		fmt.Sprintf(`DELETE FROM "%s" WHERE session_id = ?`, s.sessionTable),
-		s.sessionID,
+		s.storageKey,
 	)
 	if err != nil {
 		return err
@@ -274,19 +285,225 @@ func (s *SQLiteSession) ClearSession(ctx context.Context) error {
 	return nil
 }
 
+// LoadForkLink returns the link to the session this session was forked from.
+func (s *SQLiteSession) LoadForkLink(ctx context.Context) (ForkLink, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		parentID    sql.NullString
+		sharedItems sql.NullInt64
+	)
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT fork_parent_id, fork_shared_items FROM "%s" WHERE session_id = ?`, s.sessionTable,
+	), s.storageKey).Scan(&parentID, &sharedItems)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && !parentID.Valid {
+		return ForkLink{}, false, nil
+	}
+	if err != nil {
+		return ForkLink{}, false, fmt.Errorf("error querying fork link: %w", err)
+	}
+	return ForkLink{ParentSessionID: parentID.String, SharedItems: int(sharedItems.Int64)}, true, nil
+}
+
+// StoreForkLink stores the link to the session this session was forked from.
+func (s *SQLiteSession) StoreForkLink(ctx context.Context, link ForkLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO "%s" (session_id, fork_parent_id, fork_shared_items) VALUES (?, ?, ?)
+		ON CONFLICT (session_id) DO UPDATE SET
+			fork_parent_id = excluded.fork_parent_id,
+			fork_shared_items = excluded.fork_shared_items,
+			updated_at = CURRENT_TIMESTAMP
+	`, s.sessionTable), s.storageKey, link.ParentSessionID, link.SharedItems)
+	if err != nil {
+		return fmt.Errorf("error storing fork link: %w", err)
+	}
+	return nil
+}
+
+// MigrateLegacySession moves the session stored under the plain session ID,
+// before sessions were namespaced, to the namespaced key of this session. Its
+// items are kept before the ones already stored under the namespaced key, if
+// any. It reports whether a session was moved.
+//
+// Sessions stored under their plain ID are never used by namespaced sessions
+// otherwise: callers migrate them explicitly, once they know the namespace
+// owning them.
+func (s *SQLiteSession) MigrateLegacySession(ctx context.Context) (_ bool, err error) {
+	if s.namespace == "" {
+		return false, errors.New("session has no namespace")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO "%[1]s" (session_id, created_at, updated_at, fork_parent_id, fork_shared_items)
+		SELECT ?, created_at, updated_at, fork_parent_id, fork_shared_items FROM "%[1]s" WHERE session_id = ?
+		ON CONFLICT (session_id) DO NOTHING
+	`, s.sessionTable), s.storageKey, s.sessionID)
+	if err != nil {
+		return false, fmt.Errorf("error moving legacy session: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE "%s" SET session_id = ? WHERE session_id = ?`, s.messagesTable,
+	), s.storageKey, s.sessionID); err != nil {
+		return false, fmt.Errorf("error moving legacy session items: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM "%s" WHERE session_id = ?`, s.sessionTable), s.sessionID)
+	if err != nil {
+		return false, fmt.Errorf("error deleting legacy session: %w", err)
+	}
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return moved > 0, nil
+}
+
+// PurgeExpiredSessions deletes every session stored in the database that
+// falls outside the given retention policy, together with its items. The
+// sessions other sessions are forked from are kept until their forks are
+// deleted.
+func (s *SQLiteSession) PurgeExpiredSessions(ctx context.Context, policy RetentionPolicy) (int64, error) {
+	keys, err := s.PurgeExpiredSessionKeys(ctx, policy)
+	return int64(len(keys)), err
+}
+
+// PurgeExpiredSessionKeys is like PurgeExpiredSessions, returning the keys of
+// the deleted sessions.
+func (s *SQLiteSession) PurgeExpiredSessionKeys(ctx context.Context, policy RetentionPolicy) (_ []string, err error) {
+	if policy.isZero() {
+		return nil, nil
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+	if policy.MaxAge > 0 {
+		conditions = append(conditions, `created_at < datetime('now', ?)`)
+		args = append(args, fmt.Sprintf("-%d seconds", int64(policy.MaxAge.Seconds())))
+	}
+	if policy.MaxIdle > 0 {
+		conditions = append(conditions, `updated_at < datetime('now', ?)`)
+		args = append(args, fmt.Sprintf("-%d seconds", int64(policy.MaxIdle.Seconds())))
+	}
+	// The parents of forks are kept, as their items are shared. Forks store
+	// the ID of their parent, which has the namespace of the fork; plain IDs
+	// are kept too, in case the fork is not namespaced.
+	where := fmt.Sprintf(`(%[2]s) AND session_id NOT IN (
+		SELECT fork_parent_id FROM "%[1]s" WHERE fork_parent_id IS NOT NULL
+		UNION
+		SELECT substr(session_id, 1, instr(session_id, '/')) || fork_parent_id FROM "%[1]s"
+		WHERE fork_parent_id IS NOT NULL AND instr(session_id, '/') > 0
+	)`, s.sessionTable, strings.Join(conditions, " OR "))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE session_id IN (SELECT session_id FROM "%s" WHERE %s)`,
+		s.messagesTable, s.sessionTable, where,
+	), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting expired session items: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		`DELETE FROM "%s" WHERE %s RETURNING session_id`, s.sessionTable, where,
+	), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting expired sessions: %w", err)
+	}
+	keys, err := scanSessionKeys(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting expired sessions: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return keys, nil
+}
+
+// scanSessionKeys returns the session keys of rows, closing them.
+func scanSessionKeys(rows *sql.Rows) (_ []string, err error) {
+	defer func() {
+		if e := rows.Close(); e != nil {
+			err = errors.Join(err, fmt.Errorf("error closing sql.Rows: %w", e))
+		}
+	}()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
 // Initialize the database schema.
 func (s *SQLiteSession) initDB(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS "%s" (
 			session_id TEXT PRIMARY KEY,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			fork_parent_id TEXT,
+			fork_shared_items INTEGER
 		)
 	`, s.sessionTable))
 	if err != nil {
 		return fmt.Errorf("error creating session table: %w", err)
 	}
 
+	// Session tables created before forks were supported lack the fork link.
+	var hasForkLink bool
+	err = s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = 'fork_parent_id')`,
+		s.sessionTable).Scan(&hasForkLink)
+	if err != nil {
+		return fmt.Errorf("error inspecting session table: %w", err)
+	}
+	if !hasForkLink {
+		for _, column := range []string{"fork_parent_id TEXT", "fork_shared_items INTEGER"} {
+			_, err = s.db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN %s`, s.sessionTable, column))
+			if err != nil {
+				return fmt.Errorf("error adding fork link to session table: %w", err)
+			}
+		}
+	}
+
 	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS "%s" (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -315,6 +532,12 @@ func (s *SQLiteSession) Close() error {
 	return s.db.Close()
 }
 
+// UnmarshalItem decodes an item encoded with json.Marshal, as the sessions
+// do when loading their items.
+func UnmarshalItem(data []byte) (TResponseInputItem, error) {
+	return unmarshalMessageData(string(data))
+}
+
 func unmarshalMessageData(messageData string) (TResponseInputItem, error) {
 	var item TResponseInputItem
 	err := json.Unmarshal([]byte(messageData), &item)
@@ -15,6 +15,7 @@
 package memory
 
 import (
+	"database/sql"
 	"encoding/json"
 	"path/filepath"
 	"testing"
@@ -281,6 +282,130 @@ func TestSQLiteSession_PopItem(t *testing.T) {
 	})
 }
 
+func TestSQLiteSession_Namespace(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	newSession := func(namespace string) *SQLiteSession {
+		session, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+			SessionID:        "shared",
+			Namespace:        namespace,
+			DBDataSourceName: dbPath,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, session.Close()) })
+		return session
+	}
+
+	tenantA := newSession("tenant_a")
+	tenantB := newSession("tenant_b")
+	assert.Equal(t, "shared", tenantA.SessionID(ctx))
+
+	require.NoError(t, tenantA.AddItems(ctx, []TResponseInputItem{{
+		OfMessage: &responses.EasyInputMessageParam{
+			Content: responses.EasyInputMessageContentUnionParam{OfString: param.NewOpt("Hello")},
+			Role:    responses.EasyInputMessageRoleUser,
+			Type:    responses.EasyInputMessageTypeMessage,
+		},
+	}}))
+
+	itemsA, err := tenantA.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, itemsA, 1)
+
+	itemsB, err := tenantB.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, itemsB)
+
+	require.NoError(t, tenantB.ClearSession(ctx))
+	itemsA, err = tenantA.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, itemsA, 1)
+}
+
+func TestNamespacedSessionKey(t *testing.T) {
+	assert.Equal(t, "s1", NamespacedSessionKey("", "s1"))
+	assert.Equal(t, "tenant_a/s1", NamespacedSessionKey("tenant_a", "s1"))
+	assert.NotEqual(t, NamespacedSessionKey("a/b", "c"), NamespacedSessionKey("a", "b/c"))
+	assert.NotEqual(t, NamespacedSessionKey("a%2Fb", "c"), NamespacedSessionKey("a/b", "c"))
+
+	for _, namespace := range []string{"tenant_a", "a/b", "a%2Fb", "%25/"} {
+		gotNamespace, gotID, ok := SplitNamespacedSessionKey(NamespacedSessionKey(namespace, "b/c"))
+		assert.True(t, ok)
+		assert.Equal(t, namespace, gotNamespace)
+		assert.Equal(t, "b/c", gotID)
+	}
+	_, _, ok := SplitNamespacedSessionKey("s1")
+	assert.False(t, ok)
+}
+
+func TestSQLiteSession_NamespaceLegacyKey(t *testing.T) {
+	ctx := t.Context()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	newSession := func(namespace string) *SQLiteSession {
+		session, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+			SessionID:        "legacy",
+			Namespace:        namespace,
+			DBDataSourceName: dbPath,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, session.Close()) })
+		return session
+	}
+	message := func(text string) TResponseInputItem {
+		return TResponseInputItem{OfMessage: &responses.EasyInputMessageParam{
+			Content: responses.EasyInputMessageContentUnionParam{OfString: param.NewOpt(text)},
+			Role:    responses.EasyInputMessageRoleUser,
+			Type:    responses.EasyInputMessageTypeMessage,
+		}}
+	}
+
+	// A session stored before namespacing is only used once migrated.
+	legacy := newSession("")
+	require.NoError(t, legacy.AddItems(ctx, []TResponseInputItem{message("before")}))
+	namespaced := newSession("tenant_a")
+	require.NoError(t, namespaced.AddItems(ctx, []TResponseInputItem{message("after")}))
+	items, err := newSession("tenant_b").GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	items, err = namespaced.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []TResponseInputItem{message("after")}, items)
+
+	moved, err := namespaced.MigrateLegacySession(ctx)
+	require.NoError(t, err)
+	assert.True(t, moved)
+	items, err = newSession("tenant_a").GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []TResponseInputItem{message("before"), message("after")}, items)
+	items, err = legacy.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	moved, err = newSession("tenant_b").MigrateLegacySession(ctx)
+	require.NoError(t, err)
+	assert.False(t, moved)
+	_, err = legacy.MigrateLegacySession(ctx)
+	assert.Error(t, err)
+
+	// New sessions use the namespaced key.
+	fresh, err := NewSQLiteSession(ctx, SQLiteSessionParams{
+		SessionID:        "fresh",
+		Namespace:        "tenant_a",
+		DBDataSourceName: dbPath,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, fresh.Close()) })
+	require.NoError(t, fresh.AddItems(ctx, []TResponseInputItem{message("hello")}))
+	plain, err := NewSQLiteSession(ctx, SQLiteSessionParams{SessionID: "fresh", DBDataSourceName: dbPath})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, plain.Close()) })
+	items, err = plain.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
 func Test_unmarshalMessageData(t *testing.T) {
 	t.Run("incorrect output messages unmarshaling fix", func(t *testing.T) {
 		toMarshal := responses.ResponseInputItemUnionParam{
@@ -323,3 +448,82 @@ func Test_unmarshalMessageData(t *testing.T) {
 		})
 	})
 }
+
+func TestSQLiteSession_UpgradesLegacySchema(t *testing.T) {
+	ctx := t.Context()
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	message := func(text string) TResponseInputItem {
+		return TResponseInputItem{OfMessage: &responses.EasyInputMessageParam{
+			Content: responses.EasyInputMessageContentUnionParam{OfString: param.NewOpt(text)},
+			Role:    responses.EasyInputMessageRoleUser,
+			Type:    responses.EasyInputMessageTypeMessage,
+		}}
+	}
+
+	// A database written before sessions had namespaces and fork links.
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE agent_sessions (
+			session_id TEXT PRIMARY KEY,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE agent_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			message_data TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (session_id) REFERENCES agent_sessions (session_id) ON DELETE CASCADE
+		);
+		INSERT INTO agent_sessions (session_id) VALUES ('s1');
+	`)
+	require.NoError(t, err)
+	for _, text := range []string{"first", "second"} {
+		data, err := json.Marshal(message(text))
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, `INSERT INTO agent_messages (session_id, message_data) VALUES ('s1', ?)`, string(data))
+		require.NoError(t, err)
+	}
+
+	// Opening sessions upgrades the schema once.
+	open := func() *SQLiteSession {
+		session, err := NewSQLiteSession(ctx, SQLiteSessionParams{SessionID: "s1", Namespace: "tenant_a", DBDataSourceName: dsn})
+		require.NoError(t, err)
+		t.Cleanup(func() { assert.NoError(t, session.Close()) })
+		return session
+	}
+	session := open()
+	open()
+	var columns []string
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info('agent_sessions')`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		columns = append(columns, name)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"session_id", "created_at", "updated_at", "fork_parent_id", "fork_shared_items"}, columns)
+
+	// Migrating the legacy session again is a no-op.
+	moved, err := session.MigrateLegacySession(ctx)
+	require.NoError(t, err)
+	assert.True(t, moved)
+	moved, err = session.MigrateLegacySession(ctx)
+	require.NoError(t, err)
+	assert.False(t, moved)
+	items, err := open().GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []TResponseInputItem{message("first"), message("second")}, items)
+
+	// The upgraded session can be forked.
+	fork, err := session.Fork(ctx, "s2", 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, fork.Close()) })
+	require.NoError(t, fork.AddItems(ctx, []TResponseInputItem{message("other")}))
+	items, err = fork.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []TResponseInputItem{message("first"), message("other")}, items)
+}
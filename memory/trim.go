@@ -0,0 +1,160 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"slices"
+)
+
+// A TrimPolicy reduces the conversation history retrieved from a Session
+// before it is sent to the model.
+type TrimPolicy interface {
+	// Trim returns the items to keep, in chronological order.
+	Trim(ctx context.Context, items []TResponseInputItem) ([]TResponseInputItem, error)
+}
+
+// TokenCounter estimates the number of tokens used by a single item.
+type TokenCounter func(TResponseInputItem) int
+
+// TokenBudgetTrimPolicy keeps the most recent items whose estimated token
+// count fits within MaxTokens.
+//
+// System and developer messages are always kept and are counted first against
+// the budget. Tool calls and their outputs are never split: an output whose
+// originating call has been trimmed away, or is missing from the history, is
+// dropped as well, and is not counted against the budget.
+type TokenBudgetTrimPolicy struct {
+	// Maximum number of tokens to keep. If <= 0, items are returned untouched.
+	MaxTokens int
+
+	// Optional function used to count the tokens of each item.
+	// Defaults to EstimateTokens.
+	TokenCounter TokenCounter
+}
+
+func (p TokenBudgetTrimPolicy) Trim(_ context.Context, items []TResponseInputItem) ([]TResponseInputItem, error) {
+	if p.MaxTokens <= 0 || len(items) == 0 {
+		return items, nil
+	}
+	countTokens := p.TokenCounter
+	if countTokens == nil {
+		countTokens = EstimateTokens
+	}
+
+	keep := make([]bool, len(items))
+	budget := p.MaxTokens
+
+	for i, item := range items {
+		if isSystemItem(item) {
+			keep[i] = true
+			budget -= countTokens(item)
+		}
+	}
+
+	// Tool call outputs are counted with their call, so that the outputs
+	// dropped below, being orphans, never take up the budget.
+	calls := make(map[string]struct{})
+	for _, item := range items {
+		if callID := itemCallID(item); callID != "" && !isToolCallOutput(item) {
+			calls[callID] = struct{}{}
+		}
+	}
+	pendingOutputs := make(map[string]int)
+	for i := len(items) - 1; i >= 0; i-- {
+		if keep[i] {
+			continue
+		}
+		item := items[i]
+		callID := itemCallID(item)
+		if callID != "" && isToolCallOutput(item) {
+			if _, ok := calls[callID]; ok {
+				keep[i] = true
+				pendingOutputs[callID] += countTokens(item)
+			}
+			continue
+		}
+		tokens := countTokens(item)
+		if callID != "" {
+			tokens += pendingOutputs[callID]
+		}
+		if tokens > budget {
+			break
+		}
+		keep[i] = true
+		budget -= tokens
+	}
+
+	// Drop tool call outputs whose call did not survive trimming.
+	keptCalls := make(map[string]struct{})
+	for i, item := range items {
+		if !keep[i] {
+			continue
+		}
+		callID := itemCallID(item)
+		if callID == "" {
+			continue
+		}
+		if isToolCallOutput(item) {
+			if _, ok := keptCalls[callID]; !ok {
+				keep[i] = false
+			}
+		} else {
+			keptCalls[callID] = struct{}{}
+		}
+	}
+
+	result := make([]TResponseInputItem, 0, len(items))
+	for i, item := range items {
+		if keep[i] {
+			result = append(result, item)
+		}
+	}
+	return slices.Clip(result), nil
+}
+
+// EstimateTokens returns a rough token estimate for an item, based on the
+// length of its JSON representation (about four characters per token).
+func EstimateTokens(item TResponseInputItem) int {
+	b, err := item.MarshalJSON()
+	if err != nil {
+		return 0
+	}
+	return (len(b) + 3) / 4
+}
+
+func isSystemItem(item TResponseInputItem) bool {
+	role := item.GetRole()
+	if role == nil {
+		return false
+	}
+	return *role == "system" || *role == "developer"
+}
+
+func isToolCallOutput(item TResponseInputItem) bool {
+	return item.OfFunctionCallOutput != nil ||
+		item.OfComputerCallOutput != nil ||
+		item.OfLocalShellCallOutput != nil ||
+		item.OfShellCallOutput != nil ||
+		item.OfApplyPatchCallOutput != nil ||
+		item.OfCustomToolCallOutput != nil
+}
+
+func itemCallID(item TResponseInputItem) string {
+	if id := item.GetCallID(); id != nil {
+		return *id
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trimTestMessage(role responses.EasyInputMessageRole, text string) TResponseInputItem {
+	return TResponseInputItem{OfMessage: &responses.EasyInputMessageParam{
+		Content: responses.EasyInputMessageContentUnionParam{OfString: param.NewOpt(text)},
+		Role:    role,
+		Type:    responses.EasyInputMessageTypeMessage,
+	}}
+}
+
+func TestTokenBudgetTrimPolicy(t *testing.T) {
+	// Every item costs one token, so MaxTokens is effectively an item count.
+	countOne := func(TResponseInputItem) int { return 1 }
+
+	system := trimTestMessage(responses.EasyInputMessageRoleSystem, "be nice")
+	user1 := trimTestMessage(responses.EasyInputMessageRoleUser, "first")
+	call := TResponseInputItem{OfFunctionCall: &responses.ResponseFunctionToolCallParam{
+		CallID:    "call_1",
+		Name:      "lookup",
+		Arguments: "{}",
+	}}
+	output := responses.ResponseInputItemParamOfFunctionCallOutput("call_1", "result")
+	user2 := trimTestMessage(responses.EasyInputMessageRoleUser, "second")
+
+	items := []TResponseInputItem{system, user1, call, output, user2}
+
+	t.Run("no budget", func(t *testing.T) {
+		got, err := TokenBudgetTrimPolicy{}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, items, got)
+	})
+
+	t.Run("keeps system and latest items", func(t *testing.T) {
+		got, err := TokenBudgetTrimPolicy{MaxTokens: 4, TokenCounter: countOne}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, call, output, user2}, got)
+	})
+
+	t.Run("never keeps orphan tool outputs", func(t *testing.T) {
+		got, err := TokenBudgetTrimPolicy{MaxTokens: 3, TokenCounter: countOne}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, user2}, got)
+	})
+
+	t.Run("orphan tool outputs take no budget", func(t *testing.T) {
+		orphan := responses.ResponseInputItemParamOfFunctionCallOutput("call_0", "stale")
+		items := []TResponseInputItem{system, user1, orphan, user2}
+		got, err := TokenBudgetTrimPolicy{MaxTokens: 3, TokenCounter: countOne}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, user1, user2}, got)
+
+		// Outputs are counted with their call.
+		items = []TResponseInputItem{system, user1, call, user2, output}
+		got, err = TokenBudgetTrimPolicy{MaxTokens: 3, TokenCounter: countOne}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, user2}, got)
+		got, err = TokenBudgetTrimPolicy{MaxTokens: 4, TokenCounter: countOne}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, call, user2, output}, got)
+	})
+
+	t.Run("default estimator", func(t *testing.T) {
+		budget := EstimateTokens(system) + EstimateTokens(user2)
+		got, err := TokenBudgetTrimPolicy{MaxTokens: budget}.Trim(t.Context(), items)
+		require.NoError(t, err)
+		assert.Equal(t, []TResponseInputItem{system, user2}, got)
+	})
+}
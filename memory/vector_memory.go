@@ -0,0 +1,268 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// LongTermMemory stores knowledge from past conversations and recalls the
+// parts relevant to the current input.
+type LongTermMemory interface {
+	// Remember stores the given conversation items.
+	Remember(ctx context.Context, items []TResponseInputItem) error
+
+	// Recall returns the items to inject into the model input, given the
+	// input of the current turn. It may return no items.
+	Recall(ctx context.Context, input []TResponseInputItem) ([]TResponseInputItem, error)
+}
+
+// An Embedder converts texts into embedding vectors.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VectorRecord is a piece of text stored in a VectorStore.
+type VectorRecord struct {
+	ID     string
+	Text   string
+	Vector []float32
+}
+
+// VectorMatch is a VectorRecord returned by a similarity search.
+type VectorMatch struct {
+	Record VectorRecord
+	// Cosine similarity between the query and the record vectors.
+	Score float64
+}
+
+// A VectorStore stores VectorRecords and retrieves the most similar ones.
+type VectorStore interface {
+	Add(ctx context.Context, records []VectorRecord) error
+	// Search returns up to k records, sorted by decreasing similarity.
+	Search(ctx context.Context, vector []float32, k int) ([]VectorMatch, error)
+}
+
+// InMemoryVectorStore is a VectorStore performing exact cosine-similarity
+// search over records kept in memory.
+type InMemoryVectorStore struct {
+	mu      sync.RWMutex
+	records []VectorRecord
+}
+
+// NewInMemoryVectorStore returns an empty InMemoryVectorStore.
+func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return &InMemoryVectorStore{}
+}
+
+func (s *InMemoryVectorStore) Add(_ context.Context, records []VectorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *InMemoryVectorStore) Search(_ context.Context, vector []float32, k int) ([]VectorMatch, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]VectorMatch, 0, len(s.records))
+	for _, record := range s.records {
+		matches = append(matches, VectorMatch{
+			Record: record,
+			Score:  CosineSimilarity(vector, record.Vector),
+		})
+	}
+	slices.SortStableFunc(matches, func(a, b VectorMatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors, or 0 if
+// their lengths differ or either of them is a zero vector.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// DefaultVectorMemoryTopK is the number of memories recalled by VectorMemory
+// when TopK is not set.
+const DefaultVectorMemoryTopK = 3
+
+// VectorMemory is a LongTermMemory that embeds the text of past messages
+// into a VectorStore and recalls the ones most similar to the latest user
+// message.
+type VectorMemory struct {
+	Embedder Embedder
+	Store    VectorStore
+
+	// Maximum number of memories to recall.
+	// Default (when left zero): DefaultVectorMemoryTopK.
+	TopK int
+
+	// Optional minimum similarity a memory must have to be recalled.
+	MinScore float64
+}
+
+func (m VectorMemory) Remember(ctx context.Context, items []TResponseInputItem) error {
+	if m.Embedder == nil || m.Store == nil {
+		return errors.New("VectorMemory requires both an Embedder and a Store")
+	}
+	var texts []string
+	for _, item := range items {
+		text := strings.TrimSpace(ItemText(item))
+		if text == "" {
+			continue
+		}
+		if role := item.GetRole(); role != nil {
+			text = *role + ": " + text
+		}
+		texts = append(texts, text)
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	vectors, err := m.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed memories: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+
+	records := make([]VectorRecord, len(texts))
+	for i, text := range texts {
+		records[i] = VectorRecord{
+			ID:     uuid.NewString(),
+			Text:   text,
+			Vector: vectors[i],
+		}
+	}
+	return m.Store.Add(ctx, records)
+}
+
+func (m VectorMemory) Recall(ctx context.Context, input []TResponseInputItem) ([]TResponseInputItem, error) {
+	if m.Embedder == nil || m.Store == nil {
+		return nil, errors.New("VectorMemory requires both an Embedder and a Store")
+	}
+	query := ""
+	for i := len(input) - 1; i >= 0 && query == ""; i-- {
+		if role := input[i].GetRole(); role != nil && *role == "user" {
+			query = strings.TrimSpace(ItemText(input[i]))
+		}
+	}
+	if query == "" {
+		return nil, nil
+	}
+
+	vectors, err := m.Embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 text", len(vectors))
+	}
+
+	matches, err := m.Store.Search(ctx, vectors[0], cmp.Or(m.TopK, DefaultVectorMemoryTopK))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memories: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, match := range matches {
+		if match.Score < m.MinScore {
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("Relevant memories from previous conversations:")
+		}
+		sb.WriteString("\n- ")
+		sb.WriteString(match.Record.Text)
+	}
+	if sb.Len() == 0 {
+		return nil, nil
+	}
+	return []TResponseInputItem{{
+		OfMessage: &responses.EasyInputMessageParam{
+			Content: responses.EasyInputMessageContentUnionParam{OfString: param.NewOpt(sb.String())},
+			Role:    responses.EasyInputMessageRoleDeveloper,
+			Type:    responses.EasyInputMessageTypeMessage,
+		},
+	}}, nil
+}
+
+// ItemText returns the concatenated text content of a message item, or an
+// empty string for any other kind of item.
+func ItemText(item TResponseInputItem) string {
+	switch {
+	case item.OfMessage != nil:
+		if item.OfMessage.Content.OfString.Valid() {
+			return item.OfMessage.Content.OfString.Value
+		}
+		return inputContentText(item.OfMessage.Content.OfInputItemContentList)
+	case item.OfInputMessage != nil:
+		return inputContentText(item.OfInputMessage.Content)
+	case item.OfOutputMessage != nil:
+		var parts []string
+		for _, c := range item.OfOutputMessage.Content {
+			if c.OfOutputText != nil {
+				parts = append(parts, c.OfOutputText.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+func inputContentText(content responses.ResponseInputMessageContentListParam) string {
+	var parts []string
+	for _, c := range content {
+		if c.OfInputText != nil {
+			parts = append(parts, c.OfInputText.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
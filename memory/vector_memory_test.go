@@ -0,0 +1,106 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keywordEmbedder embeds a text as the presence of each of its keywords.
+type keywordEmbedder []string
+
+func (e keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(e))
+		for j, keyword := range e {
+			if strings.Contains(strings.ToLower(text), keyword) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0, CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.Zero(t, CosineSimilarity([]float32{1}, []float32{1, 2}))
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 2}))
+}
+
+func TestInMemoryVectorStore(t *testing.T) {
+	ctx := t.Context()
+	store := NewInMemoryVectorStore()
+	require.NoError(t, store.Add(ctx, []VectorRecord{
+		{ID: "a", Vector: []float32{1, 0}},
+		{ID: "b", Vector: []float32{1, 1}},
+		{ID: "c", Vector: []float32{0, 1}},
+	}))
+
+	matches, err := store.Search(ctx, []float32{0, 1}, 2)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "c", matches[0].Record.ID)
+	assert.Equal(t, "b", matches[1].Record.ID)
+}
+
+func TestVectorMemory(t *testing.T) {
+	ctx := t.Context()
+	m := VectorMemory{
+		Embedder: keywordEmbedder{"cat", "dog", "fish"},
+		Store:    NewInMemoryVectorStore(),
+		MinScore: 0.5,
+	}
+
+	require.NoError(t, m.Remember(ctx, []TResponseInputItem{
+		trimTestMessage(responses.EasyInputMessageRoleUser, "My cat is called Tom"),
+		trimTestMessage(responses.EasyInputMessageRoleUser, "My dog is called Rex"),
+		trimTestMessage(responses.EasyInputMessageRoleUser, ""),
+	}))
+
+	t.Run("relevant memory", func(t *testing.T) {
+		recalled, err := m.Recall(ctx, []TResponseInputItem{
+			trimTestMessage(responses.EasyInputMessageRoleUser, "What is my cat's name?"),
+		})
+		require.NoError(t, err)
+		require.Len(t, recalled, 1)
+		text := ItemText(recalled[0])
+		assert.Contains(t, text, "user: My cat is called Tom")
+		assert.NotContains(t, text, "Rex")
+	})
+
+	t.Run("no relevant memory", func(t *testing.T) {
+		recalled, err := m.Recall(ctx, []TResponseInputItem{
+			trimTestMessage(responses.EasyInputMessageRoleUser, "Do I have a fish?"),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, recalled)
+	})
+
+	t.Run("no user message", func(t *testing.T) {
+		recalled, err := m.Recall(ctx, []TResponseInputItem{
+			trimTestMessage(responses.EasyInputMessageRoleSystem, "You know about cats"),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, recalled)
+	})
+}
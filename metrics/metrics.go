@@ -0,0 +1,111 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics defines the measurements reported by agent runs.
+//
+// The agents package reports to the global Recorder, which does nothing by
+// default. See the prommetrics package for a Prometheus implementation.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Outcome labels.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// Rejection reasons, see Recorder.RunRejected.
+const (
+	// RejectionInvalid is used for requests which could not be built, for
+	// example because of an invalid workflow.
+	RejectionInvalid      = "invalid"
+	RejectionQueueFull    = "queue_full"
+	RejectionQueueTimeout = "queue_timeout"
+	// RejectionQuota is used for requests exceeding the quotas of their
+	// tenant.
+	RejectionQuota = "quota"
+	// RejectionCanceled is used for runs canceled while queued.
+	RejectionCanceled = "canceled"
+)
+
+// Guardrail kinds.
+const (
+	GuardrailKindInput  = "input"
+	GuardrailKindOutput = "output"
+)
+
+// Recorder receives measurements about agent runs.
+// Implementations must be safe for concurrent use.
+type Recorder interface {
+	// RunStarted is called when a run starts.
+	RunStarted(workflow string)
+	// RunFinished is called when a run ends with the given outcome, after
+	// the given number of turns.
+	RunFinished(workflow, outcome string, turns uint64, duration time.Duration)
+	// RunRejected is called for runs refused before starting, which are
+	// neither started nor finished.
+	RunRejected(workflow, reason string)
+	// ModelCall is called after each model request.
+	ModelCall(model, outcome string, duration time.Duration)
+	// TokensUsed is called with the token usage of each model response.
+	TokensUsed(model string, inputTokens, outputTokens uint64)
+	// ToolCall is called after each function tool invocation.
+	ToolCall(tool, outcome string, duration time.Duration)
+	// GuardrailTripped is called when a guardrail tripwire is triggered.
+	GuardrailTripped(guardrail, kind string)
+}
+
+// NoOpRecorder is a Recorder which discards all measurements.
+type NoOpRecorder struct{}
+
+func (NoOpRecorder) RunStarted(string)                                 {}
+func (NoOpRecorder) RunFinished(string, string, uint64, time.Duration) {}
+func (NoOpRecorder) RunRejected(string, string)                        {}
+func (NoOpRecorder) ModelCall(string, string, time.Duration)           {}
+func (NoOpRecorder) TokensUsed(string, uint64, uint64)                 {}
+func (NoOpRecorder) ToolCall(string, string, time.Duration)            {}
+func (NoOpRecorder) GuardrailTripped(string, string)                   {}
+
+var globalRecorder atomic.Pointer[Recorder]
+
+// SetRecorder sets the global Recorder.
+// A nil value restores the NoOpRecorder.
+func SetRecorder(recorder Recorder) {
+	if recorder == nil {
+		globalRecorder.Store(nil)
+		return
+	}
+	globalRecorder.Store(&recorder)
+}
+
+// GetRecorder returns the global Recorder.
+func GetRecorder() Recorder {
+	if v := globalRecorder.Load(); v != nil {
+		return *v
+	}
+	return NoOpRecorder{}
+}
+
+// OutcomeOf returns OutcomeFailed if err is not nil, or OutcomeSucceeded
+// otherwise.
+func OutcomeOf(err error) string {
+	if err != nil {
+		return OutcomeFailed
+	}
+	return OutcomeSucceeded
+}
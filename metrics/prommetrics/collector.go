@@ -0,0 +1,169 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prommetrics exposes agent run metrics to Prometheus.
+package prommetrics
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace is the metric namespace used when none is provided.
+const DefaultNamespace = "openai_agents"
+
+// Collector is both a metrics.Recorder and a prometheus.Collector.
+type Collector struct {
+	runsStarted       *prometheus.CounterVec
+	runsFinished      *prometheus.CounterVec
+	runsRejected      *prometheus.CounterVec
+	runDuration       *prometheus.HistogramVec
+	runTurns          *prometheus.HistogramVec
+	modelDuration     *prometheus.HistogramVec
+	tokens            *prometheus.CounterVec
+	toolDuration      *prometheus.HistogramVec
+	guardrailTripwire *prometheus.CounterVec
+}
+
+// NewCollector returns a new Collector whose metric names are prefixed by
+// namespace (DefaultNamespace, if empty).
+func NewCollector(namespace string) *Collector {
+	namespace = cmp.Or(namespace, DefaultNamespace)
+	return &Collector{
+		runsStarted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_started_total",
+			Help:      "Number of agent runs started.",
+		}, []string{"workflow"}),
+		runsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_finished_total",
+			Help:      "Number of agent runs finished, by outcome.",
+		}, []string{"workflow", "outcome"}),
+		runsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_rejected_total",
+			Help:      "Number of agent runs refused before starting, by reason.",
+		}, []string{"workflow", "reason"}),
+		runDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "run_duration_seconds",
+			Help:      "Duration of agent runs.",
+			Buckets:   []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		}, []string{"workflow", "outcome"}),
+		runTurns: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "run_turns",
+			Help:      "Number of turns taken by agent runs.",
+			Buckets:   []float64{1, 2, 3, 5, 8, 13, 21},
+		}, []string{"workflow"}),
+		modelDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "model_request_duration_seconds",
+			Help:      "Latency of model requests.",
+			Buckets:   prometheus.ExponentialBuckets(.1, 2, 10),
+		}, []string{"model", "outcome"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tokens_total",
+			Help:      "Number of tokens used, by model and type (input or output).",
+		}, []string{"model", "type"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Latency of function tool calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool", "outcome"}),
+		guardrailTripwire: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "guardrail_tripwires_total",
+			Help:      "Number of triggered guardrail tripwires.",
+		}, []string{"guardrail", "kind"}),
+	}
+}
+
+// Register creates a new Collector, registers it with reg (the default
+// Prometheus registerer, if nil) and installs it as the global
+// metrics.Recorder.
+func Register(reg prometheus.Registerer, namespace string) (*Collector, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	c := NewCollector(namespace)
+	if err := reg.Register(c); err != nil {
+		return nil, err
+	}
+	metrics.SetRecorder(c)
+	return c, nil
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.runsStarted,
+		c.runsFinished,
+		c.runsRejected,
+		c.runDuration,
+		c.runTurns,
+		c.modelDuration,
+		c.tokens,
+		c.toolDuration,
+		c.guardrailTripwire,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, col := range c.collectors() {
+		col.Describe(ch)
+	}
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, col := range c.collectors() {
+		col.Collect(ch)
+	}
+}
+
+func (c *Collector) RunStarted(workflow string) {
+	c.runsStarted.WithLabelValues(workflow).Inc()
+}
+
+func (c *Collector) RunFinished(workflow, outcome string, turns uint64, duration time.Duration) {
+	c.runsFinished.WithLabelValues(workflow, outcome).Inc()
+	c.runDuration.WithLabelValues(workflow, outcome).Observe(duration.Seconds())
+	c.runTurns.WithLabelValues(workflow).Observe(float64(turns))
+}
+
+func (c *Collector) RunRejected(workflow, reason string) {
+	c.runsRejected.WithLabelValues(workflow, reason).Inc()
+}
+
+func (c *Collector) ModelCall(model, outcome string, duration time.Duration) {
+	c.modelDuration.WithLabelValues(model, outcome).Observe(duration.Seconds())
+}
+
+func (c *Collector) TokensUsed(model string, inputTokens, outputTokens uint64) {
+	c.tokens.WithLabelValues(model, "input").Add(float64(inputTokens))
+	c.tokens.WithLabelValues(model, "output").Add(float64(outputTokens))
+}
+
+func (c *Collector) ToolCall(tool, outcome string, duration time.Duration) {
+	c.toolDuration.WithLabelValues(tool, outcome).Observe(duration.Seconds())
+}
+
+func (c *Collector) GuardrailTripped(guardrail, kind string) {
+	c.guardrailTripwire.WithLabelValues(guardrail, kind).Inc()
+}
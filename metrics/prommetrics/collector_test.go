@@ -0,0 +1,71 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prommetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c, err := Register(reg, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { metrics.SetRecorder(nil) })
+	assert.Same(t, c, metrics.GetRecorder())
+
+	_, err = Register(reg, "")
+	assert.Error(t, err, "registering the same metrics twice must fail")
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+	c.RunStarted("wf")
+	c.RunFinished("wf", metrics.OutcomeSucceeded, 2, time.Second)
+	c.RunRejected("wf", metrics.RejectionQueueFull)
+	c.ModelCall("gpt-4o", metrics.OutcomeSucceeded, 100*time.Millisecond)
+	c.TokensUsed("gpt-4o", 10, 5)
+	c.TokensUsed("gpt-4o", 1, 2)
+	c.ToolCall("search", metrics.OutcomeFailed, time.Millisecond)
+	c.GuardrailTripped("no_pii", metrics.GuardrailKindInput)
+
+	expected := `
+# HELP test_runs_finished_total Number of agent runs finished, by outcome.
+# TYPE test_runs_finished_total counter
+test_runs_finished_total{outcome="succeeded",workflow="wf"} 1
+# HELP test_runs_rejected_total Number of agent runs refused before starting, by reason.
+# TYPE test_runs_rejected_total counter
+test_runs_rejected_total{reason="queue_full",workflow="wf"} 1
+# HELP test_tokens_total Number of tokens used, by model and type (input or output).
+# TYPE test_tokens_total counter
+test_tokens_total{model="gpt-4o",type="input"} 11
+test_tokens_total{model="gpt-4o",type="output"} 7
+# HELP test_guardrail_tripwires_total Number of triggered guardrail tripwires.
+# TYPE test_guardrail_tripwires_total counter
+test_guardrail_tripwires_total{guardrail="no_pii",kind="input"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"test_runs_finished_total", "test_runs_rejected_total", "test_tokens_total", "test_guardrail_tripwires_total"))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(c, "test_run_turns"))
+	assert.Equal(t, 1, testutil.CollectAndCount(c, "test_tool_call_duration_seconds"))
+}
@@ -62,8 +62,15 @@ type ModelSettings struct {
 	Truncation param.Opt[Truncation] `json:"truncation"`
 
 	// The maximum number of output tokens to generate.
+	// Chat Completions requests of reasoning models (o-series and GPT-5),
+	// which reject max_tokens, send it as max_completion_tokens instead.
 	MaxTokens param.Opt[int64] `json:"max_tokens"`
 
+	// The maximum number of tokens to generate, including reasoning tokens.
+	// It is always sent as max_completion_tokens by Chat Completions
+	// requests, and takes precedence over MaxTokens.
+	MaxCompletionTokens param.Opt[int64] `json:"max_completion_tokens"`
+
 	// Optional configuration options for reasoning models
 	// (see https://platform.openai.com/docs/guides/reasoning).
 	Reasoning openai.ReasoningParam `json:"reasoning"`
@@ -71,6 +78,11 @@ type ModelSettings struct {
 	// Constrains the verbosity of the model's response.
 	Verbosity param.Opt[Verbosity] `json:"verbosity"`
 
+	// The processing tier of the requests, e.g. ServiceTierPriority for
+	// latency-sensitive workloads, or ServiceTierFlex for cheaper and slower
+	// processing. See https://platform.openai.com/docs/api-reference/responses/create#responses_create-service_tier
+	ServiceTier param.Opt[ServiceTier] `json:"service_tier"`
+
 	// Optional metadata to include with the model response call.
 	Metadata map[string]string `json:"metadata"`
 
@@ -91,12 +103,23 @@ type ModelSettings struct {
 	// Setting this will automatically include ``"message.output_text.logprobs"`` in the response.
 	TopLogprobs param.Opt[int64] `json:"top_logprobs"`
 
+	// Whether to return the log probabilities of the output tokens.
+	// Logprobs are exposed on the output_text content of message output items.
+	// Setting TopLogprobs implies this.
+	Logprobs param.Opt[bool] `json:"logprobs"`
+
 	// Optional additional query fields to provide with the request.
 	ExtraQuery map[string]string `json:"extra_query"`
 
 	// Optional additional headers to provide with the request.
 	ExtraHeaders map[string]string `json:"extra_headers"`
 
+	// Optional additional fields merged into the JSON body of the request,
+	// for the vendor-specific parameters of OpenAI compatible providers
+	// (e.g. OpenRouter "provider" preferences or Mistral "safe_prompt").
+	// They take precedence over the fields set from the other settings.
+	ExtraBody map[string]any `json:"extra_body"`
+
 	// Optional function which allows you to fully customize parameters and options
 	// for a call to the responses API. Pre-built parameters and options are given.
 	// You should return the final parameters and options that will be passed
@@ -120,6 +143,16 @@ const (
 	VerbosityHigh   Verbosity = "high"
 )
 
+type ServiceTier string
+
+const (
+	ServiceTierAuto     ServiceTier = "auto"
+	ServiceTierDefault  ServiceTier = "default"
+	ServiceTierFlex     ServiceTier = "flex"
+	ServiceTierScale    ServiceTier = "scale"
+	ServiceTierPriority ServiceTier = "priority"
+)
+
 type ToolChoice interface {
 	isToolChoice()
 }
@@ -161,15 +194,19 @@ func (ms ModelSettings) Resolve(override ModelSettings) ModelSettings {
 	resolveOpt(&newSettings.ParallelToolCalls, override.ParallelToolCalls)
 	resolveOpt(&newSettings.Truncation, override.Truncation)
 	resolveOpt(&newSettings.MaxTokens, override.MaxTokens)
+	resolveOpt(&newSettings.MaxCompletionTokens, override.MaxCompletionTokens)
 	resolveAny(&newSettings.Reasoning, override.Reasoning)
 	resolveOpt(&newSettings.Verbosity, override.Verbosity)
+	resolveOpt(&newSettings.ServiceTier, override.ServiceTier)
 	resolveMap(&newSettings.Metadata, override.Metadata)
 	resolveOpt(&newSettings.Store, override.Store)
 	resolveOpt(&newSettings.IncludeUsage, override.IncludeUsage)
 	resolveAny(&newSettings.ResponseInclude, override.ResponseInclude)
 	resolveOpt(&newSettings.TopLogprobs, override.TopLogprobs)
+	resolveOpt(&newSettings.Logprobs, override.Logprobs)
 	resolveMap(&newSettings.ExtraQuery, override.ExtraQuery)
 	resolveMap(&newSettings.ExtraHeaders, override.ExtraHeaders)
+	resolveMap(&newSettings.ExtraBody, override.ExtraBody)
 	resolveAny(&newSettings.CustomizeResponsesRequest, override.CustomizeResponsesRequest)
 	resolveAny(&newSettings.CustomizeChatCompletionsRequest, override.CustomizeChatCompletionsRequest)
 	return newSettings
@@ -43,23 +43,27 @@ func TestModelSettings_BasicSerialization(t *testing.T) {
 	require.NoError(t, err)
 
 	var want any = map[string]any{
-		"temperature":         json.Number("0.5"),
-		"top_p":               json.Number("0.9"),
-		"frequency_penalty":   nil,
-		"presence_penalty":    nil,
-		"tool_choice":         nil,
-		"parallel_tool_calls": nil,
-		"truncation":          nil,
-		"max_tokens":          json.Number("100"),
-		"reasoning":           map[string]any{},
-		"verbosity":           nil,
-		"metadata":            nil,
-		"store":               nil,
-		"include_usage":       nil,
-		"response_include":    nil,
-		"top_logprobs":        nil,
-		"extra_query":         nil,
-		"extra_headers":       nil,
+		"temperature":           json.Number("0.5"),
+		"top_p":                 json.Number("0.9"),
+		"frequency_penalty":     nil,
+		"presence_penalty":      nil,
+		"tool_choice":           nil,
+		"parallel_tool_calls":   nil,
+		"truncation":            nil,
+		"max_tokens":            json.Number("100"),
+		"max_completion_tokens": nil,
+		"reasoning":             map[string]any{},
+		"verbosity":             nil,
+		"service_tier":          nil,
+		"metadata":              nil,
+		"store":                 nil,
+		"include_usage":         nil,
+		"response_include":      nil,
+		"top_logprobs":          nil,
+		"logprobs":              nil,
+		"extra_query":           nil,
+		"extra_headers":         nil,
+		"extra_body":            nil,
 	}
 	assert.Equal(t, want, got)
 }
@@ -67,23 +71,27 @@ func TestModelSettings_BasicSerialization(t *testing.T) {
 // Tests whether ModelSettings can be serialized to a JSON string.
 func TestModelSettings_AllFieldsSerialization(t *testing.T) {
 	modelSettings := ModelSettings{
-		Temperature:       param.NewOpt(0.5),
-		TopP:              param.NewOpt(0.9),
-		FrequencyPenalty:  param.NewOpt(0.0),
-		PresencePenalty:   param.NewOpt(0.0),
-		ToolChoice:        ToolChoiceAuto,
-		ParallelToolCalls: param.NewOpt(true),
-		Truncation:        param.NewOpt(TruncationAuto),
-		MaxTokens:         param.NewOpt[int64](100),
-		Reasoning:         openai.ReasoningParam{},
-		Verbosity:         param.NewOpt(VerbosityMedium),
-		Metadata:          map[string]string{"foo": "bar"},
-		Store:             param.NewOpt(false),
-		IncludeUsage:      param.NewOpt(false),
-		ResponseInclude:   []responses.ResponseIncludable{responses.ResponseIncludableFileSearchCallResults},
-		TopLogprobs:       param.NewOpt(int64(1)),
-		ExtraQuery:        map[string]string{"foo": "bar"},
-		ExtraHeaders:      map[string]string{"foo": "bar"},
+		Temperature:         param.NewOpt(0.5),
+		TopP:                param.NewOpt(0.9),
+		FrequencyPenalty:    param.NewOpt(0.0),
+		PresencePenalty:     param.NewOpt(0.0),
+		ToolChoice:          ToolChoiceAuto,
+		ParallelToolCalls:   param.NewOpt(true),
+		Truncation:          param.NewOpt(TruncationAuto),
+		MaxTokens:           param.NewOpt[int64](100),
+		MaxCompletionTokens: param.NewOpt[int64](200),
+		Reasoning:           openai.ReasoningParam{},
+		Verbosity:           param.NewOpt(VerbosityMedium),
+		ServiceTier:         param.NewOpt(ServiceTierFlex),
+		Metadata:            map[string]string{"foo": "bar"},
+		Store:               param.NewOpt(false),
+		IncludeUsage:        param.NewOpt(false),
+		ResponseInclude:     []responses.ResponseIncludable{responses.ResponseIncludableFileSearchCallResults},
+		TopLogprobs:         param.NewOpt(int64(1)),
+		Logprobs:            param.NewOpt(true),
+		ExtraQuery:          map[string]string{"foo": "bar"},
+		ExtraHeaders:        map[string]string{"foo": "bar"},
+		ExtraBody:           map[string]any{"foo": "bar"},
 	}
 	res, err := json.Marshal(modelSettings)
 	require.NoError(t, err)
@@ -93,23 +101,27 @@ func TestModelSettings_AllFieldsSerialization(t *testing.T) {
 	require.NoError(t, err)
 
 	var want any = map[string]any{
-		"temperature":         json.Number("0.5"),
-		"top_p":               json.Number("0.9"),
-		"frequency_penalty":   json.Number("0"),
-		"presence_penalty":    json.Number("0"),
-		"tool_choice":         "auto",
-		"parallel_tool_calls": true,
-		"truncation":          "auto",
-		"max_tokens":          json.Number("100"),
-		"reasoning":           map[string]any{},
-		"verbosity":           "medium",
-		"metadata":            map[string]any{"foo": "bar"},
-		"store":               false,
-		"include_usage":       false,
-		"response_include":    []any{"file_search_call.results"},
-		"top_logprobs":        json.Number("1"),
-		"extra_query":         map[string]any{"foo": "bar"},
-		"extra_headers":       map[string]any{"foo": "bar"},
+		"temperature":           json.Number("0.5"),
+		"top_p":                 json.Number("0.9"),
+		"frequency_penalty":     json.Number("0"),
+		"presence_penalty":      json.Number("0"),
+		"tool_choice":           "auto",
+		"parallel_tool_calls":   true,
+		"truncation":            "auto",
+		"max_tokens":            json.Number("100"),
+		"max_completion_tokens": json.Number("200"),
+		"reasoning":             map[string]any{},
+		"verbosity":             "medium",
+		"service_tier":          "flex",
+		"metadata":              map[string]any{"foo": "bar"},
+		"store":                 false,
+		"include_usage":         false,
+		"response_include":      []any{"file_search_call.results"},
+		"top_logprobs":          json.Number("1"),
+		"logprobs":              true,
+		"extra_query":           map[string]any{"foo": "bar"},
+		"extra_headers":         map[string]any{"foo": "bar"},
+		"extra_body":            map[string]any{"foo": "bar"},
 	}
 	assert.Equal(t, want, got)
 }
@@ -138,18 +150,22 @@ func TestModelSettings_ToolChoiceMCPSerialization(t *testing.T) {
 			"server_label": "mcp",
 			"name":         "mcp_tool",
 		},
-		"parallel_tool_calls": nil,
-		"truncation":          nil,
-		"max_tokens":          nil,
-		"reasoning":           map[string]any{},
-		"verbosity":           nil,
-		"metadata":            nil,
-		"store":               nil,
-		"include_usage":       nil,
-		"response_include":    nil,
-		"top_logprobs":        nil,
-		"extra_query":         nil,
-		"extra_headers":       nil,
+		"parallel_tool_calls":   nil,
+		"truncation":            nil,
+		"max_tokens":            nil,
+		"max_completion_tokens": nil,
+		"reasoning":             map[string]any{},
+		"verbosity":             nil,
+		"service_tier":          nil,
+		"metadata":              nil,
+		"store":                 nil,
+		"include_usage":         nil,
+		"response_include":      nil,
+		"top_logprobs":          nil,
+		"logprobs":              nil,
+		"extra_query":           nil,
+		"extra_headers":         nil,
+		"extra_body":            nil,
 	}
 	assert.Equal(t, want, got)
 }
@@ -163,26 +179,30 @@ func unmarshal(data []byte, v any) error {
 
 func TestModelSettings_Resolve(t *testing.T) {
 	base := ModelSettings{
-		Temperature:       param.NewOpt(0.5),
-		TopP:              param.NewOpt(0.9),
-		FrequencyPenalty:  param.NewOpt(0.0),
-		PresencePenalty:   param.NewOpt[float64](0.0),
-		ToolChoice:        ToolChoiceAuto,
-		ParallelToolCalls: param.NewOpt(true),
-		Truncation:        param.NewOpt(TruncationAuto),
-		MaxTokens:         param.NewOpt[int64](100),
+		Temperature:         param.NewOpt(0.5),
+		TopP:                param.NewOpt(0.9),
+		FrequencyPenalty:    param.NewOpt(0.0),
+		PresencePenalty:     param.NewOpt[float64](0.0),
+		ToolChoice:          ToolChoiceAuto,
+		ParallelToolCalls:   param.NewOpt(true),
+		Truncation:          param.NewOpt(TruncationAuto),
+		MaxTokens:           param.NewOpt[int64](100),
+		MaxCompletionTokens: param.NewOpt[int64](200),
 		Reasoning: openai.ReasoningParam{
 			Effort:  openai.ReasoningEffortLow,
 			Summary: openai.ReasoningSummaryConcise,
 		},
 		Verbosity:                       param.NewOpt(VerbosityMedium),
+		ServiceTier:                     param.NewOpt(ServiceTierAuto),
 		Metadata:                        map[string]string{"foo": "bar"},
 		Store:                           param.NewOpt(false),
 		IncludeUsage:                    param.NewOpt(false),
 		ResponseInclude:                 []responses.ResponseIncludable{responses.ResponseIncludableFileSearchCallResults},
 		TopLogprobs:                     param.NewOpt(int64(1)),
+		Logprobs:                        param.NewOpt(false),
 		ExtraQuery:                      map[string]string{"foo": "bar"},
 		ExtraHeaders:                    map[string]string{"foo": "bar"},
+		ExtraBody:                       map[string]any{"foo": "bar"},
 		CustomizeResponsesRequest:       nil,
 		CustomizeChatCompletionsRequest: nil,
 	}
@@ -197,9 +217,10 @@ func TestModelSettings_Resolve(t *testing.T) {
 				Effort:  openai.ReasoningEffortMedium,
 				Summary: openai.ReasoningSummaryDetailed,
 			},
-			Verbosity:  param.NewOpt(VerbosityHigh),
-			Store:      param.NewOpt(true),
-			ExtraQuery: map[string]string{"a": "b"},
+			Verbosity:   param.NewOpt(VerbosityHigh),
+			ServiceTier: param.NewOpt(ServiceTierPriority),
+			Store:       param.NewOpt(true),
+			ExtraQuery:  map[string]string{"a": "b"},
 			CustomizeResponsesRequest: func(context.Context, *responses.ResponseNewParams, []option.RequestOption) (*responses.ResponseNewParams, []option.RequestOption, error) {
 				return nil, nil, nil
 			},
@@ -215,33 +236,40 @@ func TestModelSettings_Resolve(t *testing.T) {
 		assert.Equal(t, param.NewOpt(true), resolved.ParallelToolCalls)
 		assert.Equal(t, param.NewOpt(TruncationDisabled), resolved.Truncation)
 		assert.Equal(t, param.NewOpt[int64](100), resolved.MaxTokens)
+		assert.Equal(t, param.NewOpt[int64](200), resolved.MaxCompletionTokens)
 		assert.Equal(t, openai.ReasoningParam{
 			Effort:  openai.ReasoningEffortMedium,
 			Summary: openai.ReasoningSummaryDetailed,
 		}, resolved.Reasoning)
 		assert.Equal(t, param.NewOpt(VerbosityHigh), resolved.Verbosity)
+		assert.Equal(t, param.NewOpt(ServiceTierPriority), resolved.ServiceTier)
 		assert.Equal(t, map[string]string{"foo": "bar"}, resolved.Metadata)
 		assert.Equal(t, param.NewOpt(true), resolved.Store)
 		assert.Equal(t, param.NewOpt(false), resolved.IncludeUsage)
 		assert.Equal(t, []responses.ResponseIncludable{responses.ResponseIncludableFileSearchCallResults}, resolved.ResponseInclude)
 		assert.Equal(t, param.NewOpt(int64(1)), resolved.TopLogprobs)
+		assert.Equal(t, param.NewOpt(false), resolved.Logprobs)
 		assert.Equal(t, map[string]string{"a": "b"}, resolved.ExtraQuery)
 		assert.Equal(t, map[string]string{"foo": "bar"}, resolved.ExtraHeaders)
+		assert.Equal(t, map[string]any{"foo": "bar"}, resolved.ExtraBody)
 		assert.NotNil(t, resolved.CustomizeResponsesRequest)
 		assert.Nil(t, resolved.CustomizeChatCompletionsRequest)
 	})
 
 	t.Run("overriding second set of properties", func(t *testing.T) {
 		override := ModelSettings{
-			TopP:              param.NewOpt(0.8),
-			PresencePenalty:   param.NewOpt(0.2),
-			ParallelToolCalls: param.NewOpt(false),
-			MaxTokens:         param.NewOpt[int64](42),
-			Metadata:          map[string]string{"a": "b"},
-			IncludeUsage:      param.NewOpt(true),
-			ResponseInclude:   []responses.ResponseIncludable{responses.ResponseIncludableMessageInputImageImageURL},
-			TopLogprobs:       param.NewOpt(int64(2)),
-			ExtraHeaders:      map[string]string{"c": "d"},
+			TopP:                param.NewOpt(0.8),
+			PresencePenalty:     param.NewOpt(0.2),
+			ParallelToolCalls:   param.NewOpt(false),
+			MaxTokens:           param.NewOpt[int64](42),
+			MaxCompletionTokens: param.NewOpt[int64](84),
+			Metadata:            map[string]string{"a": "b"},
+			IncludeUsage:        param.NewOpt(true),
+			ResponseInclude:     []responses.ResponseIncludable{responses.ResponseIncludableMessageInputImageImageURL},
+			TopLogprobs:         param.NewOpt(int64(2)),
+			Logprobs:            param.NewOpt(true),
+			ExtraHeaders:        map[string]string{"c": "d"},
+			ExtraBody:           map[string]any{"safe_prompt": true},
 			CustomizeChatCompletionsRequest: func(context.Context, *openai.ChatCompletionNewParams, []option.RequestOption) (*openai.ChatCompletionNewParams, []option.RequestOption, error) {
 				return nil, nil, nil
 			},
@@ -257,18 +285,22 @@ func TestModelSettings_Resolve(t *testing.T) {
 		assert.Equal(t, param.NewOpt(false), resolved.ParallelToolCalls)
 		assert.Equal(t, param.NewOpt(TruncationAuto), resolved.Truncation)
 		assert.Equal(t, param.NewOpt[int64](42), resolved.MaxTokens)
+		assert.Equal(t, param.NewOpt[int64](84), resolved.MaxCompletionTokens)
 		assert.Equal(t, openai.ReasoningParam{
 			Effort:  openai.ReasoningEffortLow,
 			Summary: openai.ReasoningSummaryConcise,
 		}, resolved.Reasoning)
 		assert.Equal(t, param.NewOpt(VerbosityMedium), resolved.Verbosity)
+		assert.Equal(t, param.NewOpt(ServiceTierAuto), resolved.ServiceTier)
 		assert.Equal(t, map[string]string{"a": "b"}, resolved.Metadata)
 		assert.Equal(t, param.NewOpt(false), resolved.Store)
 		assert.Equal(t, param.NewOpt(true), resolved.IncludeUsage)
 		assert.Equal(t, []responses.ResponseIncludable{responses.ResponseIncludableMessageInputImageImageURL}, resolved.ResponseInclude)
 		assert.Equal(t, param.NewOpt(int64(2)), resolved.TopLogprobs)
+		assert.Equal(t, param.NewOpt(true), resolved.Logprobs)
 		assert.Equal(t, map[string]string{"foo": "bar"}, resolved.ExtraQuery)
 		assert.Equal(t, map[string]string{"c": "d"}, resolved.ExtraHeaders)
+		assert.Equal(t, map[string]any{"safe_prompt": true}, resolved.ExtraBody)
 		assert.Nil(t, resolved.CustomizeResponsesRequest)
 		assert.NotNil(t, resolved.CustomizeChatCompletionsRequest)
 	})
@@ -758,6 +758,7 @@ func ResponseOutputMessageContentUnionFromResponseStreamEventUnionPart(
 		Annotations: input.Annotations,
 		Text:        input.Text,
 		Type:        input.Type,
+		Logprobs:    input.Logprobs,
 		Refusal:     input.Refusal,
 		JSON:        input.JSON,
 	}
@@ -0,0 +1,121 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime
+
+import (
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// Audio formats of the Realtime API.
+const (
+	AudioFormatPCM16    = "pcm16"
+	AudioFormatG711ULaw = "g711_ulaw"
+	AudioFormatG711ALaw = "g711_alaw"
+)
+
+// SessionConfig configures the realtime session. The instructions and the
+// tools are taken from the current agent.
+type SessionConfig struct {
+	// Optional output modalities, e.g. ["text", "audio"]. Defaults to the
+	// ones of the model.
+	Modalities []string
+
+	// Optional voice of the model, e.g. "alloy". It cannot be changed once
+	// the model has responded with audio.
+	Voice string
+
+	// Optional format of the input audio. Defaults to AudioFormatPCM16, 24kHz
+	// mono little-endian.
+	InputAudioFormat string
+
+	// Optional format of the output audio. Defaults to AudioFormatPCM16.
+	OutputAudioFormat string
+
+	// Optional transcription of the input audio, e.g.
+	// {"model": "gpt-4o-mini-transcribe"}. Transcripts are emitted as
+	// InputTranscriptEvent.
+	InputAudioTranscription map[string]any
+
+	// Optional turn detection, e.g. {"type": "semantic_vad"}. Defaults to
+	// the server VAD of the model.
+	TurnDetection map[string]any
+
+	// Optional sampling temperature.
+	Temperature *float64
+
+	// Optional maximum number of output tokens of a response.
+	MaxResponseOutputTokens int
+}
+
+func (c SessionConfig) toMap() map[string]any {
+	session := make(map[string]any)
+	if len(c.Modalities) > 0 {
+		session["modalities"] = c.Modalities
+	}
+	if c.Voice != "" {
+		session["voice"] = c.Voice
+	}
+	if c.InputAudioFormat != "" {
+		session["input_audio_format"] = c.InputAudioFormat
+	}
+	if c.OutputAudioFormat != "" {
+		session["output_audio_format"] = c.OutputAudioFormat
+	}
+	if c.InputAudioTranscription != nil {
+		session["input_audio_transcription"] = c.InputAudioTranscription
+	}
+	if c.TurnDetection != nil {
+		session["turn_detection"] = c.TurnDetection
+	}
+	if c.Temperature != nil {
+		session["temperature"] = *c.Temperature
+	}
+	if c.MaxResponseOutputTokens > 0 {
+		session["max_response_output_tokens"] = c.MaxResponseOutputTokens
+	}
+	return session
+}
+
+// outputBytesPerMillisecond returns the size of a millisecond of output audio.
+func (c SessionConfig) outputBytesPerMillisecond() int {
+	switch c.OutputAudioFormat {
+	case AudioFormatG711ULaw, AudioFormatG711ALaw:
+		return 8 // 8kHz, 1 byte per sample
+	default:
+		return 48 // 24kHz, 2 bytes per sample
+	}
+}
+
+// DefaultGuardrailDebounceTextLength is the default value of
+// RunConfig.GuardrailDebounceTextLength.
+const DefaultGuardrailDebounceTextLength = 100
+
+// RunConfig configures a realtime run.
+type RunConfig struct {
+	// Optional name of the realtime model. Defaults to DefaultModel.
+	Model string
+
+	// Optional configuration of the session.
+	Session SessionConfig
+
+	// Optional output guardrails run on the transcripts of the responses of
+	// every agent, in addition to the OutputGuardrails of the agents.
+	OutputGuardrails []agents.OutputGuardrail
+
+	// Optional number of characters of a transcript after which the output
+	// guardrails run again. Default (when left zero):
+	// DefaultGuardrailDebounceTextLength.
+	GuardrailDebounceTextLength int
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime
+
+import (
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// SessionEvent is an event emitted by a Session.
+type SessionEvent interface {
+	isSessionEvent()
+}
+
+// AgentStartEvent is emitted when an agent starts a response.
+type AgentStartEvent struct {
+	Agent *agents.Agent
+}
+
+// AgentEndEvent is emitted when an agent has completed a response.
+type AgentEndEvent struct {
+	Agent *agents.Agent
+}
+
+// HandoffEvent is emitted when an agent hands off the session to another one.
+type HandoffEvent struct {
+	FromAgent *agents.Agent
+	ToAgent   *agents.Agent
+}
+
+// ToolStartEvent is emitted when a function tool is invoked.
+type ToolStartEvent struct {
+	Agent     *agents.Agent
+	Tool      agents.FunctionTool
+	CallID    string
+	Arguments string
+}
+
+// ToolEndEvent is emitted when a function tool has returned, with the output
+// sent to the model.
+type ToolEndEvent struct {
+	Agent  *agents.Agent
+	Tool   agents.FunctionTool
+	CallID string
+	Output any
+}
+
+// AudioEvent carries a chunk of audio of a response, in the output audio
+// format of the session.
+type AudioEvent struct {
+	ItemID     string
+	ResponseID string
+	Data       []byte
+}
+
+// AudioEndEvent is emitted once the audio of a response item is complete.
+type AudioEndEvent struct {
+	ItemID string
+}
+
+// AudioInterruptedEvent is emitted when the audio of a response item is
+// interrupted, by the user speaking or by Session.Interrupt. The audio not
+// yet played should be discarded.
+type AudioInterruptedEvent struct {
+	ItemID string
+}
+
+// TranscriptDeltaEvent carries a chunk of the transcript, or of the text, of
+// a response item.
+type TranscriptDeltaEvent struct {
+	ItemID string
+	Delta  string
+}
+
+// TranscriptEvent carries the complete transcript, or text, of a response item.
+type TranscriptEvent struct {
+	ItemID     string
+	Transcript string
+}
+
+// InputTranscriptEvent carries the transcript of the input audio of the user,
+// when SessionConfig.InputAudioTranscription is set.
+type InputTranscriptEvent struct {
+	ItemID     string
+	Transcript string
+}
+
+// GuardrailTrippedEvent is emitted when an output guardrail trips on the
+// transcript of a response, which is then interrupted.
+type GuardrailTrippedEvent struct {
+	Agent   *agents.Agent
+	Results []agents.OutputGuardrailResult
+	// The transcript checked by the guardrails.
+	Transcript string
+}
+
+// ErrorEvent reports an error of the model or of the session. The session
+// goes on after it.
+type ErrorEvent struct {
+	Err error
+}
+
+// RawModelEvent carries every server event received from the model.
+type RawModelEvent struct {
+	Data map[string]any
+}
+
+func (AgentStartEvent) isSessionEvent()       {}
+func (AgentEndEvent) isSessionEvent()         {}
+func (HandoffEvent) isSessionEvent()          {}
+func (ToolStartEvent) isSessionEvent()        {}
+func (ToolEndEvent) isSessionEvent()          {}
+func (AudioEvent) isSessionEvent()            {}
+func (AudioEndEvent) isSessionEvent()         {}
+func (AudioInterruptedEvent) isSessionEvent() {}
+func (TranscriptDeltaEvent) isSessionEvent()  {}
+func (TranscriptEvent) isSessionEvent()       {}
+func (InputTranscriptEvent) isSessionEvent()  {}
+func (GuardrailTrippedEvent) isSessionEvent() {}
+func (ErrorEvent) isSessionEvent()            {}
+func (RawModelEvent) isSessionEvent()         {}
@@ -0,0 +1,171 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// DefaultModel is the realtime model used when RunConfig.Model is empty.
+const DefaultModel = "gpt-realtime"
+
+// DefaultOpenAIRealtimeURL is the WebSocket endpoint of the OpenAI Realtime API.
+const DefaultOpenAIRealtimeURL = "wss://api.openai.com/v1/realtime"
+
+// Model opens connections to a realtime model.
+type Model interface {
+	Connect(ctx context.Context, params ModelConnectParams) (ModelConnection, error)
+}
+
+// ModelConnectParams are the parameters of Model.Connect.
+type ModelConnectParams struct {
+	// Name of the model, e.g. "gpt-realtime".
+	ModelName string
+}
+
+// ModelConnection is a connection to a realtime model, exchanging the JSON
+// events of the Realtime API.
+type ModelConnection interface {
+	// SendEvent sends a client event, such as "session.update".
+	SendEvent(ctx context.Context, event map[string]any) error
+
+	// ReceiveEvent blocks until the next server event. It returns io.EOF once
+	// the connection is closed.
+	ReceiveEvent() (map[string]any, error)
+
+	// Close closes the connection.
+	Close() error
+}
+
+// OpenAIModel connects to the OpenAI Realtime API over WebSocket.
+type OpenAIModel struct {
+	// Optional API key. Defaults to the key of the client, or to the default
+	// OpenAI key (see agents.SetDefaultOpenaiKey).
+	APIKey string
+
+	// Optional URL of the endpoint. Defaults to DefaultOpenAIRealtimeURL.
+	URL string
+
+	// Optional headers added to the WebSocket handshake.
+	Header http.Header
+
+	// Optional dialer. Defaults to websocket.DefaultDialer.
+	Dialer *websocket.Dialer
+}
+
+// NewOpenAIModel returns an OpenAIModel using the default OpenAI key.
+func NewOpenAIModel() *OpenAIModel {
+	return &OpenAIModel{}
+}
+
+// NewOpenAIModelFromClient returns an OpenAIModel using the key of the client.
+func NewOpenAIModelFromClient(client agents.OpenaiClient) *OpenAIModel {
+	return &OpenAIModel{APIKey: client.APIKey.Or("")}
+}
+
+func (m *OpenAIModel) apiKey() string {
+	if m.APIKey != "" {
+		return m.APIKey
+	}
+	if c := agents.GetDefaultOpenaiClient(); c != nil && c.APIKey.Valid() {
+		return c.APIKey.Value
+	}
+	return agents.GetDefaultOpenaiKey().Or("")
+}
+
+func (m *OpenAIModel) Connect(ctx context.Context, params ModelConnectParams) (ModelConnection, error) {
+	u, err := url.Parse(cmp.Or(m.URL, DefaultOpenAIRealtimeURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid realtime URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("model", cmp.Or(params.ModelName, DefaultModel))
+	u.RawQuery = query.Encode()
+
+	header := m.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if key := m.apiKey(); key != "" {
+		header.Set("Authorization", "Bearer "+key)
+	}
+	header.Set("OpenAI-Beta", "realtime=v1")
+
+	dialer := m.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, resp, err := dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("realtime websocket connection error (status %d): %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("realtime websocket connection error: %w", err)
+	}
+	return &openAIConnection{conn: conn}, nil
+}
+
+type openAIConnection struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (c *openAIConnection) SendEvent(ctx context.Context, event map[string]any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetWriteDeadline(deadline)
+		defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
+	}
+	if err := c.conn.WriteJSON(event); err != nil {
+		return fmt.Errorf("error sending realtime event: %w", err)
+	}
+	return nil
+}
+
+func (c *openAIConnection) ReceiveEvent() (map[string]any, error) {
+	_, message, err := c.conn.ReadMessage()
+	if err != nil {
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) || errors.Is(err, net.ErrClosed) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("error reading realtime event: %w", err)
+	}
+	var event map[string]any
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, fmt.Errorf("error JSON-unmarshaling realtime event: %w", err)
+	}
+	return event, nil
+}
+
+func (c *openAIConnection) Close() error {
+	c.writeMu.Lock()
+	_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/nlpodyssey/openai-agents-go/realtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIModelConnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gpt-realtime-mini", r.URL.Query().Get("model"))
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		assert.Equal(t, "realtime=v1", r.Header.Get("OpenAI-Beta"))
+
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		require.NoError(t, conn.WriteJSON(map[string]any{"type": "session.created"}))
+		var event map[string]any
+		require.NoError(t, conn.ReadJSON(&event))
+		require.NoError(t, conn.WriteJSON(map[string]any{"type": "echo", "event": event}))
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	model := &realtime.OpenAIModel{
+		APIKey: "test-key",
+		URL:    "ws" + strings.TrimPrefix(server.URL, "http"),
+	}
+	conn, err := model.Connect(t.Context(), realtime.ModelConnectParams{ModelName: "gpt-realtime-mini"})
+	require.NoError(t, err)
+
+	event, err := conn.ReceiveEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "session.created", event["type"])
+
+	require.NoError(t, conn.SendEvent(t.Context(), map[string]any{"type": "response.create"}))
+	event, err = conn.ReceiveEvent()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "response.create"}, event["event"])
+
+	require.NoError(t, conn.Close())
+	_, err = conn.ReceiveEvent()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package realtime runs voice agents on the OpenAI Realtime API over
+// WebSocket, with the same agents, tools, handoffs and output guardrails of
+// the agents package.
+package realtime
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// Runner starts realtime sessions with agents. The instructions, the function
+// tools, the handoffs and the output guardrails of the agents are the same
+// used by agents.Runner, so voice and text agents can share their logic.
+type Runner struct {
+	// Optional realtime model. Defaults to NewOpenAIModel().
+	Model Model
+
+	Config RunConfig
+}
+
+// Run connects to the realtime model and starts a session with the given
+// agent. The session lasts until it is closed, or the context is canceled.
+func (r Runner) Run(ctx context.Context, startingAgent *agents.Agent) (*Session, error) {
+	if startingAgent == nil {
+		return nil, agents.UserErrorf("starting agent is required")
+	}
+	model := r.Model
+	if model == nil {
+		model = NewOpenAIModel()
+	}
+
+	conn, err := model.Connect(ctx, ModelConnectParams{
+		ModelName: cmp.Or(r.Config.Model, DefaultModel),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSession(ctx, conn, r.Config)
+	if err = s.setAgent(s.ctx, startingAgent); err != nil {
+		s.cancel()
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to configure realtime session: %w", err)
+	}
+	go s.run()
+	return s, nil
+}
@@ -0,0 +1,580 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// Session is a running realtime session with an agent. Its events must be
+// consumed from Events until the channel is closed.
+type Session struct {
+	conn   ModelConnection
+	config RunConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan SessionEvent
+	wg     sync.WaitGroup
+	err    error
+
+	mu             sync.Mutex
+	agent          *agents.Agent
+	tools          map[string]agents.FunctionTool
+	handoffs       map[string]agents.Handoff
+	responseActive bool
+	audio          *playingAudio
+	transcripts    map[string]*transcriptState
+}
+
+// playingAudio tracks the audio of the response item being played.
+type playingAudio struct {
+	itemID string
+	start  time.Time
+	bytes  int
+}
+
+// transcriptState tracks the transcript of a response item checked by the
+// output guardrails.
+type transcriptState struct {
+	text    strings.Builder
+	checked int
+	tripped bool
+}
+
+func newSession(ctx context.Context, conn ModelConnection, config RunConfig) *Session {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Session{
+		conn:        conn,
+		config:      config,
+		ctx:         ctx,
+		cancel:      cancel,
+		events:      make(chan SessionEvent, 64),
+		transcripts: make(map[string]*transcriptState),
+	}
+	context.AfterFunc(ctx, func() { _ = conn.Close() })
+	return s
+}
+
+// Events returns the events of the session. The channel is closed once the
+// session is over; Err then reports why, if not closed by the caller.
+func (s *Session) Events() <-chan SessionEvent {
+	return s.events
+}
+
+// Err returns the error which ended the session, if any. It must be called
+// after the channel returned by Events has been closed.
+func (s *Session) Err() error {
+	return s.err
+}
+
+// CurrentAgent returns the agent currently responding.
+func (s *Session) CurrentAgent() *agents.Agent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.agent
+}
+
+// SendMessage sends a text message of the user and asks for a response.
+func (s *Session) SendMessage(ctx context.Context, text string) error {
+	err := s.conn.SendEvent(ctx, map[string]any{
+		"type": "conversation.item.create",
+		"item": map[string]any{
+			"type":    "message",
+			"role":    "user",
+			"content": []any{map[string]any{"type": "input_text", "text": text}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.conn.SendEvent(ctx, map[string]any{"type": "response.create"})
+}
+
+// SendAudio appends audio of the user, in the input audio format of the
+// session, to the input buffer. With turn detection, the model responds once
+// the user stops speaking.
+func (s *Session) SendAudio(ctx context.Context, audio []byte) error {
+	return s.conn.SendEvent(ctx, map[string]any{
+		"type":  "input_audio_buffer.append",
+		"audio": base64.StdEncoding.EncodeToString(audio),
+	})
+}
+
+// CommitAudio commits the input audio buffer as a message of the user and asks
+// for a response. It is needed when turn detection is disabled.
+func (s *Session) CommitAudio(ctx context.Context) error {
+	if err := s.conn.SendEvent(ctx, map[string]any{"type": "input_audio_buffer.commit"}); err != nil {
+		return err
+	}
+	return s.conn.SendEvent(ctx, map[string]any{"type": "response.create"})
+}
+
+// Interrupt cancels the response in progress, if any, and truncates its audio
+// to the part already played, which is estimated from the time elapsed since
+// its first chunk.
+func (s *Session) Interrupt(ctx context.Context) error {
+	s.mu.Lock()
+	active := s.responseActive
+	s.mu.Unlock()
+	if active {
+		if err := s.conn.SendEvent(ctx, map[string]any{"type": "response.cancel"}); err != nil {
+			return err
+		}
+	}
+	return s.interruptAudio(ctx)
+}
+
+// Close ends the session, closing the connection to the model.
+func (s *Session) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *Session) run() {
+	defer func() {
+		s.cancel()
+		s.wg.Wait()
+		close(s.events)
+	}()
+
+	for {
+		event, err := s.conn.ReceiveEvent()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && s.ctx.Err() == nil {
+				s.err = err
+			}
+			return
+		}
+		s.emit(RawModelEvent{Data: event})
+		s.handleEvent(event)
+	}
+}
+
+func (s *Session) emit(event SessionEvent) {
+	select {
+	case s.events <- event:
+	case <-s.ctx.Done():
+	}
+}
+
+// goAsync runs fn without blocking the events of the model.
+func (s *Session) goAsync(fn func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn()
+	}()
+}
+
+func (s *Session) handleEvent(event map[string]any) {
+	eventType, _ := event["type"].(string)
+	itemID, _ := event["item_id"].(string)
+
+	switch eventType {
+	case "error":
+		s.emit(ErrorEvent{Err: modelError(event["error"])})
+
+	case "response.created":
+		s.mu.Lock()
+		s.responseActive = true
+		agent := s.agent
+		s.mu.Unlock()
+		s.emit(AgentStartEvent{Agent: agent})
+
+	case "response.done":
+		s.mu.Lock()
+		s.responseActive = false
+		agent := s.agent
+		s.mu.Unlock()
+		s.emit(AgentEndEvent{Agent: agent})
+		if calls := functionCalls(event); len(calls) > 0 {
+			s.goAsync(func() { s.handleFunctionCalls(agent, calls) })
+		}
+
+	case "response.audio.delta", "response.output_audio.delta":
+		delta, _ := event["delta"].(string)
+		data, err := base64.StdEncoding.DecodeString(delta)
+		if err != nil {
+			s.emit(ErrorEvent{Err: fmt.Errorf("invalid audio delta: %w", err)})
+			return
+		}
+		s.mu.Lock()
+		if s.audio == nil || s.audio.itemID != itemID {
+			s.audio = &playingAudio{itemID: itemID, start: time.Now()}
+		}
+		s.audio.bytes += len(data)
+		s.mu.Unlock()
+		responseID, _ := event["response_id"].(string)
+		s.emit(AudioEvent{ItemID: itemID, ResponseID: responseID, Data: data})
+
+	case "response.audio.done", "response.output_audio.done":
+		s.emit(AudioEndEvent{ItemID: itemID})
+
+	case "response.audio_transcript.delta", "response.output_audio_transcript.delta",
+		"response.text.delta", "response.output_text.delta":
+		delta, _ := event["delta"].(string)
+		s.emit(TranscriptDeltaEvent{ItemID: itemID, Delta: delta})
+		s.appendTranscript(itemID, delta)
+
+	case "response.audio_transcript.done", "response.output_audio_transcript.done",
+		"response.text.done", "response.output_text.done":
+		transcript, _ := event["transcript"].(string)
+		if text, ok := event["text"].(string); ok {
+			transcript = text
+		}
+		s.emit(TranscriptEvent{ItemID: itemID, Transcript: transcript})
+		s.finishTranscript(itemID, transcript)
+
+	case "conversation.item.input_audio_transcription.completed":
+		transcript, _ := event["transcript"].(string)
+		s.emit(InputTranscriptEvent{ItemID: itemID, Transcript: transcript})
+
+	case "input_audio_buffer.speech_started":
+		// The server cancels the response itself: only the audio is truncated.
+		if err := s.interruptAudio(s.ctx); err != nil {
+			s.emit(ErrorEvent{Err: err})
+		}
+	}
+}
+
+func modelError(v any) error {
+	if m, ok := v.(map[string]any); ok {
+		if message, ok := m["message"].(string); ok {
+			if code, ok := m["code"].(string); ok && code != "" {
+				return fmt.Errorf("realtime model error (%s): %s", code, message)
+			}
+			return fmt.Errorf("realtime model error: %s", message)
+		}
+	}
+	return fmt.Errorf("realtime model error: %v", v)
+}
+
+// interruptAudio truncates the audio being played, if any.
+func (s *Session) interruptAudio(ctx context.Context) error {
+	s.mu.Lock()
+	audio := s.audio
+	s.audio = nil
+	s.mu.Unlock()
+	if audio == nil {
+		return nil
+	}
+
+	received := audio.bytes / s.config.Session.outputBytesPerMillisecond()
+	playedMs := min(int(time.Since(audio.start).Milliseconds()), received)
+	s.emit(AudioInterruptedEvent{ItemID: audio.itemID})
+	return s.conn.SendEvent(ctx, map[string]any{
+		"type":          "conversation.item.truncate",
+		"item_id":       audio.itemID,
+		"content_index": 0,
+		"audio_end_ms":  playedMs,
+	})
+}
+
+// setAgent configures the session for the agent.
+func (s *Session) setAgent(ctx context.Context, agent *agents.Agent) error {
+	session := s.config.Session.toMap()
+
+	instructions, err := agent.GetSystemPrompt(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get instructions of agent %q: %w", agent.Name, err)
+	}
+	session["instructions"] = instructions.Or("")
+
+	allTools, err := agent.GetAllTools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get tools of agent %q: %w", agent.Name, err)
+	}
+	handoffs, err := enabledHandoffs(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("failed to get handoffs of agent %q: %w", agent.Name, err)
+	}
+
+	toolsByName := make(map[string]agents.FunctionTool, len(allTools))
+	handoffsByName := make(map[string]agents.Handoff, len(handoffs))
+	tools := make([]any, 0, len(allTools)+len(handoffs))
+	for _, tool := range allTools {
+		functionTool, ok := tool.(agents.FunctionTool)
+		if !ok {
+			agents.Logger().Warn(fmt.Sprintf("Realtime sessions only support function tools, skipping tool %q of agent %q",
+				tool.ToolName(), agent.Name))
+			continue
+		}
+		toolsByName[functionTool.Name] = functionTool
+		tools = append(tools, functionToolParam(functionTool.Name, functionTool.Description, functionTool.ParamsJSONSchema))
+	}
+	for _, handoff := range handoffs {
+		handoffsByName[handoff.ToolName] = handoff
+		tools = append(tools, functionToolParam(handoff.ToolName, handoff.ToolDescription, handoff.InputJSONSchema))
+	}
+	session["tools"] = tools
+	session["tool_choice"] = "auto"
+
+	err = s.conn.SendEvent(ctx, map[string]any{"type": "session.update", "session": session})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.agent = agent
+	s.tools = toolsByName
+	s.handoffs = handoffsByName
+	s.mu.Unlock()
+	return nil
+}
+
+func functionToolParam(name, description string, parameters map[string]any) map[string]any {
+	if parameters == nil {
+		parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	return map[string]any{
+		"type":        "function",
+		"name":        name,
+		"description": description,
+		"parameters":  parameters,
+	}
+}
+
+// enabledHandoffs returns the handoffs of the agent which are enabled.
+func enabledHandoffs(ctx context.Context, agent *agents.Agent) ([]agents.Handoff, error) {
+	handoffs := slices.Clone(agent.Handoffs)
+	for _, a := range agent.AgentHandoffs {
+		h, err := agents.SafeHandoffFromAgent(agents.HandoffFromAgentParams{Agent: a})
+		if err != nil {
+			return nil, fmt.Errorf("failed to make Handoff from Agent %q: %w", a.Name, err)
+		}
+		handoffs = append(handoffs, *h)
+	}
+
+	enabled := handoffs[:0]
+	for _, h := range handoffs {
+		if h.IsEnabled != nil {
+			ok, err := h.IsEnabled.IsEnabled(ctx, agent)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		enabled = append(enabled, h)
+	}
+	return enabled, nil
+}
+
+type functionCall struct {
+	name      string
+	callID    string
+	arguments string
+}
+
+// functionCalls returns the function calls of a completed response.
+func functionCalls(event map[string]any) []functionCall {
+	response, _ := event["response"].(map[string]any)
+	output, _ := response["output"].([]any)
+	var calls []functionCall
+	for _, v := range output {
+		item, _ := v.(map[string]any)
+		if item["type"] != "function_call" {
+			continue
+		}
+		var call functionCall
+		call.name, _ = item["name"].(string)
+		call.callID, _ = item["call_id"].(string)
+		call.arguments, _ = item["arguments"].(string)
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// handleFunctionCalls runs the tools and the handoffs called in a response of
+// the agent, sends their outputs and asks for a new response.
+func (s *Session) handleFunctionCalls(agent *agents.Agent, calls []functionCall) {
+	for _, call := range calls {
+		output, err := s.handleFunctionCall(agent, call)
+		if err != nil {
+			s.emit(ErrorEvent{Err: err})
+			output = fmt.Sprintf("An error occurred while running the tool: %s", err)
+		}
+		err = s.conn.SendEvent(s.ctx, map[string]any{
+			"type": "conversation.item.create",
+			"item": map[string]any{
+				"type":    "function_call_output",
+				"call_id": call.callID,
+				"output":  output,
+			},
+		})
+		if err != nil {
+			s.emit(ErrorEvent{Err: err})
+			return
+		}
+	}
+	if err := s.conn.SendEvent(s.ctx, map[string]any{"type": "response.create"}); err != nil {
+		s.emit(ErrorEvent{Err: err})
+	}
+}
+
+func (s *Session) handleFunctionCall(agent *agents.Agent, call functionCall) (string, error) {
+	s.mu.Lock()
+	tool, isTool := s.tools[call.name]
+	handoff, isHandoff := s.handoffs[call.name]
+	s.mu.Unlock()
+
+	switch {
+	case isTool:
+		return s.runTool(agent, tool, call)
+	case isHandoff:
+		newAgent, err := handoff.OnInvokeHandoff(s.ctx, call.arguments)
+		if err != nil {
+			return "", fmt.Errorf("handoff %q failed: %w", call.name, err)
+		}
+		if err = s.setAgent(s.ctx, newAgent); err != nil {
+			return "", err
+		}
+		s.emit(HandoffEvent{FromAgent: agent, ToAgent: newAgent})
+		return handoff.GetTransferMessage(newAgent), nil
+	default:
+		return "", agents.ModelBehaviorErrorf("tool %s not found in agent %s", call.name, agent.Name)
+	}
+}
+
+func (s *Session) runTool(agent *agents.Agent, tool agents.FunctionTool, call functionCall) (string, error) {
+	s.emit(ToolStartEvent{Agent: agent, Tool: tool, CallID: call.callID, Arguments: call.arguments})
+
+	result, err := tool.OnInvokeTool(s.ctx, call.arguments)
+	if err != nil {
+		errorFn := agents.DefaultToolErrorFunction
+		if tool.FailureErrorFunction != nil {
+			errorFn = *tool.FailureErrorFunction
+		}
+		if errorFn == nil {
+			return "", fmt.Errorf("error running tool %s: %w", tool.Name, err)
+		}
+		if result, err = errorFn(s.ctx, err); err != nil {
+			return "", fmt.Errorf("error running tool %s: %w", tool.Name, err)
+		}
+	}
+
+	var output string
+	switch v := result.(type) {
+	case string:
+		output = v
+	case []byte:
+		output = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal output of tool %s: %w", tool.Name, err)
+		}
+		output = string(b)
+	}
+
+	s.emit(ToolEndEvent{Agent: agent, Tool: tool, CallID: call.callID, Output: result})
+	return output, nil
+}
+
+func (s *Session) debounceTextLength() int {
+	if s.config.GuardrailDebounceTextLength > 0 {
+		return s.config.GuardrailDebounceTextLength
+	}
+	return DefaultGuardrailDebounceTextLength
+}
+
+// appendTranscript runs the output guardrails each time the transcript of the
+// item grows by the debounce length.
+func (s *Session) appendTranscript(itemID, delta string) {
+	s.mu.Lock()
+	state, ok := s.transcripts[itemID]
+	if !ok {
+		state = &transcriptState{}
+		s.transcripts[itemID] = state
+	}
+	state.text.WriteString(delta)
+	text := state.text.String()
+	check := !state.tripped && len(text)-state.checked >= s.debounceTextLength()
+	if check {
+		state.checked = len(text)
+	}
+	agent := s.agent
+	s.mu.Unlock()
+
+	if check {
+		s.goAsync(func() { s.runGuardrails(agent, state, text) })
+	}
+}
+
+// finishTranscript runs the output guardrails on the complete transcript, if
+// not checked yet.
+func (s *Session) finishTranscript(itemID, transcript string) {
+	s.mu.Lock()
+	state, ok := s.transcripts[itemID]
+	if !ok {
+		state = &transcriptState{}
+	}
+	delete(s.transcripts, itemID)
+	check := !state.tripped && state.checked < len(transcript)
+	state.checked = len(transcript)
+	agent := s.agent
+	s.mu.Unlock()
+
+	if check {
+		s.goAsync(func() { s.runGuardrails(agent, state, transcript) })
+	}
+}
+
+func (s *Session) runGuardrails(agent *agents.Agent, state *transcriptState, transcript string) {
+	guardrails := slices.Concat(s.config.OutputGuardrails, agent.OutputGuardrails)
+	if len(guardrails) == 0 {
+		return
+	}
+
+	var triggered []agents.OutputGuardrailResult
+	for _, guardrail := range guardrails {
+		result, err := guardrail.Run(s.ctx, agent, transcript)
+		if err != nil {
+			s.emit(ErrorEvent{Err: fmt.Errorf("output guardrail %q failed: %w", guardrail.Name, err)})
+			continue
+		}
+		if result.Output.TripwireTriggered {
+			triggered = append(triggered, result)
+		}
+	}
+	if len(triggered) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	alreadyTripped := state.tripped
+	state.tripped = true
+	s.mu.Unlock()
+	if alreadyTripped {
+		return
+	}
+
+	s.emit(GuardrailTrippedEvent{Agent: agent, Results: triggered, Transcript: transcript})
+	if err := s.Interrupt(s.ctx); err != nil {
+		s.emit(ErrorEvent{Err: err})
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realtime_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/realtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeModel struct {
+	conn   *fakeConnection
+	params realtime.ModelConnectParams
+}
+
+func (m *fakeModel) Connect(_ context.Context, params realtime.ModelConnectParams) (realtime.ModelConnection, error) {
+	m.params = params
+	return m.conn, nil
+}
+
+type fakeConnection struct {
+	sent      chan map[string]any
+	server    chan map[string]any
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newFakeConnection() *fakeConnection {
+	return &fakeConnection{
+		sent:   make(chan map[string]any, 100),
+		server: make(chan map[string]any, 100),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *fakeConnection) SendEvent(_ context.Context, event map[string]any) error {
+	c.sent <- event
+	return nil
+}
+
+func (c *fakeConnection) ReceiveEvent() (map[string]any, error) {
+	select {
+	case event := <-c.server:
+		return event, nil
+	case <-c.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c *fakeConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+// waitSent returns the next event of the given type sent to the model.
+func (c *fakeConnection) waitSent(t *testing.T, eventType string) map[string]any {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-c.sent:
+			if event["type"] == eventType {
+				return event
+			}
+		case <-timeout:
+			require.FailNow(t, "timeout waiting for sent event", eventType)
+		}
+	}
+}
+
+// waitEvent returns the next session event of type T.
+func waitEvent[T realtime.SessionEvent](t *testing.T, s *realtime.Session) T {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-s.Events():
+			require.True(t, ok, "session events closed")
+			if e, ok := event.(T); ok {
+				return e
+			}
+		case <-timeout:
+			var zero T
+			require.FailNowf(t, "timeout waiting for session event", "%T", zero)
+		}
+	}
+}
+
+func startSession(t *testing.T, agent *agents.Agent, config realtime.RunConfig) (*realtime.Session, *fakeConnection) {
+	t.Helper()
+	conn := newFakeConnection()
+	session, err := realtime.Runner{Model: &fakeModel{conn: conn}, Config: config}.Run(t.Context(), agent)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	conn.waitSent(t, "session.update")
+	return session, conn
+}
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+func getWeather(_ context.Context, args weatherArgs) (string, error) {
+	return "sunny in " + args.City, nil
+}
+
+func TestRunnerConfiguresSession(t *testing.T) {
+	billing := &agents.Agent{Name: "billing", Instructions: agents.InstructionsStr("Handle billing.")}
+	agent := &agents.Agent{
+		Name:          "assistant",
+		Instructions:  agents.InstructionsStr("Be brief."),
+		Tools:         []agents.Tool{agents.NewFunctionTool("get_weather", "Get the weather.", getWeather)},
+		AgentHandoffs: []*agents.Agent{billing},
+	}
+	conn := newFakeConnection()
+	model := &fakeModel{conn: conn}
+	session, err := realtime.Runner{
+		Model:  model,
+		Config: realtime.RunConfig{Session: realtime.SessionConfig{Voice: "alloy"}},
+	}.Run(t.Context(), agent)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	assert.Equal(t, realtime.DefaultModel, model.params.ModelName)
+	update := conn.waitSent(t, "session.update")["session"].(map[string]any)
+	assert.Equal(t, "Be brief.", update["instructions"])
+	assert.Equal(t, "alloy", update["voice"])
+	assert.Equal(t, "auto", update["tool_choice"])
+
+	var toolNames []string
+	for _, tool := range update["tools"].([]any) {
+		toolNames = append(toolNames, tool.(map[string]any)["name"].(string))
+	}
+	assert.Equal(t, []string{"get_weather", "transfer_to_billing"}, toolNames)
+	assert.Same(t, agent, session.CurrentAgent())
+}
+
+func TestSessionRunsToolCalls(t *testing.T) {
+	agent := &agents.Agent{
+		Name:  "assistant",
+		Tools: []agents.Tool{agents.NewFunctionTool("get_weather", "Get the weather.", getWeather)},
+	}
+	session, conn := startSession(t, agent, realtime.RunConfig{})
+
+	conn.server <- map[string]any{"type": "response.created"}
+	conn.server <- map[string]any{
+		"type": "response.done",
+		"response": map[string]any{
+			"output": []any{map[string]any{
+				"type":      "function_call",
+				"name":      "get_weather",
+				"call_id":   "call_1",
+				"arguments": `{"city": "Rome"}`,
+			}},
+		},
+	}
+
+	start := waitEvent[realtime.ToolStartEvent](t, session)
+	assert.Equal(t, "get_weather", start.Tool.Name)
+	assert.Equal(t, `{"city": "Rome"}`, start.Arguments)
+	end := waitEvent[realtime.ToolEndEvent](t, session)
+	assert.Equal(t, "sunny in Rome", end.Output)
+
+	output := conn.waitSent(t, "conversation.item.create")["item"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":    "function_call_output",
+		"call_id": "call_1",
+		"output":  "sunny in Rome",
+	}, output)
+	conn.waitSent(t, "response.create")
+}
+
+func TestSessionHandoff(t *testing.T) {
+	billing := &agents.Agent{Name: "billing", Instructions: agents.InstructionsStr("Handle billing.")}
+	agent := &agents.Agent{Name: "assistant", AgentHandoffs: []*agents.Agent{billing}}
+	session, conn := startSession(t, agent, realtime.RunConfig{})
+
+	conn.server <- map[string]any{
+		"type": "response.done",
+		"response": map[string]any{
+			"output": []any{map[string]any{
+				"type":      "function_call",
+				"name":      "transfer_to_billing",
+				"call_id":   "call_1",
+				"arguments": "{}",
+			}},
+		},
+	}
+
+	update := conn.waitSent(t, "session.update")["session"].(map[string]any)
+	assert.Equal(t, "Handle billing.", update["instructions"])
+	handoff := waitEvent[realtime.HandoffEvent](t, session)
+	assert.Same(t, agent, handoff.FromAgent)
+	assert.Same(t, billing, handoff.ToAgent)
+	assert.Same(t, billing, session.CurrentAgent())
+
+	output := conn.waitSent(t, "conversation.item.create")["item"].(map[string]any)
+	assert.Equal(t, `{"assistant":"billing"}`, output["output"])
+	conn.waitSent(t, "response.create")
+}
+
+func TestSessionUnknownToolCall(t *testing.T) {
+	session, conn := startSession(t, &agents.Agent{Name: "assistant"}, realtime.RunConfig{})
+
+	conn.server <- map[string]any{
+		"type": "response.done",
+		"response": map[string]any{
+			"output": []any{map[string]any{"type": "function_call", "name": "missing", "call_id": "call_1"}},
+		},
+	}
+
+	event := waitEvent[realtime.ErrorEvent](t, session)
+	assert.ErrorAs(t, event.Err, &agents.ModelBehaviorError{})
+	output := conn.waitSent(t, "conversation.item.create")["item"].(map[string]any)
+	assert.Equal(t, "call_1", output["call_id"])
+}
+
+func TestSessionAudioInterruptedBySpeech(t *testing.T) {
+	session, conn := startSession(t, &agents.Agent{Name: "assistant"}, realtime.RunConfig{})
+
+	conn.server <- map[string]any{"type": "response.created"}
+	conn.server <- map[string]any{
+		"type":        "response.audio.delta",
+		"item_id":     "item_1",
+		"response_id": "resp_1",
+		"delta":       "AAECAw==",
+	}
+	audio := waitEvent[realtime.AudioEvent](t, session)
+	assert.Equal(t, []byte{0, 1, 2, 3}, audio.Data)
+	assert.Equal(t, "item_1", audio.ItemID)
+
+	conn.server <- map[string]any{"type": "input_audio_buffer.speech_started"}
+	interrupted := waitEvent[realtime.AudioInterruptedEvent](t, session)
+	assert.Equal(t, "item_1", interrupted.ItemID)
+
+	truncate := conn.waitSent(t, "conversation.item.truncate")
+	assert.Equal(t, "item_1", truncate["item_id"])
+	// 4 bytes of pcm16 audio are less than a millisecond.
+	assert.Equal(t, 0, truncate["audio_end_ms"])
+}
+
+func TestSessionInterrupt(t *testing.T) {
+	session, conn := startSession(t, &agents.Agent{Name: "assistant"}, realtime.RunConfig{})
+
+	conn.server <- map[string]any{"type": "response.created"}
+	waitEvent[realtime.AgentStartEvent](t, session)
+
+	require.NoError(t, session.Interrupt(t.Context()))
+	conn.waitSent(t, "response.cancel")
+}
+
+func TestSessionOutputGuardrailInterruptsResponse(t *testing.T) {
+	guardrail := agents.OutputGuardrail{
+		Name: "no_secrets",
+		GuardrailFunction: func(_ context.Context, _ *agents.Agent, output any) (agents.GuardrailFunctionOutput, error) {
+			return agents.GuardrailFunctionOutput{TripwireTriggered: output.(string) == "the secret is 42"}, nil
+		},
+	}
+	agent := &agents.Agent{Name: "assistant", OutputGuardrails: []agents.OutputGuardrail{guardrail}}
+	session, conn := startSession(t, agent, realtime.RunConfig{GuardrailDebounceTextLength: 5})
+
+	conn.server <- map[string]any{"type": "response.created"}
+	for _, delta := range []string{"the secret", " is 42"} {
+		conn.server <- map[string]any{"type": "response.audio_transcript.delta", "item_id": "item_1", "delta": delta}
+	}
+
+	tripped := waitEvent[realtime.GuardrailTrippedEvent](t, session)
+	assert.Equal(t, "the secret is 42", tripped.Transcript)
+	require.Len(t, tripped.Results, 1)
+	assert.Equal(t, "no_secrets", tripped.Results[0].Guardrail.Name)
+	conn.waitSent(t, "response.cancel")
+}
+
+func TestSessionSendMessage(t *testing.T) {
+	session, conn := startSession(t, &agents.Agent{Name: "assistant"}, realtime.RunConfig{})
+
+	require.NoError(t, session.SendMessage(t.Context(), "hello"))
+	item := conn.waitSent(t, "conversation.item.create")["item"].(map[string]any)
+	assert.Equal(t, "user", item["role"])
+	assert.Equal(t, []any{map[string]any{"type": "input_text", "text": "hello"}}, item["content"])
+	conn.waitSent(t, "response.create")
+
+	require.NoError(t, session.SendAudio(t.Context(), []byte{1, 2}))
+	assert.Equal(t, "AQI=", conn.waitSent(t, "input_audio_buffer.append")["audio"])
+}
+
+func TestSessionEndsWhenConnectionCloses(t *testing.T) {
+	session, conn := startSession(t, &agents.Agent{Name: "assistant"}, realtime.RunConfig{})
+
+	conn.server <- map[string]any{"type": "error", "error": map[string]any{"message": "bad request"}}
+	event := waitEvent[realtime.ErrorEvent](t, session)
+	assert.EqualError(t, event.Err, "realtime model error: bad request")
+
+	require.NoError(t, conn.Close())
+	for range session.Events() {
+	}
+	assert.NoError(t, session.Err())
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONLExporter is an Exporter writing each trace and span as a line of JSON,
+// in the same format sent to the OpenAI backend.
+//
+// The output can be inspected with the agenttrace command.
+type JSONLExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLExporter returns a JSONLExporter writing to w.
+func NewJSONLExporter(w io.Writer) *JSONLExporter {
+	return &JSONLExporter{w: w}
+}
+
+// NewJSONLFileExporter returns a JSONLExporter appending to the file at path,
+// which is created if it does not exist. Close the exporter to close the file.
+func NewJSONLFileExporter(path string) (*JSONLExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	return &JSONLExporter{w: f, closer: f}, nil
+}
+
+func (e *JSONLExporter) Export(_ context.Context, items []any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enc := json.NewEncoder(e.w)
+	for _, item := range items {
+		var data map[string]any
+		switch v := item.(type) {
+		case Trace:
+			data = v.Export()
+		case Span:
+			data = v.Export()
+		default:
+			return fmt.Errorf("JSONLExporter: unexpected item type %T", item)
+		}
+		if data == nil {
+			continue
+		}
+		if err := enc.Encode(data); err != nil {
+			return fmt.Errorf("JSONLExporter: failed to write item: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying file, if the exporter was created with
+// NewJSONLFileExporter.
+func (e *JSONLExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oteltracing exports agent traces as OpenTelemetry spans.
+//
+// Attributes follow the OpenTelemetry semantic conventions for generative AI
+// (gen_ai.*) where applicable, so traces can be inspected with any backend
+// understanding them, such as Jaeger, Tempo or Datadog.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans created by Processor.
+const ScopeName = "github.com/nlpodyssey/openai-agents-go/tracing/oteltracing"
+
+// Processor is a tracing.Processor which mirrors each trace and span as an
+// OpenTelemetry span. Each trace becomes a root span, and agent spans are
+// nested following the original parent relationships.
+type Processor struct {
+	tracer trace.Tracer
+	params ProcessorParams
+
+	mu    sync.Mutex
+	spans map[string]trace.Span // keyed by trace or span ID
+}
+
+type ProcessorParams struct {
+	// Optional tracer provider.
+	// Default: otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+
+	// Whether to record potentially sensitive data, such as tool arguments
+	// and results, as span attributes.
+	IncludeSensitiveData bool
+}
+
+// NewProcessor returns a new Processor.
+//
+// Register it with tracing.AddTraceProcessor to export spans alongside the
+// default OpenAI exporter, or with tracing.SetTraceProcessors to replace it.
+func NewProcessor(params ProcessorParams) *Processor {
+	tp := params.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Processor{
+		tracer: tp.Tracer(ScopeName),
+		params: params,
+		spans:  make(map[string]trace.Span),
+	}
+}
+
+func (p *Processor) OnTraceStart(ctx context.Context, t tracing.Trace) error {
+	_, span := p.tracer.Start(ctx, t.Name(), trace.WithAttributes(
+		attribute.String("openai.agents.trace_id", t.TraceID()),
+		attribute.String("openai.agents.workflow_name", t.Name()),
+	))
+	p.mu.Lock()
+	p.spans[t.TraceID()] = span
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Processor) OnTraceEnd(_ context.Context, t tracing.Trace) error {
+	if span := p.pop(t.TraceID()); span != nil {
+		span.End()
+	}
+	return nil
+}
+
+func (p *Processor) OnSpanStart(ctx context.Context, s tracing.Span) error {
+	p.mu.Lock()
+	parent, ok := p.spans[s.ParentID()]
+	if !ok || s.ParentID() == "" {
+		parent = p.spans[s.TraceID()]
+	}
+	p.mu.Unlock()
+
+	if parent != nil {
+		ctx = trace.ContextWithSpan(ctx, parent)
+	}
+
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(spanKind(s.SpanData())),
+		trace.WithAttributes(
+			attribute.String("openai.agents.span_id", s.SpanID()),
+			attribute.String("openai.agents.span_type", s.SpanData().Type()),
+		),
+	}
+	if startedAt := s.StartedAt(); !startedAt.IsZero() {
+		opts = append(opts, trace.WithTimestamp(startedAt))
+	}
+	_, span := p.tracer.Start(ctx, spanName(s.SpanData()), opts...)
+
+	p.mu.Lock()
+	p.spans[s.SpanID()] = span
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Processor) OnSpanEnd(_ context.Context, s tracing.Span) error {
+	span := p.pop(s.SpanID())
+	if span == nil {
+		return nil
+	}
+
+	// Span data is usually completed while the span is running, so names and
+	// attributes are only final at this point.
+	span.SetName(spanName(s.SpanData()))
+	span.SetAttributes(p.attributes(s.SpanData())...)
+	if spanErr := s.Error(); spanErr != nil {
+		span.SetStatus(codes.Error, spanErr.Message)
+		span.SetAttributes(attribute.String("error.type", "agents_error"))
+	}
+
+	var opts []trace.SpanEndOption
+	if endedAt := s.EndedAt(); !endedAt.IsZero() {
+		opts = append(opts, trace.WithTimestamp(endedAt))
+	}
+	span.End(opts...)
+	return nil
+}
+
+// Shutdown ends all spans which are still open.
+func (p *Processor) Shutdown(context.Context) error {
+	p.mu.Lock()
+	spans := p.spans
+	p.spans = make(map[string]trace.Span)
+	p.mu.Unlock()
+
+	for _, span := range spans {
+		span.End()
+	}
+	return nil
+}
+
+// ForceFlush does nothing: flushing is delegated to the OpenTelemetry
+// TracerProvider.
+func (p *Processor) ForceFlush(context.Context) error { return nil }
+
+func (p *Processor) pop(id string) trace.Span {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	span := p.spans[id]
+	delete(p.spans, id)
+	return span
+}
+
+func spanKind(data tracing.SpanData) trace.SpanKind {
+	switch data.(type) {
+	case *tracing.GenerationSpanData, *tracing.ResponseSpanData,
+		*tracing.TranscriptionSpanData, *tracing.SpeechSpanData, *tracing.MCPListToolsSpanData:
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+func spanName(data tracing.SpanData) string {
+	switch d := data.(type) {
+	case *tracing.AgentSpanData:
+		return "invoke_agent " + d.Name
+	case *tracing.FunctionSpanData:
+		return "execute_tool " + d.Name
+	case *tracing.GenerationSpanData:
+		if d.Model != "" {
+			return "chat " + d.Model
+		}
+		return "chat"
+	case *tracing.ResponseSpanData:
+		if d.Response != nil && d.Response.Model != "" {
+			return "chat " + d.Response.Model
+		}
+		return "chat"
+	case *tracing.HandoffSpanData:
+		return fmt.Sprintf("handoff %s -> %s", d.FromAgent, d.ToAgent)
+	case *tracing.CustomSpanData:
+		return d.Name
+	case *tracing.GuardrailSpanData:
+		return "guardrail " + d.Name
+	default:
+		return data.Type()
+	}
+}
+
+func (p *Processor) attributes(data tracing.SpanData) []attribute.KeyValue {
+	switch d := data.(type) {
+	case *tracing.AgentSpanData:
+		attrs := []attribute.KeyValue{
+			attribute.String("gen_ai.operation.name", "invoke_agent"),
+			attribute.String("gen_ai.agent.name", d.Name),
+			attribute.StringSlice("openai.agents.agent.handoffs", d.Handoffs),
+			attribute.StringSlice("openai.agents.agent.tools", d.Tools),
+		}
+		if d.OutputType != "" {
+			attrs = append(attrs, attribute.String("gen_ai.output.type", d.OutputType))
+		}
+		return attrs
+	case *tracing.FunctionSpanData:
+		attrs := []attribute.KeyValue{
+			attribute.String("gen_ai.operation.name", "execute_tool"),
+			attribute.String("gen_ai.tool.name", d.Name),
+		}
+		if p.params.IncludeSensitiveData {
+			if d.Input != "" {
+				attrs = append(attrs, attribute.String("gen_ai.tool.call.arguments", d.Input))
+			}
+			if d.Output != nil {
+				attrs = append(attrs, attribute.String("gen_ai.tool.call.result", fmt.Sprintf("%+v", d.Output)))
+			}
+		}
+		return attrs
+	case *tracing.GenerationSpanData:
+		attrs := []attribute.KeyValue{
+			attribute.String("gen_ai.operation.name", "chat"),
+			attribute.String("gen_ai.system", "openai"),
+		}
+		if d.Model != "" {
+			attrs = append(attrs, attribute.String("gen_ai.request.model", d.Model))
+		}
+		if v, ok := toInt64(d.Usage["input_tokens"]); ok {
+			attrs = append(attrs, attribute.Int64("gen_ai.usage.input_tokens", v))
+		}
+		if v, ok := toInt64(d.Usage["output_tokens"]); ok {
+			attrs = append(attrs, attribute.Int64("gen_ai.usage.output_tokens", v))
+		}
+		if v, ok := toInt64(d.Usage["cached_input_tokens"]); ok {
+			attrs = append(attrs, attribute.Int64("gen_ai.usage.cached_input_tokens", v))
+		}
+		if v, ok := d.Usage["estimated_cost_usd"].(float64); ok {
+			attrs = append(attrs, attribute.Float64("openai.agents.usage.estimated_cost_usd", v))
+		}
+		return attrs
+	case *tracing.ResponseSpanData:
+		attrs := []attribute.KeyValue{
+			attribute.String("gen_ai.operation.name", "chat"),
+			attribute.String("gen_ai.system", "openai"),
+		}
+		if r := d.Response; r != nil {
+			attrs = append(attrs,
+				attribute.String("gen_ai.response.id", r.ID),
+				attribute.String("gen_ai.response.model", r.Model),
+				attribute.Int64("gen_ai.usage.input_tokens", r.Usage.InputTokens),
+				attribute.Int64("gen_ai.usage.output_tokens", r.Usage.OutputTokens),
+				attribute.Int64("gen_ai.usage.cached_input_tokens", r.Usage.InputTokensDetails.CachedTokens),
+			)
+			u := &usage.Usage{
+				InputTokens:        uint64(r.Usage.InputTokens),
+				InputTokensDetails: r.Usage.InputTokensDetails,
+				OutputTokens:       uint64(r.Usage.OutputTokens),
+			}
+			if cost, ok := usage.EstimateCost(r.Model, u); ok {
+				attrs = append(attrs, attribute.Float64("openai.agents.usage.estimated_cost_usd", cost))
+			}
+		}
+		return attrs
+	case *tracing.HandoffSpanData:
+		return []attribute.KeyValue{
+			attribute.String("openai.agents.handoff.from_agent", d.FromAgent),
+			attribute.String("openai.agents.handoff.to_agent", d.ToAgent),
+		}
+	case *tracing.GuardrailSpanData:
+		return []attribute.KeyValue{
+			attribute.String("openai.agents.guardrail.name", d.Name),
+			attribute.Bool("openai.agents.guardrail.triggered", d.Triggered),
+		}
+	case *tracing.MCPListToolsSpanData:
+		return []attribute.KeyValue{
+			attribute.String("openai.agents.mcp.server", d.Server),
+			attribute.StringSlice("openai.agents.mcp.tools", d.Result),
+		}
+	case *tracing.TranscriptionSpanData:
+		return []attribute.KeyValue{attribute.String("gen_ai.request.model", d.Model)}
+	case *tracing.SpeechSpanData:
+		return []attribute.KeyValue{attribute.String("gen_ai.request.model", d.Model)}
+	default:
+		return nil
+	}
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint64:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
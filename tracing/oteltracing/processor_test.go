@@ -0,0 +1,96 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oteltracing
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProcessor(t *testing.T) {
+	ctx := t.Context()
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewProcessor(ProcessorParams{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+	})
+
+	tr := tracing.NewTraceImpl("workflow", "", "", nil, processor)
+	require.NoError(t, tr.Start(ctx, false))
+
+	agentSpan := tracing.NewSpanImpl(tr.TraceID(), "", "", processor, &tracing.AgentSpanData{Name: "assistant"})
+	require.NoError(t, agentSpan.Start(ctx, false))
+
+	generationSpan := tracing.NewSpanImpl(tr.TraceID(), "", agentSpan.SpanID(), processor, &tracing.GenerationSpanData{})
+	require.NoError(t, generationSpan.Start(ctx, false))
+	generationData := generationSpan.SpanData().(*tracing.GenerationSpanData)
+	generationData.Model = "gpt-4o"
+	generationData.Usage = map[string]any{
+		"input_tokens":        uint64(10),
+		"output_tokens":       uint64(5),
+		"cached_input_tokens": int64(4),
+		"estimated_cost_usd":  0.0001,
+	}
+	require.NoError(t, generationSpan.Finish(ctx, false))
+
+	toolSpan := tracing.NewSpanImpl(tr.TraceID(), "", agentSpan.SpanID(), processor, &tracing.FunctionSpanData{Name: "search"})
+	require.NoError(t, toolSpan.Start(ctx, false))
+	toolSpan.SetError(tracing.SpanError{Message: "boom"})
+	require.NoError(t, toolSpan.Finish(ctx, false))
+
+	require.NoError(t, agentSpan.Finish(ctx, false))
+	require.NoError(t, tr.Finish(ctx, false))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 4)
+	byName := make(map[string]sdktrace.ReadOnlySpan, len(ended))
+	for _, s := range ended {
+		byName[s.Name()] = s
+	}
+
+	root := byName["workflow"]
+	require.NotNil(t, root)
+	agent := byName["invoke_agent assistant"]
+	require.NotNil(t, agent)
+	assert.Equal(t, root.SpanContext().SpanID(), agent.Parent().SpanID())
+	assert.Contains(t, agent.Attributes(), attribute.String("gen_ai.agent.name", "assistant"))
+
+	generation := byName["chat gpt-4o"]
+	require.NotNil(t, generation)
+	assert.Equal(t, agent.SpanContext().SpanID(), generation.Parent().SpanID())
+	assert.Contains(t, generation.Attributes(), attribute.String("gen_ai.request.model", "gpt-4o"))
+	assert.Contains(t, generation.Attributes(), attribute.Int64("gen_ai.usage.input_tokens", 10))
+	assert.Contains(t, generation.Attributes(), attribute.Int64("gen_ai.usage.cached_input_tokens", 4))
+	assert.Contains(t, generation.Attributes(), attribute.Float64("openai.agents.usage.estimated_cost_usd", 0.0001))
+	assert.Contains(t, generation.Attributes(), attribute.Int64("gen_ai.usage.output_tokens", 5))
+
+	tool := byName["execute_tool search"]
+	require.NotNil(t, tool)
+	assert.Equal(t, codes.Error, tool.Status().Code)
+	assert.Equal(t, "boom", tool.Status().Description)
+	for _, attr := range tool.Attributes() {
+		assert.NotEqual(t, attribute.Key("gen_ai.tool.call.arguments"), attr.Key)
+	}
+
+	for _, s := range ended {
+		assert.Equal(t, root.SpanContext().TraceID(), s.SpanContext().TraceID())
+	}
+}
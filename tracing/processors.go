@@ -60,6 +60,7 @@ type BackendSpanExporter struct {
 	BaseDelay    time.Duration
 	MaxDelay     time.Duration
 	client       *http.Client
+	redactor     atomic.Pointer[Redactor]
 }
 
 type BackendSpanExporterParams struct {
@@ -86,6 +87,8 @@ type BackendSpanExporterParams struct {
 	MaxDelay param.Opt[time.Duration]
 	// Optional custom http.Client.
 	HTTPClient *http.Client
+	// Optional Redactor applied to traces and spans before export.
+	Redactor Redactor
 }
 
 func NewBackendSpanExporter(params BackendSpanExporterParams) *BackendSpanExporter {
@@ -101,9 +104,27 @@ func NewBackendSpanExporter(params BackendSpanExporterParams) *BackendSpanExport
 	if params.APIKey != "" {
 		b.apiKey.Store(&params.APIKey)
 	}
+	b.SetRedactor(params.Redactor)
 	return b
 }
 
+// SetRedactor sets the Redactor applied to traces and spans before export.
+// A nil value disables redaction.
+func (b *BackendSpanExporter) SetRedactor(redactor Redactor) {
+	if redactor == nil {
+		b.redactor.Store(nil)
+		return
+	}
+	b.redactor.Store(&redactor)
+}
+
+func (b *BackendSpanExporter) Redactor() Redactor {
+	if v := b.redactor.Load(); v != nil {
+		return *v
+	}
+	return nil
+}
+
 // SetAPIKey sets the OpenAI API key for the exporter.
 func (b *BackendSpanExporter) SetAPIKey(apiKey string) {
 	b.apiKey.Store(&apiKey)
@@ -140,17 +161,21 @@ func (b *BackendSpanExporter) Export(ctx context.Context, items []any) error {
 		return nil
 	}
 
-	data := make([]map[string]any, len(items))
-	for i, item := range items {
-		switch v := item.(type) {
-		case Trace:
-			data[i] = v.Export()
-		case Span:
-			data[i] = v.Export()
+	redactor := b.Redactor()
+	data := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		switch item.(type) {
+		case Trace, Span:
+			if exported, ok := RedactExport(item, redactor); ok {
+				data = append(data, exported)
+			}
 		default:
 			return fmt.Errorf("BackendSpanExporter: unexpected item type %T", item)
 		}
 	}
+	if len(data) == 0 {
+		return nil
+	}
 
 	payload := map[string]any{
 		"data": data,
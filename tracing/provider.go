@@ -24,6 +24,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -173,9 +174,18 @@ type TraceProvider interface {
 	Shutdown(context.Context)
 }
 
+// IDGenerator generates the IDs of traces, spans and groups.
+type IDGenerator interface {
+	GenTraceID() string
+	GenSpanID() string
+	GenGroupID() string
+}
+
 type DefaultTraceProvider struct {
 	multiProcessor *SynchronousMultiTracingProcessor
 	disabled       bool
+	idGenerator    IDGenerator
+	clock          func() time.Time
 }
 
 func NewDefaultTraceProvider() *DefaultTraceProvider {
@@ -214,20 +224,42 @@ func (p *DefaultTraceProvider) SetDisabled(disabled bool) {
 	p.disabled = disabled
 }
 
+// SetIDGenerator sets the generator of the IDs of traces, spans and groups,
+// e.g. to follow an organization-wide scheme, or to get deterministic IDs
+// in tests. Random IDs are generated when nil, the default.
+func (p *DefaultTraceProvider) SetIDGenerator(generator IDGenerator) {
+	p.idGenerator = generator
+}
+
+// SetClock sets the function returning the time at which the spans start
+// and finish. time.Now is used when nil, the default.
+func (p *DefaultTraceProvider) SetClock(clock func() time.Time) {
+	p.clock = clock
+}
+
 // GenTraceID generates a new trace ID.
 func (p *DefaultTraceProvider) GenTraceID() string {
+	if p.idGenerator != nil {
+		return p.idGenerator.GenTraceID()
+	}
 	u := uuid.New()
 	return "trace_" + hex.EncodeToString(u[:])
 }
 
 // GenSpanID generates a new span ID.
 func (p *DefaultTraceProvider) GenSpanID() string {
+	if p.idGenerator != nil {
+		return p.idGenerator.GenSpanID()
+	}
 	u := uuid.New()
 	return "span_" + hex.EncodeToString(u[:])[:24]
 }
 
 // GenGroupID generates a new group ID.
 func (p *DefaultTraceProvider) GenGroupID() string {
+	if p.idGenerator != nil {
+		return p.idGenerator.GenGroupID()
+	}
 	u := uuid.New()
 	return "group_" + hex.EncodeToString(u[:])[:24]
 }
@@ -318,7 +350,9 @@ func (p *DefaultTraceProvider) CreateSpan(
 		spanID = p.GenSpanID()
 	}
 
-	return NewSpanImpl(traceID, spanID, parentID, p.multiProcessor, spanData)
+	span := NewSpanImpl(traceID, spanID, parentID, p.multiProcessor, spanData)
+	span.clock = p.clock
+	return span
 }
 
 func (p *DefaultTraceProvider) Shutdown(ctx context.Context) {
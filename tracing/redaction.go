@@ -0,0 +1,212 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultRedactionReplacement is the value used by FieldRedactor to mask data
+// when no Replacement is provided.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// Redactor masks sensitive data in exported traces and spans.
+//
+// Redact receives the JSON-compatible representation of a Trace or Span (the
+// result of its Export method, normalized through a JSON round trip, so that
+// it only contains maps, slices, strings, numbers, booleans and nil values)
+// and returns the data to export. It is free to modify its argument.
+type Redactor interface {
+	Redact(data map[string]any) map[string]any
+}
+
+// RedactorFunc adapts a function to the Redactor interface.
+type RedactorFunc func(data map[string]any) map[string]any
+
+func (f RedactorFunc) Redact(data map[string]any) map[string]any { return f(data) }
+
+// FieldRedactor is a Redactor masking values by field path and by pattern.
+type FieldRedactor struct {
+	// Dot-separated paths of the values to mask, relative to the exported
+	// item, for example "span_data.input" or "span_data.output.*.content".
+	// A "*" segment matches any map key or slice element.
+	// Whole values are replaced, whatever their type.
+	Paths []string
+
+	// Substrings of any string value matching one of these patterns are
+	// replaced, for example to mask e-mail addresses or API keys.
+	Patterns []*regexp.Regexp
+
+	// Optional replacement for masked data.
+	// Default: DefaultRedactionReplacement.
+	Replacement string
+}
+
+func (r FieldRedactor) Redact(data map[string]any) map[string]any {
+	replacement := cmp.Or(r.Replacement, DefaultRedactionReplacement)
+	for _, path := range r.Paths {
+		redactPath(data, strings.Split(path, "."), replacement)
+	}
+	if len(r.Patterns) > 0 {
+		data, _ = redactPatterns(data, r.Patterns, replacement).(map[string]any)
+	}
+	return data
+}
+
+func redactPath(value any, path []string, replacement string) {
+	if len(path) == 0 {
+		return
+	}
+	key, rest := path[0], path[1:]
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if key != "*" && key != k {
+				continue
+			}
+			if len(rest) == 0 {
+				if child != nil {
+					v[k] = replacement
+				}
+			} else {
+				redactPath(child, rest, replacement)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if key != "*" && key != strconv.Itoa(i) {
+				continue
+			}
+			if len(rest) == 0 {
+				if child != nil {
+					v[i] = replacement
+				}
+			} else {
+				redactPath(child, rest, replacement)
+			}
+		}
+	}
+}
+
+func redactPatterns(value any, patterns []*regexp.Regexp, replacement string) any {
+	switch v := value.(type) {
+	case string:
+		for _, pattern := range patterns {
+			v = pattern.ReplaceAllLiteralString(v, replacement)
+		}
+		return v
+	case map[string]any:
+		for k, child := range v {
+			v[k] = redactPatterns(child, patterns, replacement)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redactPatterns(child, patterns, replacement)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// RedactExport returns the redacted export data of item, which must be a
+// Trace or a Span, and whether there is data to export: items without data,
+// or whose data cannot be redacted, must be skipped rather than exported. A
+// nil redactor returns the data unchanged.
+func RedactExport(item any, redactor Redactor) (map[string]any, bool) {
+	var data map[string]any
+	switch v := item.(type) {
+	case Trace:
+		data = v.Export()
+	case Span:
+		data = v.Export()
+	default:
+		return nil, false
+	}
+	if redactor == nil || data == nil {
+		return data, data != nil
+	}
+
+	// Work on a JSON-normalized deep copy, so that the redactor can neither
+	// observe Go-specific types nor modify the original span data.
+	b, err := json.Marshal(data)
+	if err != nil {
+		Logger().Warn("failed to JSON-marshal data for redaction", slog.String("error", err.Error()))
+		return nil, false
+	}
+	var normalized map[string]any
+	if err = json.Unmarshal(b, &normalized); err != nil {
+		Logger().Warn("failed to JSON-unmarshal data for redaction", slog.String("error", err.Error()))
+		return nil, false
+	}
+	data = redactor.Redact(normalized)
+	return data, data != nil
+}
+
+// NewRedactingExporter returns an Exporter which redacts traces and spans
+// with redactor before passing them to exporter.
+func NewRedactingExporter(exporter Exporter, redactor Redactor) Exporter {
+	return redactingExporter{exporter: exporter, redactor: redactor}
+}
+
+type redactingExporter struct {
+	exporter Exporter
+	redactor Redactor
+}
+
+func (e redactingExporter) Export(ctx context.Context, items []any) error {
+	redacted := make([]any, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case Trace:
+			if data, ok := RedactExport(v, e.redactor); ok {
+				redacted = append(redacted, redactedTrace{Trace: v, data: data})
+			}
+		case Span:
+			if data, ok := RedactExport(v, e.redactor); ok {
+				redacted = append(redacted, redactedSpan{Span: v, data: data})
+			}
+		default:
+			redacted = append(redacted, item)
+		}
+	}
+	if len(redacted) == 0 {
+		return nil
+	}
+	return e.exporter.Export(ctx, redacted)
+}
+
+// redactedTrace is a Trace exporting its redacted data.
+type redactedTrace struct {
+	Trace
+	data map[string]any
+}
+
+func (t redactedTrace) Export() map[string]any { return t.data }
+
+// redactedSpan is a Span exporting its redacted data.
+type redactedSpan struct {
+	Span
+	data map[string]any
+}
+
+func (s redactedSpan) Export() map[string]any { return s.data }
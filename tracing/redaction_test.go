@@ -0,0 +1,140 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getGenerationSpan() *SpanImpl {
+	return NewSpanImpl("test_trace_id", "test_span_id", "", nil, &GenerationSpanData{
+		Input: []map[string]any{
+			{"role": "user", "content": "my e-mail is john@example.com"},
+		},
+		Output: []map[string]any{
+			{"role": "assistant", "content": "noted"},
+		},
+		Model: "gpt-4o",
+	})
+}
+
+func TestFieldRedactor(t *testing.T) {
+	span := getGenerationSpan()
+
+	t.Run("paths", func(t *testing.T) {
+		data, ok := RedactExport(span, FieldRedactor{
+			Paths: []string{"span_data.input", "span_data.output.*.content"},
+		})
+		require.True(t, ok)
+		spanData := data["span_data"].(map[string]any)
+		assert.Equal(t, DefaultRedactionReplacement, spanData["input"])
+		assert.Equal(t, []any{
+			map[string]any{"role": "assistant", "content": DefaultRedactionReplacement},
+		}, spanData["output"])
+		assert.Equal(t, "gpt-4o", spanData["model"])
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		data, ok := RedactExport(span, FieldRedactor{
+			Patterns:    []*regexp.Regexp{regexp.MustCompile(`[\w.]+@[\w.]+`)},
+			Replacement: "***",
+		})
+		require.True(t, ok)
+		input := data["span_data"].(map[string]any)["input"].([]any)
+		assert.Equal(t, "my e-mail is ***", input[0].(map[string]any)["content"])
+	})
+
+	t.Run("original data is untouched", func(t *testing.T) {
+		assert.Equal(t, "my e-mail is john@example.com",
+			span.SpanData().(*GenerationSpanData).Input[0]["content"])
+	})
+}
+
+type recordingExporter struct {
+	exported []map[string]any
+}
+
+func (e *recordingExporter) Export(_ context.Context, items []any) error {
+	for _, item := range items {
+		e.exported = append(e.exported, item.(interface{ Export() map[string]any }).Export())
+	}
+	return nil
+}
+
+func TestNewRedactingExporter(t *testing.T) {
+	inner := new(recordingExporter)
+	exporter := NewRedactingExporter(inner, RedactorFunc(func(data map[string]any) map[string]any {
+		delete(data, "span_data")
+		return data
+	}))
+
+	require.NoError(t, exporter.Export(t.Context(), []any{getTrace(nil), getGenerationSpan()}))
+	require.Len(t, inner.exported, 2)
+	assert.Equal(t, "trace", inner.exported[0]["object"])
+	assert.Equal(t, "trace.span", inner.exported[1]["object"])
+	assert.NotContains(t, inner.exported[1], "span_data")
+}
+
+// dropSpans is a Redactor dropping the data of the spans.
+var dropSpans = RedactorFunc(func(data map[string]any) map[string]any {
+	if data["object"] == "trace.span" {
+		return nil
+	}
+	return data
+})
+
+func TestRedactExportSkipsDroppedItems(t *testing.T) {
+	_, ok := RedactExport(getGenerationSpan(), dropSpans)
+	assert.False(t, ok)
+	_, ok = RedactExport("not a span", nil)
+	assert.False(t, ok)
+	data, ok := RedactExport(getTrace(nil), dropSpans)
+	require.True(t, ok)
+	assert.Equal(t, "trace", data["object"])
+
+	inner := new(recordingExporter)
+	exporter := NewRedactingExporter(inner, dropSpans)
+	require.NoError(t, exporter.Export(t.Context(), []any{getTrace(nil), getGenerationSpan()}))
+	require.Len(t, inner.exported, 1)
+	assert.Equal(t, "trace", inner.exported[0]["object"])
+
+	rt := &testingTransport{}
+	backend := NewBackendSpanExporter(BackendSpanExporterParams{
+		APIKey:     "test_key",
+		HTTPClient: &http.Client{Transport: rt},
+		Redactor:   dropSpans,
+	})
+	t.Cleanup(func() { backend.Close() })
+	require.NoError(t, backend.Export(t.Context(), []any{getGenerationSpan()}))
+	assert.Empty(t, rt.requests, "nothing is left to export")
+
+	require.NoError(t, backend.Export(t.Context(), []any{getTrace(nil), getGenerationSpan()}))
+	require.Len(t, rt.requests, 1)
+	body, err := rt.requests[0].GetBody()
+	require.NoError(t, err)
+	var payload struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(body).Decode(&payload))
+	require.Len(t, payload.Data, 1)
+	assert.Equal(t, "trace", payload.Data[0]["object"])
+}
@@ -128,6 +128,8 @@ type SpanImpl struct {
 	prevContextSpan *Span
 	processor       Processor
 	spanData        SpanData
+	// clock, when set, replaces time.Now.
+	clock func() time.Time
 }
 
 func NewSpanImpl(
@@ -152,6 +154,13 @@ func NewSpanImpl(
 	}
 }
 
+func (s *SpanImpl) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
 func (s *SpanImpl) Run(ctx context.Context, fn func(context.Context, Span) error) (err error) {
 	ctx = ContextWithClonedOrNewScope(ctx)
 
@@ -175,7 +184,7 @@ func (s *SpanImpl) Start(ctx context.Context, markAsCurrent bool) error {
 		return nil
 	}
 
-	s.startedAt = time.Now()
+	s.startedAt = s.now()
 	err := s.processor.OnSpanStart(ctx, s)
 	if err != nil {
 		return err
@@ -194,7 +203,7 @@ func (s *SpanImpl) Finish(ctx context.Context, resetCurrent bool) error {
 		return nil
 	}
 
-	s.endedAt = time.Now()
+	s.endedAt = s.now()
 	err := s.processor.OnSpanEnd(ctx, s)
 	if err != nil {
 		return err
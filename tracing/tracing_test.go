@@ -3,6 +3,7 @@ package tracing_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -577,3 +578,37 @@ func TestNoopParentIsNoopChild(t *testing.T) {
 
 	require.Nil(t, span2.Export())
 }
+
+type sequentialIDGenerator struct{ n int }
+
+func (g *sequentialIDGenerator) next(prefix string) string {
+	g.n++
+	return fmt.Sprintf("%s_%d", prefix, g.n)
+}
+
+func (g *sequentialIDGenerator) GenTraceID() string { return g.next("trace") }
+func (g *sequentialIDGenerator) GenSpanID() string  { return g.next("span") }
+func (g *sequentialIDGenerator) GenGroupID() string { return g.next("group") }
+
+func TestTraceProviderIDGeneratorAndClock(t *testing.T) {
+	ctx := t.Context()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	provider := tracing.NewDefaultTraceProvider()
+	provider.SetIDGenerator(&sequentialIDGenerator{})
+	provider.SetClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+
+	assert.Equal(t, "group_1", provider.GenGroupID())
+	trace := provider.CreateTrace("test", "", "", nil, false)
+	assert.Equal(t, "trace_2", trace.TraceID())
+
+	span := provider.CreateSpan(ctx, &tracing.CustomSpanData{Name: "span"}, "", trace, false)
+	assert.Equal(t, "span_3", span.SpanID())
+	require.NoError(t, span.Start(ctx, false))
+	require.NoError(t, span.Finish(ctx, false))
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC), span.StartedAt())
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 7, 0, time.UTC), span.EndedAt())
+}
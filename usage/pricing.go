@@ -0,0 +1,112 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ModelPricing is the price of a model, in USD per million tokens.
+type ModelPricing struct {
+	InputPerMillion float64
+	// Price of cached input tokens. When zero, InputPerMillion is used.
+	CachedInputPerMillion float64
+	OutputPerMillion      float64
+}
+
+var (
+	pricingMu sync.RWMutex
+	// Indicative list prices, which may be outdated: use SetModelPricing to
+	// provide the prices that apply to you.
+	pricing = map[string]ModelPricing{
+		"gpt-4o":       {InputPerMillion: 2.5, CachedInputPerMillion: 1.25, OutputPerMillion: 10},
+		"gpt-4o-mini":  {InputPerMillion: 0.15, CachedInputPerMillion: 0.075, OutputPerMillion: 0.6},
+		"gpt-4.1":      {InputPerMillion: 2, CachedInputPerMillion: 0.5, OutputPerMillion: 8},
+		"gpt-4.1-mini": {InputPerMillion: 0.4, CachedInputPerMillion: 0.1, OutputPerMillion: 1.6},
+		"gpt-4.1-nano": {InputPerMillion: 0.1, CachedInputPerMillion: 0.025, OutputPerMillion: 0.4},
+		"gpt-5":        {InputPerMillion: 1.25, CachedInputPerMillion: 0.125, OutputPerMillion: 10},
+		"gpt-5-mini":   {InputPerMillion: 0.25, CachedInputPerMillion: 0.025, OutputPerMillion: 2},
+		"gpt-5-nano":   {InputPerMillion: 0.05, CachedInputPerMillion: 0.005, OutputPerMillion: 0.4},
+		"o3":           {InputPerMillion: 2, CachedInputPerMillion: 0.5, OutputPerMillion: 8},
+		"o3-mini":      {InputPerMillion: 1.1, CachedInputPerMillion: 0.55, OutputPerMillion: 4.4},
+		"o4-mini":      {InputPerMillion: 1.1, CachedInputPerMillion: 0.275, OutputPerMillion: 4.4},
+	}
+)
+
+// SetModelPricing sets the pricing used to estimate the cost of the given model.
+// It also applies to the dated snapshots of the model (e.g.
+// "gpt-4o-2024-08-06" or "gpt-4-0613" for "gpt-4o" and "gpt-4"), unless they
+// have their own pricing. Other variants, such as "o3-pro" for "o3", need
+// their own pricing.
+func SetModelPricing(model string, p ModelPricing) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	pricing[model] = p
+}
+
+// snapshotSuffix matches the date suffix of model snapshots, such as
+// "-2024-08-06", "-20240806" or "-0613".
+var snapshotSuffix = regexp.MustCompile(`-(\d{4}-\d{2}-\d{2}|\d{8}|\d{4})$`)
+
+// providerPrefixes are the prefixes of the model names routed through a
+// provider, such as "openai/gpt-4o" on OpenRouter, stripped before looking
+// the pricing up.
+var providerPrefixes = []string{"openrouter/", "openai/"}
+
+// GetModelPricing returns the pricing of the given model, if known. Model
+// names may have a known provider prefix, e.g. "openai/gpt-4o".
+func GetModelPricing(model string) (ModelPricing, bool) {
+	pricingMu.RLock()
+	defer pricingMu.RUnlock()
+
+	if p, ok := lookupModelPricing(model); ok {
+		return p, true
+	}
+	for _, prefix := range providerPrefixes {
+		model = strings.TrimPrefix(model, prefix)
+	}
+	return lookupModelPricing(model)
+}
+
+func lookupModelPricing(model string) (ModelPricing, bool) {
+	if p, ok := pricing[model]; ok {
+		return p, true
+	}
+	if loc := snapshotSuffix.FindStringIndex(model); loc != nil {
+		p, ok := pricing[model[:loc[0]]]
+		return p, ok
+	}
+	return ModelPricing{}, false
+}
+
+// EstimateCost returns the estimated cost in USD of the given usage of a
+// model, and whether the model pricing is known.
+func EstimateCost(model string, u *Usage) (float64, bool) {
+	p, ok := GetModelPricing(model)
+	if !ok || u == nil {
+		return 0, ok
+	}
+	cached := min(uint64(max(u.InputTokensDetails.CachedTokens, 0)), u.InputTokens)
+	cachedPrice := p.CachedInputPerMillion
+	if cachedPrice == 0 {
+		cachedPrice = p.InputPerMillion
+	}
+	cost := float64(u.InputTokens-cached)*p.InputPerMillion +
+		float64(cached)*cachedPrice +
+		float64(u.OutputTokens)*p.OutputPerMillion
+	return cost / 1_000_000, true
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"testing"
+
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestModelPricing sets the pricing of model until the end of the test.
+func setTestModelPricing(t *testing.T, model string, p ModelPricing) {
+	t.Helper()
+	pricingMu.RLock()
+	previous, ok := pricing[model]
+	pricingMu.RUnlock()
+	SetModelPricing(model, p)
+	t.Cleanup(func() {
+		pricingMu.Lock()
+		defer pricingMu.Unlock()
+		if ok {
+			pricing[model] = previous
+		} else {
+			delete(pricing, model)
+		}
+	})
+}
+
+func TestEstimateCost(t *testing.T) {
+	setTestModelPricing(t, "test-model", ModelPricing{
+		InputPerMillion:       2,
+		CachedInputPerMillion: 1,
+		OutputPerMillion:      10,
+	})
+	setTestModelPricing(t, "test-model-large", ModelPricing{InputPerMillion: 4})
+
+	u := &Usage{
+		InputTokens:        1_000_000,
+		InputTokensDetails: responses.ResponseUsageInputTokensDetails{CachedTokens: 400_000},
+		OutputTokens:       100_000,
+	}
+
+	t.Run("exact model", func(t *testing.T) {
+		cost, ok := EstimateCost("test-model", u)
+		require.True(t, ok)
+		assert.InDelta(t, 0.6*2+0.4*1+0.1*10, cost, 1e-9)
+	})
+
+	t.Run("dated snapshot", func(t *testing.T) {
+		for _, model := range []string{"test-model-2025-01-01", "test-model-20250101", "test-model-0613"} {
+			cost, ok := EstimateCost(model, u)
+			require.True(t, ok, model)
+			assert.InDelta(t, 2.6, cost, 1e-9, model)
+		}
+
+		// Without a cached price, cached tokens are billed as input tokens.
+		cost, ok := EstimateCost("test-model-large-2025-01-01", u)
+		require.True(t, ok)
+		assert.InDelta(t, 4.0, cost, 1e-9)
+	})
+
+	t.Run("provider prefix", func(t *testing.T) {
+		for _, model := range []string{"openai/test-model", "openrouter/openai/test-model", "openai/test-model-2025-01-01"} {
+			cost, ok := EstimateCost(model, u)
+			require.True(t, ok, model)
+			assert.InDelta(t, 2.6, cost, 1e-9, model)
+		}
+		_, ok := EstimateCost("anthropic/test-model", u)
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		_, ok := EstimateCost("unknown", u)
+		assert.False(t, ok)
+		_, ok = EstimateCost("test-modelx", u)
+		assert.False(t, ok)
+		_, ok = EstimateCost("test-model-pro", u)
+		assert.False(t, ok)
+		_, ok = EstimateCost("test-model-pro-2025-01-01", u)
+		assert.False(t, ok)
+	})
+}
+
+func TestGetModelPricingVariants(t *testing.T) {
+	o3, ok := GetModelPricing("o3")
+	require.True(t, ok)
+	snapshot, ok := GetModelPricing("o3-2025-04-16")
+	require.True(t, ok)
+	assert.Equal(t, o3, snapshot)
+
+	// Variants are not priced as their base model.
+	for _, model := range []string{"o3-pro", "gpt-5-pro", "gpt-5-pro-2025-10-06"} {
+		_, ok := GetModelPricing(model)
+		assert.False(t, ok, model)
+	}
+}
@@ -0,0 +1,236 @@
+package workflowrunner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ConcurrencyLimits bounds the concurrent runs of a RunnerService, so that a
+// burst of requests cannot exhaust the quotas of the model providers. Zero
+// values mean unlimited.
+type ConcurrencyLimits struct {
+	MaxConcurrentRuns int
+	// MaxConcurrentRunsPerWorkflow applies to the workflows without an entry
+	// in WorkflowLimits, by workflow name.
+	MaxConcurrentRunsPerWorkflow int
+	WorkflowLimits               map[string]int
+	// MaxQueuedRuns bounds the runs waiting for a slot. Runs beyond the
+	// limits are rejected right away when zero.
+	MaxQueuedRuns int
+	// MaxQueueWait bounds the time a run waits for a slot.
+	MaxQueueWait time.Duration
+}
+
+// AdmissionRejection is the reason of an AdmissionError.
+type AdmissionRejection string
+
+const (
+	// AdmissionQueueFull rejects runs when the queue holds MaxQueuedRuns runs.
+	AdmissionQueueFull AdmissionRejection = "queue_full"
+	// AdmissionQueueTimeout rejects runs which waited MaxQueueWait.
+	AdmissionQueueTimeout AdmissionRejection = "queue_timeout"
+)
+
+// AdmissionError is returned when a run is rejected by the concurrency
+// limits of the RunnerService: by Execute when the queue is full, and as the
+// error of the run task when it waited too long.
+type AdmissionError struct {
+	WorkflowName string
+	Reason       AdmissionRejection
+	// Running is the number of runs of the workflow when it was rejected.
+	Running int
+}
+
+func (e *AdmissionError) Error() string {
+	switch e.Reason {
+	case AdmissionQueueTimeout:
+		return fmt.Sprintf("workflow %q run rejected: timed out waiting for a concurrency slot (%d running)", e.WorkflowName, e.Running)
+	default:
+		return fmt.Sprintf("workflow %q run rejected: concurrency limit reached and queue full (%d running)", e.WorkflowName, e.Running)
+	}
+}
+
+// AdmissionStats reports the runs of an AdmissionController.
+type AdmissionStats struct {
+	Running int
+	Queued  int
+	// RunningByWorkflow counts the running runs by workflow name.
+	RunningByWorkflow map[string]int
+}
+
+// AdmissionController admits runs within ConcurrencyLimits. Runs beyond the
+// limits wait in a FIFO queue; a queued run whose workflow is at its own
+// limit does not hold back the runs of other workflows.
+type AdmissionController struct {
+	limits ConcurrencyLimits
+
+	mu      sync.Mutex
+	running int
+	// runningByWorkflow counts the admitted runs by workflow name.
+	runningByWorkflow map[string]int
+	queue             []*admissionTicket
+}
+
+// NewAdmissionController returns a controller enforcing the given limits.
+func NewAdmissionController(limits ConcurrencyLimits) *AdmissionController {
+	return &AdmissionController{limits: limits, runningByWorkflow: make(map[string]int)}
+}
+
+// admissionTicket is the slot of a run, admitted or queued.
+type admissionTicket struct {
+	c        *AdmissionController
+	workflow string
+	// admitted is closed once the run holds a slot.
+	admitted chan struct{}
+	released bool
+}
+
+// Acquire waits for a slot for a run of the workflow, and returns the
+// function releasing it. Unlike the runs of a RunnerService, which are
+// queued asynchronously, it blocks while queued.
+func (c *AdmissionController) Acquire(ctx context.Context, workflowName string) (release func(), err error) {
+	ticket, err := c.enter(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if err := ticket.wait(ctx); err != nil {
+		return nil, err
+	}
+	return ticket.release, nil
+}
+
+// Stats returns the current runs.
+func (c *AdmissionController) Stats() AdmissionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := AdmissionStats{Running: c.running, Queued: len(c.queue), RunningByWorkflow: make(map[string]int, len(c.runningByWorkflow))}
+	for name, n := range c.runningByWorkflow {
+		stats.RunningByWorkflow[name] = n
+	}
+	return stats
+}
+
+func (c *AdmissionController) workflowLimit(workflowName string) int {
+	if limit, ok := c.limits.WorkflowLimits[workflowName]; ok {
+		return limit
+	}
+	return c.limits.MaxConcurrentRunsPerWorkflow
+}
+
+// fits reports whether a run of the workflow can start, with c.mu held.
+func (c *AdmissionController) fits(workflowName string) bool {
+	if c.limits.MaxConcurrentRuns > 0 && c.running >= c.limits.MaxConcurrentRuns {
+		return false
+	}
+	limit := c.workflowLimit(workflowName)
+	return limit <= 0 || c.runningByWorkflow[workflowName] < limit
+}
+
+// enter admits a run or queues it, failing with an AdmissionError when the
+// queue is full.
+func (c *AdmissionController) enter(workflowName string) (*admissionTicket, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ticket := &admissionTicket{c: c, workflow: workflowName, admitted: make(chan struct{})}
+	// Queued runs are started as soon as they fit, so a run which fits does
+	// not overtake them.
+	if c.fits(workflowName) {
+		c.start(ticket)
+		return ticket, nil
+	}
+	if len(c.queue) >= c.limits.MaxQueuedRuns {
+		return nil, &AdmissionError{WorkflowName: workflowName, Reason: AdmissionQueueFull, Running: c.runningByWorkflow[workflowName]}
+	}
+	c.queue = append(c.queue, ticket)
+	return ticket, nil
+}
+
+// start gives a slot to the ticket, with c.mu held.
+func (c *AdmissionController) start(ticket *admissionTicket) {
+	c.running++
+	c.runningByWorkflow[ticket.workflow]++
+	close(ticket.admitted)
+}
+
+// dequeue starts the queued runs which fit, in order, with c.mu held.
+func (c *AdmissionController) dequeue() {
+	queue := c.queue[:0]
+	for _, ticket := range c.queue {
+		if c.fits(ticket.workflow) {
+			c.start(ticket)
+		} else {
+			queue = append(queue, ticket)
+		}
+	}
+	clear(c.queue[len(queue):])
+	c.queue = queue
+}
+
+// isAdmitted reports whether the run holds a slot.
+func (t *admissionTicket) isAdmitted() bool {
+	select {
+	case <-t.admitted:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait blocks until the run holds a slot. Runs leaving the queue because ctx
+// is done or MaxQueueWait elapsed give their place up.
+func (t *admissionTicket) wait(ctx context.Context) error {
+	var timeout <-chan time.Time
+	if t.c.limits.MaxQueueWait > 0 {
+		timer := time.NewTimer(t.c.limits.MaxQueueWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case <-t.admitted:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = &AdmissionError{WorkflowName: t.workflow, Reason: AdmissionQueueTimeout}
+	}
+
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	select {
+	case <-t.admitted:
+		// Admitted meanwhile.
+		return nil
+	default:
+	}
+	t.c.queue = slices.DeleteFunc(t.c.queue, func(queued *admissionTicket) bool { return queued == t })
+	t.released = true
+	if admissionErr, ok := err.(*AdmissionError); ok {
+		admissionErr.Running = t.c.runningByWorkflow[t.workflow]
+	}
+	return err
+}
+
+// release frees the slot of the run, or its place in the queue.
+func (t *admissionTicket) release() {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.released {
+		return
+	}
+	t.released = true
+	select {
+	case <-t.admitted:
+		c.running--
+		if c.runningByWorkflow[t.workflow]--; c.runningByWorkflow[t.workflow] <= 0 {
+			delete(c.runningByWorkflow, t.workflow)
+		}
+	default:
+		c.queue = slices.DeleteFunc(c.queue, func(queued *admissionTicket) bool { return queued == t })
+	}
+	c.dequeue()
+}
@@ -0,0 +1,191 @@
+package workflowrunner
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/nlpodyssey/openai-agents-go/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionController(t *testing.T) {
+	ctx := t.Context()
+	c := NewAdmissionController(ConcurrencyLimits{
+		MaxConcurrentRuns:            2,
+		MaxConcurrentRunsPerWorkflow: 1,
+		MaxQueuedRuns:                1,
+		MaxQueueWait:                 20 * time.Millisecond,
+	})
+	releaseA, err := c.Acquire(ctx, "a")
+	require.NoError(t, err)
+
+	// A queued run of a workflow at its limit times out.
+	var admissionErr *AdmissionError
+	_, err = c.Acquire(ctx, "a")
+	require.ErrorAs(t, err, &admissionErr)
+	assert.Equal(t, AdmissionQueueTimeout, admissionErr.Reason)
+	assert.Equal(t, 1, admissionErr.Running)
+	assert.Zero(t, c.Stats().Queued)
+
+	// Runs beyond the queue are rejected right away.
+	queued, err := c.enter("a")
+	require.NoError(t, err)
+	assert.False(t, queued.isAdmitted())
+	_, err = c.enter("a")
+	require.ErrorAs(t, err, &admissionErr)
+	assert.Equal(t, AdmissionQueueFull, admissionErr.Reason)
+
+	// The queued run does not hold back the other workflows.
+	releaseB, err := c.Acquire(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, AdmissionStats{Running: 2, Queued: 1, RunningByWorkflow: map[string]int{"a": 1, "b": 1}}, c.Stats())
+
+	releaseB()
+	releaseA()
+	assert.True(t, queued.isAdmitted())
+	queued.release()
+	assert.Equal(t, AdmissionStats{RunningByWorkflow: map[string]int{}}, c.Stats())
+}
+
+// countingSessionFactory records the sessions it creates.
+type countingSessionFactory struct {
+	factory SessionFactory
+
+	mu       sync.Mutex
+	sessions []string
+}
+
+func (f *countingSessionFactory) create(ctx context.Context, decl SessionDeclaration) (memory.Session, error) {
+	f.mu.Lock()
+	f.sessions = append(f.sessions, decl.SessionID)
+	f.mu.Unlock()
+	return f.factory(ctx, decl)
+}
+
+func (f *countingSessionFactory) created() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.sessions)
+}
+
+func TestQueuedRunsBuildOnceAdmitted(t *testing.T) {
+	ctx := t.Context()
+	model := newGatedModel()
+	service := newTestService(t, model, &recordingPublisher{})
+	sessions := &countingSessionFactory{factory: service.Builder.SessionFactory}
+	service.Builder.SessionFactory = sessions.create
+	service.Admission = NewAdmissionController(ConcurrencyLimits{MaxConcurrentRuns: 1, MaxQueuedRuns: 1})
+
+	first, err := service.Execute(ctx, testRequest("s1"))
+	require.NoError(t, err)
+	model.awaitCall(t)
+
+	// Queued runs are still validated.
+	var admissionErr *AdmissionError
+	invalid := testRequest("s0")
+	invalid.Workflow.StartingAgent = "missing"
+	_, err = service.Execute(ctx, invalid)
+	assert.ErrorContains(t, err, "missing")
+	assert.NotErrorAs(t, err, &admissionErr)
+
+	second, err := service.Execute(ctx, testRequest("s2"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, sessions.created(), "queued runs are not built")
+
+	_, err = service.Execute(ctx, testRequest("s3"))
+	require.ErrorAs(t, err, &admissionErr)
+	assert.Equal(t, AdmissionQueueFull, admissionErr.Reason)
+
+	model.release <- struct{}{}
+	require.NoError(t, first.Await().Error)
+	model.awaitCall(t)
+	model.release <- struct{}{}
+	result := second.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusCompleted, result.Value.Status)
+	assert.Equal(t, []string{"s1", "s2"}, sessions.created())
+}
+
+func TestQueuedRunTimeout(t *testing.T) {
+	ctx := t.Context()
+	model := newGatedModel()
+	service := newTestService(t, model, &recordingPublisher{})
+	sessions := &countingSessionFactory{factory: service.Builder.SessionFactory}
+	service.Builder.SessionFactory = sessions.create
+	service.Admission = NewAdmissionController(ConcurrencyLimits{
+		MaxConcurrentRuns: 1,
+		MaxQueuedRuns:     1,
+		MaxQueueWait:      20 * time.Millisecond,
+	})
+
+	first, err := service.Execute(ctx, testRequest("s1"))
+	require.NoError(t, err)
+	model.awaitCall(t)
+	second, err := service.Execute(ctx, testRequest("s2"))
+	require.NoError(t, err)
+
+	result := second.Await()
+	var admissionErr *AdmissionError
+	require.ErrorAs(t, result.Error, &admissionErr)
+	assert.Equal(t, AdmissionQueueTimeout, admissionErr.Reason)
+	assert.Equal(t, ExecutionStatusFailed, result.Value.Status)
+	assert.Equal(t, []string{"s1"}, sessions.created())
+
+	model.release <- struct{}{}
+	require.NoError(t, first.Await().Error)
+	assert.Zero(t, service.Admission.Stats().Running)
+}
+
+// rejectionRecorder records the rejected and finished runs.
+type rejectionRecorder struct {
+	metrics.NoOpRecorder
+
+	mu       sync.Mutex
+	rejected []string
+	finished []string
+}
+
+func (r *rejectionRecorder) RunRejected(workflow, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rejected = append(r.rejected, workflow+" "+reason)
+}
+
+func (r *rejectionRecorder) RunFinished(workflow, outcome string, _ uint64, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, workflow+" "+outcome)
+}
+
+func TestRejectedRunsMetrics(t *testing.T) {
+	ctx := t.Context()
+	recorder := new(rejectionRecorder)
+	metrics.SetRecorder(recorder)
+	t.Cleanup(func() { metrics.SetRecorder(nil) })
+
+	model := newGatedModel()
+	service := newTestService(t, model, &recordingPublisher{})
+	service.Admission = NewAdmissionController(ConcurrencyLimits{MaxConcurrentRuns: 1})
+	task, err := service.Execute(ctx, testRequest("s1"))
+	require.NoError(t, err)
+	model.awaitCall(t)
+	_, err = service.Execute(ctx, testRequest("s2"))
+	require.Error(t, err)
+	model.release <- struct{}{}
+	require.NoError(t, task.Await().Error)
+
+	invalid := testRequest("s3")
+	invalid.Workflow.StartingAgent = "missing"
+	_, err = service.Execute(ctx, invalid)
+	require.Error(t, err)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, []string{"wf " + metrics.RejectionQueueFull, "wf " + metrics.RejectionInvalid}, recorder.rejected)
+	assert.Equal(t, []string{"wf " + metrics.OutcomeSucceeded}, recorder.finished)
+}
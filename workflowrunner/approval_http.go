@@ -0,0 +1,142 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ApprovalHandler serves REST endpoints listing and resolving the approval
+// and input requests of the runs of a RunnerService:
+//
+//	GET  /sessions/{session_id}/approvals
+//	POST /sessions/{session_id}/approvals/{request_id}/approve
+//	POST /sessions/{session_id}/approvals/{request_id}/deny
+//	GET  /sessions/{session_id}/inputs
+//	POST /sessions/{session_id}/inputs/{request_id}
+//
+// The approval POST bodies may carry a reason, as {"reason": "..."}, and the
+// input ones carry the answer, as {"answer": ...}. Responses are the
+// execution state of the session. The handler does not authenticate the
+// approvers: wrap it in the authentication middleware of the service, and
+// mount it at RunnerService.ApprovalBaseURL, e.g. with http.StripPrefix.
+type ApprovalHandler struct {
+	Service *RunnerService
+	// OnResolved, when set, is called after each decision or answer with the
+	// updated state, e.g. to resume the run with RunnerService.Resume once
+	// nothing is pending.
+	OnResolved func(ctx context.Context, state WorkflowExecutionState)
+	mux        *http.ServeMux
+}
+
+// NewApprovalHandler returns an ApprovalHandler for the given service.
+func NewApprovalHandler(service *RunnerService) *ApprovalHandler {
+	h := &ApprovalHandler{Service: service, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /sessions/{session_id}/approvals", h.getState)
+	h.mux.HandleFunc("POST /sessions/{session_id}/approvals/{request_id}/approve", h.resolve(true))
+	h.mux.HandleFunc("POST /sessions/{session_id}/approvals/{request_id}/deny", h.resolve(false))
+	h.mux.HandleFunc("GET /sessions/{session_id}/inputs", h.getState)
+	h.mux.HandleFunc("POST /sessions/{session_id}/inputs/{request_id}", h.provideInput)
+	return h
+}
+
+func (h *ApprovalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *ApprovalHandler) getState(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("session_id")
+	state, ok, err := h.Service.GetState(r.Context(), sessionID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, ErrExecutionNotFound)
+		return
+	}
+	writeState(w, state)
+}
+
+func (h *ApprovalHandler) resolve(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+		state, err := h.Service.ResolveApproval(r.Context(), r.PathValue("session_id"), ApprovalDecisionState{
+			RequestID: r.PathValue("request_id"),
+			Approve:   approve,
+			Reason:    body.Reason,
+		})
+		if !h.writeResolution(w, err) {
+			return
+		}
+		if h.OnResolved != nil {
+			h.OnResolved(r.Context(), state)
+		}
+		writeState(w, state)
+	}
+}
+
+func (h *ApprovalHandler) provideInput(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Answer any `json:"answer"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Answer == nil {
+		writeJSONError(w, http.StatusBadRequest, errors.New("answer is required"))
+		return
+	}
+	state, err := h.Service.ProvideInput(r.Context(), r.PathValue("session_id"), InputResponseState{
+		RequestID: r.PathValue("request_id"),
+		Answer:    body.Answer,
+	})
+	if !h.writeResolution(w, err) {
+		return
+	}
+	if h.OnResolved != nil {
+		h.OnResolved(r.Context(), state)
+	}
+	writeState(w, state)
+}
+
+// writeResolution writes the error of a resolution, if any, and reports
+// whether it succeeded.
+func (h *ApprovalHandler) writeResolution(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, ErrExecutionNotFound), errors.Is(err, ErrApprovalNotFound), errors.Is(err, ErrInputRequestNotFound):
+		writeJSONError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrExecutionStateConflict):
+		writeJSONError(w, http.StatusConflict, err)
+	case err != nil:
+		writeJSONError(w, http.StatusInternalServerError, err)
+	default:
+		return true
+	}
+	return false
+}
+
+// writeState writes an execution state, without its stored request.
+func writeState(w http.ResponseWriter, state WorkflowExecutionState) {
+	state.Request = nil
+	writeJSON(w, http.StatusOK, state)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
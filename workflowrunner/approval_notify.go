@@ -0,0 +1,209 @@
+package workflowrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// ApprovalNotification is sent to the approval notifiers when a run is
+// suspended on approval requests.
+type ApprovalNotification struct {
+	SessionID    string            `json:"session_id"`
+	WorkflowName string            `json:"workflow_name"`
+	AgentName    string            `json:"agent_name"`
+	Approvals    []PendingApproval `json:"approvals"`
+	Metadata     map[string]any    `json:"metadata,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// PendingApproval is an approval request of a notification. The approve and
+// deny URLs are set when RunnerService.ApprovalBaseURL is, and point to the
+// endpoints of ApprovalHandler.
+type PendingApproval struct {
+	ApprovalRequestState
+	ApproveURL string `json:"approve_url,omitempty"`
+	DenyURL    string `json:"deny_url,omitempty"`
+}
+
+// ApprovalNotifier tells approvers that a run waits for their decisions.
+type ApprovalNotifier interface {
+	NotifyApproval(ctx context.Context, notification ApprovalNotification) error
+}
+
+// ApprovalNotifierFunc adapts a function to the ApprovalNotifier interface.
+type ApprovalNotifierFunc func(ctx context.Context, notification ApprovalNotification) error
+
+func (f ApprovalNotifierFunc) NotifyApproval(ctx context.Context, notification ApprovalNotification) error {
+	return f(ctx, notification)
+}
+
+// newApprovalNotification describes the pending approvals of a suspended run,
+// created at now.
+func newApprovalNotification(req WorkflowRequest, agentName, baseURL string, pending []ApprovalRequestState, now time.Time) ApprovalNotification {
+	notification := ApprovalNotification{
+		SessionID:    req.Session.SessionID,
+		WorkflowName: req.Workflow.Name,
+		AgentName:    agentName,
+		Approvals:    make([]PendingApproval, len(pending)),
+		Metadata:     req.Metadata,
+		CreatedAt:    now,
+	}
+	base := strings.TrimRight(baseURL, "/")
+	for i, approval := range pending {
+		notification.Approvals[i] = PendingApproval{ApprovalRequestState: approval}
+		if base != "" {
+			path := fmt.Sprintf("%s/sessions/%s/approvals/%s/", base,
+				url.PathEscape(req.Session.SessionID), url.PathEscape(approval.RequestID))
+			notification.Approvals[i].ApproveURL = path + "approve"
+			notification.Approvals[i].DenyURL = path + "deny"
+		}
+	}
+	return notification
+}
+
+// notifyApprovals sends the notification to every notifier. Failures are
+// logged: they do not fail the run, whose approvals can still be listed and
+// resolved.
+func notifyApprovals(ctx context.Context, notifiers []ApprovalNotifier, notification ApprovalNotification) {
+	if len(notification.Approvals) == 0 {
+		return
+	}
+	for _, notifier := range notifiers {
+		if err := notifier.NotifyApproval(ctx, notification); err != nil {
+			agents.Logger().WarnContext(ctx, "approval notification failed",
+				slog.String("notifier", fmt.Sprintf("%T", notifier)),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// WebhookApprovalNotifier POSTs the notifications as JSON to an endpoint.
+type WebhookApprovalNotifier struct {
+	URL string
+	// Client defaults to a client with a 10 seconds timeout.
+	Client *http.Client
+	// SigningSecret, when set, signs the requests in the
+	// CallbackSignatureHeader header, as for HTTP callbacks.
+	SigningSecret []byte
+}
+
+func (n *WebhookApprovalNotifier) NotifyApproval(ctx context.Context, notification ApprovalNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal approval notification: %w", err)
+	}
+	header := http.Header{}
+	if len(n.SigningSecret) > 0 {
+		header.Set(CallbackSignatureHeader, SignCallbackPayload(n.SigningSecret, body, time.Now()))
+	}
+	return postJSON(ctx, n.Client, n.URL, body, header)
+}
+
+// SlackApprovalNotifier posts the notifications to a Slack incoming webhook.
+type SlackApprovalNotifier struct {
+	WebhookURL string
+	// Client defaults to a client with a 10 seconds timeout.
+	Client *http.Client
+}
+
+func (n *SlackApprovalNotifier) NotifyApproval(ctx context.Context, notification ApprovalNotification) error {
+	body, err := json.Marshal(map[string]any{"text": approvalNotificationText(notification, true)})
+	if err != nil {
+		return fmt.Errorf("marshal slack message: %w", err)
+	}
+	return postJSON(ctx, n.Client, n.WebhookURL, body, nil)
+}
+
+// EmailApprovalNotifier sends the notifications by email through an SMTP
+// server.
+type EmailApprovalNotifier struct {
+	// Addr of the SMTP server, as "host:port".
+	Addr string
+	// Auth is optional, e.g. smtp.PlainAuth.
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+func (n *EmailApprovalNotifier) NotifyApproval(_ context.Context, notification ApprovalNotification) error {
+	if n.Addr == "" || n.From == "" || len(n.To) == 0 {
+		return errors.New("email notifier requires an address, a sender and recipients")
+	}
+	// Header values must not inject other headers.
+	header := strings.NewReplacer("\r", " ", "\n", " ")
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", header.Replace(n.From))
+	fmt.Fprintf(&msg, "To: %s\r\n", header.Replace(strings.Join(n.To, ", ")))
+	fmt.Fprintf(&msg, "Subject: Approval required: %s (%s)\r\n",
+		header.Replace(notification.WorkflowName), header.Replace(notification.SessionID))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(approvalNotificationText(notification, false), "\n", "\r\n"))
+	if err := smtp.SendMail(n.Addr, n.Auth, n.From, n.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("send approval email: %w", err)
+	}
+	return nil
+}
+
+// approvalNotificationText renders a notification for humans, with Slack
+// mrkdwn formatting or as plain text.
+func approvalNotificationText(notification ApprovalNotification, mrkdwn bool) string {
+	code := func(s string) string {
+		if mrkdwn {
+			return "`" + s + "`"
+		}
+		return s
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Workflow %s (session %s) is waiting for %d approvals",
+		code(notification.WorkflowName), code(notification.SessionID), len(notification.Approvals))
+	if notification.AgentName != "" {
+		fmt.Fprintf(&sb, " requested by agent %s", code(notification.AgentName))
+	}
+	sb.WriteString(".\n")
+	for _, approval := range notification.Approvals {
+		fmt.Fprintf(&sb, "\n- %s: tool %s on %s\n  arguments: %s\n",
+			code(approval.RequestID), code(approval.ToolName), code(approval.ServerLabel), code(shorten(approval.Arguments, 500)))
+		if approval.ApproveURL != "" {
+			fmt.Fprintf(&sb, "  approve: POST %s\n  deny: POST %s\n", approval.ApproveURL, approval.DenyURL)
+		}
+	}
+	return sb.String()
+}
+
+func postJSON(ctx context.Context, client *http.Client, target string, body []byte, header http.Header) error {
+	if target == "" {
+		return errors.New("missing URL")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &CallbackStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
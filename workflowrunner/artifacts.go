@@ -0,0 +1,278 @@
+package workflowrunner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// DefaultArtifactInlineLimit is the default size in bytes above which tool
+// outputs are stored as artifacts, see RunnerService.ArtifactInlineLimit.
+const DefaultArtifactInlineLimit = 16 << 10
+
+// ErrArtifactNotFound is returned by ArtifactStore.Get for unknown artifacts.
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// Artifact references a blob produced by a run, such as a large tool output,
+// a generated image or a file written by the code interpreter, kept in an
+// ArtifactStore instead of being inlined in the callback events.
+type Artifact struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+	// Name of the artifact, e.g. the file name of a code interpreter file.
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// SHA256 of the content, hex encoded.
+	SHA256 string `json:"sha256"`
+	// URI locating the content in the store, e.g. "s3://bucket/key".
+	URI       string    `json:"uri"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArtifactStore persists the artifacts of runs.
+type ArtifactStore interface {
+	// Put stores the content of the artifact, whose ID is assigned by the
+	// store when empty, and returns it with its ID, size, digest and URI.
+	Put(ctx context.Context, artifact Artifact, content []byte) (Artifact, error)
+	// Get returns the artifact with the given ID and its content, or
+	// ErrArtifactNotFound.
+	Get(ctx context.Context, id string) (Artifact, []byte, error)
+}
+
+// newArtifact fills the fields of the artifact derived from its content.
+// The artifacts stored by runs already have the ID and creation time given
+// by the IDGenerator and Clock of their RunnerService; the defaults apply to
+// the artifacts put directly.
+func newArtifact(artifact Artifact, content []byte) Artifact {
+	if artifact.ID == "" {
+		artifact.ID = RandomIDGenerator{}.ArtifactID()
+	}
+	if artifact.ContentType == "" {
+		artifact.ContentType = http.DetectContentType(content)
+	}
+	if artifact.CreatedAt.IsZero() {
+		artifact.CreatedAt = Clock(nil).now()
+	}
+	sum := sha256.Sum256(content)
+	artifact.SHA256 = hex.EncodeToString(sum[:])
+	artifact.Size = int64(len(content))
+	return artifact
+}
+
+// DirArtifactStore keeps the artifacts as files of a local directory, along
+// with their metadata as JSON.
+type DirArtifactStore struct {
+	Dir string
+}
+
+// NewDirArtifactStore returns a store keeping the artifacts in dir, created
+// on first put.
+func NewDirArtifactStore(dir string) *DirArtifactStore {
+	return &DirArtifactStore{Dir: dir}
+}
+
+func (s *DirArtifactStore) path(id string) string {
+	return filepath.Join(s.Dir, sanitizeFileName(id))
+}
+
+func (s *DirArtifactStore) Put(_ context.Context, artifact Artifact, content []byte) (Artifact, error) {
+	artifact = newArtifact(artifact, content)
+	path := s.path(artifact.ID)
+	if abs, err := filepath.Abs(path); err == nil {
+		artifact.URI = "file://" + filepath.ToSlash(abs)
+	}
+	meta, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return artifact, fmt.Errorf("marshal artifact: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return artifact, fmt.Errorf("create artifact dir: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return artifact, fmt.Errorf("write artifact: %w", err)
+	}
+	// The metadata is written last, so artifacts are only found once
+	// complete.
+	if err := os.WriteFile(path+".json", append(meta, '\n'), 0o644); err != nil {
+		return artifact, fmt.Errorf("write artifact metadata: %w", err)
+	}
+	return artifact, nil
+}
+
+func (s *DirArtifactStore) Get(_ context.Context, id string) (Artifact, []byte, error) {
+	path := s.path(id)
+	meta, err := os.ReadFile(path + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return Artifact{}, nil, fmt.Errorf("%w: %q", ErrArtifactNotFound, id)
+	}
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("read artifact metadata: %w", err)
+	}
+	var artifact Artifact
+	if err := json.Unmarshal(meta, &artifact); err != nil {
+		return Artifact{}, nil, fmt.Errorf("decode artifact metadata: %w", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("read artifact: %w", err)
+	}
+	return artifact, content, nil
+}
+
+// runArtifacts stores the artifacts of the events of a run, replacing their
+// content in the serialized events with references.
+type runArtifacts struct {
+	store       ArtifactStore
+	inlineLimit int
+	sessionID   string
+	ids         IDGenerator
+	clock       Clock
+
+	mu        sync.Mutex
+	artifacts []Artifact
+}
+
+func newRunArtifacts(store ArtifactStore, inlineLimit int, sessionID string, ids IDGenerator, clock Clock) *runArtifacts {
+	if inlineLimit <= 0 {
+		inlineLimit = DefaultArtifactInlineLimit
+	}
+	return &runArtifacts{store: store, inlineLimit: inlineLimit, sessionID: sessionID, ids: ids, clock: clock}
+}
+
+func (a *runArtifacts) put(ctx context.Context, artifact Artifact, content []byte) (Artifact, error) {
+	artifact.SessionID = a.sessionID
+	artifact.ID = a.ids.ArtifactID()
+	artifact.CreatedAt = a.clock.now()
+	artifact, err := a.store.Put(ctx, artifact, content)
+	if err != nil {
+		return artifact, fmt.Errorf("store artifact %q: %w", artifact.Name, err)
+	}
+	a.mu.Lock()
+	a.artifacts = append(a.artifacts, artifact)
+	a.mu.Unlock()
+	return artifact, nil
+}
+
+// list returns the artifacts stored so far.
+func (a *runArtifacts) list() []Artifact {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Artifact(nil), a.artifacts...)
+}
+
+// storeEvent stores the artifacts of the event, updating its payload as
+// returned by serializeStreamEvent.
+func (a *runArtifacts) storeEvent(ctx context.Context, event agents.StreamEvent, payload RunEventPayload) error {
+	switch payload := payload.(type) {
+	case *RawEventPayload:
+		// Raw events repeat the blobs of the items, e.g. generated images.
+		if len(payload.Data) > a.inlineLimit {
+			payload.DataOmittedBytes = len(payload.Data)
+			payload.Data = nil
+		}
+	case *ImageGenerationPartialImageEventPayload:
+		ev, ok := event.(agents.ImageGenerationPartialImageStreamEvent)
+		if !ok || len(ev.PartialImageB64) <= a.inlineLimit {
+			return nil
+		}
+		content, err := base64.StdEncoding.DecodeString(ev.PartialImageB64)
+		if err != nil {
+			return fmt.Errorf("decode partial image: %w", err)
+		}
+		artifact, err := a.put(ctx, Artifact{Name: fmt.Sprintf("%s_partial_%d", ev.ItemID, ev.PartialImageIndex)}, content)
+		if err != nil {
+			return err
+		}
+		payload.PartialImageB64 = ""
+		payload.Artifact = &artifact
+	case *RunItemEventPayload:
+		ev, ok := event.(agents.RunItemStreamEvent)
+		item := payload.Item
+		if !ok || item == nil {
+			return nil
+		}
+		switch v := ev.Item.(type) {
+		case agents.ToolCallOutputItem:
+			return a.storeToolOutput(ctx, v, item)
+		case agents.ToolCallItem:
+			if raw, ok := v.RawItem.(agents.ResponseOutputItemImageGenerationCall); ok && raw.Result != "" {
+				content, err := base64.StdEncoding.DecodeString(raw.Result)
+				if err != nil {
+					return fmt.Errorf("decode generated image: %w", err)
+				}
+				artifact, err := a.put(ctx, Artifact{Name: raw.ID}, content)
+				if err != nil {
+					return err
+				}
+				item["artifact"] = artifact
+			}
+		case agents.MessageOutputItem:
+			return a.storeContainerFiles(ctx, v, item)
+		}
+	}
+	return nil
+}
+
+func (a *runArtifacts) storeToolOutput(ctx context.Context, v agents.ToolCallOutputItem, item map[string]any) error {
+	var (
+		content     []byte
+		contentType string
+	)
+	switch output := v.Output.(type) {
+	case string:
+		content, contentType = []byte(output), "text/plain; charset=utf-8"
+	default:
+		data, err := json.Marshal(output)
+		if err != nil {
+			return nil
+		}
+		content, contentType = data, "application/json"
+	}
+	if len(content) <= a.inlineLimit {
+		return nil
+	}
+	name := "tool_output"
+	if raw, ok := v.RawItem.(agents.ResponseInputItemFunctionCallOutputParam); ok {
+		name = raw.CallID
+	}
+	artifact, err := a.put(ctx, Artifact{Name: name, ContentType: contentType}, content)
+	if err != nil {
+		return err
+	}
+	delete(item, "output")
+	item["artifact"] = artifact
+	return nil
+}
+
+// storeContainerFiles downloads the code interpreter files cited by the
+// message into the store.
+func (a *runArtifacts) storeContainerFiles(ctx context.Context, v agents.MessageOutputItem, item map[string]any) error {
+	var artifacts []Artifact
+	for _, file := range agents.ItemHelpers().ContainerFiles(v) {
+		data, err := agents.ContainerFileContent(ctx, agents.GetDefaultOpenaiClient(), file.ContainerID, file.FileID)
+		if err != nil {
+			return fmt.Errorf("download container file %q: %w", file.Filename, err)
+		}
+		artifact, err := a.put(ctx, Artifact{Name: file.Filename}, data)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	if len(artifacts) > 0 {
+		item["artifacts"] = artifacts
+	}
+	return nil
+}
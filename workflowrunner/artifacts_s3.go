@@ -0,0 +1,147 @@
+package workflowrunner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// S3ArtifactStore keeps the artifacts as objects of an S3 bucket, using
+// static credentials. The metadata of the artifacts is kept in the user
+// metadata of the objects.
+//
+// Credentials from instance roles or SSO are not supported: implement
+// ArtifactStore with the AWS SDK instead.
+type S3ArtifactStore struct {
+	Bucket string
+	// Prefix of the object keys, e.g. "artifacts/".
+	Prefix          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Optional endpoint override, e.g. for MinIO or LocalStack. Objects are
+	// then addressed in path style.
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewS3ArtifactStore returns a store of the given bucket using the standard
+// AWS environment variables (AWS_REGION or AWS_DEFAULT_REGION,
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN).
+func NewS3ArtifactStore(bucket string) *S3ArtifactStore {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return &S3ArtifactStore{
+		Bucket:          bucket,
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+func (s *S3ArtifactStore) key(id string) string {
+	return s.Prefix + sanitizeFileName(id)
+}
+
+func (s *S3ArtifactStore) objectURL(key string) string {
+	escaped := (&url.URL{Path: key}).EscapedPath()
+	if s.Endpoint != "" {
+		return strings.TrimSuffix(s.Endpoint, "/") + "/" + s.Bucket + "/" + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, escaped)
+}
+
+func (s *S3ArtifactStore) do(ctx context.Context, method, key string, body []byte, header http.Header) (*http.Response, error) {
+	if s.Region == "" {
+		return nil, errors.New("aws region is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	signAWSRequestV4(req, body, s.AccessKeyID, s.SecretAccessKey, s.Region, "s3", time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *S3ArtifactStore) Put(ctx context.Context, artifact Artifact, content []byte) (Artifact, error) {
+	artifact = newArtifact(artifact, content)
+	key := s.key(artifact.ID)
+	artifact.URI = "s3://" + s.Bucket + "/" + key
+
+	header := http.Header{}
+	header.Set("Content-Type", artifact.ContentType)
+	header.Set("X-Amz-Meta-Session-Id", url.QueryEscape(artifact.SessionID))
+	header.Set("X-Amz-Meta-Name", url.QueryEscape(artifact.Name))
+	header.Set("X-Amz-Meta-Sha256", artifact.SHA256)
+	header.Set("X-Amz-Meta-Created-At", artifact.CreatedAt.Format(time.RFC3339Nano))
+	resp, err := s.do(ctx, http.MethodPut, key, content, header)
+	if err != nil {
+		return artifact, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return artifact, fmt.Errorf("s3 returned status %s", resp.Status)
+	}
+	return artifact, nil
+}
+
+func (s *S3ArtifactStore) Get(ctx context.Context, id string) (Artifact, []byte, error) {
+	key := s.key(id)
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return Artifact{}, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Artifact{}, nil, fmt.Errorf("%w: %q", ErrArtifactNotFound, id)
+	}
+	if resp.StatusCode >= 300 {
+		return Artifact{}, nil, fmt.Errorf("s3 returned status %s", resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("read s3 object: %w", err)
+	}
+
+	artifact := Artifact{
+		ID:          id,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        int64(len(content)),
+		SHA256:      resp.Header.Get("X-Amz-Meta-Sha256"),
+		URI:         "s3://" + s.Bucket + "/" + key,
+	}
+	artifact.SessionID, _ = url.QueryUnescape(resp.Header.Get("X-Amz-Meta-Session-Id"))
+	artifact.Name, _ = url.QueryUnescape(resp.Header.Get("X-Amz-Meta-Name"))
+	artifact.CreatedAt, _ = time.Parse(time.RFC3339Nano, resp.Header.Get("X-Amz-Meta-Created-At"))
+	return artifact, content, nil
+}
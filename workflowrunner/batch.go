@@ -0,0 +1,234 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+)
+
+// DefaultBatchConcurrency is the number of runs of a batch in flight when
+// the batch does not set concurrency.
+const DefaultBatchConcurrency = 4
+
+// ErrBatchRequest is returned when running a batch request as a single run.
+var ErrBatchRequest = errors.New("batch requests are run with ExecuteBatch")
+
+// BatchDeclaration runs the workflow once for each item, as separate runs
+// grouped under the session of the request, e.g. for bulk classification or
+// evaluation jobs.
+type BatchDeclaration struct {
+	Items []BatchItemDeclaration `json:"items" jsonschema:"minItems=1"`
+	// Concurrency bounds the runs in flight, DefaultBatchConcurrency when
+	// zero.
+	Concurrency int `json:"concurrency,omitempty" jsonschema:"minimum=0"`
+}
+
+// BatchItemDeclaration is the input of one run of a batch.
+type BatchItemDeclaration struct {
+	// ID of the item, unique in the batch; defaults to its index. The run
+	// uses the session "<session_id>:<id>".
+	ID    string `json:"id,omitempty"`
+	Query string `json:"query" jsonschema:"minLength=1"`
+	// Metadata, Context and Inputs are merged over the ones of the request.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Context  map[string]any `json:"context,omitempty"`
+	Inputs   map[string]any `json:"inputs,omitempty"`
+}
+
+// Validate performs shallow validation of the batch declaration.
+func (b *BatchDeclaration) Validate() error {
+	if len(b.Items) == 0 {
+		return errors.New("items are required")
+	}
+	if b.Concurrency < 0 {
+		return errors.New("concurrency cannot be negative")
+	}
+	seen := make(map[string]struct{}, len(b.Items))
+	for i, item := range b.Items {
+		if strings.TrimSpace(item.Query) == "" {
+			return fmt.Errorf("items[%d]: query is required", i)
+		}
+		id := batchItemID(item, i)
+		if _, dup := seen[id]; dup {
+			return fmt.Errorf("items[%d]: duplicate id %q", i, id)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+func batchItemID(item BatchItemDeclaration, index int) string {
+	if item.ID != "" {
+		return item.ID
+	}
+	return strconv.Itoa(index)
+}
+
+// BatchSummary aggregates the runs of a batch request.
+type BatchSummary struct {
+	WorkflowName string `json:"workflow_name"`
+	// BatchID is the session ID of the batch request, which is the trace
+	// group of its runs.
+	BatchID string `json:"batch_id"`
+	// Runs are the outcomes of the items, in their order.
+	Runs      []BatchRunSummary `json:"runs"`
+	Completed int               `json:"completed"`
+	Failed    int               `json:"failed"`
+	// Suspended counts the runs waiting for approvals or inputs, resumed
+	// separately with their sessions.
+	Suspended int `json:"suspended"`
+	// Usage of all the runs.
+	Usage *RunUsage `json:"usage,omitempty"`
+}
+
+// BatchRunSummary is the outcome of the run of a batch item. Runs which
+// could not be started are reported as failed.
+type BatchRunSummary struct {
+	ItemID string `json:"item_id"`
+	RunResponse
+}
+
+// ExecuteBatch validates the batch request and runs its workflow for each
+// item, returning a task resolved once all the runs are over. The events of
+// the runs are published to the callback of the request, tagged with the
+// batch_id and batch_item metadata.
+func (s *RunnerService) ExecuteBatch(ctx context.Context, req WorkflowRequest) (*asynctask.Task[BatchSummary], error) {
+	return s.executeBatch(ctx, req, nil)
+}
+
+// ExecuteBatchWithPublisher is like ExecuteBatch, but publishes the events
+// of the runs to the given publisher, as ExecuteWithPublisher.
+func (s *RunnerService) ExecuteBatchWithPublisher(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher) (*asynctask.Task[BatchSummary], error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	req.Callback = CallbackDeclaration{Mode: CallbackModeStream}
+	return s.executeBatch(ctx, req, publisher)
+}
+
+func (s *RunnerService) executeBatch(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher) (*asynctask.Task[BatchSummary], error) {
+	if req.Batch == nil {
+		return nil, errors.New("batch is required")
+	}
+	if err := ValidateWorkflowRequest(req); err != nil {
+		return nil, err
+	}
+	batch := *req.Batch
+	concurrency := batch.Concurrency
+	if concurrency == 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	return asynctask.CreateTask(ctx, func(taskCtx context.Context) (BatchSummary, error) {
+		summary := BatchSummary{
+			WorkflowName: req.Workflow.Name,
+			BatchID:      req.Session.SessionID,
+			Runs:         make([]BatchRunSummary, len(batch.Items)),
+		}
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, item := range batch.Items {
+			itemReq := batchItemRequest(req, item, i)
+			run := &summary.Runs[i]
+			run.ItemID = batchItemID(item, i)
+			run.WorkflowName = itemReq.Workflow.Name
+			run.SessionID = itemReq.Session.SessionID
+
+			select {
+			case sem <- struct{}{}:
+			case <-taskCtx.Done():
+				run.Status = ExecutionStatusFailed
+				run.Error = context.Cause(taskCtx).Error()
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				var task *asynctask.Task[RunSummary]
+				var err error
+				if publisher != nil {
+					task, err = s.ExecuteWithPublisher(taskCtx, itemReq, publisher)
+				} else {
+					task, err = s.Execute(taskCtx, itemReq)
+				}
+				if err != nil {
+					run.Status = ExecutionStatusFailed
+					run.Error = err.Error()
+					return
+				}
+				run.RunResponse = newRunResponse(task.Await().Value)
+			}()
+		}
+		wg.Wait()
+
+		for _, run := range summary.Runs {
+			switch run.Status {
+			case ExecutionStatusCompleted:
+				summary.Completed++
+			case ExecutionStatusWaitingApproval, ExecutionStatusWaitingInput:
+				summary.Suspended++
+			default:
+				summary.Failed++
+			}
+			if run.Usage != nil {
+				if summary.Usage == nil {
+					summary.Usage = &RunUsage{}
+				}
+				summary.Usage.merge(run.Usage)
+			}
+		}
+		return summary, nil
+	}), nil
+}
+
+// batchItemRequest returns the request of the run of a batch item.
+func batchItemRequest(req WorkflowRequest, item BatchItemDeclaration, index int) WorkflowRequest {
+	id := batchItemID(item, index)
+	itemReq := req
+	itemReq.Batch = nil
+	itemReq.Query = item.Query
+	itemReq.Session.SessionID = req.Session.SessionID + ":" + id
+	itemReq.Metadata = maps.Clone(req.Metadata)
+	if itemReq.Metadata == nil {
+		itemReq.Metadata = make(map[string]any)
+	}
+	maps.Copy(itemReq.Metadata, item.Metadata)
+	itemReq.Metadata[batchIDMetadataKey] = req.Session.SessionID
+	itemReq.Metadata[batchItemMetadataKey] = id
+	if len(item.Context) > 0 {
+		itemReq.Context = maps.Clone(req.Context)
+		if itemReq.Context == nil {
+			itemReq.Context = make(map[string]any)
+		}
+		maps.Copy(itemReq.Context, item.Context)
+	}
+	if len(item.Inputs) > 0 {
+		itemReq.Inputs = maps.Clone(req.Inputs)
+		if itemReq.Inputs == nil {
+			itemReq.Inputs = make(map[string]any)
+		}
+		maps.Copy(itemReq.Inputs, item.Inputs)
+	}
+	return itemReq
+}
+
+const (
+	batchIDMetadataKey   = "batch_id"
+	batchItemMetadataKey = "batch_item"
+)
+
+// traceGroupID returns the trace group of the runs of the request: the
+// batch of its item, or its session.
+func traceGroupID(req WorkflowRequest) string {
+	if id, ok := req.Metadata[batchIDMetadataKey].(string); ok && id != "" {
+		return id
+	}
+	return req.Session.SessionID
+}
@@ -7,6 +7,8 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 	"github.com/nlpodyssey/openai-agents-go/memory"
@@ -34,6 +36,10 @@ type OutputTypeFactory func(ctx context.Context, decl OutputTypeDeclaration) (ag
 // SessionFactory allocates or loads a conversational session.
 type SessionFactory func(ctx context.Context, decl SessionDeclaration) (memory.Session, error)
 
+// VectorStoreFactory returns the vector store holding the long-term memories
+// available to a session.
+type VectorStoreFactory func(ctx context.Context, decl SessionDeclaration) (memory.VectorStore, error)
+
 // BuildResult contains the artifacts required to execute a workflow.
 type BuildResult struct {
 	StartingAgent *agents.Agent
@@ -42,13 +48,56 @@ type BuildResult struct {
 	Session       memory.Session
 	WorkflowName  string
 	TraceMetadata map[string]any
+	// Variants are the names of the variants chosen for the agents
+	// declaring any, by agent.
+	Variants map[string]string
+	// Inputs of the request, with the defaults of the missing ones.
+	Inputs map[string]any
+
+	// Routes, loops, fan-outs and retry policies of the agents declaring
+	// any.
+	flows map[*agents.Agent]*agentFlow
+	// Declared model names of the agents declaring any.
+	modelNames map[*agents.Agent]string
 }
 
 // Builder converts declarative workflow payloads into executable SDK primitives.
 type Builder struct {
 	ToolFactories       map[string]ToolFactory
 	OutputTypeFactories map[string]OutputTypeFactory
-	SessionFactory      SessionFactory
+	// OutputTypePresets are the JSON schemas referenced by
+	// OutputTypeDeclaration.PresetRef, see WithOutputTypePreset.
+	OutputTypePresets map[string]map[string]any
+	// ModelProviderFactories are keyed by ModelDeclaration.Provider.
+	ModelProviderFactories map[string]ModelProviderFactory
+	SessionFactory         SessionFactory
+	// SessionFactories are keyed by SessionDeclaration.Store.
+	SessionFactories map[string]SessionFactory
+	// Optional per-account quota enforcement, keyed by Credentials.AccountID.
+	TenantQuotas TenantQuotaTracker
+	// Embedder and VectorStoreFactory back sessions declaring long_term_memory.
+	Embedder           memory.Embedder
+	VectorStoreFactory VectorStoreFactory
+	// SecretProvider resolves the ${secret:NAME} references of the credential
+	// fields: model keys and headers, and the authorization and headers of
+	// hosted MCP tools and MCP servers. References elsewhere fail the build.
+	SecretProvider SecretProvider
+	// WorkflowRegistry resolves the WorkflowRef of requests.
+	WorkflowRegistry WorkflowRegistry
+	// ToolUseBehaviorHandlers are referenced by the custom tool_use_behavior
+	// of agents, see WithToolUseBehaviorHandler.
+	ToolUseBehaviorHandlers map[string]ToolUseBehaviorHandler
+	// TemplateFuncs are added to the functions of instruction templates, see
+	// WithTemplateFuncs.
+	TemplateFuncs template.FuncMap
+	// StripUnauthorizedTools removes the tools and MCP servers whose required
+	// capabilities the caller lacks, instead of failing the build with a
+	// *CapabilityError. Workflows lacking capabilities always fail.
+	StripUnauthorizedTools bool
+	// LookupEnv resolves the ${env:NAME} references of manifests, e.g.
+	// os.LookupEnv. Environment references are rejected when nil, as
+	// manifests could otherwise read any variable of the process.
+	LookupEnv func(name string) (string, bool)
 }
 
 // NewDefaultBuilder returns a Builder with the builtin registries initialized.
@@ -60,29 +109,193 @@ func NewDefaultBuilder() *Builder {
 			"file_search":      newFileSearchTool,
 			"image_generation": newImageGenerationTool,
 			"hosted_mcp":       newHostedMCPTool,
+			"ask_user":         newAskUserTool,
 		},
 		OutputTypeFactories: map[string]OutputTypeFactory{
 			"json_object": newJSONMapOutputType,
 		},
+		OutputTypePresets: DefaultOutputTypePresets(),
+		ModelProviderFactories: map[string]ModelProviderFactory{
+			"openai":     newOpenAIModelProvider,
+			"anthropic":  OpenAICompatibleProvider("https://api.anthropic.com/v1/", "ANTHROPIC_API_KEY"),
+			"mistral":    OpenAICompatibleProvider("https://api.mistral.ai/v1/", "MISTRAL_API_KEY"),
+			"openrouter": OpenAICompatibleProvider("https://openrouter.ai/api/v1/", "OPENROUTER_API_KEY"),
+			"custom":     OpenAICompatibleProvider("", ""),
+		},
 		SessionFactory: NewSQLiteSessionFactory("workflowrunner_sessions"),
+		SessionFactories: map[string]SessionFactory{
+			"sqlite":   NewSQLiteSessionFactory("workflowrunner_sessions"),
+			"postgres": NewPostgresSessionFactory(""),
+		},
+		VectorStoreFactory: NewInMemoryVectorStoreFactory(),
 	}
 }
 
 // Build constructs agents, run configuration, and session resources from the request.
 func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult, error) {
-	if err := ValidateWorkflowRequest(req); err != nil {
+	req, variants, err := b.prepareRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := b.buildAgents(ctx, req)
+	if err != nil {
 		return nil, err
 	}
 
-	sessionFactory := b.SessionFactory
-	if sessionFactory == nil {
-		sessionFactory = NewSQLiteSessionFactory("workflowrunner_sessions")
+	sessionFactory, err := b.sessionFactory(req.Session)
+	if err != nil {
+		return nil, err
 	}
 	session, err := sessionFactory(ctx, req.Session)
 	if err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
+	session, forked, err := forkSession(ctx, sessionFactory, req.Session, session)
+	if err != nil {
+		closeSession(session)
+		return nil, fmt.Errorf("fork session: %w", err)
+	}
+
+	runConfig := agents.RunConfig{
+		WorkflowName:  req.Workflow.Name,
+		ModelProvider: graph.modelProvider,
+	}
+	if req.Session.MaxTurns > 0 {
+		runConfig.MaxTurns = uint64(req.Session.MaxTurns)
+	}
+	runConfig.OutputValidationRetries = req.Session.OutputValidationRetries
+	runConfig.HandoffLoopPolicy = agents.HandoffLoopPolicy{
+		MaxRepeats:          req.Session.MaxHandoffRepeats,
+		InterventionMessage: req.Session.HandoffLoopIntervention,
+	}
+	runConfig.Session = session
+	if req.Session.HistorySize > 0 {
+		runConfig.LimitMemory = req.Session.HistorySize
+	}
+	if req.Session.HistoryTokenBudget > 0 {
+		runConfig.SessionTrimPolicy = memory.TokenBudgetTrimPolicy{MaxTokens: req.Session.HistoryTokenBudget}
+	}
+	if decl := req.Session.LongTermMemory; decl != nil {
+		ltm, err := b.longTermMemory(ctx, req.Session, *decl)
+		if err != nil {
+			closeSession(session)
+			return nil, fmt.Errorf("long-term memory: %w", err)
+		}
+		runConfig.LongTermMemory = ltm
+	}
+	// The tenant quota is acquired last, so that failed builds hold none.
+	if b.TenantQuotas != nil {
+		tenant, err := b.acquireTenantSession(ctx, req.Session, session, forked)
+		if err != nil {
+			closeSession(session)
+			return nil, err
+		}
+		session = tenant
+		runConfig.Session = session
+	}
+	runConfig.TracingDisabled = false
+	runConfig.GroupID = traceGroupID(req)
+	traceMetadata := composeTraceMetadata(req)
+	for agent, variant := range variants {
+		traceMetadata["variant."+agent] = variant
+	}
+	runConfig.TraceMetadata = maps.Clone(traceMetadata)
+
+	builderResult := &BuildResult{
+		StartingAgent: graph.startingAgent,
+		AgentMap:      graph.agentMap,
+		Runner:        agents.Runner{Config: runConfig},
+		Session:       session,
+		WorkflowName:  req.Workflow.Name,
+		TraceMetadata: traceMetadata,
+		Variants:      variants,
+		Inputs:        req.Inputs,
+		flows:         graph.flows,
+		modelNames:    graph.modelNames,
+	}
+	return builderResult, nil
+}
+
+// agentDeclarationName returns the name under which the agent is declared.
+func (r *BuildResult) agentDeclarationName(agent *agents.Agent) string {
+	for name, a := range r.AgentMap {
+		if a == agent {
+			return name
+		}
+	}
+	return displayAgentName(agent)
+}
+
+// prepareRequest resolves the workflow reference of the request, validates
+// it, applies the agent variants and resolves the secret and interpolation
+// references of its workflow. It returns the chosen variants, by agent.
+//
+// Secrets are resolved before interpolation, so that the references found in
+// the metadata, context and inputs of the caller are left as they are.
+func (b *Builder) prepareRequest(ctx context.Context, req WorkflowRequest) (WorkflowRequest, map[string]string, error) {
+	req, err := b.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return req, nil, err
+	}
+	if err := ValidateWorkflowRequest(req); err != nil {
+		return req, nil, err
+	}
+	if err := authorizeWorkflow(req); err != nil {
+		return req, nil, err
+	}
+	if req.Inputs, err = resolveInputs(req.Workflow.Inputs, req.Inputs); err != nil {
+		return req, nil, fmt.Errorf("inputs invalid: %w", err)
+	}
+	workflow, variants := applyVariants(req)
+	req.Workflow = workflow
+	if req.Workflow, err = b.resolveSecrets(ctx, req.Workflow); err != nil {
+		return req, nil, fmt.Errorf("resolve secrets: %w", err)
+	}
+	if req.Workflow, err = b.interpolateWorkflow(req); err != nil {
+		return req, nil, fmt.Errorf("interpolate workflow: %w", err)
+	}
+	if cfg := req.Session.StoreConfig; cfg != nil && cfg.DSN != "" {
+		resolved := *cfg
+		resolver := &secretResolver{provider: b.SecretProvider, cache: make(map[string]string)}
+		if resolved.DSN, err = resolver.resolveString(ctx, cfg.DSN); err != nil {
+			return req, nil, fmt.Errorf("session store_config dsn: %w", err)
+		}
+		if resolved.DSN, err = b.newInterpolator(req).interpolateString(resolved.DSN); err != nil {
+			return req, nil, fmt.Errorf("session store_config dsn: %w", err)
+		}
+		req.Session.StoreConfig = &resolved
+	}
+	return req, variants, nil
+}
+
+// sessionFactory returns the factory of the store declared by the session.
+func (b *Builder) sessionFactory(decl SessionDeclaration) (SessionFactory, error) {
+	if decl.Store != "" {
+		factory, ok := b.SessionFactories[decl.Store]
+		if !ok {
+			return nil, fmt.Errorf("session store %q is not registered", decl.Store)
+		}
+		return factory, nil
+	}
+	if b.SessionFactory != nil {
+		return b.SessionFactory, nil
+	}
+	return NewSQLiteSessionFactory("workflowrunner_sessions"), nil
+}
 
+// agentGraph holds the agents built from a workflow declaration.
+type agentGraph struct {
+	startingAgent *agents.Agent
+	agentMap      map[string]*agents.Agent
+	flows         map[*agents.Agent]*agentFlow
+	modelNames    map[*agents.Agent]string
+	// Provider of the agent models, nil when they all use the default one.
+	modelProvider agents.ModelProvider
+}
+
+// buildAgents constructs the agents of the workflow, with their tools,
+// handoffs, routes and loops.
+func (b *Builder) buildAgents(ctx context.Context, req WorkflowRequest) (*agentGraph, error) {
 	agentMap := make(map[string]*agents.Agent, len(req.Workflow.Agents))
 	type pendingConfig struct {
 		decl       AgentDeclaration
@@ -91,6 +304,8 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 		toolDecls  []ToolDeclaration
 	}
 	pending := make([]pendingConfig, 0, len(req.Workflow.Agents))
+	modelNames := make(map[*agents.Agent]string, len(req.Workflow.Agents))
+	providers := newModelProviders(b.ModelProviderFactories)
 
 	for _, decl := range req.Workflow.Agents {
 		agent := agents.New(decl.Name)
@@ -100,8 +315,12 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 		if decl.HandoffDescription != "" {
 			agent.WithHandoffDescription(decl.HandoffDescription)
 		}
-		if strings.TrimSpace(decl.Instructions) != "" {
-			agent.WithInstructions(decl.Instructions)
+		instructions, err := b.renderInstructions(req, decl)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q instructions template: %w", decl.Name, err)
+		}
+		if strings.TrimSpace(instructions) != "" {
+			agent.WithInstructions(instructions)
 		}
 		if decl.PromptID != "" {
 			agent.WithPrompt(agents.Prompt{ID: decl.PromptID})
@@ -110,6 +329,14 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 			if err := applyModelDeclaration(agent, *decl.Model); err != nil {
 				return nil, fmt.Errorf("agent %q model: %w", decl.Name, err)
 			}
+			modelNames[agent] = decl.Model.Model
+			// The model is resolved here rather than by the runner, as
+			// agent tools run with agents.DefaultRunner.
+			if model, err := providers.model(ctx, *decl.Model); err != nil {
+				return nil, fmt.Errorf("agent %q model: %w", decl.Name, err)
+			} else if model != nil {
+				agent.WithModelInstance(model)
+			}
 		}
 		if decl.OutputType != nil {
 			outputType, err := b.buildOutputType(ctx, *decl.OutputType)
@@ -118,6 +345,20 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 			}
 			agent.WithOutputType(outputType)
 		}
+		if decl.ToolUseBehavior != nil {
+			behavior, err := b.buildToolUseBehavior(*decl.ToolUseBehavior)
+			if err != nil {
+				return nil, fmt.Errorf("agent %q tool_use_behavior: %w", decl.Name, err)
+			}
+			agent.WithToolUseBehavior(behavior)
+		}
+		if limits := decl.UsageLimits; limits != nil {
+			agent.WithUsageLimits(agents.AgentUsageLimits{
+				MaxTurns:     uint64(limits.MaxTurns),
+				MaxToolCalls: uint64(limits.MaxToolCalls),
+				MaxTokens:    uint64(limits.MaxTokens),
+			})
+		}
 		if gr, err := buildInputGuardrails(ctx, decl.InputGuardrails); err != nil {
 			return nil, fmt.Errorf("agent %q input guardrails: %w", decl.Name, err)
 		} else if len(gr) > 0 {
@@ -128,28 +369,34 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 		} else if len(gr) > 0 {
 			agent.WithOutputGuardrails(gr)
 		}
+		toolDecls, err := b.authorizeTools(ctx, req, decl.Name, append(slices.Clone(decl.Tools), toolsFromMCP(decl.MCPServers)...))
+		if err != nil {
+			return nil, err
+		}
 		pending = append(pending, pendingConfig{
 			decl:       decl,
 			agent:      agent,
 			agentTools: decl.AgentTools,
-			toolDecls:  append(slices.Clone(decl.Tools), toolsFromMCP(decl.MCPServers)...),
+			toolDecls:  toolDecls,
 		})
 		agentMap[decl.Name] = agent
 	}
 
-	// Second pass: attach handoffs and tools.
+	// Second pass: attach handoffs, tools, routes and loops.
+	flows := make(map[*agents.Agent]*agentFlow)
 	for _, item := range pending {
 		agent := item.agent
+		if flow, err := compileAgentFlow(item.decl, agentMap); err != nil {
+			return nil, fmt.Errorf("agent %q: %w", item.decl.Name, err)
+		} else if flow != nil {
+			flows[agent] = flow
+		}
 		if len(item.decl.Handoffs) > 0 {
-			handoffAgents := make([]*agents.Agent, 0, len(item.decl.Handoffs))
-			for _, ref := range item.decl.Handoffs {
-				target, ok := agentMap[ref]
-				if !ok {
-					return nil, fmt.Errorf("agent %q references unknown handoff agent %q", item.decl.Name, ref)
-				}
-				handoffAgents = append(handoffAgents, target)
+			handoffAgents, handoffs, err := buildHandoffs(req, item.decl, agentMap)
+			if err != nil {
+				return nil, err
 			}
-			agent.WithAgentHandoffs(handoffAgents...)
+			agent.WithAgentHandoffs(handoffAgents...).WithHandoffs(handoffs...)
 		}
 		if len(item.agentTools) > 0 {
 			for _, ref := range item.agentTools {
@@ -181,43 +428,122 @@ func (b *Builder) Build(ctx context.Context, req WorkflowRequest) (*BuildResult,
 				agent.AddTool(tool)
 			}
 		}
+		if slices.ContainsFunc(item.toolDecls, func(decl ToolDeclaration) bool { return decl.Type == "ask_user" }) {
+			agent.WithToolUseBehavior(askUserBehavior{next: agent.ToolUseBehavior})
+		}
 	}
 
 	startingAgent, ok := agentMap[req.Workflow.StartingAgent]
 	if !ok {
 		return nil, fmt.Errorf("starting agent %q missing", req.Workflow.StartingAgent)
 	}
+	return &agentGraph{
+		startingAgent: startingAgent,
+		agentMap:      agentMap,
+		flows:         flows,
+		modelNames:    modelNames,
+		modelProvider: providers.modelProvider(),
+	}, nil
+}
 
-	runConfig := agents.RunConfig{
-		WorkflowName: req.Workflow.Name,
+// forkSession reopens child when it was forked by an earlier request, or
+// forks it from the parent declared in decl.ForkFrom, sharing the parent
+// history rather than copying it. Sessions that already hold items are not
+// forked again, so follow-up messages can keep sending the same payload.
+// It reports whether child was forked.
+func forkSession(ctx context.Context, factory SessionFactory, decl SessionDeclaration, child memory.Session) (memory.Session, bool, error) {
+	openParent := func(ctx context.Context, sessionID string) (memory.Session, error) {
+		parentDecl := decl
+		parentDecl.SessionID = sessionID
+		parentDecl.ForkFrom = nil
+		return factory(ctx, parentDecl)
 	}
-	if req.Session.MaxTurns > 0 {
-		runConfig.MaxTurns = uint64(req.Session.MaxTurns)
+	session, err := memory.OpenForkedSession(ctx, child, openParent)
+	if err != nil {
+		return child, false, err
 	}
-	runConfig.Session = session
-	if req.Session.HistorySize > 0 {
-		runConfig.LimitMemory = req.Session.HistorySize
+	if session != child || decl.ForkFrom == nil {
+		return session, false, nil
 	}
-	runConfig.TracingDisabled = false
-	runConfig.GroupID = req.Session.SessionID
-	traceMetadata := composeTraceMetadata(req)
-	runConfig.TraceMetadata = maps.Clone(traceMetadata)
 
-	builderResult := &BuildResult{
-		StartingAgent: startingAgent,
-		AgentMap:      agentMap,
-		Runner:        agents.Runner{Config: runConfig},
-		Session:       session,
-		WorkflowName:  req.Workflow.Name,
-		TraceMetadata: traceMetadata,
+	existing, err := child.GetItems(ctx, 1)
+	if err != nil {
+		return child, false, err
 	}
-	return builderResult, nil
+	if len(existing) > 0 {
+		return child, false, nil
+	}
+	if _, ok := child.(memory.ForkLinkStore); !ok {
+		return child, false, fmt.Errorf("session store %T cannot fork sessions", child)
+	}
+
+	opened, err := openParent(ctx, decl.ForkFrom.SessionID)
+	if err != nil {
+		return child, false, fmt.Errorf("open parent session %q: %w", decl.ForkFrom.SessionID, err)
+	}
+	parent, err := memory.OpenForkedSession(ctx, opened, openParent)
+	if err != nil {
+		closeSession(opened)
+		return child, false, err
+	}
+	atIndex := -1
+	if decl.ForkFrom.AtIndex != nil {
+		atIndex = *decl.ForkFrom.AtIndex
+	}
+	forked, err := memory.ForkSession(ctx, parent, child, atIndex)
+	if err != nil {
+		closeSession(parent)
+		return child, false, err
+	}
+	return forked, true, nil
 }
 
-func applyModelDeclaration(agent *agents.Agent, decl ModelDeclaration) error {
-	if strings.TrimSpace(decl.Provider) != "" && !strings.EqualFold(decl.Provider, "openai") {
-		return fmt.Errorf("provider %q not supported (only openai is available in this build)", decl.Provider)
+func closeSession(session memory.Session) {
+	if closer, ok := session.(interface{ Close() error }); ok {
+		_ = closer.Close()
+	}
+}
+
+func (b *Builder) longTermMemory(ctx context.Context, session SessionDeclaration, decl LongTermMemoryDeclaration) (memory.LongTermMemory, error) {
+	if b.Embedder == nil {
+		return nil, errors.New("builder has no embedder configured")
+	}
+	storeFactory := b.VectorStoreFactory
+	if storeFactory == nil {
+		return nil, errors.New("builder has no vector store factory configured")
+	}
+	store, err := storeFactory(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("create vector store: %w", err)
 	}
+	return memory.VectorMemory{
+		Embedder: b.Embedder,
+		Store:    store,
+		TopK:     decl.TopK,
+		MinScore: decl.MinScore,
+	}, nil
+}
+
+// NewInMemoryVectorStoreFactory keeps one in-memory vector store per user and
+// account for the lifetime of the process, so that memories are shared across
+// the sessions of the same user.
+func NewInMemoryVectorStoreFactory() VectorStoreFactory {
+	var mu sync.Mutex
+	stores := make(map[string]*memory.InMemoryVectorStore)
+	return func(_ context.Context, decl SessionDeclaration) (memory.VectorStore, error) {
+		key := memory.NamespacedSessionKey(decl.Credentials.AccountID, decl.Credentials.UserID)
+		mu.Lock()
+		defer mu.Unlock()
+		store, ok := stores[key]
+		if !ok {
+			store = memory.NewInMemoryVectorStore()
+			stores[key] = store
+		}
+		return store, nil
+	}
+}
+
+func applyModelDeclaration(agent *agents.Agent, decl ModelDeclaration) error {
 	if strings.TrimSpace(decl.Model) == "" {
 		return errors.New("model name cannot be empty")
 	}
@@ -232,6 +558,9 @@ func applyModelDeclaration(agent *agents.Agent, decl ModelDeclaration) error {
 	if decl.MaxTokens != nil {
 		settings.MaxTokens = param.NewOpt(*decl.MaxTokens)
 	}
+	if decl.MaxCompletionTokens != nil {
+		settings.MaxCompletionTokens = param.NewOpt(*decl.MaxCompletionTokens)
+	}
 	if decl.Verbosity != "" {
 		switch strings.ToLower(decl.Verbosity) {
 		case "low":
@@ -244,6 +573,18 @@ func applyModelDeclaration(agent *agents.Agent, decl ModelDeclaration) error {
 			return fmt.Errorf("unsupported verbosity %q", decl.Verbosity)
 		}
 	}
+	if decl.ServiceTier != "" {
+		settings.ServiceTier = param.NewOpt(modelsettings.ServiceTier(decl.ServiceTier))
+	}
+	if decl.Truncation != "" {
+		settings.Truncation = param.NewOpt(modelsettings.Truncation(decl.Truncation))
+	}
+	if decl.Logprobs {
+		settings.Logprobs = param.NewOpt(true)
+	}
+	if decl.TopLogprobs != nil {
+		settings.TopLogprobs = param.NewOpt(*decl.TopLogprobs)
+	}
 	if decl.Metadata != nil {
 		settings.Metadata = decl.Metadata
 	}
@@ -253,6 +594,9 @@ func applyModelDeclaration(agent *agents.Agent, decl ModelDeclaration) error {
 	if decl.ExtraQuery != nil {
 		settings.ExtraQuery = decl.ExtraQuery
 	}
+	if decl.ExtraBody != nil {
+		settings.ExtraBody = decl.ExtraBody
+	}
 	if decl.Reasoning != nil {
 		settings.Reasoning = buildReasoningParam(*decl.Reasoning)
 	}
@@ -291,6 +635,12 @@ func buildReasoningParam(decl ReasoningDeclaration) openai.ReasoningParam {
 }
 
 func (b *Builder) buildOutputType(ctx context.Context, decl OutputTypeDeclaration) (agents.OutputTypeInterface, error) {
+	if decl.PresetRef != "" {
+		var ok bool
+		if decl, ok = b.outputTypePreset(decl); !ok {
+			return nil, fmt.Errorf("output type preset %q not registered", decl.PresetRef)
+		}
+	}
 	if decl.Schema == nil {
 		factory, ok := b.OutputTypeFactories[decl.Name]
 		if !ok {
@@ -322,9 +672,10 @@ func toolsFromMCP(decls []MCPDeclaration) []ToolDeclaration {
 			config[k] = v
 		}
 		out = append(out, ToolDeclaration{
-			Type:   "hosted_mcp",
-			Name:   d.ServerLabel,
-			Config: config,
+			Type:                 "hosted_mcp",
+			Name:                 d.ServerLabel,
+			Config:               config,
+			RequiredCapabilities: d.RequiredCapabilities,
 		})
 	}
 	return out
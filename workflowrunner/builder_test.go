@@ -0,0 +1,59 @@
+package workflowrunner
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildForksSessionLazily(t *testing.T) {
+	ctx := t.Context()
+	builder := newTestBuilder(t)
+	req := func(sessionID string, fork *SessionForkDeclaration) WorkflowRequest {
+		return WorkflowRequest{
+			Query: "hello",
+			Session: SessionDeclaration{
+				SessionID:   sessionID,
+				ForkFrom:    fork,
+				Credentials: CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+			},
+			Callback: CallbackDeclaration{Mode: "stdout"},
+			Workflow: WorkflowDeclaration{
+				Name:          "wf",
+				StartingAgent: "assistant",
+				Agents:        []AgentDeclaration{{Name: "assistant", Instructions: "Help."}},
+			},
+		}
+	}
+	items := func(texts ...string) []memory.TResponseInputItem {
+		var items []memory.TResponseInputItem
+		for _, text := range texts {
+			items = append(items, agentstesting.GetTextInputItem(text))
+		}
+		return items
+	}
+	build := func(req WorkflowRequest, add ...string) []memory.TResponseInputItem {
+		result, err := builder.Build(ctx, req)
+		require.NoError(t, err)
+		defer closeSession(result.Session)
+		require.NoError(t, result.Session.AddItems(ctx, items(add...)))
+		stored, err := result.Session.GetItems(ctx, 0)
+		require.NoError(t, err)
+		return stored
+	}
+
+	build(req("s0", nil), "first", "second")
+	atIndex := 1
+	assert.Equal(t, items("first", "what if"), build(req("s1", &SessionForkDeclaration{SessionID: "s0", AtIndex: &atIndex}), "what if"))
+
+	// Later requests share the forked items without declaring the fork,
+	// and do not see the items added to the parent since.
+	assert.Equal(t, items("first", "second", "third"), build(req("s0", nil), "third"))
+	assert.Equal(t, items("first", "what if", "again"), build(req("s1", nil), "again"))
+
+	_, err := builder.Build(ctx, req("s2", &SessionForkDeclaration{SessionID: "missing"}))
+	assert.ErrorIs(t, err, memory.ErrSessionNotFound)
+}
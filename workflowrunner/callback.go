@@ -2,10 +2,15 @@ package workflowrunner
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,18 +20,27 @@ type CallbackPublisher interface {
 	Publish(ctx context.Context, event CallbackEvent) error
 }
 
-// CallbackEvent describes an update emitted during a workflow run.
+// CallbackPublisherFactory creates the publisher of a callback declaration.
+type CallbackPublisherFactory func(ctx context.Context, decl CallbackDeclaration) (CallbackPublisher, error)
+
+// CallbackEvent describes an update emitted during a workflow run. The type
+// of its payload depends on its type, see CallbackEventSchema.
 type CallbackEvent struct {
-	Type      string         `json:"type"`
-	Timestamp time.Time      `json:"timestamp"`
-	Payload   any            `json:"payload,omitempty"`
-	Metadata  map[string]any `json:"metadata,omitempty"`
+	Type string `json:"type"`
+	// Version of the schema of the payload, see CallbackEventSchemaVersion.
+	SchemaVersion int            `json:"schema_version,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Payload       any            `json:"payload,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
 }
 
 // HTTPCallbackPublisher POSTs events to a configured endpoint as JSON.
 type HTTPCallbackPublisher struct {
 	client *http.Client
 	URL    string
+	// SigningSecret, when set, signs the requests in the
+	// CallbackSignatureHeader header.
+	SigningSecret []byte
 }
 
 // NewHTTPCallbackPublisher constructs an HTTP publisher with an optional custom client.
@@ -50,17 +64,110 @@ func (p *HTTPCallbackPublisher) Publish(ctx context.Context, event CallbackEvent
 		return fmt.Errorf("build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if len(p.SigningSecret) > 0 {
+		req.Header.Set(CallbackSignatureHeader, SignCallbackPayload(p.SigningSecret, body, time.Now()))
+	}
 	resp, err := p.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("post callback: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
-		return fmt.Errorf("callback returned status %s", resp.Status)
+		return &CallbackStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 	return nil
 }
 
+// CallbackSignatureHeader is the header carrying the signature of HTTP
+// callbacks, as "t=<unix timestamp>,v1=<signature>". The signature is the
+// hex-encoded HMAC-SHA256 of "<unix timestamp>.<body>" keyed with the
+// signing secret.
+const CallbackSignatureHeader = "X-Signature"
+
+// DefaultCallbackSignatureTolerance is the maximum age of a callback accepted
+// by VerifyCallbackSignature when no tolerance is given.
+const DefaultCallbackSignatureTolerance = 5 * time.Minute
+
+// ErrInvalidCallbackSignature is returned by VerifyCallbackSignature for
+// callbacks which were not signed with the secret, or are too old.
+var ErrInvalidCallbackSignature = errors.New("invalid callback signature")
+
+// SignCallbackPayload returns the CallbackSignatureHeader value of a callback
+// body sent at the given time.
+func SignCallbackPayload(secret, body []byte, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(callbackHMAC(secret, ts, body))
+}
+
+// VerifyCallbackSignature checks the CallbackSignatureHeader value of a
+// received callback against its raw body. Callbacks signed more than
+// tolerance ago, or in the future, are rejected to prevent replays; a zero
+// tolerance means DefaultCallbackSignatureTolerance. The header may carry
+// several v1 signatures, e.g. while rotating the secret: one must match.
+func VerifyCallbackSignature(secret, body []byte, header string, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultCallbackSignatureTolerance
+	}
+	var (
+		ts         string
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidCallbackSignature)
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidCallbackSignature)
+	}
+	expected := callbackHMAC(secret, ts, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature mismatch", ErrInvalidCallbackSignature)
+}
+
+func callbackHMAC(secret []byte, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// resolveCallback returns the callback of the request with the interpolation
+// references of its target and the secret references of its signing secret
+// replaced by their values.
+func (b *Builder) resolveCallback(ctx context.Context, req WorkflowRequest) (CallbackDeclaration, error) {
+	callback := req.Callback
+	target, err := b.newInterpolator(req).interpolateString(callback.Target)
+	if err != nil {
+		return callback, fmt.Errorf("callback target: %w", err)
+	}
+	callback.Target = target
+	if err := callback.Validate(); err != nil {
+		return callback, fmt.Errorf("callback invalid: %w", err)
+	}
+	resolver := &secretResolver{provider: b.SecretProvider, cache: make(map[string]string)}
+	if callback.SigningSecret, err = resolver.resolveString(ctx, callback.SigningSecret); err != nil {
+		return callback, fmt.Errorf("callback signing_secret: %w", err)
+	}
+	return callback, nil
+}
+
 // StdoutCallbackPublisher prints events to stdout (useful for local testing).
 type StdoutCallbackPublisher struct{}
 
@@ -0,0 +1,132 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCallbackPublisherClosed is returned when publishing to a closed
+// AsyncCallbackPublisher.
+var ErrCallbackPublisherClosed = errors.New("callback publisher is closed")
+
+// AsyncCallbackPublisher delivers the events to its publisher from a
+// goroutine, in the order they were published, so that a slow endpoint does
+// not stall the run. Publish queues the event, waiting only while the queue
+// is full, and returns the errors of the deliveries which failed since the
+// previous call. Close delivers the queued events before returning.
+type AsyncCallbackPublisher struct {
+	publisher CallbackPublisher
+	queue     chan asyncCallback
+
+	// queueMu serializes the sends to the queue with its closing.
+	queueMu sync.Mutex
+	closed  bool
+	done    chan struct{}
+
+	errsMu sync.Mutex
+	errs   []error
+}
+
+// asyncCallback is an event to deliver, or a flush request when flushed is
+// set.
+type asyncCallback struct {
+	ctx     context.Context
+	event   CallbackEvent
+	flushed chan struct{}
+}
+
+// NewAsyncCallbackPublisher returns a publisher queueing up to queueSize
+// events for the given publisher.
+func NewAsyncCallbackPublisher(publisher CallbackPublisher, queueSize int) *AsyncCallbackPublisher {
+	p := &AsyncCallbackPublisher{
+		publisher: publisher,
+		queue:     make(chan asyncCallback, max(queueSize, 1)),
+		done:      make(chan struct{}),
+	}
+	go p.deliver()
+	return p
+}
+
+func (p *AsyncCallbackPublisher) Publish(ctx context.Context, event CallbackEvent) error {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	if p.closed {
+		return ErrCallbackPublisherClosed
+	}
+	// The event is delivered even if the run is canceled meanwhile.
+	select {
+	case p.queue <- asyncCallback{ctx: context.WithoutCancel(ctx), event: event}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.takeErrors()
+}
+
+// Flush waits until the events published so far are delivered, and returns
+// the errors of the failed deliveries.
+func (p *AsyncCallbackPublisher) Flush(ctx context.Context) error {
+	p.queueMu.Lock()
+	if p.closed {
+		p.queueMu.Unlock()
+		return ErrCallbackPublisherClosed
+	}
+	flushed := make(chan struct{})
+	select {
+	case p.queue <- asyncCallback{flushed: flushed}:
+	case <-ctx.Done():
+		p.queueMu.Unlock()
+		return ctx.Err()
+	}
+	p.queueMu.Unlock()
+
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.takeErrors()
+}
+
+// Close delivers the queued events and stops the publisher, returning the
+// errors of the failed deliveries. If ctx is done first, the remaining
+// events are delivered in the background.
+func (p *AsyncCallbackPublisher) Close(ctx context.Context) error {
+	p.queueMu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.queueMu.Unlock()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.takeErrors()
+}
+
+func (p *AsyncCallbackPublisher) deliver() {
+	defer close(p.done)
+	for callback := range p.queue {
+		if callback.flushed != nil {
+			close(callback.flushed)
+			continue
+		}
+		if err := p.publisher.Publish(callback.ctx, callback.event); err != nil {
+			p.errsMu.Lock()
+			p.errs = append(p.errs, err)
+			p.errsMu.Unlock()
+		}
+	}
+}
+
+// takeErrors returns and forgets the delivery errors.
+func (p *AsyncCallbackPublisher) takeErrors() error {
+	p.errsMu.Lock()
+	defer p.errsMu.Unlock()
+	err := errors.Join(p.errs...)
+	p.errs = nil
+	return err
+}
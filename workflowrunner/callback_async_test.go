@@ -0,0 +1,124 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher records the types of the events it receives, waiting
+// for release (when set) before each delivery and failing the events of the
+// types in fail.
+type recordingPublisher struct {
+	release chan struct{}
+	fail    map[string]error
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event CallbackEvent) error {
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	p.events = append(p.events, event.Type)
+	p.mu.Unlock()
+	return p.fail[event.Type]
+}
+
+func (p *recordingPublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.events...)
+}
+
+func TestAsyncCallbackPublisher_DeliversInOrder(t *testing.T) {
+	ctx := t.Context()
+	target := &recordingPublisher{}
+	p := NewAsyncCallbackPublisher(target, 2)
+
+	types := []string{"a", "b", "c", "d", "e"}
+	for _, typ := range types {
+		require.NoError(t, p.Publish(ctx, CallbackEvent{Type: typ}))
+	}
+	require.NoError(t, p.Flush(ctx))
+	assert.Equal(t, types, target.published())
+	require.NoError(t, p.Close(ctx))
+}
+
+func TestAsyncCallbackPublisher_PublishDoesNotWaitForDelivery(t *testing.T) {
+	ctx := t.Context()
+	target := &recordingPublisher{release: make(chan struct{})}
+	p := NewAsyncCallbackPublisher(target, 2)
+
+	// The first event is being delivered, the next two are queued.
+	for _, typ := range []string{"a", "b", "c"} {
+		require.NoError(t, p.Publish(ctx, CallbackEvent{Type: typ}))
+	}
+	assert.Empty(t, target.published())
+
+	// The queue is full: publishing waits until ctx is done.
+	fullCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Publish(fullCtx, CallbackEvent{Type: "d"}), context.DeadlineExceeded)
+
+	close(target.release)
+	require.NoError(t, p.Close(ctx))
+	assert.Equal(t, []string{"a", "b", "c"}, target.published())
+}
+
+func TestAsyncCallbackPublisher_ReportsDeliveryErrors(t *testing.T) {
+	ctx := t.Context()
+	errFailed := errors.New("failed")
+	target := &recordingPublisher{fail: map[string]error{"b": errFailed}}
+	p := NewAsyncCallbackPublisher(target, 4)
+
+	require.NoError(t, p.Publish(ctx, CallbackEvent{Type: "a"}))
+	require.NoError(t, p.Publish(ctx, CallbackEvent{Type: "b"}))
+	assert.ErrorIs(t, p.Flush(ctx), errFailed)
+
+	// The error is handed off once.
+	require.NoError(t, p.Publish(ctx, CallbackEvent{Type: "c"}))
+	require.NoError(t, p.Flush(ctx))
+
+	require.NoError(t, p.Publish(ctx, CallbackEvent{Type: "b"}))
+	assert.ErrorIs(t, p.Close(ctx), errFailed)
+}
+
+func TestAsyncCallbackPublisher_CloseDeliversQueuedEvents(t *testing.T) {
+	ctx := t.Context()
+	target := &recordingPublisher{release: make(chan struct{})}
+	p := NewAsyncCallbackPublisher(target, 4)
+
+	for _, typ := range []string{"a", "b", "c"} {
+		require.NoError(t, p.Publish(ctx, CallbackEvent{Type: typ}))
+	}
+
+	// Close gives up waiting when ctx is done, the delivery goes on.
+	closeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Close(closeCtx), context.DeadlineExceeded)
+	assert.ErrorIs(t, p.Publish(ctx, CallbackEvent{Type: "d"}), ErrCallbackPublisherClosed)
+	assert.ErrorIs(t, p.Flush(ctx), ErrCallbackPublisherClosed)
+
+	close(target.release)
+	require.NoError(t, p.Close(ctx))
+	assert.Equal(t, []string{"a", "b", "c"}, target.published())
+}
+
+func TestAsyncCallbackPublisher_DeliversAfterCancellation(t *testing.T) {
+	target := &recordingPublisher{}
+	p := NewAsyncCallbackPublisher(target, 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	require.NoError(t, p.Publish(ctx, CallbackEvent{Type: "a"}))
+	cancel()
+	require.NoError(t, p.Close(t.Context()))
+	assert.Equal(t, []string{"a"}, target.published())
+}
@@ -0,0 +1,288 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
+// CallbackEventSchemaVersion is the version of the schema of the callback
+// events, sent as CallbackEvent.SchemaVersion. It changes when payload
+// fields are removed or change meaning; fields may be added within a
+// version. See CallbackEventSchema.
+const CallbackEventSchemaVersion = 1
+
+// Types of the callback events, each with its payload type.
+const (
+	CallbackEventRunStarted   = "run.started"   // RunStartedPayload
+	CallbackEventRunResumed   = "run.resumed"   // RunStartedPayload
+	CallbackEventRunRecovered = "run.recovered" // RunStartedPayload
+	CallbackEventRunEvent     = "run.event"     // RunEventPayload
+	CallbackEventRunFanOut    = "run.fan_out"   // RunFanOutPayload
+	CallbackEventRunFanIn     = "run.fan_in"    // RunFanInPayload
+	CallbackEventRunRetry     = "run.retry"     // RunRetryPayload
+	CallbackEventRunSuspended = "run.suspended" // RunSuspendedPayload
+	CallbackEventRunIteration = "run.iteration" // RunIterationPayload
+	CallbackEventRunRouted    = "run.routed"    // RunRoutedPayload
+	CallbackEventRunCompleted = "run.completed" // RunCompletedPayload
+	CallbackEventRunFailed    = "run.failed"    // RunFailedPayload
+	CallbackEventRunTimeout   = "run.timeout"   // RunTimeoutPayload
+
+	CallbackEventGuardrailEvaluated = "guardrail.evaluated" // GuardrailEvaluatedPayload
+)
+
+// RunStartedPayload is the payload of the run.started events, and of the
+// run.resumed and run.recovered events of the runs continuing a suspended
+// or interrupted one.
+type RunStartedPayload struct {
+	Workflow string `json:"workflow"`
+	Session  string `json:"session"`
+	Query    string `json:"query"`
+	// Variants chosen for the agents declaring any, by agent.
+	Variants map[string]string `json:"variants,omitempty"`
+	// Agent continuing the run, for resumed and recovered runs.
+	Agent string `json:"agent,omitempty"`
+	// Turn of the snapshot a recovered run continues from.
+	Turn int `json:"turn,omitempty"`
+	// Approvals resolved since the run was suspended, for resumed runs.
+	Approvals []ApprovalDecisionState `json:"approvals,omitempty"`
+}
+
+// RunEventPayload is the payload of the run.event events, reporting the
+// stream events of the agents. It is one of the *EventPayload types, whose
+// EventKind tells their kind.
+type RunEventPayload interface {
+	isRunEventPayload()
+}
+
+// Kinds of the run.event events, each with its payload type.
+const (
+	RunEventKindRaw                         = "raw"                            // RawEventPayload
+	RunEventKindAgentUpdated                = "agent_updated"                  // AgentUpdatedEventPayload
+	RunEventKindReasoningSummaryDelta       = "reasoning_summary_delta"        // ReasoningSummaryDeltaEventPayload
+	RunEventKindImageGenerationPartialImage = "image_generation_partial_image" // ImageGenerationPartialImageEventPayload
+	RunEventKindRunItem                     = "run_item"                       // RunItemEventPayload
+	RunEventKindUnknown                     = "unknown"                        // UnknownEventPayload
+)
+
+// RawEventPayload reports the raw events of the model responses.
+type RawEventPayload struct {
+	EventKind string `json:"event_kind" jsonschema:"enum=raw"`
+	// Type of the response event.
+	Type string `json:"type"`
+	// Data is the JSON of the response event, unless it is larger than the
+	// inline limit of the artifact store.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Size of the data omitted in favor of the artifacts.
+	DataOmittedBytes int    `json:"data_omitted_bytes,omitempty"`
+	MarshalError     string `json:"marshal_error,omitempty"`
+}
+
+// AgentUpdatedEventPayload reports the agent taking over the run.
+type AgentUpdatedEventPayload struct {
+	EventKind string `json:"event_kind" jsonschema:"enum=agent_updated"`
+	AgentName string `json:"agent_name"`
+}
+
+// ReasoningSummaryDeltaEventPayload reports the reasoning summary deltas of
+// reasoning models.
+type ReasoningSummaryDeltaEventPayload struct {
+	EventKind    string `json:"event_kind" jsonschema:"enum=reasoning_summary_delta"`
+	Agent        string `json:"agent"`
+	ItemID       string `json:"item_id"`
+	SummaryIndex int64  `json:"summary_index"`
+	Delta        string `json:"delta"`
+}
+
+// ImageGenerationPartialImageEventPayload reports the partial images of the
+// image_generation tools declaring partial_images.
+type ImageGenerationPartialImageEventPayload struct {
+	EventKind         string `json:"event_kind" jsonschema:"enum=image_generation_partial_image"`
+	Agent             string `json:"agent"`
+	ItemID            string `json:"item_id"`
+	PartialImageIndex int64  `json:"partial_image_index"`
+	// PartialImageB64 is omitted when the image is stored as Artifact.
+	PartialImageB64 string    `json:"partial_image_b64,omitempty"`
+	Artifact        *Artifact `json:"artifact,omitempty"`
+}
+
+// RunItemEventPayload reports the items generated by the run.
+type RunItemEventPayload struct {
+	EventKind string `json:"event_kind" jsonschema:"enum=run_item"`
+	// Name of the stream event, e.g. "message_output_created".
+	Name string `json:"name"`
+	// Item has the fields of the items of SerializedRunItems.
+	Item map[string]any `json:"item"`
+}
+
+// UnknownEventPayload reports the stream events of unknown types.
+type UnknownEventPayload struct {
+	EventKind string `json:"event_kind" jsonschema:"enum=unknown"`
+	// Go type of the event.
+	Type string `json:"type"`
+}
+
+func (*RawEventPayload) isRunEventPayload()                         {}
+func (*AgentUpdatedEventPayload) isRunEventPayload()                {}
+func (*ReasoningSummaryDeltaEventPayload) isRunEventPayload()       {}
+func (*ImageGenerationPartialImageEventPayload) isRunEventPayload() {}
+func (*RunItemEventPayload) isRunEventPayload()                     {}
+func (*UnknownEventPayload) isRunEventPayload()                     {}
+
+// GuardrailEvaluatedPayload is the payload of the guardrail.evaluated
+// events, published for each guardrail evaluated by a run.
+type GuardrailEvaluatedPayload = GuardrailResultState
+
+// RunFanOutPayload is the payload of the run.fan_out events.
+type RunFanOutPayload struct {
+	// Agent aggregating the outputs of the fan-out.
+	Agent string `json:"agent"`
+	// Agents run in parallel.
+	Agents []string `json:"agents"`
+}
+
+// RunFanInPayload is the payload of the run.fan_in events.
+type RunFanInPayload struct {
+	Agent   string        `json:"agent"`
+	Outputs []FanInOutput `json:"outputs"`
+}
+
+// FanInOutput is the final output of an agent run by a fan-out.
+type FanInOutput struct {
+	Agent  string `json:"agent"`
+	Output any    `json:"output"`
+}
+
+// RunRetryPayload is the payload of the run.retry events.
+type RunRetryPayload struct {
+	Agent string `json:"agent"`
+	// Attempt about to start, from 2.
+	Attempt    int    `json:"attempt"`
+	Error      string `json:"error"`
+	ErrorClass string `json:"error_class"`
+	BackoffMS  int64  `json:"backoff_ms"`
+}
+
+// RunSuspendedPayload is the payload of the run.suspended events.
+type RunSuspendedPayload struct {
+	Agent            string                 `json:"agent"`
+	PendingApprovals []ApprovalRequestState `json:"pending_approvals"`
+	PendingInputs    []InputRequestState    `json:"pending_inputs"`
+	LastResponseID   string                 `json:"last_response_id"`
+}
+
+// RunIterationPayload is the payload of the run.iteration events.
+type RunIterationPayload struct {
+	Agent         string `json:"agent"`
+	Iteration     int    `json:"iteration"`
+	MaxIterations int    `json:"max_iterations"`
+	// Output guardrail whose tripwire repeated the agent, if any.
+	Guardrail string `json:"guardrail"`
+}
+
+// RunRoutedPayload is the payload of the run.routed events.
+type RunRoutedPayload struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Index of the route among the ones of the From agent.
+	Route int `json:"route"`
+	// Output guardrail whose tripwire selected the route, if any.
+	Guardrail string `json:"guardrail"`
+	// Number of times the From agent ran in a row.
+	Iterations int `json:"iterations"`
+}
+
+// RunCompletedPayload is the payload of the run.completed events.
+type RunCompletedPayload struct {
+	FinalOutput    any                 `json:"final_output"`
+	LastResponseID string              `json:"last_response_id"`
+	Usage          *RunUsage           `json:"usage"`
+	Variants       map[string]string   `json:"variants,omitempty"`
+	Artifacts      []Artifact          `json:"artifacts,omitempty"`
+	Items          *SerializedRunItems `json:"items,omitempty"`
+}
+
+// RunFailedPayload is the payload of the run.failed events.
+type RunFailedPayload struct {
+	Error string `json:"error"`
+}
+
+// RunTimeoutPayload is the payload of the run.timeout events.
+type RunTimeoutPayload struct {
+	Error           string `json:"error"`
+	DeadlineSeconds int    `json:"deadline_seconds"`
+	Agent           string `json:"agent"`
+	Iteration       int    `json:"iteration"`
+	RouteHops       int    `json:"route_hops"`
+	// The last response and number of items of the interrupted step, if
+	// it started.
+	LastResponseID string `json:"last_response_id,omitempty"`
+	NewItems       int    `json:"new_items,omitempty"`
+}
+
+// newCallbackEvent returns an event of the given type and payload, see the
+// CallbackEvent* constants.
+func newCallbackEvent(timestamp time.Time, eventType string, payload any) CallbackEvent {
+	return CallbackEvent{
+		Type:          eventType,
+		SchemaVersion: CallbackEventSchemaVersion,
+		Timestamp:     timestamp,
+		Payload:       payload,
+	}
+}
+
+// callbackEventPayloads maps the types of the callback events to their
+// payloads, for CallbackEventSchema. The run.event events have a payload
+// for each kind.
+var callbackEventPayloads = []struct {
+	eventType string
+	payload   any
+}{
+	{CallbackEventRunStarted, &RunStartedPayload{}},
+	{CallbackEventRunResumed, &RunStartedPayload{}},
+	{CallbackEventRunRecovered, &RunStartedPayload{}},
+	{CallbackEventRunEvent, &RawEventPayload{}},
+	{CallbackEventRunEvent, &AgentUpdatedEventPayload{}},
+	{CallbackEventRunEvent, &ReasoningSummaryDeltaEventPayload{}},
+	{CallbackEventRunEvent, &ImageGenerationPartialImageEventPayload{}},
+	{CallbackEventRunEvent, &RunItemEventPayload{}},
+	{CallbackEventRunEvent, &UnknownEventPayload{}},
+	{CallbackEventRunFanOut, &RunFanOutPayload{}},
+	{CallbackEventRunFanIn, &RunFanInPayload{}},
+	{CallbackEventRunRetry, &RunRetryPayload{}},
+	{CallbackEventRunSuspended, &RunSuspendedPayload{}},
+	{CallbackEventRunIteration, &RunIterationPayload{}},
+	{CallbackEventRunRouted, &RunRoutedPayload{}},
+	{CallbackEventRunCompleted, &RunCompletedPayload{}},
+	{CallbackEventRunFailed, &RunFailedPayload{}},
+	{CallbackEventRunTimeout, &RunTimeoutPayload{}},
+	{CallbackEventGuardrailEvaluated, &GuardrailEvaluatedPayload{}},
+}
+
+// CallbackEventSchema returns the JSON Schema of the callback events, whose
+// payload depends on their type.
+func CallbackEventSchema() *jsonschema.Schema {
+	reflector := newSchemaReflector()
+	schema := reflector.Reflect(&CallbackEvent{})
+	event := schema.Definitions["CallbackEvent"]
+	for _, p := range callbackEventPayloads {
+		payload := reflector.Reflect(p.payload)
+		for name, def := range payload.Definitions {
+			schema.Definitions[name] = def
+		}
+		if payload.Ref == "" {
+			payload.Version = ""
+		} else {
+			payload = &jsonschema.Schema{Ref: payload.Ref}
+		}
+		props := jsonschema.NewProperties()
+		props.Set("type", &jsonschema.Schema{Const: p.eventType})
+		props.Set("payload", payload)
+		event.OneOf = append(event.OneOf, &jsonschema.Schema{
+			Properties: props,
+			Required:   []string{"type", "payload"},
+		})
+	}
+	return schema
+}
@@ -0,0 +1,72 @@
+package workflowrunner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEventPayloads(t *testing.T) {
+	ctx := t.Context()
+	agent := &agents.Agent{Name: "painter"}
+	image := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 64)))
+	event := agents.ImageGenerationPartialImageStreamEvent{
+		Agent:             agent,
+		ItemID:            "ig_1",
+		PartialImageIndex: 1,
+		PartialImageB64:   image,
+	}
+
+	payload := serializeStreamEvent(event)
+	require.IsType(t, &ImageGenerationPartialImageEventPayload{}, payload)
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"event_kind": "image_generation_partial_image",
+		"agent": "painter",
+		"item_id": "ig_1",
+		"partial_image_index": 1,
+		"partial_image_b64": "`+image+`"
+	}`, string(data))
+
+	// Stored images are replaced by their artifact.
+	artifacts := newRunArtifacts(NewDirArtifactStore(t.TempDir()), 16, "s1", RandomIDGenerator{}, nil)
+	require.NoError(t, artifacts.storeEvent(ctx, event, payload))
+	partial := payload.(*ImageGenerationPartialImageEventPayload)
+	assert.Empty(t, partial.PartialImageB64)
+	require.NotNil(t, partial.Artifact)
+	assert.Equal(t, "ig_1_partial_1", partial.Artifact.Name)
+	assert.Equal(t, artifacts.list(), []Artifact{*partial.Artifact})
+
+	payload = serializeStreamEvent(agents.AgentUpdatedStreamEvent{NewAgent: agent})
+	assert.Equal(t, &AgentUpdatedEventPayload{EventKind: RunEventKindAgentUpdated, AgentName: "painter"}, payload)
+}
+
+func TestCallbackEventSchemaRunEvents(t *testing.T) {
+	schema := CallbackEventSchema()
+	var kinds []any
+	for _, variant := range schema.Definitions["CallbackEvent"].OneOf {
+		typ, _ := variant.Properties.Get("type")
+		if typ.Const != CallbackEventRunEvent {
+			continue
+		}
+		payload, _ := variant.Properties.Get("payload")
+		def := schema.Definitions[strings.TrimPrefix(payload.Ref, "#/$defs/")]
+		require.NotNil(t, def, payload.Ref)
+		kind, _ := def.Properties.Get("event_kind")
+		kinds = append(kinds, kind.Enum...)
+	}
+	assert.ElementsMatch(t, []any{
+		RunEventKindRaw,
+		RunEventKindAgentUpdated,
+		RunEventKindReasoningSummaryDelta,
+		RunEventKindImageGenerationPartialImage,
+		RunEventKindRunItem,
+		RunEventKindUnknown,
+	}, kinds)
+}
@@ -0,0 +1,195 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Defaults of CallbackRetryPolicy.
+const (
+	DefaultCallbackMaxAttempts    = 3
+	DefaultCallbackInitialBackoff = 200 * time.Millisecond
+	DefaultCallbackMaxBackoff     = 10 * time.Second
+	DefaultCallbackBackoffFactor  = 2.0
+)
+
+// CallbackStatusError is returned by HTTPCallbackPublisher when the endpoint
+// answers with a non-successful status.
+type CallbackStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *CallbackStatusError) Error() string {
+	return fmt.Sprintf("callback returned status %s", e.Status)
+}
+
+// isRetryableCallbackError reports whether delivering the event again may
+// succeed. Client errors are permanent, except for timeouts and rate limits.
+func isRetryableCallbackError(err error) bool {
+	var statusErr *CallbackStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 ||
+			statusErr.StatusCode == http.StatusRequestTimeout ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// DeadLetterSink receives the callback events which could not be delivered.
+type DeadLetterSink interface {
+	DeadLetter(ctx context.Context, event CallbackEvent, cause error) error
+}
+
+// DeadLetterSinkFunc adapts a function to the DeadLetterSink interface, e.g.
+// to push undelivered events to a queue.
+type DeadLetterSinkFunc func(ctx context.Context, event CallbackEvent, cause error) error
+
+func (f DeadLetterSinkFunc) DeadLetter(ctx context.Context, event CallbackEvent, cause error) error {
+	return f(ctx, event, cause)
+}
+
+// DeadLetter is an undelivered event, as written by FileDeadLetterSink.
+type DeadLetter struct {
+	Event    CallbackEvent `json:"event"`
+	Target   string        `json:"target,omitempty"`
+	Error    string        `json:"error"`
+	Attempts int           `json:"attempts"`
+	FailedAt time.Time     `json:"failed_at"`
+}
+
+// FileDeadLetterSink appends undelivered events to a file, one DeadLetter
+// JSON object per line.
+type FileDeadLetterSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *FileDeadLetterSink) DeadLetter(_ context.Context, event CallbackEvent, cause error) error {
+	letter := DeadLetter{Event: event, Error: cause.Error(), FailedAt: time.Now().UTC()}
+	var deliveryErr *CallbackDeliveryError
+	if errors.As(cause, &deliveryErr) {
+		letter.Target = deliveryErr.Target
+		letter.Attempts = deliveryErr.Attempts
+	}
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("marshal dead letter: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open dead letter file: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write dead letter: %w", err)
+	}
+	return f.Close()
+}
+
+// CallbackDeliveryError is returned by RetryingCallbackPublisher when an event
+// could not be delivered.
+type CallbackDeliveryError struct {
+	Target   string
+	Attempts int
+	Err      error
+}
+
+func (e *CallbackDeliveryError) Error() string {
+	return fmt.Sprintf("callback delivery failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *CallbackDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryingCallbackPublisher retries the events its publisher fails to
+// deliver with exponential backoff, and hands the events which permanently
+// failed to the dead-letter sink, if any.
+type RetryingCallbackPublisher struct {
+	Publisher CallbackPublisher
+	Policy    CallbackRetryPolicy
+	// Target is recorded in the errors and dead letters.
+	Target     string
+	DeadLetter DeadLetterSink
+}
+
+func (p *RetryingCallbackPublisher) Publish(ctx context.Context, event CallbackEvent) error {
+	maxAttempts := p.Policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultCallbackMaxAttempts
+	}
+	var (
+		err      error
+		attempts int
+	)
+	for attempts < maxAttempts {
+		if attempts > 0 {
+			timer := time.NewTimer(p.Policy.backoff(attempts))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				err = errors.Join(err, ctx.Err())
+				return p.deadLetter(ctx, event, &CallbackDeliveryError{Target: p.Target, Attempts: attempts, Err: err})
+			case <-timer.C:
+			}
+		}
+		attempts++
+		if err = p.Publisher.Publish(ctx, event); err == nil {
+			return nil
+		}
+		if !isRetryableCallbackError(err) {
+			break
+		}
+	}
+	return p.deadLetter(ctx, event, &CallbackDeliveryError{Target: p.Target, Attempts: attempts, Err: err})
+}
+
+func (p *RetryingCallbackPublisher) deadLetter(ctx context.Context, event CallbackEvent, cause *CallbackDeliveryError) error {
+	if p.DeadLetter == nil {
+		return cause
+	}
+	// The run may have been canceled, the event must be recorded anyway.
+	if err := p.DeadLetter.DeadLetter(context.WithoutCancel(ctx), event, cause); err != nil {
+		return errors.Join(cause, fmt.Errorf("dead letter: %w", err))
+	}
+	return cause
+}
+
+// backoff returns the delay before the given retry, starting from 1.
+func (p CallbackRetryPolicy) backoff(retry int) time.Duration {
+	initial := DefaultCallbackInitialBackoff
+	if p.InitialBackoffMS > 0 {
+		initial = time.Duration(p.InitialBackoffMS) * time.Millisecond
+	}
+	maxBackoff := DefaultCallbackMaxBackoff
+	if p.MaxBackoffMS > 0 {
+		maxBackoff = time.Duration(p.MaxBackoffMS) * time.Millisecond
+	}
+	factor := DefaultCallbackBackoffFactor
+	if p.BackoffFactor >= 1 {
+		factor = p.BackoffFactor
+	}
+	return exponentialBackoff(retry, initial, maxBackoff, factor)
+}
+
+// exponentialBackoff returns the delay before the given retry, starting
+// from 1, with up to 20% of jitter so that failures are not retried in
+// bursts.
+func exponentialBackoff(retry int, initial, maxBackoff time.Duration, factor float64) time.Duration {
+	delay := float64(initial)
+	for i := 1; i < retry && delay < float64(maxBackoff); i++ {
+		delay *= factor
+	}
+	delay = min(delay, float64(maxBackoff))
+	return time.Duration(delay * (0.8 + 0.2*rand.Float64()))
+}
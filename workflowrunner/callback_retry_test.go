@@ -0,0 +1,166 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingPublisher fails the first len(errs) deliveries with errs, in order,
+// and calls onPublish, if set, on each of them.
+type failingPublisher struct {
+	errs      []error
+	onPublish func(attempt int)
+
+	mu       sync.Mutex
+	attempts int
+}
+
+func (p *failingPublisher) Publish(context.Context, CallbackEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	if p.onPublish != nil {
+		p.onPublish(p.attempts)
+	}
+	if p.attempts <= len(p.errs) {
+		return p.errs[p.attempts-1]
+	}
+	return nil
+}
+
+func statusError(code int) error {
+	return &CallbackStatusError{StatusCode: code, Status: http.StatusText(code)}
+}
+
+func TestRetryingCallbackPublisher(t *testing.T) {
+	unavailable := statusError(http.StatusServiceUnavailable)
+	policy := CallbackRetryPolicy{MaxAttempts: 3, InitialBackoffMS: 1, MaxBackoffMS: 2}
+
+	tests := []struct {
+		name     string
+		errs     []error
+		attempts int
+		err      error
+	}{
+		{name: "delivered", attempts: 1},
+		{name: "delivered after retries", errs: []error{unavailable, errors.New("connection reset")}, attempts: 3},
+		{name: "gives up", errs: []error{unavailable, unavailable, unavailable, nil}, attempts: 3, err: unavailable},
+		{name: "rate limited", errs: []error{statusError(http.StatusTooManyRequests)}, attempts: 2},
+		{name: "timed out", errs: []error{statusError(http.StatusRequestTimeout)}, attempts: 2},
+		{name: "client error", errs: []error{statusError(http.StatusBadRequest)}, attempts: 1, err: statusError(http.StatusBadRequest)},
+		{name: "canceled", errs: []error{context.Canceled}, attempts: 1, err: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &failingPublisher{errs: tt.errs}
+			var letters []error
+			publisher := &RetryingCallbackPublisher{
+				Publisher: inner,
+				Policy:    policy,
+				Target:    "https://example.com/hook",
+				DeadLetter: DeadLetterSinkFunc(func(_ context.Context, _ CallbackEvent, cause error) error {
+					letters = append(letters, cause)
+					return nil
+				}),
+			}
+			err := publisher.Publish(t.Context(), CallbackEvent{Type: CallbackEventRunStarted})
+			assert.Equal(t, tt.attempts, inner.attempts)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Empty(t, letters)
+				return
+			}
+			var deliveryErr *CallbackDeliveryError
+			require.ErrorAs(t, err, &deliveryErr)
+			assert.Equal(t, tt.attempts, deliveryErr.Attempts)
+			assert.Equal(t, "https://example.com/hook", deliveryErr.Target)
+			// The error of the last attempt is returned.
+			assert.Equal(t, tt.err, deliveryErr.Err)
+			assert.Equal(t, []error{err}, letters)
+		})
+	}
+}
+
+func TestRetryingCallbackPublisherDefaultsToThreeAttempts(t *testing.T) {
+	inner := &failingPublisher{errs: []error{errors.New("a"), errors.New("b"), errors.New("c"), errors.New("d")}}
+	publisher := &RetryingCallbackPublisher{Publisher: inner, Policy: CallbackRetryPolicy{InitialBackoffMS: 1}}
+	err := publisher.Publish(t.Context(), CallbackEvent{})
+	assert.EqualError(t, err, "callback delivery failed after 3 attempts: c")
+	assert.Equal(t, DefaultCallbackMaxAttempts, inner.attempts)
+}
+
+func TestRetryingCallbackPublisherStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	unavailable := statusError(http.StatusServiceUnavailable)
+	inner := &failingPublisher{
+		errs: []error{unavailable, unavailable, unavailable},
+		// The context is canceled while waiting to retry.
+		onPublish: func(int) { cancel() },
+	}
+	var dead []CallbackEvent
+	publisher := &RetryingCallbackPublisher{
+		Publisher: inner,
+		Policy:    CallbackRetryPolicy{MaxAttempts: 3, InitialBackoffMS: int(time.Hour / time.Millisecond)},
+		DeadLetter: DeadLetterSinkFunc(func(ctx context.Context, event CallbackEvent, _ error) error {
+			// Dead letters are recorded even though the run was canceled.
+			if ctx.Err() == nil {
+				dead = append(dead, event)
+			}
+			return nil
+		}),
+	}
+
+	start := time.Now()
+	err := publisher.Publish(ctx, CallbackEvent{Type: CallbackEventRunFailed})
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, 1, inner.attempts)
+	var deliveryErr *CallbackDeliveryError
+	require.ErrorAs(t, err, &deliveryErr)
+	assert.Equal(t, 1, deliveryErr.Attempts)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, unavailable)
+	assert.Equal(t, []CallbackEvent{{Type: CallbackEventRunFailed}}, dead)
+}
+
+func TestRetryingCallbackPublisherReportsDeadLetterErrors(t *testing.T) {
+	publisher := &RetryingCallbackPublisher{
+		Publisher: &failingPublisher{errs: []error{statusError(http.StatusBadRequest)}},
+		DeadLetter: DeadLetterSinkFunc(func(context.Context, CallbackEvent, error) error {
+			return errors.New("disk full")
+		}),
+	}
+	err := publisher.Publish(t.Context(), CallbackEvent{})
+	var deliveryErr *CallbackDeliveryError
+	assert.ErrorAs(t, err, &deliveryErr)
+	assert.ErrorContains(t, err, "dead letter: disk full")
+}
+
+func TestCallbackRetryPolicyBackoff(t *testing.T) {
+	policy := CallbackRetryPolicy{InitialBackoffMS: 100, MaxBackoffMS: 1000, BackoffFactor: 3}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 300 * time.Millisecond,
+		3: 900 * time.Millisecond,
+		4: 1000 * time.Millisecond,
+		9: 1000 * time.Millisecond,
+	} {
+		// Up to 20% of jitter is subtracted.
+		for range 20 {
+			backoff := policy.backoff(retry)
+			assert.LessOrEqual(t, backoff, want, "retry %d", retry)
+			assert.GreaterOrEqual(t, backoff, want*8/10, "retry %d", retry)
+		}
+	}
+
+	// Defaults apply to unset and invalid values.
+	backoff := CallbackRetryPolicy{BackoffFactor: 0.5}.backoff(2)
+	assert.LessOrEqual(t, backoff, 2*DefaultCallbackInitialBackoff)
+	assert.GreaterOrEqual(t, backoff, 2*DefaultCallbackInitialBackoff*8/10)
+}
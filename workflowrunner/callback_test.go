@@ -0,0 +1,123 @@
+package workflowrunner
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackSignature(t *testing.T) {
+	secret := []byte("s3cr3t")
+	body := []byte(`{"type":"run.completed"}`)
+	now := time.Now()
+	header := SignCallbackPayload(secret, body, now)
+	ts, v1, _ := strings.Cut(header, ",v1=")
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		body      []byte
+		header    string
+		tolerance time.Duration
+		valid     bool
+	}{
+		{name: "round trip", header: header, valid: true},
+		{name: "spaces", header: strings.ReplaceAll(header, ",", ", "), valid: true},
+		{name: "tampered body", body: []byte(`{"type":"run.failed"}`), header: header},
+		{name: "other secret", secret: []byte("other"), header: header},
+		{name: "tampered signature", header: header[:len(header)-1] + flipHex(header[len(header)-1:])},
+		{name: "tampered timestamp", header: "t=" + strconv.FormatInt(now.Unix()-1, 10) + ",v1=" + v1},
+		{name: "too old", header: SignCallbackPayload(secret, body, now.Add(-6*time.Minute))},
+		{name: "in the future", header: SignCallbackPayload(secret, body, now.Add(6*time.Minute))},
+		{name: "custom tolerance", header: SignCallbackPayload(secret, body, now.Add(-6*time.Minute)), tolerance: 10 * time.Minute, valid: true},
+		{name: "below custom tolerance", header: SignCallbackPayload(secret, body, now.Add(-2*time.Minute)), tolerance: time.Minute},
+		{name: "empty", header: ""},
+		{name: "missing timestamp", header: "v1=" + v1},
+		{name: "malformed timestamp", header: "t=yesterday,v1=" + v1},
+		{name: "missing signature", header: ts},
+		{name: "malformed signature", header: ts + ",v1=not-hex"},
+		{name: "other scheme", header: strings.Replace(header, "v1=", "v0=", 1)},
+		{name: "multiple signatures, one valid", header: ts + ",v1=" + flipHex(v1) + ",v1=" + v1, valid: true},
+		{name: "multiple signatures, none valid", header: ts + ",v1=" + flipHex(v1) + ",v1=00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.secret == nil {
+				tt.secret = secret
+			}
+			if tt.body == nil {
+				tt.body = body
+			}
+			err := VerifyCallbackSignature(tt.secret, tt.body, tt.header, tt.tolerance)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidCallbackSignature)
+			}
+		})
+	}
+}
+
+// flipHex changes every hex digit of s.
+func flipHex(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '0' {
+			return '1'
+		}
+		return '0'
+	}, s)
+}
+
+// TestVerifyCallbackSignatureComparesInConstantTime checks that signatures
+// are compared with hmac.Equal, not to leak their bytes through timing.
+func TestVerifyCallbackSignatureComparesInConstantTime(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "callback.go", nil, 0)
+	require.NoError(t, err)
+	var verify *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "VerifyCallbackSignature" {
+			verify = fn
+		}
+	}
+	require.NotNil(t, verify)
+
+	var calls []string
+	ast.Inspect(verify.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				calls = append(calls, pkg.Name+"."+sel.Sel.Name)
+			}
+		}
+		return true
+	})
+	assert.Contains(t, calls, "hmac.Equal")
+	assert.NotContains(t, calls, "bytes.Equal")
+}
+
+func TestHTTPCallbackPublisherSignsRequests(t *testing.T) {
+	secret := []byte("s3cr3t")
+	received := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			err = VerifyCallbackSignature(secret, body, r.Header.Get(CallbackSignatureHeader), 0)
+		}
+		received <- err
+	}))
+	defer server.Close()
+
+	publisher := NewHTTPCallbackPublisher(server.URL, nil)
+	publisher.SigningSecret = secret
+	require.NoError(t, publisher.Publish(t.Context(), CallbackEvent{Type: "run.started", Timestamp: time.Now()}))
+	assert.NoError(t, <-received)
+}
@@ -0,0 +1,74 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// ErrMissingCapabilities is returned, wrapped in a *CapabilityError, when the
+// credentials of a request lack the capabilities required by its workflow or
+// by one of its tools.
+var ErrMissingCapabilities = errors.New("missing capabilities")
+
+// CapabilityError reports the capabilities the caller lacks.
+type CapabilityError struct {
+	// Subject is what requires the capabilities, e.g. `workflow "support"`.
+	Subject string
+	Missing []string
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("%s requires capabilities the caller lacks: %s", e.Subject, strings.Join(e.Missing, ", "))
+}
+
+func (e *CapabilityError) Unwrap() error { return ErrMissingCapabilities }
+
+// missingCapabilities returns the required capabilities not granted.
+func missingCapabilities(granted, required []string) []string {
+	var missing []string
+	for _, capability := range required {
+		if !slices.Contains(granted, capability) && !slices.Contains(missing, capability) {
+			missing = append(missing, capability)
+		}
+	}
+	return missing
+}
+
+// authorizeWorkflow checks the capabilities required by the workflow.
+func authorizeWorkflow(req WorkflowRequest) error {
+	missing := missingCapabilities(req.Session.Credentials.Capabilities, req.Workflow.RequiredCapabilities)
+	if len(missing) > 0 {
+		return &CapabilityError{Subject: fmt.Sprintf("workflow %q", req.Workflow.Name), Missing: missing}
+	}
+	return nil
+}
+
+// authorizeTools returns the tools of the agent the caller is granted. Tools
+// lacking capabilities fail the build, unless Builder.StripUnauthorizedTools
+// is set, in which case they are left out of the agent.
+func (b *Builder) authorizeTools(ctx context.Context, req WorkflowRequest, agentName string, tools []ToolDeclaration) ([]ToolDeclaration, error) {
+	authorized := tools[:0:0]
+	for _, tool := range tools {
+		missing := missingCapabilities(req.Session.Credentials.Capabilities, tool.RequiredCapabilities)
+		if len(missing) == 0 {
+			authorized = append(authorized, tool)
+			continue
+		}
+		subject := fmt.Sprintf("agent %q tool %q", agentName, cmp.Or(tool.Name, tool.Type))
+		if !b.StripUnauthorizedTools {
+			return nil, &CapabilityError{Subject: subject, Missing: missing}
+		}
+		agents.Logger().InfoContext(ctx, "tool removed: missing capabilities",
+			slog.String("workflow", req.Workflow.Name),
+			slog.String("tool", subject),
+			slog.String("missing", strings.Join(missing, ",")))
+	}
+	return authorized, nil
+}
@@ -0,0 +1,101 @@
+package workflowrunner
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAuthorizesTools(t *testing.T) {
+	request := func(capabilities ...string) WorkflowRequest {
+		req := testRequest("s1")
+		req.Session.Credentials.Capabilities = capabilities
+		agent := &req.Workflow.Agents[0]
+		agent.Model = nil
+		agent.Tools = []ToolDeclaration{
+			{Type: "code_interpreter"},
+			{Type: "web_search", RequiredCapabilities: []string{"search"}},
+		}
+		agent.MCPServers = []MCPDeclaration{
+			{ServerLabel: "docs", Address: "https://docs.example.com/mcp"},
+			{ServerLabel: "crm", Address: "https://crm.example.com/mcp", RequiredCapabilities: []string{"crm:read", "crm:write"}},
+		}
+		return req
+	}
+	// toolNames returns the names of the tools of the agent, and the labels
+	// of its MCP servers.
+	toolNames := func(result *BuildResult) []string {
+		var names []string
+		for _, tool := range result.StartingAgent.Tools {
+			if mcp, ok := tool.(agents.HostedMCPTool); ok {
+				names = append(names, mcp.ToolConfig.ServerLabel)
+			} else {
+				names = append(names, tool.ToolName())
+			}
+		}
+		return names
+	}
+	allTools := []string{"code_interpreter", "web_search", "docs", "crm"}
+
+	tests := []struct {
+		name         string
+		strip        bool
+		capabilities []string
+		// Either the built tools or the error.
+		tools   []string
+		subject string
+		missing []string
+	}{
+		{name: "granted", capabilities: []string{"search", "crm:read", "crm:write"}, tools: allTools},
+		{name: "granted, stripping", strip: true, capabilities: []string{"search", "crm:read", "crm:write"}, tools: allTools},
+		{name: "tool not granted", capabilities: []string{"crm:read", "crm:write"}, subject: `agent "assistant" tool "web_search"`, missing: []string{"search"}},
+		{name: "MCP server not granted", capabilities: []string{"search", "crm:read"}, subject: `agent "assistant" tool "crm"`, missing: []string{"crm:write"}},
+		{name: "tool stripped", strip: true, capabilities: []string{"crm:read", "crm:write"}, tools: []string{"code_interpreter", "docs", "crm"}},
+		{name: "MCP server stripped", strip: true, capabilities: []string{"search", "crm:write"}, tools: []string{"code_interpreter", "web_search", "docs"}},
+		{name: "all stripped", strip: true, tools: []string{"code_interpreter", "docs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newTestBuilder(t)
+			builder.StripUnauthorizedTools = tt.strip
+			result, err := builder.Build(t.Context(), request(tt.capabilities...))
+			if tt.tools == nil {
+				var capErr *CapabilityError
+				require.ErrorAs(t, err, &capErr)
+				assert.ErrorIs(t, err, ErrMissingCapabilities)
+				assert.Equal(t, tt.subject, capErr.Subject)
+				assert.Equal(t, tt.missing, capErr.Missing)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { closeSession(result.Session) })
+			assert.Equal(t, tt.tools, toolNames(result))
+		})
+	}
+}
+
+func TestBuildAuthorizesWorkflow(t *testing.T) {
+	for _, strip := range []bool{false, true} {
+		builder := newTestBuilder(t)
+		builder.StripUnauthorizedTools = strip
+		req := testRequest("s1")
+		req.Workflow.Agents[0].Model = nil
+		req.Session.Credentials.Capabilities = []string{"support"}
+		req.Workflow.RequiredCapabilities = []string{"support", "billing", "billing"}
+
+		// Workflows lacking capabilities fail even when stripping tools.
+		_, err := builder.Build(t.Context(), req)
+		var capErr *CapabilityError
+		require.ErrorAs(t, err, &capErr)
+		assert.Equal(t, `workflow "wf"`, capErr.Subject)
+		assert.Equal(t, []string{"billing"}, capErr.Missing)
+		assert.EqualError(t, capErr, `workflow "wf" requires capabilities the caller lacks: billing`)
+
+		req.Session.Credentials.Capabilities = append(req.Session.Credentials.Capabilities, "billing")
+		result, err := builder.Build(t.Context(), req)
+		require.NoError(t, err)
+		closeSession(result.Session)
+	}
+}
@@ -0,0 +1,65 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/computer"
+)
+
+// ComputerFactory returns the computer controlled by a computer tool.
+type ComputerFactory func(ctx context.Context, decl ToolDeclaration, env ToolFactoryEnv) (computer.Computer, error)
+
+// ComputerToolFactory returns a factory for computer tools controlling the
+// computers of newComputer. It is not registered by default, as workflows
+// cannot declare a computer implementation; register it e.g. as
+// Builder.ToolFactories["computer"].
+//
+// The declaration may set the environment ("browser", "mac", "windows",
+// "ubuntu" or "linux") and the display_width and display_height declared to
+// the model instead of those of the computer. Pending safety checks fail the
+// run unless acknowledge_safety_checks is true, or lists the codes of the
+// checks to acknowledge.
+func ComputerToolFactory(newComputer ComputerFactory) ToolFactory {
+	return func(ctx context.Context, decl ToolDeclaration, env ToolFactoryEnv) (agents.Tool, error) {
+		tool := agents.ComputerTool{}
+		if environment, ok := getString(decl.Config, "environment"); ok {
+			switch e := computer.Environment(environment); e {
+			case computer.EnvironmentBrowser, computer.EnvironmentMac, computer.EnvironmentWindows,
+				computer.EnvironmentUbuntu, computer.EnvironmentLinux:
+				tool.Environment = e
+			default:
+				return nil, fmt.Errorf("unsupported computer environment %q", environment)
+			}
+		}
+		width, hasWidth := getFloat(decl.Config, "display_width")
+		height, hasHeight := getFloat(decl.Config, "display_height")
+		if hasWidth || hasHeight {
+			if width <= 0 || height <= 0 {
+				return nil, errors.New("computer display_width and display_height must both be positive")
+			}
+			tool.Dimensions = computer.Dimensions{Width: int64(width), Height: int64(height)}
+		}
+		tool.OnSafetyCheck = rejectSafetyChecks
+		if ack, ok := getBool(decl.Config, "acknowledge_safety_checks"); ok {
+			if ack {
+				tool.OnSafetyCheck = agents.AcknowledgeSafetyChecks()
+			}
+		} else if codes, ok := getSlice[string](decl.Config, "acknowledge_safety_checks"); ok && len(codes) > 0 {
+			tool.OnSafetyCheck = agents.AcknowledgeSafetyChecks(codes...)
+		}
+
+		c, err := newComputer(ctx, decl, env)
+		if err != nil {
+			return nil, fmt.Errorf("create computer: %w", err)
+		}
+		tool.Computer = c
+		return tool, nil
+	}
+}
+
+func rejectSafetyChecks(context.Context, agents.ComputerToolSafetyCheckData) (bool, error) {
+	return false, nil
+}
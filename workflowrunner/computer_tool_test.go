@@ -0,0 +1,60 @@
+package workflowrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/computer"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeComputer struct{ computer.Computer }
+
+func newFakeComputer(context.Context, ToolDeclaration, ToolFactoryEnv) (computer.Computer, error) {
+	return fakeComputer{}, nil
+}
+
+func TestComputerToolFactory(t *testing.T) {
+	ctx := t.Context()
+	factory := ComputerToolFactory(newFakeComputer)
+
+	tool, err := factory(ctx, ToolDeclaration{Type: "computer", Config: map[string]any{
+		"environment":               "browser",
+		"display_width":             1280.0,
+		"display_height":            720.0,
+		"acknowledge_safety_checks": []any{"irrelevant_domain"},
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	computerTool := tool.(agents.ComputerTool)
+	assert.Equal(t, fakeComputer{}, computerTool.Computer)
+	assert.Equal(t, computer.EnvironmentBrowser, computerTool.Environment)
+	assert.Equal(t, computer.Dimensions{Width: 1280, Height: 720}, computerTool.Dimensions)
+
+	check := func(tool agents.ComputerTool, code string) bool {
+		ack, err := tool.OnSafetyCheck(ctx, agents.ComputerToolSafetyCheckData{
+			SafetyCheck: responses.ResponseComputerToolCallPendingSafetyCheck{Code: code},
+		})
+		require.NoError(t, err)
+		return ack
+	}
+	assert.True(t, check(computerTool, "irrelevant_domain"))
+	assert.False(t, check(computerTool, "malicious_instructions"))
+
+	tool, err = factory(ctx, ToolDeclaration{Type: "computer"}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.False(t, check(tool.(agents.ComputerTool), "irrelevant_domain"))
+
+	tool, err = factory(ctx, ToolDeclaration{Type: "computer", Config: map[string]any{
+		"acknowledge_safety_checks": true,
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.True(t, check(tool.(agents.ComputerTool), "malicious_instructions"))
+
+	_, err = factory(ctx, ToolDeclaration{Type: "computer", Config: map[string]any{"environment": "dos"}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "unsupported computer environment")
+	_, err = factory(ctx, ToolDeclaration{Type: "computer", Config: map[string]any{"display_width": 1280.0}}, ToolFactoryEnv{})
+	assert.Error(t, err)
+}
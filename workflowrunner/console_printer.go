@@ -2,15 +2,29 @@ package workflowrunner
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 )
 
+// consolePrinter prints the progress of the runs with a console callback
+// mode. On a terminal (CallbackModeTTY) it also colors its output, draws the
+// tool calls as a tree and animates a spinner while the active agent waits
+// for the model or a tool.
 type consolePrinter struct {
-	enabled      bool
-	verbose      bool
+	enabled bool
+	verbose bool
+	tty     bool
+	color   bool
+	out     io.Writer
+
+	// mu guards the fields below, shared with the spinner goroutine and the
+	// fan-out branches.
+	mu           sync.Mutex
 	startTime    time.Time
 	turns        int
 	tools        map[string]struct{}
@@ -19,28 +33,162 @@ type consolePrinter struct {
 	currentAgent string
 	streamAgent  string
 	streamActive bool
+	// streamed is set once a streamed message is over, until its item.
+	streamed bool
+	// status shown next to the spinner, empty when idle.
+	status       string
+	spinnerFrame int
+	spinnerDrawn bool
+	spinnerStop  chan struct{}
 }
 
-func newConsolePrinter(enabled bool, verbose bool) *consolePrinter {
+const (
+	ansiBold    = "1"
+	ansiDim     = "2"
+	ansiRed     = "31"
+	ansiGreen   = "32"
+	ansiYellow  = "33"
+	ansiMagenta = "35"
+	ansiCyan    = "36"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// newConsolePrinter returns a printer writing to stdout. tty enables the
+// interactive display, colored unless NO_COLOR is set.
+func newConsolePrinter(enabled bool, verbose bool, tty bool) *consolePrinter {
 	return &consolePrinter{
 		enabled: enabled,
 		verbose: verbose,
+		tty:     tty,
+		color:   tty && os.Getenv("NO_COLOR") == "",
+		out:     os.Stdout,
 		tools:   make(map[string]struct{}),
 	}
 }
 
+// stdoutIsTerminal reports whether stdout is an interactive terminal, where
+// CallbackModeTTY draws its display.
+func stdoutIsTerminal() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (p *consolePrinter) printf(format string, args ...any) {
+	p.clearSpinner()
+	_, _ = fmt.Fprintf(p.out, format, args...)
+}
+
+// style wraps s in the given SGR attributes when colors are enabled.
+func (p *consolePrinter) style(attrs, s string) string {
+	if !p.color || s == "" {
+		return s
+	}
+	return "\x1b[" + attrs + "m" + s + "\x1b[0m"
+}
+
+// wait shows the spinner with the given status, in TTY mode.
+func (p *consolePrinter) wait(status string) {
+	if !p.tty {
+		return
+	}
+	p.status = status
+	if p.spinnerStop == nil {
+		p.spinnerStop = make(chan struct{})
+		go p.spin(p.spinnerStop)
+	}
+	p.drawSpinner()
+}
+
+// idle hides the spinner until the next wait.
+func (p *consolePrinter) idle() {
+	p.status = ""
+	p.clearSpinner()
+}
+
+func (p *consolePrinter) spin(stop <-chan struct{}) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.spinnerFrame++
+			p.drawSpinner()
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *consolePrinter) drawSpinner() {
+	if p.status == "" || p.streamActive {
+		return
+	}
+	frame := spinnerFrames[p.spinnerFrame%len(spinnerFrames)]
+	_, _ = fmt.Fprintf(p.out, "\r\x1b[2K%s %s", p.style(ansiCyan, frame), p.style(ansiDim, p.status))
+	p.spinnerDrawn = true
+}
+
+func (p *consolePrinter) clearSpinner() {
+	if p.spinnerDrawn {
+		_, _ = fmt.Fprint(p.out, "\r\x1b[2K")
+		p.spinnerDrawn = false
+	}
+}
+
+// close stops the spinner once the run is over.
+func (p *consolePrinter) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle()
+	if p.spinnerStop != nil {
+		close(p.spinnerStop)
+		p.spinnerStop = nil
+	}
+}
+
+func (p *consolePrinter) thinking() {
+	if p.currentAgent == "" {
+		p.wait("thinking")
+		return
+	}
+	p.wait(p.currentAgent + " is thinking")
+}
+
 func (p *consolePrinter) OnRunStarted(query string) {
 	if !p.enabled {
 		return
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.startTime = time.Now()
-	fmt.Printf("user: %s\n", shorten(strings.TrimSpace(query), 240))
+	p.printf("%s %s\n", p.style(ansiBold+";"+ansiGreen, "user:"), shorten(strings.TrimSpace(query), 240))
+	p.thinking()
+}
+
+func (p *consolePrinter) OnRunResumed(agent string) {
+	if !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.startTime = time.Now()
+	p.currentAgent = agent
+	p.printf("%s %s\n", p.style(ansiMagenta, "[resumed]"), fallbackAgent(agent))
+	p.thinking()
 }
 
 func (p *consolePrinter) OnStreamEvent(event agents.StreamEvent) {
 	if !p.enabled {
 		return
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	switch ev := event.(type) {
 	case agents.RawResponsesStreamEvent:
 		p.handleRawEvent(ev)
@@ -50,8 +198,9 @@ func (p *consolePrinter) OnStreamEvent(event agents.StreamEvent) {
 		if ev.NewAgent != nil {
 			p.currentAgent = ev.NewAgent.Name
 			if p.verbose {
-				fmt.Printf("[agent switch] -> %s\n", ev.NewAgent.Name)
+				p.printf("%s %s\n", p.style(ansiMagenta, "[agent switch] ->"), ev.NewAgent.Name)
 			}
+			p.thinking()
 		}
 	}
 }
@@ -60,6 +209,7 @@ func (p *consolePrinter) handleRawEvent(ev agents.RawResponsesStreamEvent) {
 	switch ev.Data.Type {
 	case "response.output_text.delta", "response.reasoning_summary_text.delta":
 		if !p.streamActive {
+			p.idle()
 			p.streamAgent = fallbackAgent(p.currentAgent)
 			if p.firstAgent == "" && p.streamAgent != "" {
 				p.firstAgent = p.streamAgent
@@ -68,15 +218,16 @@ func (p *consolePrinter) handleRawEvent(ev agents.RawResponsesStreamEvent) {
 				p.lastAgent = p.streamAgent
 			}
 			p.turns++
-			fmt.Printf("agent %s: ", p.streamAgent)
+			p.printf("%s ", p.style(ansiBold+";"+ansiCyan, "agent "+p.streamAgent+":"))
 			p.streamActive = true
 		}
-		fmt.Print(ev.Data.Delta)
+		p.printf("%s", ev.Data.Delta)
 	case "response.output_text.done", "response.reasoning_summary_text.done", "response.content_part.done":
 		if p.streamActive {
-			fmt.Println()
+			p.printf("\n")
 			p.streamActive = false
 			p.streamAgent = ""
+			p.streamed = true
 		}
 	}
 }
@@ -84,9 +235,13 @@ func (p *consolePrinter) handleRawEvent(ev agents.RawResponsesStreamEvent) {
 func (p *consolePrinter) handleRunItem(item agents.RunItem) {
 	switch v := item.(type) {
 	case agents.MessageOutputItem:
-		if p.streamActive {
+		if p.streamActive || p.streamed {
+			if p.streamActive {
+				p.printf("\n")
+			}
 			p.streamActive = false
 			p.streamAgent = ""
+			p.streamed = false
 			if v.Agent != nil {
 				p.lastAgent = v.Agent.Name
 			}
@@ -102,57 +257,139 @@ func (p *consolePrinter) handleRunItem(item agents.RunItem) {
 		p.turns++
 		message := strings.TrimSpace(agents.ItemHelpers().TextMessageOutput(v))
 		if message != "" {
-			fmt.Printf("agent %s: %s\n", agentName, shorten(message, 240))
+			p.printf("%s %s\n", p.style(ansiBold+";"+ansiCyan, "agent "+agentName+":"), shorten(message, 240))
 		}
 	case agents.ToolCallItem:
 		toolName := readableToolName(v.RawItem)
 		if toolName != "" {
 			p.tools[toolName] = struct{}{}
 		}
-		fmt.Printf("tool %s called by %s\n", toolName, displayAgentName(v.Agent))
+		if p.tty {
+			p.printf("%s %s %s\n", p.style(ansiDim, "├─"), p.style(ansiYellow, toolName),
+				p.style(ansiDim, shorten(readableToolArguments(v.RawItem), 120)))
+			p.wait(displayAgentName(v.Agent) + " is running " + toolName)
+			return
+		}
+		p.printf("tool %s called by %s\n", toolName, displayAgentName(v.Agent))
 	case agents.ToolCallOutputItem:
 		toolName := readableToolOutputName(v.RawItem)
 		if toolName != "" {
 			p.tools[toolName] = struct{}{}
 		}
 		output := shorten(stringifyValue(v.Output), 200)
+		if p.tty {
+			p.printf("%s %s\n", p.style(ansiDim, "│  └─"), strings.ReplaceAll(output, "\n", " "))
+			p.thinking()
+			return
+		}
 		if output != "" {
-			fmt.Printf("tool %s output: %s\n", toolName, output)
+			p.printf("tool %s output: %s\n", toolName, output)
 		}
 	case agents.HandoffOutputItem:
-		fmt.Printf("handoff %s -> %s\n", displayAgentName(v.SourceAgent), displayAgentName(v.TargetAgent))
+		if p.tty {
+			p.printf("%s %s %s → %s\n", p.style(ansiDim, "└─"), p.style(ansiMagenta, "handoff"),
+				displayAgentName(v.SourceAgent), displayAgentName(v.TargetAgent))
+			return
+		}
+		p.printf("handoff %s -> %s\n", displayAgentName(v.SourceAgent), displayAgentName(v.TargetAgent))
+	case agents.OutputValidationFeedbackItem:
+		p.printf("%s (%s): asking for a corrected output\n", p.style(ansiYellow, "invalid output"),
+			displayAgentName(v.Agent))
 	case agents.MCPApprovalRequestItem:
-		fmt.Printf("approval required (%s): request %s for tool %s\n",
+		p.printf("%s (%s): request %s for tool %s\n", p.style(ansiYellow, "approval required"),
 			displayAgentName(v.Agent), shorten(v.RawItem.ID, 40), v.RawItem.Name)
 	case agents.MCPApprovalResponseItem:
-		fmt.Printf("approval response: request %s approve=%t\n",
+		p.printf("approval response: request %s approve=%t\n",
 			shorten(v.RawItem.ApprovalRequestID, 40), v.RawItem.Approve)
 	}
 }
 
+func (p *consolePrinter) OnRunRouted(from, to string) {
+	if !p.enabled || !p.verbose {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printf("%s %s -> %s\n", p.style(ansiMagenta, "[route]"), from, to)
+}
+
+func (p *consolePrinter) OnRunIteration(agent string, iteration, maxIterations int) {
+	if !p.enabled || !p.verbose {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printf("%s %s iteration %d/%d\n", p.style(ansiMagenta, "[loop]"), agent, iteration, maxIterations)
+}
+
+func (p *consolePrinter) OnRunRetry(agent string, attempt int, err error) {
+	if !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.printf("%s %s attempt %d after error: %v\n", p.style(ansiYellow, "[retry]"), agent, attempt, err)
+}
+
+func (p *consolePrinter) OnRunFanIn(agent string, results []FanInOutput) {
+	if !p.enabled || !p.verbose {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, result := range results {
+		p.printf("%s %s -> %s: %s\n", p.style(ansiMagenta, "[fan-out]"), result.Agent, agent, shorten(stringifyValue(result.Output), 120))
+	}
+}
+
 func (p *consolePrinter) OnRunCompleted(finalOutput any, lastAgent string) {
 	if !p.enabled {
 		return
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle()
 	if lastAgent != "" {
 		p.lastAgent = lastAgent
 	}
 	duration := time.Since(p.startTime)
-	fmt.Println("---")
-	fmt.Println("Session summary")
-	fmt.Printf("  turns: %d\n", p.turns)
-	fmt.Printf("  starting agent: %s\n", p.firstAgent)
-	fmt.Printf("  final agent: %s\n", p.lastAgent)
-	fmt.Printf("  runtime: %s\n", duration.Truncate(time.Millisecond))
+	p.printf("%s\n", p.style(ansiDim, "---"))
+	p.printf("%s\n", p.style(ansiBold, "Session summary"))
+	p.printf("  turns: %d\n", p.turns)
+	p.printf("  starting agent: %s\n", p.firstAgent)
+	p.printf("  final agent: %s\n", p.lastAgent)
+	p.printf("  runtime: %s\n", duration.Truncate(time.Millisecond))
 	if len(p.tools) > 0 {
 		toolNames := make([]string, 0, len(p.tools))
 		for name := range p.tools {
 			toolNames = append(toolNames, name)
 		}
-		fmt.Printf("  tools: %s\n", strings.Join(toolNames, ", "))
+		p.printf("  tools: %s\n", strings.Join(toolNames, ", "))
 	}
 	if finalOutput != nil && p.verbose {
-		fmt.Printf("  final output: %s\n", shorten(stringifyValue(finalOutput), 400))
+		p.printf("  final output: %s\n", shorten(stringifyValue(finalOutput), 400))
+	}
+}
+
+func (p *consolePrinter) OnRunSuspended(pending []ApprovalRequestState, inputs []InputRequestState) {
+	if !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle()
+	p.printf("%s\n", p.style(ansiDim, "---"))
+	if len(pending) > 0 {
+		p.printf("%s\n", p.style(ansiYellow, fmt.Sprintf("Run suspended, waiting for %d approvals:", len(pending))))
+		for _, req := range pending {
+			p.printf("  %s: %s on %s %s\n", req.RequestID, req.ToolName, req.ServerLabel, shorten(req.Arguments, 120))
+		}
+	}
+	if len(inputs) > 0 {
+		p.printf("%s\n", p.style(ansiYellow, fmt.Sprintf("Run suspended, waiting for %d answers:", len(inputs))))
+		for _, req := range inputs {
+			p.printf("  %s: %s\n", req.RequestID, shorten(req.Question, 120))
+		}
 	}
 }
 
@@ -160,9 +397,24 @@ func (p *consolePrinter) OnRunFailed(err error) {
 	if !p.enabled {
 		return
 	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle()
 	duration := time.Since(p.startTime)
-	fmt.Println("---")
-	fmt.Printf("Run failed after %s: %v\n", duration.Truncate(time.Millisecond), err)
+	p.printf("%s\n", p.style(ansiDim, "---"))
+	p.printf("%s\n", p.style(ansiRed, fmt.Sprintf("Run failed after %s: %v", duration.Truncate(time.Millisecond), err)))
+}
+
+// readableToolArguments returns the arguments of function tool calls.
+func readableToolArguments(raw agents.ToolCallItemType) string {
+	switch v := raw.(type) {
+	case agents.ResponseFunctionToolCall:
+		return v.Arguments
+	case agents.ResponseOutputItemMcpCall:
+		return v.Name + " " + v.Arguments
+	default:
+		return ""
+	}
 }
 
 func readableToolName(raw agents.ToolCallItemType) string {
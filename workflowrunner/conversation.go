@@ -0,0 +1,139 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+)
+
+// ErrExecutionActive is returned when continuing the conversation of a
+// session whose run is still running, or suspended on approval or input
+// requests.
+var ErrExecutionActive = errors.New("execution is active")
+
+// SessionMessage is the body of POST /sessions/{session_id}/messages.
+type SessionMessage struct {
+	Message string `json:"message" jsonschema:"minLength=1"`
+	// Credentials of the caller, authorizing the run as those of
+	// WorkflowRequest.Session; they are not stored with the request.
+	Credentials CredentialDeclaration `json:"credentials"`
+}
+
+// Continue runs a new turn of the conversation of a session, answering the
+// message of the user with the workflow of the last run of the session,
+// stored with its execution state. The message is added to the session as
+// the query of the run, so chat backends send only the new message instead
+// of the whole workflow request. The run is authorized with the credentials
+// of the caller, as the stored request has none; sessions of other accounts
+// than the one of the caller are not found. The events are published to
+// the callback of the stored request; the ones of the runs started with a
+// publisher are discarded, see ContinueWithPublisher.
+func (s *RunnerService) Continue(ctx context.Context, sessionID string, message SessionMessage) (*asynctask.Task[RunSummary], error) {
+	req, err := s.conversationRequest(ctx, sessionID, message)
+	if err != nil {
+		return nil, err
+	}
+	if req.Callback.Mode == CallbackModeStream {
+		return s.execute(ctx, req, discardCallbackPublisher{}, nil)
+	}
+	return s.execute(ctx, req, nil, nil)
+}
+
+// ContinueWithPublisher is like Continue, with the events published as by
+// ExecuteWithPublisher.
+func (s *RunnerService) ContinueWithPublisher(ctx context.Context, sessionID string, message SessionMessage, publisher CallbackPublisher) (*asynctask.Task[RunSummary], error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	req, err := s.conversationRequest(ctx, sessionID, message)
+	if err != nil {
+		return nil, err
+	}
+	req.Callback = CallbackDeclaration{Mode: CallbackModeStream}
+	return s.execute(ctx, req, publisher, nil)
+}
+
+// conversationRequest returns the stored request of the session, asking the
+// message with the credentials of the caller.
+func (s *RunnerService) conversationRequest(ctx context.Context, sessionID string, message SessionMessage) (WorkflowRequest, error) {
+	if strings.TrimSpace(message.Message) == "" {
+		return WorkflowRequest{}, errors.New("message is required")
+	}
+	state, ok, err := s.GetState(ctx, sessionID)
+	if err != nil {
+		return WorkflowRequest{}, err
+	}
+	// Sessions of other accounts are reported as not found, not to disclose
+	// their existence.
+	if !ok || (state.Request != nil && state.Request.Session.Credentials.AccountID != message.Credentials.AccountID) {
+		return WorkflowRequest{}, fmt.Errorf("%w: session %q", ErrExecutionNotFound, sessionID)
+	}
+	switch state.Status {
+	case ExecutionStatusRunning, ExecutionStatusWaitingApproval, ExecutionStatusWaitingInput:
+		return WorkflowRequest{}, fmt.Errorf("%w: session %q is %s", ErrExecutionActive, sessionID, state.Status)
+	}
+	if state.Request == nil {
+		return WorkflowRequest{}, fmt.Errorf("session %q has no stored workflow request", sessionID)
+	}
+	req := *state.Request
+	req.Query = message.Message
+	req.Session.Credentials = message.Credentials
+	return req, nil
+}
+
+// storedRequest returns the request to store with the execution state of a
+// run, to continue its conversation. Sessions are forked only by their
+// first run. The credentials are left out but the account owning the
+// session, and so are the secrets which are not ${secret:NAME} references:
+// the callback signing secret, and the password of the session store DSN.
+func storedRequest(req WorkflowRequest) *WorkflowRequest {
+	req.Session.ForkFrom = nil
+	req.Session.Credentials = CredentialDeclaration{AccountID: req.Session.Credentials.AccountID}
+	if !isSecretReference(req.Callback.SigningSecret) {
+		req.Callback.SigningSecret = ""
+	}
+	if req.Session.StoreConfig != nil {
+		storeConfig := *req.Session.StoreConfig
+		storeConfig.DSN = stripDSNPassword(storeConfig.DSN)
+		req.Session.StoreConfig = &storeConfig
+	}
+	return &req
+}
+
+// isSecretReference reports whether s is made of ${secret:NAME} references
+// only.
+func isSecretReference(s string) bool {
+	return strings.TrimSpace(secretRefPattern.ReplaceAllString(s, "")) == ""
+}
+
+var (
+	dsnURLPasswordPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*://[^:/@]*):([^@/]*)@`)
+	dsnPasswordPattern    = regexp.MustCompile(`(^|\s+)(?i:password)\s*=\s*('(?:[^'\\]|\\.)*'|\S*)`)
+	dsnReferencePattern   = regexp.MustCompile(`^\$\{(secret|env):[^}]*\}$`)
+)
+
+// stripDSNPassword removes the password of a URL or key/value DSN, unless
+// it is a ${secret:NAME} or ${env:NAME} reference.
+func stripDSNPassword(dsn string) string {
+	if m := dsnURLPasswordPattern.FindStringSubmatch(dsn); m != nil {
+		if dsnReferencePattern.MatchString(m[2]) {
+			return dsn
+		}
+		return m[1] + "@" + dsn[len(m[0]):]
+	}
+	stripped := dsnPasswordPattern.ReplaceAllStringFunc(dsn, func(match string) string {
+		m := dsnPasswordPattern.FindStringSubmatch(match)
+		if dsnReferencePattern.MatchString(strings.Trim(m[2], "'")) {
+			return match
+		}
+		return ""
+	})
+	if stripped == dsn {
+		return dsn
+	}
+	return strings.TrimSpace(stripped)
+}
@@ -0,0 +1,90 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredRequest(t *testing.T) {
+	req := WorkflowRequest{
+		Query: "hello",
+		Session: SessionDeclaration{
+			SessionID:   "s1",
+			ForkFrom:    &SessionForkDeclaration{SessionID: "s0"},
+			Credentials: CredentialDeclaration{UserID: "u1", AccountID: "a1", Capabilities: []string{"admin"}},
+			StoreConfig: &SessionStoreConfig{DSN: "postgres://app:hunter2@db:5432/chat", Schema: "tenant"},
+		},
+		Callback: CallbackDeclaration{Target: "https://example.com/hook", SigningSecret: "literal"},
+	}
+	stored := storedRequest(req)
+	assert.Nil(t, stored.Session.ForkFrom)
+	assert.Equal(t, CredentialDeclaration{AccountID: "a1"}, stored.Session.Credentials)
+	assert.Empty(t, stored.Callback.SigningSecret)
+	assert.Equal(t, "postgres://app@db:5432/chat", stored.Session.StoreConfig.DSN)
+	assert.Equal(t, "tenant", stored.Session.StoreConfig.Schema)
+	assert.Equal(t, "postgres://app:hunter2@db:5432/chat", req.Session.StoreConfig.DSN, "the request is not modified")
+
+	req.Callback.SigningSecret = "${secret:CALLBACK_KEY}"
+	assert.Equal(t, "${secret:CALLBACK_KEY}", storedRequest(req).Callback.SigningSecret)
+}
+
+func TestStripDSNPassword(t *testing.T) {
+	tests := []struct{ dsn, want string }{
+		{"postgres://app:hunter2@db/chat?sslmode=disable", "postgres://app@db/chat?sslmode=disable"},
+		{"postgres://app:${secret:DB_PASSWORD}@db/chat", "postgres://app:${secret:DB_PASSWORD}@db/chat"},
+		{"postgres://app@db/chat", "postgres://app@db/chat"},
+		{"host=db user=app password=hunter2 dbname=chat", "host=db user=app dbname=chat"},
+		{"host=db password='hun ter2' dbname=chat", "host=db dbname=chat"},
+		{"host=db password=${env:DB_PASSWORD} dbname=chat", "host=db password=${env:DB_PASSWORD} dbname=chat"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, stripDSNPassword(tt.dsn), tt.dsn)
+	}
+}
+
+func TestWriteStateOmitsRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeState(rec, WorkflowExecutionState{
+		SessionID: "s1",
+		Request:   &WorkflowRequest{Query: "hello"},
+	})
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "s1", body["session_id"])
+	assert.NotContains(t, body, "request")
+}
+
+func TestContinueRejectsOtherAccounts(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, nil)
+	service := newTestService(t, model, &recordingPublisher{})
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("again")}},
+	})
+	task, err := service.Execute(ctx, testRequest("s1"))
+	require.NoError(t, err)
+	require.NoError(t, task.Await().Error)
+
+	_, err = service.Continue(ctx, "s1", SessionMessage{
+		Message:     "show me your history",
+		Credentials: CredentialDeclaration{UserID: "u2", AccountID: "a2"},
+	})
+	assert.ErrorIs(t, err, ErrExecutionNotFound)
+
+	task, err = service.Continue(ctx, "s1", SessionMessage{
+		Message:     "hello again",
+		Credentials: CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+	})
+	require.NoError(t, err)
+	result := task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, "again", result.Value.FinalOutput)
+}
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 	"github.com/nlpodyssey/openai-agents-go/memory"
 	"github.com/openai/openai-go/v3/packages/param"
 	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared"
 	"github.com/openai/openai-go/v3/shared/constant"
 )
 
@@ -29,6 +31,9 @@ func newWebSearchTool(_ context.Context, decl ToolDeclaration, _ ToolFactoryEnv)
 		if country, ok := getString(loc, "country"); ok {
 			tool.UserLocation.Country = param.NewOpt(country)
 		}
+		if timezone, ok := getString(loc, "timezone"); ok {
+			tool.UserLocation.Timezone = param.NewOpt(timezone)
+		}
 		if t, ok := getString(loc, "type"); ok {
 			tool.UserLocation.Type = t
 		}
@@ -36,28 +41,41 @@ func newWebSearchTool(_ context.Context, decl ToolDeclaration, _ ToolFactoryEnv)
 			tool.UserLocation.Type = string(constant.ValueOf[constant.Approximate]())
 		}
 	}
+	if filters, ok := getMap(decl.Config, "filters"); ok {
+		if domains, ok := getSlice[string](filters, "allowed_domains"); ok {
+			tool.Filters.AllowedDomains = domains
+		} else if _, ok := filters["allowed_domains"]; ok {
+			return nil, errors.New("web_search filters.allowed_domains must be a list of domains")
+		}
+	}
+	tool.SearchContextSize = responses.WebSearchToolSearchContextSizeMedium
 	if ctxSize, ok := getString(decl.Config, "search_context_size"); ok {
-		switch strings.ToLower(ctxSize) {
-		case "low", "medium", "high":
-			tool.SearchContextSize = responses.WebSearchToolSearchContextSize(ctxSize)
+		switch size := responses.WebSearchToolSearchContextSize(strings.ToLower(ctxSize)); size {
+		case responses.WebSearchToolSearchContextSizeLow,
+			responses.WebSearchToolSearchContextSizeMedium,
+			responses.WebSearchToolSearchContextSizeHigh:
+			tool.SearchContextSize = size
 		default:
-			tool.SearchContextSize = responses.WebSearchToolSearchContextSize("medium")
+			return nil, fmt.Errorf("unsupported web_search search_context_size %q", ctxSize)
 		}
-	} else {
-		tool.SearchContextSize = responses.WebSearchToolSearchContextSize("medium")
 	}
 	return tool, nil
 }
 
-func newCodeInterpreterTool(_ context.Context, _ ToolDeclaration, _ ToolFactoryEnv) (agents.Tool, error) {
+func newCodeInterpreterTool(_ context.Context, decl ToolDeclaration, _ ToolFactoryEnv) (agents.Tool, error) {
+	fileIDs, _ := getSlice[string](decl.Config, "file_ids")
+	memoryLimit, _ := getString(decl.Config, "memory_limit")
+	container := agents.CodeInterpreterAutoContainer(memoryLimit, fileIDs...)
+	if containerID, ok := getString(decl.Config, "container_id"); ok && containerID != "" {
+		if len(fileIDs) > 0 || memoryLimit != "" {
+			return nil, errors.New("code_interpreter file_ids and memory_limit apply only to new containers, not to container_id")
+		}
+		container = agents.CodeInterpreterContainerID(containerID)
+	}
 	return agents.CodeInterpreterTool{
 		ToolConfig: responses.ToolCodeInterpreterParam{
-			Container: responses.ToolCodeInterpreterContainerUnionParam{
-				OfCodeInterpreterContainerAuto: &responses.ToolCodeInterpreterContainerCodeInterpreterContainerAutoParam{
-					Type: constant.ValueOf[constant.Auto](),
-				},
-			},
-			Type: constant.ValueOf[constant.CodeInterpreter](),
+			Container: container,
+			Type:      constant.ValueOf[constant.CodeInterpreter](),
 		},
 	}, nil
 }
@@ -68,29 +86,87 @@ func newFileSearchTool(_ context.Context, decl ToolDeclaration, _ ToolFactoryEnv
 		cfg.VectorStoreIDs = vectorIDs
 	}
 	if limit, ok := getFloat(decl.Config, "max_num_results"); ok {
+		if limit < 1 || limit > 50 {
+			return nil, fmt.Errorf("file_search max_num_results must be between 1 and 50, got %v", limit)
+		}
 		cfg.MaxNumResults = param.NewOpt[int64](int64(limit))
 	}
 	if includeResults, ok := getBool(decl.Config, "include_search_results"); ok {
 		cfg.IncludeSearchResults = includeResults
 	}
+	// Filters and ranking options have the JSON form of the Responses API.
+	if filters, ok := getMap(decl.Config, "filters"); ok {
+		var err error
+		switch filterType, _ := getString(filters, "type"); filterType {
+		case "and", "or":
+			cfg.Filters.OfCompoundFilter = new(shared.CompoundFilterParam)
+			_, err = decodeConfig(decl.Config, "filters", cfg.Filters.OfCompoundFilter)
+		default:
+			cfg.Filters.OfComparisonFilter = new(shared.ComparisonFilterParam)
+			_, err = decodeConfig(decl.Config, "filters", cfg.Filters.OfComparisonFilter)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("file_search filters: %w", err)
+		}
+	}
+	if _, err := decodeConfig(decl.Config, "ranking_options", &cfg.RankingOptions); err != nil {
+		return nil, fmt.Errorf("file_search ranking_options: %w", err)
+	}
 	return cfg, nil
 }
 
+// imageGenerationOptions are the accepted values of the options of
+// image_generation tools.
+var imageGenerationOptions = map[string][]string{
+	"size":          {"auto", "1024x1024", "1024x1536", "1536x1024"},
+	"quality":       {"auto", "low", "medium", "high"},
+	"output_format": {"png", "webp", "jpeg"},
+	"background":    {"auto", "transparent", "opaque"},
+	"moderation":    {"auto", "low"},
+}
+
 func newImageGenerationTool(_ context.Context, decl ToolDeclaration, _ ToolFactoryEnv) (agents.Tool, error) {
-	tool := agents.ImageGenerationTool{}
-	if cfg, ok := getMap(decl.Config, "tool_config"); ok {
-		param := responses.ToolImageGenerationParam{}
-		if quality, ok := getString(cfg, "quality"); ok {
-			param.Quality = quality
+	// The options were first nested in tool_config.
+	cfg, ok := getMap(decl.Config, "tool_config")
+	if !ok {
+		cfg = decl.Config
+	}
+	toolConfig := responses.ToolImageGenerationParam{
+		Type: constant.ValueOf[constant.ImageGeneration](),
+	}
+	options := map[string]*string{
+		"size":          &toolConfig.Size,
+		"quality":       &toolConfig.Quality,
+		"output_format": &toolConfig.OutputFormat,
+		"background":    &toolConfig.Background,
+		"moderation":    &toolConfig.Moderation,
+	}
+	for name, field := range options {
+		value, ok := getString(cfg, name)
+		if !ok {
+			continue
 		}
-		param.Type = constant.ValueOf[constant.ImageGeneration]()
-		tool.ToolConfig = param
-	} else {
-		tool.ToolConfig = responses.ToolImageGenerationParam{
-			Type: constant.ValueOf[constant.ImageGeneration](),
+		if !slices.Contains(imageGenerationOptions[name], value) {
+			return nil, fmt.Errorf("unsupported image_generation %s %q", name, value)
 		}
+		*field = value
 	}
-	return tool, nil
+	if model, ok := getString(cfg, "model"); ok {
+		toolConfig.Model = model
+	}
+	if compression, ok := getFloat(cfg, "output_compression"); ok {
+		if compression < 0 || compression > 100 {
+			return nil, fmt.Errorf("image_generation output_compression must be between 0 and 100, got %v", compression)
+		}
+		toolConfig.OutputCompression = param.NewOpt(int64(compression))
+	}
+	if partialImages, ok := getFloat(cfg, "partial_images"); ok {
+		if partialImages < 0 || partialImages > 3 {
+			return nil, fmt.Errorf("image_generation partial_images must be between 0 and 3, got %v", partialImages)
+		}
+		toolConfig.PartialImages = param.NewOpt(int64(partialImages))
+	}
+	return agents.ImageGenerationTool{ToolConfig: toolConfig}, nil
 }
 
 func newHostedMCPTool(_ context.Context, decl ToolDeclaration, env ToolFactoryEnv) (agents.Tool, error) {
@@ -109,16 +185,25 @@ func newHostedMCPTool(_ context.Context, decl ToolDeclaration, env ToolFactoryEn
 	if v, ok := getString(decl.Config, "require_approval"); ok && v != "" {
 		requireApproval = v
 	}
-	return agents.HostedMCPTool{
-		ToolConfig: responses.ToolMcpParam{
-			ServerLabel: label,
-			ServerURL:   param.NewOpt(url),
-			RequireApproval: responses.ToolMcpRequireApprovalUnionParam{
-				OfMcpToolApprovalSetting: param.NewOpt(requireApproval),
-			},
-			Type: constant.ValueOf[constant.Mcp](),
+	toolConfig := responses.ToolMcpParam{
+		ServerLabel: label,
+		ServerURL:   param.NewOpt(url),
+		RequireApproval: responses.ToolMcpRequireApprovalUnionParam{
+			OfMcpToolApprovalSetting: param.NewOpt(requireApproval),
 		},
-	}, nil
+		Type: constant.ValueOf[constant.Mcp](),
+	}
+	// Credentials are usually given as ${secret:NAME} references.
+	if authorization, ok := getString(decl.Config, "authorization"); ok && authorization != "" {
+		toolConfig.Authorization = param.NewOpt(authorization)
+	}
+	if headers, ok := getMap(decl.Config, "headers"); ok {
+		toolConfig.Headers = make(map[string]string, len(headers))
+		for k, v := range headers {
+			toolConfig.Headers[k] = fmt.Sprint(v)
+		}
+	}
+	return agents.HostedMCPTool{ToolConfig: toolConfig}, nil
 }
 
 func newJSONMapOutputType(_ context.Context, decl OutputTypeDeclaration) (agents.OutputTypeInterface, error) {
@@ -136,6 +221,7 @@ func newJSONMapOutputType(_ context.Context, decl OutputTypeDeclaration) (agents
 }
 
 // NewSQLiteSessionFactory stores sessions on-disk inside baseDir (created if needed).
+// Sessions are namespaced by the caller's account ID.
 func NewSQLiteSessionFactory(baseDir string) SessionFactory {
 	return func(ctx context.Context, decl SessionDeclaration) (memory.Session, error) {
 		if err := os.MkdirAll(baseDir, 0o755); err != nil {
@@ -144,6 +230,7 @@ func NewSQLiteSessionFactory(baseDir string) SessionFactory {
 		dbPath := filepath.Join(baseDir, fmt.Sprintf("%s.db", sanitizeFileName(decl.SessionID)))
 		return memory.NewSQLiteSession(ctx, memory.SQLiteSessionParams{
 			SessionID:        decl.SessionID,
+			Namespace:        decl.Credentials.AccountID,
 			DBDataSourceName: dbPath,
 		})
 	}
@@ -194,6 +281,23 @@ func getMap(source map[string]any, key string) (map[string]any, bool) {
 	}
 }
 
+// decodeConfig decodes the JSON form of the value of key, if present, into
+// dst.
+func decodeConfig(source map[string]any, key string, dst any) (bool, error) {
+	raw, ok := source[key]
+	if !ok || raw == nil {
+		return false, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func getString(source map[string]any, key string) (string, bool) {
 	if source == nil {
 		return "", false
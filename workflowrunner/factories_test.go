@@ -0,0 +1,154 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebSearchTool(t *testing.T) {
+	ctx := t.Context()
+	tool, err := newWebSearchTool(ctx, ToolDeclaration{Type: "web_search", Config: map[string]any{
+		"user_location": map[string]any{
+			"city":     "Turin",
+			"country":  "IT",
+			"timezone": "Europe/Rome",
+		},
+		"search_context_size": "High",
+		"filters": map[string]any{
+			"allowed_domains": []any{"example.com", "example.org"},
+		},
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	webSearch := tool.(agents.WebSearchTool)
+	assert.Equal(t, responses.WebSearchToolUserLocationParam{
+		City:     param.NewOpt("Turin"),
+		Country:  param.NewOpt("IT"),
+		Timezone: param.NewOpt("Europe/Rome"),
+		Type:     "approximate",
+	}, webSearch.UserLocation)
+	assert.Equal(t, responses.WebSearchToolSearchContextSizeHigh, webSearch.SearchContextSize)
+	assert.Equal(t, []string{"example.com", "example.org"}, webSearch.Filters.AllowedDomains)
+
+	tool, err = newWebSearchTool(ctx, ToolDeclaration{Type: "web_search"}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.Equal(t, agents.WebSearchTool{SearchContextSize: responses.WebSearchToolSearchContextSizeMedium}, tool)
+
+	_, err = newWebSearchTool(ctx, ToolDeclaration{Type: "web_search", Config: map[string]any{
+		"search_context_size": "huge",
+	}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "search_context_size")
+	_, err = newWebSearchTool(ctx, ToolDeclaration{Type: "web_search", Config: map[string]any{
+		"filters": map[string]any{"allowed_domains": "example.com"},
+	}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "allowed_domains")
+}
+
+func TestNewFileSearchTool(t *testing.T) {
+	ctx := t.Context()
+	tool, err := newFileSearchTool(ctx, ToolDeclaration{Type: "file_search", Config: map[string]any{
+		"vector_store_ids":       []any{"vs_1"},
+		"max_num_results":        5.0,
+		"include_search_results": true,
+		"filters": map[string]any{
+			"type": "and",
+			"filters": []any{
+				map[string]any{"type": "eq", "key": "lang", "value": "en"},
+				map[string]any{"type": "gte", "key": "year", "value": 2024.0},
+			},
+		},
+		"ranking_options": map[string]any{"ranker": "auto", "score_threshold": 0.5},
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	fileSearch := tool.(agents.FileSearchTool)
+	assert.Equal(t, []string{"vs_1"}, fileSearch.VectorStoreIDs)
+	assert.Equal(t, param.NewOpt[int64](5), fileSearch.MaxNumResults)
+	assert.True(t, fileSearch.IncludeSearchResults)
+	assert.Equal(t, "auto", fileSearch.RankingOptions.Ranker)
+	assert.Equal(t, param.NewOpt(0.5), fileSearch.RankingOptions.ScoreThreshold)
+
+	filters, err := json.Marshal(fileSearch.Filters)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "and", "filters": [
+		{"type": "eq", "key": "lang", "value": "en"},
+		{"type": "gte", "key": "year", "value": 2024}
+	]}`, string(filters))
+
+	_, err = newFileSearchTool(ctx, ToolDeclaration{Type: "file_search", Config: map[string]any{
+		"max_num_results": 100.0,
+	}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "max_num_results")
+}
+
+func TestNewCodeInterpreterTool(t *testing.T) {
+	ctx := t.Context()
+	containerJSON := func(tool agents.Tool) string {
+		data, err := json.Marshal(tool.(agents.CodeInterpreterTool).ToolConfig.Container)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	tool, err := newCodeInterpreterTool(ctx, ToolDeclaration{Type: "code_interpreter"}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "auto"}`, containerJSON(tool))
+
+	tool, err = newCodeInterpreterTool(ctx, ToolDeclaration{Type: "code_interpreter", Config: map[string]any{
+		"file_ids":     []any{"file-1"},
+		"memory_limit": "4g",
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "auto", "file_ids": ["file-1"], "memory_limit": "4g"}`, containerJSON(tool))
+
+	tool, err = newCodeInterpreterTool(ctx, ToolDeclaration{Type: "code_interpreter", Config: map[string]any{
+		"container_id": "cntr_1",
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"cntr_1"`, containerJSON(tool))
+
+	_, err = newCodeInterpreterTool(ctx, ToolDeclaration{Type: "code_interpreter", Config: map[string]any{
+		"container_id": "cntr_1",
+		"file_ids":     []any{"file-1"},
+	}}, ToolFactoryEnv{})
+	assert.Error(t, err)
+}
+
+func TestNewImageGenerationTool(t *testing.T) {
+	ctx := t.Context()
+	tool, err := newImageGenerationTool(ctx, ToolDeclaration{Type: "image_generation", Config: map[string]any{
+		"size":               "1024x1536",
+		"quality":            "high",
+		"output_format":      "webp",
+		"output_compression": 80.0,
+		"background":         "transparent",
+		"partial_images":     2.0,
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	toolConfig := tool.(agents.ImageGenerationTool).ToolConfig
+	assert.Equal(t, "1024x1536", toolConfig.Size)
+	assert.Equal(t, "high", toolConfig.Quality)
+	assert.Equal(t, "webp", toolConfig.OutputFormat)
+	assert.Equal(t, param.NewOpt[int64](80), toolConfig.OutputCompression)
+	assert.Equal(t, "transparent", toolConfig.Background)
+	assert.Equal(t, param.NewOpt[int64](2), toolConfig.PartialImages)
+
+	// The options may be nested in tool_config.
+	tool, err = newImageGenerationTool(ctx, ToolDeclaration{Type: "image_generation", Config: map[string]any{
+		"tool_config": map[string]any{"quality": "low"},
+	}}, ToolFactoryEnv{})
+	require.NoError(t, err)
+	assert.Equal(t, "low", tool.(agents.ImageGenerationTool).ToolConfig.Quality)
+
+	_, err = newImageGenerationTool(ctx, ToolDeclaration{Type: "image_generation", Config: map[string]any{
+		"size": "4096x4096",
+	}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "size")
+	_, err = newImageGenerationTool(ctx, ToolDeclaration{Type: "image_generation", Config: map[string]any{
+		"partial_images": 5.0,
+	}}, ToolFactoryEnv{})
+	assert.ErrorContains(t, err, "partial_images")
+}
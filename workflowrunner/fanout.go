@@ -0,0 +1,102 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+)
+
+// fanOutBranch is an agent run in parallel by a fan-out.
+type fanOutBranch struct {
+	name  string
+	agent *agents.Agent
+}
+
+// runFanOut runs the branches concurrently on the same input. The runner
+// must not have a session, as the branches would write to it concurrently.
+func runFanOut(ctx context.Context, runner agents.Runner, aggregator string, branches []fanOutBranch, input []agents.TResponseInputItem) ([]FanInOutput, error) {
+	results := make([]FanInOutput, len(branches))
+	err := tracing.CustomSpan(ctx, tracing.CustomSpanParams{
+		Name: "fan_out",
+		Data: map[string]any{
+			"aggregator": aggregator,
+			"agents":     fanOutNames(branches),
+		},
+	}, func(ctx context.Context, _ tracing.Span) error {
+		errs := make([]error, len(branches))
+		var wg sync.WaitGroup
+		for i, branch := range branches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := runner.RunInputs(ctx, branch.agent, slices.Clone(input))
+				if err != nil {
+					errs[i] = fmt.Errorf("fan-out agent %q: %w", branch.name, err)
+					return
+				}
+				results[i] = FanInOutput{Agent: branch.name, Output: result.FinalOutput}
+			}()
+		}
+		wg.Wait()
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func fanOutNames(branches []fanOutBranch) []string {
+	names := make([]string, len(branches))
+	for i, branch := range branches {
+		names[i] = branch.name
+	}
+	return names
+}
+
+// fanInMessage merges the outputs of a fan-out into a user message for the
+// aggregator agent.
+func fanInMessage(results []FanInOutput) []agents.TResponseInputItem {
+	var sb strings.Builder
+	sb.WriteString("Outputs of the agents run in parallel:")
+	for _, result := range results {
+		fmt.Fprintf(&sb, "\n\n## %s\n%s", result.Agent, outputText(result.Output))
+	}
+	return agents.ItemHelpers().InputToNewInputList(agents.InputString(sb.String()))
+}
+
+// conversationInput returns the session history followed by the query, which
+// is the input of the first agent of a run.
+func conversationInput(ctx context.Context, config agents.RunConfig, query string) ([]agents.TResponseInputItem, error) {
+	history, err := sessionHistory(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	queryItems := agents.ItemHelpers().InputToNewInputList(agents.InputString(query))
+	return slices.Concat(history, queryItems), nil
+}
+
+// sessionHistory returns the items of the session of the run, limited and
+// trimmed as configured.
+func sessionHistory(ctx context.Context, config agents.RunConfig) ([]agents.TResponseInputItem, error) {
+	if config.Session == nil {
+		return nil, nil
+	}
+	history, err := config.Session.GetItems(ctx, config.LimitMemory)
+	if err != nil {
+		return nil, fmt.Errorf("get session items: %w", err)
+	}
+	if config.SessionTrimPolicy != nil {
+		history, err = config.SessionTrimPolicy.Trim(ctx, history)
+		if err != nil {
+			return nil, fmt.Errorf("trim session items: %w", err)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,284 @@
+// Package grpcserver exposes a workflowrunner.RunnerService as a gRPC service,
+// defined in workflowrunnerpb/workflowrunner.proto.
+//
+//	srv := grpc.NewServer()
+//	workflowrunnerpb.RegisterWorkflowRunnerServer(srv, grpcserver.New(workflowrunner.NewRunnerService(nil)))
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	pb "github.com/nlpodyssey/openai-agents-go/workflowrunner/grpcserver/workflowrunnerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the WorkflowRunner gRPC service on top of a RunnerService.
+type Server struct {
+	pb.UnimplementedWorkflowRunnerServer
+	Runner *workflowrunner.RunnerService
+}
+
+// New returns a Server backed by the given RunnerService.
+func New(runner *workflowrunner.RunnerService) *Server {
+	return &Server{Runner: runner}
+}
+
+// ExecuteWorkflow runs the workflow and waits for its completion. Failed runs
+// are reported in the summary error, while invalid requests fail the call.
+func (s *Server) ExecuteWorkflow(ctx context.Context, in *pb.ExecuteWorkflowRequest) (*pb.ExecuteWorkflowResponse, error) {
+	req, err := decodeWorkflowRequest(in)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.Runner.Execute(ctx, req)
+	if err != nil {
+		return nil, executeError(err)
+	}
+	return awaitSummary(task)
+}
+
+// StreamEvents runs the workflow, sending each of its events to the stream.
+func (s *Server) StreamEvents(in *pb.ExecuteWorkflowRequest, stream pb.WorkflowRunner_StreamEventsServer) error {
+	req, err := decodeWorkflowRequest(in)
+	if err != nil {
+		return err
+	}
+	task, err := s.Runner.ExecuteWithPublisher(stream.Context(), req, streamPublisher{stream: stream})
+	if err != nil {
+		return executeError(err)
+	}
+	// Run failures are already sent as run.failed events.
+	_ = task.Await()
+	return stream.Context().Err()
+}
+
+// ResumeWorkflow resumes the suspended run of the session of the request and
+// waits for its completion, see workflowrunner.RunnerService.Resume.
+func (s *Server) ResumeWorkflow(ctx context.Context, in *pb.ExecuteWorkflowRequest) (*pb.ExecuteWorkflowResponse, error) {
+	req, err := decodeWorkflowRequest(in)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.Runner.Resume(ctx, req)
+	if err != nil {
+		return nil, executeError(err)
+	}
+	return awaitSummary(task)
+}
+
+// ContinueConversation runs a new turn of the conversation of the session and
+// waits for its completion, see workflowrunner.RunnerService.Continue.
+func (s *Server) ContinueConversation(ctx context.Context, in *pb.ContinueConversationRequest) (*pb.ExecuteWorkflowResponse, error) {
+	if in.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	var message workflowrunner.SessionMessage
+	if err := decodeStruct(in.GetMessage(), &message); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid message: %v", err)
+	}
+	task, err := s.Runner.Continue(ctx, in.GetSessionId(), message)
+	if err != nil {
+		return nil, executeError(err)
+	}
+	return awaitSummary(task)
+}
+
+func (s *Server) ResolveApproval(ctx context.Context, in *pb.ResolveApprovalRequest) (*pb.ExecutionState, error) {
+	if in.GetSessionId() == "" || in.GetRequestId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id and request_id are required")
+	}
+	state, err := s.Runner.ResolveApproval(ctx, in.GetSessionId(), workflowrunner.ApprovalDecisionState{
+		RequestID: in.GetRequestId(),
+		Approve:   in.GetApprove(),
+		Reason:    in.GetReason(),
+	})
+	switch {
+	case errors.Is(err, workflowrunner.ErrExecutionNotFound), errors.Is(err, workflowrunner.ErrApprovalNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toProtoState(state)
+}
+
+func (s *Server) GetState(ctx context.Context, in *pb.GetStateRequest) (*pb.ExecutionState, error) {
+	if in.GetSessionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	state, ok, err := s.Runner.GetState(ctx, in.GetSessionId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no execution state for session %q", in.GetSessionId())
+	}
+	return toProtoState(state)
+}
+
+// awaitSummary waits for the run and returns its summary.
+func awaitSummary(task *asynctask.Task[workflowrunner.RunSummary]) (*pb.ExecuteWorkflowResponse, error) {
+	summary, err := toProtoSummary(task.Await().Value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExecuteWorkflowResponse{Summary: summary}, nil
+}
+
+// executeError maps the errors of runs refused before starting, as
+// workflowrunner.RunHandler does with HTTP status codes.
+func executeError(err error) error {
+	var admissionErr *workflowrunner.AdmissionError
+	switch {
+	case errors.As(err, &admissionErr):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, workflowrunner.ErrMissingCapabilities):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, workflowrunner.ErrExecutionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, workflowrunner.ErrExecutionNotSuspended), errors.Is(err, workflowrunner.ErrApprovalsPending),
+		errors.Is(err, workflowrunner.ErrInputsPending), errors.Is(err, workflowrunner.ErrExecutionStateConflict),
+		errors.Is(err, workflowrunner.ErrExecutionActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// streamPublisher is a CallbackPublisher sending events to a StreamEvents call.
+type streamPublisher struct {
+	stream pb.WorkflowRunner_StreamEventsServer
+}
+
+func (p streamPublisher) Publish(_ context.Context, event workflowrunner.CallbackEvent) error {
+	payload, err := toValue(event.Payload)
+	if err != nil {
+		return err
+	}
+	var metadata *structpb.Struct
+	if event.Metadata != nil {
+		metadata, err = toStruct(event.Metadata)
+		if err != nil {
+			return err
+		}
+	}
+	return p.stream.Send(&pb.WorkflowEvent{
+		Type:      event.Type,
+		Timestamp: toTimestamp(event.Timestamp),
+		Payload:   payload,
+		Metadata:  metadata,
+	})
+}
+
+func decodeWorkflowRequest(in *pb.ExecuteWorkflowRequest) (workflowrunner.WorkflowRequest, error) {
+	var req workflowrunner.WorkflowRequest
+	if in.GetRequest() == nil {
+		return req, status.Error(codes.InvalidArgument, "request is required")
+	}
+	if err := decodeStruct(in.GetRequest(), &req); err != nil {
+		return req, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	return req, nil
+}
+
+// decodeStruct decodes a protobuf Struct into v as its JSON encoding.
+func decodeStruct(s *structpb.Struct, v any) error {
+	raw, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func toProtoSummary(summary workflowrunner.RunSummary) (*pb.RunSummary, error) {
+	finalOutput, err := toValue(summary.FinalOutput)
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.RunSummary{
+		WorkflowName:   summary.WorkflowName,
+		SessionId:      summary.SessionID,
+		FinalOutput:    finalOutput,
+		LastResponseId: summary.LastResponseID,
+	}
+	if summary.Error != nil {
+		out.Error = summary.Error.Error()
+	}
+	return out, nil
+}
+
+func toProtoState(state workflowrunner.WorkflowExecutionState) (*pb.ExecutionState, error) {
+	finalOutput, err := toValue(state.FinalOutput)
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.ExecutionState{
+		SessionId:      state.SessionID,
+		WorkflowName:   state.WorkflowName,
+		Status:         string(state.Status),
+		LastAgent:      state.LastAgent,
+		LastResponseId: state.LastResponseID,
+		LastQuery:      state.LastQuery,
+		LastError:      state.LastError,
+		FinalOutput:    finalOutput,
+		UpdatedAt:      toTimestamp(state.UpdatedAt),
+	}
+	for _, req := range state.PendingApprovals {
+		out.PendingApprovals = append(out.PendingApprovals, &pb.ApprovalRequest{
+			RequestId:   req.RequestID,
+			AgentName:   req.AgentName,
+			ToolName:    req.ToolName,
+			ServerLabel: req.ServerLabel,
+			Arguments:   req.Arguments,
+			CreatedAt:   toTimestamp(req.CreatedAt),
+		})
+	}
+	for _, decision := range state.ResolvedApprovals {
+		out.ResolvedApprovals = append(out.ResolvedApprovals, &pb.ApprovalDecision{
+			RequestId:  decision.RequestID,
+			Approve:    decision.Approve,
+			Reason:     decision.Reason,
+			ResolvedAt: toTimestamp(decision.ResolvedAt),
+		})
+	}
+	return out, nil
+}
+
+// toValue converts any JSON-serializable value to a protobuf Value.
+func toValue(v any) (*structpb.Value, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode value: %v", err)
+	}
+	value := new(structpb.Value)
+	if err := value.UnmarshalJSON(raw); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert value: %v", err)
+	}
+	return value, nil
+}
+
+func toStruct(m map[string]any) (*structpb.Struct, error) {
+	value, err := toValue(m)
+	if err != nil {
+		return nil, err
+	}
+	s := value.GetStructValue()
+	if s == nil {
+		return nil, status.Errorf(codes.Internal, "expected an object, got %v", value)
+	}
+	return s, nil
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
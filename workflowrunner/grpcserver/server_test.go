@@ -0,0 +1,267 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	pb "github.com/nlpodyssey/openai-agents-go/workflowrunner/grpcserver/workflowrunnerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type testModelProvider struct {
+	model agents.Model
+}
+
+func (p testModelProvider) GetModel(string) (agents.Model, error) { return p.model, nil }
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(context.Context, workflowrunner.CallbackEvent) error { return nil }
+
+// newTestClient serves a RunnerService running the agents with model over an
+// in-memory connection, and returns a client of it.
+func newTestClient(t *testing.T, model agents.Model) (pb.WorkflowRunnerClient, *workflowrunner.RunnerService) {
+	builder := workflowrunner.NewDefaultBuilder()
+	builder.SessionFactory = workflowrunner.NewSQLiteSessionFactory(t.TempDir())
+	builder.SessionFactories["sqlite"] = builder.SessionFactory
+	builder.ModelProviderFactories["test"] = func(context.Context, workflowrunner.ModelDeclaration) (agents.ModelProvider, error) {
+		return testModelProvider{model: model}, nil
+	}
+	runner := workflowrunner.NewRunnerService(builder)
+	runner.CallbackFactory = func(context.Context, workflowrunner.CallbackDeclaration) (workflowrunner.CallbackPublisher, error) {
+		return discardPublisher{}, nil
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterWorkflowRunnerServer(srv, New(runner))
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, conn.Close()) })
+	return pb.NewWorkflowRunnerClient(conn), runner
+}
+
+func testRequest(sessionID string) workflowrunner.WorkflowRequest {
+	return workflowrunner.WorkflowRequest{
+		Query: "hello",
+		Session: workflowrunner.SessionDeclaration{
+			SessionID:   sessionID,
+			Credentials: workflowrunner.CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+		},
+		Callback: workflowrunner.CallbackDeclaration{Target: "https://example.com/hook"},
+		Workflow: workflowrunner.WorkflowDeclaration{
+			Name:          "wf",
+			StartingAgent: "assistant",
+			Agents: []workflowrunner.AgentDeclaration{{
+				Name:         "assistant",
+				Instructions: "Help.",
+				Model:        &workflowrunner.ModelDeclaration{Provider: "test", Model: "fake"},
+			}},
+		},
+	}
+}
+
+func toStructpb(t *testing.T, v any) *structpb.Struct {
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	s := new(structpb.Struct)
+	require.NoError(t, s.UnmarshalJSON(raw))
+	return s
+}
+
+func executeRequest(t *testing.T, req workflowrunner.WorkflowRequest) *pb.ExecuteWorkflowRequest {
+	return &pb.ExecuteWorkflowRequest{Request: toStructpb(t, req)}
+}
+
+func continueRequest(t *testing.T, sessionID, accountID, message string) *pb.ContinueConversationRequest {
+	return &pb.ContinueConversationRequest{
+		SessionId: sessionID,
+		Message: toStructpb(t, workflowrunner.SessionMessage{
+			Message:     message,
+			Credentials: workflowrunner.CredentialDeclaration{UserID: "u1", AccountID: accountID},
+		}),
+	}
+}
+
+func TestServerExecuteAndContinue(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("fine")}},
+	})
+	client, _ := newTestClient(t, model)
+
+	resp, err := client.ExecuteWorkflow(ctx, executeRequest(t, testRequest("s1")))
+	require.NoError(t, err)
+	assert.Equal(t, "wf", resp.GetSummary().GetWorkflowName())
+	assert.Equal(t, "s1", resp.GetSummary().GetSessionId())
+	assert.Equal(t, "hi", resp.GetSummary().GetFinalOutput().GetStringValue())
+	assert.Empty(t, resp.GetSummary().GetError())
+
+	resp, err = client.ContinueConversation(ctx, continueRequest(t, "s1", "a1", "how are you?"))
+	require.NoError(t, err)
+	assert.Equal(t, "fine", resp.GetSummary().GetFinalOutput().GetStringValue())
+
+	state, err := client.GetState(ctx, &pb.GetStateRequest{SessionId: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, string(workflowrunner.ExecutionStatusCompleted), state.GetStatus())
+	assert.Equal(t, "how are you?", state.GetLastQuery())
+}
+
+func TestServerStreamEvents(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, nil)
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")},
+	})
+	client, _ := newTestClient(t, model)
+
+	stream, err := client.StreamEvents(t.Context(), executeRequest(t, testRequest("s1")))
+	require.NoError(t, err)
+	var types []string
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		types = append(types, event.GetType())
+	}
+	require.NotEmpty(t, types)
+	assert.Equal(t, workflowrunner.CallbackEventRunStarted, types[0])
+	assert.Equal(t, workflowrunner.CallbackEventRunCompleted, types[len(types)-1])
+}
+
+func TestServerResolveApprovalAndResume(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{{
+			ID:          "mcpr_1",
+			Type:        "mcp_approval_request",
+			ServerLabel: "crm",
+			Name:        "delete_contact",
+			Arguments:   `{"id": 7}`,
+		}},
+	})
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("deleted")},
+	})
+	client, _ := newTestClient(t, model)
+	req := testRequest("s1")
+	req.Workflow.Agents[0].MCPServers = []workflowrunner.MCPDeclaration{{
+		ServerLabel:     "crm",
+		Address:         "https://mcp.example.com",
+		RequireApproval: "always",
+	}}
+
+	_, err := client.ExecuteWorkflow(ctx, executeRequest(t, req))
+	require.NoError(t, err)
+	state, err := client.GetState(ctx, &pb.GetStateRequest{SessionId: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, string(workflowrunner.ExecutionStatusWaitingApproval), state.GetStatus())
+	require.Len(t, state.GetPendingApprovals(), 1)
+	assert.Equal(t, "delete_contact", state.GetPendingApprovals()[0].GetToolName())
+
+	// Suspended runs are resumed, not continued, once all their approval
+	// requests are resolved.
+	_, err = client.ContinueConversation(ctx, continueRequest(t, "s1", "a1", "go on"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.ResumeWorkflow(ctx, executeRequest(t, req))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	state, err = client.ResolveApproval(ctx, &pb.ResolveApprovalRequest{SessionId: "s1", RequestId: "mcpr_1", Approve: true})
+	require.NoError(t, err)
+	assert.Empty(t, state.GetPendingApprovals())
+	require.Len(t, state.GetResolvedApprovals(), 1)
+	assert.True(t, state.GetResolvedApprovals()[0].GetApprove())
+
+	resp, err := client.ResumeWorkflow(ctx, executeRequest(t, req))
+	require.NoError(t, err)
+	assert.Equal(t, "deleted", resp.GetSummary().GetFinalOutput().GetStringValue())
+
+	// Completed runs are not suspended anymore.
+	_, err = client.ResumeWorkflow(ctx, executeRequest(t, req))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerErrorCodes(t *testing.T) {
+	ctx := t.Context()
+	client, runner := newTestClient(t, agentstesting.NewFakeModel(false, nil))
+
+	invalid := testRequest("s1")
+	invalid.Workflow.StartingAgent = "missing"
+	missingCapabilities := testRequest("s1")
+	missingCapabilities.Workflow.RequiredCapabilities = []string{"admin"}
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"execute without request", func() error {
+			_, err := client.ExecuteWorkflow(ctx, &pb.ExecuteWorkflowRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"execute invalid workflow", func() error {
+			_, err := client.ExecuteWorkflow(ctx, executeRequest(t, invalid))
+			return err
+		}, codes.InvalidArgument},
+		{"execute missing capabilities", func() error {
+			_, err := client.ExecuteWorkflow(ctx, executeRequest(t, missingCapabilities))
+			return err
+		}, codes.PermissionDenied},
+		{"resume unknown session", func() error {
+			_, err := client.ResumeWorkflow(ctx, executeRequest(t, testRequest("unknown")))
+			return err
+		}, codes.NotFound},
+		{"continue without session", func() error {
+			_, err := client.ContinueConversation(ctx, continueRequest(t, "", "a1", "hello"))
+			return err
+		}, codes.InvalidArgument},
+		{"continue unknown session", func() error {
+			_, err := client.ContinueConversation(ctx, continueRequest(t, "unknown", "a1", "hello"))
+			return err
+		}, codes.NotFound},
+		{"resolve unknown session", func() error {
+			_, err := client.ResolveApproval(ctx, &pb.ResolveApprovalRequest{SessionId: "unknown", RequestId: "r1"})
+			return err
+		}, codes.NotFound},
+		{"get unknown state", func() error {
+			_, err := client.GetState(ctx, &pb.GetStateRequest{SessionId: "unknown"})
+			return err
+		}, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.call()))
+		})
+	}
+
+	t.Run("admission", func(t *testing.T) {
+		runner.Admission = workflowrunner.NewAdmissionController(workflowrunner.ConcurrencyLimits{MaxConcurrentRuns: 1})
+		t.Cleanup(func() { runner.Admission = nil })
+		release, err := runner.Admission.Acquire(ctx, "other")
+		require.NoError(t, err)
+		defer release()
+		_, err = client.ExecuteWorkflow(ctx, executeRequest(t, testRequest("s2")))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
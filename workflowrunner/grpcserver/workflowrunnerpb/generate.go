@@ -0,0 +1,5 @@
+// Package workflowrunnerpb contains the protobuf definition of the
+// WorkflowRunner gRPC service, and the code generated from it.
+package workflowrunnerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative workflowrunner.proto
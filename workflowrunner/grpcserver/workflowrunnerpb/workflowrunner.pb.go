@@ -0,0 +1,868 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v6.32.1
+// source: workflowrunner.proto
+
+package workflowrunnerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecuteWorkflowRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The workflow request, in the same JSON format accepted by RunnerService
+	// (query, session, callback, workflow, metadata, context).
+	Request       *structpb.Struct `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteWorkflowRequest) Reset() {
+	*x = ExecuteWorkflowRequest{}
+	mi := &file_workflowrunner_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteWorkflowRequest) ProtoMessage() {}
+
+func (x *ExecuteWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecuteWorkflowRequest) GetRequest() *structpb.Struct {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type ExecuteWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       *RunSummary            `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteWorkflowResponse) Reset() {
+	*x = ExecuteWorkflowResponse{}
+	mi := &file_workflowrunner_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteWorkflowResponse) ProtoMessage() {}
+
+func (x *ExecuteWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExecuteWorkflowResponse) GetSummary() *RunSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type RunSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowName   string                 `protobuf:"bytes,1,opt,name=workflow_name,json=workflowName,proto3" json:"workflow_name,omitempty"`
+	SessionId      string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	FinalOutput    *structpb.Value        `protobuf:"bytes,3,opt,name=final_output,json=finalOutput,proto3" json:"final_output,omitempty"`
+	LastResponseId string                 `protobuf:"bytes,4,opt,name=last_response_id,json=lastResponseId,proto3" json:"last_response_id,omitempty"`
+	// Set when the run failed.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSummary) Reset() {
+	*x = RunSummary{}
+	mi := &file_workflowrunner_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSummary) ProtoMessage() {}
+
+func (x *RunSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSummary.ProtoReflect.Descriptor instead.
+func (*RunSummary) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RunSummary) GetWorkflowName() string {
+	if x != nil {
+		return x.WorkflowName
+	}
+	return ""
+}
+
+func (x *RunSummary) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RunSummary) GetFinalOutput() *structpb.Value {
+	if x != nil {
+		return x.FinalOutput
+	}
+	return nil
+}
+
+func (x *RunSummary) GetLastResponseId() string {
+	if x != nil {
+		return x.LastResponseId
+	}
+	return ""
+}
+
+func (x *RunSummary) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// WorkflowEvent mirrors workflowrunner.CallbackEvent.
+type WorkflowEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. run.started, run.event, run.completed, run.failed.
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Payload       *structpb.Value        `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowEvent) Reset() {
+	*x = WorkflowEvent{}
+	mi := &file_workflowrunner_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowEvent) ProtoMessage() {}
+
+func (x *WorkflowEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowEvent.ProtoReflect.Descriptor instead.
+func (*WorkflowEvent) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WorkflowEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WorkflowEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *WorkflowEvent) GetPayload() *structpb.Value {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *WorkflowEvent) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ContinueConversationRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// The message, in the same JSON format accepted by RunnerService.Continue
+	// (message, credentials).
+	Message       *structpb.Struct `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContinueConversationRequest) Reset() {
+	*x = ContinueConversationRequest{}
+	mi := &file_workflowrunner_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContinueConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContinueConversationRequest) ProtoMessage() {}
+
+func (x *ContinueConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContinueConversationRequest.ProtoReflect.Descriptor instead.
+func (*ContinueConversationRequest) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ContinueConversationRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ContinueConversationRequest) GetMessage() *structpb.Struct {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type ResolveApprovalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Approve       bool                   `protobuf:"varint,3,opt,name=approve,proto3" json:"approve,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveApprovalRequest) Reset() {
+	*x = ResolveApprovalRequest{}
+	mi := &file_workflowrunner_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveApprovalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveApprovalRequest) ProtoMessage() {}
+
+func (x *ResolveApprovalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveApprovalRequest.ProtoReflect.Descriptor instead.
+func (*ResolveApprovalRequest) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResolveApprovalRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ResolveApprovalRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ResolveApprovalRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ResolveApprovalRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_workflowrunner_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStateRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// ExecutionState mirrors workflowrunner.WorkflowExecutionState.
+type ExecutionState struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SessionId         string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	WorkflowName      string                 `protobuf:"bytes,2,opt,name=workflow_name,json=workflowName,proto3" json:"workflow_name,omitempty"`
+	Status            string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	LastAgent         string                 `protobuf:"bytes,4,opt,name=last_agent,json=lastAgent,proto3" json:"last_agent,omitempty"`
+	LastResponseId    string                 `protobuf:"bytes,5,opt,name=last_response_id,json=lastResponseId,proto3" json:"last_response_id,omitempty"`
+	LastQuery         string                 `protobuf:"bytes,6,opt,name=last_query,json=lastQuery,proto3" json:"last_query,omitempty"`
+	LastError         string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	PendingApprovals  []*ApprovalRequest     `protobuf:"bytes,8,rep,name=pending_approvals,json=pendingApprovals,proto3" json:"pending_approvals,omitempty"`
+	ResolvedApprovals []*ApprovalDecision    `protobuf:"bytes,9,rep,name=resolved_approvals,json=resolvedApprovals,proto3" json:"resolved_approvals,omitempty"`
+	FinalOutput       *structpb.Value        `protobuf:"bytes,10,opt,name=final_output,json=finalOutput,proto3" json:"final_output,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExecutionState) Reset() {
+	*x = ExecutionState{}
+	mi := &file_workflowrunner_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionState) ProtoMessage() {}
+
+func (x *ExecutionState) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionState.ProtoReflect.Descriptor instead.
+func (*ExecutionState) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExecutionState) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetWorkflowName() string {
+	if x != nil {
+		return x.WorkflowName
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetLastAgent() string {
+	if x != nil {
+		return x.LastAgent
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetLastResponseId() string {
+	if x != nil {
+		return x.LastResponseId
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetLastQuery() string {
+	if x != nil {
+		return x.LastQuery
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ExecutionState) GetPendingApprovals() []*ApprovalRequest {
+	if x != nil {
+		return x.PendingApprovals
+	}
+	return nil
+}
+
+func (x *ExecutionState) GetResolvedApprovals() []*ApprovalDecision {
+	if x != nil {
+		return x.ResolvedApprovals
+	}
+	return nil
+}
+
+func (x *ExecutionState) GetFinalOutput() *structpb.Value {
+	if x != nil {
+		return x.FinalOutput
+	}
+	return nil
+}
+
+func (x *ExecutionState) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ApprovalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	ToolName      string                 `protobuf:"bytes,3,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"`
+	ServerLabel   string                 `protobuf:"bytes,4,opt,name=server_label,json=serverLabel,proto3" json:"server_label,omitempty"`
+	Arguments     string                 `protobuf:"bytes,5,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovalRequest) Reset() {
+	*x = ApprovalRequest{}
+	mi := &file_workflowrunner_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalRequest) ProtoMessage() {}
+
+func (x *ApprovalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalRequest.ProtoReflect.Descriptor instead.
+func (*ApprovalRequest) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ApprovalRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ApprovalRequest) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *ApprovalRequest) GetToolName() string {
+	if x != nil {
+		return x.ToolName
+	}
+	return ""
+}
+
+func (x *ApprovalRequest) GetServerLabel() string {
+	if x != nil {
+		return x.ServerLabel
+	}
+	return ""
+}
+
+func (x *ApprovalRequest) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
+func (x *ApprovalRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ApprovalDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Approve       bool                   `protobuf:"varint,2,opt,name=approve,proto3" json:"approve,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ResolvedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovalDecision) Reset() {
+	*x = ApprovalDecision{}
+	mi := &file_workflowrunner_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovalDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalDecision) ProtoMessage() {}
+
+func (x *ApprovalDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_workflowrunner_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalDecision.ProtoReflect.Descriptor instead.
+func (*ApprovalDecision) Descriptor() ([]byte, []int) {
+	return file_workflowrunner_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ApprovalDecision) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ApprovalDecision) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ApprovalDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ApprovalDecision) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+var File_workflowrunner_proto protoreflect.FileDescriptor
+
+const file_workflowrunner_proto_rawDesc = "" +
+	"\n" +
+	"\x14workflowrunner.proto\x12\x11workflowrunner.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"K\n" +
+	"\x16ExecuteWorkflowRequest\x121\n" +
+	"\arequest\x18\x01 \x01(\v2\x17.google.protobuf.StructR\arequest\"R\n" +
+	"\x17ExecuteWorkflowResponse\x127\n" +
+	"\asummary\x18\x01 \x01(\v2\x1d.workflowrunner.v1.RunSummaryR\asummary\"\xcb\x01\n" +
+	"\n" +
+	"RunSummary\x12#\n" +
+	"\rworkflow_name\x18\x01 \x01(\tR\fworkflowName\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x129\n" +
+	"\ffinal_output\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\vfinalOutput\x12(\n" +
+	"\x10last_response_id\x18\x04 \x01(\tR\x0elastResponseId\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\xc4\x01\n" +
+	"\rWorkflowEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x120\n" +
+	"\apayload\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\apayload\x123\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"o\n" +
+	"\x1bContinueConversationRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x121\n" +
+	"\amessage\x18\x02 \x01(\v2\x17.google.protobuf.StructR\amessage\"\x88\x01\n" +
+	"\x16ResolveApprovalRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x18\n" +
+	"\aapprove\x18\x03 \x01(\bR\aapprove\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"0\n" +
+	"\x0fGetStateRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x8e\x04\n" +
+	"\x0eExecutionState\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12#\n" +
+	"\rworkflow_name\x18\x02 \x01(\tR\fworkflowName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"last_agent\x18\x04 \x01(\tR\tlastAgent\x12(\n" +
+	"\x10last_response_id\x18\x05 \x01(\tR\x0elastResponseId\x12\x1d\n" +
+	"\n" +
+	"last_query\x18\x06 \x01(\tR\tlastQuery\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x12O\n" +
+	"\x11pending_approvals\x18\b \x03(\v2\".workflowrunner.v1.ApprovalRequestR\x10pendingApprovals\x12R\n" +
+	"\x12resolved_approvals\x18\t \x03(\v2#.workflowrunner.v1.ApprovalDecisionR\x11resolvedApprovals\x129\n" +
+	"\ffinal_output\x18\n" +
+	" \x01(\v2\x16.google.protobuf.ValueR\vfinalOutput\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe8\x01\n" +
+	"\x0fApprovalRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x02 \x01(\tR\tagentName\x12\x1b\n" +
+	"\ttool_name\x18\x03 \x01(\tR\btoolName\x12!\n" +
+	"\fserver_label\x18\x04 \x01(\tR\vserverLabel\x12\x1c\n" +
+	"\targuments\x18\x05 \x01(\tR\targuments\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa0\x01\n" +
+	"\x10ApprovalDecision\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x18\n" +
+	"\aapprove\x18\x02 \x01(\bR\aapprove\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12;\n" +
+	"\vresolved_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt2\xea\x04\n" +
+	"\x0eWorkflowRunner\x12h\n" +
+	"\x0fExecuteWorkflow\x12).workflowrunner.v1.ExecuteWorkflowRequest\x1a*.workflowrunner.v1.ExecuteWorkflowResponse\x12]\n" +
+	"\fStreamEvents\x12).workflowrunner.v1.ExecuteWorkflowRequest\x1a .workflowrunner.v1.WorkflowEvent0\x01\x12g\n" +
+	"\x0eResumeWorkflow\x12).workflowrunner.v1.ExecuteWorkflowRequest\x1a*.workflowrunner.v1.ExecuteWorkflowResponse\x12r\n" +
+	"\x14ContinueConversation\x12..workflowrunner.v1.ContinueConversationRequest\x1a*.workflowrunner.v1.ExecuteWorkflowResponse\x12_\n" +
+	"\x0fResolveApproval\x12).workflowrunner.v1.ResolveApprovalRequest\x1a!.workflowrunner.v1.ExecutionState\x12Q\n" +
+	"\bGetState\x12\".workflowrunner.v1.GetStateRequest\x1a!.workflowrunner.v1.ExecutionStateBSZQgithub.com/nlpodyssey/openai-agents-go/workflowrunner/grpcserver/workflowrunnerpbb\x06proto3"
+
+var (
+	file_workflowrunner_proto_rawDescOnce sync.Once
+	file_workflowrunner_proto_rawDescData []byte
+)
+
+func file_workflowrunner_proto_rawDescGZIP() []byte {
+	file_workflowrunner_proto_rawDescOnce.Do(func() {
+		file_workflowrunner_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_workflowrunner_proto_rawDesc), len(file_workflowrunner_proto_rawDesc)))
+	})
+	return file_workflowrunner_proto_rawDescData
+}
+
+var file_workflowrunner_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_workflowrunner_proto_goTypes = []any{
+	(*ExecuteWorkflowRequest)(nil),      // 0: workflowrunner.v1.ExecuteWorkflowRequest
+	(*ExecuteWorkflowResponse)(nil),     // 1: workflowrunner.v1.ExecuteWorkflowResponse
+	(*RunSummary)(nil),                  // 2: workflowrunner.v1.RunSummary
+	(*WorkflowEvent)(nil),               // 3: workflowrunner.v1.WorkflowEvent
+	(*ContinueConversationRequest)(nil), // 4: workflowrunner.v1.ContinueConversationRequest
+	(*ResolveApprovalRequest)(nil),      // 5: workflowrunner.v1.ResolveApprovalRequest
+	(*GetStateRequest)(nil),             // 6: workflowrunner.v1.GetStateRequest
+	(*ExecutionState)(nil),              // 7: workflowrunner.v1.ExecutionState
+	(*ApprovalRequest)(nil),             // 8: workflowrunner.v1.ApprovalRequest
+	(*ApprovalDecision)(nil),            // 9: workflowrunner.v1.ApprovalDecision
+	(*structpb.Struct)(nil),             // 10: google.protobuf.Struct
+	(*structpb.Value)(nil),              // 11: google.protobuf.Value
+	(*timestamppb.Timestamp)(nil),       // 12: google.protobuf.Timestamp
+}
+var file_workflowrunner_proto_depIdxs = []int32{
+	10, // 0: workflowrunner.v1.ExecuteWorkflowRequest.request:type_name -> google.protobuf.Struct
+	2,  // 1: workflowrunner.v1.ExecuteWorkflowResponse.summary:type_name -> workflowrunner.v1.RunSummary
+	11, // 2: workflowrunner.v1.RunSummary.final_output:type_name -> google.protobuf.Value
+	12, // 3: workflowrunner.v1.WorkflowEvent.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 4: workflowrunner.v1.WorkflowEvent.payload:type_name -> google.protobuf.Value
+	10, // 5: workflowrunner.v1.WorkflowEvent.metadata:type_name -> google.protobuf.Struct
+	10, // 6: workflowrunner.v1.ContinueConversationRequest.message:type_name -> google.protobuf.Struct
+	8,  // 7: workflowrunner.v1.ExecutionState.pending_approvals:type_name -> workflowrunner.v1.ApprovalRequest
+	9,  // 8: workflowrunner.v1.ExecutionState.resolved_approvals:type_name -> workflowrunner.v1.ApprovalDecision
+	11, // 9: workflowrunner.v1.ExecutionState.final_output:type_name -> google.protobuf.Value
+	12, // 10: workflowrunner.v1.ExecutionState.updated_at:type_name -> google.protobuf.Timestamp
+	12, // 11: workflowrunner.v1.ApprovalRequest.created_at:type_name -> google.protobuf.Timestamp
+	12, // 12: workflowrunner.v1.ApprovalDecision.resolved_at:type_name -> google.protobuf.Timestamp
+	0,  // 13: workflowrunner.v1.WorkflowRunner.ExecuteWorkflow:input_type -> workflowrunner.v1.ExecuteWorkflowRequest
+	0,  // 14: workflowrunner.v1.WorkflowRunner.StreamEvents:input_type -> workflowrunner.v1.ExecuteWorkflowRequest
+	0,  // 15: workflowrunner.v1.WorkflowRunner.ResumeWorkflow:input_type -> workflowrunner.v1.ExecuteWorkflowRequest
+	4,  // 16: workflowrunner.v1.WorkflowRunner.ContinueConversation:input_type -> workflowrunner.v1.ContinueConversationRequest
+	5,  // 17: workflowrunner.v1.WorkflowRunner.ResolveApproval:input_type -> workflowrunner.v1.ResolveApprovalRequest
+	6,  // 18: workflowrunner.v1.WorkflowRunner.GetState:input_type -> workflowrunner.v1.GetStateRequest
+	1,  // 19: workflowrunner.v1.WorkflowRunner.ExecuteWorkflow:output_type -> workflowrunner.v1.ExecuteWorkflowResponse
+	3,  // 20: workflowrunner.v1.WorkflowRunner.StreamEvents:output_type -> workflowrunner.v1.WorkflowEvent
+	1,  // 21: workflowrunner.v1.WorkflowRunner.ResumeWorkflow:output_type -> workflowrunner.v1.ExecuteWorkflowResponse
+	1,  // 22: workflowrunner.v1.WorkflowRunner.ContinueConversation:output_type -> workflowrunner.v1.ExecuteWorkflowResponse
+	7,  // 23: workflowrunner.v1.WorkflowRunner.ResolveApproval:output_type -> workflowrunner.v1.ExecutionState
+	7,  // 24: workflowrunner.v1.WorkflowRunner.GetState:output_type -> workflowrunner.v1.ExecutionState
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_workflowrunner_proto_init() }
+func file_workflowrunner_proto_init() {
+	if File_workflowrunner_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflowrunner_proto_rawDesc), len(file_workflowrunner_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_workflowrunner_proto_goTypes,
+		DependencyIndexes: file_workflowrunner_proto_depIdxs,
+		MessageInfos:      file_workflowrunner_proto_msgTypes,
+	}.Build()
+	File_workflowrunner_proto = out.File
+	file_workflowrunner_proto_goTypes = nil
+	file_workflowrunner_proto_depIdxs = nil
+}
@@ -0,0 +1,339 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: workflowrunner.proto
+
+package workflowrunnerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorkflowRunner_ExecuteWorkflow_FullMethodName      = "/workflowrunner.v1.WorkflowRunner/ExecuteWorkflow"
+	WorkflowRunner_StreamEvents_FullMethodName         = "/workflowrunner.v1.WorkflowRunner/StreamEvents"
+	WorkflowRunner_ResumeWorkflow_FullMethodName       = "/workflowrunner.v1.WorkflowRunner/ResumeWorkflow"
+	WorkflowRunner_ContinueConversation_FullMethodName = "/workflowrunner.v1.WorkflowRunner/ContinueConversation"
+	WorkflowRunner_ResolveApproval_FullMethodName      = "/workflowrunner.v1.WorkflowRunner/ResolveApproval"
+	WorkflowRunner_GetState_FullMethodName             = "/workflowrunner.v1.WorkflowRunner/GetState"
+)
+
+// WorkflowRunnerClient is the client API for WorkflowRunner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WorkflowRunner exposes the workflowrunner.RunnerService over gRPC.
+type WorkflowRunnerClient interface {
+	// ExecuteWorkflow runs a workflow and waits for its completion.
+	// Events are published to the callback declared in the request.
+	ExecuteWorkflow(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error)
+	// StreamEvents runs a workflow and streams its events to the caller.
+	// The callback declared in the request, if any, is ignored.
+	StreamEvents(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowEvent], error)
+	// ResumeWorkflow resumes a run suspended on approval or input requests,
+	// once they are all resolved, and waits for its completion.
+	ResumeWorkflow(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error)
+	// ContinueConversation runs a new turn of the conversation of a session
+	// with the workflow of its last run, and waits for its completion.
+	ContinueConversation(ctx context.Context, in *ContinueConversationRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error)
+	// ResolveApproval approves or rejects a pending approval request.
+	ResolveApproval(ctx context.Context, in *ResolveApprovalRequest, opts ...grpc.CallOption) (*ExecutionState, error)
+	// GetState returns the execution state of a session.
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*ExecutionState, error)
+}
+
+type workflowRunnerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowRunnerClient(cc grpc.ClientConnInterface) WorkflowRunnerClient {
+	return &workflowRunnerClient{cc}
+}
+
+func (c *workflowRunnerClient) ExecuteWorkflow(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteWorkflowResponse)
+	err := c.cc.Invoke(ctx, WorkflowRunner_ExecuteWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRunnerClient) StreamEvents(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WorkflowEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowRunner_ServiceDesc.Streams[0], WorkflowRunner_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecuteWorkflowRequest, WorkflowEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowRunner_StreamEventsClient = grpc.ServerStreamingClient[WorkflowEvent]
+
+func (c *workflowRunnerClient) ResumeWorkflow(ctx context.Context, in *ExecuteWorkflowRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteWorkflowResponse)
+	err := c.cc.Invoke(ctx, WorkflowRunner_ResumeWorkflow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRunnerClient) ContinueConversation(ctx context.Context, in *ContinueConversationRequest, opts ...grpc.CallOption) (*ExecuteWorkflowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteWorkflowResponse)
+	err := c.cc.Invoke(ctx, WorkflowRunner_ContinueConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRunnerClient) ResolveApproval(ctx context.Context, in *ResolveApprovalRequest, opts ...grpc.CallOption) (*ExecutionState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecutionState)
+	err := c.cc.Invoke(ctx, WorkflowRunner_ResolveApproval_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowRunnerClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*ExecutionState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecutionState)
+	err := c.cc.Invoke(ctx, WorkflowRunner_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkflowRunnerServer is the server API for WorkflowRunner service.
+// All implementations must embed UnimplementedWorkflowRunnerServer
+// for forward compatibility.
+//
+// WorkflowRunner exposes the workflowrunner.RunnerService over gRPC.
+type WorkflowRunnerServer interface {
+	// ExecuteWorkflow runs a workflow and waits for its completion.
+	// Events are published to the callback declared in the request.
+	ExecuteWorkflow(context.Context, *ExecuteWorkflowRequest) (*ExecuteWorkflowResponse, error)
+	// StreamEvents runs a workflow and streams its events to the caller.
+	// The callback declared in the request, if any, is ignored.
+	StreamEvents(*ExecuteWorkflowRequest, grpc.ServerStreamingServer[WorkflowEvent]) error
+	// ResumeWorkflow resumes a run suspended on approval or input requests,
+	// once they are all resolved, and waits for its completion.
+	ResumeWorkflow(context.Context, *ExecuteWorkflowRequest) (*ExecuteWorkflowResponse, error)
+	// ContinueConversation runs a new turn of the conversation of a session
+	// with the workflow of its last run, and waits for its completion.
+	ContinueConversation(context.Context, *ContinueConversationRequest) (*ExecuteWorkflowResponse, error)
+	// ResolveApproval approves or rejects a pending approval request.
+	ResolveApproval(context.Context, *ResolveApprovalRequest) (*ExecutionState, error)
+	// GetState returns the execution state of a session.
+	GetState(context.Context, *GetStateRequest) (*ExecutionState, error)
+	mustEmbedUnimplementedWorkflowRunnerServer()
+}
+
+// UnimplementedWorkflowRunnerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkflowRunnerServer struct{}
+
+func (UnimplementedWorkflowRunnerServer) ExecuteWorkflow(context.Context, *ExecuteWorkflowRequest) (*ExecuteWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteWorkflow not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) StreamEvents(*ExecuteWorkflowRequest, grpc.ServerStreamingServer[WorkflowEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) ResumeWorkflow(context.Context, *ExecuteWorkflowRequest) (*ExecuteWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWorkflow not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) ContinueConversation(context.Context, *ContinueConversationRequest) (*ExecuteWorkflowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ContinueConversation not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) ResolveApproval(context.Context, *ResolveApprovalRequest) (*ExecutionState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveApproval not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) GetState(context.Context, *GetStateRequest) (*ExecutionState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedWorkflowRunnerServer) mustEmbedUnimplementedWorkflowRunnerServer() {}
+func (UnimplementedWorkflowRunnerServer) testEmbeddedByValue()                        {}
+
+// UnsafeWorkflowRunnerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowRunnerServer will
+// result in compilation errors.
+type UnsafeWorkflowRunnerServer interface {
+	mustEmbedUnimplementedWorkflowRunnerServer()
+}
+
+func RegisterWorkflowRunnerServer(s grpc.ServiceRegistrar, srv WorkflowRunnerServer) {
+	// If the following call pancis, it indicates UnimplementedWorkflowRunnerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkflowRunner_ServiceDesc, srv)
+}
+
+func _WorkflowRunner_ExecuteWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRunnerServer).ExecuteWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRunner_ExecuteWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRunnerServer).ExecuteWorkflow(ctx, req.(*ExecuteWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRunner_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteWorkflowRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowRunnerServer).StreamEvents(m, &grpc.GenericServerStream[ExecuteWorkflowRequest, WorkflowEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowRunner_StreamEventsServer = grpc.ServerStreamingServer[WorkflowEvent]
+
+func _WorkflowRunner_ResumeWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRunnerServer).ResumeWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRunner_ResumeWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRunnerServer).ResumeWorkflow(ctx, req.(*ExecuteWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRunner_ContinueConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContinueConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRunnerServer).ContinueConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRunner_ContinueConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRunnerServer).ContinueConversation(ctx, req.(*ContinueConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRunner_ResolveApproval_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveApprovalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRunnerServer).ResolveApproval(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRunner_ResolveApproval_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRunnerServer).ResolveApproval(ctx, req.(*ResolveApprovalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowRunner_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowRunnerServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowRunner_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowRunnerServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorkflowRunner_ServiceDesc is the grpc.ServiceDesc for WorkflowRunner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowRunner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "workflowrunner.v1.WorkflowRunner",
+	HandlerType: (*WorkflowRunnerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExecuteWorkflow",
+			Handler:    _WorkflowRunner_ExecuteWorkflow_Handler,
+		},
+		{
+			MethodName: "ResumeWorkflow",
+			Handler:    _WorkflowRunner_ResumeWorkflow_Handler,
+		},
+		{
+			MethodName: "ContinueConversation",
+			Handler:    _WorkflowRunner_ContinueConversation_Handler,
+		},
+		{
+			MethodName: "ResolveApproval",
+			Handler:    _WorkflowRunner_ResolveApproval_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _WorkflowRunner_GetState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _WorkflowRunner_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "workflowrunner.proto",
+}
@@ -2,9 +2,13 @@ package workflowrunner
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 )
@@ -55,6 +59,14 @@ func buildOutputGuardrails(ctx context.Context, decls []GuardrailDeclaration) ([
 		if err != nil {
 			return nil, fmt.Errorf("build output guardrail %q: %w", decl.Name, err)
 		}
+		// Turns suspended on ask_user calls have no output to check.
+		check := gr.GuardrailFunction
+		gr.GuardrailFunction = func(ctx context.Context, agent *agents.Agent, output any) (agents.GuardrailFunctionOutput, error) {
+			if _, ok := output.(inputSuspension); ok {
+				return agents.GuardrailFunctionOutput{}, nil
+			}
+			return check(ctx, agent, output)
+		}
 		guardrails = append(guardrails, gr)
 	}
 	return guardrails, nil
@@ -177,3 +189,68 @@ func containsProfanity(text string) bool {
 	}
 	return false
 }
+
+// stepGuardrailResults returns the results of the guardrails evaluated by a
+// step of a run starting with agent, whose stream ended with streamErr. The
+// result of a guardrail whose tripwire ended the stream is only in the error.
+func stepGuardrailResults(agent *agents.Agent, result *agents.RunResultStreaming, streamErr error, now time.Time) []GuardrailResultState {
+	var results []GuardrailResultState
+	addInput := func(r agents.InputGuardrailResult) {
+		results = append(results, GuardrailResultState{
+			Guardrail:         r.Guardrail.Name,
+			Kind:              "input",
+			Agent:             displayAgentName(agent),
+			TripwireTriggered: r.Output.TripwireTriggered,
+			OutputInfo:        guardrailOutputInfo(r.Output.OutputInfo),
+			EvaluatedAt:       now,
+		})
+	}
+	addOutput := func(r agents.OutputGuardrailResult) {
+		results = append(results, GuardrailResultState{
+			Guardrail:         r.Guardrail.Name,
+			Kind:              "output",
+			Agent:             displayAgentName(r.Agent),
+			TripwireTriggered: r.Output.TripwireTriggered,
+			OutputInfo:        guardrailOutputInfo(r.Output.OutputInfo),
+			EvaluatedAt:       now,
+		})
+	}
+	recorded := func(kind, name string) bool {
+		return slices.ContainsFunc(results, func(r GuardrailResultState) bool {
+			return r.Kind == kind && r.Guardrail == name
+		})
+	}
+
+	for _, r := range result.InputGuardrailResults() {
+		addInput(r)
+	}
+	for _, r := range result.OutputGuardrailResults() {
+		addOutput(r)
+	}
+	var inputTripwire agents.InputGuardrailTripwireTriggeredError
+	if errors.As(streamErr, &inputTripwire) && !recorded("input", inputTripwire.GuardrailResult.Guardrail.Name) {
+		addInput(inputTripwire.GuardrailResult)
+	}
+	var outputTripwire agents.OutputGuardrailTripwireTriggeredError
+	if errors.As(streamErr, &outputTripwire) && !recorded("output", outputTripwire.GuardrailResult.Guardrail.Name) {
+		addOutput(outputTripwire.GuardrailResult)
+	}
+	return results
+}
+
+// guardrailOutputInfo returns the JSON value of the output info of a
+// guardrail, or its string form if it cannot be marshaled.
+func guardrailOutputInfo(info any) any {
+	if info == nil {
+		return nil
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Sprint(info)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Sprint(info)
+	}
+	return value
+}
@@ -0,0 +1,68 @@
+package workflowrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepGuardrailResults(t *testing.T) {
+	ctx := t.Context()
+	inputGuardrails, err := buildInputGuardrails(ctx, []GuardrailDeclaration{{Name: "math_homework_input"}})
+	require.NoError(t, err)
+	outputGuardrails, err := buildOutputGuardrails(ctx, []GuardrailDeclaration{{Name: "phone_number_output"}})
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	run := func(input, output string) ([]GuardrailResultState, error) {
+		model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+			Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage(output)},
+		})
+		agent := &agents.Agent{
+			Name:             "tutor",
+			InputGuardrails:  inputGuardrails,
+			OutputGuardrails: outputGuardrails,
+			Model:            param.NewOpt(agents.NewAgentModel(model)),
+		}
+		result, err := agents.Runner{}.RunStreamed(ctx, agent, input)
+		require.NoError(t, err)
+		streamErr := result.StreamEvents(func(agents.StreamEvent) error { return nil })
+		return stepGuardrailResults(agent, result, streamErr, now), streamErr
+	}
+
+	results, streamErr := run("please do my math homework", "x = 2")
+	assert.ErrorAs(t, streamErr, &agents.InputGuardrailTripwireTriggeredError{})
+	require.NotEmpty(t, results)
+	assert.Equal(t, GuardrailResultState{
+		Guardrail:         "math_homework_input",
+		Kind:              "input",
+		Agent:             "tutor",
+		TripwireTriggered: true,
+		OutputInfo:        map[string]any{"keyword": "math homework"},
+		EvaluatedAt:       now,
+	}, results[0])
+
+	results, streamErr = run("hello", "call me at 555-123-4567")
+	assert.ErrorAs(t, streamErr, &agents.OutputGuardrailTripwireTriggeredError{})
+	require.Len(t, results, 2)
+	assert.Equal(t, "input", results[0].Kind)
+	assert.False(t, results[0].TripwireTriggered)
+	assert.Equal(t, "phone_number_output", results[1].Guardrail)
+	assert.Equal(t, "output", results[1].Kind)
+	assert.Equal(t, "tutor", results[1].Agent)
+	assert.True(t, results[1].TripwireTriggered)
+}
+
+func TestGuardrailOutputInfo(t *testing.T) {
+	type info struct {
+		Keyword string `json:"keyword"`
+	}
+	assert.Nil(t, guardrailOutputInfo(nil))
+	assert.Equal(t, map[string]any{"keyword": "algebra"}, guardrailOutputInfo(info{Keyword: "algebra"}))
+	assert.Equal(t, "(1+2i)", guardrailOutputInfo(complex(1, 2)))
+}
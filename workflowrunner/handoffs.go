@@ -0,0 +1,139 @@
+package workflowrunner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// buildHandoffs returns the handoffs declared by decl. Targets with a
+// condition are wrapped in a handoff enabled only when the run matches it.
+func buildHandoffs(req WorkflowRequest, decl AgentDeclaration, agentMap map[string]*agents.Agent) ([]*agents.Agent, []agents.Handoff, error) {
+	conditions := make(map[string]HandoffConditionDeclaration, len(decl.HandoffConditions))
+	for _, cond := range decl.HandoffConditions {
+		conditions[cond.Agent] = cond
+	}
+
+	var (
+		handoffAgents []*agents.Agent
+		handoffs      []agents.Handoff
+	)
+	for _, ref := range decl.Handoffs {
+		target, ok := agentMap[ref]
+		if !ok {
+			return nil, nil, fmt.Errorf("agent %q references unknown handoff agent %q", decl.Name, ref)
+		}
+		cond, ok := conditions[ref]
+		if !ok && len(conditions) == 0 {
+			handoffAgents = append(handoffAgents, target)
+			continue
+		}
+		// Once an agent has conditions, all its handoffs are built here to
+		// keep them in the declared order.
+		params := agents.HandoffFromAgentParams{Agent: target}
+		if ok {
+			enabler, err := compileHandoffCondition(req, decl.Name, cond)
+			if err != nil {
+				return nil, nil, fmt.Errorf("agent %q handoff condition %q: %w", decl.Name, ref, err)
+			}
+			params.IsEnabled = enabler
+		}
+		handoff, err := agents.SafeHandoffFromAgent(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("agent %q handoff %q: %w", decl.Name, ref, err)
+		}
+		handoffs = append(handoffs, *handoff)
+	}
+	return handoffAgents, handoffs, nil
+}
+
+// compileHandoffCondition returns the enabler of the handoff declared by
+// cond. It is evaluated against the handoffRun of the context, falling back
+// to req for runs not started by a RunnerService.
+func compileHandoffCondition(req WorkflowRequest, agentName string, cond HandoffConditionDeclaration) (agents.HandoffEnabler, error) {
+	var expr *template.Template
+	if cond.Expression != "" {
+		var err error
+		if expr, err = parseRouteExpression(cond.Expression); err != nil {
+			return nil, fmt.Errorf("expression: %w", err)
+		}
+	}
+	fallback := newHandoffRun(req)
+	return agents.HandoffEnablerFunc(func(ctx context.Context, _ *agents.Agent) (bool, error) {
+		run := handoffRunFromContext(ctx)
+		if run == nil {
+			run = fallback
+		}
+		credentials := run.req.Session.Credentials
+		if missing := missingCapabilities(credentials.Capabilities, cond.RequiredCapabilities); len(missing) > 0 {
+			return false, nil
+		}
+		if expr == nil {
+			return true, nil
+		}
+		var sb strings.Builder
+		if err := expr.Execute(&sb, run.conditionData(agentName, cond.Agent)); err != nil {
+			return false, fmt.Errorf("handoff condition %q: %w", cond.Agent, err)
+		}
+		return strings.TrimSpace(sb.String()) == "true", nil
+	}), nil
+}
+
+// handoffRun holds the data of a run which handoff conditions are evaluated
+// against: its request, and the outputs of the agents completed so far.
+type handoffRun struct {
+	req     WorkflowRequest
+	mu      sync.Mutex
+	output  any
+	outputs map[string]any
+}
+
+type handoffRunKey struct{}
+
+func newHandoffRun(req WorkflowRequest) *handoffRun {
+	return &handoffRun{req: req, outputs: make(map[string]any)}
+}
+
+// contextWithHandoffRun returns a context whose handoff conditions are
+// evaluated against run.
+func contextWithHandoffRun(ctx context.Context, run *handoffRun) context.Context {
+	return context.WithValue(ctx, handoffRunKey{}, run)
+}
+
+func handoffRunFromContext(ctx context.Context) *handoffRun {
+	run, _ := ctx.Value(handoffRunKey{}).(*handoffRun)
+	return run
+}
+
+// recordOutput records the output of the agent declared as agentName, which
+// completed a step of the run.
+func (r *handoffRun) recordOutput(agentName string, output any) {
+	output = normalizeJSONValue(output)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.output = output
+	r.outputs[agentName] = output
+}
+
+// conditionData returns the data of the handoff conditions of agentName.
+func (r *handoffRun) conditionData(agentName, target string) map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return map[string]any{
+		"agent":        agentName,
+		"target":       target,
+		"output":       r.output,
+		"outputs":      maps.Clone(r.outputs),
+		"metadata":     r.req.Metadata,
+		"context":      r.req.Context,
+		"inputs":       r.req.Inputs,
+		"capabilities": r.req.Session.Credentials.Capabilities,
+		"user_id":      r.req.Session.Credentials.UserID,
+		"account_id":   r.req.Session.Credentials.AccountID,
+	}
+}
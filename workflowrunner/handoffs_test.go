@@ -0,0 +1,108 @@
+package workflowrunner
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandoffConditionUsesRunData(t *testing.T) {
+	ctx := t.Context()
+	req := WorkflowRequest{Inputs: map[string]any{"tier": "basic"}}
+	enabler, err := compileHandoffCondition(req, "assistant", HandoffConditionDeclaration{
+		Agent:                "billing",
+		RequiredCapabilities: []string{"billing:write"},
+		Expression:           `{{ if eq .inputs.tier "gold" }}{{ with .outputs.triage }}{{ eq .topic "billing" }}{{ end }}{{ end }}`,
+	})
+	require.NoError(t, err)
+
+	// Outside of a run, the request of the build is used.
+	enabled, err := enabler.IsEnabled(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	req.Inputs = map[string]any{"tier": "gold"}
+	req.Session.Credentials.Capabilities = []string{"billing:write"}
+	run := newHandoffRun(req)
+	ctx = contextWithHandoffRun(ctx, run)
+	enabled, err = enabler.IsEnabled(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	run.recordOutput("triage", struct {
+		Topic string `json:"topic"`
+	}{Topic: "billing"})
+	enabled, err = enabler.IsEnabled(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+// handoffRecordingModel records the handoffs offered on each call of the
+// wrapped model.
+type handoffRecordingModel struct {
+	*agentstesting.FakeModel
+
+	mu      sync.Mutex
+	offered [][]string
+}
+
+func (m *handoffRecordingModel) StreamResponse(ctx context.Context, params agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	var names []string
+	for _, handoff := range params.Handoffs {
+		names = append(names, handoff.ToolName)
+	}
+	m.mu.Lock()
+	m.offered = append(m.offered, names)
+	m.mu.Unlock()
+	return m.FakeModel.StreamResponse(ctx, params, yield)
+}
+
+func TestHandoffConditionSeesPriorOutputs(t *testing.T) {
+	ctx := t.Context()
+	model := &handoffRecordingModel{FakeModel: agentstesting.NewFakeModel(false, nil)}
+	service := newTestService(t, model, &recordingPublisher{})
+	modelDecl := &ModelDeclaration{Provider: "test", Model: "fake"}
+	req := testRequest("s1")
+	req.Workflow.StartingAgent = "triage"
+	req.Workflow.Agents = []AgentDeclaration{
+		{
+			Name:         "triage",
+			Instructions: "Classify.",
+			Model:        modelDecl,
+			Routes:       []RouteDeclaration{{Next: "assistant"}},
+		},
+		{
+			Name:              "assistant",
+			Instructions:      "Help.",
+			Model:             modelDecl,
+			Handoffs:          []string{"billing"},
+			HandoffConditions: []HandoffConditionDeclaration{{Agent: "billing", Expression: `{{ eq .output "billing" }}`}},
+		},
+		{Name: "billing", Instructions: "Bill.", Model: modelDecl},
+	}
+
+	run := func(topic string) []string {
+		model.offered = nil
+		model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage(topic)}},
+			{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+		})
+		task, err := service.Execute(ctx, req)
+		require.NoError(t, err)
+		result := task.Await()
+		require.NoError(t, result.Error)
+		assert.Equal(t, "done", result.Value.FinalOutput)
+		require.Len(t, model.offered, 2)
+		assert.Empty(t, model.offered[0])
+		return model.offered[1]
+	}
+
+	assert.Equal(t, []string{"transfer_to_billing"}, run("billing"))
+	req.Session.SessionID = "s2"
+	assert.Empty(t, run("shipping"))
+}
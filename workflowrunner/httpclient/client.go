@@ -0,0 +1,218 @@
+// Package httpclient is a client of the HTTP API of workflowrunner, served by
+// workflowrunner.RunHandler and described by workflowrunner.OpenAPIDocument.
+//
+//	client := httpclient.New("http://localhost:8080")
+//	resp, err := client.Run(ctx, req)
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+// Error is returned for the error responses of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("workflowrunner API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client calls the HTTP API of workflowrunner.
+type Client struct {
+	// BaseURL where the RunHandler is mounted, e.g. "http://localhost:8080".
+	BaseURL string
+	// HTTPClient defaults to http.DefaultClient. Runs can take long: avoid
+	// client timeouts, and bound the calls with their context instead.
+	HTTPClient *http.Client
+	// Header is added to each request, e.g. for authentication.
+	Header http.Header
+}
+
+// New returns a Client of the API served at baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// Run runs the workflow of the request and waits for its outcome. Failed
+// runs are reported in the Error of the response.
+func (c *Client) Run(ctx context.Context, req workflowrunner.WorkflowRequest) (workflowrunner.RunResponse, error) {
+	var resp workflowrunner.RunResponse
+	err := c.do(ctx, http.MethodPost, "/runs", req, &resp)
+	return resp, err
+}
+
+// Submit starts the run of the workflow of the request without waiting for
+// it. Its events are published to the callback of the request, and its
+// outcome can be followed with GetState.
+func (c *Client) Submit(ctx context.Context, req workflowrunner.WorkflowRequest) (workflowrunner.RunAccepted, error) {
+	var resp workflowrunner.RunAccepted
+	err := c.do(ctx, http.MethodPost, "/runs?async=true", req, &resp)
+	return resp, err
+}
+
+// Resume continues the run of the session of the request, suspended on
+// approval or input requests, and waits for its outcome.
+func (c *Client) Resume(ctx context.Context, req workflowrunner.WorkflowRequest) (workflowrunner.RunResponse, error) {
+	var resp workflowrunner.RunResponse
+	err := c.do(ctx, http.MethodPost, "/runs/resume", req, &resp)
+	return resp, err
+}
+
+// GetState returns the execution state of the session.
+func (c *Client) GetState(ctx context.Context, sessionID string) (workflowrunner.WorkflowExecutionState, error) {
+	var state workflowrunner.WorkflowExecutionState
+	err := c.do(ctx, http.MethodGet, "/sessions/"+url.PathEscape(sessionID)+"/state", nil, &state)
+	return state, err
+}
+
+// ResolveApproval approves or denies a pending approval request of the
+// session, and returns the updated execution state.
+func (c *Client) ResolveApproval(ctx context.Context, sessionID, requestID string, approve bool, reason string) (workflowrunner.WorkflowExecutionState, error) {
+	action := "deny"
+	if approve {
+		action = "approve"
+	}
+	var state workflowrunner.WorkflowExecutionState
+	path := "/sessions/" + url.PathEscape(sessionID) + "/approvals/" + url.PathEscape(requestID) + "/" + action
+	err := c.do(ctx, http.MethodPost, path, map[string]string{"reason": reason}, &state)
+	return state, err
+}
+
+// ProvideInput answers a pending input request of the session, and returns
+// the updated execution state.
+func (c *Client) ProvideInput(ctx context.Context, sessionID, requestID string, answer any) (workflowrunner.WorkflowExecutionState, error) {
+	var state workflowrunner.WorkflowExecutionState
+	path := "/sessions/" + url.PathEscape(sessionID) + "/inputs/" + url.PathEscape(requestID)
+	err := c.do(ctx, http.MethodPost, path, map[string]any{"answer": answer}, &state)
+	return state, err
+}
+
+// Stream runs the workflow of the request, returning the stream of its
+// events. The run is canceled if the stream is closed before its end.
+func (c *Client) Stream(ctx context.Context, req workflowrunner.WorkflowRequest) (*EventStream, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/runs/stream", req)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+// Continue continues the conversation of the session with a new message of
+// the user, returning the stream of the events of the run, see
+// workflowrunner.RunnerService.Continue. The run is authorized with the
+// credentials of the message.
+func (c *Client) Continue(ctx context.Context, sessionID string, message workflowrunner.SessionMessage) (*EventStream, error) {
+	path := "/sessions/" + url.PathEscape(sessionID) + "/messages"
+	resp, err := c.send(ctx, http.MethodPost, path, message)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// send sends the request, returning the successful responses.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for name, values := range c.Header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr workflowrunner.APIError
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = strings.TrimSpace(string(data))
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Message: apiErr.Error}
+	}
+	return resp, nil
+}
+
+// EventStream reads the events of a streamed run.
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// Next returns the next event of the run, or io.EOF once the run is over.
+func (s *EventStream) Next() (workflowrunner.CallbackEvent, error) {
+	var data strings.Builder
+	for {
+		line, err := s.reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		// Event names repeat the type of the data, and comments are ignored.
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+		if line == "" || err != nil {
+			if data.Len() > 0 {
+				return decodeEvent(data.String())
+			}
+			if err != nil {
+				return workflowrunner.CallbackEvent{}, err
+			}
+		}
+	}
+}
+
+// Close closes the stream, canceling the run if it is not over.
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
+
+func decodeEvent(data string) (workflowrunner.CallbackEvent, error) {
+	var event workflowrunner.CallbackEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return event, fmt.Errorf("decode event: %w", err)
+	}
+	return event, nil
+}
@@ -0,0 +1,251 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testModelProvider struct {
+	model agents.Model
+}
+
+func (p testModelProvider) GetModel(string) (agents.Model, error) { return p.model, nil }
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(context.Context, workflowrunner.CallbackEvent) error { return nil }
+
+// newTestClient serves the HTTP API of a RunnerService running the agents
+// with model, and returns a client of it. The headers of the requests are
+// sent to headers, if not nil.
+func newTestClient(t *testing.T, model agents.Model, headers chan<- http.Header) *Client {
+	builder := workflowrunner.NewDefaultBuilder()
+	builder.SessionFactory = workflowrunner.NewSQLiteSessionFactory(t.TempDir())
+	builder.SessionFactories["sqlite"] = builder.SessionFactory
+	builder.ModelProviderFactories["test"] = func(context.Context, workflowrunner.ModelDeclaration) (agents.ModelProvider, error) {
+		return testModelProvider{model: model}, nil
+	}
+	service := workflowrunner.NewRunnerService(builder)
+	service.CallbackFactory = func(context.Context, workflowrunner.CallbackDeclaration) (workflowrunner.CallbackPublisher, error) {
+		return discardPublisher{}, nil
+	}
+	handler := workflowrunner.NewRunHandler(service)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if headers != nil {
+			headers <- r.Header.Clone()
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return New(server.URL + "/")
+}
+
+func testRequest(sessionID string) workflowrunner.WorkflowRequest {
+	return workflowrunner.WorkflowRequest{
+		Query: "hello",
+		Session: workflowrunner.SessionDeclaration{
+			SessionID:   sessionID,
+			Credentials: workflowrunner.CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+		},
+		Callback: workflowrunner.CallbackDeclaration{Target: "https://example.com/hook"},
+		Workflow: workflowrunner.WorkflowDeclaration{
+			Name:          "wf",
+			StartingAgent: "assistant",
+			Agents: []workflowrunner.AgentDeclaration{{
+				Name:         "assistant",
+				Instructions: "Help.",
+				Model:        &workflowrunner.ModelDeclaration{Provider: "test", Model: "fake"},
+			}},
+		},
+	}
+}
+
+// readEvents returns the types of the events of the stream, until its end.
+func readEvents(t *testing.T, stream *EventStream) []string {
+	t.Helper()
+	defer func() { assert.NoError(t, stream.Close()) }()
+	var types []string
+	for {
+		event, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return types
+		}
+		require.NoError(t, err)
+		types = append(types, event.Type)
+	}
+}
+
+func TestClientRunAndContinue(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")}},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("fine")}},
+	})
+	headers := make(chan http.Header, 8)
+	client := newTestClient(t, model, headers)
+	client.Header = http.Header{"Authorization": {"Bearer token"}}
+
+	resp, err := client.Run(ctx, testRequest("s1"))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Error)
+	assert.Equal(t, "s1", resp.SessionID)
+	assert.Equal(t, "hi", resp.FinalOutput)
+	header := <-headers
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	stream, err := client.Continue(ctx, "s1", workflowrunner.SessionMessage{
+		Message:     "how are you?",
+		Credentials: workflowrunner.CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+	})
+	require.NoError(t, err)
+	types := readEvents(t, stream)
+	require.NotEmpty(t, types)
+	assert.Equal(t, workflowrunner.CallbackEventRunStarted, types[0])
+	assert.Equal(t, workflowrunner.CallbackEventRunCompleted, types[len(types)-1])
+
+	state, err := client.GetState(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, workflowrunner.ExecutionStatusCompleted, state.Status)
+	assert.Equal(t, "how are you?", state.LastQuery)
+	assert.Equal(t, "fine", state.FinalOutput)
+}
+
+func TestClientStream(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")},
+	})
+	client := newTestClient(t, model, nil)
+
+	stream, err := client.Stream(t.Context(), testRequest("s1"))
+	require.NoError(t, err)
+	types := readEvents(t, stream)
+	require.NotEmpty(t, types)
+	assert.Equal(t, workflowrunner.CallbackEventRunStarted, types[0])
+	assert.Equal(t, workflowrunner.CallbackEventRunCompleted, types[len(types)-1])
+}
+
+func TestClientApprovalAndResume(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{{
+			ID:          "mcpr_1",
+			Type:        "mcp_approval_request",
+			ServerLabel: "crm",
+			Name:        "delete_contact",
+			Arguments:   `{"id": 7}`,
+		}},
+	})
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("deleted")},
+	})
+	client := newTestClient(t, model, nil)
+	req := testRequest("s1")
+	req.Workflow.Agents[0].MCPServers = []workflowrunner.MCPDeclaration{{
+		ServerLabel:     "crm",
+		Address:         "https://mcp.example.com",
+		RequireApproval: "always",
+	}}
+
+	resp, err := client.Run(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, workflowrunner.ExecutionStatusWaitingApproval, resp.Status)
+	require.Len(t, resp.PendingApprovals, 1)
+
+	_, err = client.Resume(ctx, req)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	state, err := client.ResolveApproval(ctx, "s1", "mcpr_1", true, "ok")
+	require.NoError(t, err)
+	assert.Empty(t, state.PendingApprovals)
+	require.Len(t, state.ResolvedApprovals, 1)
+	assert.Equal(t, "ok", state.ResolvedApprovals[0].Reason)
+
+	resp, err = client.Resume(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, workflowrunner.ExecutionStatusCompleted, resp.Status)
+	assert.Equal(t, "deleted", resp.FinalOutput)
+}
+
+func TestClientSubmit(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("hi")},
+	})
+	client := newTestClient(t, model, nil)
+
+	accepted, err := client.Submit(t.Context(), testRequest("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, "s1", accepted.SessionID)
+}
+
+func TestClientErrors(t *testing.T) {
+	ctx := t.Context()
+	client := newTestClient(t, agentstesting.NewFakeModel(false, nil), nil)
+
+	_, err := client.GetState(ctx, "unknown")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Message, "not found")
+
+	_, err = client.ProvideInput(ctx, "unknown", "r1", "yes")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	invalid := testRequest("s1")
+	invalid.Workflow.StartingAgent = "missing"
+	_, err = client.Stream(ctx, invalid)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+
+	// Error bodies which are not JSON are kept as their message.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	defer server.Close()
+	_, err = New(server.URL).Run(ctx, testRequest("s1"))
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, &Error{StatusCode: http.StatusBadGateway, Message: "upstream unavailable"}, apiErr)
+	assert.EqualError(t, err, "workflowrunner API returned status 502: upstream unavailable")
+}
+
+func TestEventStreamNext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": keep-alive\n\n"+
+			"event: run.started\r\ndata: {\"type\": \"run.started\"}\r\n\r\n"+
+			"event: run.event\ndata: {\"type\": \"run.event\",\ndata: \"payload\": \"a\\nb\"}\n\n"+
+			"data: {\"type\": \"run.completed\"}")
+	}))
+	defer server.Close()
+
+	stream, err := New(server.URL).Stream(t.Context(), testRequest("s1"))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, stream.Close()) }()
+	event, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "run.started", event.Type)
+	event, err = stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "run.event", event.Type)
+	assert.Equal(t, "a\nb", event.Payload)
+	// The last event may lack its blank line.
+	event, err = stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "run.completed", event.Type)
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,73 @@
+package workflowrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nlpodyssey/openai-agents-go/tracing"
+)
+
+// IDGenerator generates the IDs assigned by a RunnerService, e.g. to follow
+// an organization-wide scheme, or to get deterministic IDs in tests. The IDs
+// of the spans are generated by the trace provider, see
+// tracing.DefaultTraceProvider.SetIDGenerator.
+type IDGenerator interface {
+	// TraceID returns the ID of the trace of a run.
+	TraceID() string
+	// ArtifactID returns the ID of an artifact stored by a run.
+	ArtifactID() string
+	// SessionID returns the session ID of a run started by a webhook
+	// trigger.
+	SessionID() string
+}
+
+// RandomIDGenerator is the default IDGenerator, generating random IDs.
+type RandomIDGenerator struct{}
+
+// TraceID returns an ID generated by the trace provider.
+func (RandomIDGenerator) TraceID() string { return tracing.GenTraceID() }
+
+// ArtifactID returns a random UUID.
+func (RandomIDGenerator) ArtifactID() string { return uuid.NewString() }
+
+// SessionID returns a random UUID.
+func (RandomIDGenerator) SessionID() string { return uuid.NewString() }
+
+// Clock returns the current time.
+type Clock func() time.Time
+
+func (s *RunnerService) ids() IDGenerator {
+	if s.IDGenerator == nil {
+		return RandomIDGenerator{}
+	}
+	return s.IDGenerator
+}
+
+// now returns the current time in UTC, as told by the clock of the service.
+func (s *RunnerService) now() time.Time {
+	return s.Clock.now()
+}
+
+type clockContextKey struct{}
+
+// contextWithClock returns a context carrying the clock of a run, for the
+// tools of its agents.
+func contextWithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockContextKey{}, clock)
+}
+
+// clockFromContext returns the clock of the run, or nil for time.Now.
+func clockFromContext(ctx context.Context) Clock {
+	clock, _ := ctx.Value(clockContextKey{}).(Clock)
+	return clock
+}
+
+// now returns the current time in UTC, as told by the clock, or time.Now
+// when nil.
+func (c Clock) now() time.Time {
+	if c == nil {
+		return time.Now().UTC()
+	}
+	return c().UTC()
+}
@@ -0,0 +1,184 @@
+package workflowrunner
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// Types of the workflow inputs.
+const (
+	InputTypeString  = "string"
+	InputTypeNumber  = "number"
+	InputTypeInteger = "integer"
+	InputTypeBoolean = "boolean"
+	InputTypeObject  = "object"
+	InputTypeArray   = "array"
+)
+
+// WorkflowInputDeclaration declares a parameter of the workflow, provided by
+// the callers in the inputs of the requests. Inputs are referenced as
+// ${inputs:NAME} in the declarations, and as {{ .inputs.NAME }} in
+// instruction templates and route expressions.
+type WorkflowInputDeclaration struct {
+	Name string `json:"name" jsonschema:"pattern=^[A-Za-z_][A-Za-z0-9_]*$"`
+	// Type of the value, string by default.
+	Type        string `json:"type,omitempty" jsonschema:"enum=string,enum=number,enum=integer,enum=boolean,enum=object,enum=array"`
+	Description string `json:"description,omitempty"`
+	// Required inputs without default must be provided.
+	Required bool `json:"required,omitempty"`
+	// Default value of the input when not provided.
+	Default any `json:"default,omitempty"`
+	// Enum lists the allowed values, when set.
+	Enum []any `json:"enum,omitempty"`
+}
+
+// identifierPattern matches the names of inputs and SQL schemas.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (d WorkflowInputDeclaration) inputType() string {
+	if d.Type == "" {
+		return InputTypeString
+	}
+	return d.Type
+}
+
+func validateInputDeclarations(inputs []WorkflowInputDeclaration) error {
+	seen := make(map[string]struct{}, len(inputs))
+	for i, input := range inputs {
+		if !identifierPattern.MatchString(input.Name) {
+			return fmt.Errorf("inputs[%d]: invalid name %q", i, input.Name)
+		}
+		if _, dup := seen[input.Name]; dup {
+			return fmt.Errorf("duplicate input name %q", input.Name)
+		}
+		seen[input.Name] = struct{}{}
+		if err := validateInputDeclaration(input); err != nil {
+			return fmt.Errorf("input %q: %w", input.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateInputDeclaration(input WorkflowInputDeclaration) error {
+	switch input.inputType() {
+	case InputTypeString, InputTypeNumber, InputTypeInteger, InputTypeBoolean, InputTypeObject, InputTypeArray:
+	default:
+		return fmt.Errorf("unsupported type %q", input.Type)
+	}
+	for _, value := range input.Enum {
+		if err := checkInputType(input, normalizeJSONValue(value)); err != nil {
+			return fmt.Errorf("enum: %w", err)
+		}
+	}
+	if input.Default != nil {
+		if err := checkInputValue(input, normalizeJSONValue(input.Default)); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveInputs validates the inputs provided by the request against the
+// declarations of the workflow, and returns them with the defaults of the
+// missing ones.
+func resolveInputs(decls []WorkflowInputDeclaration, provided map[string]any) (map[string]any, error) {
+	values := normalizeJSONMap(provided)
+	if unknown := unknownInputs(decls, values); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown input %q", unknown[0])
+	}
+	resolved := make(map[string]any, len(decls))
+	for _, decl := range decls {
+		value, err := resolveInput(decl, values[decl.Name])
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", decl.Name, err)
+		}
+		if value != nil {
+			resolved[decl.Name] = value
+		}
+	}
+	return resolved, nil
+}
+
+// unknownInputs returns the sorted names of the values not declared.
+func unknownInputs(decls []WorkflowInputDeclaration, values map[string]any) []string {
+	var unknown []string
+	for name := range values {
+		if !slices.ContainsFunc(decls, func(d WorkflowInputDeclaration) bool { return d.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// resolveInput checks the JSON-decoded value of an input, returning its
+// default when missing.
+func resolveInput(decl WorkflowInputDeclaration, value any) (any, error) {
+	if value == nil {
+		if decl.Default == nil && decl.Required {
+			return nil, errors.New("value is required")
+		}
+		return normalizeJSONValue(decl.Default), nil
+	}
+	return value, checkInputValue(decl, value)
+}
+
+// checkInputValue checks a JSON-decoded value against the declaration.
+func checkInputValue(decl WorkflowInputDeclaration, value any) error {
+	if err := checkInputType(decl, value); err != nil {
+		return err
+	}
+	if len(decl.Enum) > 0 && !slices.ContainsFunc(decl.Enum, func(allowed any) bool {
+		return reflect.DeepEqual(normalizeJSONValue(allowed), value)
+	}) {
+		return fmt.Errorf("value %s is not one of %s", outputText(value), outputText(decl.Enum))
+	}
+	return nil
+}
+
+func checkInputType(decl WorkflowInputDeclaration, value any) error {
+	var ok bool
+	switch decl.inputType() {
+	case InputTypeString:
+		_, ok = value.(string)
+	case InputTypeNumber:
+		_, ok = value.(float64)
+	case InputTypeInteger:
+		n, isNumber := value.(float64)
+		ok = isNumber && n == math.Trunc(n)
+	case InputTypeBoolean:
+		_, ok = value.(bool)
+	case InputTypeObject:
+		_, ok = value.(map[string]any)
+	case InputTypeArray:
+		_, ok = value.([]any)
+	}
+	if !ok {
+		return fmt.Errorf("expected %s, got %s", decl.inputType(), jsonTypeName(value))
+	}
+	return nil
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,35 @@
+// Command genschema writes the JSON Schema of the current version of workflow
+// manifests to workflowrunner/schema/<version>/workflow_request.schema.json,
+// the JSON Schema of the callback events to
+// workflowrunner/schema/callback_event.schema.json, and the OpenAPI document
+// of the HTTP API to workflowrunner/schema/openapi.json.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+func main() {
+	writeJSON(filepath.Join("schema", workflowrunner.CurrentManifestVersion, "workflow_request.schema.json"),
+		workflowrunner.WorkflowRequestSchema())
+	writeJSON(filepath.Join("schema", "callback_event.schema.json"), workflowrunner.CallbackEventSchema())
+	writeJSON(filepath.Join("schema", "openapi.json"), workflowrunner.OpenAPIDocument())
+}
+
+func writeJSON(path string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
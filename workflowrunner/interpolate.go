@@ -0,0 +1,186 @@
+package workflowrunner
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// interpolationPattern matches the ${env:NAME}, ${metadata:PATH},
+// ${context:PATH} and ${inputs:PATH} references of manifests. A default value
+// can follow the name, as in ${metadata:region:-eu}.
+var interpolationPattern = regexp.MustCompile(`\$\{(env|metadata|context|inputs):([^}]*)\}`)
+
+func hasInterpolation(s string) bool {
+	return interpolationPattern.MatchString(s)
+}
+
+// interpolator replaces the interpolation references found in declarations,
+// using the request metadata, context and inputs as data.
+type interpolator struct {
+	lookupEnv func(name string) (string, bool)
+	metadata  map[string]any
+	context   map[string]any
+	inputs    map[string]any
+}
+
+func (b *Builder) newInterpolator(req WorkflowRequest) *interpolator {
+	return &interpolator{
+		lookupEnv: b.LookupEnv,
+		metadata:  normalizeJSONMap(req.Metadata),
+		context:   normalizeJSONMap(req.Context),
+		inputs:    normalizeJSONMap(req.Inputs),
+	}
+}
+
+func normalizeJSONMap(m map[string]any) map[string]any {
+	normalized, _ := normalizeJSONValue(m).(map[string]any)
+	return normalized
+}
+
+func (in *interpolator) interpolateString(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var interpolateErr error
+	out := interpolationPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if interpolateErr != nil {
+			return ref
+		}
+		match := interpolationPattern.FindStringSubmatch(ref)
+		value, err := in.lookup(match[1], match[2])
+		if err != nil {
+			interpolateErr = err
+			return ref
+		}
+		return value
+	})
+	return out, interpolateErr
+}
+
+func (in *interpolator) lookup(source, ref string) (string, error) {
+	name, fallback, hasFallback := strings.Cut(ref, ":-")
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("empty %s reference", source)
+	}
+	var (
+		value any
+		found bool
+	)
+	switch source {
+	case "env":
+		if in.lookupEnv == nil {
+			return "", fmt.Errorf("environment variable %q is referenced but Builder.LookupEnv is not set", name)
+		}
+		value, found = in.lookupEnv(name)
+	case "metadata":
+		value, found = lookupField(in.metadata, name)
+	case "context":
+		value, found = lookupField(in.context, name)
+	case "inputs":
+		value, found = lookupField(in.inputs, name)
+	}
+	if !found || value == nil {
+		if hasFallback {
+			return fallback, nil
+		}
+		return "", fmt.Errorf("%s %q is not set", source, name)
+	}
+	return outputText(value), nil
+}
+
+func (in *interpolator) interpolateValue(v any) (any, error) {
+	switch value := v.(type) {
+	case string:
+		return in.interpolateString(value)
+	case map[string]any:
+		return in.interpolateMap(value)
+	case []any:
+		out := make([]any, len(value))
+		for i, item := range value {
+			interpolated, err := in.interpolateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = interpolated
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func (in *interpolator) interpolateMap(m map[string]any) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		interpolated, err := in.interpolateValue(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = interpolated
+	}
+	return out, nil
+}
+
+// interpolateAgent returns a copy of the agent declaration where the
+// references of instructions, model name and endpoint, tool configurations
+// and MCP servers are replaced by their values.
+func (in *interpolator) interpolateAgent(decl AgentDeclaration) (AgentDeclaration, error) {
+	var err error
+	if decl.Instructions, err = in.interpolateString(decl.Instructions); err != nil {
+		return decl, fmt.Errorf("instructions: %w", err)
+	}
+	if decl.Model != nil {
+		model := *decl.Model
+		if model.Model, err = in.interpolateString(model.Model); err != nil {
+			return decl, fmt.Errorf("model: %w", err)
+		}
+		if strings.TrimSpace(model.Model) == "" {
+			return decl, errors.New("model: interpolated model name is empty")
+		}
+		if model.BaseURL, err = in.interpolateString(model.BaseURL); err != nil {
+			return decl, fmt.Errorf("model base_url: %w", err)
+		}
+		decl.Model = &model
+	}
+
+	decl.Tools = slices.Clone(decl.Tools)
+	for i, tool := range decl.Tools {
+		if decl.Tools[i].Config, err = in.interpolateMap(tool.Config); err != nil {
+			return decl, fmt.Errorf("tool %q config: %w", tool.Type, err)
+		}
+	}
+
+	decl.MCPServers = slices.Clone(decl.MCPServers)
+	for i, mcp := range decl.MCPServers {
+		if decl.MCPServers[i].Address, err = in.interpolateString(mcp.Address); err != nil {
+			return decl, fmt.Errorf("mcp %q address: %w", mcp.ServerLabel, err)
+		}
+		if decl.MCPServers[i].Additional, err = in.interpolateMap(mcp.Additional); err != nil {
+			return decl, fmt.Errorf("mcp %q additional: %w", mcp.ServerLabel, err)
+		}
+	}
+	return decl, nil
+}
+
+// interpolateWorkflow returns a copy of the request workflow where the
+// interpolation references of the agents are replaced by their values.
+func (b *Builder) interpolateWorkflow(req WorkflowRequest) (WorkflowDeclaration, error) {
+	in := b.newInterpolator(req)
+	workflow := req.Workflow
+	agentDecls := make([]AgentDeclaration, len(workflow.Agents))
+	for i, decl := range workflow.Agents {
+		interpolated, err := in.interpolateAgent(decl)
+		if err != nil {
+			return workflow, fmt.Errorf("agent %q: %w", decl.Name, err)
+		}
+		agentDecls[i] = interpolated
+	}
+	workflow.Agents = agentDecls
+	return workflow, nil
+}
@@ -0,0 +1,222 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/util/transforms"
+)
+
+// Rules of the lint warnings.
+const (
+	// LintUnreachableAgent reports agents which no handoff, agent tool, route
+	// or fan-out leads to from the starting agent, so they never run.
+	LintUnreachableAgent = "unreachable_agent"
+	// LintUnregistered reports tool types, guardrails, output types, tool use
+	// behavior handlers and model providers not registered with the Builder,
+	// which would fail the build of the workflow.
+	LintUnregistered = "unregistered"
+	// LintUnusedAgentTool reports agent tools whose name is already taken by
+	// another tool of the agent, so the model cannot call them.
+	LintUnusedAgentTool = "unused_agent_tool"
+	// LintMissingHandoffDescription reports handoff targets without a
+	// handoff_description, which the model needs to choose among handoffs.
+	LintMissingHandoffDescription = "missing_handoff_description"
+	// LintMissingToolDescription reports agent tools without a description.
+	LintMissingToolDescription = "missing_tool_description"
+)
+
+// LintWarning is a non-fatal issue found in a workflow manifest.
+type LintWarning struct {
+	// Rule is one of the Lint* constants.
+	Rule string `json:"rule"`
+	// JSON path of the value, e.g. "$.workflow.agents[0].handoffs[0]".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.Path, w.Message, w.Rule)
+}
+
+// Lint reports the issues of the workflow of the request which do not
+// prevent it from running, but are likely mistakes. Invalid requests fail
+// as with ValidateWorkflowRequest.
+func (s *RunnerService) Lint(ctx context.Context, req WorkflowRequest) ([]LintWarning, error) {
+	if s.Builder == nil {
+		return nil, errors.New("RunnerService missing Builder")
+	}
+	return s.Builder.Lint(ctx, req)
+}
+
+// Lint is like RunnerService.Lint. References are checked against the
+// registries of the Builder.
+func (b *Builder) Lint(ctx context.Context, req WorkflowRequest) ([]LintWarning, error) {
+	req, err := b.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateWorkflowRequest(req); err != nil {
+		return nil, err
+	}
+
+	var warnings []LintWarning
+	add := func(rule, path, format string, args ...any) {
+		warnings = append(warnings, LintWarning{Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	workflow := req.Workflow
+	index := make(map[string]int, len(workflow.Agents))
+	for i, agent := range workflow.Agents {
+		index[agent.Name] = i
+	}
+
+	reachable := lintReachableAgents(workflow, index)
+	for i, agent := range workflow.Agents {
+		if !reachable[agent.Name] {
+			add(LintUnreachableAgent, fmt.Sprintf("$.workflow.agents[%d]", i),
+				"agent %q is not reachable from the starting agent %q", agent.Name, workflow.StartingAgent)
+		}
+	}
+
+	handoffSources := make(map[string]string)
+	for _, agent := range workflow.Agents {
+		for _, target := range agent.Handoffs {
+			if _, ok := handoffSources[target]; !ok {
+				handoffSources[target] = agent.Name
+			}
+		}
+	}
+	for i, agent := range workflow.Agents {
+		if source, ok := handoffSources[agent.Name]; ok && strings.TrimSpace(agent.HandoffDescription) == "" {
+			add(LintMissingHandoffDescription, fmt.Sprintf("$.workflow.agents[%d].handoff_description", i),
+				"agent %q is a handoff target of %q without handoff_description", agent.Name, source)
+		}
+	}
+
+	if store := req.Session.Store; store != "" {
+		if _, ok := b.SessionFactories[store]; !ok {
+			add(LintUnregistered, "$.session.store", "session store %q is not registered", store)
+		}
+	}
+	for i, agent := range workflow.Agents {
+		path := fmt.Sprintf("$.workflow.agents[%d]", i)
+		warnings = append(warnings, b.lintRegistrations(path, agent)...)
+
+		toolNames := make(map[string]string)
+		for j, tool := range agent.Tools {
+			if tool.Type == "ask_user" {
+				toolNames[cmp.Or(tool.Name, "ask_user")] = fmt.Sprintf("tools[%d]", j)
+			}
+		}
+		for j, ref := range agent.AgentTools {
+			toolPath := fmt.Sprintf("%s.agent_tools[%d]", path, j)
+			name := ref.ToolName
+			if name == "" {
+				target := workflow.Agents[index[ref.AgentName]]
+				name = transforms.TransformStringFunctionStyle(cmp.Or(target.DisplayName, target.Name))
+			}
+			if other, ok := toolNames[name]; ok {
+				add(LintUnusedAgentTool, toolPath,
+					"tool name %q is already used by %s, so agent %q cannot be called", name, other, ref.AgentName)
+			} else {
+				toolNames[name] = fmt.Sprintf("agent_tools[%d]", j)
+			}
+			if strings.TrimSpace(ref.Description) == "" {
+				add(LintMissingToolDescription, toolPath+".description", "agent tool %q has no description", name)
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// lintReachableAgents returns the names of the agents which can run, starting
+// from the starting agent.
+func lintReachableAgents(workflow WorkflowDeclaration, index map[string]int) map[string]bool {
+	reachable := map[string]bool{workflow.StartingAgent: true}
+	queue := []string{workflow.StartingAgent}
+	for len(queue) > 0 {
+		agent := workflow.Agents[index[queue[0]]]
+		queue = queue[1:]
+		next := make([]string, 0, len(agent.Handoffs)+len(agent.AgentTools)+len(agent.Routes))
+		next = append(next, agent.Handoffs...)
+		for _, ref := range agent.AgentTools {
+			next = append(next, ref.AgentName)
+		}
+		for _, route := range agent.Routes {
+			next = append(next, route.Next)
+		}
+		if agent.FanOut != nil {
+			next = append(next, agent.FanOut.Agents...)
+		}
+		for _, name := range next {
+			if !reachable[name] {
+				reachable[name] = true
+				queue = append(queue, name)
+			}
+		}
+	}
+	return reachable
+}
+
+// lintRegistrations reports the references of an agent declaration missing
+// from the registries of the builder.
+func (b *Builder) lintRegistrations(path string, agent AgentDeclaration) []LintWarning {
+	var warnings []LintWarning
+	add := func(path, format string, args ...any) {
+		warnings = append(warnings, LintWarning{Rule: LintUnregistered, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for j, tool := range agent.Tools {
+		if _, ok := b.ToolFactories[tool.Type]; !ok {
+			add(fmt.Sprintf("%s.tools[%d].type", path, j), "tool type %q is not registered", tool.Type)
+		}
+	}
+	if _, ok := b.ToolFactories["hosted_mcp"]; !ok && len(agent.MCPServers) > 0 {
+		add(path+".mcp_servers", "tool type %q is not registered", "hosted_mcp")
+	}
+	for j, gr := range agent.InputGuardrails {
+		if _, ok := inputGuardrailRegistry[strings.ToLower(gr.Name)]; !ok {
+			add(fmt.Sprintf("%s.input_guardrails[%d].name", path, j), "input guardrail %q is not registered", gr.Name)
+		}
+	}
+	for j, gr := range agent.OutputGuardrails {
+		if _, ok := outputGuardrailRegistry[strings.ToLower(gr.Name)]; !ok {
+			add(fmt.Sprintf("%s.output_guardrails[%d].name", path, j), "output guardrail %q is not registered", gr.Name)
+		}
+	}
+	if decl := agent.OutputType; decl != nil {
+		switch {
+		case decl.PresetRef != "":
+			if _, ok := b.OutputTypePresets[decl.PresetRef]; !ok {
+				add(path+".output_type.preset_ref", "output type preset %q is not registered", decl.PresetRef)
+			}
+		case decl.Schema == nil:
+			if _, ok := b.OutputTypeFactories[decl.Name]; !ok {
+				add(path+".output_type.name", "output type %q is not registered", decl.Name)
+			}
+		}
+	}
+	if decl := agent.ToolUseBehavior; decl != nil && decl.Mode == "custom" {
+		if _, ok := b.ToolUseBehaviorHandlers[decl.Handler]; !ok {
+			add(path+".tool_use_behavior.handler", "tool use behavior handler %q is not registered", decl.Handler)
+		}
+	}
+	lintProvider := func(path string, decl *ModelDeclaration) {
+		if decl == nil || usesDefaultProvider(*decl) {
+			return
+		}
+		name := cmp.Or(strings.ToLower(strings.TrimSpace(decl.Provider)), "openai")
+		if _, ok := b.ModelProviderFactories[name]; !ok {
+			add(path+".provider", "model provider %q is not registered", decl.Provider)
+		}
+	}
+	lintProvider(path+".model", agent.Model)
+	for j, variant := range agent.Variants {
+		lintProvider(fmt.Sprintf("%s.variants[%d].model", path, j), variant.Model)
+	}
+	return warnings
+}
@@ -0,0 +1,54 @@
+package loadtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+// LoadCorpus reads the requests replayed by Run from the given files:
+// .jsonl files hold a request per line, and other files a single JSON
+// request, as the manifests of wfrun.
+func LoadCorpus(paths ...string) ([]workflowrunner.WorkflowRequest, error) {
+	var corpus []workflowrunner.WorkflowRequest
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".jsonl") {
+			var req workflowrunner.WorkflowRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				return nil, fmt.Errorf("decode %s: %w", path, err)
+			}
+			corpus = append(corpus, req)
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 8<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var req workflowrunner.WorkflowRequest
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+				return nil, fmt.Errorf("decode %s:%d: %w", path, line, err)
+			}
+			corpus = append(corpus, req)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	if len(corpus) == 0 {
+		return nil, errors.New("corpus is empty")
+	}
+	return corpus, nil
+}
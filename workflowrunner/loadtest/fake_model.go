@@ -0,0 +1,135 @@
+package loadtest
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared/constant"
+)
+
+// FakeProvider is the name of the provider registered by UseFakeModel.
+const FakeProvider = "loadtest_fake"
+
+// DefaultFakeOutput is the reply of a FakeModel without Output.
+const DefaultFakeOutput = "ok"
+
+// FakeModel is a model replying to every turn with the same text message
+// after Latency, to measure the overhead of the runner without calling a
+// provider. Unlike agentstesting.FakeModel, it is safe for concurrent runs.
+// Agents declaring an output type need a JSON Output matching it.
+type FakeModel struct {
+	// Latency of each response, simulating the time spent by the provider.
+	Latency time.Duration
+	// Output is the text of the replies, DefaultFakeOutput when empty.
+	Output string
+}
+
+func (m *FakeModel) GetResponse(ctx context.Context, _ agents.ModelResponseParams) (*agents.ModelResponse, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+	tokens := uint64(m.outputTokens())
+	return &agents.ModelResponse{
+		Output: []agents.TResponseOutputItem{m.message()},
+		Usage:  &usage.Usage{Requests: 1, OutputTokens: tokens, TotalTokens: tokens},
+	}, nil
+}
+
+func (m *FakeModel) StreamResponse(ctx context.Context, _ agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+	// The runner counts the requests of the responses with a usage.
+	tokens := m.outputTokens()
+	return yield(ctx, agents.TResponseStreamEvent{ // responses.ResponseCompletedEvent
+		Response: responses.Response{
+			ID:     "loadtest",
+			Object: "response",
+			Output: []responses.ResponseOutputItemUnion{m.message()},
+			Usage:  responses.ResponseUsage{OutputTokens: tokens, TotalTokens: tokens},
+		},
+		Type: "response.completed",
+	})
+}
+
+func (m *FakeModel) wait(ctx context.Context) error {
+	if m.Latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(m.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+func (m *FakeModel) output() string {
+	if m.Output == "" {
+		return DefaultFakeOutput
+	}
+	return m.Output
+}
+
+// outputTokens estimates the tokens of the output, at about four bytes per
+// token.
+func (m *FakeModel) outputTokens() int64 {
+	return int64(len(m.output())+3) / 4
+}
+
+func (m *FakeModel) message() responses.ResponseOutputItemUnion {
+	return responses.ResponseOutputItemUnion{ // responses.ResponseOutputMessage
+		ID:   "loadtest",
+		Type: "message",
+		Role: constant.ValueOf[constant.Assistant](),
+		Content: []responses.ResponseOutputMessageContentUnion{{ // responses.ResponseOutputText
+			Text: m.output(),
+			Type: "output_text",
+		}},
+		Status: string(responses.ResponseOutputMessageStatusCompleted),
+	}
+}
+
+type fakeModelProvider struct {
+	model *FakeModel
+}
+
+func (p fakeModelProvider) GetModel(string) (agents.Model, error) {
+	return p.model, nil
+}
+
+// UseFakeModel registers the FakeProvider serving the model to the builder,
+// for the requests rewritten with WithFakeModel.
+func UseFakeModel(builder *workflowrunner.Builder, model *FakeModel) {
+	if builder.ModelProviderFactories == nil {
+		builder.ModelProviderFactories = make(map[string]workflowrunner.ModelProviderFactory)
+	}
+	builder.ModelProviderFactories[FakeProvider] = func(context.Context, workflowrunner.ModelDeclaration) (agents.ModelProvider, error) {
+		return fakeModelProvider{model: model}, nil
+	}
+}
+
+// WithFakeModel returns a copy of the request whose agents use the model
+// registered with UseFakeModel, keeping their declared model names. Requests
+// referencing a registered workflow with WorkflowRef are returned unchanged.
+func WithFakeModel(req workflowrunner.WorkflowRequest) workflowrunner.WorkflowRequest {
+	req.Workflow.Agents = slices.Clone(req.Workflow.Agents)
+	for i := range req.Workflow.Agents {
+		agent := &req.Workflow.Agents[i]
+		name := "fake"
+		if agent.Model != nil && agent.Model.Model != "" {
+			name = agent.Model.Model
+		}
+		agent.Model = &workflowrunner.ModelDeclaration{Provider: FakeProvider, Model: name}
+	}
+	return req
+}
+
+var _ agents.Model = (*FakeModel)(nil)
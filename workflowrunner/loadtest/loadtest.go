@@ -0,0 +1,301 @@
+// Package loadtest replays a corpus of workflow requests against a
+// workflowrunner.RunnerService, reporting the throughput, the latencies of
+// the runs and of their turns and the memory usage, to guide capacity
+// planning.
+//
+//	corpus, err := loadtest.LoadCorpus("requests.jsonl")
+//	report, err := loadtest.Run(ctx, service, corpus, loadtest.Config{Runs: 1000, Concurrency: 32})
+//
+// Runs call the providers declared by the requests; to measure the runner
+// alone, register a FakeModel with UseFakeModel and rewrite the requests with
+// WithFakeModel.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+const (
+	// DefaultConcurrency is the number of runs in flight when the Config
+	// does not set it.
+	DefaultConcurrency = 4
+	// DefaultMemorySampleInterval is the interval of the samples of the heap
+	// when the Config does not set it.
+	DefaultMemorySampleInterval = 100 * time.Millisecond
+)
+
+// Config of a load test.
+type Config struct {
+	// Runs is the number of runs, cycling through the corpus; one per
+	// request when zero.
+	Runs int
+	// Concurrency bounds the runs in flight, DefaultConcurrency when zero.
+	Concurrency int
+	// Publisher receives the events of the runs, which are discarded when
+	// nil. The callbacks declared by the requests are never called.
+	Publisher workflowrunner.CallbackPublisher
+	// MemorySampleInterval is the interval of the samples of the heap
+	// measuring its peak, DefaultMemorySampleInterval when zero.
+	MemorySampleInterval time.Duration
+}
+
+// Report of a load test. Latencies are in milliseconds.
+type Report struct {
+	Runs        int `json:"runs"`
+	Concurrency int `json:"concurrency"`
+	Completed   int `json:"completed"`
+	// Suspended counts the runs waiting for approvals or inputs.
+	Suspended int `json:"suspended"`
+	Failed    int `json:"failed"`
+	// Turns is the number of model requests of the runs.
+	Turns          uint64  `json:"turns"`
+	DurationMs     float64 `json:"duration_ms"`
+	RunsPerSecond  float64 `json:"runs_per_second"`
+	TurnsPerSecond float64 `json:"turns_per_second"`
+	// RunLatency is the latency of the runs, from their submission to their
+	// outcome.
+	RunLatency LatencyStats `json:"run_latency"`
+	// TurnLatency is the latency of the turns, measured as the latency of
+	// each run divided by its turns.
+	TurnLatency LatencyStats `json:"turn_latency"`
+	Memory      MemoryStats  `json:"memory"`
+	// Errors counts the failed runs by error message.
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// LatencyStats summarizes latency samples, in milliseconds.
+type LatencyStats struct {
+	Samples int     `json:"samples"`
+	Mean    float64 `json:"mean_ms"`
+	P50     float64 `json:"p50_ms"`
+	P90     float64 `json:"p90_ms"`
+	P99     float64 `json:"p99_ms"`
+	Max     float64 `json:"max_ms"`
+}
+
+// MemoryStats reports the memory used by the process during the load test,
+// including anything else it runs meanwhile.
+type MemoryStats struct {
+	// PeakHeapBytes is the largest heap sampled.
+	PeakHeapBytes uint64 `json:"peak_heap_bytes"`
+	// TotalAllocBytes and Mallocs are the bytes and objects allocated.
+	TotalAllocBytes uint64  `json:"total_alloc_bytes"`
+	Mallocs         uint64  `json:"mallocs"`
+	BytesPerRun     float64 `json:"bytes_per_run"`
+	AllocsPerRun    float64 `json:"allocs_per_run"`
+	NumGC           uint32  `json:"num_gc"`
+}
+
+// Run replays the corpus against the service. Each run uses the session of
+// its request suffixed with ":load-<n>", so runs never share sessions.
+// Failed runs are reported, not returned as errors; when ctx is canceled the
+// runs not started are left out of the report, returned with the cause.
+func Run(ctx context.Context, service *workflowrunner.RunnerService, corpus []workflowrunner.WorkflowRequest, cfg Config) (*Report, error) {
+	if len(corpus) == 0 {
+		return nil, errors.New("corpus is empty")
+	}
+	for i, req := range corpus {
+		if req.Batch != nil {
+			return nil, fmt.Errorf("corpus[%d]: %w", i, workflowrunner.ErrBatchRequest)
+		}
+	}
+	runs := cfg.Runs
+	if runs <= 0 {
+		runs = len(corpus)
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	publisher := cfg.Publisher
+	if publisher == nil {
+		publisher = discardPublisher{}
+	}
+	interval := cfg.MemorySampleInterval
+	if interval <= 0 {
+		interval = DefaultMemorySampleInterval
+	}
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	sampler := startHeapSampler(interval)
+
+	outcomes := make([]runOutcome, runs)
+	start := time.Now()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	started := 0
+	for ; started < runs; started++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		req := corpus[started%len(corpus)]
+		req.Session.SessionID += ":load-" + strconv.Itoa(started)
+		outcome := &outcomes[started]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			*outcome = execute(ctx, service, req, publisher)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	peak := max(sampler.stop(), after.HeapAlloc)
+
+	report := newReport(outcomes[:started], elapsed)
+	report.Concurrency = concurrency
+	report.Memory = MemoryStats{
+		PeakHeapBytes:   peak,
+		TotalAllocBytes: after.TotalAlloc - before.TotalAlloc,
+		Mallocs:         after.Mallocs - before.Mallocs,
+		NumGC:           after.NumGC - before.NumGC,
+	}
+	if started > 0 {
+		report.Memory.BytesPerRun = float64(report.Memory.TotalAllocBytes) / float64(started)
+		report.Memory.AllocsPerRun = float64(report.Memory.Mallocs) / float64(started)
+	}
+	if started < runs {
+		return report, context.Cause(ctx)
+	}
+	return report, nil
+}
+
+type runOutcome struct {
+	status  workflowrunner.ExecutionStatus
+	err     string
+	turns   uint64
+	latency time.Duration
+}
+
+func execute(ctx context.Context, service *workflowrunner.RunnerService, req workflowrunner.WorkflowRequest, publisher workflowrunner.CallbackPublisher) runOutcome {
+	start := time.Now()
+	task, err := service.ExecuteWithPublisher(ctx, req, publisher)
+	if err != nil {
+		return runOutcome{status: workflowrunner.ExecutionStatusFailed, err: err.Error(), latency: time.Since(start)}
+	}
+	summary := task.Await().Value
+	outcome := runOutcome{status: summary.Status, latency: time.Since(start)}
+	if summary.Error != nil {
+		outcome.err = summary.Error.Error()
+	}
+	if summary.Usage != nil {
+		outcome.turns = summary.Usage.Requests
+	}
+	return outcome
+}
+
+func newReport(outcomes []runOutcome, elapsed time.Duration) *Report {
+	report := &Report{Runs: len(outcomes), DurationMs: milliseconds(elapsed)}
+	runLatencies := make([]time.Duration, 0, len(outcomes))
+	var turnLatencies []time.Duration
+	for _, outcome := range outcomes {
+		switch outcome.status {
+		case workflowrunner.ExecutionStatusCompleted:
+			report.Completed++
+		case workflowrunner.ExecutionStatusWaitingApproval, workflowrunner.ExecutionStatusWaitingInput:
+			report.Suspended++
+		default:
+			report.Failed++
+			if report.Errors == nil {
+				report.Errors = make(map[string]int)
+			}
+			report.Errors[outcome.err]++
+		}
+		report.Turns += outcome.turns
+		runLatencies = append(runLatencies, outcome.latency)
+		if outcome.turns > 0 {
+			turnLatencies = append(turnLatencies, outcome.latency/time.Duration(outcome.turns))
+		}
+	}
+	if elapsed > 0 {
+		report.RunsPerSecond = float64(report.Runs) / elapsed.Seconds()
+		report.TurnsPerSecond = float64(report.Turns) / elapsed.Seconds()
+	}
+	report.RunLatency = newLatencyStats(runLatencies)
+	report.TurnLatency = newLatencyStats(turnLatencies)
+	return report
+}
+
+func newLatencyStats(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+	slices.Sort(samples)
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+	return LatencyStats{
+		Samples: len(samples),
+		Mean:    milliseconds(total / time.Duration(len(samples))),
+		P50:     milliseconds(percentile(samples, 50)),
+		P90:     milliseconds(percentile(samples, 90)),
+		P99:     milliseconds(percentile(samples, 99)),
+		Max:     milliseconds(samples[len(samples)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// heapSampler records the peak of the heap, sampled periodically.
+type heapSampler struct {
+	done chan struct{}
+	peak chan uint64
+}
+
+func startHeapSampler(interval time.Duration) *heapSampler {
+	s := &heapSampler{done: make(chan struct{}), peak: make(chan uint64)}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var peak uint64
+		var stats runtime.MemStats
+		for {
+			select {
+			case <-ticker.C:
+				runtime.ReadMemStats(&stats)
+				peak = max(peak, stats.HeapAlloc)
+			case <-s.done:
+				s.peak <- peak
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// stop stops the sampler, returning the peak of the heap.
+func (s *heapSampler) stop() uint64 {
+	close(s.done)
+	return <-s.peak
+}
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(context.Context, workflowrunner.CallbackEvent) error { return nil }
@@ -0,0 +1,103 @@
+package workflowrunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Manifest versions. Manifests without a version are v1 manifests.
+//
+// v2 changes from v1:
+//   - agents[].handoff is renamed to agents[].handoffs;
+//   - agents[].mcp is renamed to agents[].mcp_servers;
+//   - callback must be an object: a callback URL string becomes {"target": URL}.
+const (
+	ManifestVersionV1 = "v1"
+	ManifestVersionV2 = "v2"
+
+	// CurrentManifestVersion is the version of the Go types and of the
+	// schema returned by WorkflowRequestSchema.
+	CurrentManifestVersion = ManifestVersionV2
+)
+
+// UnmarshalJSON decodes a manifest of any supported version, migrating it to
+// the current version.
+func (r *WorkflowRequest) UnmarshalJSON(data []byte) error {
+	migrated, err := MigrateManifestBytes(data)
+	if err != nil {
+		return err
+	}
+	type alias WorkflowRequest
+	var decoded alias
+	if err := json.Unmarshal(migrated, &decoded); err != nil {
+		return err
+	}
+	*r = WorkflowRequest(decoded)
+	return nil
+}
+
+// Migrate returns the request upgraded to CurrentManifestVersion.
+// Requests decoded from JSON are migrated automatically; Migrate is useful for
+// requests built in Go, and to reject unsupported versions early.
+func Migrate(req WorkflowRequest) (WorkflowRequest, error) {
+	switch req.Version {
+	case "", ManifestVersionV1, ManifestVersionV2:
+		// v1 and v2 only differ in their JSON encoding.
+		req.Version = CurrentManifestVersion
+		return req, nil
+	default:
+		return req, fmt.Errorf("unsupported manifest version %q", req.Version)
+	}
+}
+
+// MigrateManifestBytes upgrades a JSON-encoded manifest to
+// CurrentManifestVersion. Manifests already at the current version are
+// returned unchanged.
+func MigrateManifestBytes(data []byte) ([]byte, error) {
+	var manifest map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&manifest); err != nil {
+		return nil, err
+	}
+	version, ok := manifest["version"].(string)
+	if manifest["version"] != nil && !ok {
+		return nil, fmt.Errorf("unsupported manifest version %v", manifest["version"])
+	}
+	switch version {
+	case ManifestVersionV2:
+		return data, nil
+	case "", ManifestVersionV1:
+		migrateManifestV1ToV2(manifest)
+	default:
+		return nil, fmt.Errorf("unsupported manifest version %q", manifest["version"])
+	}
+	return json.Marshal(manifest)
+}
+
+func migrateManifestV1ToV2(manifest map[string]any) {
+	manifest["version"] = ManifestVersionV2
+	if target, ok := manifest["callback"].(string); ok {
+		manifest["callback"] = map[string]any{"target": target}
+	}
+	workflow, _ := manifest["workflow"].(map[string]any)
+	agentList, _ := workflow["agents"].([]any)
+	for _, a := range agentList {
+		agent, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		renameKey(agent, "handoff", "handoffs")
+		renameKey(agent, "mcp", "mcp_servers")
+	}
+}
+
+func renameKey(m map[string]any, from, to string) {
+	if v, ok := m[from]; ok {
+		if _, exists := m[to]; !exists {
+			m[to] = v
+		}
+		delete(m, from)
+	}
+}
@@ -0,0 +1,85 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateManifestBytes(t *testing.T) {
+	v1 := `{
+		"query": "hello",
+		"callback": "https://example.com/hook",
+		"workflow": {"name": "wf", "starting_agent": "a", "agents": [
+			{"name": "a", "handoff": ["b"], "mcp": [{"address": "https://mcp.example.com"}]},
+			{"name": "b", "handoff": ["a"], "handoffs": ["c"]}
+		]}
+	}`
+	v2 := `{
+		"version": "v2",
+		"query": "hello",
+		"callback": {"target": "https://example.com/hook"},
+		"workflow": {"name": "wf", "starting_agent": "a", "agents": [
+			{"name": "a", "handoffs": ["b"], "mcp_servers": [{"address": "https://mcp.example.com"}]},
+			{"name": "b", "handoffs": ["c"]}
+		]}
+	}`
+
+	migrated, err := MigrateManifestBytes([]byte(v1))
+	require.NoError(t, err)
+	assert.JSONEq(t, v2, string(migrated))
+
+	// Migrating again leaves the manifest unchanged.
+	again, err := MigrateManifestBytes(migrated)
+	require.NoError(t, err)
+	assert.Equal(t, string(migrated), string(again))
+	again, err = MigrateManifestBytes([]byte(`{"version": "v1", "callback": "https://example.com/hook"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": "v2", "callback": {"target": "https://example.com/hook"}}`, string(again))
+
+	_, err = MigrateManifestBytes([]byte(`{"version": "v3"}`))
+	assert.ErrorContains(t, err, `unsupported manifest version "v3"`)
+	_, err = MigrateManifestBytes([]byte(`{"version": 2}`))
+	assert.ErrorContains(t, err, "unsupported manifest version")
+}
+
+func TestMigrate(t *testing.T) {
+	for _, version := range []string{"", ManifestVersionV1, ManifestVersionV2} {
+		req := testRequest("s1")
+		req.Version = version
+		migrated, err := Migrate(req)
+		require.NoError(t, err)
+		assert.Equal(t, CurrentManifestVersion, migrated.Version)
+
+		// Migrating again leaves the request unchanged.
+		again, err := Migrate(migrated)
+		require.NoError(t, err)
+		assert.Equal(t, migrated, again)
+	}
+
+	req := testRequest("s1")
+	req.Version = "v3"
+	_, err := Migrate(req)
+	assert.ErrorContains(t, err, `unsupported manifest version "v3"`)
+}
+
+func TestWorkflowRequestUnmarshalMigrates(t *testing.T) {
+	var req WorkflowRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"query": "hello",
+		"callback": "https://example.com/hook",
+		"workflow": {"name": "wf", "starting_agent": "a", "agents": [{"name": "a", "handoff": ["a"]}]}
+	}`), &req))
+	assert.Equal(t, CurrentManifestVersion, req.Version)
+	assert.Equal(t, "https://example.com/hook", req.Callback.Target)
+	assert.Equal(t, []string{"a"}, req.Workflow.Agents[0].Handoffs)
+
+	// Encoded requests decode to the same request.
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	var again WorkflowRequest
+	require.NoError(t, json.Unmarshal(data, &again))
+	assert.Equal(t, req, again)
+}
@@ -0,0 +1,108 @@
+// Package natscallback publishes workflowrunner callback events to NATS
+// JetStream, for callbacks declared with the "nats" mode:
+//
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	js, _ := jetstream.New(nc)
+//	service := workflowrunner.NewRunnerService(nil)
+//	service.CallbackFactory = natscallback.CallbackFactory(js, service.CallbackFactory)
+//
+// The callback target is a text/template rendering the subject of each
+// event, e.g. "workflows.${metadata:tenant}.{{.Type}}".
+package natscallback
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+)
+
+// SubjectData is the data of subject templates.
+type SubjectData struct {
+	// Type of the event, e.g. "run.completed".
+	Type     string
+	Metadata map[string]any
+}
+
+// Publisher publishes callback events to JetStream. Each publication waits
+// for the acknowledgement of the stream, and carries a message ID derived
+// from the event, so that retried events are deduplicated by JetStream:
+// delivery is at least once.
+type Publisher struct {
+	js      jetstream.JetStream
+	subject *template.Template
+	// SigningSecret, when set, signs the messages in the
+	// workflowrunner.CallbackSignatureHeader header.
+	SigningSecret []byte
+}
+
+// NewPublisher returns a Publisher rendering the subject of each event with
+// the given template, see SubjectData.
+func NewPublisher(js jetstream.JetStream, subjectTemplate string) (*Publisher, error) {
+	if js == nil {
+		return nil, errors.New("jetstream is required")
+	}
+	tmpl, err := template.New("subject").Option("missingkey=error").Parse(subjectTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse subject template: %w", err)
+	}
+	return &Publisher{js: js, subject: tmpl}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, event workflowrunner.CallbackEvent) error {
+	var sb strings.Builder
+	if err := p.subject.Execute(&sb, SubjectData{Type: event.Type, Metadata: event.Metadata}); err != nil {
+		return fmt.Errorf("render subject: %w", err)
+	}
+	subject := sb.String()
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid subject %q", subject)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	msg := nats.NewMsg(subject)
+	msg.Data = body
+	msg.Header.Set("Content-Type", "application/json")
+	if len(p.SigningSecret) > 0 {
+		msg.Header.Set(workflowrunner.CallbackSignatureHeader, workflowrunner.SignCallbackPayload(p.SigningSecret, body, time.Now()))
+	}
+	sum := sha256.Sum256(body)
+	if _, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(hex.EncodeToString(sum[:16]))); err != nil {
+		return fmt.Errorf("publish to %q: %w", subject, err)
+	}
+	return nil
+}
+
+// CallbackFactory returns a RunnerService callback factory creating a
+// Publisher for the callbacks in the "nats" mode, and delegating the other
+// modes to next.
+func CallbackFactory(js jetstream.JetStream, next workflowrunner.CallbackPublisherFactory) workflowrunner.CallbackPublisherFactory {
+	return func(ctx context.Context, decl workflowrunner.CallbackDeclaration) (workflowrunner.CallbackPublisher, error) {
+		if !strings.EqualFold(decl.Mode, workflowrunner.CallbackModeNATS) {
+			if next == nil {
+				return nil, fmt.Errorf("unsupported callback mode %q", decl.Mode)
+			}
+			return next(ctx, decl)
+		}
+		publisher, err := NewPublisher(js, decl.Target)
+		if err != nil {
+			return nil, err
+		}
+		if decl.SigningSecret != "" {
+			publisher.SigningSecret = []byte(decl.SigningSecret)
+		}
+		return publisher, nil
+	}
+}
@@ -0,0 +1,148 @@
+package natscallback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJetStream records the published messages, failing with err if set.
+// The other methods of jetstream.JetStream are not implemented.
+type fakeJetStream struct {
+	jetstream.JetStream
+	err  error
+	msgs []*nats.Msg
+	opts [][]jetstream.PublishOpt
+}
+
+func (js *fakeJetStream) PublishMsg(_ context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	if js.err != nil {
+		return nil, js.err
+	}
+	js.msgs = append(js.msgs, msg)
+	js.opts = append(js.opts, opts)
+	return &jetstream.PubAck{Stream: "WORKFLOWS", Sequence: uint64(len(js.msgs))}, nil
+}
+
+func testEvent() workflowrunner.CallbackEvent {
+	return workflowrunner.CallbackEvent{
+		Type:      workflowrunner.CallbackEventRunCompleted,
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Payload:   map[string]any{"final_output": "hi"},
+		Metadata:  map[string]any{"tenant": "acme"},
+	}
+}
+
+func TestPublisherPublish(t *testing.T) {
+	js := &fakeJetStream{}
+	publisher, err := NewPublisher(js, "workflows.{{.Metadata.tenant}}.{{.Type}}")
+	require.NoError(t, err)
+
+	event := testEvent()
+	require.NoError(t, publisher.Publish(t.Context(), event))
+	require.Len(t, js.msgs, 1)
+	msg := js.msgs[0]
+	assert.Equal(t, "workflows.acme.run.completed", msg.Subject)
+	assert.Equal(t, "application/json", msg.Header.Get("Content-Type"))
+	assert.Empty(t, msg.Header.Get(workflowrunner.CallbackSignatureHeader))
+	var decoded workflowrunner.CallbackEvent
+	require.NoError(t, json.Unmarshal(msg.Data, &decoded))
+	assert.Equal(t, event, decoded)
+	// Each publication carries its message ID.
+	assert.Len(t, js.opts[0], 1)
+
+	// Retried events have the same body, hence the same message ID.
+	require.NoError(t, publisher.Publish(t.Context(), event))
+	require.Len(t, js.msgs, 2)
+	assert.Equal(t, msg.Data, js.msgs[1].Data)
+}
+
+func TestPublisherSignsMessages(t *testing.T) {
+	js := &fakeJetStream{}
+	publisher, err := NewPublisher(js, "workflows.{{.Type}}")
+	require.NoError(t, err)
+	publisher.SigningSecret = []byte("secret")
+
+	require.NoError(t, publisher.Publish(t.Context(), testEvent()))
+	require.Len(t, js.msgs, 1)
+	msg := js.msgs[0]
+	signature := msg.Header.Get(workflowrunner.CallbackSignatureHeader)
+	require.NotEmpty(t, signature)
+	assert.NoError(t, workflowrunner.VerifyCallbackSignature([]byte("secret"), msg.Data, signature, 0))
+	assert.Error(t, workflowrunner.VerifyCallbackSignature([]byte("other"), msg.Data, signature, 0))
+}
+
+func TestPublisherErrors(t *testing.T) {
+	_, err := NewPublisher(nil, "workflows")
+	assert.EqualError(t, err, "jetstream is required")
+	_, err = NewPublisher(&fakeJetStream{}, "workflows.{{.Type")
+	assert.ErrorContains(t, err, "parse subject template")
+
+	tests := []struct {
+		name    string
+		subject string
+		err     string
+	}{
+		{"missing metadata", "workflows.{{.Metadata.region}}", "render subject"},
+		{"empty subject", "{{if false}}x{{end}}", `invalid subject ""`},
+		{"whitespace in subject", "workflows.{{.Metadata.tenant}} x", `invalid subject "workflows.acme x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			js := &fakeJetStream{}
+			publisher, err := NewPublisher(js, tt.subject)
+			require.NoError(t, err)
+			assert.ErrorContains(t, publisher.Publish(t.Context(), testEvent()), tt.err)
+			assert.Empty(t, js.msgs)
+		})
+	}
+
+	unavailable := errors.New("no responders")
+	publisher, err := NewPublisher(&fakeJetStream{err: unavailable}, "workflows.{{.Type}}")
+	require.NoError(t, err)
+	err = publisher.Publish(t.Context(), testEvent())
+	assert.ErrorIs(t, err, unavailable)
+	assert.ErrorContains(t, err, `publish to "workflows.run.completed"`)
+}
+
+type namedPublisher string
+
+func (namedPublisher) Publish(context.Context, workflowrunner.CallbackEvent) error { return nil }
+
+func TestCallbackFactory(t *testing.T) {
+	js := &fakeJetStream{}
+	next := func(_ context.Context, decl workflowrunner.CallbackDeclaration) (workflowrunner.CallbackPublisher, error) {
+		return namedPublisher(decl.Mode), nil
+	}
+	factory := CallbackFactory(js, next)
+
+	publisher, err := factory(t.Context(), workflowrunner.CallbackDeclaration{
+		Target:        "workflows.{{.Type}}",
+		Mode:          "NATS",
+		SigningSecret: "secret",
+	})
+	require.NoError(t, err)
+	require.IsType(t, &Publisher{}, publisher)
+	assert.Equal(t, []byte("secret"), publisher.(*Publisher).SigningSecret)
+	require.NoError(t, publisher.Publish(t.Context(), testEvent()))
+	assert.Len(t, js.msgs, 1)
+
+	_, err = factory(t.Context(), workflowrunner.CallbackDeclaration{Target: "workflows.{{.Type", Mode: "nats"})
+	assert.ErrorContains(t, err, "parse subject template")
+
+	// The other modes are delegated to next.
+	publisher, err = factory(t.Context(), workflowrunner.CallbackDeclaration{Target: "https://example.com/hook", Mode: "http"})
+	require.NoError(t, err)
+	assert.Equal(t, namedPublisher("http"), publisher)
+
+	_, err = CallbackFactory(js, nil)(t.Context(), workflowrunner.CallbackDeclaration{Mode: "http"})
+	assert.EqualError(t, err, `unsupported callback mode "http"`)
+}
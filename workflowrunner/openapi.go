@@ -0,0 +1,207 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// OpenAPIDocument returns the OpenAPI 3.1 document of the HTTP API served by
+// RunHandler, with the schemas generated from the Go types. It is written to
+// schema/openapi.json by go generate, e.g. to generate clients in other
+// languages.
+func OpenAPIDocument() map[string]any {
+	ref := func(name string) map[string]any {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	jsonContent := func(schema map[string]any) map[string]any {
+		return map[string]any{"application/json": map[string]any{"schema": schema}}
+	}
+	response := func(description string, schema map[string]any) map[string]any {
+		return map[string]any{"description": description, "content": jsonContent(schema)}
+	}
+	errorResponse := func(description string) map[string]any {
+		return response(description, ref("APIError"))
+	}
+	sessionParam := map[string]any{
+		"name": "session_id", "in": "path", "required": true,
+		"schema": map[string]any{"type": "string"},
+	}
+	requestParam := map[string]any{
+		"name": "request_id", "in": "path", "required": true,
+		"schema": map[string]any{"type": "string"},
+	}
+	runBody := map[string]any{"required": true, "content": jsonContent(ref("WorkflowRequest"))}
+	eventStream := map[string]any{
+		"description": "Server-Sent Events named after the event types, whose data is a CallbackEvent.",
+		"content": map[string]any{"text/event-stream": map[string]any{
+			"schema": map[string]any{"type": "string"},
+		}},
+	}
+	runErrors := map[string]any{
+		"400": errorResponse("The body is not a workflow request."),
+		"403": errorResponse("The credentials lack capabilities required by the workflow."),
+		"422": errorResponse("The workflow request is invalid."),
+		"429": errorResponse("The run was rejected by admission control."),
+	}
+	stateResponses := map[string]any{
+		"200": response("The execution state of the session.", ref("WorkflowExecutionState")),
+		"404": errorResponse("The session or request was not found."),
+		"409": errorResponse("The execution state was modified concurrently."),
+	}
+	reasonBody := map[string]any{"content": jsonContent(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"reason": map[string]any{"type": "string"}},
+	})}
+
+	paths := map[string]any{
+		"/runs": map[string]any{"post": map[string]any{
+			"operationId": "run",
+			"summary":     "Run a workflow, waiting for its outcome unless async is set.",
+			"parameters": []any{map[string]any{
+				"name": "async", "in": "query",
+				"schema": map[string]any{"type": "boolean"},
+			}},
+			"requestBody": runBody,
+			"responses": mergeResponses(runErrors, map[string]any{
+				"200": response("The outcome of the run.", ref("RunResponse")),
+				"202": response("The run was started.", ref("RunAccepted")),
+			}),
+		}},
+		"/runs/stream": map[string]any{"post": map[string]any{
+			"operationId": "streamRun",
+			"summary":     "Run a workflow, streaming its events.",
+			"requestBody": runBody,
+			"responses": mergeResponses(runErrors, map[string]any{
+				"200": eventStream,
+			}),
+		}},
+		"/runs/resume": map[string]any{"post": map[string]any{
+			"operationId": "resumeRun",
+			"summary":     "Resume a run suspended on approval or input requests.",
+			"requestBody": runBody,
+			"responses": mergeResponses(runErrors, map[string]any{
+				"200": response("The outcome of the run.", ref("RunResponse")),
+				"404": errorResponse("The session has no execution state."),
+				"409": errorResponse("The run is not suspended, or requests are pending."),
+			}),
+		}},
+		"/sessions/{session_id}/messages": map[string]any{"post": map[string]any{
+			"operationId": "continueConversation",
+			"summary":     "Continue the conversation of a session with a new message, streaming the events of the run.",
+			"parameters":  []any{sessionParam},
+			"requestBody": map[string]any{"required": true, "content": jsonContent(ref("SessionMessage"))},
+			"responses": mergeResponses(runErrors, map[string]any{
+				"200": eventStream,
+				"400": errorResponse("The body is not a message."),
+				"404": errorResponse("The session has no execution state."),
+				"409": errorResponse("The run of the session is running or suspended."),
+			}),
+		}},
+		"/sessions/{session_id}/state": map[string]any{"get": map[string]any{
+			"operationId": "getState",
+			"parameters":  []any{sessionParam},
+			"responses":   stateResponses,
+		}},
+		"/sessions/{session_id}/approvals": map[string]any{"get": map[string]any{
+			"operationId": "listApprovals",
+			"parameters":  []any{sessionParam},
+			"responses":   stateResponses,
+		}},
+		"/sessions/{session_id}/approvals/{request_id}/approve": map[string]any{"post": map[string]any{
+			"operationId": "approve",
+			"parameters":  []any{sessionParam, requestParam},
+			"requestBody": reasonBody,
+			"responses":   stateResponses,
+		}},
+		"/sessions/{session_id}/approvals/{request_id}/deny": map[string]any{"post": map[string]any{
+			"operationId": "deny",
+			"parameters":  []any{sessionParam, requestParam},
+			"requestBody": reasonBody,
+			"responses":   stateResponses,
+		}},
+		"/sessions/{session_id}/inputs": map[string]any{"get": map[string]any{
+			"operationId": "listInputs",
+			"parameters":  []any{sessionParam},
+			"responses":   stateResponses,
+		}},
+		"/sessions/{session_id}/inputs/{request_id}": map[string]any{"post": map[string]any{
+			"operationId": "provideInput",
+			"parameters":  []any{sessionParam, requestParam},
+			"requestBody": map[string]any{"required": true, "content": jsonContent(map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"answer": map[string]any{}},
+				"required":   []any{"answer"},
+			})},
+			"responses": stateResponses,
+		}},
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "workflowrunner",
+			"version": CurrentManifestVersion,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": openAPISchemas(&WorkflowRequest{}, &RunResponse{}, &RunAccepted{},
+				&WorkflowExecutionState{}, CallbackEventSchema(), &SessionMessage{}, &APIError{}),
+		},
+	}
+}
+
+// openAPISchemas reflects the JSON Schemas of the values, unless already
+// schemas, whose definitions are referenced as OpenAPI components.
+func openAPISchemas(values ...any) map[string]any {
+	schemas := make(map[string]any)
+	for _, v := range values {
+		schema, ok := v.(*jsonschema.Schema)
+		if !ok {
+			schema = newSchemaReflector().Reflect(v)
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			panic(err)
+		}
+		var defs struct {
+			Defs map[string]json.RawMessage `json:"$defs"`
+		}
+		if err := json.Unmarshal(data, &defs); err != nil {
+			panic(err)
+		}
+		refs := make([]string, 0, 2*len(defs.Defs))
+		for name := range defs.Defs {
+			refs = append(refs, `"#/$defs/`+name+`"`, `"#/components/schemas/`+openAPIComponentName(name)+`"`)
+		}
+		for name, def := range defs.Defs {
+			var schema any
+			if err := json.Unmarshal([]byte(strings.NewReplacer(refs...).Replace(string(def))), &schema); err != nil {
+				panic(err)
+			}
+			schemas[openAPIComponentName(name)] = schema
+		}
+	}
+	return schemas
+}
+
+// openAPIComponentName replaces the characters not allowed in the names of
+// OpenAPI components, e.g. of generic types such as Opt[string].
+func openAPIComponentName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimSuffix(name, "]"))
+}
+
+func mergeResponses(a, b map[string]any) map[string]any {
+	merged := maps.Clone(a)
+	maps.Copy(merged, b)
+	return merged
+}
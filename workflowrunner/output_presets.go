@@ -0,0 +1,82 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// DefaultOutputTypePresets returns the builtin output type presets, which
+// manifests reference with OutputTypeDeclaration.PresetRef:
+//
+//   - "classification": a label with a confidence between 0 and 1, and the
+//     rationale of the choice;
+//   - "extraction": a list of items, each with a type, a value and the text
+//     it was extracted from;
+//   - "qa_with_citations": an answer with the sources supporting it.
+//
+// The schemas are compatible with strict structured outputs.
+func DefaultOutputTypePresets() map[string]map[string]any {
+	return map[string]map[string]any{
+		"classification": strictObject(map[string]any{
+			"label":      map[string]any{"type": "string"},
+			"confidence": map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+			"rationale":  map[string]any{"type": "string"},
+		}),
+		"extraction": strictObject(map[string]any{
+			"items": map[string]any{
+				"type": "array",
+				"items": strictObject(map[string]any{
+					"type":  map[string]any{"type": "string"},
+					"value": map[string]any{"type": "string"},
+					"span":  map[string]any{"type": "string"},
+				}),
+			},
+		}),
+		"qa_with_citations": strictObject(map[string]any{
+			"answer": map[string]any{"type": "string"},
+			"citations": map[string]any{
+				"type": "array",
+				"items": strictObject(map[string]any{
+					"source": map[string]any{"type": "string"},
+					"quote":  map[string]any{"type": "string"},
+				}),
+			},
+		}),
+	}
+}
+
+// strictObject returns an object schema requiring all of its properties.
+func strictObject(properties map[string]any) map[string]any {
+	var required []any
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		required = append(required, name)
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// WithOutputTypePreset registers an output type JSON schema which manifests
+// reference with OutputTypeDeclaration.PresetRef, and returns the builder.
+func (b *Builder) WithOutputTypePreset(name string, schema map[string]any) *Builder {
+	if b.OutputTypePresets == nil {
+		b.OutputTypePresets = make(map[string]map[string]any)
+	}
+	b.OutputTypePresets[name] = maps.Clone(schema)
+	return b
+}
+
+// outputTypePreset resolves the preset of the declaration into its schema.
+func (b *Builder) outputTypePreset(decl OutputTypeDeclaration) (OutputTypeDeclaration, bool) {
+	schema, ok := b.OutputTypePresets[decl.PresetRef]
+	if !ok {
+		return decl, false
+	}
+	decl.Name = cmp.Or(decl.Name, decl.PresetRef)
+	decl.Schema = schema
+	return decl, true
+}
@@ -0,0 +1,162 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// WorkflowPlan is the fully resolved agent graph of a workflow, as it would be
+// executed. See RunnerService.Plan.
+type WorkflowPlan struct {
+	WorkflowName  string      `json:"workflow_name"`
+	StartingAgent string      `json:"starting_agent"`
+	MaxRouteHops  int         `json:"max_route_hops"`
+	Agents        []AgentPlan `json:"agents"`
+}
+
+// AgentPlan describes a built agent. Agents are referenced by their
+// declaration names. Model keys, endpoints and headers and tool
+// configurations are left out, as they may hold resolved secrets.
+type AgentPlan struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	// Variant chosen for the session of the request, if the agent declares
+	// any.
+	Variant string `json:"variant,omitempty"`
+	// Instructions rendered for the request.
+	Instructions     string             `json:"instructions,omitempty"`
+	PromptID         string             `json:"prompt_id,omitempty"`
+	Provider         string             `json:"provider,omitempty"`
+	Model            string             `json:"model,omitempty"`
+	Tools            []ToolPlan         `json:"tools,omitempty"`
+	Handoffs         []string           `json:"handoffs,omitempty"`
+	InputGuardrails  []string           `json:"input_guardrails,omitempty"`
+	OutputGuardrails []string           `json:"output_guardrails,omitempty"`
+	OutputType       string             `json:"output_type,omitempty"`
+	OutputSchema     map[string]any     `json:"output_schema,omitempty"`
+	Routes           []RouteDeclaration `json:"routes,omitempty"`
+	Loop             *LoopDeclaration   `json:"loop,omitempty"`
+	FanOut           []string           `json:"fan_out,omitempty"`
+}
+
+// ToolPlan describes a tool of a built agent.
+type ToolPlan struct {
+	// Name of the tool, as seen by the model.
+	Name string `json:"name"`
+	// Type of the tool declaration, or "agent_tool" for agents used as tools.
+	Type string `json:"type"`
+	// Agent called by an agent tool.
+	Agent string `json:"agent,omitempty"`
+}
+
+// Plan builds the workflow of the request, resolving its tools, output
+// types, interpolation and secret references and rendering the agent
+// instructions, and returns the resulting agent graph without calling any
+// model. No session is created, so plans can be used to validate manifests,
+// e.g. in CI.
+func (s *RunnerService) Plan(ctx context.Context, req WorkflowRequest) (*WorkflowPlan, error) {
+	if s.Builder == nil {
+		return nil, errors.New("RunnerService missing Builder")
+	}
+	return s.Builder.Plan(ctx, req)
+}
+
+// Plan is like RunnerService.Plan.
+func (b *Builder) Plan(ctx context.Context, req WorkflowRequest) (*WorkflowPlan, error) {
+	req, variants, err := b.prepareRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Callback.Mode != CallbackModeStream {
+		if _, err := b.resolveCallback(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	graph, err := b.buildAgents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRouteHops := req.Workflow.MaxRouteHops
+	if maxRouteHops == 0 {
+		maxRouteHops = DefaultMaxRouteHops
+	}
+	plan := &WorkflowPlan{
+		WorkflowName:  req.Workflow.Name,
+		StartingAgent: req.Workflow.StartingAgent,
+		MaxRouteHops:  maxRouteHops,
+		Agents:        make([]AgentPlan, 0, len(req.Workflow.Agents)),
+	}
+	for _, decl := range req.Workflow.Agents {
+		agentPlan, err := planAgent(ctx, decl, graph)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", decl.Name, err)
+		}
+		agentPlan.Variant = variants[decl.Name]
+		plan.Agents = append(plan.Agents, agentPlan)
+	}
+	return plan, nil
+}
+
+func planAgent(ctx context.Context, decl AgentDeclaration, graph *agentGraph) (AgentPlan, error) {
+	agent := graph.agentMap[decl.Name]
+	plan := AgentPlan{
+		Name:             decl.Name,
+		DisplayName:      decl.DisplayName,
+		PromptID:         decl.PromptID,
+		Handoffs:         decl.Handoffs,
+		InputGuardrails:  guardrailNames(decl.InputGuardrails),
+		OutputGuardrails: guardrailNames(decl.OutputGuardrails),
+		Routes:           decl.Routes,
+		Loop:             decl.Loop,
+	}
+	instructions, err := agent.GetSystemPrompt(ctx)
+	if err != nil {
+		return plan, fmt.Errorf("render instructions: %w", err)
+	}
+	plan.Instructions = instructions.Or("")
+	if decl.Model != nil {
+		plan.Provider = decl.Model.Provider
+		plan.Model = decl.Model.Model
+	}
+	if decl.FanOut != nil {
+		plan.FanOut = decl.FanOut.Agents
+	}
+	if agent.OutputType != nil {
+		plan.OutputType = agent.OutputType.Name()
+		if !agent.OutputType.IsPlainText() {
+			if plan.OutputSchema, err = agent.OutputType.JSONSchema(); err != nil {
+				return plan, fmt.Errorf("output type schema: %w", err)
+			}
+		}
+	}
+
+	// Tools were added in the same order by buildAgents.
+	toolTypes := make([]ToolPlan, 0, len(agent.Tools))
+	for _, ref := range decl.AgentTools {
+		toolTypes = append(toolTypes, ToolPlan{Type: "agent_tool", Agent: ref.AgentName})
+	}
+	for _, toolDecl := range slices.Concat(decl.Tools, toolsFromMCP(decl.MCPServers)) {
+		toolTypes = append(toolTypes, ToolPlan{Type: toolDecl.Type})
+	}
+	for i, tool := range agent.Tools {
+		if i < len(toolTypes) {
+			toolTypes[i].Name = tool.ToolName()
+		}
+	}
+	plan.Tools = toolTypes
+	return plan, nil
+}
+
+func guardrailNames(decls []GuardrailDeclaration) []string {
+	if len(decls) == 0 {
+		return nil
+	}
+	names := make([]string, len(decls))
+	for i, decl := range decls {
+		names[i] = decl.Name
+	}
+	return names
+}
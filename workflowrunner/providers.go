@@ -0,0 +1,165 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+// ModelProviderFactory creates the provider of the models declared with
+// ModelDeclaration.Provider.
+type ModelProviderFactory func(ctx context.Context, decl ModelDeclaration) (agents.ModelProvider, error)
+
+// Model APIs which can be declared in ModelDeclaration.API.
+const (
+	ModelAPIResponses       = "responses"
+	ModelAPIChatCompletions = "chat_completions"
+)
+
+// structuredOutputModes maps ModelDeclaration.StructuredOutput values to
+// the modes of the Chat Completions models.
+var structuredOutputModes = map[string]agents.StructuredOutputMode{
+	"":            agents.StructuredOutputJSONSchema,
+	"json_schema": agents.StructuredOutputJSONSchema,
+	"json_mode":   agents.StructuredOutputJSONMode,
+	"prompt":      agents.StructuredOutputPrompt,
+}
+
+// OpenAICompatibleProvider returns a factory for providers exposing an
+// OpenAI compatible API at baseURL, e.g. "https://api.mistral.ai/v1/". The
+// base URL and API key of the declaration take precedence; the API key
+// defaults to the apiKeyEnv environment variable. Models use the Chat
+// Completions API, unless the declaration asks for the Responses API.
+func OpenAICompatibleProvider(defaultBaseURL, apiKeyEnv string) ModelProviderFactory {
+	return func(_ context.Context, decl ModelDeclaration) (agents.ModelProvider, error) {
+		baseURL := defaultBaseURL
+		if decl.BaseURL != "" {
+			baseURL = decl.BaseURL
+		}
+		if baseURL == "" {
+			return nil, errors.New("base_url is required")
+		}
+		apiKey := decl.APIKey
+		if apiKey == "" && apiKeyEnv != "" {
+			apiKey = os.Getenv(apiKeyEnv)
+		}
+		if apiKey == "" {
+			return nil, errors.New("api_key is required")
+		}
+		client := agents.NewOpenaiClient(param.NewOpt(baseURL), param.NewOpt(apiKey))
+		return agents.NewOpenAIProvider(agents.OpenAIProviderParams{
+			OpenaiClient: &client,
+			UseResponses: param.NewOpt(strings.EqualFold(decl.API, ModelAPIResponses)),
+		}), nil
+	}
+}
+
+func newOpenAIModelProvider(_ context.Context, decl ModelDeclaration) (agents.ModelProvider, error) {
+	params := agents.OpenAIProviderParams{}
+	if decl.BaseURL != "" || decl.APIKey != "" {
+		var baseURL, apiKey param.Opt[string]
+		if decl.BaseURL != "" {
+			baseURL = param.NewOpt(decl.BaseURL)
+		}
+		if decl.APIKey != "" {
+			apiKey = param.NewOpt(decl.APIKey)
+		}
+		client := agents.NewOpenaiClient(baseURL, apiKey)
+		params.OpenaiClient = &client
+	}
+	if decl.API != "" {
+		params.UseResponses = param.NewOpt(strings.EqualFold(decl.API, ModelAPIResponses))
+	}
+	return agents.NewOpenAIProvider(params), nil
+}
+
+// usesDefaultProvider reports whether the declared model is served by the
+// default OpenAI provider of the runner.
+func usesDefaultProvider(decl ModelDeclaration) bool {
+	provider := strings.TrimSpace(decl.Provider)
+	return (provider == "" || strings.EqualFold(provider, "openai")) &&
+		decl.BaseURL == "" && decl.APIKey == "" && decl.API == "" && decl.StructuredOutput == ""
+}
+
+// modelProviders gathers the providers declared by the agents of a workflow
+// into a MultiProvider, where each distinct provider configuration has its
+// own prefix.
+type modelProviders struct {
+	factories map[string]ModelProviderFactory
+	providers *agents.MultiProviderMap
+	prefixes  map[modelProviderKey]string
+	multi     *agents.MultiProvider
+}
+
+type modelProviderKey struct {
+	provider, baseURL, apiKey, api string
+}
+
+func newModelProviders(factories map[string]ModelProviderFactory) *modelProviders {
+	return &modelProviders{
+		factories: factories,
+		providers: agents.NewMultiProviderMap(),
+		prefixes:  make(map[modelProviderKey]string),
+	}
+}
+
+// model returns the model of the declaration, or nil when it is served by the
+// default provider.
+func (p *modelProviders) model(ctx context.Context, decl ModelDeclaration) (agents.Model, error) {
+	if usesDefaultProvider(decl) {
+		return nil, nil
+	}
+	name := strings.ToLower(strings.TrimSpace(decl.Provider))
+	if name == "" {
+		name = "openai"
+	}
+	key := modelProviderKey{provider: name, baseURL: decl.BaseURL, apiKey: decl.APIKey, api: decl.API}
+	prefix, ok := p.prefixes[key]
+	if !ok {
+		factory, ok := p.factories[name]
+		if !ok {
+			return nil, fmt.Errorf("provider %q not registered", decl.Provider)
+		}
+		provider, err := factory(ctx, decl)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", decl.Provider, err)
+		}
+		prefix = name
+		for n := 2; prefix == "openai" || p.providers.HasPrefix(prefix); n++ {
+			prefix = fmt.Sprintf("%s-%d", name, n)
+		}
+		p.providers.AddProvider(prefix, provider)
+		p.prefixes[key] = prefix
+	}
+	if p.multi == nil {
+		p.multi = agents.NewMultiProvider(agents.NewMultiProviderParams{ProviderMap: p.providers})
+	}
+	model, err := p.multi.GetModel(prefix + "/" + decl.Model)
+	if err != nil {
+		return nil, err
+	}
+	mode := structuredOutputModes[strings.ToLower(decl.StructuredOutput)]
+	if mode == agents.StructuredOutputJSONSchema {
+		return model, nil
+	}
+	chatModel, ok := model.(agents.OpenAIChatCompletionsModel)
+	if !ok {
+		return nil, fmt.Errorf("structured_output %q requires the %s api", decl.StructuredOutput, ModelAPIChatCompletions)
+	}
+	chatModel.StructuredOutputMode = mode
+	return chatModel, nil
+}
+
+// modelProvider returns the MultiProvider of the runner, or nil when all the
+// agents use the default provider.
+func (p *modelProviders) modelProvider() agents.ModelProvider {
+	if p.multi == nil {
+		return nil
+	}
+	return p.multi
+}
@@ -0,0 +1,130 @@
+// Package redisstate provides a Redis-backed workflowrunner.ExecutionStateStore,
+// so that execution states and pending approvals survive process restarts and
+// are shared by the replicas of a service:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	service := workflowrunner.NewRunnerService(nil)
+//	service.StateStore = redisstate.New(client, 24*time.Hour)
+package redisstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/workflowrunner"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyPrefix is the prefix of the keys of the states.
+const DefaultKeyPrefix = "workflowrunner:state:"
+
+// saveScript replaces the state stored in the hash KEYS[1] when its version
+// is ARGV[1], or when ARGV[1] is zero and the stored state is not suspended.
+// ARGV[2] is the encoded state, ARGV[3] the TTL in milliseconds and ARGV[4]
+// "1" when the state is suspended. It returns the new version, or 0 on
+// conflicts.
+var saveScript = redis.NewScript(`
+local expected = tonumber(ARGV[1])
+local current = redis.call('HGET', KEYS[1], 'version')
+local version = expected + 1
+if current then
+	if expected == 0 then
+		if redis.call('HGET', KEYS[1], 'suspended') == '1' then
+			return 0
+		end
+		version = tonumber(current) + 1
+	elseif tonumber(current) ~= expected then
+		return 0
+	end
+end
+redis.call('HSET', KEYS[1], 'version', version, 'state', ARGV[2], 'suspended', ARGV[4])
+local ttl = tonumber(ARGV[3])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return version
+`)
+
+// Store is an ExecutionStateStore keeping each state in a Redis hash.
+// Versions are checked atomically by a script, which implements the
+// optimistic locking of workflowrunner.ExecutionStateStore. The version of
+// the hash is authoritative over the one of the encoded state.
+type Store struct {
+	client redis.UniversalClient
+	// KeyPrefix defaults to DefaultKeyPrefix.
+	KeyPrefix string
+	// TTL of the states, refreshed by each save. Zero keeps them until they
+	// are cleared.
+	TTL time.Duration
+}
+
+// New returns a Store using the given client, which may be a single node,
+// cluster or sentinel client.
+func New(client redis.UniversalClient, ttl time.Duration) *Store {
+	return &Store{client: client, TTL: ttl}
+}
+
+func (s *Store) key(sessionID string) string {
+	prefix := s.KeyPrefix
+	if prefix == "" {
+		prefix = DefaultKeyPrefix
+	}
+	return prefix + sessionID
+}
+
+func (s *Store) Save(ctx context.Context, state workflowrunner.WorkflowExecutionState) error {
+	if state.SessionID == "" {
+		return errors.New("missing session id")
+	}
+	expected := state.Version
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal execution state: %w", err)
+	}
+	suspended := "0"
+	if state.Checkpoint != nil {
+		suspended = "1"
+	}
+	saved, err := saveScript.Run(ctx, s.client, []string{s.key(state.SessionID)},
+		expected, data, s.TTL.Milliseconds(), suspended).Int()
+	if err != nil {
+		return fmt.Errorf("save execution state: %w", err)
+	}
+	if saved == 0 {
+		return workflowrunner.ErrExecutionStateConflict
+	}
+	return nil
+}
+
+func (s *Store) Load(ctx context.Context, sessionID string) (workflowrunner.WorkflowExecutionState, bool, error) {
+	values, err := s.client.HMGet(ctx, s.key(sessionID), "version", "state").Result()
+	if err != nil {
+		return workflowrunner.WorkflowExecutionState{}, false, fmt.Errorf("load execution state: %w", err)
+	}
+	version, _ := values[0].(string)
+	data, ok := values[1].(string)
+	if !ok {
+		return workflowrunner.WorkflowExecutionState{}, false, nil
+	}
+	var state workflowrunner.WorkflowExecutionState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return workflowrunner.WorkflowExecutionState{}, false, fmt.Errorf("decode execution state: %w", err)
+	}
+	if state.Version, err = strconv.ParseInt(version, 10, 64); err != nil {
+		return workflowrunner.WorkflowExecutionState{}, false, fmt.Errorf("decode execution state version: %w", err)
+	}
+	return state, true, nil
+}
+
+func (s *Store) Clear(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, s.key(sessionID)).Err(); err != nil {
+		return fmt.Errorf("clear execution state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrWorkflowNotFound is returned when a workflow reference matches no
+	// registered workflow.
+	ErrWorkflowNotFound = errors.New("workflow not found")
+	// ErrWorkflowVersionExists is returned when registering a version again:
+	// registered versions are immutable.
+	ErrWorkflowVersionExists = errors.New("workflow version already registered")
+)
+
+// WorkflowRegistry stores workflow declarations under names and versions, so
+// that requests can reference them with WorkflowRequest.WorkflowRef instead
+// of embedding them.
+type WorkflowRegistry interface {
+	// Register stores a new version of the named workflow.
+	Register(ctx context.Context, name, version string, workflow WorkflowDeclaration) error
+	// Get returns a version of the named workflow, or its latest version when
+	// version is empty, along with the version returned.
+	Get(ctx context.Context, name, version string) (WorkflowDeclaration, string, error)
+	// Versions lists the versions of the named workflow, oldest first.
+	Versions(ctx context.Context, name string) ([]string, error)
+}
+
+// workflowRefPattern matches the names and versions of workflow references.
+var workflowRefPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ParseWorkflowRef splits a "name@version" reference; the version is empty
+// for references to the latest version.
+func ParseWorkflowRef(ref string) (name, version string, err error) {
+	name, version, hasVersion := strings.Cut(ref, "@")
+	if !workflowRefPattern.MatchString(name) {
+		return "", "", fmt.Errorf("invalid workflow name in reference %q", ref)
+	}
+	if hasVersion && !workflowRefPattern.MatchString(version) {
+		return "", "", fmt.Errorf("invalid workflow version in reference %q", ref)
+	}
+	return name, version, nil
+}
+
+// CompareWorkflowVersions orders versions such as "v2" < "v10" < "v10.1":
+// an optional "v" prefix is ignored and dot-separated numbers are compared
+// numerically; other versions are compared as strings.
+func CompareWorkflowVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		if aErr == nil && bErr == nil {
+			c = cmp.Compare(an, bn)
+		} else {
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+func latestWorkflowVersion(versions []string) (string, bool) {
+	if len(versions) == 0 {
+		return "", false
+	}
+	return slices.MaxFunc(versions, CompareWorkflowVersions), true
+}
+
+func validateWorkflowRegistration(name, version string, workflow WorkflowDeclaration) error {
+	if !workflowRefPattern.MatchString(name) {
+		return fmt.Errorf("invalid workflow name %q", name)
+	}
+	if !workflowRefPattern.MatchString(version) {
+		return fmt.Errorf("invalid workflow version %q", version)
+	}
+	if err := validateWorkflowDeclaration(workflow); err != nil {
+		return fmt.Errorf("workflow %s@%s invalid: %w", name, version, err)
+	}
+	return nil
+}
+
+// resolveWorkflowRef replaces the workflow reference of the request with the
+// registered declaration.
+func (b *Builder) resolveWorkflowRef(ctx context.Context, req WorkflowRequest) (WorkflowRequest, error) {
+	if req.WorkflowRef == "" {
+		return req, nil
+	}
+	if req.Workflow.Name != "" || len(req.Workflow.Agents) > 0 {
+		return req, errors.New("workflow and workflow_ref are mutually exclusive")
+	}
+	if b.WorkflowRegistry == nil {
+		return req, fmt.Errorf("workflow_ref %q: no workflow registry configured", req.WorkflowRef)
+	}
+	name, version, err := ParseWorkflowRef(req.WorkflowRef)
+	if err != nil {
+		return req, err
+	}
+	workflow, _, err := b.WorkflowRegistry.Get(ctx, name, version)
+	if err != nil {
+		return req, fmt.Errorf("workflow_ref %q: %w", req.WorkflowRef, err)
+	}
+	req.Workflow = workflow
+	req.WorkflowRef = ""
+	return req, nil
+}
+
+// InMemoryWorkflowRegistry is a WorkflowRegistry held in memory.
+type InMemoryWorkflowRegistry struct {
+	mu        sync.RWMutex
+	workflows map[string]map[string]WorkflowDeclaration
+}
+
+func NewInMemoryWorkflowRegistry() *InMemoryWorkflowRegistry {
+	return &InMemoryWorkflowRegistry{workflows: make(map[string]map[string]WorkflowDeclaration)}
+}
+
+func (r *InMemoryWorkflowRegistry) Register(_ context.Context, name, version string, workflow WorkflowDeclaration) error {
+	if err := validateWorkflowRegistration(name, version, workflow); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, ok := r.workflows[name]
+	if !ok {
+		versions = make(map[string]WorkflowDeclaration)
+		r.workflows[name] = versions
+	}
+	if _, exists := versions[version]; exists {
+		return fmt.Errorf("%w: %s@%s", ErrWorkflowVersionExists, name, version)
+	}
+	versions[version] = workflow
+	return nil
+}
+
+func (r *InMemoryWorkflowRegistry) Get(_ context.Context, name, version string) (WorkflowDeclaration, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := r.workflows[name]
+	if version == "" {
+		var ok bool
+		if version, ok = latestWorkflowVersion(slices.Collect(maps.Keys(versions))); !ok {
+			return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s", ErrWorkflowNotFound, name)
+		}
+	}
+	workflow, ok := versions[version]
+	if !ok {
+		return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s@%s", ErrWorkflowNotFound, name, version)
+	}
+	return workflow, version, nil
+}
+
+func (r *InMemoryWorkflowRegistry) Versions(_ context.Context, name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := slices.Collect(maps.Keys(r.workflows[name]))
+	slices.SortFunc(versions, CompareWorkflowVersions)
+	return versions, nil
+}
+
+// DirWorkflowRegistry stores each workflow version as a JSON workflow
+// declaration in <Dir>/<name>/<version>.json, e.g. files kept in a
+// repository and deployed with the service.
+type DirWorkflowRegistry struct {
+	Dir string
+}
+
+func (r DirWorkflowRegistry) path(name, version string) string {
+	return filepath.Join(r.Dir, name, version+".json")
+}
+
+func (r DirWorkflowRegistry) Register(_ context.Context, name, version string, workflow WorkflowDeclaration) error {
+	if err := validateWorkflowRegistration(name, version, workflow); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(workflow, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal workflow: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(r.Dir, name), 0o755); err != nil {
+		return fmt.Errorf("create workflow dir: %w", err)
+	}
+	f, err := os.OpenFile(r.path(name, version), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: %s@%s", ErrWorkflowVersionExists, name, version)
+	}
+	if err != nil {
+		return fmt.Errorf("create workflow file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write workflow file: %w", err)
+	}
+	return f.Close()
+}
+
+func (r DirWorkflowRegistry) Get(ctx context.Context, name, version string) (WorkflowDeclaration, string, error) {
+	if !workflowRefPattern.MatchString(name) || (version != "" && !workflowRefPattern.MatchString(version)) {
+		return WorkflowDeclaration{}, "", fmt.Errorf("invalid workflow reference %s@%s", name, version)
+	}
+	if version == "" {
+		versions, err := r.Versions(ctx, name)
+		if err != nil {
+			return WorkflowDeclaration{}, "", err
+		}
+		var ok bool
+		if version, ok = latestWorkflowVersion(versions); !ok {
+			return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s", ErrWorkflowNotFound, name)
+		}
+	}
+	data, err := os.ReadFile(r.path(name, version))
+	if errors.Is(err, os.ErrNotExist) {
+		return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s@%s", ErrWorkflowNotFound, name, version)
+	}
+	if err != nil {
+		return WorkflowDeclaration{}, "", fmt.Errorf("read workflow file: %w", err)
+	}
+	var workflow WorkflowDeclaration
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return WorkflowDeclaration{}, "", fmt.Errorf("decode workflow %s@%s: %w", name, version, err)
+	}
+	return workflow, version, nil
+}
+
+func (r DirWorkflowRegistry) Versions(_ context.Context, name string) ([]string, error) {
+	if !workflowRefPattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid workflow name %q", name)
+	}
+	entries, err := os.ReadDir(filepath.Join(r.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list workflow versions: %w", err)
+	}
+	var versions []string
+	for _, entry := range entries {
+		if version, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() && workflowRefPattern.MatchString(version) {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortFunc(versions, CompareWorkflowVersions)
+	return versions, nil
+}
+
+// SQLWorkflowRegistry stores the workflows in a SQL table, created on first
+// use, of a SQLite or PostgreSQL database.
+type SQLWorkflowRegistry struct {
+	DB *sql.DB
+	// Table defaults to "workflow_registry".
+	Table string
+	// Postgres selects the PostgreSQL placeholders.
+	Postgres bool
+
+	initOnce sync.Once
+	initErr  error
+}
+
+func (r *SQLWorkflowRegistry) table() string {
+	return cmp.Or(r.Table, "workflow_registry")
+}
+
+// query rewrites the "?" placeholders for PostgreSQL.
+func (r *SQLWorkflowRegistry) query(q string) string {
+	if !r.Postgres {
+		return q
+	}
+	var sb strings.Builder
+	n := 0
+	for _, c := range q {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&sb, "$%d", n)
+		} else {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+func (r *SQLWorkflowRegistry) init(ctx context.Context) error {
+	r.initOnce.Do(func() {
+		_, r.initErr = r.DB.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			name TEXT NOT NULL,
+			version TEXT NOT NULL,
+			workflow TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (name, version)
+		)`, r.table()))
+		if r.initErr != nil {
+			r.initErr = fmt.Errorf("create workflow registry table: %w", r.initErr)
+		}
+	})
+	return r.initErr
+}
+
+func (r *SQLWorkflowRegistry) Register(ctx context.Context, name, version string, workflow WorkflowDeclaration) error {
+	if err := validateWorkflowRegistration(name, version, workflow); err != nil {
+		return err
+	}
+	if err := r.init(ctx); err != nil {
+		return err
+	}
+	data, err := json.Marshal(workflow)
+	if err != nil {
+		return fmt.Errorf("marshal workflow: %w", err)
+	}
+	res, err := r.DB.ExecContext(ctx, r.query(fmt.Sprintf(
+		`INSERT INTO %s (name, version, workflow, created_at) VALUES (?, ?, ?, ?) ON CONFLICT (name, version) DO NOTHING`,
+		r.table())), name, version, string(data), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("register workflow: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s@%s", ErrWorkflowVersionExists, name, version)
+	}
+	return nil
+}
+
+func (r *SQLWorkflowRegistry) Get(ctx context.Context, name, version string) (WorkflowDeclaration, string, error) {
+	if version == "" {
+		versions, err := r.Versions(ctx, name)
+		if err != nil {
+			return WorkflowDeclaration{}, "", err
+		}
+		var ok bool
+		if version, ok = latestWorkflowVersion(versions); !ok {
+			return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s", ErrWorkflowNotFound, name)
+		}
+	} else if err := r.init(ctx); err != nil {
+		return WorkflowDeclaration{}, "", err
+	}
+	var data string
+	err := r.DB.QueryRowContext(ctx, r.query(fmt.Sprintf(
+		`SELECT workflow FROM %s WHERE name = ? AND version = ?`, r.table())), name, version).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return WorkflowDeclaration{}, "", fmt.Errorf("%w: %s@%s", ErrWorkflowNotFound, name, version)
+	}
+	if err != nil {
+		return WorkflowDeclaration{}, "", fmt.Errorf("get workflow: %w", err)
+	}
+	var workflow WorkflowDeclaration
+	if err := json.Unmarshal([]byte(data), &workflow); err != nil {
+		return WorkflowDeclaration{}, "", fmt.Errorf("decode workflow %s@%s: %w", name, version, err)
+	}
+	return workflow, version, nil
+}
+
+func (r *SQLWorkflowRegistry) Versions(ctx context.Context, name string) ([]string, error) {
+	if err := r.init(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := r.DB.QueryContext(ctx, r.query(fmt.Sprintf(
+		`SELECT version FROM %s WHERE name = ?`, r.table())), name)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow versions: %w", err)
+	}
+	defer rows.Close()
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("list workflow versions: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list workflow versions: %w", err)
+	}
+	slices.SortFunc(versions, CompareWorkflowVersions)
+	return versions, nil
+}
@@ -0,0 +1,265 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// DefaultMaxRouteHops is the maximum number of routes followed in a run when
+// the workflow does not set max_route_hops.
+const DefaultMaxRouteHops = 10
+
+// agentFlow holds the compiled routes, loop, fan-out and retry policy of an
+// agent.
+type agentFlow struct {
+	agentName string
+	routes    []compiledRoute
+	loop      *compiledLoop
+	fanOut    []fanOutBranch
+	retry     *AgentRetryPolicy
+}
+
+type compiledRoute struct {
+	index int
+	when  compiledCondition
+	next  *agents.Agent
+}
+
+type compiledLoop struct {
+	until         compiledCondition
+	maxIterations int
+	feedback      string
+}
+
+type compiledCondition struct {
+	decl ConditionDeclaration
+	expr *template.Template
+}
+
+// routeOutcome is the outcome of an agent, matched against its conditions.
+type routeOutcome struct {
+	output any
+	// Name of the output guardrail whose tripwire was triggered, if any.
+	guardrail string
+	// Number of times the agent ran in a row, see LoopDeclaration.
+	iteration int
+}
+
+// routeMatch is a route selected for an outcome.
+type routeMatch struct {
+	from  string
+	index int
+	next  *agents.Agent
+}
+
+func compileAgentFlow(decl AgentDeclaration, agentMap map[string]*agents.Agent) (*agentFlow, error) {
+	if len(decl.Routes) == 0 && decl.Loop == nil && decl.FanOut == nil && decl.Retry == nil {
+		return nil, nil
+	}
+	flow := &agentFlow{agentName: decl.Name, routes: make([]compiledRoute, 0, len(decl.Routes)), retry: decl.Retry}
+	for i, route := range decl.Routes {
+		next, ok := agentMap[route.Next]
+		if !ok {
+			return nil, fmt.Errorf("route %d references unknown agent %q", i, route.Next)
+		}
+		when, err := compileCondition(route.When)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		flow.routes = append(flow.routes, compiledRoute{index: i, when: when, next: next})
+	}
+	if decl.Loop != nil {
+		until, err := compileCondition(decl.Loop.Until)
+		if err != nil {
+			return nil, fmt.Errorf("loop: %w", err)
+		}
+		flow.loop = &compiledLoop{
+			until:         until,
+			maxIterations: decl.Loop.MaxIterations,
+			feedback:      decl.Loop.Feedback,
+		}
+	}
+	if decl.FanOut != nil {
+		for _, name := range decl.FanOut.Agents {
+			agent, ok := agentMap[name]
+			if !ok {
+				return nil, fmt.Errorf("fan_out references unknown agent %q", name)
+			}
+			flow.fanOut = append(flow.fanOut, fanOutBranch{name: name, agent: agent})
+		}
+	}
+	return flow, nil
+}
+
+func compileCondition(decl ConditionDeclaration) (compiledCondition, error) {
+	cond := compiledCondition{decl: decl}
+	if strings.TrimSpace(decl.Expression) != "" {
+		expr, err := parseRouteExpression(decl.Expression)
+		if err != nil {
+			return cond, fmt.Errorf("expression: %w", err)
+		}
+		cond.expr = expr
+	}
+	return cond, nil
+}
+
+func parseRouteExpression(expression string) (*template.Template, error) {
+	return template.New("expression").
+		Funcs(templateFuncs).
+		Option("missingkey=zero").
+		Parse(expression)
+}
+
+// nextRoute returns the first route of agent matching the outcome, if any.
+func (b *BuildResult) nextRoute(req WorkflowRequest, agent *agents.Agent, outcome routeOutcome) (*routeMatch, error) {
+	flow := b.flows[agent]
+	if flow == nil {
+		return nil, nil
+	}
+	output := normalizeJSONValue(outcome.output)
+	for _, route := range flow.routes {
+		ok, err := route.when.matches(flow.agentName, output, outcome, req)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q route %d: %w", flow.agentName, route.index, err)
+		}
+		if ok {
+			return &routeMatch{from: flow.agentName, index: route.index, next: route.next}, nil
+		}
+	}
+	return nil, nil
+}
+
+// repeatAgent returns the flow of agent when it loops and its outcome calls
+// for another iteration.
+func (b *BuildResult) repeatAgent(req WorkflowRequest, agent *agents.Agent, outcome routeOutcome) (*agentFlow, error) {
+	flow := b.flows[agent]
+	if flow == nil || flow.loop == nil || outcome.iteration >= flow.loop.maxIterations {
+		return nil, nil
+	}
+	done, err := flow.loop.until.matches(flow.agentName, normalizeJSONValue(outcome.output), outcome, req)
+	if err != nil || done {
+		return nil, err
+	}
+	return flow, nil
+}
+
+func (c compiledCondition) matches(agentName string, output any, outcome routeOutcome, req WorkflowRequest) (bool, error) {
+	cond := c.decl
+	if !strings.EqualFold(cond.GuardrailTriggered, outcome.guardrail) {
+		return false, nil
+	}
+
+	value, found := output, true
+	if cond.Field != "" {
+		value, found = lookupField(output, cond.Field)
+	}
+	if cond.Exists != nil && found != *cond.Exists {
+		return false, nil
+	}
+	if cond.Equals != nil && (!found || !reflect.DeepEqual(value, normalizeJSONValue(cond.Equals))) {
+		return false, nil
+	}
+	if len(cond.In) > 0 {
+		if !found {
+			return false, nil
+		}
+		in := false
+		for _, candidate := range cond.In {
+			if reflect.DeepEqual(value, normalizeJSONValue(candidate)) {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return false, nil
+		}
+	}
+	if cond.Contains != "" &&
+		(!found || !strings.Contains(strings.ToLower(outputText(value)), strings.ToLower(cond.Contains))) {
+		return false, nil
+	}
+
+	if c.expr != nil {
+		var sb strings.Builder
+		err := c.expr.Execute(&sb, map[string]any{
+			"output":    output,
+			"text":      outputText(output),
+			"agent":     agentName,
+			"guardrail": outcome.guardrail,
+			"iteration": outcome.iteration,
+			"metadata":  req.Metadata,
+			"context":   req.Context,
+			"inputs":    req.Inputs,
+		})
+		if err != nil {
+			return false, err
+		}
+		if strings.TrimSpace(sb.String()) != "true" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// lookupField resolves a dot-separated path in a JSON value.
+func lookupField(value any, path string) (any, bool) {
+	return lookupKeys(value, strings.Split(path, "."))
+}
+
+// lookupKeys walks the keys of maps and indices of lists.
+func lookupKeys(value any, keys []string) (any, bool) {
+	for _, key := range keys {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// normalizeJSONValue converts a value to its generic JSON representation, so
+// that structured outputs and declared values compare consistently.
+func normalizeJSONValue(v any) any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func outputText(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(raw)
+	}
+}
@@ -0,0 +1,230 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+)
+
+// RunResponse is the RunSummary returned by the HTTP API, with the error
+// as a message.
+type RunResponse struct {
+	RunSummary
+	Error string `json:"error,omitempty"`
+}
+
+// RunAccepted is returned by the HTTP API for the runs submitted without
+// waiting for them.
+type RunAccepted struct {
+	SessionID string `json:"session_id"`
+}
+
+// APIError is the body of the HTTP API error responses.
+type APIError struct {
+	Error string `json:"error"`
+}
+
+// RunHandler serves a JSON HTTP API submitting the runs of a RunnerService
+// and following them, described by OpenAPIDocument:
+//
+//	POST /runs
+//	POST /runs/stream
+//	POST /runs/resume
+//	POST /sessions/{session_id}/messages
+//	GET  /sessions/{session_id}/state
+//	GET  /openapi.json
+//
+// along with the endpoints of ApprovalHandler. The runs endpoints take a
+// WorkflowRequest. POST /runs waits for the run and returns a RunResponse,
+// or with ?async=true returns 202 Accepted and a RunAccepted at once. Its
+// events are published to the callback of the request, or discarded when it
+// declares the stream mode. POST /runs/stream streams the events of the run
+// as Server-Sent Events, named after their type, whose data is the JSON
+// CallbackEvent; the run is canceled when the client disconnects. POST
+// /sessions/{session_id}/messages takes a SessionMessage and streams the run
+// continuing the conversation of the session, see RunnerService.Continue. As
+// ApprovalHandler, the handler does not authenticate its clients.
+type RunHandler struct {
+	Service   *RunnerService
+	approvals *ApprovalHandler
+	mux       *http.ServeMux
+}
+
+// NewRunHandler returns a RunHandler for the given service. Set OnResolved
+// of Approvals to resume the runs once resolved.
+func NewRunHandler(service *RunnerService) *RunHandler {
+	h := &RunHandler{Service: service, approvals: NewApprovalHandler(service), mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /runs", h.run)
+	h.mux.HandleFunc("POST /runs/stream", h.stream)
+	h.mux.HandleFunc("POST /runs/resume", h.resume)
+	h.mux.HandleFunc("POST /sessions/{session_id}/messages", h.message)
+	h.mux.HandleFunc("GET /sessions/{session_id}/state", h.approvals.getState)
+	h.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, OpenAPIDocument())
+	})
+	h.mux.Handle("/sessions/", h.approvals)
+	return h
+}
+
+// Approvals returns the ApprovalHandler serving the approval and input
+// endpoints.
+func (h *RunHandler) Approvals() *ApprovalHandler {
+	return h.approvals
+}
+
+func (h *RunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *RunHandler) run(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRunRequest(w, r)
+	if !ok {
+		return
+	}
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	ctx := r.Context()
+	if async {
+		// Runs outlive the submitting requests.
+		ctx = context.WithoutCancel(ctx)
+	}
+	var task *asynctask.Task[RunSummary]
+	var err error
+	if req.Callback.Mode == CallbackModeStream {
+		task, err = h.Service.ExecuteWithPublisher(ctx, req, discardCallbackPublisher{})
+	} else {
+		task, err = h.Service.Execute(ctx, req)
+	}
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+	if async {
+		writeJSON(w, http.StatusAccepted, RunAccepted{SessionID: req.Session.SessionID})
+		return
+	}
+	writeJSON(w, http.StatusOK, newRunResponse(task.Await().Value))
+}
+
+func (h *RunHandler) resume(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRunRequest(w, r)
+	if !ok {
+		return
+	}
+	var task *asynctask.Task[RunSummary]
+	var err error
+	if req.Callback.Mode == CallbackModeStream {
+		task, err = h.Service.ResumeWithPublisher(r.Context(), req, discardCallbackPublisher{})
+	} else {
+		task, err = h.Service.Resume(r.Context(), req)
+	}
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newRunResponse(task.Await().Value))
+}
+
+func (h *RunHandler) stream(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRunRequest(w, r)
+	if !ok {
+		return
+	}
+	publisher := &sseCallbackPublisher{w: w}
+	task, err := h.Service.ExecuteWithPublisher(r.Context(), req, publisher)
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+	// Run failures are sent as run.failed events.
+	_ = task.Await()
+}
+
+func (h *RunHandler) message(w http.ResponseWriter, r *http.Request) {
+	var body SessionMessage
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid message: %w", err))
+		return
+	}
+	publisher := &sseCallbackPublisher{w: w}
+	task, err := h.Service.ContinueWithPublisher(r.Context(), r.PathValue("session_id"), body, publisher)
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+	// Run failures are sent as run.failed events.
+	_ = task.Await()
+}
+
+func newRunResponse(summary RunSummary) RunResponse {
+	resp := RunResponse{RunSummary: summary}
+	if summary.Error != nil {
+		resp.Error = summary.Error.Error()
+	}
+	return resp
+}
+
+func decodeRunRequest(w http.ResponseWriter, r *http.Request) (WorkflowRequest, bool) {
+	var req WorkflowRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 8<<20)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid workflow request: %w", err))
+		return req, false
+	}
+	return req, true
+}
+
+// writeRunError writes the error of a run refused before starting.
+func writeRunError(w http.ResponseWriter, err error) {
+	var admissionErr *AdmissionError
+	switch {
+	case errors.As(err, &admissionErr):
+		writeJSONError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, ErrMissingCapabilities):
+		writeJSONError(w, http.StatusForbidden, err)
+	case errors.Is(err, ErrExecutionNotFound):
+		writeJSONError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrExecutionNotSuspended), errors.Is(err, ErrApprovalsPending), errors.Is(err, ErrExecutionStateConflict),
+		errors.Is(err, ErrExecutionActive):
+		writeJSONError(w, http.StatusConflict, err)
+	default:
+		writeJSONError(w, http.StatusUnprocessableEntity, err)
+	}
+}
+
+// discardCallbackPublisher drops the events of the runs declaring the stream
+// callback mode, which are not streamed.
+type discardCallbackPublisher struct{}
+
+func (discardCallbackPublisher) Publish(context.Context, CallbackEvent) error { return nil }
+
+// sseCallbackPublisher writes the events as Server-Sent Events.
+type sseCallbackPublisher struct {
+	w       http.ResponseWriter
+	mu      sync.Mutex
+	started bool
+}
+
+func (p *sseCallbackPublisher) Publish(_ context.Context, event CallbackEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal callback event: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.started {
+		p.started = true
+		p.w.Header().Set("Content-Type", "text/event-stream")
+		p.w.Header().Set("Cache-Control", "no-cache")
+		p.w.WriteHeader(http.StatusOK)
+	}
+	if _, err := fmt.Fprintf(p.w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	return http.NewResponseController(p.w).Flush()
+}
@@ -0,0 +1,157 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/usage"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+// TokenUsage counts the model requests and tokens of a run, or of one of its
+// agents, with their estimated cost.
+type TokenUsage struct {
+	Requests        uint64 `json:"requests"`
+	InputTokens     uint64 `json:"input_tokens"`
+	CachedTokens    uint64 `json:"cached_tokens,omitempty"`
+	OutputTokens    uint64 `json:"output_tokens"`
+	ReasoningTokens uint64 `json:"reasoning_tokens,omitempty"`
+	TotalTokens     uint64 `json:"total_tokens"`
+	// EstimatedCostUSD is estimated with usage.EstimateCost, from the prices
+	// set with usage.SetModelPricing.
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+func (u *TokenUsage) add(model string, response *usage.Usage) (priced bool) {
+	u.Requests += response.Requests
+	u.InputTokens += response.InputTokens
+	u.CachedTokens += uint64(max(response.InputTokensDetails.CachedTokens, 0))
+	u.OutputTokens += response.OutputTokens
+	u.ReasoningTokens += uint64(max(response.OutputTokensDetails.ReasoningTokens, 0))
+	u.TotalTokens += response.TotalTokens
+	cost, priced := usage.EstimateCost(model, response)
+	u.EstimatedCostUSD += cost
+	return priced
+}
+
+func (u *TokenUsage) merge(other TokenUsage) {
+	u.Requests += other.Requests
+	u.InputTokens += other.InputTokens
+	u.CachedTokens += other.CachedTokens
+	u.OutputTokens += other.OutputTokens
+	u.ReasoningTokens += other.ReasoningTokens
+	u.TotalTokens += other.TotalTokens
+	u.EstimatedCostUSD += other.EstimatedCostUSD
+}
+
+// RunUsage is the usage of a run, including the runs it resumed, broken down
+// by agent declaration name. It covers the agents of the workflow, fan-out
+// branches included, but not the agents run as tools.
+type RunUsage struct {
+	TokenUsage
+	ByAgent map[string]TokenUsage `json:"by_agent,omitempty"`
+	// UnpricedModels lists the models without known pricing, whose usage is
+	// left out of the estimated costs.
+	UnpricedModels []string `json:"unpriced_models,omitempty"`
+}
+
+func (u *RunUsage) clone() *RunUsage {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	c.ByAgent = maps.Clone(u.ByAgent)
+	c.UnpricedModels = slices.Clone(u.UnpricedModels)
+	return &c
+}
+
+// merge adds the usage of another run.
+func (u *RunUsage) merge(other *RunUsage) {
+	u.TokenUsage.merge(other.TokenUsage)
+	for agent, agentUsage := range other.ByAgent {
+		if u.ByAgent == nil {
+			u.ByAgent = make(map[string]TokenUsage)
+		}
+		merged := u.ByAgent[agent]
+		merged.merge(agentUsage)
+		u.ByAgent[agent] = merged
+	}
+	for _, model := range other.UnpricedModels {
+		if !slices.Contains(u.UnpricedModels, model) {
+			u.UnpricedModels = append(u.UnpricedModels, model)
+		}
+	}
+	slices.Sort(u.UnpricedModels)
+}
+
+// runUsageTracker aggregates the usage of the model responses, as the
+// agents.AgentHooks of every agent of the workflow.
+type runUsageTracker struct {
+	build *BuildResult
+
+	mu    sync.Mutex
+	usage RunUsage
+}
+
+// newRunUsageTracker sets itself as the hooks of the built agents. It
+// continues from the usage of a suspended run, if any.
+func newRunUsageTracker(build *BuildResult, previous *RunUsage) *runUsageTracker {
+	t := &runUsageTracker{build: build}
+	if previous != nil {
+		t.usage = *previous.clone()
+	}
+	for _, agent := range build.AgentMap {
+		agent.Hooks = t
+	}
+	return t
+}
+
+func (t *runUsageTracker) OnStart(context.Context, *agents.Agent) error { return nil }
+
+func (t *runUsageTracker) OnEnd(context.Context, *agents.Agent, any) error { return nil }
+
+func (t *runUsageTracker) OnHandoff(context.Context, *agents.Agent, *agents.Agent) error { return nil }
+
+func (t *runUsageTracker) OnToolStart(context.Context, *agents.Agent, agents.Tool, any) error {
+	return nil
+}
+
+func (t *runUsageTracker) OnToolEnd(context.Context, *agents.Agent, agents.Tool, any) error {
+	return nil
+}
+
+func (t *runUsageTracker) OnLLMStart(context.Context, *agents.Agent, param.Opt[string], []agents.TResponseInputItem) error {
+	return nil
+}
+
+func (t *runUsageTracker) OnLLMEnd(_ context.Context, agent *agents.Agent, response agents.ModelResponse) error {
+	if response.Usage == nil {
+		return nil
+	}
+	name := t.build.agentDeclarationName(agent)
+	model := cmp.Or(t.build.modelNames[agent], "default")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.usage.add(model, response.Usage) && !slices.Contains(t.usage.UnpricedModels, model) {
+		t.usage.UnpricedModels = append(t.usage.UnpricedModels, model)
+	}
+	if t.usage.ByAgent == nil {
+		t.usage.ByAgent = make(map[string]TokenUsage)
+	}
+	agentUsage := t.usage.ByAgent[name]
+	agentUsage.add(model, response.Usage)
+	t.usage.ByAgent[name] = agentUsage
+	return nil
+}
+
+// snapshot returns a copy of the usage so far.
+func (t *runUsageTracker) snapshot() *RunUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage.clone()
+}
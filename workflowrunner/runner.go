@@ -1,33 +1,106 @@
 package workflowrunner
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/nlpodyssey/openai-agents-go/agents"
 	"github.com/nlpodyssey/openai-agents-go/asynctask"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/nlpodyssey/openai-agents-go/metrics"
 	"github.com/nlpodyssey/openai-agents-go/tracing"
+	"github.com/openai/openai-go/v3/packages/param"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+var (
+	// ErrExecutionNotFound is returned when no execution state exists for a session.
+	ErrExecutionNotFound = errors.New("execution not found")
+	// ErrApprovalNotFound is returned when resolving an approval which is not pending.
+	ErrApprovalNotFound = errors.New("approval request not found")
+	// ErrExecutionNotSuspended is returned when resuming a session whose run
+	// was not suspended on approval requests.
+	ErrExecutionNotSuspended = errors.New("execution is not suspended")
+	// ErrApprovalsPending is returned when resuming a run before all its
+	// approval requests are resolved.
+	ErrApprovalsPending = errors.New("approval requests are pending")
+	// ErrRunDeadlineExceeded is returned by runs which exceeded the
+	// deadline_seconds of their session.
+	ErrRunDeadlineExceeded = errors.New("run deadline exceeded")
 )
 
 // RunnerService orchestrates building and executing workflow requests.
 type RunnerService struct {
 	Builder         *Builder
-	CallbackFactory func(ctx context.Context, decl CallbackDeclaration) (CallbackPublisher, error)
+	CallbackFactory CallbackPublisherFactory
 	StateStore      ExecutionStateStore
+	// DeadLetterSink receives the callback events which could not be
+	// delivered despite the retry policy of the callback.
+	DeadLetterSink DeadLetterSink
+	// ApprovalNotifiers are told about the runs suspended on approval
+	// requests.
+	ApprovalNotifiers []ApprovalNotifier
+	// ApprovalBaseURL is the URL where ApprovalHandler is served, used to
+	// link the approve and deny endpoints in the notifications.
+	ApprovalBaseURL string
+	// Admission, when set, bounds the concurrent runs, including resumed
+	// ones. Queued runs wait in their task, and are built once admitted.
+	Admission *AdmissionController
+	// TurnSnapshots saves a snapshot of the conversation in the execution
+	// state before each model call, so that the runs of a crashed process
+	// can be continued by another one with Recover.
+	TurnSnapshots bool
+	// ArtifactStore, when set, keeps the tool outputs larger than
+	// ArtifactInlineLimit, the generated images and the code interpreter
+	// files cited by the agents, which the callback events reference as
+	// artifacts instead of inlining them.
+	ArtifactStore ArtifactStore
+	// ArtifactInlineLimit is the size in bytes of the largest tool output
+	// inlined in the events. Defaults to DefaultArtifactInlineLimit.
+	ArtifactInlineLimit int
+	// StreamBuffer bounds the stream events of the runs waiting to be
+	// published, see agents.StreamBuffer; the zero value does not bound
+	// them. The runs are slowed down by slow callbacks once the buffer is
+	// full, unless it drops the deltas.
+	StreamBuffer agents.StreamBuffer
+	// IDGenerator generates the IDs of the traces, artifacts and triggered
+	// sessions. Defaults to RandomIDGenerator.
+	IDGenerator IDGenerator
+	// Clock timestamps the execution states, callback events, snapshots
+	// and artifacts. Defaults to time.Now.
+	Clock Clock
 }
 
 // RunSummary holds metadata about a completed run.
 type RunSummary struct {
-	WorkflowName   string           `json:"workflow_name"`
-	SessionID      string           `json:"session_id"`
-	FinalOutput    any              `json:"final_output"`
-	NewItems       []agents.RunItem `json:"-"`
-	LastResponseID string           `json:"last_response_id"`
-	Error          error            `json:"error,omitempty"`
+	WorkflowName string `json:"workflow_name"`
+	SessionID    string `json:"session_id"`
+	// Status is completed, failed, or waiting_approval or waiting_input when
+	// the run was suspended on the PendingApprovals or PendingInputs, see
+	// RunnerService.Resume.
+	Status           ExecutionStatus        `json:"status"`
+	PendingApprovals []ApprovalRequestState `json:"pending_approvals,omitempty"`
+	PendingInputs    []InputRequestState    `json:"pending_inputs,omitempty"`
+	FinalOutput      any                    `json:"final_output"`
+	NewItems         []agents.RunItem       `json:"-"`
+	// Items is the JSON serialization of NewItems, when requested with
+	// WorkflowRequest.IncludeItems.
+	Items          *SerializedRunItems `json:"items,omitempty"`
+	LastResponseID string              `json:"last_response_id"`
+	// Variants chosen for the agents declaring any, by agent.
+	Variants map[string]string `json:"variants,omitempty"`
+	// Usage of the run, including the runs it resumed.
+	Usage *RunUsage `json:"usage,omitempty"`
+	// Artifacts stored by the run, see RunnerService.ArtifactStore.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	Error     error      `json:"error,omitempty"`
 }
 
 // NewRunnerService constructs a RunnerService with sensible defaults.
@@ -41,8 +114,12 @@ func NewRunnerService(builder *Builder) *RunnerService {
 		CallbackFactory: func(ctx context.Context, decl CallbackDeclaration) (CallbackPublisher, error) {
 			switch decl.Mode {
 			case "", "http":
-				return NewHTTPCallbackPublisher(decl.Target, nil), nil
-			case "stdout", "stdout_verbose":
+				publisher := NewHTTPCallbackPublisher(decl.Target, nil)
+				if decl.SigningSecret != "" {
+					publisher.SigningSecret = []byte(decl.SigningSecret)
+				}
+				return publisher, nil
+			case "stdout", "stdout_verbose", CallbackModeTTY:
 				return StdoutCallbackPublisher{}, nil
 			default:
 				return nil, fmt.Errorf("unsupported callback mode %q", decl.Mode)
@@ -54,23 +131,211 @@ func NewRunnerService(builder *Builder) *RunnerService {
 
 // Execute validates, builds, and runs the workflow asynchronously.
 func (s *RunnerService) Execute(ctx context.Context, req WorkflowRequest) (*asynctask.Task[RunSummary], error) {
-	if s.Builder == nil {
-		return nil, errors.New("RunnerService missing Builder")
+	return s.execute(ctx, req, nil, nil)
+}
+
+// ExecuteWithPublisher is like Execute, but publishes the run events to the
+// given publisher instead of the callback declared in the request, which is
+// ignored and may be omitted.
+func (s *RunnerService) ExecuteWithPublisher(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher) (*asynctask.Task[RunSummary], error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
 	}
-	buildResult, err := s.Builder.Build(ctx, req)
+	req.Callback = CallbackDeclaration{Mode: CallbackModeStream}
+	return s.execute(ctx, req, publisher, nil)
+}
+
+// Resume continues the run of the session of the request, which was
+// suspended on approval or input requests, once they are all resolved with
+// ResolveApproval and ProvideInput. The request declares the workflow and
+// session of the suspended run; its query defaults to the one of the run.
+// The approval responses and the answers are added to the session, and the
+// run goes on from the agent which requested them.
+func (s *RunnerService) Resume(ctx context.Context, req WorkflowRequest) (*asynctask.Task[RunSummary], error) {
+	req, err := s.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.suspendedState(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	if strings.TrimSpace(req.Query) == "" {
+		req.Query = state.LastQuery
+	}
+	return s.execute(ctx, req, nil, &state)
+}
 
-	callbackFactory := s.CallbackFactory
-	if callbackFactory == nil {
-		callbackFactory = func(ctx context.Context, decl CallbackDeclaration) (CallbackPublisher, error) {
-			return StdoutCallbackPublisher{}, nil
-		}
+// ResumeWithPublisher is like Resume, with the events published as by
+// ExecuteWithPublisher.
+func (s *RunnerService) ResumeWithPublisher(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher) (*asynctask.Task[RunSummary], error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	req, err := s.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	publisher, err := callbackFactory(ctx, req.Callback)
+	state, err := s.suspendedState(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("create callback publisher: %w", err)
+		return nil, err
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		req.Query = state.LastQuery
+	}
+	req.Callback = CallbackDeclaration{Mode: CallbackModeStream}
+	return s.execute(ctx, req, publisher, &state)
+}
+
+// resolveWorkflowRef resolves the workflow reference of the request before
+// building it, as the workflow name is needed beforehand.
+func (s *RunnerService) resolveWorkflowRef(ctx context.Context, req WorkflowRequest) (WorkflowRequest, error) {
+	if s.Builder == nil {
+		return req, errors.New("RunnerService missing Builder")
+	}
+	return s.Builder.resolveWorkflowRef(ctx, req)
+}
+
+// suspendedState returns the state of the run to resume.
+func (s *RunnerService) suspendedState(ctx context.Context, req WorkflowRequest) (WorkflowExecutionState, error) {
+	sessionID := req.Session.SessionID
+	state, ok, err := s.GetState(ctx, sessionID)
+	if err != nil {
+		return state, err
+	}
+	if !ok {
+		return state, fmt.Errorf("%w: session %q", ErrExecutionNotFound, sessionID)
+	}
+	if state.Checkpoint == nil {
+		return state, fmt.Errorf("%w: session %q is %s", ErrExecutionNotSuspended, sessionID, state.Status)
+	}
+	if len(state.PendingApprovals) > 0 {
+		return state, fmt.Errorf("%w: session %q has %d pending approvals", ErrApprovalsPending, sessionID, len(state.PendingApprovals))
+	}
+	if len(state.PendingInputs) > 0 {
+		return state, fmt.Errorf("%w: session %q has %d pending inputs", ErrInputsPending, sessionID, len(state.PendingInputs))
+	}
+	if state.WorkflowName != req.Workflow.Name {
+		return state, fmt.Errorf("session %q was suspended in workflow %q, not %q", sessionID, state.WorkflowName, req.Workflow.Name)
+	}
+	return state, nil
+}
+
+// execute runs the request, or resumes the suspended run of the given state,
+// or recovers it from its snapshot if it has no checkpoint.
+func (s *RunnerService) execute(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher, resume *WorkflowExecutionState) (*asynctask.Task[RunSummary], error) {
+	if req.Batch != nil {
+		return nil, ErrBatchRequest
+	}
+	req, err := s.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	declared := req
+	var ticket *admissionTicket
+	if s.Admission != nil {
+		if ticket, err = s.Admission.enter(req.Workflow.Name); err != nil {
+			recordRejection(req.Workflow.Name, err)
+			return nil, err
+		}
+		// The slot is handed over to the task, if any.
+		defer func() {
+			if ticket != nil {
+				ticket.release()
+			}
+		}()
+	}
+	var (
+		buildResult *BuildResult
+		resumeAgent *agents.Agent
+		snapshot    *ExecutionSnapshot
+		runUsage    *runUsageTracker
+	)
+	if resume != nil && resume.Checkpoint == nil {
+		snapshot = resume.Snapshot
+	}
+	// build creates or forks the session and acquires the tenant quota, so
+	// queued runs build once admitted.
+	build := func(ctx context.Context) error {
+		result, err := s.Builder.Build(ctx, req)
+		if err != nil {
+			return err
+		}
+		if resume != nil {
+			name := ""
+			if resume.Checkpoint != nil {
+				name = resume.Checkpoint.Agent
+			} else {
+				name = snapshot.Agent
+			}
+			var ok bool
+			if resumeAgent, ok = result.AgentMap[name]; !ok {
+				closeSession(result.Session)
+				return fmt.Errorf("suspended agent %q not found in workflow", name)
+			}
+		}
+		buildResult = result
+		// Route expressions see the defaults of the inputs.
+		req.Inputs = buildResult.Inputs
+		var previousUsage *RunUsage
+		if resume != nil {
+			previousUsage = resume.Usage
+		}
+		runUsage = newRunUsageTracker(buildResult, previousUsage)
+		if s.TurnSnapshots {
+			withTurnSnapshots(buildResult)
+		}
+		return nil
+	}
+	closeBuild := func() {
+		if buildResult != nil {
+			closeSession(buildResult.Session)
+		}
+	}
+	if ticket == nil || ticket.isAdmitted() {
+		if err := build(ctx); err != nil {
+			recordRejection(req.Workflow.Name, err)
+			return nil, err
+		}
+	} else {
+		// Queued runs fail right away when invalid.
+		if err := ValidateWorkflowRequest(req); err != nil {
+			recordRejection(req.Workflow.Name, err)
+			return nil, err
+		}
+		if err := authorizeWorkflow(req); err != nil {
+			recordRejection(req.Workflow.Name, err)
+			return nil, err
+		}
+	}
+
+	var asyncQueueSize int
+	if publisher == nil {
+		req.Callback, err = s.Builder.resolveCallback(ctx, req)
+		if err != nil {
+			closeBuild()
+			return nil, err
+		}
+		callbackFactory := s.CallbackFactory
+		if callbackFactory == nil {
+			callbackFactory = func(ctx context.Context, decl CallbackDeclaration) (CallbackPublisher, error) {
+				return StdoutCallbackPublisher{}, nil
+			}
+		}
+		publisher, err = callbackFactory(ctx, req.Callback)
+		if err != nil {
+			closeBuild()
+			return nil, fmt.Errorf("create callback publisher: %w", err)
+		}
+		if req.Callback.Retry != nil {
+			publisher = &RetryingCallbackPublisher{
+				Publisher:  publisher,
+				Policy:     *req.Callback.Retry,
+				Target:     req.Callback.Target,
+				DeadLetter: s.DeadLetterSink,
+			}
+		}
+		asyncQueueSize = req.Callback.QueueSize
 	}
 
 	stateStore := s.StateStore
@@ -78,116 +343,497 @@ func (s *RunnerService) Execute(ctx context.Context, req WorkflowRequest) (*asyn
 		stateStore = NewInMemoryExecutionStateStore()
 	}
 	tracker := newExecutionStateTracker(stateStore, req.Session.SessionID, req.Workflow.Name)
+	tracker.clock = s.Clock
+	tracker.state.Request = storedRequest(declared)
+	var resumeResponses []agents.TResponseInputItem
+	if resume != nil {
+		resumeResponses = append(approvalResponseItems(resume.ResolvedApprovals),
+			inputResponseItems(resume.ProvidedInputs)...)
+		// The run is claimed before starting, so that it is resumed once.
+		tracker = resumeExecutionStateTracker(stateStore, *resume)
+		tracker.clock = s.Clock
+		tracker.state.Request = storedRequest(declared)
+		claim := tracker.OnRunResumed
+		if snapshot != nil {
+			claim = tracker.OnRunRecovered
+		}
+		if err := claim(ctx); err != nil {
+			closeBuild()
+			if errors.Is(err, ErrExecutionStateConflict) {
+				return nil, fmt.Errorf("resume session %q: %w", req.Session.SessionID, err)
+			}
+			return nil, err
+		}
+	}
+	tracker.usage = runUsage
 	callbackMode := strings.ToLower(req.Callback.Mode)
-	consoleEnabled := callbackMode == "stdout" || callbackMode == "stdout_verbose"
-	consoleVerbose := callbackMode == "stdout_verbose"
-	printer := newConsolePrinter(consoleEnabled, consoleVerbose)
+	consoleEnabled := callbackMode == "stdout" || callbackMode == "stdout_verbose" || callbackMode == CallbackModeTTY
+	consoleVerbose := callbackMode == "stdout_verbose" || callbackMode == CallbackModeTTY
+	printer := newConsolePrinter(consoleEnabled, consoleVerbose, callbackMode == CallbackModeTTY && stdoutIsTerminal())
 	skipPublishing := consoleEnabled
+	var artifacts *runArtifacts
+	if s.ArtifactStore != nil {
+		artifacts = newRunArtifacts(s.ArtifactStore, s.ArtifactInlineLimit, req.Session.SessionID, s.ids(), s.Clock)
+	}
 
+	runTicket := ticket
+	ticket = nil
 	return asynctask.CreateTask(ctx, func(taskCtx context.Context) (RunSummary, error) {
-		defer func() {
-			if closer, ok := buildResult.Session.(interface{ Close() error }); ok {
-				_ = closer.Close()
+		defer closeBuild()
+		defer printer.close()
+		if asyncQueueSize > 0 {
+			async := NewAsyncCallbackPublisher(publisher, asyncQueueSize)
+			publisher = async
+			// The run completes once its events are delivered.
+			defer func() { _ = async.Close(context.WithoutCancel(taskCtx)) }()
+		}
+		if runTicket != nil {
+			defer runTicket.release()
+			err := runTicket.wait(taskCtx)
+			if err == nil && buildResult == nil {
+				if err = build(taskCtx); err == nil {
+					tracker.usage = runUsage
+				}
 			}
-		}()
+			if err != nil {
+				recordRejection(req.Workflow.Name, err)
+				if resume != nil {
+					_ = tracker.OnResumeRejected(taskCtx, *resume, err)
+				}
+				return RunSummary{
+					WorkflowName: req.Workflow.Name,
+					SessionID:    req.Session.SessionID,
+					Status:       ExecutionStatusFailed,
+					Error:        err,
+				}, err
+			}
+		}
+		if req.Session.DeadlineSeconds > 0 {
+			var cancel context.CancelFunc
+			taskCtx, cancel = context.WithTimeoutCause(taskCtx,
+				time.Duration(req.Session.DeadlineSeconds)*time.Second, ErrRunDeadlineExceeded)
+			defer cancel()
+		}
+		taskCtx = agents.ContextWithLogAttrs(
+			taskCtx,
+			slog.String("session_id", req.Session.SessionID),
+			slog.String("account_id", req.Session.Credentials.AccountID),
+		)
+		taskCtx = contextWithClock(taskCtx, s.Clock)
+		// Handoff conditions see the outputs of the agents completed so far.
+		handoffs := newHandoffRun(req)
+		taskCtx = contextWithHandoffRun(taskCtx, handoffs)
 
 		summary := RunSummary{
 			WorkflowName: req.Workflow.Name,
 			SessionID:    req.Session.SessionID,
+			Variants:     buildResult.Variants,
 		}
 		traceMetadata := buildResult.TraceMetadata
 		if traceMetadata == nil {
 			traceMetadata = composeTraceMetadata(req)
 		}
-		traceID := tracing.GenTraceID()
+		traceID := s.ids().TraceID()
 		buildResult.Runner.Config.TraceID = traceID
+		buildResult.Runner.Config.StreamBuffer = s.StreamBuffer
 
 		traceErr := tracing.RunTrace(taskCtx, tracing.TraceParams{
 			WorkflowName: req.Workflow.Name,
 			TraceID:      traceID,
-			GroupID:      req.Session.SessionID,
+			GroupID:      traceGroupID(req),
 			Metadata:     traceMetadata,
 		}, func(ctx context.Context, _ tracing.Trace) error {
-			if err := tracker.OnRunStarted(ctx, req.Query); err != nil {
-				return err
+			startPayload := &RunStartedPayload{
+				Workflow: req.Workflow.Name,
+				Session:  req.Session.SessionID,
+				Query:    req.Query,
+				Variants: buildResult.Variants,
 			}
-			printer.OnRunStarted(req.Query)
-			startEvent := CallbackEvent{
-				Type:      "run.started",
-				Timestamp: time.Now().UTC(),
-				Payload: map[string]any{
-					"workflow": req.Workflow.Name,
-					"session":  req.Session.SessionID,
-					"query":    req.Query,
-				},
+			startEvent := newCallbackEvent(s.now(), CallbackEventRunStarted, startPayload)
+			switch {
+			case snapshot != nil:
+				startEvent.Type = CallbackEventRunRecovered
+				startPayload.Agent = displayAgentName(resumeAgent)
+				startPayload.Turn = snapshot.Turn
+				printer.OnRunResumed(displayAgentName(resumeAgent))
+			case resume != nil:
+				startEvent.Type = CallbackEventRunResumed
+				startPayload.Agent = displayAgentName(resumeAgent)
+				startPayload.Approvals = resume.ResolvedApprovals
+				printer.OnRunResumed(displayAgentName(resumeAgent))
+			default:
+				if err := tracker.OnRunStarted(ctx, req.Query); err != nil {
+					return err
+				}
+				printer.OnRunStarted(req.Query)
 			}
 			if !skipPublishing {
 				_ = publisher.Publish(ctx, startEvent)
 			}
 
-			result, err := buildResult.Runner.RunStreamed(ctx, buildResult.StartingAgent, req.Query)
-			if err != nil {
+			// timeout ends a run canceled by its deadline, reporting how far
+			// it went. ctx is no longer canceled.
+			timeout := func(ctx context.Context, agent *agents.Agent, result *agents.RunResultStreaming, iteration, routeHops int) error {
+				runErr := fmt.Errorf("%w after %ds", ErrRunDeadlineExceeded, req.Session.DeadlineSeconds)
+				summary.Status = ExecutionStatusTimedOut
+				summary.Error = runErr
+				payload := RunTimeoutPayload{
+					Error:           runErr.Error(),
+					DeadlineSeconds: req.Session.DeadlineSeconds,
+					Agent:           displayAgentName(agent),
+					Iteration:       iteration,
+					RouteHops:       routeHops,
+				}
+				if result != nil {
+					summary.NewItems = result.NewItems()
+					summary.LastResponseID = result.LastResponseID()
+					payload.LastResponseID = result.LastResponseID()
+					payload.NewItems = len(result.NewItems())
+				}
+				if !skipPublishing {
+					_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunTimeout, payload))
+				}
+				_ = tracker.OnRunTimedOut(ctx, runErr)
+				printer.OnRunFailed(runErr)
+				return runErr
+			}
+
+			maxRouteHops := req.Workflow.MaxRouteHops
+			if maxRouteHops == 0 {
+				maxRouteHops = DefaultMaxRouteHops
+			}
+			runner := buildResult.Runner
+			agent := buildResult.StartingAgent
+			var (
+				result    *agents.RunResultStreaming
+				history   []agents.TResponseInputItem
+				routeHops int
+				iteration = 1
+				// Only the first step runs with the session: the input of
+				// later steps already holds the session history, and their
+				// items are saved to the session separately.
+				firstStep = true
+			)
+			fail := func(err error) error {
+				if errors.Is(context.Cause(ctx), ErrRunDeadlineExceeded) {
+					return timeout(context.WithoutCancel(ctx), agent, result, iteration, routeHops)
+				}
 				runErr := wrapRunError(err)
+				summary.Status = ExecutionStatusFailed
 				summary.Error = runErr
 				if !skipPublishing {
-					_ = publisher.Publish(ctx, CallbackEvent{
-						Type:      "run.failed",
-						Timestamp: time.Now().UTC(),
-						Payload: map[string]any{
-							"error": runErr.Error(),
-						},
-					})
+					_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunFailed, RunFailedPayload{
+						Error: runErr.Error(),
+					}))
 				}
 				_ = tracker.OnRunFailed(ctx, runErr)
 				printer.OnRunFailed(runErr)
 				return runErr
 			}
-
-			streamErr := result.StreamEvents(func(ev agents.StreamEvent) error {
+			onStreamEvent := func(ev agents.StreamEvent) error {
 				if err := tracker.OnStreamEvent(ctx, ev); err != nil {
 					return err
 				}
 				printer.OnStreamEvent(ev)
+				if skipPublishing && artifacts == nil {
+					return nil
+				}
+				payload := serializeStreamEvent(ev)
+				if artifacts != nil {
+					if err := artifacts.storeEvent(ctx, ev, payload); err != nil {
+						return err
+					}
+				}
 				if skipPublishing {
 					return nil
 				}
-				event := CallbackEvent{
-					Type:      "run.event",
-					Timestamp: time.Now().UTC(),
-					Payload:   serializeStreamEvent(ev),
+				return publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunEvent, payload))
+			}
+
+			detachSession := func() {
+				firstStep = false
+				runner.Config.Session = nil
+				runner.Config.LongTermMemory = nil
+			}
+			// A resumed run continues with the session history, which ends
+			// with the approval and input requests, followed by their
+			// responses. A recovered run continues with the input of its
+			// snapshot, whose items not yet in the session are added.
+			resumed := resume != nil
+			switch {
+			case snapshot != nil:
+				history = snapshot.Input
+				if err := saveStepItems(ctx, buildResult.Session, history[len(history)-snapshot.Unsaved:]); err != nil {
+					return fail(err)
+				}
+				detachSession()
+				agent = resumeAgent
+				iteration = max(snapshot.Iteration, 1)
+				routeHops = snapshot.RouteHops
+			case resumed:
+				input, err := sessionHistory(ctx, runner.Config)
+				if err != nil {
+					return fail(err)
+				}
+				if err := saveStepItems(ctx, buildResult.Session, resumeResponses); err != nil {
+					return fail(err)
+				}
+				history = append(input, resumeResponses...)
+				detachSession()
+				agent = resumeAgent
+				iteration = max(resume.Checkpoint.Iteration, 1)
+				routeHops = resume.Checkpoint.RouteHops
+			}
+			for {
+				flow := buildResult.flows[agent]
+				// The fan-out of a resumed agent already ran.
+				if flow != nil && len(flow.fanOut) > 0 && iteration == 1 && !resumed {
+					if firstStep {
+						input, err := conversationInput(ctx, runner.Config, req.Query)
+						if err != nil {
+							return fail(err)
+						}
+						if err := saveStepItems(ctx, buildResult.Session, input[len(input)-1:]); err != nil {
+							return fail(err)
+						}
+						history = input
+						detachSession()
+					}
+					if !skipPublishing {
+						_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunFanOut, RunFanOutPayload{
+							Agent:  flow.agentName,
+							Agents: fanOutNames(flow.fanOut),
+						}))
+					}
+					results, err := runFanOut(ctx, runner, flow.agentName, flow.fanOut, history)
+					if err != nil {
+						return fail(err)
+					}
+					if !skipPublishing {
+						_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunFanIn, RunFanInPayload{
+							Agent:   flow.agentName,
+							Outputs: results,
+						}))
+					}
+					printer.OnRunFanIn(flow.agentName, results)
+					for _, r := range results {
+						handoffs.recordOutput(r.Agent, r.Output)
+					}
+					fanInItems := fanInMessage(results)
+					if err := saveStepItems(ctx, buildResult.Session, fanInItems); err != nil {
+						return fail(err)
+					}
+					history = append(history, fanInItems...)
+				}
+
+				var streamErr error
+				ranWithSession := firstStep
+				runStep := func(ctx context.Context) (err error) {
+					if s.TurnSnapshots {
+						step := &turnStep{tracker: tracker, build: buildResult, iteration: iteration, routeHops: routeHops, saved: len(history)}
+						if firstStep {
+							step.saved = -1
+						}
+						ctx = contextWithTurnStep(ctx, step)
+					}
+					if firstStep {
+						result, err = runner.RunStreamed(ctx, agent, req.Query)
+					} else {
+						result, err = runner.RunInputsStreamed(ctx, agent, history)
+					}
+					if err != nil {
+						return err
+					}
+					streamErr = result.StreamEvents(onStreamEvent)
+					return nil
+				}
+				var err error
+				// Failed steps are run again from their input, as declared
+				// by the retry policy of the agent.
+				for attempt := 1; ; attempt++ {
+					streamErr = nil
+					if flow != nil && flow.loop != nil {
+						err = tracing.CustomSpan(ctx, tracing.CustomSpanParams{
+							Name: "loop_iteration",
+							Data: map[string]any{
+								"agent":          flow.agentName,
+								"iteration":      iteration,
+								"max_iterations": flow.loop.maxIterations,
+							},
+						}, func(ctx context.Context, _ tracing.Span) error { return runStep(ctx) })
+					} else {
+						err = runStep(ctx)
+					}
+					stepErr := cmp.Or(err, streamErr)
+					if flow == nil {
+						break
+					}
+					class := flow.retry.retryClass(attempt, stepErr)
+					if class == "" {
+						break
+					}
+					backoff := flow.retry.retryDelay(attempt, stepErr)
+					if !skipPublishing {
+						_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunRetry, RunRetryPayload{
+							Agent:      flow.agentName,
+							Attempt:    attempt + 1,
+							Error:      stepErr.Error(),
+							ErrorClass: class,
+							BackoffMS:  backoff.Milliseconds(),
+						}))
+					}
+					printer.OnRunRetry(flow.agentName, attempt+1, stepErr)
+					timer := time.NewTimer(backoff)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return fail(errors.Join(stepErr, context.Cause(ctx)))
+					case <-timer.C:
+					}
+				}
+				if err == nil {
+					guardrails := stepGuardrailResults(agent, result, streamErr, s.now())
+					if !skipPublishing {
+						for _, r := range guardrails {
+							_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventGuardrailEvaluated, r))
+						}
+					}
+					if err := tracker.OnGuardrailsEvaluated(ctx, guardrails); err != nil {
+						return fail(err)
+					}
+				}
+				if err != nil {
+					return fail(err)
+				}
+
+				outcome := routeOutcome{output: result.FinalOutput(), iteration: iteration}
+				lastAgent := result.LastAgent()
+				var tripwire agents.OutputGuardrailTripwireTriggeredError
+				switch {
+				case streamErr == nil:
+				case errors.As(streamErr, &tripwire):
+					outcome.guardrail = tripwire.GuardrailResult.Guardrail.Name
+					outcome.output = tripwire.GuardrailResult.AgentOutput
+					lastAgent = tripwire.GuardrailResult.Agent
+				default:
+					return fail(streamErr)
+				}
+				handoffs.recordOutput(buildResult.agentDeclarationName(lastAgent), outcome.output)
+				if !ranWithSession {
+					if err := saveStepItems(ctx, buildResult.Session, runItemsToInput(result.NewItems())); err != nil {
+						return fail(err)
+					}
+				}
+				history = result.ToInputList()
+				detachSession()
+				resumed = false
+
+				// Approval requests without an OnApprovalRequest hook, and
+				// ask_user calls, end the step: the run is suspended until
+				// they are resolved.
+				if streamErr == nil && (hasUnansweredApprovals(result.NewItems()) || hasInputRequests(result.NewItems())) {
+					checkpoint := ExecutionCheckpoint{
+						Agent:          buildResult.agentDeclarationName(lastAgent),
+						Iteration:      iteration,
+						RouteHops:      routeHops,
+						LastResponseID: result.LastResponseID(),
+					}
+					if err := tracker.OnRunSuspended(ctx, checkpoint); err != nil {
+						return fail(err)
+					}
+					summary.Status = tracker.state.Status
+					summary.PendingApprovals = tracker.state.PendingApprovals
+					summary.PendingInputs = tracker.state.PendingInputs
+					summary.NewItems = result.NewItems()
+					summary.LastResponseID = result.LastResponseID()
+					if !skipPublishing {
+						_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunSuspended, RunSuspendedPayload{
+							Agent:            displayAgentName(lastAgent),
+							PendingApprovals: summary.PendingApprovals,
+							PendingInputs:    summary.PendingInputs,
+							LastResponseID:   result.LastResponseID(),
+						}))
+					}
+					printer.OnRunSuspended(summary.PendingApprovals, summary.PendingInputs)
+					notifyApprovals(ctx, s.ApprovalNotifiers, newApprovalNotification(
+						req, displayAgentName(lastAgent), s.ApprovalBaseURL, summary.PendingApprovals, s.now()))
+					return nil
+				}
+
+				loopFlow, err := buildResult.repeatAgent(req, lastAgent, outcome)
+				if err != nil {
+					return fail(err)
+				}
+				if loopFlow != nil {
+					iteration++
+					if !skipPublishing {
+						_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunIteration, RunIterationPayload{
+							Agent:         loopFlow.agentName,
+							Iteration:     iteration,
+							MaxIterations: loopFlow.loop.maxIterations,
+							Guardrail:     outcome.guardrail,
+						}))
+					}
+					printer.OnRunIteration(loopFlow.agentName, iteration, loopFlow.loop.maxIterations)
+					if loopFlow.loop.feedback != "" {
+						feedback := agents.ItemHelpers().InputToNewInputList(agents.InputString(loopFlow.loop.feedback))
+						if err := saveStepItems(ctx, buildResult.Session, feedback); err != nil {
+							return fail(err)
+						}
+						history = append(history, feedback...)
+					}
+					agent = lastAgent
+					continue
+				}
+
+				route, err := buildResult.nextRoute(req, lastAgent, outcome)
+				if err != nil {
+					return fail(err)
 				}
-				return publisher.Publish(ctx, event)
-			})
-			if streamErr != nil {
-				streamErr = wrapRunError(streamErr)
-				summary.Error = streamErr
+				if route == nil {
+					if streamErr != nil {
+						return fail(streamErr)
+					}
+					break
+				}
+				if routeHops >= maxRouteHops {
+					return fail(fmt.Errorf("workflow exceeded %d route hops (last route: agent %q route %d)", maxRouteHops, route.from, route.index))
+				}
+				routeHops++
 				if !skipPublishing {
-					_ = publisher.Publish(ctx, CallbackEvent{
-						Type:      "run.failed",
-						Timestamp: time.Now().UTC(),
-						Payload: map[string]any{
-							"error": streamErr.Error(),
-						},
-					})
+					_ = publisher.Publish(ctx, newCallbackEvent(s.now(), CallbackEventRunRouted, RunRoutedPayload{
+						From:       route.from,
+						To:         displayAgentName(route.next),
+						Route:      route.index,
+						Guardrail:  outcome.guardrail,
+						Iterations: iteration,
+					}))
 				}
-				_ = tracker.OnRunFailed(ctx, streamErr)
-				printer.OnRunFailed(streamErr)
-				return streamErr
+				printer.OnRunRouted(route.from, displayAgentName(route.next))
+				agent = route.next
+				iteration = 1
 			}
 
 			final := result.FinalOutput()
+			summary.Status = ExecutionStatusCompleted
 			summary.FinalOutput = final
 			summary.NewItems = result.NewItems()
 			summary.LastResponseID = result.LastResponseID()
 
-			completeEvent := CallbackEvent{
-				Type:      "run.completed",
-				Timestamp: time.Now().UTC(),
-				Payload: map[string]any{
-					"final_output":     final,
-					"last_response_id": result.LastResponseID(),
-				},
+			completePayload := RunCompletedPayload{
+				FinalOutput:    final,
+				LastResponseID: result.LastResponseID(),
+				Usage:          runUsage.snapshot(),
+				Variants:       buildResult.Variants,
+			}
+			if req.IncludeItems {
+				summary.Items = SerializeRunItems(summary.NewItems)
+				completePayload.Items = summary.Items
 			}
+			if artifacts != nil {
+				completePayload.Artifacts = artifacts.list()
+			}
+			completeEvent := newCallbackEvent(s.now(), CallbackEventRunCompleted, completePayload)
 			if !skipPublishing {
 				_ = publisher.Publish(ctx, completeEvent)
 			}
@@ -196,7 +842,12 @@ func (s *RunnerService) Execute(ctx context.Context, req WorkflowRequest) (*asyn
 			return nil
 		})
 
+		summary.Usage = runUsage.snapshot()
+		if artifacts != nil {
+			summary.Artifacts = artifacts.list()
+		}
 		if traceErr != nil && summary.Error == nil {
+			summary.Status = ExecutionStatusFailed
 			summary.Error = traceErr
 			_ = tracker.OnRunFailed(taskCtx, traceErr)
 			printer.OnRunFailed(traceErr)
@@ -206,6 +857,121 @@ func (s *RunnerService) Execute(ctx context.Context, req WorkflowRequest) (*asyn
 	}), nil
 }
 
+// recordRejection counts a run refused by err before starting.
+func recordRejection(workflowName string, err error) {
+	reason := metrics.RejectionInvalid
+	var (
+		admissionErr *AdmissionError
+		quotaErr     *TenantQuotaError
+	)
+	switch {
+	case errors.As(err, &admissionErr):
+		reason = string(admissionErr.Reason)
+	case errors.As(err, &quotaErr):
+		reason = metrics.RejectionQuota
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		reason = metrics.RejectionCanceled
+	}
+	metrics.GetRecorder().RunRejected(workflowName, reason)
+}
+
+// GetState returns the execution state of the given session, and whether it
+// was found.
+func (s *RunnerService) GetState(ctx context.Context, sessionID string) (WorkflowExecutionState, bool, error) {
+	if s.StateStore == nil {
+		return WorkflowExecutionState{}, false, nil
+	}
+	return s.StateStore.Load(ctx, sessionID)
+}
+
+// ResolveApproval records the decision for a pending approval request of the
+// given session, removing it from the pending approvals. The execution goes
+// back to the running status once no approval is pending; a suspended run is
+// then continued with Resume.
+func (s *RunnerService) ResolveApproval(ctx context.Context, sessionID string, decision ApprovalDecisionState) (WorkflowExecutionState, error) {
+	if decision.ResolvedAt.IsZero() {
+		decision.ResolvedAt = s.now()
+	}
+	for attempt := 1; ; attempt++ {
+		state, ok, err := s.GetState(ctx, sessionID)
+		if err != nil {
+			return WorkflowExecutionState{}, err
+		}
+		if !ok {
+			return WorkflowExecutionState{}, fmt.Errorf("%w: session %q", ErrExecutionNotFound, sessionID)
+		}
+
+		index := slices.IndexFunc(state.PendingApprovals, func(req ApprovalRequestState) bool {
+			return req.RequestID == decision.RequestID
+		})
+		if index < 0 {
+			return WorkflowExecutionState{}, fmt.Errorf("%w: request %q", ErrApprovalNotFound, decision.RequestID)
+		}
+		state.PendingApprovals = slices.Delete(state.PendingApprovals, index, index+1)
+		state.ResolvedApprovals = append(state.ResolvedApprovals, decision)
+		state.Status = state.waitingStatus()
+		state.UpdatedAt = s.now()
+		err = s.StateStore.Save(ctx, state)
+		if errors.Is(err, ErrExecutionStateConflict) && attempt < maxStateSaveAttempts {
+			continue
+		}
+		if err != nil {
+			return WorkflowExecutionState{}, err
+		}
+		state.Version++
+		return state, nil
+	}
+}
+
+// hasUnansweredApprovals reports whether the items of a step hold approval
+// requests without a response.
+func hasUnansweredApprovals(items []agents.RunItem) bool {
+	answered := make(map[string]bool)
+	for _, item := range items {
+		if response, ok := item.(agents.MCPApprovalResponseItem); ok {
+			answered[response.RawItem.ApprovalRequestID] = true
+		}
+	}
+	for _, item := range items {
+		if request, ok := item.(agents.MCPApprovalRequestItem); ok && !answered[request.RawItem.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalResponseItems converts the approval decisions to the input items
+// answering the approval requests.
+func approvalResponseItems(decisions []ApprovalDecisionState) []agents.TResponseInputItem {
+	items := make([]agents.TResponseInputItem, len(decisions))
+	for i, decision := range decisions {
+		items[i] = responses.ResponseInputItemParamOfMcpApprovalResponse(decision.RequestID, decision.Approve)
+		if !decision.Approve && decision.Reason != "" {
+			items[i].OfMcpApprovalResponse.Reason = param.NewOpt(decision.Reason)
+		}
+	}
+	return items
+}
+
+// saveStepItems adds the items of a workflow step to the session.
+func saveStepItems(ctx context.Context, session memory.Session, items []agents.TResponseInputItem) error {
+	if session == nil || len(items) == 0 {
+		return nil
+	}
+	if err := session.AddItems(ctx, items); err != nil {
+		return fmt.Errorf("save step items to session: %w", err)
+	}
+	return nil
+}
+
+func runItemsToInput(items []agents.RunItem) []agents.TResponseInputItem {
+	inputItems := make([]agents.TResponseInputItem, len(items))
+	for i, item := range items {
+		inputItems[i] = item.ToInputItem()
+	}
+	return inputItems
+}
+
 func wrapRunError(err error) error {
 	var agentsErr *agents.AgentsError
 	if errors.As(err, &agentsErr) && agentsErr.RunData != nil {
@@ -216,17 +982,21 @@ func wrapRunError(err error) error {
 	return err
 }
 
-func serializeStreamEvent(event agents.StreamEvent) map[string]any {
+func serializeStreamEvent(event agents.StreamEvent) RunEventPayload {
 	switch ev := event.(type) {
 	case agents.RawResponsesStreamEvent:
-		payload := map[string]any{
-			"event_kind": "raw",
-			"type":       ev.Data.Type,
+		payload := &RawEventPayload{
+			EventKind: RunEventKindRaw,
+			Type:      ev.Data.Type,
 		}
-		if raw, err := json.Marshal(ev.Data); err == nil {
-			payload["data"] = json.RawMessage(raw)
+		// Events decoded from the provider keep their JSON, which is much
+		// smaller and cheaper than marshaling all the fields of the union.
+		if raw := ev.Data.RawJSON(); raw != "" {
+			payload.Data = json.RawMessage(raw)
+		} else if raw, err := json.Marshal(ev.Data); err == nil {
+			payload.Data = raw
 		} else {
-			payload["marshal_error"] = err.Error()
+			payload.MarshalError = err.Error()
 		}
 		return payload
 	case agents.AgentUpdatedStreamEvent:
@@ -234,32 +1004,85 @@ func serializeStreamEvent(event agents.StreamEvent) map[string]any {
 		if ev.NewAgent != nil {
 			agentName = ev.NewAgent.Name
 		}
-		return map[string]any{
-			"event_kind": "agent_updated",
-			"agent_name": agentName,
+		return &AgentUpdatedEventPayload{
+			EventKind: RunEventKindAgentUpdated,
+			AgentName: agentName,
+		}
+	case agents.ReasoningSummaryDeltaStreamEvent:
+		return &ReasoningSummaryDeltaEventPayload{
+			EventKind:    RunEventKindReasoningSummaryDelta,
+			Agent:        displayAgentName(ev.Agent),
+			ItemID:       ev.ItemID,
+			SummaryIndex: ev.SummaryIndex,
+			Delta:        ev.Delta,
+		}
+	case agents.ImageGenerationPartialImageStreamEvent:
+		return &ImageGenerationPartialImageEventPayload{
+			EventKind:         RunEventKindImageGenerationPartialImage,
+			Agent:             displayAgentName(ev.Agent),
+			ItemID:            ev.ItemID,
+			PartialImageIndex: ev.PartialImageIndex,
+			PartialImageB64:   ev.PartialImageB64,
 		}
 	case agents.RunItemStreamEvent:
-		return map[string]any{
-			"event_kind": "run_item",
-			"name":       string(ev.Name),
-			"item":       summarizeRunItem(ev.Item),
+		return &RunItemEventPayload{
+			EventKind: RunEventKindRunItem,
+			Name:      string(ev.Name),
+			Item:      summarizeRunItem(ev.Item),
 		}
 	default:
-		return map[string]any{
-			"event_kind": "unknown",
-			"type":       fmt.Sprintf("%T", event),
+		return &UnknownEventPayload{
+			EventKind: RunEventKindUnknown,
+			Type:      fmt.Sprintf("%T", event),
 		}
 	}
 }
 
+// RunItemsSchemaVersion is the version of the JSON serialization of the run
+// items made by SerializeRunItems. It changes when fields are removed or
+// change meaning; fields may be added within a version.
+const RunItemsSchemaVersion = 1
+
+// SerializedRunItems is the JSON serialization of the items generated by a
+// run, as included in RunSummary.Items and the run.completed callbacks.
+type SerializedRunItems struct {
+	SchemaVersion int `json:"schema_version"`
+	// Items have the same fields as the items of the run_item stream
+	// events, starting with their type and agent.
+	Items []map[string]any `json:"items"`
+}
+
+// SerializeRunItems returns the JSON serialization of the given run items.
+func SerializeRunItems(items []agents.RunItem) *SerializedRunItems {
+	serialized := &SerializedRunItems{
+		SchemaVersion: RunItemsSchemaVersion,
+		Items:         make([]map[string]any, len(items)),
+	}
+	for i, item := range items {
+		serialized.Items[i] = summarizeRunItem(item)
+	}
+	return serialized
+}
+
 func summarizeRunItem(item agents.RunItem) map[string]any {
 	switch v := item.(type) {
 	case agents.MessageOutputItem:
-		return map[string]any{
+		payload := map[string]any{
 			"type":  v.Type,
 			"agent": displayAgentName(v.Agent),
 			"text":  agents.ItemHelpers().TextMessageOutput(v),
 		}
+		if logprobs := agents.ItemHelpers().TextMessageLogprobs(v); len(logprobs) > 0 {
+			tokens := make([]map[string]any, len(logprobs))
+			for i, lp := range logprobs {
+				tokens[i] = map[string]any{"token": lp.Token, "logprob": lp.Logprob}
+			}
+			payload["logprobs"] = tokens
+		}
+		if len(v.Annotations) > 0 {
+			payload["annotations"] = v.Annotations
+		}
+		return payload
 	case agents.ToolCallItem:
 		payload := map[string]any{
 			"type":      v.Type,
@@ -273,6 +1096,10 @@ func summarizeRunItem(item agents.RunItem) map[string]any {
 			payload["web_search_status"] = raw.Status
 		case agents.ResponseFileSearchToolCall:
 			payload["file_search_status"] = raw.Status
+			payload["file_search_queries"] = raw.Queries
+			if results := agents.ItemHelpers().FileSearchResults(v); len(results) > 0 {
+				payload["file_search_results"] = results
+			}
 		}
 		return payload
 	case agents.ToolCallOutputItem:
@@ -282,12 +1109,40 @@ func summarizeRunItem(item agents.RunItem) map[string]any {
 			"output": v.Output,
 		}
 	case agents.HandoffOutputItem:
-		return map[string]any{
+		payload := map[string]any{
 			"type":         v.Type,
 			"agent":        displayAgentName(v.Agent),
 			"source_agent": displayAgentName(v.SourceAgent),
 			"target_agent": displayAgentName(v.TargetAgent),
 		}
+		if d := v.Details; d != nil {
+			payload["arguments"] = d.ToolCall.Arguments
+			payload["started_at"] = d.StartedAt
+			payload["duration_ms"] = d.Duration.Milliseconds()
+			payload["input_items"] = len(d.Input)
+		}
+		return payload
+	case agents.ReasoningItem:
+		summary := make([]string, len(v.RawItem.Summary))
+		for i, part := range v.RawItem.Summary {
+			summary[i] = part.Text
+		}
+		return map[string]any{
+			"type":    v.Type,
+			"agent":   displayAgentName(v.Agent),
+			"summary": summary,
+		}
+	case agents.OutputValidationFeedbackItem:
+		payload := map[string]any{
+			"type":  v.Type,
+			"agent": displayAgentName(v.Agent),
+			"error": v.Error.Error(),
+		}
+		var validationErr *agents.JSONValidationError
+		if errors.As(v.Error, &validationErr) {
+			payload["violations"] = validationErr.Violations
+		}
+		return payload
 	default:
 		return map[string]any{
 			"type": fmt.Sprintf("%T", item),
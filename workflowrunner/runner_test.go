@@ -0,0 +1,213 @@
+package workflowrunner
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModelProvider serves model for any name.
+type testModelProvider struct {
+	model agents.Model
+}
+
+func (p testModelProvider) GetModel(string) (agents.Model, error) { return p.model, nil }
+
+// newTestService returns a service running the agents declaring the "test"
+// provider with model, and publishing the events to publisher.
+// newTestBuilder returns the default builder storing its SQLite sessions in
+// a temporary directory instead of the package directory.
+func newTestBuilder(t *testing.T) *Builder {
+	builder := NewDefaultBuilder()
+	builder.SessionFactory = NewSQLiteSessionFactory(t.TempDir())
+	builder.SessionFactories["sqlite"] = builder.SessionFactory
+	return builder
+}
+
+func newTestService(t *testing.T, model agents.Model, publisher CallbackPublisher) *RunnerService {
+	builder := newTestBuilder(t)
+	builder.ModelProviderFactories["test"] = func(context.Context, ModelDeclaration) (agents.ModelProvider, error) {
+		return testModelProvider{model: model}, nil
+	}
+	service := NewRunnerService(builder)
+	service.CallbackFactory = func(context.Context, CallbackDeclaration) (CallbackPublisher, error) {
+		return publisher, nil
+	}
+	return service
+}
+
+// testRequest returns a request running a single agent with the model of
+// the "test" provider.
+func testRequest(sessionID string) WorkflowRequest {
+	return WorkflowRequest{
+		Query: "hello",
+		Session: SessionDeclaration{
+			SessionID:   sessionID,
+			Credentials: CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+		},
+		Callback: CallbackDeclaration{Target: "https://example.com/hook"},
+		Workflow: WorkflowDeclaration{
+			Name:          "wf",
+			StartingAgent: "assistant",
+			Agents: []AgentDeclaration{{
+				Name:         "assistant",
+				Instructions: "Help.",
+				Model:        &ModelDeclaration{Provider: "test", Model: "fake"},
+			}},
+		},
+	}
+}
+
+// gatedModel answers each call with a text message once it receives from
+// release, counting the calls going on at the same time.
+type gatedModel struct {
+	release chan struct{}
+	called  chan struct{}
+
+	mu           sync.Mutex
+	active, peak int
+}
+
+func newGatedModel() *gatedModel {
+	return &gatedModel{release: make(chan struct{}), called: make(chan struct{}, 16)}
+}
+
+func (m *gatedModel) GetResponse(ctx context.Context, params agents.ModelResponseParams) (*agents.ModelResponse, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+	return &agents.ModelResponse{Output: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}}, nil
+}
+
+func (m *gatedModel) StreamResponse(ctx context.Context, params agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+	return yield(ctx, agents.TResponseStreamEvent{
+		Response: agentstesting.GetResponseObj([]agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}, "", nil),
+		Type:     "response.completed",
+	})
+}
+
+func (m *gatedModel) wait(ctx context.Context) error {
+	m.mu.Lock()
+	m.active++
+	m.peak = max(m.peak, m.active)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.active--
+		m.mu.Unlock()
+	}()
+	m.called <- struct{}{}
+	select {
+	case <-m.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// awaitCall waits for a call of the model to start.
+func (m *gatedModel) awaitCall(t *testing.T) {
+	t.Helper()
+	receive(t, m.called)
+}
+
+// maxConcurrentCalls returns the largest number of calls going on at the
+// same time.
+func (m *gatedModel) maxConcurrentCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peak
+}
+
+// receive returns the next value of ch, failing the test after a while.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out")
+		panic("unreachable")
+	}
+}
+
+func TestApprovalSuspendResume(t *testing.T) {
+	ctx := t.Context()
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{{
+			ID:          "mcpr_1",
+			Type:        "mcp_approval_request",
+			ServerLabel: "crm",
+			Name:        "delete_contact",
+			Arguments:   `{"id": 7}`,
+		}},
+	})
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("deleted")},
+	})
+	publisher := &recordingPublisher{}
+	service := newTestService(t, model, publisher)
+	req := testRequest("s1")
+	req.Workflow.Agents[0].MCPServers = []MCPDeclaration{{
+		ServerLabel:     "crm",
+		Address:         "https://mcp.example.com",
+		RequireApproval: "always",
+	}}
+
+	task, err := service.Execute(ctx, req)
+	require.NoError(t, err)
+	result := task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusWaitingApproval, result.Value.Status)
+	require.Len(t, result.Value.PendingApprovals, 1)
+	pending := result.Value.PendingApprovals[0]
+	assert.Equal(t, "mcpr_1", pending.RequestID)
+	assert.Equal(t, "crm", pending.ServerLabel)
+	assert.Equal(t, "delete_contact", pending.ToolName)
+
+	// The run is resumed once all the requests are resolved.
+	_, err = service.Resume(ctx, req)
+	require.Error(t, err)
+	state, err := service.ResolveApproval(ctx, "s1", ApprovalDecisionState{RequestID: "mcpr_1", Approve: true})
+	require.NoError(t, err)
+	assert.Empty(t, state.PendingApprovals)
+
+	task, err = service.Resume(ctx, req)
+	require.NoError(t, err)
+	result = task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusCompleted, result.Value.Status)
+	assert.Equal(t, "deleted", result.Value.FinalOutput)
+
+	// The model sees the approval in the conversation.
+	input := model.LastTurnArgs.Input.(agents.InputItems)
+	last := input[len(input)-1].OfMcpApprovalResponse
+	require.NotNil(t, last)
+	assert.Equal(t, "mcpr_1", last.ApprovalRequestID)
+	assert.True(t, last.Approve)
+
+	state, ok, err := service.GetState(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, ExecutionStatusCompleted, state.Status)
+	assert.Nil(t, state.Checkpoint)
+	assert.Equal(t, []string{
+		CallbackEventRunStarted, CallbackEventRunSuspended,
+		CallbackEventRunResumed, CallbackEventRunCompleted,
+	}, slices.DeleteFunc(publisher.published(), func(typ string) bool {
+		return typ == CallbackEventRunEvent || typ == CallbackEventGuardrailEvaluated
+	}))
+
+	_, err = service.Resume(ctx, req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,346 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy decides what happens when a scheduled run is due while the
+// previous run of the schedule is still going.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the due run.
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue starts the due run once the previous runs are over.
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapCancel cancels the previous run and starts the due one.
+	OverlapCancel OverlapPolicy = "cancel"
+)
+
+// Schedule submits a workflow request on a cron schedule.
+type Schedule struct {
+	// Name identifies the schedule in the Scheduler.
+	Name string `json:"name"`
+	// Cron expression, with the five standard fields ("*/15 * * * *"), or a
+	// descriptor such as "@hourly" or "@every 10m".
+	Cron string `json:"cron"`
+	// Timezone of the cron expression, e.g. "Europe/Rome"; defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Overlap policy, skip by default.
+	Overlap OverlapPolicy `json:"overlap,omitempty" jsonschema:"enum=skip,enum=queue,enum=cancel"`
+	// JitterMS is the maximum random delay of each run, spreading the runs
+	// of schedules sharing the same expression.
+	JitterMS int `json:"jitter_ms,omitempty" jsonschema:"minimum=0"`
+	// UniqueSessions appends the scheduled time to the session ID of each
+	// run, so that runs neither share their history nor their state.
+	UniqueSessions bool            `json:"unique_sessions,omitempty"`
+	Request        WorkflowRequest `json:"request"`
+}
+
+// ScheduledRun is the outcome of a scheduled run, see Scheduler.OnRunFinished.
+type ScheduledRun struct {
+	Schedule    string
+	ScheduledAt time.Time
+	Summary     RunSummary
+	// Err is the error of the run, or of its submission.
+	Err error
+}
+
+// Scheduler submits the runs of its schedules to a RunnerService.
+type Scheduler struct {
+	Service *RunnerService
+	// OnRunFinished, when set, is called after each scheduled run, including
+	// the ones which could not be submitted.
+	OnRunFinished func(run ScheduledRun)
+	// OnRunSkipped, when set, is called for the runs dropped by the skip
+	// overlap policy.
+	OnRunSkipped func(schedule string, scheduledAt time.Time)
+
+	mu        sync.Mutex
+	schedules map[string]*scheduleEntry
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+type scheduleEntry struct {
+	schedule Schedule
+	cron     cron.Schedule
+	stop     context.CancelFunc
+
+	mu      sync.Mutex
+	running *asynctask.Task[RunSummary]
+	// queued runs, by scheduled time, for the queue overlap policy.
+	queued []time.Time
+	// idle is closed when no run is going.
+	idle chan struct{}
+}
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// NewScheduler returns a Scheduler submitting runs to the given service.
+func NewScheduler(service *RunnerService) *Scheduler {
+	return &Scheduler{Service: service, schedules: make(map[string]*scheduleEntry)}
+}
+
+// ValidateSchedule checks the schedule and its workflow request.
+func ValidateSchedule(schedule Schedule) error {
+	if strings.TrimSpace(schedule.Name) == "" {
+		return errors.New("schedule name is required")
+	}
+	if _, err := parseSchedule(schedule); err != nil {
+		return err
+	}
+	switch schedule.Overlap {
+	case "", OverlapSkip, OverlapQueue, OverlapCancel:
+	default:
+		return fmt.Errorf("schedule %q: unsupported overlap policy %q", schedule.Name, schedule.Overlap)
+	}
+	if schedule.JitterMS < 0 {
+		return fmt.Errorf("schedule %q: jitter_ms must be non-negative", schedule.Name)
+	}
+	if err := ValidateWorkflowRequest(schedule.Request); err != nil {
+		return fmt.Errorf("schedule %q: %w", schedule.Name, err)
+	}
+	return nil
+}
+
+func parseSchedule(schedule Schedule) (cron.Schedule, error) {
+	spec := strings.TrimSpace(schedule.Cron)
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, fmt.Errorf("schedule %q: invalid timezone: %w", schedule.Name, err)
+		}
+		spec = "CRON_TZ=" + schedule.Timezone + " " + spec
+	}
+	sched, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %q: invalid cron expression: %w", schedule.Name, err)
+	}
+	return sched, nil
+}
+
+// Add registers a schedule, replacing the one with the same name. Schedules
+// added to a started scheduler are triggered right away.
+func (s *Scheduler) Add(schedule Schedule) error {
+	if err := ValidateSchedule(schedule); err != nil {
+		return err
+	}
+	sched, err := parseSchedule(schedule)
+	if err != nil {
+		return err
+	}
+	entry := &scheduleEntry{schedule: schedule, cron: sched, idle: closedChan()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.schedules[schedule.Name]; ok && previous.stop != nil {
+		previous.stop()
+	}
+	s.schedules[schedule.Name] = entry
+	if s.ctx != nil {
+		s.startEntry(entry)
+	}
+	return nil
+}
+
+// Remove unregisters a schedule, reporting whether it existed. Its ongoing
+// run is not canceled.
+func (s *Scheduler) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.schedules[name]
+	if !ok {
+		return false
+	}
+	if entry.stop != nil {
+		entry.stop()
+	}
+	delete(s.schedules, name)
+	return true
+}
+
+// Schedules returns the registered schedules.
+func (s *Scheduler) Schedules() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := make([]Schedule, 0, len(s.schedules))
+	for _, entry := range s.schedules {
+		schedules = append(schedules, entry.schedule)
+	}
+	return schedules
+}
+
+// Start triggers the schedules until ctx is done or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if s.Service == nil {
+		return errors.New("Scheduler missing Service")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return errors.New("scheduler already started")
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, entry := range s.schedules {
+		s.startEntry(entry)
+	}
+	return nil
+}
+
+// Stop stops triggering the schedules, cancels the ongoing runs and waits
+// for them to finish.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	s.mu.Lock()
+	s.ctx, s.cancel = nil, nil
+	s.mu.Unlock()
+}
+
+func (s *Scheduler) startEntry(entry *scheduleEntry) {
+	schedulerCtx := s.ctx
+	ctx, stop := context.WithCancel(schedulerCtx)
+	entry.stop = stop
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(schedulerCtx, ctx, entry)
+	}()
+}
+
+// loop triggers the runs of a schedule until ctx is done.
+func (s *Scheduler) loop(schedulerCtx, ctx context.Context, entry *scheduleEntry) {
+	for {
+		now := s.Service.now()
+		scheduledAt := entry.cron.Next(now)
+		if scheduledAt.IsZero() {
+			return
+		}
+		delay := scheduledAt.Sub(now)
+		if entry.schedule.JitterMS > 0 {
+			delay += time.Duration(rand.Int64N(int64(entry.schedule.JitterMS)+1)) * time.Millisecond
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			// Ongoing runs are canceled with the scheduler, not with the
+			// removal of their schedule.
+			if schedulerCtx.Err() != nil {
+				entry.cancelRunning()
+			}
+			<-entry.waitIdle()
+			return
+		case <-timer.C:
+		}
+		s.trigger(ctx, entry, scheduledAt)
+	}
+}
+
+func (s *Scheduler) trigger(ctx context.Context, entry *scheduleEntry, scheduledAt time.Time) {
+	entry.mu.Lock()
+	if entry.running != nil {
+		switch entry.schedule.Overlap {
+		case OverlapQueue:
+			entry.queued = append(entry.queued, scheduledAt)
+			entry.mu.Unlock()
+			return
+		case OverlapCancel:
+			running := entry.running
+			entry.mu.Unlock()
+			running.Cancel()
+			<-entry.waitIdle()
+			entry.mu.Lock()
+		default:
+			entry.mu.Unlock()
+			if s.OnRunSkipped != nil {
+				s.OnRunSkipped(entry.schedule.Name, scheduledAt)
+			}
+			return
+		}
+	}
+	s.submit(ctx, entry, scheduledAt)
+	entry.mu.Unlock()
+}
+
+// submit starts a run of the schedule, with entry.mu held, and reports
+// whether it started.
+func (s *Scheduler) submit(ctx context.Context, entry *scheduleEntry, scheduledAt time.Time) bool {
+	req := entry.schedule.Request
+	if entry.schedule.UniqueSessions {
+		req.Session.SessionID = fmt.Sprintf("%s:%s", req.Session.SessionID, scheduledAt.UTC().Format(time.RFC3339))
+	}
+	// Runs outlive the removal of their schedule, but not the scheduler.
+	task, err := s.Service.Execute(context.WithoutCancel(ctx), req)
+	if err != nil {
+		s.finished(ScheduledRun{Schedule: entry.schedule.Name, ScheduledAt: scheduledAt, Err: err})
+		return false
+	}
+	entry.running = task
+	entry.idle = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		result := task.Await()
+		s.finished(ScheduledRun{
+			Schedule:    entry.schedule.Name,
+			ScheduledAt: scheduledAt,
+			Summary:     result.Value,
+			Err:         result.Error,
+		})
+
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		entry.running = nil
+		close(entry.idle)
+		for len(entry.queued) > 0 && ctx.Err() == nil {
+			next := entry.queued[0]
+			entry.queued = entry.queued[1:]
+			if s.submit(ctx, entry, next) {
+				break
+			}
+		}
+	}()
+	return true
+}
+
+func (s *Scheduler) finished(run ScheduledRun) {
+	if s.OnRunFinished != nil {
+		s.OnRunFinished(run)
+	}
+}
+
+func (e *scheduleEntry) cancelRunning() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queued = nil
+	if e.running != nil {
+		e.running.Cancel()
+	}
+}
+
+func (e *scheduleEntry) waitIdle() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.idle
+}
+
+func closedChan() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
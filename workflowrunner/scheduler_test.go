@@ -0,0 +1,75 @@
+package workflowrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerOverlap(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2026, 1, 1, 0, minute, 0, 0, time.UTC) }
+	setup := func(t *testing.T, overlap OverlapPolicy) (*Scheduler, *scheduleEntry, *gatedModel, chan ScheduledRun, chan time.Time) {
+		model := newGatedModel()
+		scheduler := NewScheduler(newTestService(t, model, &recordingPublisher{}))
+		finished := make(chan ScheduledRun, 8)
+		skipped := make(chan time.Time, 8)
+		scheduler.OnRunFinished = func(run ScheduledRun) { finished <- run }
+		scheduler.OnRunSkipped = func(_ string, scheduledAt time.Time) { skipped <- scheduledAt }
+		entry := &scheduleEntry{
+			schedule: Schedule{Name: "nightly", Overlap: overlap, UniqueSessions: true, Request: testRequest("s1")},
+			idle:     closedChan(),
+		}
+		t.Cleanup(scheduler.wg.Wait)
+		return scheduler, entry, model, finished, skipped
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		scheduler, entry, model, finished, skipped := setup(t, OverlapSkip)
+		scheduler.trigger(t.Context(), entry, at(0))
+		model.awaitCall(t)
+		scheduler.trigger(t.Context(), entry, at(1))
+		assert.Equal(t, at(1), receive(t, skipped))
+
+		model.release <- struct{}{}
+		run := receive(t, finished)
+		require.NoError(t, run.Err)
+		assert.Equal(t, at(0), run.ScheduledAt)
+		assert.Equal(t, ExecutionStatusCompleted, run.Summary.Status)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		scheduler, entry, model, finished, skipped := setup(t, OverlapQueue)
+		for minute := range 3 {
+			scheduler.trigger(t.Context(), entry, at(minute))
+		}
+		for minute := range 3 {
+			model.awaitCall(t)
+			model.release <- struct{}{}
+			run := receive(t, finished)
+			require.NoError(t, run.Err)
+			assert.Equal(t, at(minute), run.ScheduledAt)
+			assert.Equal(t, "s1:"+at(minute).Format(time.RFC3339), run.Summary.SessionID)
+		}
+		assert.Equal(t, 1, model.maxConcurrentCalls())
+		assert.Empty(t, skipped)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		scheduler, entry, model, finished, skipped := setup(t, OverlapCancel)
+		scheduler.trigger(t.Context(), entry, at(0))
+		model.awaitCall(t)
+		scheduler.trigger(t.Context(), entry, at(1))
+		run := receive(t, finished)
+		assert.Equal(t, at(0), run.ScheduledAt)
+		assert.Error(t, run.Err)
+
+		model.awaitCall(t)
+		model.release <- struct{}{}
+		run = receive(t, finished)
+		require.NoError(t, run.Err)
+		assert.Equal(t, at(1), run.ScheduledAt)
+		assert.Empty(t, skipped)
+	})
+}
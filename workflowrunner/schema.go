@@ -0,0 +1,291 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:generate go run ./internal/genschema
+
+// WorkflowRequestSchema returns the JSON Schema of a WorkflowRequest manifest,
+// generated from the Go types. It can be used by editors for autocompletion,
+// or to validate manifests outside Go.
+func WorkflowRequestSchema() *jsonschema.Schema {
+	return newSchemaReflector().Reflect(&WorkflowRequest{})
+}
+
+// WorkflowDeclarationSchema returns the JSON Schema of a WorkflowDeclaration.
+func WorkflowDeclarationSchema() *jsonschema.Schema {
+	return newSchemaReflector().Reflect(&WorkflowDeclaration{})
+}
+
+func newSchemaReflector() *jsonschema.Reflector {
+	return &jsonschema.Reflector{
+		RequiredFromJSONSchemaTags: false,
+		AllowAdditionalProperties:  false,
+	}
+}
+
+// JSONSchemaExtend requires either the query or the batch of the request.
+func (WorkflowRequest) JSONSchemaExtend(schema *jsonschema.Schema) {
+	schema.OneOf = []*jsonschema.Schema{
+		{Required: []string{"query"}},
+		{Required: []string{"batch"}},
+	}
+}
+
+// JSONSchema describes a callback object. v1 manifests could also provide the
+// target URL as a plain string.
+func (CallbackDeclaration) JSONSchema() *jsonschema.Schema {
+	props := jsonschema.NewProperties()
+	props.Set("target", &jsonschema.Schema{Type: "string"})
+	props.Set("mode", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{"", "http", "stdout", "stdout_verbose", CallbackModeTTY, CallbackModeStream, CallbackModeNATS},
+	})
+	props.Set("signing_secret", &jsonschema.Schema{Type: "string"})
+	retryProps := jsonschema.NewProperties()
+	for _, name := range []string{"max_attempts", "initial_backoff_ms", "max_backoff_ms"} {
+		retryProps.Set(name, &jsonschema.Schema{Type: "integer", Minimum: json.Number("0")})
+	}
+	retryProps.Set("backoff_factor", &jsonschema.Schema{Type: "number", Minimum: json.Number("0")})
+	props.Set("retry", &jsonschema.Schema{Type: "object", Properties: retryProps, AdditionalProperties: jsonschema.FalseSchema})
+	props.Set("queue_size", &jsonschema.Schema{Type: "integer", Minimum: json.Number("0")})
+	return &jsonschema.Schema{Type: "object", Properties: props, AdditionalProperties: jsonschema.FalseSchema}
+}
+
+// ManifestError is an issue found in a workflow manifest.
+type ManifestError struct {
+	// JSON path of the invalid value, e.g. "$.workflow.agents[0].name".
+	Path    string
+	Message string
+}
+
+func (e ManifestError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ManifestErrors lists all the issues found in a workflow manifest.
+type ManifestErrors []ManifestError
+
+func (e ManifestErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+var (
+	compiledRequestSchema     *gojsonschema.Schema
+	compiledRequestSchemaErr  error
+	compiledRequestSchemaOnce sync.Once
+)
+
+func requestSchemaValidator() (*gojsonschema.Schema, error) {
+	compiledRequestSchemaOnce.Do(func() {
+		compiledRequestSchema, compiledRequestSchemaErr = gojsonschema.NewSchema(
+			gojsonschema.NewGoLoader(WorkflowRequestSchema()))
+	})
+	return compiledRequestSchema, compiledRequestSchemaErr
+}
+
+// ValidateManifestBytes validates a JSON-encoded WorkflowRequest against its
+// JSON Schema and the cross-references between agents. Unlike
+// ValidateWorkflowRequest, it reports all the issues found, as ManifestErrors.
+//
+// Manifests of older versions are migrated first, so paths refer to the
+// current version of the manifest.
+func ValidateManifestBytes(data []byte) error {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ManifestErrors{{Path: "$", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	if _, isObject := raw.(map[string]any); isObject {
+		migrated, err := MigrateManifestBytes(data)
+		if err != nil {
+			return ManifestErrors{{Path: "$.version", Message: err.Error()}}
+		}
+		data = migrated
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return ManifestErrors{{Path: "$", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+		}
+	}
+
+	schema, err := requestSchemaValidator()
+	if err != nil {
+		return fmt.Errorf("compile manifest schema: %w", err)
+	}
+	result, err := schema.Validate(gojsonschema.NewGoLoader(raw))
+	if err != nil {
+		return fmt.Errorf("validate manifest: %w", err)
+	}
+	var errs ManifestErrors
+	for _, resultErr := range result.Errors() {
+		errs = append(errs, schemaManifestError(resultErr))
+	}
+	slices.SortStableFunc(errs, func(a, b ManifestError) int { return strings.Compare(a.Path, b.Path) })
+
+	var req WorkflowRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		// Type mismatches are already reported by the schema.
+		if len(errs) == 0 {
+			errs = append(errs, ManifestError{Path: "$", Message: err.Error()})
+		}
+		return errs
+	}
+	for _, refErr := range validateManifestReferences(req) {
+		// Skip values already reported as invalid by the schema.
+		reported := slices.ContainsFunc(errs, func(e ManifestError) bool {
+			return strings.HasPrefix(e.Path, refErr.Path)
+		})
+		if !reported {
+			errs = append(errs, refErr)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	// Catch any remaining rule not expressed by the schema.
+	if err := ValidateWorkflowRequest(req); err != nil {
+		return ManifestErrors{{Path: "$", Message: err.Error()}}
+	}
+	return nil
+}
+
+func schemaManifestError(err gojsonschema.ResultError) ManifestError {
+	path := jsonPath(strings.Split(err.Context().String(), ".")[1:]...)
+	message := err.Description()
+	switch err.Type() {
+	case "required", "additional_property_not_allowed":
+		if property, ok := err.Details()["property"].(string); ok {
+			path += jsonPath(property)[1:]
+			if err.Type() == "required" {
+				message = "is required"
+			} else {
+				message = "is not allowed"
+			}
+		}
+	}
+	return ManifestError{Path: path, Message: message}
+}
+
+// jsonPath builds a JSON path from keys and array indices.
+func jsonPath(segments ...string) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			sb.WriteString("[" + segment + "]")
+		} else {
+			sb.WriteString("." + segment)
+		}
+	}
+	return sb.String()
+}
+
+// validateManifestReferences checks the rules not expressed by the schema,
+// such as references between agents.
+func validateManifestReferences(req WorkflowRequest) ManifestErrors {
+	var errs ManifestErrors
+	add := func(path, format string, args ...any) {
+		errs = append(errs, ManifestError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if err := req.Callback.Validate(); err != nil {
+		add("$.callback", "%s", err.Error())
+	}
+	if fork := req.Session.ForkFrom; fork != nil && fork.SessionID == req.Session.SessionID {
+		add("$.session.fork_from.session_id", "must differ from session_id")
+	}
+
+	workflow := req.Workflow
+	seen := make(map[string]struct{}, len(workflow.Agents))
+	for i, agent := range workflow.Agents {
+		if _, dup := seen[agent.Name]; dup {
+			add(fmt.Sprintf("$.workflow.agents[%d].name", i), "duplicate agent name %q", agent.Name)
+		}
+		seen[agent.Name] = struct{}{}
+	}
+	if _, ok := seen[workflow.StartingAgent]; !ok && workflow.StartingAgent != "" {
+		add("$.workflow.starting_agent", "agent %q not found", workflow.StartingAgent)
+	}
+	for i, agent := range workflow.Agents {
+		for j, h := range agent.Handoffs {
+			if _, ok := seen[h]; !ok {
+				add(fmt.Sprintf("$.workflow.agents[%d].handoffs[%d]", i, j), "agent %q not found", h)
+			}
+		}
+		for j, cond := range agent.HandoffConditions {
+			if !slices.Contains(agent.Handoffs, cond.Agent) {
+				add(fmt.Sprintf("$.workflow.agents[%d].handoff_conditions[%d].agent", i, j), "agent %q is not one of the handoffs", cond.Agent)
+			}
+			if cond.Expression != "" {
+				if _, err := parseRouteExpression(cond.Expression); err != nil {
+					add(fmt.Sprintf("$.workflow.agents[%d].handoff_conditions[%d].expression", i, j), "%s", err.Error())
+				}
+			}
+		}
+		for j, tool := range agent.AgentTools {
+			if _, ok := seen[tool.AgentName]; !ok {
+				add(fmt.Sprintf("$.workflow.agents[%d].agent_tools[%d].agent_name", i, j), "agent %q not found", tool.AgentName)
+			}
+		}
+		for j, route := range agent.Routes {
+			if _, ok := seen[route.Next]; !ok {
+				add(fmt.Sprintf("$.workflow.agents[%d].routes[%d].next", i, j), "agent %q not found", route.Next)
+			}
+			if route.When.Expression != "" {
+				if _, err := parseRouteExpression(route.When.Expression); err != nil {
+					add(fmt.Sprintf("$.workflow.agents[%d].routes[%d].when.expression", i, j), "%s", err.Error())
+				}
+			}
+		}
+		if agent.FanOut != nil {
+			for j, name := range agent.FanOut.Agents {
+				path := fmt.Sprintf("$.workflow.agents[%d].fan_out.agents[%d]", i, j)
+				if name == agent.Name {
+					add(path, "agent cannot fan out to itself")
+				} else if _, ok := seen[name]; !ok {
+					add(path, "agent %q not found", name)
+				}
+			}
+		}
+		if agent.Loop != nil && agent.Loop.Until.Expression != "" {
+			if _, err := parseRouteExpression(agent.Loop.Until.Expression); err != nil {
+				add(fmt.Sprintf("$.workflow.agents[%d].loop.until.expression", i), "%s", err.Error())
+			}
+		}
+	}
+
+	inputNames := make(map[string]struct{}, len(workflow.Inputs))
+	for i, input := range workflow.Inputs {
+		if _, dup := inputNames[input.Name]; dup {
+			add(fmt.Sprintf("$.workflow.inputs[%d].name", i), "duplicate input name %q", input.Name)
+		}
+		inputNames[input.Name] = struct{}{}
+		if err := validateInputDeclaration(input); err != nil {
+			add(fmt.Sprintf("$.workflow.inputs[%d]", i), "%s", err.Error())
+		}
+	}
+	if req.WorkflowRef == "" {
+		// The inputs of referenced workflows are checked once resolved.
+		values := normalizeJSONMap(req.Inputs)
+		for _, name := range unknownInputs(workflow.Inputs, values) {
+			add("$.inputs."+name, "input is not declared by the workflow")
+		}
+		for _, input := range workflow.Inputs {
+			if _, err := resolveInput(input, values[input.Name]); err != nil {
+				add("$.inputs."+input.Name, "%s", err.Error())
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,129 @@
+package workflowrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateManifestBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		// errs maps the paths of the expected errors to a part of their
+		// message.
+		errs map[string]string
+	}{
+		{
+			name: "valid",
+			manifest: `{
+				"version": "v2",
+				"query": "hello",
+				"session": {"session_id": "s1", "credentials": {"user_id": "u1", "account_id": "a1"}},
+				"callback": {"target": "https://example.com/hook"},
+				"workflow": {"name": "wf", "starting_agent": "triage", "agents": [
+					{"name": "triage", "instructions": "Route.", "handoffs": ["billing"]},
+					{"name": "billing", "instructions": "Help."}
+				]}
+			}`,
+		},
+		{
+			name: "valid v1",
+			manifest: `{
+				"query": "hello",
+				"session": {"session_id": "s1", "credentials": {"user_id": "u1", "account_id": "a1"}},
+				"callback": "https://example.com/hook",
+				"workflow": {"name": "wf", "starting_agent": "triage", "agents": [
+					{"name": "triage", "instructions": "Route.", "handoff": ["billing"]},
+					{"name": "billing", "instructions": "Help."}
+				]}
+			}`,
+		},
+		{
+			name:     "invalid JSON",
+			manifest: `{"query": "hello",`,
+			errs:     map[string]string{"$": "invalid JSON"},
+		},
+		{
+			// Manifests are JSON; YAML documents must be converted first.
+			name:     "YAML",
+			manifest: "query: hello\nworkflow:\n  name: wf\n",
+			errs:     map[string]string{"$": "invalid JSON"},
+		},
+		{
+			name:     "unsupported version",
+			manifest: `{"version": "v9", "query": "hello"}`,
+			errs:     map[string]string{"$.version": `unsupported manifest version "v9"`},
+		},
+		{
+			name: "unknown fields",
+			manifest: `{
+				"query": "hello",
+				"session": {"session_id": "s1", "credentials": {"user_id": "u1", "account_id": "a1"}, "ttl": 60},
+				"callback": {"target": "https://example.com/hook"},
+				"workflow": {"name": "wf", "starting_agent": "a", "agents": [
+					{"name": "a", "instructions": "Help.", "temperature": 0.2}
+				]}
+			}`,
+			errs: map[string]string{
+				"$.session.ttl":                    "is not allowed",
+				"$.workflow.agents[0].temperature": "is not allowed",
+			},
+		},
+		{
+			name: "schema violations",
+			manifest: `{
+				"session": {"session_id": "", "credentials": {"user_id": "u1", "account_id": "a1"}},
+				"callback": {"target": "https://example.com/hook", "mode": "carrier_pigeon"},
+				"workflow": {"name": "wf", "starting_agent": "a", "agents": [
+					{"name": "a", "instructions": "Help.", "handoffs": "b"}
+				]}
+			}`,
+			errs: map[string]string{
+				"$":                             "one and only one schema",
+				"$.query":                       "is required",
+				"$.session.session_id":          "length must be greater than or equal to 1",
+				"$.callback.mode":               "must be one of",
+				"$.workflow.agents[0].handoffs": "Expected: array, given: string",
+			},
+		},
+		{
+			name: "references",
+			manifest: `{
+				"query": "hello",
+				"session": {"session_id": "s1", "credentials": {"user_id": "u1", "account_id": "a1"}},
+				"callback": {"target": "https://example.com/hook"},
+				"workflow": {"name": "wf", "starting_agent": "missing", "agents": [
+					{"name": "a", "instructions": "Help.", "handoffs": ["b"]},
+					{"name": "a", "instructions": "Help."}
+				]}
+			}`,
+			errs: map[string]string{
+				"$.workflow.starting_agent":        `agent "missing" not found`,
+				"$.workflow.agents[0].handoffs[0]": `agent "b" not found`,
+				"$.workflow.agents[1].name":        `duplicate agent name "a"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateManifestBytes([]byte(tt.manifest))
+			if tt.errs == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var errs ManifestErrors
+			require.ErrorAs(t, err, &errs)
+			got := make(map[string]string, len(errs))
+			for _, e := range errs {
+				got[e.Path] = e.Message
+			}
+			require.Len(t, got, len(tt.errs), "%v", errs)
+			for path, message := range tt.errs {
+				require.Contains(t, got, path, "%v", errs)
+				assert.Contains(t, got[path], message)
+			}
+		})
+	}
+}
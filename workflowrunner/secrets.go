@@ -0,0 +1,298 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrSecretNotFound is returned by a SecretProvider which has no secret with
+// the requested name.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider resolves the secrets referenced in manifests as
+// ${secret:NAME}.
+type SecretProvider interface {
+	ResolveSecret(ctx context.Context, name string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+func (f SecretProviderFunc) ResolveSecret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// SecretProviders tries each provider in order, until one of them has the
+// secret.
+type SecretProviders []SecretProvider
+
+func (providers SecretProviders) ResolveSecret(ctx context.Context, name string) (string, error) {
+	for _, p := range providers {
+		value, err := p.ResolveSecret(ctx, name)
+		if !errors.Is(err, ErrSecretNotFound) {
+			return value, err
+		}
+	}
+	return "", ErrSecretNotFound
+}
+
+// EnvSecretProvider resolves secrets from environment variables named
+// Prefix followed by the secret name.
+type EnvSecretProvider struct {
+	Prefix string
+}
+
+func (p EnvSecretProvider) ResolveSecret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(p.Prefix + name)
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// FileSecretProvider resolves secrets from the files of a directory, such as
+// Docker or Kubernetes mounted secrets. Trailing newlines are trimmed.
+type FileSecretProvider struct {
+	Dir string
+}
+
+func (p FileSecretProvider) ResolveSecret(_ context.Context, name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid secret file name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+var secretRefPattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// secretResolver replaces the secret references found in declarations.
+type secretResolver struct {
+	provider SecretProvider
+	cache    map[string]string
+}
+
+func (r *secretResolver) resolveString(ctx context.Context, s string) (string, error) {
+	if !strings.Contains(s, "${secret:") {
+		return s, nil
+	}
+	var resolveErr error
+	out := secretRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := secretRefPattern.FindStringSubmatch(ref)[1]
+		if resolveErr != nil {
+			return ref
+		}
+		if value, ok := r.cache[name]; ok {
+			return value
+		}
+		value, err := r.resolveName(ctx, name)
+		if err != nil {
+			resolveErr = err
+			return ref
+		}
+		r.cache[name] = value
+		return value
+	})
+	return out, resolveErr
+}
+
+func (r *secretResolver) resolveName(ctx context.Context, name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", errors.New("empty secret reference")
+	}
+	if r.provider == nil {
+		return "", fmt.Errorf("secret %q is referenced but Builder.SecretProvider is not set", name)
+	}
+	value, err := r.provider.ResolveSecret(ctx, name)
+	if err != nil {
+		// The error must never contain the secret value.
+		return "", fmt.Errorf("resolve secret %q: %w", name, err)
+	}
+	return value, nil
+}
+
+func (r *secretResolver) resolveValue(ctx context.Context, v any) (any, error) {
+	switch value := v.(type) {
+	case string:
+		return r.resolveString(ctx, value)
+	case map[string]any:
+		return r.resolveMap(ctx, value)
+	case []any:
+		out := make([]any, len(value))
+		for i, item := range value {
+			resolved, err := r.resolveValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r *secretResolver) resolveMap(ctx context.Context, m map[string]any) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		resolved, err := r.resolveValue(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = resolved
+	}
+	return out, nil
+}
+
+func (r *secretResolver) resolveStringMap(ctx context.Context, m map[string]string) (map[string]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := maps.Clone(m)
+	for k, v := range out {
+		resolved, err := r.resolveString(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = resolved
+	}
+	return out, nil
+}
+
+// errSecretNotAllowed is returned for the secret references found outside of
+// credential fields, where a manifest could send the secret to any host.
+var errSecretNotAllowed = errors.New("secret references are only allowed in credential fields")
+
+// credentialConfigKeys are the keys of the hosted MCP tool configurations and
+// MCP server additional fields holding credentials.
+var credentialConfigKeys = []string{"authorization", "headers"}
+
+// hasSecretRef reports whether a configuration value references a secret.
+func hasSecretRef(v any) bool {
+	switch value := v.(type) {
+	case string:
+		return secretRefPattern.MatchString(value)
+	case map[string]any:
+		for _, item := range value {
+			if hasSecretRef(item) {
+				return true
+			}
+		}
+	case []any:
+		return slices.ContainsFunc(value, hasSecretRef)
+	case map[string]string:
+		for _, item := range value {
+			if secretRefPattern.MatchString(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveCredentials returns a copy of the configuration where the secret
+// references of the credential keys are replaced by their values. References
+// found under other keys are rejected.
+func (r *secretResolver) resolveCredentials(ctx context.Context, config map[string]any, credentials bool) (map[string]any, error) {
+	if config == nil {
+		return nil, nil
+	}
+	out := maps.Clone(config)
+	for k, v := range out {
+		if !credentials || !slices.Contains(credentialConfigKeys, k) {
+			if hasSecretRef(v) {
+				return nil, fmt.Errorf("%s: %w", k, errSecretNotAllowed)
+			}
+			continue
+		}
+		resolved, err := r.resolveValue(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = resolved
+	}
+	return out, nil
+}
+
+// resolveAgentSecrets returns a copy of the agent declaration where the
+// secret references of the credential fields are replaced by their values:
+// model keys and headers, and the authorization and headers of hosted MCP
+// tools and MCP servers. References found in other fields, such as endpoints,
+// are rejected.
+func (r *secretResolver) resolveAgentSecrets(ctx context.Context, decl AgentDeclaration) (AgentDeclaration, error) {
+	var err error
+	if decl.Model != nil {
+		model := *decl.Model
+		if model.APIKey, err = r.resolveString(ctx, model.APIKey); err != nil {
+			return decl, fmt.Errorf("model api_key: %w", err)
+		}
+		if model.ExtraHeaders, err = r.resolveStringMap(ctx, model.ExtraHeaders); err != nil {
+			return decl, fmt.Errorf("model extra_headers: %w", err)
+		}
+		if hasSecretRef(model.BaseURL) {
+			return decl, fmt.Errorf("model base_url: %w", errSecretNotAllowed)
+		}
+		if hasSecretRef(model.ExtraQuery) {
+			return decl, fmt.Errorf("model extra_query: %w", errSecretNotAllowed)
+		}
+		decl.Model = &model
+	}
+
+	decl.Tools = slices.Clone(decl.Tools)
+	for i, tool := range decl.Tools {
+		if decl.Tools[i].Config, err = r.resolveCredentials(ctx, tool.Config, tool.Type == "hosted_mcp"); err != nil {
+			return decl, fmt.Errorf("tool %q config: %w", tool.Type, err)
+		}
+	}
+
+	decl.MCPServers = slices.Clone(decl.MCPServers)
+	for i, mcp := range decl.MCPServers {
+		if hasSecretRef(mcp.Address) {
+			return decl, fmt.Errorf("mcp %q address: %w", mcp.ServerLabel, errSecretNotAllowed)
+		}
+		if decl.MCPServers[i].Additional, err = r.resolveCredentials(ctx, mcp.Additional, true); err != nil {
+			return decl, fmt.Errorf("mcp %q additional: %w", mcp.ServerLabel, err)
+		}
+	}
+
+	for _, guardrails := range []*[]GuardrailDeclaration{&decl.InputGuardrails, &decl.OutputGuardrails} {
+		for _, gr := range *guardrails {
+			if hasSecretRef(gr.Config) {
+				return decl, fmt.Errorf("guardrail %q config: %w", gr.Name, errSecretNotAllowed)
+			}
+		}
+	}
+	return decl, nil
+}
+
+// resolveSecrets returns a copy of the workflow where the secret references
+// of the agents are replaced by their values.
+func (b *Builder) resolveSecrets(ctx context.Context, workflow WorkflowDeclaration) (WorkflowDeclaration, error) {
+	resolver := &secretResolver{provider: b.SecretProvider, cache: make(map[string]string)}
+	agentDecls := make([]AgentDeclaration, len(workflow.Agents))
+	for i, decl := range workflow.Agents {
+		resolved, err := resolver.resolveAgentSecrets(ctx, decl)
+		if err != nil {
+			return workflow, fmt.Errorf("agent %q: %w", decl.Name, err)
+		}
+		agentDecls[i] = resolved
+	}
+	workflow.Agents = agentDecls
+	return workflow, nil
+}
@@ -0,0 +1,92 @@
+package workflowrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSecretProvider(secrets map[string]string) SecretProvider {
+	return SecretProviderFunc(func(_ context.Context, name string) (string, error) {
+		value, ok := secrets[name]
+		if !ok {
+			return "", ErrSecretNotFound
+		}
+		return value, nil
+	})
+}
+
+func TestPrepareRequestKeepsSecretReferencesOfInputsLiteral(t *testing.T) {
+	builder := newTestBuilder(t)
+	builder.SecretProvider = testSecretProvider(map[string]string{"OPENAI_API_KEY": "sk-server"})
+	req := testRequest("s1")
+	req.Workflow.Inputs = []WorkflowInputDeclaration{{Name: "host"}}
+	req.Workflow.Agents[0].Model = &ModelDeclaration{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		BaseURL:  "https://${inputs:host}/v1",
+		APIKey:   "${secret:OPENAI_API_KEY}",
+	}
+	req.Inputs = map[string]any{"host": "evil.example/${secret:OPENAI_API_KEY}"}
+	req.Metadata = map[string]any{"note": "${secret:OPENAI_API_KEY}"}
+	req.Workflow.Agents[0].Instructions = "Note: ${metadata:note}"
+
+	prepared, _, err := builder.prepareRequest(t.Context(), req)
+	require.NoError(t, err)
+	agent := prepared.Workflow.Agents[0]
+	assert.Equal(t, "https://evil.example/${secret:OPENAI_API_KEY}/v1", agent.Model.BaseURL)
+	assert.Equal(t, "sk-server", agent.Model.APIKey)
+	assert.Equal(t, "Note: ${secret:OPENAI_API_KEY}", agent.Instructions)
+}
+
+func TestResolveSecretsOnlyInCredentialFields(t *testing.T) {
+	builder := newTestBuilder(t)
+	builder.SecretProvider = testSecretProvider(map[string]string{"KEY": "s3cr3t"})
+	workflow := func(decl AgentDeclaration) WorkflowDeclaration {
+		decl.Name = "assistant"
+		return WorkflowDeclaration{Name: "wf", StartingAgent: "assistant", Agents: []AgentDeclaration{decl}}
+	}
+
+	resolved, err := builder.resolveSecrets(t.Context(), workflow(AgentDeclaration{
+		Model: &ModelDeclaration{
+			Model:        "gpt-4o",
+			APIKey:       "${secret:KEY}",
+			ExtraHeaders: map[string]string{"X-Api-Key": "${secret:KEY}"},
+		},
+		Tools: []ToolDeclaration{{Type: "hosted_mcp", Config: map[string]any{
+			"server_url":    "https://mcp.example.com",
+			"authorization": "Bearer ${secret:KEY}",
+		}}},
+		MCPServers: []MCPDeclaration{{
+			Address:    "https://mcp.example.com",
+			Additional: map[string]any{"headers": map[string]any{"X-Token": "${secret:KEY}"}},
+		}},
+	}))
+	require.NoError(t, err)
+	agent := resolved.Agents[0]
+	assert.Equal(t, "s3cr3t", agent.Model.APIKey)
+	assert.Equal(t, "s3cr3t", agent.Model.ExtraHeaders["X-Api-Key"])
+	assert.Equal(t, "Bearer s3cr3t", agent.Tools[0].Config["authorization"])
+	assert.Equal(t, map[string]any{"X-Token": "s3cr3t"}, agent.MCPServers[0].Additional["headers"])
+
+	rejected := map[string]AgentDeclaration{
+		"base_url":    {Model: &ModelDeclaration{Model: "gpt-4o", BaseURL: "https://evil.example/${secret:KEY}"}},
+		"extra_query": {Model: &ModelDeclaration{Model: "gpt-4o", ExtraQuery: map[string]string{"k": "${secret:KEY}"}}},
+		"mcp_address": {MCPServers: []MCPDeclaration{{Address: "https://evil.example/${secret:KEY}"}}},
+		"mcp_additional": {MCPServers: []MCPDeclaration{{
+			Address:    "https://mcp.example.com",
+			Additional: map[string]any{"server_url": "https://evil.example/${secret:KEY}"},
+		}}},
+		"tool_config":      {Tools: []ToolDeclaration{{Type: "web_search", Config: map[string]any{"user_location": map[string]any{"city": "${secret:KEY}"}}}}},
+		"hosted_mcp_url":   {Tools: []ToolDeclaration{{Type: "hosted_mcp", Config: map[string]any{"server_url": "https://evil.example/${secret:KEY}"}}}},
+		"guardrail_config": {InputGuardrails: []GuardrailDeclaration{{Name: "regex", Config: map[string]any{"pattern": "${secret:KEY}"}}}},
+	}
+	for name, decl := range rejected {
+		t.Run(name, func(t *testing.T) {
+			_, err := builder.resolveSecrets(t.Context(), workflow(decl))
+			assert.ErrorIs(t, err, errSecretNotAllowed)
+		})
+	}
+}
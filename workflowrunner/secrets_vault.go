@@ -0,0 +1,91 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultSecretProvider resolves secrets from a HashiCorp Vault KV version 2
+// secrets engine. Secret names have the form "path#key", e.g.
+// "team/openai#api_key"; the key defaults to "value".
+type VaultSecretProvider struct {
+	// Address of the Vault server, e.g. "https://vault.example.com:8200".
+	Address string
+	Token   string
+	// Mount path of the KV engine. Defaults to "secret".
+	Mount string
+	// Optional Vault Enterprise namespace.
+	Namespace string
+	Client    *http.Client
+}
+
+// NewVaultSecretProvider returns a provider using the VAULT_ADDR,
+// VAULT_TOKEN and VAULT_NAMESPACE environment variables.
+func NewVaultSecretProvider() *VaultSecretProvider {
+	return &VaultSecretProvider{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+func (p *VaultSecretProvider) ResolveSecret(ctx context.Context, name string) (string, error) {
+	path, key, found := strings.Cut(name, "#")
+	if !found {
+		key = "value"
+	}
+	mount := p.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	endpoint, err := url.JoinPath(p.Address, "v1", mount, "data", path)
+	if err != nil {
+		return "", fmt.Errorf("invalid vault address: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("vault returned status %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+	value, ok := body.Data.Data[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,133 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+)
+
+// SessionStoreConfig configures the store of the sessions, see
+// SessionDeclaration.StoreConfig. Its fields apply to the PostgreSQL store.
+type SessionStoreConfig struct {
+	// DSN of the database, overriding the one of the factory. It can
+	// reference ${secret:NAME} and ${env:NAME}.
+	DSN string `json:"dsn,omitempty"`
+	// Schema holding the tables, set as the search_path of the connections.
+	Schema string `json:"schema,omitempty" jsonschema:"pattern=^[A-Za-z_][A-Za-z0-9_]*$"`
+	// TablePrefix is prepended to the names of the session tables.
+	TablePrefix string `json:"table_prefix,omitempty" jsonschema:"pattern=^[A-Za-z_][A-Za-z0-9_]*$"`
+	// MaxConns and MinConns size the connection pool, with the pgxpool
+	// defaults when zero.
+	MaxConns int32 `json:"max_conns,omitempty" jsonschema:"minimum=0"`
+	MinConns int32 `json:"min_conns,omitempty" jsonschema:"minimum=0"`
+}
+
+func validateSessionStoreConfig(cfg SessionStoreConfig) error {
+	if cfg.Schema != "" && !identifierPattern.MatchString(cfg.Schema) {
+		return fmt.Errorf("schema %q is not a valid identifier", cfg.Schema)
+	}
+	if cfg.TablePrefix != "" && !identifierPattern.MatchString(cfg.TablePrefix) {
+		return fmt.Errorf("table_prefix %q is not a valid identifier", cfg.TablePrefix)
+	}
+	if cfg.MaxConns < 0 || cfg.MinConns < 0 {
+		return errors.New("pool sizes cannot be negative")
+	}
+	if cfg.MaxConns > 0 && cfg.MinConns > cfg.MaxConns {
+		return errors.New("min_conns cannot exceed max_conns")
+	}
+	return nil
+}
+
+// NewPostgresSessionFactory stores sessions in PostgreSQL, namespaced by the
+// caller's account ID. The store_config of the sessions can override the
+// dsn, required when empty here, and set the schema, the prefix of the
+// tables and the size of the connection pool. Sessions with the same
+// configuration share a pool, kept open for the lifetime of the factory.
+func NewPostgresSessionFactory(dsn string) SessionFactory {
+	pools := &pgSessionPools{pools: make(map[SessionStoreConfig]*pgxpool.Pool)}
+	return func(ctx context.Context, decl SessionDeclaration) (memory.Session, error) {
+		cfg := SessionStoreConfig{DSN: dsn}
+		if decl.StoreConfig != nil {
+			cfg = *decl.StoreConfig
+			if cfg.DSN == "" {
+				cfg.DSN = dsn
+			}
+		}
+		if cfg.DSN == "" {
+			return nil, errors.New("postgres session store: dsn is required")
+		}
+		pool, err := pools.get(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return memory.NewPgSession(ctx, memory.PgSessionParams{
+			SessionID:     decl.SessionID,
+			Namespace:     decl.Credentials.AccountID,
+			SessionTable:  cfg.TablePrefix + "agent_sessions",
+			MessagesTable: cfg.TablePrefix + "agent_messages",
+			Conn:          pgPoolConn{pool: pool},
+		})
+	}
+}
+
+type pgSessionPools struct {
+	mu    sync.Mutex
+	pools map[SessionStoreConfig]*pgxpool.Pool
+}
+
+func (p *pgSessionPools) get(ctx context.Context, cfg SessionStoreConfig) (*pgxpool.Pool, error) {
+	// The table prefix does not change the connections.
+	key := cfg
+	key.TablePrefix = ""
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pool, ok := p.pools[key]; ok {
+		return pool, nil
+	}
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		// The error could include the password of the DSN.
+		return nil, errors.New("postgres session store: invalid dsn")
+	}
+	if cfg.Schema != "" {
+		poolConfig.ConnConfig.RuntimeParams["search_path"] = cfg.Schema
+	}
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("postgres session store: %w", err)
+	}
+	p.pools[key] = pool
+	return pool, nil
+}
+
+// pgPoolConn runs the queries of a session on a shared pool, which is not
+// closed with the session.
+type pgPoolConn struct {
+	pool *pgxpool.Pool
+}
+
+func (c pgPoolConn) Query(ctx context.Context, sql string, args ...any) (memory.PgRowsInterface, error) {
+	return c.pool.Query(ctx, sql, args...)
+}
+
+func (c pgPoolConn) QueryRow(ctx context.Context, sql string, args ...any) memory.PgRowInterface {
+	return c.pool.QueryRow(ctx, sql, args...)
+}
+
+func (c pgPoolConn) Exec(ctx context.Context, sql string, args ...any) (any, error) {
+	return c.pool.Exec(ctx, sql, args...)
+}
+
+func (c pgPoolConn) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,195 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/asynctask"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+// ErrExecutionNotRecoverable is returned when recovering a session whose run
+// is not running, or has no snapshot.
+var ErrExecutionNotRecoverable = errors.New("execution is not recoverable")
+
+// ExecutionSnapshot is the input of the model call starting a turn of a
+// run, from which the run can be recovered if its process crashes. See
+// RunnerService.TurnSnapshots.
+type ExecutionSnapshot struct {
+	// Agent is the declaration name of the agent taking the turn.
+	Agent string `json:"agent"`
+	// Iteration of the agent loop, if any.
+	Iteration int `json:"iteration"`
+	// RouteHops taken by the run so far.
+	RouteHops int `json:"route_hops"`
+	// Turn of the agent step, from 1.
+	Turn int `json:"turn"`
+	// Input holds the conversation so far.
+	Input []agents.TResponseInputItem `json:"input"`
+	// Unsaved is the number of items at the end of Input which were not
+	// added to the session yet.
+	Unsaved   int       `json:"unsaved"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *ExecutionSnapshot) UnmarshalJSON(data []byte) error {
+	type plain ExecutionSnapshot
+	var snapshot struct {
+		plain
+		Input []json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	*s = ExecutionSnapshot(snapshot.plain)
+	s.Input = make([]agents.TResponseInputItem, len(snapshot.Input))
+	for i, raw := range snapshot.Input {
+		item, err := memory.UnmarshalItem(raw)
+		if err != nil {
+			return fmt.Errorf("decode snapshot input item %d: %w", i, err)
+		}
+		s.Input[i] = item
+	}
+	return nil
+}
+
+// turnStep is an agent step of a run, whose turns are snapshotted.
+type turnStep struct {
+	tracker   *executionStateTracker
+	build     *BuildResult
+	iteration int
+	routeHops int
+	// saved is the number of leading input items already in the session,
+	// or -1 for steps run with the session, whose input is known with
+	// their first turn.
+	saved int
+	turn  int
+}
+
+type turnStepKey struct{}
+
+// contextWithTurnStep returns a context whose model calls are snapshotted
+// as the turns of step.
+func contextWithTurnStep(ctx context.Context, step *turnStep) context.Context {
+	return context.WithValue(ctx, turnStepKey{}, step)
+}
+
+// snapshot records the turn about to start with the given input.
+func (s *turnStep) snapshot(ctx context.Context, agent *agents.Agent, input []agents.TResponseInputItem) error {
+	if s.saved < 0 {
+		// The session history, followed by the query.
+		s.saved = max(len(input)-1, 0)
+	}
+	s.turn++
+	return s.tracker.OnTurnSnapshot(ctx, ExecutionSnapshot{
+		Agent:     s.build.agentDeclarationName(agent),
+		Iteration: s.iteration,
+		RouteHops: s.routeHops,
+		Turn:      s.turn,
+		Input:     input,
+		Unsaved:   len(input) - s.saved,
+		CreatedAt: s.tracker.clock.now(),
+	})
+}
+
+// turnSnapshotHooks snapshots the turns of the agents, before each model
+// call, on top of their other hooks.
+type turnSnapshotHooks struct {
+	agents.AgentHooks
+}
+
+// withTurnSnapshots wraps the hooks of the built agents.
+func withTurnSnapshots(build *BuildResult) {
+	for _, agent := range build.AgentMap {
+		agent.Hooks = turnSnapshotHooks{AgentHooks: agent.Hooks}
+	}
+}
+
+func (h turnSnapshotHooks) OnLLMStart(ctx context.Context, agent *agents.Agent, systemPrompt param.Opt[string], input []agents.TResponseInputItem) error {
+	if err := h.AgentHooks.OnLLMStart(ctx, agent, systemPrompt, input); err != nil {
+		return err
+	}
+	// Agents run as tools and fan-out branches are part of the turns of
+	// their caller.
+	step, ok := ctx.Value(turnStepKey{}).(*turnStep)
+	if !ok || agents.ToolDataFromContext(ctx) != nil {
+		return nil
+	}
+	return step.snapshot(ctx, agent, input)
+}
+
+// Recover continues the run of the session of the request, whose process
+// crashed while it was running, from the snapshot of its last turn, which
+// runs again. TurnSnapshots must have been enabled by the crashed process.
+//
+// Only the process recovering a run first claims it; others fail with
+// ErrExecutionStateConflict. As the runs are not leased, recover the ones
+// known to be lost, e.g. running without updates for longer than a turn
+// can take.
+func (s *RunnerService) Recover(ctx context.Context, req WorkflowRequest) (*asynctask.Task[RunSummary], error) {
+	req, err := s.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.recoverableState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		req.Query = state.LastQuery
+	}
+	return s.execute(ctx, req, nil, &state)
+}
+
+// RecoverWithPublisher is like Recover, with the events published as by
+// ExecuteWithPublisher.
+func (s *RunnerService) RecoverWithPublisher(ctx context.Context, req WorkflowRequest, publisher CallbackPublisher) (*asynctask.Task[RunSummary], error) {
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	req, err := s.resolveWorkflowRef(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.recoverableState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		req.Query = state.LastQuery
+	}
+	req.Callback = CallbackDeclaration{Mode: CallbackModeStream}
+	return s.execute(ctx, req, publisher, &state)
+}
+
+// recoverableState returns the state of the run to recover.
+func (s *RunnerService) recoverableState(ctx context.Context, req WorkflowRequest) (WorkflowExecutionState, error) {
+	sessionID := req.Session.SessionID
+	state, ok, err := s.GetState(ctx, sessionID)
+	if err != nil {
+		return state, err
+	}
+	if !ok {
+		return state, fmt.Errorf("%w: session %q", ErrExecutionNotFound, sessionID)
+	}
+	if state.Status != ExecutionStatusRunning {
+		return state, fmt.Errorf("%w: session %q is %s", ErrExecutionNotRecoverable, sessionID, state.Status)
+	}
+	if state.Snapshot == nil {
+		return state, fmt.Errorf("%w: session %q has no snapshot", ErrExecutionNotRecoverable, sessionID)
+	}
+	if unsaved := state.Snapshot.Unsaved; unsaved < 0 || unsaved > len(state.Snapshot.Input) {
+		return state, fmt.Errorf("%w: session %q has a snapshot of %d items with %d unsaved",
+			ErrExecutionNotRecoverable, sessionID, len(state.Snapshot.Input), unsaved)
+	}
+	if state.WorkflowName != req.Workflow.Name {
+		return state, fmt.Errorf("session %q was running workflow %q, not %q", sessionID, state.WorkflowName, req.Workflow.Name)
+	}
+	return state, nil
+}
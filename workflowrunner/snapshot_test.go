@@ -0,0 +1,137 @@
+package workflowrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverableStateValidatesUnsaved(t *testing.T) {
+	ctx := t.Context()
+	service := &RunnerService{StateStore: NewInMemoryExecutionStateStore()}
+	req := WorkflowRequest{
+		Session:  SessionDeclaration{SessionID: "s1"},
+		Workflow: WorkflowDeclaration{Name: "wf"},
+	}
+	input := []agents.TResponseInputItem{agentstesting.GetTextInputItem("hello")}
+
+	for _, unsaved := range []int{-1, 2} {
+		require.NoError(t, service.StateStore.Save(ctx, WorkflowExecutionState{
+			SessionID:    "s1",
+			WorkflowName: "wf",
+			Status:       ExecutionStatusRunning,
+			Snapshot:     &ExecutionSnapshot{Agent: "a", Input: input, Unsaved: unsaved},
+		}))
+		_, err := service.recoverableState(ctx, req)
+		assert.ErrorIs(t, err, ErrExecutionNotRecoverable, "unsaved %d", unsaved)
+	}
+
+	require.NoError(t, service.StateStore.Save(ctx, WorkflowExecutionState{
+		SessionID:    "s1",
+		WorkflowName: "wf",
+		Status:       ExecutionStatusRunning,
+		Snapshot:     &ExecutionSnapshot{Agent: "a", Input: input, Unsaved: 1},
+	}))
+	state, err := service.recoverableState(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Snapshot.Unsaved)
+}
+
+// stallingModel answers the first call with first, and blocks the next ones
+// until their context is done, as a process crashing meanwhile.
+type stallingModel struct {
+	first   *agentstesting.FakeModel
+	stalled chan struct{}
+	calls   int
+}
+
+func (m *stallingModel) GetResponse(ctx context.Context, params agents.ModelResponseParams) (*agents.ModelResponse, error) {
+	if m.calls++; m.calls == 1 {
+		return m.first.GetResponse(ctx, params)
+	}
+	close(m.stalled)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (m *stallingModel) StreamResponse(ctx context.Context, params agents.ModelResponseParams, yield agents.ModelStreamResponseCallback) error {
+	if m.calls++; m.calls == 1 {
+		return m.first.StreamResponse(ctx, params, yield)
+	}
+	close(m.stalled)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRecoverFromTurnSnapshot(t *testing.T) {
+	ctx := t.Context()
+	lookup := func(context.Context, ToolDeclaration, ToolFactoryEnv) (agents.Tool, error) {
+		return agents.NewFunctionTool("lookup", "Look the weather up.",
+			func(context.Context, struct{}) (string, error) { return "sunny", nil }), nil
+	}
+	req := testRequest("s1")
+	req.Workflow.Agents[0].Tools = []ToolDeclaration{{Type: "lookup"}}
+
+	// The first process crashes during the second turn.
+	crashing := &stallingModel{
+		first: agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+			Value: []agents.TResponseOutputItem{agentstesting.GetFunctionToolCall("lookup", "{}")},
+		}),
+		stalled: make(chan struct{}),
+	}
+	crashed := newTestService(t, crashing, &recordingPublisher{})
+	crashed.Builder.ToolFactories["lookup"] = lookup
+	crashed.StateStore = NewDirExecutionStateStore(t.TempDir())
+	crashed.TurnSnapshots = true
+	task, err := crashed.Execute(ctx, req)
+	require.NoError(t, err)
+	<-crashing.stalled
+	state, ok, err := crashed.GetState(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	task.Cancel()
+	require.Error(t, task.Await().Error)
+
+	require.NotNil(t, state.Snapshot)
+	assert.Equal(t, ExecutionStatusRunning, state.Status)
+	assert.Equal(t, "assistant", state.Snapshot.Agent)
+	assert.Equal(t, 2, state.Snapshot.Turn)
+	require.Len(t, state.Snapshot.Input, 3)
+
+	// Another process recovers the run from the snapshot of the turn.
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("It is sunny.")},
+	})
+	publisher := &recordingPublisher{}
+	service := newTestService(t, model, publisher)
+	service.Builder.SessionFactory = crashed.Builder.SessionFactory
+	service.Builder.ToolFactories["lookup"] = lookup
+	require.NoError(t, service.StateStore.Save(ctx, state))
+
+	task, err = service.Recover(ctx, req)
+	require.NoError(t, err)
+	result := task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusCompleted, result.Value.Status)
+	assert.Equal(t, "It is sunny.", result.Value.FinalOutput)
+	assert.Equal(t, CallbackEventRunRecovered, publisher.published()[0])
+
+	input := model.LastTurnArgs.Input.(agents.InputItems)
+	require.Len(t, input, 3)
+	assert.Equal(t, "sunny", input[2].OfFunctionCallOutput.Output.OfString.Value)
+
+	// The session holds the conversation once.
+	session, err := service.Builder.SessionFactory(ctx, req.Session)
+	require.NoError(t, err)
+	defer closeSession(session)
+	items, err := session.GetItems(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+
+	_, err = service.Recover(ctx, req)
+	assert.ErrorIs(t, err, ErrExecutionNotRecoverable)
+}
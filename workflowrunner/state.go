@@ -2,7 +2,12 @@ package workflowrunner
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -15,8 +20,10 @@ const (
 	ExecutionStatusIdle            ExecutionStatus = "idle"
 	ExecutionStatusRunning         ExecutionStatus = "running"
 	ExecutionStatusWaitingApproval ExecutionStatus = "waiting_approval"
+	ExecutionStatusWaitingInput    ExecutionStatus = "waiting_input"
 	ExecutionStatusCompleted       ExecutionStatus = "completed"
 	ExecutionStatusFailed          ExecutionStatus = "failed"
+	ExecutionStatusTimedOut        ExecutionStatus = "timed_out"
 )
 
 type ApprovalRequestState struct {
@@ -28,25 +35,132 @@ type ApprovalRequestState struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// ApprovalDecisionState records the resolution of an approval request.
+type ApprovalDecisionState struct {
+	RequestID  string    `json:"request_id"`
+	Approve    bool      `json:"approve"`
+	Reason     string    `json:"reason,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// GuardrailResultState records the evaluation of a guardrail during a run.
+type GuardrailResultState struct {
+	Guardrail string `json:"guardrail"`
+	// Kind is "input" or "output".
+	Kind string `json:"kind" jsonschema:"enum=input,enum=output"`
+	// Agent whose input or output was checked.
+	Agent             string `json:"agent"`
+	TripwireTriggered bool   `json:"tripwire_triggered"`
+	// OutputInfo reported by the guardrail, as JSON.
+	OutputInfo  any       `json:"output_info,omitempty"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
 type WorkflowExecutionState struct {
-	SessionID        string                 `json:"session_id"`
-	WorkflowName     string                 `json:"workflow_name"`
-	Status           ExecutionStatus        `json:"status"`
-	LastAgent        string                 `json:"last_agent"`
-	LastResponseID   string                 `json:"last_response_id"`
-	LastQuery        string                 `json:"last_query"`
-	LastError        string                 `json:"last_error"`
-	PendingApprovals []ApprovalRequestState `json:"pending_approvals"`
-	FinalOutput      any                    `json:"final_output,omitempty"`
-	UpdatedAt        time.Time              `json:"updated_at"`
+	SessionID         string                  `json:"session_id"`
+	WorkflowName      string                  `json:"workflow_name"`
+	Status            ExecutionStatus         `json:"status"`
+	LastAgent         string                  `json:"last_agent"`
+	LastResponseID    string                  `json:"last_response_id"`
+	LastQuery         string                  `json:"last_query"`
+	LastError         string                  `json:"last_error"`
+	PendingApprovals  []ApprovalRequestState  `json:"pending_approvals"`
+	ResolvedApprovals []ApprovalDecisionState `json:"resolved_approvals,omitempty"`
+	FinalOutput       any                     `json:"final_output,omitempty"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+	// Version of the stored state, incremented by each save. See
+	// ExecutionStateStore.
+	Version int64 `json:"version"`
+	// Checkpoint of a run suspended on approval or input requests, see
+	// RunnerService.Resume.
+	Checkpoint *ExecutionCheckpoint `json:"checkpoint,omitempty"`
+	// Usage of the run so far.
+	Usage *RunUsage `json:"usage,omitempty"`
+	// PendingInputs are the questions of ask_user tools waiting for an
+	// answer, see RunnerService.ProvideInput.
+	PendingInputs  []InputRequestState  `json:"pending_inputs,omitempty"`
+	ProvidedInputs []InputResponseState `json:"provided_inputs,omitempty"`
+	// Snapshot of the turn in progress of a running execution, see
+	// RunnerService.TurnSnapshots.
+	Snapshot *ExecutionSnapshot `json:"snapshot,omitempty"`
+	// GuardrailResults of the guardrails evaluated by the run, including
+	// its resumptions.
+	GuardrailResults []GuardrailResultState `json:"guardrail_results,omitempty"`
+	// Request of the last run of the session, with its secret references
+	// unresolved and without its literal secrets and credentials but the
+	// owning AccountID, see RunnerService.Continue. It is only kept by the
+	// stores: ApprovalHandler leaves it out of its responses.
+	Request *WorkflowRequest `json:"request,omitempty" jsonschema:"-"`
+}
+
+// waitingStatus returns the status of an execution waiting for approvals or
+// inputs, as they get resolved: waiting_approval while approvals are
+// pending, then waiting_input while inputs are, then running. Other statuses
+// are kept.
+func (s WorkflowExecutionState) waitingStatus() ExecutionStatus {
+	if s.Status != ExecutionStatusWaitingApproval && s.Status != ExecutionStatusWaitingInput {
+		return s.Status
+	}
+	switch {
+	case len(s.PendingApprovals) > 0:
+		return ExecutionStatusWaitingApproval
+	case len(s.PendingInputs) > 0:
+		return ExecutionStatusWaitingInput
+	default:
+		return ExecutionStatusRunning
+	}
+}
+
+// ExecutionCheckpoint is the point where a run was suspended, waiting for
+// its approval and input requests to be resolved.
+type ExecutionCheckpoint struct {
+	// Agent is the declaration name of the agent which requested the
+	// approvals or inputs.
+	Agent string `json:"agent"`
+	// Iteration of the agent loop, if any.
+	Iteration int `json:"iteration"`
+	// RouteHops taken by the run so far.
+	RouteHops      int    `json:"route_hops"`
+	LastResponseID string `json:"last_response_id,omitempty"`
 }
 
+// ErrExecutionStateConflict is returned by ExecutionStateStore.Save when the
+// stored state was modified since it was loaded.
+var ErrExecutionStateConflict = errors.New("execution state was modified concurrently")
+
+// maxStateSaveAttempts bounds the retries of saves conflicting with
+// concurrent updates.
+const maxStateSaveAttempts = 5
+
+// ExecutionStateStore persists the execution states, with optimistic locking:
+// Save replaces the stored state only when its version equals state.Version,
+// and stores it with version state.Version+1; otherwise it returns
+// ErrExecutionStateConflict. A zero Version replaces any stored state but a
+// suspended one, which has a Checkpoint, and stores it with the stored
+// version plus one, so that versions never repeat.
 type ExecutionStateStore interface {
 	Save(ctx context.Context, state WorkflowExecutionState) error
 	Load(ctx context.Context, sessionID string) (WorkflowExecutionState, bool, error)
 	Clear(ctx context.Context, sessionID string) error
 }
 
+// nextStateVersion returns the version of state saved over stored, if ok,
+// as described by ExecutionStateStore, or ErrExecutionStateConflict.
+func nextStateVersion(stored WorkflowExecutionState, ok bool, state WorkflowExecutionState) (int64, error) {
+	switch {
+	case !ok:
+		return state.Version + 1, nil
+	case state.Version == 0 && stored.Checkpoint != nil:
+		return 0, ErrExecutionStateConflict
+	case state.Version == 0:
+		return stored.Version + 1, nil
+	case state.Version != stored.Version:
+		return 0, ErrExecutionStateConflict
+	default:
+		return state.Version + 1, nil
+	}
+}
+
 type InMemoryExecutionStateStore struct {
 	mu   sync.RWMutex
 	data map[string]WorkflowExecutionState
@@ -62,10 +176,21 @@ func (s *InMemoryExecutionStateStore) Save(_ context.Context, state WorkflowExec
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	stored, ok := s.data[state.SessionID]
+	version, err := nextStateVersion(stored, ok, state)
+	if err != nil {
+		return err
+	}
 	copyState := state
+	copyState.Version = version
 	if len(state.PendingApprovals) > 0 {
 		copyState.PendingApprovals = append([]ApprovalRequestState(nil), state.PendingApprovals...)
 	}
+	if len(state.ResolvedApprovals) > 0 {
+		copyState.ResolvedApprovals = append([]ApprovalDecisionState(nil), state.ResolvedApprovals...)
+	}
+	copyState.PendingInputs = slices.Clone(state.PendingInputs)
+	copyState.ProvidedInputs = slices.Clone(state.ProvidedInputs)
 	s.data[state.SessionID] = copyState
 	return nil
 }
@@ -80,6 +205,11 @@ func (s *InMemoryExecutionStateStore) Load(_ context.Context, sessionID string)
 	if len(state.PendingApprovals) > 0 {
 		state.PendingApprovals = append([]ApprovalRequestState(nil), state.PendingApprovals...)
 	}
+	if len(state.ResolvedApprovals) > 0 {
+		state.ResolvedApprovals = append([]ApprovalDecisionState(nil), state.ResolvedApprovals...)
+	}
+	state.PendingInputs = slices.Clone(state.PendingInputs)
+	state.ProvidedInputs = slices.Clone(state.ProvidedInputs)
 	return state, true, nil
 }
 
@@ -90,9 +220,107 @@ func (s *InMemoryExecutionStateStore) Clear(_ context.Context, sessionID string)
 	return nil
 }
 
+// DirExecutionStateStore keeps each state as a JSON file in
+// <Dir>/<session_id>.json, e.g. for command line tools resuming runs across
+// invocations. Versions are checked under a lock held by the process only,
+// so a directory must not be shared by concurrent processes.
+type DirExecutionStateStore struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// NewDirExecutionStateStore returns a store keeping the states in dir,
+// created on first save.
+func NewDirExecutionStateStore(dir string) *DirExecutionStateStore {
+	return &DirExecutionStateStore{Dir: dir}
+}
+
+func (s *DirExecutionStateStore) path(sessionID string) string {
+	return filepath.Join(s.Dir, sanitizeFileName(sessionID)+".json")
+}
+
+func (s *DirExecutionStateStore) Save(_ context.Context, state WorkflowExecutionState) error {
+	if state.SessionID == "" {
+		return errors.New("missing session id")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok, err := s.load(state.SessionID)
+	if err != nil {
+		return err
+	}
+	if state.Version, err = nextStateVersion(stored, ok, state); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal execution state: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	// Write to a temporary file first, so readers never see partial states.
+	f, err := os.CreateTemp(s.Dir, ".state-*")
+	if err != nil {
+		return fmt.Errorf("create state file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("write state file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("write state file: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path(state.SessionID)); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("replace state file: %w", err)
+	}
+	return nil
+}
+
+func (s *DirExecutionStateStore) Load(_ context.Context, sessionID string) (WorkflowExecutionState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(sessionID)
+}
+
+func (s *DirExecutionStateStore) load(sessionID string) (WorkflowExecutionState, bool, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return WorkflowExecutionState{}, false, nil
+	}
+	if err != nil {
+		return WorkflowExecutionState{}, false, fmt.Errorf("read state file: %w", err)
+	}
+	var state WorkflowExecutionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return WorkflowExecutionState{}, false, fmt.Errorf("decode execution state %q: %w", sessionID, err)
+	}
+	return state, true, nil
+}
+
+func (s *DirExecutionStateStore) Clear(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(s.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
 type executionStateTracker struct {
+	// mu guards state, as turn snapshots are taken by the goroutine running
+	// the agent, while stream events are handled by another.
+	mu    sync.Mutex
 	store ExecutionStateStore
 	state WorkflowExecutionState
+	// usage, when set, is stored with the state.
+	usage *runUsageTracker
+	clock Clock
 }
 
 func newExecutionStateTracker(store ExecutionStateStore, sessionID, workflowName string) *executionStateTracker {
@@ -106,31 +334,88 @@ func newExecutionStateTracker(store ExecutionStateStore, sessionID, workflowName
 	}
 }
 
+// resumeExecutionStateTracker tracks the execution of a suspended run from
+// its stored state.
+func resumeExecutionStateTracker(store ExecutionStateStore, state WorkflowExecutionState) *executionStateTracker {
+	return &executionStateTracker{store: store, state: state}
+}
+
 func (t *executionStateTracker) OnRunStarted(ctx context.Context, query string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.state.Status = ExecutionStatusRunning
 	t.state.LastQuery = query
 	t.state.PendingApprovals = nil
+	t.state.ResolvedApprovals = nil
+	t.state.PendingInputs = nil
+	t.state.ProvidedInputs = nil
 	t.state.LastError = ""
 	t.state.FinalOutput = nil
-	t.state.UpdatedAt = time.Now().UTC()
-	return t.store.Save(ctx, t.state)
+	t.state.Checkpoint = nil
+	t.state.Snapshot = nil
+	t.state.GuardrailResults = nil
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// OnRunResumed marks a suspended run as running again. The resolved
+// approvals and provided inputs are discarded, as their responses were added
+// to the session.
+func (t *executionStateTracker) OnRunResumed(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.Status = ExecutionStatusRunning
+	t.state.ResolvedApprovals = nil
+	t.state.ProvidedInputs = nil
+	t.state.LastError = ""
+	t.state.Checkpoint = nil
+	t.state.Snapshot = nil
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// OnRunRecovered claims a running execution whose process crashed, to
+// continue it from its snapshot. Unlike other saves, conflicts are not
+// merged: they mean the execution was claimed by another process, or is
+// still running.
+func (t *executionStateTracker) OnRunRecovered(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.LastError = ""
+	t.state.UpdatedAt = t.clock.now()
+	if err := t.store.Save(ctx, t.state); err != nil {
+		return err
+	}
+	t.state.Version++
+	return nil
+}
+
+// OnTurnSnapshot records the snapshot of a turn about to start.
+func (t *executionStateTracker) OnTurnSnapshot(ctx context.Context, snapshot ExecutionSnapshot) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.Snapshot = &snapshot
+	t.state.UpdatedAt = snapshot.CreatedAt
+	return t.save(ctx)
 }
 
 func (t *executionStateTracker) OnStreamEvent(ctx context.Context, event agents.StreamEvent) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	switch ev := event.(type) {
 	case agents.AgentUpdatedStreamEvent:
 		if ev.NewAgent != nil {
 			t.state.LastAgent = ev.NewAgent.Name
-			t.state.UpdatedAt = time.Now().UTC()
-			return t.store.Save(ctx, t.state)
+			t.state.UpdatedAt = t.clock.now()
+			return t.save(ctx)
 		}
 	case agents.RunItemStreamEvent:
 		switch item := ev.Item.(type) {
 		case agents.MessageOutputItem:
 			if item.Agent != nil {
 				t.state.LastAgent = item.Agent.Name
-				t.state.UpdatedAt = time.Now().UTC()
-				return t.store.Save(ctx, t.state)
+				t.state.UpdatedAt = t.clock.now()
+				return t.save(ctx)
 			}
 		case agents.MCPApprovalRequestItem:
 			req := ApprovalRequestState{
@@ -139,13 +424,13 @@ func (t *executionStateTracker) OnStreamEvent(ctx context.Context, event agents.
 				ToolName:    item.RawItem.Name,
 				ServerLabel: item.RawItem.ServerLabel,
 				Arguments:   item.RawItem.Arguments,
-				CreatedAt:   time.Now().UTC(),
+				CreatedAt:   t.clock.now(),
 			}
 			t.state.LastAgent = req.AgentName
 			t.state.PendingApprovals = append(t.state.PendingApprovals, req)
 			t.state.Status = ExecutionStatusWaitingApproval
-			t.state.UpdatedAt = time.Now().UTC()
-			return t.store.Save(ctx, t.state)
+			t.state.UpdatedAt = t.clock.now()
+			return t.save(ctx)
 		case agents.MCPApprovalResponseItem:
 			id := item.RawItem.ApprovalRequestID
 			if id == "" {
@@ -159,37 +444,192 @@ func (t *executionStateTracker) OnStreamEvent(ctx context.Context, event agents.
 			}
 			if len(filtered) != len(t.state.PendingApprovals) {
 				t.state.PendingApprovals = append([]ApprovalRequestState(nil), filtered...)
-				if len(t.state.PendingApprovals) == 0 && t.state.Status == ExecutionStatusWaitingApproval {
-					t.state.Status = ExecutionStatusRunning
-				}
-				t.state.UpdatedAt = time.Now().UTC()
-				return t.store.Save(ctx, t.state)
+				t.state.Status = t.state.waitingStatus()
+				t.state.UpdatedAt = t.clock.now()
+				return t.save(ctx)
+			}
+		case agents.ToolCallOutputItem:
+			req, ok := item.Output.(InputRequestState)
+			if !ok {
+				break
 			}
+			req.CreatedAt = t.clock.now()
+			t.state.LastAgent = req.AgentName
+			t.state.PendingInputs = append(t.state.PendingInputs, req)
+			if t.state.Status != ExecutionStatusWaitingApproval {
+				t.state.Status = ExecutionStatusWaitingInput
+			}
+			t.state.UpdatedAt = t.clock.now()
+			return t.save(ctx)
 		}
 	}
 	return nil
 }
 
 func (t *executionStateTracker) OnRunCompleted(ctx context.Context, lastResponseID string, finalOutput any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.state.Status = ExecutionStatusCompleted
 	t.state.LastResponseID = lastResponseID
 	t.state.FinalOutput = finalOutput
 	t.state.PendingApprovals = nil
+	t.state.PendingInputs = nil
 	t.state.LastError = ""
-	t.state.UpdatedAt = time.Now().UTC()
-	return t.store.Save(ctx, t.state)
+	t.state.Checkpoint = nil
+	t.state.Snapshot = nil
+	t.state.UpdatedAt = t.clock.now()
+	// Conflicts are not merged: the run was resumed concurrently.
+	if err := t.store.Save(ctx, t.state); err != nil {
+		return err
+	}
+	t.state.Version++
+	return nil
+}
+
+// OnResumeRejected restores the checkpoint of a resumed run which could not
+// start, so that it can be resumed again.
+func (t *executionStateTracker) OnResumeRejected(ctx context.Context, suspended WorkflowExecutionState, err error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.Status = suspended.Status
+	t.state.Checkpoint = suspended.Checkpoint
+	t.state.ResolvedApprovals = suspended.ResolvedApprovals
+	t.state.ProvidedInputs = suspended.ProvidedInputs
+	t.state.Snapshot = suspended.Snapshot
+	t.state.LastError = err.Error()
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// OnGuardrailsEvaluated records the results of the guardrails evaluated by a
+// step of the run.
+func (t *executionStateTracker) OnGuardrailsEvaluated(ctx context.Context, results []GuardrailResultState) error {
+	if len(results) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.GuardrailResults = append(t.state.GuardrailResults, results...)
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// OnRunSuspended checkpoints a run which stopped on approval or input
+// requests.
+func (t *executionStateTracker) OnRunSuspended(ctx context.Context, checkpoint ExecutionCheckpoint) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.Status = ExecutionStatusWaitingApproval
+	if len(t.state.PendingApprovals) == 0 {
+		t.state.Status = ExecutionStatusWaitingInput
+	}
+	t.state.LastResponseID = checkpoint.LastResponseID
+	t.state.Checkpoint = &checkpoint
+	t.state.Snapshot = nil
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
 }
 
 func (t *executionStateTracker) OnRunFailed(ctx context.Context, err error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	if err == nil {
 		return nil
 	}
 	t.state.LastError = err.Error()
-	if len(t.state.PendingApprovals) > 0 {
+	t.state.Snapshot = nil
+	switch {
+	case len(t.state.PendingApprovals) > 0:
 		t.state.Status = ExecutionStatusWaitingApproval
-	} else {
+	case len(t.state.PendingInputs) > 0:
+		t.state.Status = ExecutionStatusWaitingInput
+	default:
 		t.state.Status = ExecutionStatusFailed
 	}
-	t.state.UpdatedAt = time.Now().UTC()
-	return t.store.Save(ctx, t.state)
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// OnRunTimedOut records a run canceled by its deadline. The state keeps the
+// progress tracked so far.
+func (t *executionStateTracker) OnRunTimedOut(ctx context.Context, err error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.LastError = err.Error()
+	t.state.Status = ExecutionStatusTimedOut
+	t.state.Snapshot = nil
+	t.state.UpdatedAt = t.clock.now()
+	return t.save(ctx)
+}
+
+// save stores the tracked state. Approvals may be resolved concurrently by
+// RunnerService.ResolveApproval, and inputs provided by
+// RunnerService.ProvideInput: on conflicts, the stored resolutions are merged
+// into the tracked state before saving again.
+//
+// The first save of a new tracker replaces the stored state of the previous
+// run of the session, if any, unless it is suspended.
+func (t *executionStateTracker) save(ctx context.Context) error {
+	if t.usage != nil {
+		t.state.Usage = t.usage.snapshot()
+	}
+	if t.state.Version == 0 {
+		stored, ok, err := t.store.Load(ctx, t.state.SessionID)
+		if err != nil {
+			return err
+		}
+		if ok && stored.Checkpoint != nil {
+			return fmt.Errorf("%w: session %q is %s", ErrExecutionActive, t.state.SessionID, stored.Status)
+		}
+		t.state.Version = stored.Version
+	}
+	for attempt := 1; ; attempt++ {
+		err := t.store.Save(ctx, t.state)
+		if err == nil {
+			t.state.Version++
+			return nil
+		}
+		if !errors.Is(err, ErrExecutionStateConflict) || attempt == maxStateSaveAttempts {
+			return err
+		}
+		stored, ok, err := t.store.Load(ctx, t.state.SessionID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			t.state.Version = 0
+			continue
+		}
+		t.mergeResolutions(stored)
+	}
+}
+
+func (t *executionStateTracker) mergeResolutions(stored WorkflowExecutionState) {
+	t.state.Version = stored.Version
+	t.state.ResolvedApprovals = stored.ResolvedApprovals
+	resolved := make(map[string]bool, len(stored.ResolvedApprovals))
+	for _, decision := range stored.ResolvedApprovals {
+		resolved[decision.RequestID] = true
+	}
+	var pending []ApprovalRequestState
+	for _, req := range t.state.PendingApprovals {
+		if !resolved[req.RequestID] {
+			pending = append(pending, req)
+		}
+	}
+	t.state.PendingApprovals = pending
+
+	t.state.ProvidedInputs = stored.ProvidedInputs
+	provided := make(map[string]bool, len(stored.ProvidedInputs))
+	for _, response := range stored.ProvidedInputs {
+		provided[response.RequestID] = true
+	}
+	var pendingInputs []InputRequestState
+	for _, req := range t.state.PendingInputs {
+		if !provided[req.RequestID] {
+			pendingInputs = append(pendingInputs, req)
+		}
+	}
+	t.state.PendingInputs = pendingInputs
+	t.state.Status = t.state.waitingStatus()
 }
@@ -0,0 +1,74 @@
+package workflowrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionStateStoreVersions(t *testing.T) {
+	stores := map[string]ExecutionStateStore{
+		"memory": NewInMemoryExecutionStateStore(),
+		"dir":    NewDirExecutionStateStore(t.TempDir()),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			load := func() WorkflowExecutionState {
+				state, ok, err := store.Load(ctx, "s1")
+				require.NoError(t, err)
+				require.True(t, ok)
+				return state
+			}
+
+			require.NoError(t, store.Save(ctx, WorkflowExecutionState{SessionID: "s1", Status: ExecutionStatusRunning}))
+			state := load()
+			assert.EqualValues(t, 1, state.Version)
+			require.NoError(t, store.Save(ctx, state))
+			assert.EqualValues(t, 2, load().Version)
+
+			// Stale versions conflict.
+			assert.ErrorIs(t, store.Save(ctx, state), ErrExecutionStateConflict)
+
+			// Unconditional saves keep incrementing the stored version.
+			require.NoError(t, store.Save(ctx, WorkflowExecutionState{SessionID: "s1", Status: ExecutionStatusCompleted}))
+			state = load()
+			assert.EqualValues(t, 3, state.Version)
+			assert.Equal(t, ExecutionStatusCompleted, state.Status)
+
+			// Suspended states are only replaced by conditional saves.
+			state.Status = ExecutionStatusWaitingApproval
+			state.Checkpoint = &ExecutionCheckpoint{Agent: "a"}
+			require.NoError(t, store.Save(ctx, state))
+			assert.ErrorIs(t, store.Save(ctx, WorkflowExecutionState{SessionID: "s1"}), ErrExecutionStateConflict)
+			state = load()
+			assert.EqualValues(t, 4, state.Version)
+			assert.NotNil(t, state.Checkpoint)
+			state.Checkpoint = nil
+			require.NoError(t, store.Save(ctx, state))
+			assert.EqualValues(t, 5, load().Version)
+		})
+	}
+}
+
+func TestExecutionStateTrackerDoesNotReplaceSuspendedState(t *testing.T) {
+	ctx := t.Context()
+	store := NewInMemoryExecutionStateStore()
+	require.NoError(t, store.Save(ctx, WorkflowExecutionState{SessionID: "s1", Status: ExecutionStatusCompleted}))
+
+	tracker := newExecutionStateTracker(store, "s1", "wf")
+	require.NoError(t, tracker.OnRunStarted(ctx, "hello"))
+	require.NoError(t, tracker.OnRunSuspended(ctx, ExecutionCheckpoint{Agent: "a"}))
+	state, _, err := store.Load(ctx, "s1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, state.Version)
+	assert.Equal(t, tracker.state.Version, state.Version)
+
+	err = newExecutionStateTracker(store, "s1", "wf").OnRunStarted(ctx, "again")
+	assert.ErrorIs(t, err, ErrExecutionActive)
+	state, _, err = store.Load(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, ExecutionStatusWaitingInput, state.Status)
+	assert.Equal(t, "hello", state.LastQuery)
+}
@@ -0,0 +1,133 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3"
+)
+
+// Defaults of AgentRetryPolicy.
+const (
+	DefaultStepRetryMaxAttempts    = 3
+	DefaultStepRetryInitialBackoff = time.Second
+	DefaultStepRetryMaxBackoff     = 30 * time.Second
+	DefaultStepRetryBackoffFactor  = 2.0
+)
+
+// Error classes which can be declared in AgentRetryPolicy.RetryOn.
+const (
+	// RetryOnModelError retries the model API errors which may be transient:
+	// server errors, timeouts, rate limits and connection failures.
+	RetryOnModelError = "model_error"
+	// RetryOnModelBehavior retries the invalid responses of the model, such
+	// as calls to unknown tools or outputs not matching the output type.
+	RetryOnModelBehavior = "model_behavior"
+	// RetryOnGuardrail retries the steps whose input or output guardrail
+	// tripwire was triggered.
+	RetryOnGuardrail = "guardrail"
+	// RetryOnMaxTurns retries the steps exceeding the max turns.
+	RetryOnMaxTurns = "max_turns"
+)
+
+var retryOnClasses = []string{RetryOnModelError, RetryOnModelBehavior, RetryOnGuardrail, RetryOnMaxTurns}
+
+// stepErrorClass returns the retry class of the error of a step, or "" when
+// it is not retryable.
+func stepErrorClass(err error) string {
+	var (
+		inputTripwire  agents.InputGuardrailTripwireTriggeredError
+		outputTripwire agents.OutputGuardrailTripwireTriggeredError
+		maxTurns       agents.MaxTurnsExceededError
+		behavior       agents.ModelBehaviorError
+		rateLimit      agents.ModelRateLimitError
+		apiErr         *openai.Error
+		netErr         net.Error
+	)
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ""
+	case errors.As(err, &inputTripwire), errors.As(err, &outputTripwire):
+		return RetryOnGuardrail
+	case errors.As(err, &maxTurns):
+		return RetryOnMaxTurns
+	case errors.As(err, &behavior):
+		return RetryOnModelBehavior
+	case errors.As(err, &rateLimit):
+		// An exhausted quota stays so until it is raised.
+		if rateLimit.QuotaExceeded {
+			return ""
+		}
+		return RetryOnModelError
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+			return RetryOnModelError
+		}
+		if apiErr.StatusCode >= 500 {
+			return RetryOnModelError
+		}
+	case errors.As(err, &netErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return RetryOnModelError
+	}
+	return ""
+}
+
+// retryClass returns the class of the error of a failed attempt when the
+// policy retries it, or "".
+func (p *AgentRetryPolicy) retryClass(attempt int, err error) string {
+	if p == nil {
+		return ""
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultStepRetryMaxAttempts
+	}
+	class := stepErrorClass(err)
+	if attempt >= maxAttempts || class == "" {
+		return ""
+	}
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{RetryOnModelError}
+	}
+	if !slices.Contains(retryOn, class) {
+		return ""
+	}
+	return class
+}
+
+// backoff returns the delay before the given retry, starting from 1.
+func (p *AgentRetryPolicy) backoff(retry int) time.Duration {
+	initial := DefaultStepRetryInitialBackoff
+	if p.InitialBackoffMS > 0 {
+		initial = time.Duration(p.InitialBackoffMS) * time.Millisecond
+	}
+	maxBackoff := DefaultStepRetryMaxBackoff
+	if p.MaxBackoffMS > 0 {
+		maxBackoff = time.Duration(p.MaxBackoffMS) * time.Millisecond
+	}
+	factor := DefaultStepRetryBackoffFactor
+	if p.BackoffFactor >= 1 {
+		factor = p.BackoffFactor
+	}
+	return exponentialBackoff(retry, initial, maxBackoff, factor)
+}
+
+// retryDelay returns the delay before retrying the given failed attempt,
+// starting from 1: the backoff of the policy, or the time to wait asked by
+// the provider of a rate limited model, if longer.
+func (p *AgentRetryPolicy) retryDelay(attempt int, err error) time.Duration {
+	delay := p.backoff(attempt)
+	var rateLimit agents.ModelRateLimitError
+	if errors.As(err, &rateLimit) {
+		delay = max(delay, rateLimit.RetryAfter)
+	}
+	return delay
+}
@@ -0,0 +1,226 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiError returns the error of a model API answering with the given status.
+func apiError(statusCode int) *openai.Error {
+	req := httptest.NewRequest(http.MethodPost, "https://api.openai.com/v1/responses", nil)
+	return &openai.Error{
+		StatusCode: statusCode,
+		Request:    req,
+		Response:   &http.Response{StatusCode: statusCode, Request: req},
+	}
+}
+
+func TestStepErrorClass(t *testing.T) {
+	rateLimit := agents.ModelRateLimitError{AgentsError: agents.NewAgentsError("rate limited")}
+	quota := rateLimit
+	quota.QuotaExceeded = true
+
+	tests := []struct {
+		name  string
+		err   error
+		class string
+	}{
+		{"nil", nil, ""},
+		{"canceled", fmt.Errorf("step: %w", context.Canceled), ""},
+		{"deadline exceeded", context.DeadlineExceeded, ""},
+		{"input guardrail", agents.NewInputGuardrailTripwireTriggeredError(agents.InputGuardrailResult{}), RetryOnGuardrail},
+		{"output guardrail", agents.NewOutputGuardrailTripwireTriggeredError(agents.OutputGuardrailResult{}), RetryOnGuardrail},
+		{"max turns", agents.NewMaxTurnsExceededError("too many turns"), RetryOnMaxTurns},
+		{"model behavior", agents.NewModelBehaviorError("unknown tool"), RetryOnModelBehavior},
+		{"rate limit", rateLimit, RetryOnModelError},
+		{"exhausted quota", quota, ""},
+		{"server error", apiError(http.StatusBadGateway), RetryOnModelError},
+		{"request timeout", apiError(http.StatusRequestTimeout), RetryOnModelError},
+		{"conflict", apiError(http.StatusConflict), RetryOnModelError},
+		{"too many requests", apiError(http.StatusTooManyRequests), RetryOnModelError},
+		{"bad request", apiError(http.StatusBadRequest), ""},
+		{"unauthorized", apiError(http.StatusUnauthorized), ""},
+		{"connection failure", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, RetryOnModelError},
+		{"truncated response", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), RetryOnModelError},
+		{"other", errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.class, stepErrorClass(tt.err))
+		})
+	}
+}
+
+func TestAgentRetryPolicyRetryClass(t *testing.T) {
+	modelErr := apiError(http.StatusServiceUnavailable)
+	behaviorErr := agents.NewModelBehaviorError("unknown tool")
+
+	var none *AgentRetryPolicy
+	assert.Empty(t, none.retryClass(1, modelErr))
+
+	// Model errors are retried up to three attempts by default.
+	policy := &AgentRetryPolicy{}
+	assert.Equal(t, RetryOnModelError, policy.retryClass(1, modelErr))
+	assert.Equal(t, RetryOnModelError, policy.retryClass(2, modelErr))
+	assert.Empty(t, policy.retryClass(3, modelErr))
+	assert.Empty(t, policy.retryClass(1, behaviorErr))
+
+	policy = &AgentRetryPolicy{MaxAttempts: 5, RetryOn: []string{RetryOnModelBehavior}}
+	assert.Equal(t, RetryOnModelBehavior, policy.retryClass(4, behaviorErr))
+	assert.Empty(t, policy.retryClass(5, behaviorErr))
+	assert.Empty(t, policy.retryClass(1, modelErr))
+}
+
+func TestAgentRetryPolicyRetryDelay(t *testing.T) {
+	policy := &AgentRetryPolicy{InitialBackoffMS: 100, MaxBackoffMS: 1000}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: time.Second} {
+		delay := policy.retryDelay(attempt, errors.New("boom"))
+		assert.LessOrEqual(t, delay, want)
+		assert.GreaterOrEqual(t, delay, want*8/10)
+	}
+
+	// Rate limited providers may ask to wait longer.
+	rateLimit := agents.ModelRateLimitError{AgentsError: agents.NewAgentsError("rate limited"), RetryAfter: time.Minute}
+	assert.Equal(t, time.Minute, policy.retryDelay(1, rateLimit))
+	rateLimit.RetryAfter = time.Millisecond
+	assert.LessOrEqual(t, policy.retryDelay(1, rateLimit), 100*time.Millisecond)
+	assert.Greater(t, policy.retryDelay(1, rateLimit), time.Millisecond)
+}
+
+// retryPublisher records the run.retry events, calling onRetry, if set, on
+// each of them.
+type retryPublisher struct {
+	onRetry func()
+
+	mu      sync.Mutex
+	retries []RunRetryPayload
+}
+
+func (p *retryPublisher) Publish(_ context.Context, event CallbackEvent) error {
+	if event.Type != CallbackEventRunRetry {
+		return nil
+	}
+	p.mu.Lock()
+	p.retries = append(p.retries, event.Payload.(RunRetryPayload))
+	p.mu.Unlock()
+	if p.onRetry != nil {
+		p.onRetry()
+	}
+	return nil
+}
+
+func TestStepRetry(t *testing.T) {
+	unavailable := apiError(http.StatusServiceUnavailable)
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Error: unavailable},
+		{Error: unavailable},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+	publisher := &retryPublisher{}
+	service := newTestService(t, model, publisher)
+	req := testRequest("s1")
+	req.Workflow.Agents[0].Retry = &AgentRetryPolicy{MaxAttempts: 3, InitialBackoffMS: 1}
+
+	task, err := service.Execute(t.Context(), req)
+	require.NoError(t, err)
+	result := task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, "done", result.Value.FinalOutput)
+	require.Len(t, publisher.retries, 2)
+	for i, retry := range publisher.retries {
+		assert.Equal(t, "assistant", retry.Agent)
+		assert.Equal(t, i+2, retry.Attempt)
+		assert.Equal(t, RetryOnModelError, retry.ErrorClass)
+	}
+}
+
+func TestStepRetryGivesUp(t *testing.T) {
+	unavailable := apiError(http.StatusServiceUnavailable)
+	tests := []struct {
+		name    string
+		outputs []agentstesting.FakeModelTurnOutput
+		policy  *AgentRetryPolicy
+		retries int
+	}{
+		{
+			name:    "after max attempts",
+			outputs: []agentstesting.FakeModelTurnOutput{{Error: unavailable}, {Error: unavailable}, {Error: unavailable}},
+			policy:  &AgentRetryPolicy{MaxAttempts: 2, InitialBackoffMS: 1},
+			retries: 1,
+		},
+		{
+			name:    "without policy",
+			outputs: []agentstesting.FakeModelTurnOutput{{Error: unavailable}},
+			retries: 0,
+		},
+		{
+			name:    "on errors of other classes",
+			outputs: []agentstesting.FakeModelTurnOutput{{Error: unavailable}},
+			policy:  &AgentRetryPolicy{InitialBackoffMS: 1, RetryOn: []string{RetryOnModelBehavior}},
+			retries: 0,
+		},
+		{
+			name:    "on permanent errors",
+			outputs: []agentstesting.FakeModelTurnOutput{{Error: apiError(http.StatusBadRequest)}},
+			policy:  &AgentRetryPolicy{InitialBackoffMS: 1},
+			retries: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := agentstesting.NewFakeModel(false, nil)
+			model.AddMultipleTurnOutputs(tt.outputs)
+			publisher := &retryPublisher{}
+			service := newTestService(t, model, publisher)
+			req := testRequest("s1")
+			req.Workflow.Agents[0].Retry = tt.policy
+
+			task, err := service.Execute(t.Context(), req)
+			require.NoError(t, err)
+			result := task.Await()
+			var apiErr *openai.Error
+			assert.ErrorAs(t, result.Error, &apiErr)
+			assert.Len(t, publisher.retries, tt.retries)
+		})
+	}
+}
+
+func TestStepRetryBackoffIsCanceled(t *testing.T) {
+	unavailable := apiError(http.StatusServiceUnavailable)
+	model := agentstesting.NewFakeModel(false, nil)
+	model.AddMultipleTurnOutputs([]agentstesting.FakeModelTurnOutput{
+		{Error: unavailable},
+		{Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("done")}},
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	publisher := &retryPublisher{onRetry: cancel}
+	service := newTestService(t, model, publisher)
+	req := testRequest("s1")
+	req.Workflow.Agents[0].Retry = &AgentRetryPolicy{InitialBackoffMS: int(time.Hour / time.Millisecond)}
+
+	start := time.Now()
+	task, err := service.Execute(ctx, req)
+	require.NoError(t, err)
+	result := task.Await()
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.ErrorIs(t, result.Error, context.Canceled)
+	var apiErr *openai.Error
+	assert.ErrorAs(t, result.Error, &apiErr)
+	assert.Len(t, publisher.retries, 1)
+}
@@ -0,0 +1,146 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the functions of instruction templates and route
+// expressions. Builder.TemplateFuncs adds to them in instruction templates.
+var templateFuncs = template.FuncMap{
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"trim":      strings.TrimSpace,
+	"replace":   func(from, to, s string) string { return strings.ReplaceAll(s, from, to) },
+	"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+	"join":      templateJoin,
+	"truncate":  templateTruncate,
+	"default":   templateDefault,
+	"toJson":    templateToJSON,
+	"toPrettyJson": func(v any) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	"now":  func() time.Time { return time.Now().UTC() },
+	"date": templateDate,
+}
+
+// templateJoin joins the items of a list, e.g. {{ .context.tags | join ", " }}.
+func templateJoin(sep string, list any) (string, error) {
+	if list == nil {
+		return "", nil
+	}
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: %T is not a list", list)
+	}
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = outputText(v.Index(i).Interface())
+	}
+	return strings.Join(items, sep), nil
+}
+
+// templateTruncate shortens a text to n runes.
+func templateTruncate(n int, s string) string {
+	if runes := []rune(s); len(runes) > n && n >= 0 {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// templateDefault returns fallback when value is missing or empty, e.g.
+// {{ .metadata.region | default "eu" }}.
+func templateDefault(fallback, value any) any {
+	if value == nil {
+		return fallback
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return fallback
+		}
+	}
+	return value
+}
+
+func templateToJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// templateDate formats a time.Time, an RFC 3339 string or Unix seconds with
+// a Go layout, e.g. {{ now | date "2006-01-02" }}.
+func templateDate(layout string, value any) (string, error) {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("date: %w", err)
+		}
+		t = parsed
+	case float64:
+		t = time.Unix(int64(v), 0).UTC()
+	case int:
+		t = time.Unix(int64(v), 0).UTC()
+	case int64:
+		t = time.Unix(v, 0).UTC()
+	default:
+		return "", fmt.Errorf("date: unsupported value %T", value)
+	}
+	return t.Format(layout), nil
+}
+
+// WithTemplateFuncs adds functions to the instruction templates, overriding
+// the builtin ones with the same names, and returns the builder.
+func (b *Builder) WithTemplateFuncs(funcs template.FuncMap) *Builder {
+	if b.TemplateFuncs == nil {
+		b.TemplateFuncs = make(template.FuncMap, len(funcs))
+	}
+	maps.Copy(b.TemplateFuncs, funcs)
+	return b
+}
+
+// renderInstructions renders the instructions of agents declaring
+// instructions_template.
+func (b *Builder) renderInstructions(req WorkflowRequest, decl AgentDeclaration) (string, error) {
+	if !decl.InstructionsTemplate {
+		return decl.Instructions, nil
+	}
+	funcs := maps.Clone(templateFuncs)
+	maps.Copy(funcs, b.TemplateFuncs)
+	tmpl, err := template.New(decl.Name).
+		Funcs(funcs).
+		Option("missingkey=zero").
+		Parse(decl.Instructions)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, map[string]any{
+		"agent":      decl.Name,
+		"workflow":   req.Workflow.Name,
+		"session_id": req.Session.SessionID,
+		"user_id":    req.Session.Credentials.UserID,
+		"account_id": req.Session.Credentials.AccountID,
+		"metadata":   normalizeJSONMap(req.Metadata),
+		"context":    normalizeJSONMap(req.Context),
+		"inputs":     normalizeJSONMap(req.Inputs),
+	})
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,222 @@
+package workflowrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nlpodyssey/openai-agents-go/memory"
+)
+
+// TenantLimits bounds the session resources a single account may consume.
+// Zero values mean unlimited.
+type TenantLimits struct {
+	MaxSessions     int
+	MaxStoredTokens int
+}
+
+// TenantQuotaError is returned when an account exceeds one of its TenantLimits.
+type TenantQuotaError struct {
+	AccountID string
+	Resource  string
+	Limit     int
+}
+
+func (e *TenantQuotaError) Error() string {
+	return fmt.Sprintf("account %q exceeded its %s quota (limit %d)", e.AccountID, e.Resource, e.Limit)
+}
+
+// TenantQuotaTracker records per-account session usage and enforces limits.
+type TenantQuotaTracker interface {
+	// AcquireSession registers sessionID for accountID, already holding
+	// storedTokens, e.g. stored by earlier processes. Sessions already
+	// registered are always accepted and keep their count.
+	AcquireSession(ctx context.Context, accountID, sessionID string, storedTokens int) error
+	// AddTokens adjusts the stored token count of a session; delta may be negative.
+	AddTokens(ctx context.Context, accountID, sessionID string, delta int) error
+	// ReleaseSession forgets a session and the tokens it was holding.
+	ReleaseSession(ctx context.Context, accountID, sessionID string) error
+}
+
+// InMemoryTenantQuotaTracker tracks usage for the lifetime of the process.
+type InMemoryTenantQuotaTracker struct {
+	// Limits applied to accounts without an entry in AccountLimits.
+	Limits        TenantLimits
+	AccountLimits map[string]TenantLimits
+
+	mu       sync.Mutex
+	accounts map[string]map[string]int
+}
+
+// NewInMemoryTenantQuotaTracker returns a tracker applying limits to every account.
+func NewInMemoryTenantQuotaTracker(limits TenantLimits) *InMemoryTenantQuotaTracker {
+	return &InMemoryTenantQuotaTracker{
+		Limits:   limits,
+		accounts: make(map[string]map[string]int),
+	}
+}
+
+func (t *InMemoryTenantQuotaTracker) limitsFor(accountID string) TenantLimits {
+	if limits, ok := t.AccountLimits[accountID]; ok {
+		return limits
+	}
+	return t.Limits
+}
+
+func (t *InMemoryTenantQuotaTracker) AcquireSession(_ context.Context, accountID, sessionID string, storedTokens int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.accounts == nil {
+		t.accounts = make(map[string]map[string]int)
+	}
+	sessions := t.accounts[accountID]
+	if _, ok := sessions[sessionID]; ok {
+		return nil
+	}
+	limits := t.limitsFor(accountID)
+	if limits.MaxSessions > 0 && len(sessions) >= limits.MaxSessions {
+		return &TenantQuotaError{AccountID: accountID, Resource: "sessions", Limit: limits.MaxSessions}
+	}
+	if limits.MaxStoredTokens > 0 && storedTokens > 0 && totalTokens(sessions)+storedTokens > limits.MaxStoredTokens {
+		return &TenantQuotaError{AccountID: accountID, Resource: "stored tokens", Limit: limits.MaxStoredTokens}
+	}
+	if sessions == nil {
+		sessions = make(map[string]int)
+		t.accounts[accountID] = sessions
+	}
+	sessions[sessionID] = max(0, storedTokens)
+	return nil
+}
+
+func (t *InMemoryTenantQuotaTracker) AddTokens(_ context.Context, accountID, sessionID string, delta int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sessions := t.accounts[accountID]
+	if sessions == nil {
+		return fmt.Errorf("session %q not acquired for account %q", sessionID, accountID)
+	}
+	if limits := t.limitsFor(accountID); delta > 0 && limits.MaxStoredTokens > 0 {
+		if totalTokens(sessions)+delta > limits.MaxStoredTokens {
+			return &TenantQuotaError{AccountID: accountID, Resource: "stored tokens", Limit: limits.MaxStoredTokens}
+		}
+	}
+	sessions[sessionID] = max(0, sessions[sessionID]+delta)
+	return nil
+}
+
+func (t *InMemoryTenantQuotaTracker) ReleaseSession(_ context.Context, accountID, sessionID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.accounts[accountID], sessionID)
+	return nil
+}
+
+func totalTokens(sessions map[string]int) int {
+	total := 0
+	for _, tokens := range sessions {
+		total += tokens
+	}
+	return total
+}
+
+// TenantSessionPurger purges the expired sessions of Purger, releasing the
+// quota of the namespaced ones from Quotas, so that the sessions deleted by
+// the retention GC stop counting against the quota of their account. It is
+// passed to memory.RunSessionGC in place of Purger.
+type TenantSessionPurger struct {
+	Purger memory.SessionKeyPurger
+	Quotas TenantQuotaTracker
+}
+
+func (p TenantSessionPurger) PurgeExpiredSessions(ctx context.Context, policy memory.RetentionPolicy) (int64, error) {
+	keys, err := p.Purger.PurgeExpiredSessionKeys(ctx, policy)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, key := range keys {
+		accountID, sessionID, ok := memory.SplitNamespacedSessionKey(key)
+		if !ok {
+			continue
+		}
+		if err := p.Quotas.ReleaseSession(ctx, accountID, sessionID); err != nil {
+			errs = append(errs, fmt.Errorf("release session %q of account %q: %w", sessionID, accountID, err))
+		}
+	}
+	return int64(len(keys)), errors.Join(errs...)
+}
+
+// acquireTenantSession registers the session with Builder.TenantQuotas,
+// counting the tokens it already stores, and wraps it to report the tokens
+// stored by the run. A session forked by the build is cleared when its
+// quota is refused.
+func (b *Builder) acquireTenantSession(ctx context.Context, decl SessionDeclaration, session memory.Session, forked bool) (memory.Session, error) {
+	items, err := session.GetItems(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read session: %w", err)
+	}
+	tokens := 0
+	for _, item := range items {
+		tokens += memory.EstimateTokens(item)
+	}
+	accountID := decl.Credentials.AccountID
+	if err := b.TenantQuotas.AcquireSession(ctx, accountID, decl.SessionID, tokens); err != nil {
+		if forked {
+			_ = session.ClearSession(ctx)
+		}
+		return nil, err
+	}
+	return &tenantSession{Session: session, tracker: b.TenantQuotas, accountID: accountID}, nil
+}
+
+// tenantSession reports stored tokens of the wrapped session to a TenantQuotaTracker.
+type tenantSession struct {
+	memory.Session
+	tracker   TenantQuotaTracker
+	accountID string
+}
+
+func (s *tenantSession) AddItems(ctx context.Context, items []memory.TResponseInputItem) error {
+	tokens := 0
+	for _, item := range items {
+		tokens += memory.EstimateTokens(item)
+	}
+	sessionID := s.SessionID(ctx)
+	if err := s.tracker.AddTokens(ctx, s.accountID, sessionID, tokens); err != nil {
+		return err
+	}
+	if err := s.Session.AddItems(ctx, items); err != nil {
+		_ = s.tracker.AddTokens(ctx, s.accountID, sessionID, -tokens)
+		return err
+	}
+	return nil
+}
+
+func (s *tenantSession) PopItem(ctx context.Context) (*memory.TResponseInputItem, error) {
+	item, err := s.Session.PopItem(ctx)
+	if err != nil || item == nil {
+		return item, err
+	}
+	return item, s.tracker.AddTokens(ctx, s.accountID, s.SessionID(ctx), -memory.EstimateTokens(*item))
+}
+
+func (s *tenantSession) ClearSession(ctx context.Context) error {
+	if err := s.Session.ClearSession(ctx); err != nil {
+		return err
+	}
+	// Release the stored tokens but keep the session registered, since it
+	// remains in use for the rest of the run.
+	sessionID := s.SessionID(ctx)
+	if err := s.tracker.ReleaseSession(ctx, s.accountID, sessionID); err != nil {
+		return err
+	}
+	return s.tracker.AcquireSession(ctx, s.accountID, sessionID, 0)
+}
+
+func (s *tenantSession) Close() error {
+	if closer, ok := s.Session.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
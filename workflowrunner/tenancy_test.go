@@ -0,0 +1,110 @@
+package workflowrunner
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/nlpodyssey/openai-agents-go/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTenantQuotaTracker(t *testing.T) {
+	ctx := t.Context()
+	tracker := NewInMemoryTenantQuotaTracker(TenantLimits{MaxSessions: 2, MaxStoredTokens: 100})
+
+	// Stored tokens count towards the quota.
+	require.NoError(t, tracker.AcquireSession(ctx, "a", "s1", 80))
+	var quotaErr *TenantQuotaError
+	require.ErrorAs(t, tracker.AcquireSession(ctx, "a", "s2", 30), &quotaErr)
+	assert.Equal(t, "stored tokens", quotaErr.Resource)
+	assert.ErrorAs(t, tracker.AddTokens(ctx, "a", "s1", 30), &quotaErr)
+
+	// Registered sessions are accepted again, keeping their count.
+	require.NoError(t, tracker.AcquireSession(ctx, "a", "s1", 0))
+	assert.ErrorAs(t, tracker.AddTokens(ctx, "a", "s1", 30), &quotaErr)
+
+	require.NoError(t, tracker.AcquireSession(ctx, "a", "s2", 20))
+	require.ErrorAs(t, tracker.AcquireSession(ctx, "a", "s3", 0), &quotaErr)
+	assert.Equal(t, "sessions", quotaErr.Resource)
+
+	// Other accounts have their own quota.
+	require.NoError(t, tracker.AcquireSession(ctx, "b", "s1", 0))
+}
+
+func TestBuildAcquiresTenantQuotaLast(t *testing.T) {
+	ctx := t.Context()
+	tracker := NewInMemoryTenantQuotaTracker(TenantLimits{MaxSessions: 1, MaxStoredTokens: 1000})
+	builder := newTestBuilder(t)
+	builder.TenantQuotas = tracker
+	req := func(sessionID string) WorkflowRequest {
+		return WorkflowRequest{
+			Query: "hello",
+			Session: SessionDeclaration{
+				SessionID:   sessionID,
+				Credentials: CredentialDeclaration{UserID: "u1", AccountID: "a1"},
+			},
+			Callback: CallbackDeclaration{Mode: "stdout"},
+			Workflow: WorkflowDeclaration{
+				Name:          "wf",
+				StartingAgent: "assistant",
+				Agents:        []AgentDeclaration{{Name: "assistant", Instructions: "Help."}},
+			},
+		}
+	}
+
+	// A failed build holds no quota.
+	failing := req("s1")
+	failing.Session.LongTermMemory = &LongTermMemoryDeclaration{TopK: 3}
+	_, err := builder.Build(ctx, failing)
+	require.ErrorContains(t, err, "long-term memory")
+
+	// The tokens already stored by the session are counted.
+	result, err := builder.Build(ctx, req("s2"))
+	require.NoError(t, err)
+	require.NoError(t, result.Session.AddItems(ctx, []memory.TResponseInputItem{agentstesting.GetTextInputItem("hello there")}))
+	closeSession(result.Session)
+
+	restarted := NewInMemoryTenantQuotaTracker(TenantLimits{MaxStoredTokens: 1})
+	builder.TenantQuotas = restarted
+	_, err = builder.Build(ctx, req("s2"))
+	var quotaErr *TenantQuotaError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, "stored tokens", quotaErr.Resource)
+}
+
+func TestTenantSessionPurgerReleasesQuota(t *testing.T) {
+	ctx := t.Context()
+	tracker := NewInMemoryTenantQuotaTracker(TenantLimits{MaxSessions: 1})
+	dbPath := filepath.Join(t.TempDir(), "sessions.db")
+	session, err := memory.NewSQLiteSession(ctx, memory.SQLiteSessionParams{
+		SessionID:        "s1",
+		Namespace:        "a/1",
+		DBDataSourceName: dbPath,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, session.Close()) })
+	require.NoError(t, session.AddItems(ctx, []memory.TResponseInputItem{agentstesting.GetTextInputItem("hello")}))
+	require.NoError(t, tracker.AcquireSession(ctx, "a/1", "s1", 0))
+	var quotaErr *TenantQuotaError
+	require.ErrorAs(t, tracker.AcquireSession(ctx, "a/1", "s2", 0), &quotaErr)
+
+	purger := TenantSessionPurger{Purger: session, Quotas: tracker}
+	deleted, err := purger.PurgeExpiredSessions(ctx, memory.RetentionPolicy{MaxIdle: time.Hour})
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	require.ErrorAs(t, tracker.AcquireSession(ctx, "a/1", "s2", 0), &quotaErr)
+
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	_, err = db.ExecContext(ctx, `UPDATE agent_sessions SET updated_at = datetime('now', '-2 hours')`)
+	require.NoError(t, err)
+	deleted, err = purger.PurgeExpiredSessions(ctx, memory.RetentionPolicy{MaxIdle: time.Hour})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.NoError(t, tracker.AcquireSession(ctx, "a/1", "s2", 0))
+}
@@ -0,0 +1,56 @@
+package workflowrunner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+)
+
+// Tool use behavior modes which can be declared in
+// ToolUseBehaviorDeclaration.Mode.
+const (
+	ToolUseRunLLMAgain     = "run_llm_again"
+	ToolUseStopOnFirstTool = "stop_on_first_tool"
+	ToolUseStopAtTools     = "stop_at_tools"
+	ToolUseCustom          = "custom"
+)
+
+// ToolUseBehaviorHandler decides whether the results of the function tools
+// called in a turn are the final output of the agent, as
+// agents.ToolsToFinalOutputFunction. Config is the configuration declared
+// with the handler reference.
+type ToolUseBehaviorHandler func(ctx context.Context, config map[string]any, results []agents.FunctionToolResult) (agents.ToolsToFinalOutputResult, error)
+
+// WithToolUseBehaviorHandler registers a handler which agents reference with
+// the custom tool_use_behavior mode, and returns the builder.
+func (b *Builder) WithToolUseBehaviorHandler(name string, handler ToolUseBehaviorHandler) *Builder {
+	if b.ToolUseBehaviorHandlers == nil {
+		b.ToolUseBehaviorHandlers = make(map[string]ToolUseBehaviorHandler)
+	}
+	b.ToolUseBehaviorHandlers[name] = handler
+	return b
+}
+
+func (b *Builder) buildToolUseBehavior(decl ToolUseBehaviorDeclaration) (agents.ToolUseBehavior, error) {
+	switch decl.Mode {
+	case "", ToolUseRunLLMAgain:
+		return agents.RunLLMAgain(), nil
+	case ToolUseStopOnFirstTool:
+		return agents.StopOnFirstTool(), nil
+	case ToolUseStopAtTools:
+		return agents.StopAtTools(decl.ToolNames...), nil
+	case ToolUseCustom:
+		handler, ok := b.ToolUseBehaviorHandlers[decl.Handler]
+		if !ok {
+			return nil, fmt.Errorf("handler %q not registered", decl.Handler)
+		}
+		config := maps.Clone(decl.Config)
+		return agents.ToolsToFinalOutputFunction(func(ctx context.Context, results []agents.FunctionToolResult) (agents.ToolsToFinalOutputResult, error) {
+			return handler(ctx, config, results)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported mode %q", decl.Mode)
+	}
+}
@@ -9,34 +9,116 @@ import (
 
 // WorkflowRequest represents the top-level payload describing a workflow run.
 type WorkflowRequest struct {
-	Query    string              `json:"query"`
+	// Version of the manifest format, see CurrentManifestVersion.
+	Version string `json:"version,omitempty" jsonschema:"enum=v2"`
+	// Query of the run; required unless Batch is set.
+	Query string `json:"query,omitempty" jsonschema:"minLength=1"`
+	// Batch runs the workflow once for each of its items instead of the
+	// query, see RunnerService.ExecuteBatch.
+	Batch    *BatchDeclaration   `json:"batch,omitempty"`
 	Session  SessionDeclaration  `json:"session"`
 	Callback CallbackDeclaration `json:"callback"`
-	Workflow WorkflowDeclaration `json:"workflow"`
-	Metadata map[string]any      `json:"metadata,omitempty"`
-	Context  map[string]any      `json:"context,omitempty"`
+	Workflow WorkflowDeclaration `json:"workflow,omitempty"`
+	// WorkflowRef references a workflow of Builder.WorkflowRegistry instead
+	// of declaring it, as "name@version", or "name" for its latest version.
+	WorkflowRef string         `json:"workflow_ref,omitempty" jsonschema:"pattern=^[A-Za-z0-9][A-Za-z0-9_.-]*(@[A-Za-z0-9][A-Za-z0-9_.-]*)?$"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Context     map[string]any `json:"context,omitempty"`
+	// Inputs are the parameters of the workflow, validated against its
+	// declared inputs.
+	Inputs map[string]any `json:"inputs,omitempty"`
+	// IncludeItems adds the items generated by the run, serialized with
+	// SerializeRunItems, to its RunSummary and run.completed callback.
+	IncludeItems bool `json:"include_items,omitempty"`
 }
 
 // SessionDeclaration carries caller-provided state and execution limits.
 type SessionDeclaration struct {
-	SessionID   string                `json:"session_id"`
-	HistorySize int                   `json:"history_size,omitempty"`
-	MaxTurns    int                   `json:"max_turns,omitempty"`
-	Credentials CredentialDeclaration `json:"credentials"`
+	SessionID          string                     `json:"session_id" jsonschema:"minLength=1"`
+	HistorySize        int                        `json:"history_size,omitempty" jsonschema:"minimum=0"`
+	HistoryTokenBudget int                        `json:"history_token_budget,omitempty" jsonschema:"minimum=0"`
+	MaxTurns           int                        `json:"max_turns,omitempty" jsonschema:"minimum=0"`
+	ForkFrom           *SessionForkDeclaration    `json:"fork_from,omitempty"`
+	LongTermMemory     *LongTermMemoryDeclaration `json:"long_term_memory,omitempty"`
+	Credentials        CredentialDeclaration      `json:"credentials"`
+	// DeadlineSeconds bounds the wall-clock time of each run, or of each
+	// resumption of a suspended run.
+	DeadlineSeconds int `json:"deadline_seconds,omitempty" jsonschema:"minimum=0"`
+	// Store selects the session factory of Builder.SessionFactories, e.g.
+	// "sqlite" or "postgres"; Builder.SessionFactory is used when empty.
+	Store       string              `json:"store,omitempty"`
+	StoreConfig *SessionStoreConfig `json:"store_config,omitempty"`
+	// OutputValidationRetries is the number of times the model is asked to
+	// fix a structured output failing validation, given the violations.
+	OutputValidationRetries int `json:"output_validation_retries,omitempty" jsonschema:"minimum=0"`
+	// MaxHandoffRepeats is the number of handoffs from an agent to another
+	// allowed in a run, detecting agents handing off to each other in a
+	// loop; unlimited when zero.
+	MaxHandoffRepeats int `json:"max_handoff_repeats,omitempty" jsonschema:"minimum=0"`
+	// HandoffLoopIntervention rejects the handoffs of a loop with this
+	// message to the model, instead of failing the run.
+	HandoffLoopIntervention string `json:"handoff_loop_intervention,omitempty"`
+}
+
+// LongTermMemoryDeclaration enables recalling relevant items from past
+// sessions of the same user.
+type LongTermMemoryDeclaration struct {
+	TopK     int     `json:"top_k,omitempty" jsonschema:"minimum=0"`
+	MinScore float64 `json:"min_score,omitempty" jsonschema:"minimum=-1,maximum=1"`
+}
+
+// SessionForkDeclaration seeds a new session with the history of an existing one.
+type SessionForkDeclaration struct {
+	SessionID string `json:"session_id" jsonschema:"minLength=1"`
+	// AtIndex is the number of leading items to share; the whole history is shared when nil.
+	AtIndex *int `json:"at_index,omitempty" jsonschema:"minimum=0"`
 }
 
 // CredentialDeclaration contains minimal identity data used for validation / logging.
 type CredentialDeclaration struct {
-	UserID       string         `json:"user_id"`
-	AccountID    string         `json:"account_id"`
+	UserID       string         `json:"user_id" jsonschema:"minLength=1"`
+	AccountID    string         `json:"account_id" jsonschema:"minLength=1"`
 	Capabilities []string       `json:"capabilities,omitempty"`
 	Metadata     map[string]any `json:"metadata,omitempty"`
 }
 
+// CallbackModeStream is the callback mode used by
+// RunnerService.ExecuteWithPublisher, where events are delivered to the
+// caller-provided publisher.
+const CallbackModeStream = "stream"
+
+// CallbackModeNATS publishes the callback events to NATS JetStream, where the
+// target is the subject template. See the natscallback package.
+const CallbackModeNATS = "nats"
+
+// CallbackModeTTY prints the events to stdout as an interactive display, with
+// colors, a spinner for the active agent, the live token stream and the tool
+// calls as a tree. Outside a terminal it prints as "stdout_verbose".
+const CallbackModeTTY = "stdout_tty"
+
 // CallbackDeclaration describes how streaming events should be published.
 type CallbackDeclaration struct {
 	Target string `json:"target"`
 	Mode   string `json:"mode,omitempty"`
+	// SigningSecret is the key of the HMAC signature of HTTP callbacks,
+	// usually a ${secret:NAME} reference. See VerifyCallbackSignature.
+	SigningSecret string `json:"signing_secret,omitempty"`
+	// Retry enables the retries of HTTP callbacks which failed to deliver.
+	Retry *CallbackRetryPolicy `json:"retry,omitempty"`
+	// QueueSize enables the asynchronous delivery of the events, queueing up
+	// to QueueSize of them, so that a slow endpoint does not stall the run.
+	// See AsyncCallbackPublisher.
+	QueueSize int `json:"queue_size,omitempty" jsonschema:"minimum=0"`
+}
+
+// CallbackRetryPolicy retries callback deliveries with exponential backoff.
+// Zero fields take the DefaultCallback* values.
+type CallbackRetryPolicy struct {
+	// MaxAttempts counts the first delivery.
+	MaxAttempts      int     `json:"max_attempts,omitempty" jsonschema:"minimum=0"`
+	InitialBackoffMS int     `json:"initial_backoff_ms,omitempty" jsonschema:"minimum=0"`
+	MaxBackoffMS     int     `json:"max_backoff_ms,omitempty" jsonschema:"minimum=0"`
+	BackoffFactor    float64 `json:"backoff_factor,omitempty" jsonschema:"minimum=0"`
 }
 
 // UnmarshalJSON allows callback to be provided as string or object.
@@ -60,28 +142,168 @@ func (c *CallbackDeclaration) UnmarshalJSON(data []byte) error {
 
 // WorkflowDeclaration defines the agent graph that should be executed.
 type WorkflowDeclaration struct {
-	Name          string             `json:"name"`
-	StartingAgent string             `json:"starting_agent"`
-	Agents        []AgentDeclaration `json:"agents"`
+	Name          string             `json:"name" jsonschema:"minLength=1"`
+	StartingAgent string             `json:"starting_agent" jsonschema:"minLength=1"`
+	Agents        []AgentDeclaration `json:"agents" jsonschema:"minItems=1"`
 	Metadata      map[string]any     `json:"metadata,omitempty"`
+	// MaxRouteHops caps the number of agent routes followed in a run, so that
+	// routing cycles terminate. Defaults to DefaultMaxRouteHops.
+	MaxRouteHops int `json:"max_route_hops,omitempty" jsonschema:"minimum=0"`
+	// RequiredCapabilities must all be granted by Session.Credentials for the
+	// workflow to run.
+	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
+	// Inputs declare the parameters of the workflow, provided by the
+	// requests, turning the manifest into a reusable template.
+	Inputs []WorkflowInputDeclaration `json:"inputs,omitempty"`
 }
 
 // AgentDeclaration captures the configuration of a single agent.
 type AgentDeclaration struct {
-	Name               string                 `json:"name"`
+	Name               string                 `json:"name" jsonschema:"minLength=1"`
 	DisplayName        string                 `json:"display_name,omitempty"`
 	Instructions       string                 `json:"instructions,omitempty"`
 	PromptID           string                 `json:"prompt_id,omitempty"`
 	Model              *ModelDeclaration      `json:"model,omitempty"`
-	Handoffs           []string               `json:"handoff,omitempty"`
+	Handoffs           []string               `json:"handoffs,omitempty"`
 	AgentTools         []AgentToolReference   `json:"agent_tools,omitempty"`
 	Tools              []ToolDeclaration      `json:"tools,omitempty"`
-	MCPServers         []MCPDeclaration       `json:"mcp,omitempty"`
+	MCPServers         []MCPDeclaration       `json:"mcp_servers,omitempty"`
 	InputGuardrails    []GuardrailDeclaration `json:"input_guardrails,omitempty"`
 	OutputGuardrails   []GuardrailDeclaration `json:"output_guardrails,omitempty"`
 	OutputType         *OutputTypeDeclaration `json:"output_type,omitempty"`
 	HandoffDescription string                 `json:"handoff_description,omitempty"`
 	Annotations        map[string]any         `json:"annotations,omitempty"`
+	// InstructionsTemplate renders Instructions as a text/template when the
+	// workflow is built. See the README for the available data and functions.
+	InstructionsTemplate bool `json:"instructions_template,omitempty"`
+	// ToolUseBehavior decides whether function tool results end the agent
+	// turn; by default they are sent back to the model.
+	ToolUseBehavior *ToolUseBehaviorDeclaration `json:"tool_use_behavior,omitempty"`
+	// UsageLimits bound the turns, tool calls and tokens of this agent in a
+	// run, on top of the session max_turns.
+	UsageLimits *AgentUsageLimitsDeclaration `json:"usage_limits,omitempty"`
+	// HandoffConditions offer handoffs to the model only when the run
+	// matches them; handoffs without a condition are always offered.
+	HandoffConditions []HandoffConditionDeclaration `json:"handoff_conditions,omitempty"`
+	// Routes choose the agent to run next once this agent is done, in order:
+	// the first route whose condition matches is followed.
+	Routes []RouteDeclaration `json:"routes,omitempty"`
+	// Loop runs this agent again until its output passes, before routes are
+	// evaluated.
+	Loop *LoopDeclaration `json:"loop,omitempty"`
+	// FanOut runs other agents in parallel on the input of this agent, which
+	// then runs with their outputs to aggregate them.
+	FanOut *FanOutDeclaration `json:"fan_out,omitempty"`
+	// Retry runs the steps started by this agent again when they fail with
+	// transient errors.
+	Retry *AgentRetryPolicy `json:"retry,omitempty"`
+	// Variants replace the instructions, prompt or model of the agent for a
+	// share of the sessions, proportional to their weights, e.g. for prompt
+	// experiments. See the README.
+	Variants []AgentVariantDeclaration `json:"variants,omitempty"`
+}
+
+// AgentVariantDeclaration overrides the fields it sets in the declaration of
+// its agent when chosen.
+type AgentVariantDeclaration struct {
+	Name         string            `json:"name" jsonschema:"minLength=1"`
+	Weight       int               `json:"weight" jsonschema:"minimum=1"`
+	Instructions string            `json:"instructions,omitempty"`
+	PromptID     string            `json:"prompt_id,omitempty"`
+	Model        *ModelDeclaration `json:"model,omitempty"`
+}
+
+// FanOutDeclaration lists the agents run in parallel before the declaring
+// agent. An agent may be listed more than once, e.g. to sample several
+// answers to pick from.
+type FanOutDeclaration struct {
+	Agents []string `json:"agents" jsonschema:"minItems=1"`
+}
+
+// LoopDeclaration repeats an agent until its outcome matches Until, or
+// MaxIterations is reached. Outcomes where an output guardrail tripwire was
+// triggered never pass, unless Until names the guardrail.
+type LoopDeclaration struct {
+	Until         ConditionDeclaration `json:"until,omitempty"`
+	MaxIterations int                  `json:"max_iterations" jsonschema:"minimum=1"`
+	// Feedback is added as a user message before each new iteration, e.g.
+	// "The answer is incomplete, try again."
+	Feedback string `json:"feedback,omitempty"`
+}
+
+// AgentRetryPolicy retries the failed steps of an agent with exponential
+// backoff, when their error is of one of the RetryOn classes. Zero fields
+// take the DefaultStepRetry* values.
+type AgentRetryPolicy struct {
+	// MaxAttempts counts the first attempt.
+	MaxAttempts      int     `json:"max_attempts,omitempty" jsonschema:"minimum=0"`
+	InitialBackoffMS int     `json:"initial_backoff_ms,omitempty" jsonschema:"minimum=0"`
+	MaxBackoffMS     int     `json:"max_backoff_ms,omitempty" jsonschema:"minimum=0"`
+	BackoffFactor    float64 `json:"backoff_factor,omitempty" jsonschema:"minimum=0"`
+	// RetryOn lists the retried error classes, model_error by default.
+	RetryOn []string `json:"retry_on,omitempty" jsonschema:"enum=model_error,enum=model_behavior,enum=guardrail,enum=max_turns"`
+}
+
+// AgentUsageLimitsDeclaration maps to agents.AgentUsageLimits. Zero values
+// are unlimited.
+type AgentUsageLimitsDeclaration struct {
+	MaxTurns     int `json:"max_turns,omitempty" jsonschema:"minimum=0"`
+	MaxToolCalls int `json:"max_tool_calls,omitempty" jsonschema:"minimum=0"`
+	MaxTokens    int `json:"max_tokens,omitempty" jsonschema:"minimum=0"`
+}
+
+// HandoffConditionDeclaration enables the handoff to Agent, which must be
+// listed in the handoffs of the agent, only when all its fields match.
+type HandoffConditionDeclaration struct {
+	Agent string `json:"agent" jsonschema:"minLength=1"`
+	// RequiredCapabilities must all be granted by the session credentials.
+	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
+	// Expression is a Go text/template which matches when it renders "true".
+	// It is evaluated against the request and the outputs of the agents
+	// completed earlier in the run. See the README for its data.
+	Expression string `json:"expression,omitempty"`
+}
+
+// RouteDeclaration continues the run with another agent when its condition
+// matches the outcome of the agent declaring it.
+type RouteDeclaration struct {
+	When ConditionDeclaration `json:"when"`
+	Next string               `json:"next" jsonschema:"minLength=1"`
+}
+
+// ConditionDeclaration matches the outcome of an agent. All the fields set
+// must match; an empty condition always matches a successful outcome.
+//
+// Outcomes where an output guardrail tripwire was triggered only match
+// conditions naming that guardrail in GuardrailTriggered.
+type ConditionDeclaration struct {
+	// Field is a dot-separated path into the structured output, e.g.
+	// "ticket.priority" or "items.0.id". Equals, In and Contains apply to the
+	// whole output when it is empty.
+	Field    string `json:"field,omitempty"`
+	Equals   any    `json:"equals,omitempty"`
+	In       []any  `json:"in,omitempty"`
+	Contains string `json:"contains,omitempty"`
+	// Exists checks whether Field is present in the output.
+	Exists *bool `json:"exists,omitempty"`
+	// GuardrailTriggered is the name of an output guardrail whose tripwire
+	// was triggered by the agent output.
+	GuardrailTriggered string `json:"guardrail_triggered,omitempty"`
+	// Expression is a text/template which matches when it renders "true".
+	// See the README for the available data and functions.
+	Expression string `json:"expression,omitempty"`
+}
+
+// ToolUseBehaviorDeclaration configures how the results of function tools are
+// handled, see agents.Agent.ToolUseBehavior.
+type ToolUseBehaviorDeclaration struct {
+	Mode string `json:"mode,omitempty" jsonschema:"enum=run_llm_again,enum=stop_on_first_tool,enum=stop_at_tools,enum=custom"`
+	// ToolNames stop the agent when called, with the stop_at_tools mode.
+	ToolNames []string `json:"tool_names,omitempty"`
+	// Handler names a Builder.ToolUseBehaviorHandlers entry, with the custom
+	// mode, which is called with Config.
+	Handler string         `json:"handler,omitempty"`
+	Config  map[string]any `json:"config,omitempty"`
 }
 
 // AgentToolReference allows referencing another agent as a tool.
@@ -93,23 +315,29 @@ type AgentToolReference struct {
 
 // ToolDeclaration represents a tool that should be attached to an agent.
 type ToolDeclaration struct {
-	Type   string         `json:"type"`
+	Type   string         `json:"type" jsonschema:"minLength=1"`
 	Name   string         `json:"name,omitempty"`
 	Config map[string]any `json:"config,omitempty"`
+	// RequiredCapabilities must all be granted by Session.Credentials for the
+	// tool to be available, see Builder.StripUnauthorizedTools.
+	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
 }
 
 // MCPDeclaration configures hosted or stdio MCP servers.
 type MCPDeclaration struct {
 	Type            string         `json:"type,omitempty"`
 	ServerLabel     string         `json:"server_label,omitempty"`
-	Address         string         `json:"address"`
+	Address         string         `json:"address" jsonschema:"minLength=1"`
 	RequireApproval string         `json:"require_approval,omitempty"`
 	Additional      map[string]any `json:"additional,omitempty"`
+	// RequiredCapabilities must all be granted by Session.Credentials for the
+	// server to be available, as for tools.
+	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
 }
 
 // GuardrailDeclaration references a reusable guardrail preset.
 type GuardrailDeclaration struct {
-	Name   string         `json:"name"`
+	Name   string         `json:"name" jsonschema:"minLength=1"`
 	Config map[string]any `json:"config,omitempty"`
 	Target string         `json:"target,omitempty"`
 }
@@ -119,12 +347,26 @@ type OutputTypeDeclaration struct {
 	Name   string         `json:"name"`
 	Strict bool           `json:"strict,omitempty"`
 	Schema map[string]any `json:"schema,omitempty"`
+	// PresetRef names a Builder.OutputTypePresets schema, used instead of
+	// Schema, e.g. "classification". Name defaults to the preset name.
+	PresetRef string `json:"preset_ref,omitempty"`
 }
 
 // ModelDeclaration indicates which model/provider to use and optional settings.
 type ModelDeclaration struct {
-	Provider     string                `json:"provider,omitempty"`
-	Model        string                `json:"model"`
+	// Provider names a Builder.ModelProviderFactories entry, e.g. "anthropic"
+	// or "openrouter". Defaults to "openai".
+	Provider string `json:"provider,omitempty"`
+	// BaseURL overrides the endpoint of the provider; it is required by the
+	// "custom" provider.
+	BaseURL string `json:"base_url,omitempty"`
+	// APIKey overrides the key of the provider, usually as a ${secret:NAME}
+	// reference.
+	APIKey string `json:"api_key,omitempty"`
+	// API selects the Responses or Chat Completions API of OpenAI
+	// compatible providers.
+	API          string                `json:"api,omitempty" jsonschema:"enum=responses,enum=chat_completions"`
+	Model        string                `json:"model" jsonschema:"minLength=1"`
 	Temperature  *float64              `json:"temperature,omitempty"`
 	TopP         *float64              `json:"top_p,omitempty"`
 	MaxTokens    *int64                `json:"max_tokens,omitempty"`
@@ -134,6 +376,28 @@ type ModelDeclaration struct {
 	ExtraHeaders map[string]string     `json:"extra_headers,omitempty"`
 	ExtraQuery   map[string]string     `json:"extra_query,omitempty"`
 	ToolChoice   string                `json:"tool_choice,omitempty"`
+	// ExtraBody holds vendor-specific fields merged into the JSON body of
+	// the requests, e.g. {"safe_prompt": true} for Mistral.
+	ExtraBody map[string]any `json:"extra_body,omitempty"`
+	// ServiceTier selects the processing tier of the requests, e.g.
+	// "priority" for latency-sensitive workflows.
+	ServiceTier string `json:"service_tier,omitempty" jsonschema:"enum=auto,enum=default,enum=flex,enum=scale,enum=priority"`
+	// MaxCompletionTokens bounds the tokens generated including reasoning
+	// tokens; max_tokens is sent as max_completion_tokens to the reasoning
+	// models anyway.
+	MaxCompletionTokens *int64 `json:"max_completion_tokens,omitempty" jsonschema:"minimum=1"`
+	// Truncation selects how the Responses API handles inputs exceeding the
+	// context window: "auto" drops items from the middle of the
+	// conversation, "disabled" (the API default) fails the request.
+	Truncation string `json:"truncation,omitempty" jsonschema:"enum=auto,enum=disabled"`
+	// Logprobs requests the log probabilities of the output tokens, and
+	// TopLogprobs the number of most likely alternatives for each of them.
+	Logprobs    bool   `json:"logprobs,omitempty"`
+	TopLogprobs *int64 `json:"top_logprobs,omitempty" jsonschema:"minimum=0,maximum=20"`
+	// StructuredOutput selects how Chat Completions models are asked for the
+	// output type of the agent: "json_schema" (default), or the "json_mode"
+	// and "prompt" fallbacks for the models without structured outputs.
+	StructuredOutput string `json:"structured_output,omitempty" jsonschema:"enum=json_schema,enum=json_mode,enum=prompt"`
 }
 
 // ReasoningDeclaration mirrors the subset of OpenAI reasoning parameters we support.
@@ -145,12 +409,24 @@ type ReasoningDeclaration struct {
 // Validate performs shallow validation of the callback declaration.
 func (c *CallbackDeclaration) Validate() error {
 	mode := strings.ToLower(c.Mode)
-	if mode == "stdout" {
+	if mode == "stdout" || mode == "stdout_verbose" || mode == CallbackModeTTY || mode == CallbackModeStream {
 		return nil
 	}
 	if strings.TrimSpace(c.Target) == "" {
 		return fmt.Errorf("callback target is required")
 	}
+	if r := c.Retry; r != nil {
+		if r.MaxAttempts < 0 || r.InitialBackoffMS < 0 || r.MaxBackoffMS < 0 {
+			return fmt.Errorf("callback retry values cannot be negative")
+		}
+		if r.BackoffFactor != 0 && r.BackoffFactor < 1 {
+			return fmt.Errorf("callback retry backoff_factor must be at least 1")
+		}
+	}
+	if mode == CallbackModeNATS || hasInterpolation(c.Target) {
+		// Interpolated targets are validated again by the runner.
+		return nil
+	}
 	if _, err := url.ParseRequestURI(c.Target); err != nil {
 		return fmt.Errorf("callback target %q is not a valid URL: %w", c.Target, err)
 	}
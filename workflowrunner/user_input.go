@@ -0,0 +1,182 @@
+package workflowrunner
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+var (
+	// ErrInputRequestNotFound is returned when answering an input request
+	// which is not pending.
+	ErrInputRequestNotFound = errors.New("input request not found")
+	// ErrInputsPending is returned when resuming a run before all its input
+	// requests are answered.
+	ErrInputsPending = errors.New("input requests are pending")
+)
+
+// InputRequestState is a question asked to the user by an ask_user tool.
+type InputRequestState struct {
+	// RequestID is the ID of the tool call.
+	RequestID string `json:"request_id"`
+	AgentName string `json:"agent_name"`
+	Question  string `json:"question"`
+	// Fields lists the values the answer should provide, if any, e.g. for
+	// forms.
+	Fields    []string  `json:"fields,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InputResponseState records the answer to an input request.
+type InputResponseState struct {
+	RequestID string `json:"request_id"`
+	// Question is copied from the request.
+	Question string `json:"question,omitempty"`
+	// Answer is a text, or any JSON value such as the values of the fields
+	// of a form.
+	Answer     any       `json:"answer"`
+	ProvidedAt time.Time `json:"provided_at"`
+}
+
+type askUserArgs struct {
+	Question string   `json:"question" jsonschema:"description=The question to ask the user."`
+	Fields   []string `json:"fields" jsonschema:"description=The names of the values the answer should provide. Empty for a free text answer."`
+}
+
+// newAskUserTool creates the ask_user tool, which suspends the run until the
+// question is answered with RunnerService.ProvideInput. The description can
+// be set in the "description" config entry.
+func newAskUserTool(_ context.Context, decl ToolDeclaration, env ToolFactoryEnv) (agents.Tool, error) {
+	description, _ := getString(decl.Config, "description")
+	description = cmp.Or(description, "Ask the user a question and wait for the answer. "+
+		"Use it when information only the user can provide is missing.")
+	return agents.SafeNewFunctionTool(cmp.Or(decl.Name, "ask_user"), description,
+		func(ctx context.Context, args askUserArgs) (InputRequestState, error) {
+			req := InputRequestState{
+				AgentName: env.AgentName,
+				Question:  args.Question,
+				Fields:    args.Fields,
+				CreatedAt: clockFromContext(ctx).now(),
+			}
+			if data := agents.ToolDataFromContext(ctx); data != nil {
+				req.RequestID = data.ToolCallID
+			}
+			return req, nil
+		})
+}
+
+// askUserBehavior ends the turns of agents calling an ask_user tool, so that
+// the run is suspended; other turns are handled by the declared behavior.
+// The final output of the suspended turns is an inputSuspension, not the
+// question: the suspension is reported by the pending inputs of the run.
+type askUserBehavior struct {
+	next agents.ToolUseBehavior
+}
+
+func (b askUserBehavior) ToolsToFinalOutput(ctx context.Context, results []agents.FunctionToolResult) (agents.ToolsToFinalOutputResult, error) {
+	var suspension inputSuspension
+	for _, result := range results {
+		if req, ok := result.Output.(InputRequestState); ok {
+			suspension.Requests = append(suspension.Requests, req)
+		}
+	}
+	if len(suspension.Requests) > 0 {
+		return agents.ToolsToFinalOutputResult{
+			IsFinalOutput: true,
+			FinalOutput:   param.NewOpt[any](suspension),
+		}, nil
+	}
+	next := b.next
+	if next == nil {
+		next = agents.RunLLMAgain()
+	}
+	return next.ToolsToFinalOutput(ctx, results)
+}
+
+// inputSuspension is the final output of the turns suspended on ask_user
+// calls. The output of plain text agents is its String, which is empty.
+type inputSuspension struct {
+	Requests []InputRequestState
+}
+
+func (inputSuspension) String() string { return "" }
+
+// hasInputRequests reports whether the items of a step hold calls of
+// ask_user tools.
+func hasInputRequests(items []agents.RunItem) bool {
+	return slices.ContainsFunc(items, func(item agents.RunItem) bool {
+		output, ok := item.(agents.ToolCallOutputItem)
+		if !ok {
+			return false
+		}
+		_, ok = output.Output.(InputRequestState)
+		return ok
+	})
+}
+
+// inputResponseItems returns the answers as user messages.
+func inputResponseItems(responses []InputResponseState) []agents.TResponseInputItem {
+	items := make([]agents.TResponseInputItem, 0, len(responses))
+	for _, response := range responses {
+		answer, ok := response.Answer.(string)
+		if !ok {
+			data, err := json.Marshal(response.Answer)
+			if err != nil {
+				data = []byte(fmt.Sprint(response.Answer))
+			}
+			answer = string(data)
+		}
+		if response.Question != "" {
+			answer = fmt.Sprintf("Answer to %q: %s", response.Question, answer)
+		}
+		items = append(items, agents.ItemHelpers().InputToNewInputList(agents.InputString(answer))...)
+	}
+	return items
+}
+
+// ProvideInput records the answer to a pending input request of the given
+// session, removing it from the pending inputs. As with ResolveApproval, the
+// execution goes back to the running status once nothing is pending, and a
+// suspended run is then continued with Resume.
+func (s *RunnerService) ProvideInput(ctx context.Context, sessionID string, response InputResponseState) (WorkflowExecutionState, error) {
+	if response.ProvidedAt.IsZero() {
+		response.ProvidedAt = s.now()
+	}
+	for attempt := 1; ; attempt++ {
+		state, ok, err := s.GetState(ctx, sessionID)
+		if err != nil {
+			return WorkflowExecutionState{}, err
+		}
+		if !ok {
+			return WorkflowExecutionState{}, fmt.Errorf("%w: session %q", ErrExecutionNotFound, sessionID)
+		}
+
+		index := slices.IndexFunc(state.PendingInputs, func(req InputRequestState) bool {
+			return req.RequestID == response.RequestID
+		})
+		if index < 0 {
+			return WorkflowExecutionState{}, fmt.Errorf("%w: request %q", ErrInputRequestNotFound, response.RequestID)
+		}
+		response.Question = state.PendingInputs[index].Question
+		state.PendingInputs = slices.Delete(state.PendingInputs, index, index+1)
+		state.ProvidedInputs = append(state.ProvidedInputs, response)
+		state.Status = state.waitingStatus()
+		state.UpdatedAt = s.now()
+		err = s.StateStore.Save(ctx, state)
+		if errors.Is(err, ErrExecutionStateConflict) && attempt < maxStateSaveAttempts {
+			continue
+		}
+		if err != nil {
+			return WorkflowExecutionState{}, err
+		}
+		state.Version++
+		return state, nil
+	}
+}
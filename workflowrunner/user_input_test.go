@@ -0,0 +1,100 @@
+package workflowrunner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAskUserToolUsesRunClock(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tool, err := newAskUserTool(t.Context(), ToolDeclaration{Type: "ask_user"}, ToolFactoryEnv{AgentName: "assistant"})
+	require.NoError(t, err)
+
+	ctx := contextWithClock(t.Context(), func() time.Time { return now })
+	output, err := tool.(agents.FunctionTool).OnInvokeTool(ctx, `{"question": "Which city?", "fields": []}`)
+	require.NoError(t, err)
+	req := output.(InputRequestState)
+	assert.Equal(t, "Which city?", req.Question)
+	assert.Equal(t, "assistant", req.AgentName)
+	assert.Equal(t, now, req.CreatedAt)
+}
+
+func TestAskUserSuspendProvideInputResume(t *testing.T) {
+	ctx := t.Context()
+	question := "Should we call you at 555-123-4567?"
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{
+			agentstesting.GetFunctionToolCall("ask_user", `{"question": "`+question+`", "fields": []}`),
+		},
+	})
+	model.SetNextOutput(agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage(`{"confirmed": true}`)},
+	})
+	service := newTestService(t, model, &recordingPublisher{})
+	req := testRequest("s1")
+	agent := &req.Workflow.Agents[0]
+	agent.Tools = []ToolDeclaration{{Type: "ask_user"}}
+	agent.OutputGuardrails = []GuardrailDeclaration{{Name: "phone_number_output"}}
+	agent.OutputType = &OutputTypeDeclaration{
+		Name:   "confirmation",
+		Strict: true,
+		Schema: map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"confirmed": map[string]any{"type": "boolean"}},
+			"required":             []any{"confirmed"},
+			"additionalProperties": false,
+		},
+	}
+
+	// The question is neither the final output nor checked by the output
+	// guardrails.
+	task, err := service.Execute(ctx, req)
+	require.NoError(t, err)
+	result := task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusWaitingInput, result.Value.Status)
+	assert.Nil(t, result.Value.FinalOutput)
+	require.Len(t, result.Value.PendingInputs, 1)
+	pending := result.Value.PendingInputs[0]
+	assert.Equal(t, question, pending.Question)
+	assert.Equal(t, "assistant", pending.AgentName)
+
+	_, err = service.ProvideInput(ctx, "s1", InputResponseState{RequestID: "unknown", Answer: "yes"})
+	assert.ErrorIs(t, err, ErrInputRequestNotFound)
+	state, err := service.ProvideInput(ctx, "s1", InputResponseState{RequestID: pending.RequestID, Answer: "yes"})
+	require.NoError(t, err)
+	assert.Empty(t, state.PendingInputs)
+
+	task, err = service.Resume(ctx, req)
+	require.NoError(t, err)
+	result = task.Await()
+	require.NoError(t, result.Error)
+	assert.Equal(t, ExecutionStatusCompleted, result.Value.Status)
+	assert.Equal(t, map[string]any{"confirmed": true}, result.Value.FinalOutput)
+
+	// The model sees the answer in the conversation.
+	input := model.LastTurnArgs.Input.(agents.InputItems)
+	answer := input[len(input)-1].OfMessage
+	require.NotNil(t, answer)
+	assert.Equal(t, `Answer to "`+question+`": yes`, answer.Content.OfString.Value)
+}
+
+func TestAskUserBehavior(t *testing.T) {
+	ctx := t.Context()
+	req := InputRequestState{RequestID: "call_1", Question: "Which city?"}
+	result, err := askUserBehavior{}.ToolsToFinalOutput(ctx, []agents.FunctionToolResult{{Output: req}})
+	require.NoError(t, err)
+	assert.True(t, result.IsFinalOutput)
+	assert.Equal(t, inputSuspension{Requests: []InputRequestState{req}}, result.FinalOutput.Value)
+	assert.Empty(t, fmt.Sprint(result.FinalOutput.Value))
+
+	result, err = askUserBehavior{}.ToolsToFinalOutput(ctx, []agents.FunctionToolResult{{Output: "sunny"}})
+	require.NoError(t, err)
+	assert.False(t, result.IsFinalOutput)
+}
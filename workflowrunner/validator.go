@@ -3,13 +3,26 @@ package workflowrunner
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/nlpodyssey/openai-agents-go/modelsettings"
 )
 
 // ValidateWorkflowRequest performs structural validation and returns an error
 // describing the first issue encountered.
 func ValidateWorkflowRequest(req WorkflowRequest) error {
-	if strings.TrimSpace(req.Query) == "" {
+	if _, err := Migrate(req); err != nil {
+		return err
+	}
+	if req.Batch != nil {
+		if strings.TrimSpace(req.Query) != "" {
+			return errors.New("query and batch are mutually exclusive")
+		}
+		if err := req.Batch.Validate(); err != nil {
+			return fmt.Errorf("batch invalid: %w", err)
+		}
+	} else if strings.TrimSpace(req.Query) == "" {
 		return errors.New("query is required")
 	}
 	if err := validateSession(req.Session); err != nil {
@@ -18,9 +31,22 @@ func ValidateWorkflowRequest(req WorkflowRequest) error {
 	if err := req.Callback.Validate(); err != nil {
 		return fmt.Errorf("callback invalid: %w", err)
 	}
+	if req.WorkflowRef != "" {
+		// The referenced workflow is validated once resolved.
+		if req.Workflow.Name != "" || len(req.Workflow.Agents) > 0 {
+			return errors.New("workflow and workflow_ref are mutually exclusive")
+		}
+		if _, _, err := ParseWorkflowRef(req.WorkflowRef); err != nil {
+			return fmt.Errorf("workflow_ref invalid: %w", err)
+		}
+		return nil
+	}
 	if err := validateWorkflowDeclaration(req.Workflow); err != nil {
 		return fmt.Errorf("workflow invalid: %w", err)
 	}
+	if _, err := resolveInputs(req.Workflow.Inputs, req.Inputs); err != nil {
+		return fmt.Errorf("inputs invalid: %w", err)
+	}
 	return nil
 }
 
@@ -37,9 +63,45 @@ func validateSession(session SessionDeclaration) error {
 	if session.HistorySize < 0 {
 		return errors.New("history_size cannot be negative")
 	}
+	if session.HistoryTokenBudget < 0 {
+		return errors.New("history_token_budget cannot be negative")
+	}
 	if session.MaxTurns < 0 {
 		return errors.New("max_turns cannot be negative")
 	}
+	if session.DeadlineSeconds < 0 {
+		return errors.New("deadline_seconds cannot be negative")
+	}
+	if session.OutputValidationRetries < 0 {
+		return errors.New("output_validation_retries cannot be negative")
+	}
+	if session.MaxHandoffRepeats < 0 {
+		return errors.New("max_handoff_repeats cannot be negative")
+	}
+	if ltm := session.LongTermMemory; ltm != nil {
+		if ltm.TopK < 0 {
+			return errors.New("long_term_memory.top_k cannot be negative")
+		}
+		if ltm.MinScore < -1 || ltm.MinScore > 1 {
+			return errors.New("long_term_memory.min_score must be between -1 and 1")
+		}
+	}
+	if fork := session.ForkFrom; fork != nil {
+		if fork.SessionID == "" {
+			return errors.New("fork_from.session_id is required")
+		}
+		if fork.SessionID == session.SessionID {
+			return errors.New("fork_from.session_id must differ from session_id")
+		}
+		if fork.AtIndex != nil && *fork.AtIndex < 0 {
+			return errors.New("fork_from.at_index cannot be negative")
+		}
+	}
+	if cfg := session.StoreConfig; cfg != nil {
+		if err := validateSessionStoreConfig(*cfg); err != nil {
+			return fmt.Errorf("store_config: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -53,6 +115,15 @@ func validateWorkflowDeclaration(workflow WorkflowDeclaration) error {
 	if len(workflow.Agents) == 0 {
 		return errors.New("agents cannot be empty")
 	}
+	if workflow.MaxRouteHops < 0 {
+		return errors.New("max_route_hops cannot be negative")
+	}
+	if err := validateCapabilities(workflow.RequiredCapabilities); err != nil {
+		return err
+	}
+	if err := validateInputDeclarations(workflow.Inputs); err != nil {
+		return err
+	}
 
 	seen := make(map[string]struct{}, len(workflow.Agents))
 	for i, agent := range workflow.Agents {
@@ -77,35 +148,173 @@ func validateWorkflowDeclaration(workflow WorkflowDeclaration) error {
 				return fmt.Errorf("agent %q handoff %q not found", agent.Name, h)
 			}
 		}
+		for _, cond := range agent.HandoffConditions {
+			if !slices.Contains(agent.Handoffs, cond.Agent) {
+				return fmt.Errorf("agent %q handoff condition references %q, which is not one of its handoffs", agent.Name, cond.Agent)
+			}
+		}
 		for _, tool := range agent.AgentTools {
 			if _, ok := seen[tool.AgentName]; !ok {
 				return fmt.Errorf("agent %q agent_tool references unknown agent %q", agent.Name, tool.AgentName)
 			}
 		}
+		if agent.FanOut != nil {
+			if len(agent.FanOut.Agents) == 0 {
+				return fmt.Errorf("agent %q fan_out.agents cannot be empty", agent.Name)
+			}
+			for _, name := range agent.FanOut.Agents {
+				if name == agent.Name {
+					return fmt.Errorf("agent %q fan_out cannot include itself", agent.Name)
+				}
+				if _, ok := seen[name]; !ok {
+					return fmt.Errorf("agent %q fan_out references unknown agent %q", agent.Name, name)
+				}
+			}
+		}
+		for i, route := range agent.Routes {
+			if _, ok := seen[route.Next]; !ok {
+				return fmt.Errorf("agent %q route %d references unknown agent %q", agent.Name, i, route.Next)
+			}
+		}
 	}
 	return nil
 }
 
 func validateAgentDeclaration(agent AgentDeclaration) error {
 	if agent.Model != nil {
-		if agent.Model.Model == "" {
-			return errors.New("model.model is required when model is present")
+		if err := validateModelDeclaration(*agent.Model); err != nil {
+			return err
 		}
 	}
 	for _, tool := range agent.Tools {
 		if strings.TrimSpace(tool.Type) == "" {
 			return fmt.Errorf("tool missing type")
 		}
+		if err := validateCapabilities(tool.RequiredCapabilities); err != nil {
+			return fmt.Errorf("tool %q: %w", tool.Type, err)
+		}
+	}
+	if limits := agent.UsageLimits; limits != nil &&
+		(limits.MaxTurns < 0 || limits.MaxToolCalls < 0 || limits.MaxTokens < 0) {
+		return errors.New("usage_limits cannot be negative")
+	}
+	seenConditions := make(map[string]struct{}, len(agent.HandoffConditions))
+	for _, cond := range agent.HandoffConditions {
+		if _, dup := seenConditions[cond.Agent]; dup {
+			return fmt.Errorf("duplicate handoff condition for %q", cond.Agent)
+		}
+		seenConditions[cond.Agent] = struct{}{}
+		if err := validateCapabilities(cond.RequiredCapabilities); err != nil {
+			return fmt.Errorf("handoff condition %q: %w", cond.Agent, err)
+		}
+		if cond.Expression != "" {
+			if _, err := parseRouteExpression(cond.Expression); err != nil {
+				return fmt.Errorf("handoff condition %q expression: %w", cond.Agent, err)
+			}
+		}
 	}
 	for _, mcp := range agent.MCPServers {
 		if strings.TrimSpace(mcp.Address) == "" {
 			return fmt.Errorf("mcp address is required")
 		}
+		if err := validateCapabilities(mcp.RequiredCapabilities); err != nil {
+			return fmt.Errorf("mcp server %q: %w", mcp.Address, err)
+		}
+	}
+	if agent.OutputType != nil && agent.OutputType.PresetRef != "" && agent.OutputType.Schema != nil {
+		return errors.New("output_type.preset_ref and output_type.schema are mutually exclusive")
+	}
+	if behavior := agent.ToolUseBehavior; behavior != nil {
+		switch behavior.Mode {
+		case "", ToolUseRunLLMAgain, ToolUseStopOnFirstTool:
+		case ToolUseStopAtTools:
+			if len(behavior.ToolNames) == 0 {
+				return errors.New("tool_use_behavior.tool_names is required by the stop_at_tools mode")
+			}
+		case ToolUseCustom:
+			if strings.TrimSpace(behavior.Handler) == "" {
+				return errors.New("tool_use_behavior.handler is required by the custom mode")
+			}
+		default:
+			return fmt.Errorf("tool_use_behavior.mode %q is not supported", behavior.Mode)
+		}
+	}
+	if r := agent.Retry; r != nil {
+		if r.MaxAttempts < 0 || r.InitialBackoffMS < 0 || r.MaxBackoffMS < 0 {
+			return errors.New("retry values cannot be negative")
+		}
+		if r.BackoffFactor != 0 && r.BackoffFactor < 1 {
+			return errors.New("retry backoff_factor must be at least 1")
+		}
+		for _, class := range r.RetryOn {
+			if !slices.Contains(retryOnClasses, class) {
+				return fmt.Errorf("retry retry_on %q must be one of %s", class, strings.Join(retryOnClasses, ", "))
+			}
+		}
+	}
+	if agent.Loop != nil && agent.Loop.MaxIterations < 1 {
+		return errors.New("loop.max_iterations must be at least 1")
 	}
 	for _, gr := range append(agent.InputGuardrails, agent.OutputGuardrails...) {
 		if strings.TrimSpace(gr.Name) == "" {
 			return fmt.Errorf("guardrail missing name")
 		}
 	}
+	variants := make(map[string]struct{}, len(agent.Variants))
+	for i, variant := range agent.Variants {
+		if strings.TrimSpace(variant.Name) == "" {
+			return fmt.Errorf("variants[%d] missing name", i)
+		}
+		if _, dup := variants[variant.Name]; dup {
+			return fmt.Errorf("duplicate variant name %q", variant.Name)
+		}
+		variants[variant.Name] = struct{}{}
+		if variant.Weight < 1 {
+			return fmt.Errorf("variant %q weight must be at least 1", variant.Name)
+		}
+		if variant.Model != nil {
+			if err := validateModelDeclaration(*variant.Model); err != nil {
+				return fmt.Errorf("variant %q: %w", variant.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateModelDeclaration(model ModelDeclaration) error {
+	if model.Model == "" {
+		return errors.New("model.model is required when model is present")
+	}
+	switch strings.ToLower(model.API) {
+	case "", ModelAPIResponses, ModelAPIChatCompletions:
+	default:
+		return fmt.Errorf("model.api %q must be %q or %q", model.API, ModelAPIResponses, ModelAPIChatCompletions)
+	}
+	switch modelsettings.ServiceTier(model.ServiceTier) {
+	case "", modelsettings.ServiceTierAuto, modelsettings.ServiceTierDefault, modelsettings.ServiceTierFlex,
+		modelsettings.ServiceTierScale, modelsettings.ServiceTierPriority:
+	default:
+		return fmt.Errorf("model.service_tier %q must be auto, default, flex, scale or priority", model.ServiceTier)
+	}
+	switch modelsettings.Truncation(model.Truncation) {
+	case "", modelsettings.TruncationAuto, modelsettings.TruncationDisabled:
+	default:
+		return fmt.Errorf("model.truncation %q must be auto or disabled", model.Truncation)
+	}
+	if model.TopLogprobs != nil && (*model.TopLogprobs < 0 || *model.TopLogprobs > 20) {
+		return fmt.Errorf("model.top_logprobs must be between 0 and 20, got %d", *model.TopLogprobs)
+	}
+	if _, ok := structuredOutputModes[strings.ToLower(model.StructuredOutput)]; !ok {
+		return fmt.Errorf("model.structured_output %q must be json_schema, json_mode or prompt", model.StructuredOutput)
+	}
+	return nil
+}
+
+func validateCapabilities(capabilities []string) error {
+	for _, capability := range capabilities {
+		if strings.TrimSpace(capability) == "" {
+			return errors.New("required_capabilities cannot contain empty values")
+		}
+	}
 	return nil
 }
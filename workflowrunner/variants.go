@@ -0,0 +1,60 @@
+package workflowrunner
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+// applyVariants chooses a variant for each agent declaring any, and merges
+// it into the agent declaration. It returns the workflow and the names of
+// the chosen variants, by agent.
+//
+// The choice only depends on the session ID, so that the runs of a session,
+// including resumed ones, keep the same variants.
+func applyVariants(req WorkflowRequest) (WorkflowDeclaration, map[string]string) {
+	workflow := req.Workflow
+	if !slices.ContainsFunc(workflow.Agents, func(decl AgentDeclaration) bool { return len(decl.Variants) > 0 }) {
+		return workflow, nil
+	}
+	chosen := make(map[string]string)
+	workflow.Agents = slices.Clone(workflow.Agents)
+	for i, decl := range workflow.Agents {
+		if len(decl.Variants) == 0 {
+			continue
+		}
+		variant := chooseVariant(req.Session.SessionID, decl)
+		chosen[decl.Name] = variant.Name
+		if variant.Instructions != "" {
+			decl.Instructions = variant.Instructions
+		}
+		if variant.PromptID != "" {
+			decl.PromptID = variant.PromptID
+		}
+		if variant.Model != nil {
+			decl.Model = variant.Model
+		}
+		workflow.Agents[i] = decl
+	}
+	return workflow, chosen
+}
+
+// chooseVariant picks a variant of the agent with a probability proportional
+// to its weight, hashing the session ID.
+func chooseVariant(sessionID string, decl AgentDeclaration) AgentVariantDeclaration {
+	total := 0
+	for _, variant := range decl.Variants {
+		total += variant.Weight
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sessionID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(decl.Name))
+	n := int(h.Sum64() % uint64(total))
+	for _, variant := range decl.Variants {
+		if n < variant.Weight {
+			return variant
+		}
+		n -= variant.Weight
+	}
+	return decl.Variants[len(decl.Variants)-1]
+}
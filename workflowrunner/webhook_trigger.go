@@ -0,0 +1,325 @@
+package workflowrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WebhookTrigger runs a workflow for each request received on its webhook,
+// see WebhookTriggers.
+type WebhookTrigger struct {
+	// Name identifies the trigger, served at /webhooks/{name}.
+	Name string `json:"name"`
+	// Request is the template of the runs, typically referencing a
+	// registered workflow with WorkflowRef. The mapped payload values are
+	// set on a copy for each run.
+	Request WorkflowRequest `json:"request"`
+	Mapping WebhookMapping  `json:"mapping,omitempty"`
+	// SigningSecret, when set, requires the requests to be signed as HTTP
+	// callbacks, in the CallbackSignatureHeader header.
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+// WebhookMapping extracts the values of a run from the JSON payload of a
+// webhook, with JSONPath expressions such as $.issue.title, $.items[0].id or
+// $['user-name'].
+type WebhookMapping struct {
+	// Query of the run; non-string values are JSON-encoded. Defaults to the
+	// query of the request template, or the whole payload if it has none.
+	Query string `json:"query,omitempty"`
+	// SessionID of the run. Defaults to the session ID of the request
+	// template, suffixed with a unique delivery ID so that runs do not share
+	// their history.
+	SessionID string `json:"session_id,omitempty"`
+	// Inputs are set in the context of the run, by key.
+	Inputs map[string]string `json:"inputs,omitempty"`
+	// Metadata is set in the metadata of the run, by key.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// TriggeredRun is the outcome of a run started by a webhook, see
+// WebhookTriggers.OnRunFinished.
+type TriggeredRun struct {
+	Trigger   string
+	SessionID string
+	Summary   RunSummary
+	Err       error
+}
+
+// WebhookTriggers serves the webhooks of its triggers, as
+//
+//	POST /webhooks/{name}
+//
+// Each request with a JSON payload starts a run of the trigger, and is
+// answered with 202 Accepted and the session ID of the run, without waiting
+// for it. Mount the handler with http.StripPrefix as needed, and wrap it in
+// the authentication middleware of the service for triggers without a
+// signing secret.
+type WebhookTriggers struct {
+	Service *RunnerService
+	// OnRunFinished, when set, is called after each triggered run.
+	OnRunFinished func(run TriggeredRun)
+
+	mu       sync.RWMutex
+	triggers map[string]WebhookTrigger
+	mux      *http.ServeMux
+}
+
+// NewWebhookTriggers returns a WebhookTriggers submitting runs to the given
+// service.
+func NewWebhookTriggers(service *RunnerService) *WebhookTriggers {
+	w := &WebhookTriggers{
+		Service:  service,
+		triggers: make(map[string]WebhookTrigger),
+		mux:      http.NewServeMux(),
+	}
+	w.mux.HandleFunc("POST /webhooks/{name}", w.trigger)
+	return w
+}
+
+// ValidateWebhookTrigger checks the trigger, its mapping and its workflow
+// request.
+func ValidateWebhookTrigger(trigger WebhookTrigger) error {
+	if !workflowRefPattern.MatchString(trigger.Name) {
+		return fmt.Errorf("invalid webhook trigger name %q", trigger.Name)
+	}
+	paths := map[string]string{"query": trigger.Mapping.Query, "session_id": trigger.Mapping.SessionID}
+	for key, path := range trigger.Mapping.Inputs {
+		paths["inputs."+key] = path
+	}
+	for key, path := range trigger.Mapping.Metadata {
+		paths["metadata."+key] = path
+	}
+	for field, path := range paths {
+		if path == "" && (field == "query" || field == "session_id") {
+			continue
+		}
+		if _, err := parseJSONPath(path); err != nil {
+			return fmt.Errorf("webhook trigger %q mapping %s: %w", trigger.Name, field, err)
+		}
+	}
+	// The query may come from the payload.
+	req := trigger.Request
+	if strings.TrimSpace(req.Query) == "" {
+		req.Query = "webhook payload"
+	}
+	if err := ValidateWorkflowRequest(req); err != nil {
+		return fmt.Errorf("webhook trigger %q: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// Add registers a trigger, replacing the one with the same name.
+func (w *WebhookTriggers) Add(trigger WebhookTrigger) error {
+	if err := ValidateWebhookTrigger(trigger); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.triggers[trigger.Name] = trigger
+	return nil
+}
+
+// Remove unregisters a trigger, reporting whether it existed. Its ongoing
+// runs are not canceled.
+func (w *WebhookTriggers) Remove(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.triggers[name]
+	delete(w.triggers, name)
+	return ok
+}
+
+// Triggers returns the registered triggers.
+func (w *WebhookTriggers) Triggers() []WebhookTrigger {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Collect(maps.Values(w.triggers))
+}
+
+func (w *WebhookTriggers) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mux.ServeHTTP(rw, r)
+}
+
+func (w *WebhookTriggers) trigger(rw http.ResponseWriter, r *http.Request) {
+	w.mu.RLock()
+	trigger, ok := w.triggers[r.PathValue("name")]
+	w.mu.RUnlock()
+	if !ok {
+		writeJSONError(rw, http.StatusNotFound, fmt.Errorf("webhook trigger %q not found", r.PathValue("name")))
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeJSONError(rw, http.StatusBadRequest, err)
+		return
+	}
+	if trigger.SigningSecret != "" {
+		if err := VerifyCallbackSignature([]byte(trigger.SigningSecret), body, r.Header.Get(CallbackSignatureHeader), 0); err != nil {
+			writeJSONError(rw, http.StatusUnauthorized, err)
+			return
+		}
+	}
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Errorf("invalid JSON payload: %w", err))
+		return
+	}
+	req, err := trigger.request(payload, w.Service.ids().SessionID())
+	if err != nil {
+		writeJSONError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	// Runs outlive the webhook requests.
+	task, err := w.Service.Execute(context.WithoutCancel(r.Context()), req)
+	var admissionErr *AdmissionError
+	switch {
+	case errors.As(err, &admissionErr):
+		writeJSONError(rw, http.StatusTooManyRequests, err)
+		return
+	case errors.Is(err, ErrMissingCapabilities):
+		writeJSONError(rw, http.StatusForbidden, err)
+		return
+	case err != nil:
+		writeJSONError(rw, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if w.OnRunFinished != nil {
+		go func() {
+			result := task.Await()
+			w.OnRunFinished(TriggeredRun{
+				Trigger:   trigger.Name,
+				SessionID: req.Session.SessionID,
+				Summary:   result.Value,
+				Err:       result.Error,
+			})
+		}()
+	}
+	writeJSON(rw, http.StatusAccepted, map[string]string{
+		"trigger":    trigger.Name,
+		"session_id": req.Session.SessionID,
+	})
+}
+
+// request maps the payload into a copy of the request template.
+func (t WebhookTrigger) request(payload any, deliveryID string) (WorkflowRequest, error) {
+	req := t.Request
+	switch {
+	case t.Mapping.Query != "":
+		value, ok, err := lookupJSONPath(payload, t.Mapping.Query)
+		if err != nil {
+			return req, fmt.Errorf("mapping query: %w", err)
+		}
+		if !ok {
+			return req, fmt.Errorf("mapping query: %s not found in payload", t.Mapping.Query)
+		}
+		req.Query = outputText(value)
+	case strings.TrimSpace(req.Query) == "":
+		req.Query = outputText(payload)
+	}
+
+	if t.Mapping.SessionID != "" {
+		value, ok, err := lookupJSONPath(payload, t.Mapping.SessionID)
+		if err != nil {
+			return req, fmt.Errorf("mapping session_id: %w", err)
+		}
+		if !ok {
+			return req, fmt.Errorf("mapping session_id: %s not found in payload", t.Mapping.SessionID)
+		}
+		req.Session.SessionID = outputText(value)
+	} else {
+		req.Session.SessionID = fmt.Sprintf("%s:%s", req.Session.SessionID, deliveryID)
+	}
+
+	var err error
+	if req.Context, err = mapJSONPaths(payload, t.Mapping.Inputs, req.Context); err != nil {
+		return req, fmt.Errorf("mapping inputs: %w", err)
+	}
+	if req.Metadata, err = mapJSONPaths(payload, t.Mapping.Metadata, req.Metadata); err != nil {
+		return req, fmt.Errorf("mapping metadata: %w", err)
+	}
+	return req, nil
+}
+
+// mapJSONPaths sets the values of the paths found in the payload on a copy
+// of target. Paths missing from the payload are left out.
+func mapJSONPaths(payload any, paths map[string]string, target map[string]any) (map[string]any, error) {
+	if len(paths) == 0 {
+		return target, nil
+	}
+	out := maps.Clone(target)
+	if out == nil {
+		out = make(map[string]any, len(paths))
+	}
+	for key, path := range paths {
+		value, ok, err := lookupJSONPath(payload, path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if ok {
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+// lookupJSONPath resolves a JSONPath expression in a JSON value.
+func lookupJSONPath(value any, path string) (any, bool, error) {
+	keys, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := lookupKeys(value, keys)
+	return value, ok, nil
+}
+
+// parseJSONPath parses the subset of JSONPath made of member names and
+// array indices, as in $.a.b[0]['c d'], into keys.
+func parseJSONPath(path string) ([]string, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+	var keys []string
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", path)
+			}
+			keys = append(keys, name)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed bracket", path)
+			}
+			key := rest[1:end]
+			if len(key) >= 2 && (key[0] == '\'' || key[0] == '"') && key[len(key)-1] == key[0] {
+				key = key[1 : len(key)-1]
+			} else if _, err := strconv.Atoi(key); err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: %q is neither an index nor a quoted name", path, key)
+			}
+			keys = append(keys, key)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,125 @@
+package workflowrunner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/openai-agents-go/agents"
+	"github.com/nlpodyssey/openai-agents-go/agentstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postWebhook posts body to the webhook of the named trigger, signed with
+// secret unless empty.
+func postWebhook(t *testing.T, handler http.Handler, name, body string, secret string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/"+name, strings.NewReader(body))
+	if secret != "" {
+		r.Header.Set(CallbackSignatureHeader, SignCallbackPayload([]byte(secret), []byte(body), time.Now()))
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestWebhookTriggerStartsRun(t *testing.T) {
+	model := agentstesting.NewFakeModel(false, &agentstesting.FakeModelTurnOutput{
+		Value: []agents.TResponseOutputItem{agentstesting.GetTextMessage("triaged")},
+	})
+	triggers := NewWebhookTriggers(newTestService(t, model, &recordingPublisher{}))
+	finished := make(chan TriggeredRun, 1)
+	triggers.OnRunFinished = func(run TriggeredRun) { finished <- run }
+	require.NoError(t, triggers.Add(WebhookTrigger{
+		Name:    "issues",
+		Request: testRequest("issues"),
+		Mapping: WebhookMapping{
+			Query:    "$.issue.title",
+			Inputs:   map[string]string{"number": "$.issue.number"},
+			Metadata: map[string]string{"repo": "$['repository']['full-name']", "missing": "$.sender"},
+		},
+		SigningSecret: "s3cr3t",
+	}))
+
+	body := `{"issue": {"title": "It crashes", "number": 42}, "repository": {"full-name": "o/r"}}`
+	w := postWebhook(t, triggers, "issues", body, "s3cr3t")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "issues", resp["trigger"])
+	assert.True(t, strings.HasPrefix(resp["session_id"], "issues:"), resp["session_id"])
+
+	run := <-finished
+	require.NoError(t, run.Err)
+	assert.Equal(t, "issues", run.Trigger)
+	assert.Equal(t, resp["session_id"], run.SessionID)
+	assert.Equal(t, "triaged", run.Summary.FinalOutput)
+
+	state, ok, err := triggers.Service.GetState(t.Context(), run.SessionID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "It crashes", state.LastQuery)
+	require.NotNil(t, state.Request)
+	assert.Equal(t, map[string]any{"number": float64(42)}, state.Request.Context)
+	assert.Equal(t, map[string]any{"repo": "o/r"}, state.Request.Metadata)
+}
+
+func TestWebhookTriggerRejectsRequests(t *testing.T) {
+	publisher := &recordingPublisher{}
+	triggers := NewWebhookTriggers(newTestService(t, agentstesting.NewFakeModel(false, nil), publisher))
+	restricted := testRequest("restricted")
+	restricted.Workflow.RequiredCapabilities = []string{"admin"}
+	for _, trigger := range []WebhookTrigger{
+		{Name: "signed", Request: testRequest("signed"), SigningSecret: "s3cr3t"},
+		{Name: "mapped", Request: testRequest("mapped"), Mapping: WebhookMapping{Query: "$.issue.title"}},
+		{Name: "restricted", Request: restricted},
+	} {
+		require.NoError(t, triggers.Add(trigger))
+	}
+	valid := `{"issue": {"title": "It crashes"}}`
+
+	tests := []struct {
+		name    string
+		trigger string
+		body    string
+		secret  string
+		status  int
+	}{
+		{name: "unknown trigger", trigger: "missing", body: valid, status: http.StatusNotFound},
+		{name: "unsigned", trigger: "signed", body: valid, status: http.StatusUnauthorized},
+		{name: "signed with another secret", trigger: "signed", body: valid, secret: "other", status: http.StatusUnauthorized},
+		{name: "malformed payload", trigger: "mapped", body: `{"issue": `, status: http.StatusBadRequest},
+		{name: "malformed signed payload", trigger: "signed", body: `not json`, secret: "s3cr3t", status: http.StatusBadRequest},
+		{name: "unmapped payload", trigger: "mapped", body: `{"pull_request": {}}`, status: http.StatusBadRequest},
+		{name: "missing capabilities", trigger: "restricted", body: valid, status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postWebhook(t, triggers, tt.trigger, tt.body, tt.secret)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+
+	// Rejected requests start no run.
+	assert.Empty(t, publisher.published())
+}
+
+func TestValidateWebhookTrigger(t *testing.T) {
+	require.NoError(t, ValidateWebhookTrigger(WebhookTrigger{Name: "issues", Request: testRequest("issues")}))
+
+	invalid := map[string]WebhookTrigger{
+		"name":         {Name: "bad name", Request: testRequest("issues")},
+		"query path":   {Name: "issues", Request: testRequest("issues"), Mapping: WebhookMapping{Query: "issue.title"}},
+		"input path":   {Name: "issues", Request: testRequest("issues"), Mapping: WebhookMapping{Inputs: map[string]string{"n": "$.items[first]"}}},
+		"workflow":     {Name: "issues", Request: WorkflowRequest{}},
+		"unclosed key": {Name: "issues", Request: testRequest("issues"), Mapping: WebhookMapping{SessionID: "$['id'"}},
+	}
+	for name, trigger := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, ValidateWebhookTrigger(trigger))
+		})
+	}
+}